@@ -0,0 +1,100 @@
+// This program builds a .xlsx workbook from CSV files, one worksheet per file.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"GoTools/pkg/converters"
+	. "GoTools/pkg/helpers"
+)
+
+// cliOptions holds the command line flags.
+type cliOptions struct {
+	out         string
+	sheets      string
+	delimiter   string
+	encoding    string
+	noHeader    bool
+	styleHeader bool
+	autoWidth   bool
+	types       bool
+	timeout     time.Duration
+}
+
+var cli cliOptions
+
+func main() {
+	processingErr := ErrMsg{Code: Success}
+	defer func() {
+		processingErr.Exit()
+	}()
+	flag.StringVar(&cli.out, "out", "", "The path of the .xlsx file to write")
+	flag.StringVar(&cli.sheets, "sheets", "", "Comma separated worksheet names, one per CSV file (default the file names)")
+	flag.StringVar(&cli.delimiter, "delimiter", ",", `Field delimiter, "\t" for a tab`)
+	flag.StringVar(&cli.encoding, "encoding", "UTF-8", "Input encoding: UTF-8, UTF-16, UTF-16LE, UTF-16BE, ISO-8859-1 or Windows-1252")
+	flag.BoolVar(&cli.noHeader, "no-header", false, "Treat the first record as data instead of the header row")
+	flag.BoolVar(&cli.styleHeader, "style-header", false, "Write the header row in bold and freeze it")
+	flag.BoolVar(&cli.autoWidth, "autowidth", false, "Size every column to its longest value")
+	flag.BoolVar(&cli.types, "types", false, "Write numbers, booleans and dates as such instead of as text")
+	flag.DurationVar(&cli.timeout, "timeout", 0, "Abort the conversion once it has run this long, e.g. 90s or 10m (default no limit)")
+	flag.Parse()
+
+	paths := flag.Args()
+	if len(paths) < 1 {
+		processingErr = ErrMsg{Err: errors.New("no CSV files provided"), Code: ErrNoInput}
+		return
+	}
+	for _, path := range paths {
+		if exists, pathErr := PathExists(path); pathErr != nil || !exists {
+			processingErr = ErrMsg{Err: pathErr, Code: ErrNoFile}
+			return
+		}
+	}
+	outPath := strings.TrimSpace(cli.out)
+	if !CheckExtension(outPath, ".xlsx") {
+		processingErr = ErrMsg{Err: errors.New("the -out flag must name a .xlsx file"), Code: ErrInvalidFileType}
+		return
+	}
+	opts, optsErr := buildOptions()
+	if optsErr != nil {
+		processingErr = ErrMsg{Err: optsErr, Code: ErrNoInput}
+		return
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if cli.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cli.timeout)
+		defer cancel()
+	}
+	if convertErr := converters.CsvToXlsx(ctx, outPath, paths, opts); convertErr != nil {
+		code := ErrParse
+		if errors.Is(convertErr, context.Canceled) || errors.Is(convertErr, context.DeadlineExceeded) {
+			code = ErrCancelled
+		}
+		processingErr = ErrMsg{Err: convertErr, Code: code}
+	}
+}
+
+// buildOptions turns the command line flags into converter options.
+func buildOptions() (opts converters.CsvToXlsxOptions, err error) {
+	if opts.Delimiter, err = SingleRune("-delimiter", strings.ReplaceAll(cli.delimiter, `\t`, "\t")); err != nil {
+		return opts, err
+	}
+	if len(cli.sheets) > 0 {
+		opts.SheetNames = strings.Split(cli.sheets, ",")
+	}
+	opts.Encoding = cli.encoding
+	opts.NoHeader = cli.noHeader
+	opts.StyleHeader = cli.styleHeader
+	opts.AutoWidth = cli.autoWidth
+	opts.CoerceTypes = cli.types
+	return opts, nil
+}
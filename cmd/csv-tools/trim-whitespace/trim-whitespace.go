@@ -28,6 +28,7 @@ func main() {
 		processingErr.Exit()
 	}()
 	filePathPtr := flag.String("path", "", "CSV file path")
+	cleanPtr := flag.Bool("clean", false, "Also collapse internal whitespace and strip non-breaking spaces, zero-width and control characters")
 	flag.Parse()
 	pipeInput, _ := os.Stdin.Stat()
 
@@ -37,9 +38,9 @@ func main() {
 		if inputErr != nil {
 			processingErr = ErrMsg{Err: inputErr, Code: ErrStdin}
 		}
-		processingErr = processCSV(strings.TrimSpace(input))
+		processingErr = processCSV(strings.TrimSpace(input), *cleanPtr)
 	} else if *filePathPtr != "" {
-		processingErr = processCSV(*filePathPtr)
+		processingErr = processCSV(*filePathPtr, *cleanPtr)
 	} else {
 		processingErr = ErrMsg{
 			Err:  fmt.Errorf("no CSV path provided from pipe nor --path flag"),
@@ -48,7 +49,7 @@ func main() {
 	}
 }
 
-func processCSV(path string) ErrMsg {
+func processCSV(path string, clean bool) ErrMsg {
 
 	if exists, _ := PathExists(path); !exists {
 		return ErrMsg{Err: fmt.Errorf("file '%s' does not exist", path), Code: ErrNoFile}
@@ -59,7 +60,7 @@ func processCSV(path string) ErrMsg {
 			Code: ErrInvalidFileType,
 		}
 	}
-	tempFile, ioErr := readWriteCsv(path)
+	tempFile, ioErr := readWriteCsv(path, clean)
 	if ioErr != nil {
 		return ErrMsg{Err: ioErr, Code: ErrReadWrite}
 	}
@@ -83,7 +84,7 @@ func processCSV(path string) ErrMsg {
 	return ErrMsg{Code: Success}
 }
 
-func readWriteCsv(path string) (string, error) {
+func readWriteCsv(path string, clean bool) (string, error) {
 	originalCsv, readErr := os.Open(path)
 	if readErr != nil {
 		return "", readErr
@@ -120,7 +121,11 @@ func readWriteCsv(path string) (string, error) {
 			break
 		}
 		for i, field := range record {
-			newRecord[i] = strings.TrimSpace(field)
+			if clean {
+				newRecord[i] = CleanValue(field, CleanOptions{KeepLineBreaks: true})
+			} else {
+				newRecord[i] = strings.TrimSpace(field)
+			}
 		}
 		writeErr := writer.Write(newRecord)
 		if writeErr != nil {
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"GoTools/pkg/converters"
+	. "GoTools/pkg/helpers"
+	"github.com/spf13/cobra"
+)
+
+// convertFlags holds the flags of the convert subcommand.
+type convertFlags struct {
+	read        readFlags
+	outDir      string
+	format      string
+	concurrency int
+	failFast    bool
+}
+
+// batchFormats are the formats the convert subcommand writes, each with the sink writing a file at a path.
+var batchFormats = map[string]func(path string) (converters.RowSink, error){
+	"xml":     writerSink(func(w io.Writer) converters.RowSink { return converters.XmlSink(w, converters.XmlOptions{}) }),
+	"csv":     writerSink(func(w io.Writer) converters.RowSink { return converters.CsvSink(w, converters.CsvOptions{}) }),
+	"json":    writerSink(func(w io.Writer) converters.RowSink { return converters.JsonSink(w, converters.JsonOptions{}) }),
+	"parquet": writerSink(func(w io.Writer) converters.RowSink { return converters.ParquetSink(w, converters.ParquetOptions{}) }),
+	"xlsx": func(path string) (converters.RowSink, error) {
+		return converters.XlsxSink(path, converters.StyleOptions{}), nil
+	},
+}
+
+// writerSink returns the function opening the sink newSink returns for a file created at path.
+func writerSink(newSink func(w io.Writer) converters.RowSink) func(path string) (converters.RowSink, error) {
+	return func(path string) (converters.RowSink, error) {
+		return converters.FileSink(path, newSink)
+	}
+}
+
+// newConvertCommand returns the convert subcommand, converting every workbook matching glob patterns.
+func newConvertCommand() *cobra.Command {
+	var f convertFlags
+	cmd := &cobra.Command{
+		Use:   "convert [flags] <pattern>...",
+		Short: "Convert every workbook matching glob patterns, such as \"in/**/*.xlsx\", into a directory",
+		Long: "Convert every workbook matching glob patterns into the --out directory, ** matching any number of " +
+			"directories. The files keep the directories they lie in below the leading directories of their " +
+			"pattern, such as in/2024/a.xlsx converted from in/**/*.xlsx into out/2024/a.xml. A line per file " +
+			"reports whether it converted; any failure fails the command once every file is done.",
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			openSink, known := batchFormats[f.format]
+			if !known {
+				return inputError(fmt.Errorf("unknown output format '%s'", f.format))
+			}
+			if len(strings.TrimSpace(f.outDir)) < 1 {
+				return inputError(errors.New("no output directory provided with the --out flag"))
+			}
+			if f.concurrency < 0 {
+				return inputError(fmt.Errorf("invalid concurrency %d", f.concurrency))
+			}
+			opts, optsErr := f.read.readOptions()
+			if optsErr != nil {
+				return inputError(optsErr)
+			}
+			sheets, sheetsErr := f.read.selector()
+			if sheetsErr != nil {
+				return inputError(sheetsErr)
+			}
+			files, filesErr := batchFiles(args, f.outDir, "."+f.format)
+			if filesErr != nil {
+				return inputError(filesErr)
+			}
+			jobs := make([]converters.Job, len(files))
+			for index, file := range files {
+				jobs[index] = f.read.conversionJob(file.path, file.outPath, sheets, opts, openSink)
+			}
+			ctx, cancel := commandContext(cmd)
+			defer cancel()
+			results, runErr := converters.RunJobs(ctx, jobs, converters.EngineOptions{Concurrency: f.concurrency, FailFast: f.failFast})
+			if err := writeSummary(cmd.OutOrStdout(), files, results); err != nil {
+				return outputError(err)
+			}
+			if runErr != nil {
+				return conversionError(runErr)
+			}
+			return nil
+		},
+	}
+	flags := cmd.Flags()
+	f.read.register(flags, "convert")
+	flags.StringVar(&f.outDir, "out", "", "Directory to write the converted files into")
+	flags.StringVar(&f.format, "format", "xml", "Format of the converted files: xml, csv, json, parquet or xlsx")
+	flags.IntVar(&f.concurrency, "concurrency", 0, "Number of files converted at a time (default the number of CPUs)")
+	flags.BoolVar(&f.failFast, "fail-fast", false, "Stop converting as soon as a file fails instead of converting every other file")
+	return cmd
+}
+
+// conversionJob returns the job converting the worksheet of the workbook at path selected by sheets, read with
+// opts, into the file at outPath opened with openSink, its directory created. The job fails when path does not
+// name a workbook.
+func (f *readFlags) conversionJob(path, outPath string, sheets converters.SheetSelector, opts converters.ReadOptions,
+	openSink func(path string) (converters.RowSink, error)) converters.Job {
+	return converters.Job{
+		Name:   path,
+		Source: converters.SheetSource(path, sheets, opts),
+		OpenSink: func() (converters.RowSink, error) {
+			if err := f.checkInput(path); err != nil {
+				return nil, err
+			}
+			if err := os.MkdirAll(filepath.Dir(outPath), 0o755); err != nil {
+				return nil, err
+			}
+			return openSink(outPath)
+		},
+	}
+}
+
+// batchFile is a file matching a pattern of the convert subcommand and the path it is converted to.
+type batchFile struct {
+	path    string
+	outPath string
+}
+
+// batchFiles returns the files matching patterns, each converted into outDir below the directories it lies in
+// under the leading directories of its pattern, its extension replaced with ext. Directories are left out and a
+// file matching several patterns is converted once. Two files converted to the same path fail.
+func batchFiles(patterns []string, outDir, ext string) ([]batchFile, error) {
+	var files []batchFile
+	seen := make(map[string]bool)
+	sources := make(map[string]string)
+	for _, pattern := range patterns {
+		matches, globErr := Glob(pattern)
+		if globErr != nil {
+			return nil, fmt.Errorf("pattern '%s': %w", pattern, globErr)
+		}
+		base := GlobBase(pattern)
+		for _, path := range matches {
+			if info, err := os.Stat(path); err != nil || info.IsDir() || seen[path] {
+				continue
+			}
+			seen[path] = true
+			relative, relErr := filepath.Rel(base, path)
+			if relErr != nil {
+				return nil, relErr
+			}
+			outPath := filepath.Join(outDir, strings.TrimSuffix(relative, filepath.Ext(relative))+ext)
+			if source, taken := sources[outPath]; taken {
+				return nil, fmt.Errorf("%s and %s would both be converted to %s", source, path, outPath)
+			}
+			sources[outPath] = path
+			files = append(files, batchFile{path: path, outPath: outPath})
+		}
+	}
+	if len(files) < 1 {
+		return nil, fmt.Errorf("no files match %s", strings.Join(patterns, " "))
+	}
+	return files, nil
+}
+
+// writeSummary writes a line per file to w telling whether it converted, followed by the number of files which
+// did and which failed.
+func writeSummary(w io.Writer, files []batchFile, results []converters.JobResult) error {
+	var summary strings.Builder
+	failed := 0
+	for index, result := range results {
+		if result.Err != nil {
+			failed++
+		}
+		summary.WriteString(resultLine(result, files[index].outPath))
+	}
+	fmt.Fprintf(&summary, "%d converted, %d failed\n", len(results)-failed, failed)
+	_, err := io.WriteString(w, summary.String())
+	return err
+}
+
+// resultLine returns the line telling whether the file of result converted to outPath.
+func resultLine(result converters.JobResult, outPath string) string {
+	if result.Err != nil {
+		return fmt.Sprintf("FAIL %s: %v\n", result.Name, result.Err)
+	}
+	return fmt.Sprintf("ok   %s -> %s (%d records, %s)\n", result.Name, outPath, result.Records, result.Duration.Round(time.Millisecond))
+}
@@ -0,0 +1,127 @@
+// This program gathers the tools of the toolkit as subcommands of a single command line, such as
+//
+//	gotools xlsx2csv --sheet Orders --out exports orders.xlsx
+//
+// sharing the flags which control how workbooks are read.
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"os/signal"
+	"time"
+
+	"GoTools/pkg/converters"
+	. "GoTools/pkg/helpers"
+	"github.com/spf13/cobra"
+)
+
+// timeout is the value of the --timeout flag shared by every subcommand.
+var timeout time.Duration
+
+func main() {
+	processingErr := ErrMsg{Code: Success}
+	defer func() {
+		processingErr.Exit()
+	}()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if err := newRootCommand().ExecuteContext(ctx); err != nil {
+		processingErr = ErrMsg{Err: err, Code: exitCode(err)}
+	}
+}
+
+// newRootCommand returns the gotools command holding every subcommand.
+func newRootCommand() *cobra.Command {
+	root := &cobra.Command{
+		Use:           "gotools",
+		Short:         "Convert, validate, profile, merge and split workbooks",
+		SilenceErrors: true,
+		SilenceUsage:  true,
+	}
+	root.PersistentFlags().DurationVar(&timeout, "timeout", 0, "Abort the command once it has run this long, e.g. 90s or 10m (default no limit)")
+	root.AddCommand(
+		newXlsxToXmlCommand(),
+		newXlsxToCsvCommand(),
+		newXlsxToJsonCommand(),
+		newConvertCommand(),
+		newWatchCommand(),
+		newRunCommand(),
+		newValidateCommand(),
+		newProfileCommand(),
+		newPreviewCommand(),
+		newMergeCommand(),
+		newSplitCommand(),
+	)
+	return root
+}
+
+// exitError is the error a command fails with along with the exit code of the program.
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e exitError) Error() string {
+	return e.err.Error()
+}
+
+func (e exitError) Unwrap() error {
+	return e.err
+}
+
+// exitCode returns the exit code of the program for err, ErrNoInput for the errors of the command line itself,
+// such as an unknown flag.
+func exitCode(err error) int {
+	var exit exitError
+	if errors.As(err, &exit) {
+		return exit.code
+	}
+	return ErrNoInput
+}
+
+// inputError returns err as the error of invalid flags or arguments.
+func inputError(err error) error {
+	return exitError{code: ErrNoInput, err: err}
+}
+
+// outputError returns err as the error of writing to stdout.
+func outputError(err error) error {
+	return exitError{code: ErrStdout, err: err}
+}
+
+// conversionError returns err as the error of a failed conversion, telling schema violations and cancelled
+// conversions apart from other errors.
+func conversionError(err error) error {
+	var validationErr *converters.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		return exitError{code: ErrValidation, err: err}
+	case errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded):
+		return exitError{code: ErrCancelled, err: err}
+	}
+	return exitError{code: ErrParse, err: err}
+}
+
+// commandContext returns the context of cmd, cancelled on interrupt and once --timeout elapses.
+func commandContext(cmd *cobra.Command) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(cmd.Context())
+	}
+	return context.WithTimeout(cmd.Context(), timeout)
+}
+
+// writeOutput runs write with a buffered writer to the output of cmd, stdout unless redirected, and flushes it.
+func writeOutput(cmd *cobra.Command, write func(w io.Writer) error) error {
+	writer := bufio.NewWriter(cmd.OutOrStdout())
+	if err := write(writer); err != nil {
+		return conversionError(err)
+	}
+	if err := writer.Flush(); err != nil {
+		return outputError(err)
+	}
+	return nil
+}
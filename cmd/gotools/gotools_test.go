@@ -0,0 +1,339 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	. "GoTools/pkg/helpers"
+	"github.com/xuri/excelize/v2"
+)
+
+// createTestWorkbook saves a workbook holding a People worksheet into a temporary directory.
+func createTestWorkbook(t *testing.T) string {
+	t.Helper()
+	file := excelize.NewFile()
+	defer func() {
+		if err := file.Close(); err != nil {
+			t.Errorf("Error closing test workbook: %v", err)
+		}
+	}()
+	if err := file.SetSheetName("Sheet1", "People"); err != nil {
+		t.Fatalf("Error renaming test sheet: %v", err)
+	}
+	for index, row := range [][]any{{"Name", "Age"}, {"Ann", 31}, {"Bob", 42}} {
+		cellName, _ := excelize.CoordinatesToCellName(1, index+1)
+		if err := file.SetSheetRow("People", cellName, &row); err != nil {
+			t.Fatalf("Error writing test row: %v", err)
+		}
+	}
+	path := filepath.Join(t.TempDir(), "people.xlsx")
+	if err := file.SaveAs(path); err != nil {
+		t.Fatalf("Error saving test workbook: %v", err)
+	}
+	return path
+}
+
+// createTestSpreadsheet returns an OpenDocument spreadsheet holding a People sheet.
+func createTestSpreadsheet(t *testing.T) []byte {
+	t.Helper()
+	content := `<office:document-content xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0"
+    xmlns:table="urn:oasis:names:tc:opendocument:xmlns:table:1.0" xmlns:text="urn:oasis:names:tc:opendocument:xmlns:text:1.0">
+  <office:body><office:spreadsheet><table:table table:name="People">
+    <table:table-row><table:table-cell office:value-type="string"><text:p>Name</text:p></table:table-cell></table:table-row>
+    <table:table-row><table:table-cell office:value-type="string"><text:p>Ann</text:p></table:table-cell></table:table-row>
+  </table:table></office:spreadsheet></office:body>
+</office:document-content>`
+	var archive bytes.Buffer
+	writer := zip.NewWriter(&archive)
+	for name, data := range map[string]string{"mimetype": "application/vnd.oasis.opendocument.spreadsheet", "content.xml": content} {
+		entry, err := writer.Create(name)
+		if err != nil {
+			t.Fatalf("Error creating test spreadsheet: %v", err)
+		}
+		if _, err = entry.Write([]byte(data)); err != nil {
+			t.Fatalf("Error writing test spreadsheet: %v", err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Error closing test spreadsheet: %v", err)
+	}
+	return archive.Bytes()
+}
+
+// run runs gotools with args, returning what it wrote to stdout.
+func run(args ...string) (string, error) {
+	root := newRootCommand()
+	var output bytes.Buffer
+	root.SetOut(&output)
+	root.SetErr(&output)
+	root.SetArgs(args)
+	err := root.ExecuteContext(context.Background())
+	return output.String(), err
+}
+
+func TestSubcommands(t *testing.T) {
+	path := createTestWorkbook(t)
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{name: "xlsx2csv", args: []string{"xlsx2csv", "--delimiter", ";", path}, want: "Name;Age\nAnn;31\nBob;42\n"},
+		{name: "xlsx2csv where", args: []string{"xlsx2csv", "--where", "Age > 40", path}, want: "Name,Age\nBob,42\n"},
+		{name: "xlsx2json", args: []string{"xlsx2json", "--ndjson", "--types", path}, want: "{\"Name\":\"Ann\",\"Age\":31}\n{\"Name\":\"Bob\",\"Age\":42}\n"},
+		{
+			name: "xlsx2xml",
+			args: []string{"xlsx2xml", "--compact", "--root", "People", "--row", "Person", path},
+			want: "<People><Person><Name>Ann</Name><Age>31</Age></Person><Person><Name>Bob</Name><Age>42</Age></Person></People>",
+		},
+		{name: "preview", args: []string{"preview", "--rows", "1", path}, want: "Name  Age\nAnn   31\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := run(tt.args...)
+			if err != nil {
+				t.Fatalf("gotools %v error = %v", tt.args, err)
+			}
+			if got != tt.want {
+				t.Errorf("gotools %v = %q, want %q", tt.args, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitAndMerge(t *testing.T) {
+	path := createTestWorkbook(t)
+	dir := t.TempDir()
+	if _, err := run("split", "--column", "Name", "--format", "csv", "--name", "{value}", "--out", dir, path); err != nil {
+		t.Fatalf("gotools split error = %v", err)
+	}
+	content, _ := os.ReadFile(filepath.Join(dir, "Ann.csv"))
+	if want := "Name,Age\nAnn,31\n"; string(content) != want {
+		t.Errorf("Ann.csv = %q, want %q", content, want)
+	}
+	merged := filepath.Join(dir, "merged.xlsx")
+	if _, err := run("merge", "--out", merged, path, path); err != nil {
+		t.Fatalf("gotools merge error = %v", err)
+	}
+	got, err := run("preview", merged)
+	if err != nil {
+		t.Fatalf("gotools preview error = %v", err)
+	}
+	if want := "Name  Age\nAnn   31\nBob   42\nAnn   31\nBob   42\n"; got != want {
+		t.Errorf("merged workbook = %q, want %q", got, want)
+	}
+}
+
+func TestExitCodes(t *testing.T) {
+	path := createTestWorkbook(t)
+	schema := filepath.Join(t.TempDir(), "people.xsd")
+	content := `<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema">
+  <xs:element name="DataTable">
+    <xs:complexType><xs:sequence>
+      <xs:element name="Row" maxOccurs="unbounded">
+        <xs:complexType><xs:sequence>
+          <xs:element name="Name" type="xs:string"/>
+          <xs:element name="Age" type="xs:integer"/>
+        </xs:sequence></xs:complexType>
+      </xs:element>
+    </xs:sequence></xs:complexType>
+  </xs:element>
+</xs:schema>`
+	if err := os.WriteFile(schema, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{name: "Valid", args: []string{"validate", "--schema", schema, path}, want: Success},
+		{name: "Invalid", args: []string{"validate", "--schema", schema, "--order", "alphabetical", path}, want: ErrValidation},
+		{name: "No schema", args: []string{"validate", path}, want: ErrNoInput},
+		{name: "Missing file", args: []string{"xlsx2csv", filepath.Join(t.TempDir(), "missing.xlsx")}, want: ErrNoFile},
+		{name: "Wrong file type", args: []string{"xlsx2csv", schema}, want: ErrInvalidFileType},
+		{name: "Unknown flag", args: []string{"xlsx2csv", "--nope", path}, want: ErrNoInput},
+		{name: "Invalid flag value", args: []string{"profile", "--format", "pdf", path}, want: ErrNoInput},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := run(tt.args...)
+			code := Success
+			if err != nil {
+				code = exitCode(err)
+			}
+			if code != tt.want {
+				t.Errorf("gotools %v exit code = %d (%v), want %d", tt.args, code, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvert(t *testing.T) {
+	workbook, readErr := os.ReadFile(createTestWorkbook(t))
+	if readErr != nil {
+		t.Fatal(readErr)
+	}
+	dir := t.TempDir()
+	inputs := map[string][]byte{"a.xlsx": workbook, "2024/03/b.xlsx": workbook, "notes.txt": nil}
+	for name, content := range inputs {
+		path := filepath.Join(dir, "in", filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, content, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	outDir := filepath.Join(dir, "out")
+	pattern := filepath.Join(dir, "in", "**", "*.xlsx")
+	summary, err := run("convert", "--format", "csv", "--out", outDir, pattern)
+	if err != nil {
+		t.Fatalf("gotools convert error = %v", err)
+	}
+	if !strings.HasSuffix(summary, "2 converted, 0 failed\n") {
+		t.Errorf("summary = %q", summary)
+	}
+	for _, name := range []string{"a.csv", "2024/03/b.csv"} {
+		content, _ := os.ReadFile(filepath.Join(outDir, filepath.FromSlash(name)))
+		if want := "Name,Age\nAnn,31\nBob,42\n"; string(content) != want {
+			t.Errorf("%s = %q, want %q", name, content, want)
+		}
+	}
+
+	summary, err = run("convert", "--out", outDir, filepath.Join(dir, "in", "**", "*"))
+	if code := exitCode(err); err == nil || code != ErrParse {
+		t.Errorf("gotools convert with a failing file exit code = %d (%v), want %d", code, err, ErrParse)
+	}
+	if !strings.Contains(summary, "FAIL "+filepath.Join(dir, "in", "notes.txt")) || !strings.HasSuffix(summary, "2 converted, 1 failed\n") {
+		t.Errorf("summary = %q", summary)
+	}
+
+	if _, err := run("convert", "--out", outDir, filepath.Join(dir, "in", "*.xlsx"), filepath.Join(dir, "in", "**", "a.xlsx")); err != nil {
+		t.Errorf("gotools convert of a file matching two patterns error = %v", err)
+	}
+	if _, err := run("convert", "--out", outDir, filepath.Join(dir, "none", "*.xlsx")); exitCode(err) != ErrNoInput {
+		t.Errorf("gotools convert matching no files error = %v, want exit code %d", err, ErrNoInput)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	workbook, readErr := os.ReadFile(createTestWorkbook(t))
+	if readErr != nil {
+		t.Fatal(readErr)
+	}
+	dir := t.TempDir()
+	in, outDir := filepath.Join(dir, "in"), filepath.Join(dir, "out")
+	if err := os.Mkdir(in, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	// twin.xlsx and twin.ods share their name, the second converted is numbered instead of overwriting the first.
+	for name, content := range map[string][]byte{"before.xlsx": workbook, "twin.xlsx": workbook, "twin.ods": createTestSpreadsheet(t)} {
+		if err := os.WriteFile(filepath.Join(in, name), content, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	root := newRootCommand()
+	var output bytes.Buffer
+	root.SetOut(&output)
+	root.SetArgs([]string{"watch", "--format", "csv", "--out", outDir, "--settle", "200ms", in})
+	done := make(chan error)
+	go func() {
+		done <- root.ExecuteContext(ctx)
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+	for name, content := range map[string][]byte{"after.xlsx": workbook, "broken.xlsx": []byte("not a workbook"), "notes.txt": nil} {
+		if err := os.WriteFile(filepath.Join(in, name), content, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := []string{
+		filepath.Join(outDir, "before.csv"),
+		filepath.Join(outDir, "after.csv"),
+		filepath.Join(outDir, "twin.csv"),
+		filepath.Join(outDir, "twin-2.csv"),
+		filepath.Join(in, "processed", "before.xlsx"),
+		filepath.Join(in, "processed", "after.xlsx"),
+		filepath.Join(in, "failed", "broken.xlsx"),
+		filepath.Join(in, "failed", "broken.xlsx.error"),
+	}
+	for deadline := time.Now().Add(10 * time.Second); time.Now().Before(deadline); time.Sleep(50 * time.Millisecond) {
+		missing := false
+		for _, path := range want {
+			if exists, _ := PathExists(path); !exists {
+				missing = true
+			}
+		}
+		if !missing {
+			break
+		}
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("gotools watch error = %v", err)
+	}
+	for _, path := range want {
+		if exists, _ := PathExists(path); !exists {
+			t.Errorf("%s is missing, output %q", path, output.String())
+		}
+	}
+	content, _ := os.ReadFile(filepath.Join(outDir, "after.csv"))
+	if want := "Name,Age\nAnn,31\nBob,42\n"; string(content) != want {
+		t.Errorf("after.csv = %q, want %q", content, want)
+	}
+	if exists, _ := PathExists(filepath.Join(in, "notes.txt")); !exists {
+		t.Error("notes.txt, which matches no pattern, was moved")
+	}
+	if _, err := run("watch", "--out", outDir, filepath.Join(dir, "missing")); exitCode(err) != ErrNoInput {
+		t.Errorf("gotools watch of a missing folder error = %v, want exit code %d", err, ErrNoInput)
+	}
+	if _, err := run("watch", "--format", "xlsx", "--out", in+string(filepath.Separator), in); exitCode(err) != ErrNoInput ||
+		!strings.Contains(err.Error(), "must not be the watched folder") {
+		t.Errorf("gotools watch into the watched folder error = %v, want exit code %d", err, ErrNoInput)
+	}
+}
+
+func TestRun(t *testing.T) {
+	path := createTestWorkbook(t)
+	dir := t.TempDir()
+	out := filepath.Join(dir, "out", "people.json")
+	config := filepath.Join(dir, "people.yaml")
+	content := "source: {path: " + path + "}\nwhere: Age > 40\nsink: {path: " + out + ", json: {lines: true}}\n"
+	if err := os.WriteFile(config, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	summary, err := run("run", config)
+	if err != nil {
+		t.Fatalf("gotools run error = %v", err)
+	}
+	if !strings.HasSuffix(summary, "1 converted, 0 failed\n") {
+		t.Errorf("summary = %q", summary)
+	}
+	got, _ := os.ReadFile(out)
+	if want := "{\"Name\":\"Bob\",\"Age\":\"42\"}\n"; string(got) != want {
+		t.Errorf("people.json = %q, want %q", got, want)
+	}
+
+	csv := filepath.Join(dir, "people.csv")
+	if _, err := run("run", "--output", csv, config); err != nil {
+		t.Fatalf("gotools run --output error = %v", err)
+	}
+	if got, _ := os.ReadFile(csv); string(got) != "Name,Age\nBob,42\n" {
+		t.Errorf("people.csv = %q, want %q", got, "Name,Age\nBob,42\n")
+	}
+	if _, err := run("run", filepath.Join(dir, "missing.yaml")); exitCode(err) != ErrNoFile {
+		t.Errorf("gotools run of a missing configuration error = %v, want exit code %d", err, ErrNoFile)
+	}
+	if _, err := run("run", "--input", path, config, config); exitCode(err) != ErrNoInput {
+		t.Errorf("gotools run --input of two configurations error = %v, want exit code %d", err, ErrNoInput)
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"GoTools/pkg/converters"
+	. "GoTools/pkg/helpers"
+	"github.com/spf13/cobra"
+)
+
+// mergeFlags holds the flags of the merge subcommand.
+type mergeFlags struct {
+	read         readFlags
+	out          string
+	headers      string
+	aliases      string
+	sourceColumn string
+	sheetColumn  string
+	report       string
+	styleHeader  bool
+	autoWidth    bool
+	types        bool
+}
+
+// newMergeCommand returns the merge subcommand, stacking the worksheets of several workbooks into one.
+func newMergeCommand() *cobra.Command {
+	var f mergeFlags
+	cmd := &cobra.Command{
+		Use:   "merge [flags] <workbook>...",
+		Short: "Merge the worksheets of several workbooks with matching headers into a single worksheet",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			for _, path := range args {
+				if err := f.read.checkInput(path); err != nil {
+					return err
+				}
+			}
+			if !CheckExtension(strings.TrimSpace(f.out), ".xlsx") {
+				return exitError{code: ErrInvalidFileType, err: errors.New("the --out flag must name a .xlsx file")}
+			}
+			opts, optsErr := f.options()
+			if optsErr != nil {
+				return inputError(optsErr)
+			}
+			style := converters.StyleOptions{StyleHeader: f.styleHeader, FreezeHeader: f.styleHeader, AutoWidth: f.autoWidth, CoerceTypes: f.types}
+			ctx, cancel := commandContext(cmd)
+			defer cancel()
+			report, mergeErr := converters.MergeWorkbooks(ctx, strings.TrimSpace(f.out), args, opts, style)
+			if mergeErr != nil {
+				return conversionError(mergeErr)
+			}
+			if len(f.report) > 0 {
+				if err := writeMergeReport(f.report, report); err != nil {
+					return exitError{code: ErrWriteFile, err: err}
+				}
+			}
+			return nil
+		},
+	}
+	flags := cmd.Flags()
+	f.read.register(flags, "merge from every workbook")
+	flags.StringVar(&f.out, "out", "", "The path of the merged .xlsx file to write")
+	flags.StringVar(&f.headers, "headers", "union", "How the columns are reconciled: union, intersect or strict")
+	flags.StringVar(&f.aliases, "aliases", "", "Comma separated alternative column names, e.g. Qty=Quantity,Cust=Customer")
+	flags.StringVar(&f.sourceColumn, "source-column", "", "Name of a column added to hold the file every record comes from")
+	flags.StringVar(&f.sheetColumn, "sheet-column", "", "Name of a column added to hold the worksheet every record comes from")
+	flags.StringVar(&f.report, "report", "", "Path of a JSON file to write the columns every worksheet lacks or holds in excess to")
+	flags.BoolVar(&f.styleHeader, "style-header", false, "Write the header row in bold and freeze it")
+	flags.BoolVar(&f.autoWidth, "autowidth", false, "Size every column to its longest value")
+	flags.BoolVar(&f.types, "types", false, "Write numbers, booleans and dates as such instead of as text")
+	return cmd
+}
+
+// options turns the flags into merge options.
+func (f *mergeFlags) options() (opts converters.MergeOptions, err error) {
+	if opts.ReadOptions, err = f.read.readOptions(); err != nil {
+		return opts, err
+	}
+	if opts.Sheets, err = f.read.selector(); err != nil {
+		return opts, err
+	}
+	opts.SourceColumn = f.sourceColumn
+	opts.SheetColumn = f.sheetColumn
+	if len(f.aliases) > 0 {
+		opts.HeaderAliases = make(map[string]string)
+		for _, pair := range strings.Split(f.aliases, ",") {
+			alias, column, found := strings.Cut(pair, "=")
+			if !found || len(strings.TrimSpace(alias)) < 1 || len(strings.TrimSpace(column)) < 1 {
+				return opts, fmt.Errorf("--aliases expects alias=column pairs, got '%s'", pair)
+			}
+			opts.HeaderAliases[strings.TrimSpace(alias)] = strings.TrimSpace(column)
+		}
+	}
+	opts.Headers, err = converters.ParseHeaderMode(f.headers)
+	return opts, err
+}
+
+// writeMergeReport writes the merge report as JSON to the file at path.
+func writeMergeReport(path string, report converters.MergeReport) (writeErr error) {
+	file, createErr := os.Create(path)
+	if createErr != nil {
+		return createErr
+	}
+	defer func(file *os.File) {
+		if err := file.Close(); err != nil && writeErr == nil {
+			writeErr = err
+		}
+	}(file)
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"GoTools/pkg/converters"
+	"GoTools/pkg/datatable"
+	"github.com/spf13/cobra"
+)
+
+// previewFlags holds the flags of the preview subcommand.
+type previewFlags struct {
+	read  readFlags
+	rows  int
+	mode  string
+	seed  int64
+	types bool
+}
+
+// newPreviewCommand returns the preview subcommand, showing some records of a worksheet as aligned columns.
+func newPreviewCommand() *cobra.Command {
+	var f previewFlags
+	cmd := &cobra.Command{
+		Use:   "preview [flags] <workbook>",
+		Short: "Show the first, the last or a sample of the records of a worksheet",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := f.read.checkInput(args[0]); err != nil {
+				return err
+			}
+			opts, optsErr := f.options(cmd.Flags().Changed("seed"))
+			if optsErr != nil {
+				return inputError(optsErr)
+			}
+			ctx, cancel := commandContext(cmd)
+			defer cancel()
+			table, previewErr := converters.PreviewTable(ctx, args[0], opts)
+			if previewErr != nil {
+				return conversionError(previewErr)
+			}
+			if err := writeTable(cmd.OutOrStdout(), table, f.types); err != nil {
+				return outputError(err)
+			}
+			return nil
+		},
+	}
+	flags := cmd.Flags()
+	f.read.register(flags, "preview")
+	flags.IntVar(&f.rows, "rows", 20, "Number of records shown")
+	flags.StringVar(&f.mode, "mode", "head", "Records shown: head for the first ones, tail for the last ones or sample for a random sample")
+	flags.Int64Var(&f.seed, "seed", 0, "Seed of the random sample; the same seed shows the same records (default the current time)")
+	flags.BoolVar(&f.types, "types", false, "Write the type inferred for every column below its name")
+	return cmd
+}
+
+// options turns the flags into preview options, seeded with the current time unless seeded says --seed was given.
+func (f *previewFlags) options(seeded bool) (opts converters.PreviewOptions, err error) {
+	if opts.ReadOptions, err = f.read.readOptions(); err != nil {
+		return opts, err
+	}
+	if opts.Sheets, err = f.read.selector(); err != nil {
+		return opts, err
+	}
+	opts.Rows = f.rows
+	if opts.Mode, err = converters.ParsePreviewMode(f.mode); err != nil {
+		return opts, err
+	}
+	opts.Seed = f.seed
+	if opts.Mode == converters.PreviewSample && !seeded {
+		opts.Seed = time.Now().UnixNano()
+	}
+	opts.InferTypes = f.types
+	return opts, nil
+}
+
+// writeTable writes the columns and the records of table to w as columns aligned with spaces, with the types of
+// the columns below their names when types is set.
+func writeTable(w io.Writer, table *datatable.DataTable, types bool) error {
+	aligned := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	line := func(values []string) error {
+		for index, value := range values {
+			values[index] = strings.NewReplacer("\t", " ", "\r\n", " ", "\n", " ").Replace(value)
+		}
+		_, err := aligned.Write([]byte(strings.Join(values, "\t") + "\n"))
+		return err
+	}
+	if err := line(table.ColumnNames()); err != nil {
+		return err
+	}
+	if types {
+		columns := table.Columns()
+		names := make([]string, len(columns))
+		for index, column := range columns {
+			names[index] = "(" + column.Type.String() + ")"
+		}
+		if err := line(names); err != nil {
+			return err
+		}
+	}
+	if err := table.Each(func(row datatable.Row) error { return line(row.Values()) }); err != nil {
+		return err
+	}
+	return aligned.Flush()
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"GoTools/pkg/converters"
+	"github.com/spf13/cobra"
+)
+
+// profileFlags holds the flags of the profile subcommand.
+type profileFlags struct {
+	read       readFlags
+	format     string
+	sampleSize int
+	confidence float64
+}
+
+// newProfileCommand returns the profile subcommand, describing the columns of worksheets.
+func newProfileCommand() *cobra.Command {
+	var f profileFlags
+	cmd := &cobra.Command{
+		Use:   "profile [flags] <workbook>",
+		Short: "Report the types, blanks, distinct values, ranges and lengths of the columns of worksheets",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := f.read.checkInput(args[0]); err != nil {
+				return err
+			}
+			opts, optsErr := f.options()
+			if optsErr != nil {
+				return inputError(optsErr)
+			}
+			ctx, cancel := commandContext(cmd)
+			defer cancel()
+			profile, profileErr := converters.ProfileWorkbook(ctx, args[0], opts)
+			if profileErr != nil {
+				return conversionError(profileErr)
+			}
+			return writeOutput(cmd, func(w io.Writer) error {
+				if f.format == "html" {
+					return profile.WriteHtml(w)
+				}
+				return profile.WriteJson(w)
+			})
+		},
+	}
+	flags := cmd.Flags()
+	f.read.register(flags, "profile")
+	flags.StringVar(&f.format, "format", "json", "Format of the report: json or html")
+	flags.IntVar(&f.sampleSize, "sample-size", 0, "Number of values the type of a column is inferred from (default every value)")
+	flags.Float64Var(&f.confidence, "min-confidence", 1, "Share of the values which must be valid for a type for a column to be given it")
+	return cmd
+}
+
+// options turns the flags into profiling options.
+func (f *profileFlags) options() (opts converters.ProfileOptions, err error) {
+	if f.format != "json" && f.format != "html" {
+		return opts, fmt.Errorf("unknown report format '%s'", f.format)
+	}
+	if opts.ReadOptions, err = f.read.readOptions(); err != nil {
+		return opts, err
+	}
+	if opts.Sheets, err = f.read.selector(); err != nil {
+		return opts, err
+	}
+	opts.Inference = converters.InferenceOptions{SampleSize: f.sampleSize, MinConfidence: f.confidence}
+	return opts, nil
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"GoTools/pkg/converters"
+	"GoTools/pkg/fixedwidth"
+	. "GoTools/pkg/helpers"
+	"GoTools/pkg/transforms"
+	"github.com/spf13/pflag"
+)
+
+// readFlags holds the flags controlling how the rows of a workbook are read, the same for every subcommand
+// reading one.
+type readFlags struct {
+	sheet          string
+	sheets         string
+	dataRange      string
+	region         bool
+	nullTokens     string
+	transforms     string
+	include        string
+	exclude        string
+	pattern        string
+	order          string
+	where          string
+	merged         bool
+	skipHiddenRows bool
+	skipHiddenCols bool
+	formulas       string
+	header         int
+	skip           int
+	footer         int
+	password       string
+	fixedWidth     string
+	fixedEncoding  string
+}
+
+// register adds the flags to flags, verb saying what the subcommand does with the worksheets, such as convert.
+func (f *readFlags) register(flags *pflag.FlagSet, verb string) {
+	flags.StringVar(&f.sheet, "sheet", "", "The name of the worksheet to "+verb)
+	flags.StringVar(&f.sheets, "sheets", "", "Worksheets to "+verb+": all, index:<n>, regex:<expr> or name:<name>")
+	flags.StringVar(&f.dataRange, "range", "", "Named range or Excel table to read instead of the whole worksheet, found on whichever worksheet holds it")
+	flags.BoolVar(&f.region, "detect-region", false, "Detect where the data starts and ends, skipping blank leading rows and columns, blank rows and footer notes")
+	flags.StringVar(&f.nullTokens, "null-tokens", "", "Comma separated values, such as N/A or #N/A, read as empty cells; default for the common ones")
+	flags.StringVar(&f.transforms, "transforms", "", "Path to a YAML or JSON file configuring the transforms cleaning the values of columns, such as trim, date and map")
+	flags.StringVar(&f.include, "include", "", "Comma separated list of the only column headers to read")
+	flags.StringVar(&f.exclude, "exclude", "", "Comma separated list of column headers to leave out")
+	flags.StringVar(&f.pattern, "columns", "", "Regular expression column headers must match to be read")
+	flags.StringVar(&f.order, "order", "sheet", "Column order: sheet, alphabetical or names:<column>,<column>,...")
+	flags.StringVar(&f.where, "where", "", `Only read rows matching the expression, e.g. 'Status != "Cancelled"'`)
+	flags.BoolVar(&f.merged, "merged", false, "Copy the value of merged cells into every cell they cover")
+	flags.BoolVar(&f.skipHiddenRows, "skip-hidden-rows", false, "Leave out the rows hidden in the workbook; the header row is always read")
+	flags.BoolVar(&f.skipHiddenCols, "skip-hidden-cols", false, "Leave out the columns hidden in the workbook, which loads the whole worksheet into memory")
+	flags.StringVar(&f.formulas, "formulas", "cached", "Value of formula cells: cached, text or evaluate")
+	flags.IntVar(&f.header, "header", 0, "Zero-based index of the row holding the column names")
+	flags.IntVar(&f.skip, "skip", 0, "Number of rows to skip directly below the header row")
+	flags.IntVar(&f.footer, "skip-footer", 0, "Number of rows to skip at the end of the worksheet")
+	flags.StringVar(&f.password, "password-env", "XLSX_PASSWORD", "Environment variable holding the password of an encrypted workbook")
+	flags.StringVar(&f.fixedWidth, "fixed-width", "", "Layout file of the fields of a fixed-width text file to read instead of a workbook")
+	flags.StringVar(&f.fixedEncoding, "fixed-width-encoding", "UTF-8", "Encoding of the fixed-width text file: UTF-8, UTF-16, UTF-16LE, UTF-16BE, ISO-8859-1 or Windows-1252")
+}
+
+// readOptions turns the flags into the options reading the rows of a workbook.
+func (f *readFlags) readOptions() (opts converters.ReadOptions, err error) {
+	opts.ResolveMergedCells = f.merged
+	opts.SkipHiddenRows = f.skipHiddenRows
+	opts.SkipHiddenColumns = f.skipHiddenCols
+	opts.Password = converters.PasswordFromEnv(f.password)
+	opts.HeaderRowIndex = f.header
+	opts.SkipRows = f.skip
+	opts.SkipFooterRows = f.footer
+	opts.Range = f.dataRange
+	opts.Region.Detect = f.region
+	opts.NullTokens = ParseNullTokens(f.nullTokens)
+	if len(f.include) > 0 {
+		opts.Columns.Include = strings.Split(f.include, ",")
+	}
+	if len(f.exclude) > 0 {
+		opts.Columns.Exclude = strings.Split(f.exclude, ",")
+	}
+	if len(f.pattern) > 0 {
+		if opts.Columns.Pattern, err = regexp.Compile(f.pattern); err != nil {
+			return opts, err
+		}
+	}
+	if opts.ColumnOrder, err = converters.ParseColumnOrder(f.order); err != nil {
+		return opts, err
+	}
+	if len(f.where) > 0 {
+		if opts.RowFilter, err = converters.ParseRowFilter(f.where); err != nil {
+			return opts, err
+		}
+	}
+	if len(f.transforms) > 0 {
+		if opts.Transforms, err = transforms.LoadConfig(f.transforms); err != nil {
+			return opts, err
+		}
+	}
+	if len(f.fixedWidth) > 0 {
+		if opts.FixedWidth, err = fixedwidth.LoadLayout(f.fixedWidth); err != nil {
+			return opts, err
+		}
+		opts.FixedWidthEncoding = f.fixedEncoding
+	}
+	opts.Formulas, err = converters.ParseFormulaMode(f.formulas)
+	return opts, err
+}
+
+// selector returns the worksheets selected by the flags, a worksheet name given with --sheet taking precedence
+// over a --sheets selector.
+func (f *readFlags) selector() (converters.SheetSelector, error) {
+	if len(f.sheet) > 0 {
+		return converters.SheetByName(f.sheet), nil
+	}
+	return converters.ParseSheetSelector(f.sheets)
+}
+
+// checkInput checks that path names a workbook, or a text file read with --fixed-width.
+func (f *readFlags) checkInput(path string) error {
+	if len(strings.TrimSpace(path)) < 1 {
+		return inputError(errors.New("no workbook path provided"))
+	}
+	if exists, pathErr := PathExists(path); pathErr != nil || !exists {
+		if pathErr == nil {
+			pathErr = fmt.Errorf("no such file: %s", path)
+		}
+		return exitError{code: ErrNoFile, err: pathErr}
+	}
+	if len(f.fixedWidth) < 1 && !CheckExtension(path, ".xlsx") && !CheckExtension(path, ".xls") && !CheckExtension(path, ".ods") {
+		return exitError{code: ErrInvalidFileType, err: fmt.Errorf("invalid file type: %s", path)}
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"GoTools/pkg/converters"
+	. "GoTools/pkg/helpers"
+	"github.com/spf13/cobra"
+)
+
+// runFlags holds the flags of the run subcommand.
+type runFlags struct {
+	input       string
+	output      string
+	concurrency int
+	failFast    bool
+}
+
+// newRunCommand returns the run subcommand, running the conversions described by pipeline configuration files.
+func newRunCommand() *cobra.Command {
+	var f runFlags
+	cmd := &cobra.Command{
+		Use:   "run [flags] <config>...",
+		Short: "Run the conversions described by YAML or JSON pipeline configuration files",
+		Long: "Run the conversions described by pipeline configuration files, each naming the worksheet to read, " +
+			"how to map, select, transform and filter its columns and rows, and the file to write, so conversions " +
+			"can be versioned along with the workbooks they convert. A line per configuration reports whether it " +
+			"converted; any failure fails the command once every conversion is done.",
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if (len(f.input) > 0 || len(f.output) > 0) && len(args) > 1 {
+				return inputError(errors.New("--input and --output only apply to a single configuration"))
+			}
+			if f.concurrency < 0 {
+				return inputError(fmt.Errorf("invalid concurrency %d", f.concurrency))
+			}
+			jobs := make([]converters.Job, len(args))
+			files := make([]batchFile, len(args))
+			for index, path := range args {
+				pipeline, loadErr := f.pipeline(path)
+				if loadErr != nil {
+					return loadErr
+				}
+				jobs[index] = pipeline.Job()
+				files[index] = batchFile{path: pipeline.Input, outPath: pipeline.Output}
+			}
+			ctx, cancel := commandContext(cmd)
+			defer cancel()
+			results, runErr := converters.RunJobs(ctx, jobs, converters.EngineOptions{Concurrency: f.concurrency, FailFast: f.failFast})
+			if err := writeSummary(cmd.OutOrStdout(), files, results); err != nil {
+				return outputError(err)
+			}
+			if runErr != nil {
+				return conversionError(runErr)
+			}
+			return nil
+		},
+	}
+	flags := cmd.Flags()
+	flags.StringVar(&f.input, "input", "", "Workbook to read instead of the source path of the configuration")
+	flags.StringVar(&f.output, "output", "", "File to write instead of the sink path of the configuration, in the format of its extension unless the configuration sets one")
+	flags.IntVar(&f.concurrency, "concurrency", 0, "Number of conversions run at a time (default the number of CPUs)")
+	flags.BoolVar(&f.failFast, "fail-fast", false, "Stop as soon as a conversion fails instead of running every other one")
+	return cmd
+}
+
+// pipeline returns the conversion configured by the file at path, its paths replaced by --input and --output.
+func (f *runFlags) pipeline(path string) (*converters.Pipeline, error) {
+	config, loadErr := converters.LoadPipelineConfig(path)
+	if errors.Is(loadErr, os.ErrNotExist) {
+		return nil, exitError{code: ErrNoFile, err: fmt.Errorf("no such file: %s", path)}
+	}
+	if loadErr != nil {
+		return nil, inputError(loadErr)
+	}
+	if len(f.input) > 0 {
+		config.Source.Path = f.input
+	}
+	if len(f.output) > 0 {
+		config.Sink.Path = f.output
+	}
+	pipeline, err := config.Pipeline()
+	if err != nil {
+		return nil, inputError(fmt.Errorf("%s: %w", path, err))
+	}
+	return pipeline, nil
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"errors"
+	"strings"
+
+	"GoTools/pkg/converters"
+	. "GoTools/pkg/helpers"
+	"github.com/spf13/cobra"
+)
+
+// splitFlags holds the flags of the split subcommand.
+type splitFlags struct {
+	read        readFlags
+	column      string
+	format      string
+	outDir      string
+	name        string
+	blank       string
+	dropColumn  bool
+	slug        bool
+	styleHeader bool
+	autoWidth   bool
+	types       bool
+}
+
+// newSplitCommand returns the split subcommand, writing a file per value of a column of a worksheet.
+func newSplitCommand() *cobra.Command {
+	var f splitFlags
+	cmd := &cobra.Command{
+		Use:   "split [flags] <workbook>",
+		Short: "Split a worksheet into one file per value of one of its columns",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := f.read.checkInput(args[0]); err != nil {
+				return err
+			}
+			opts, optsErr := f.options()
+			if optsErr != nil {
+				return inputError(optsErr)
+			}
+			ctx, cancel := commandContext(cmd)
+			defer cancel()
+			if _, err := converters.SplitWorkbook(ctx, f.outDir, args[0], opts); err != nil {
+				return conversionError(err)
+			}
+			return nil
+		},
+	}
+	flags := cmd.Flags()
+	f.read.register(flags, "split")
+	flags.StringVar(&f.column, "column", "", "The column whose values key the files")
+	flags.StringVar(&f.format, "format", "xlsx", "Format of the files written: xlsx, csv or xml")
+	flags.StringVar(&f.outDir, "out", ".", "Directory to write the files into")
+	flags.StringVar(&f.name, "name", "", "File name of the parts, {value} standing for the column value (default <workbook>_{value})")
+	flags.StringVar(&f.blank, "blank", "blank", "Value naming the file of the records whose column is blank")
+	flags.BoolVar(&f.dropColumn, "drop-column", false, "Leave the split column out of the files")
+	flags.BoolVar(&f.slug, "slug", false, "Write the file names as lower case ASCII with dashes, such as sales_south-east for South East")
+	flags.BoolVar(&f.styleHeader, "style-header", false, "Write the header row of .xlsx files in bold and freeze it")
+	flags.BoolVar(&f.autoWidth, "autowidth", false, "Size every column of .xlsx files to its longest value")
+	flags.BoolVar(&f.types, "types", false, "Write numbers, booleans and dates into .xlsx files as such instead of as text")
+	return cmd
+}
+
+// options turns the flags into split options.
+func (f *splitFlags) options() (opts converters.SplitOptions, err error) {
+	if len(strings.TrimSpace(f.column)) < 1 {
+		return opts, errors.New("no column provided with the --column flag")
+	}
+	if opts.ReadOptions, err = f.read.readOptions(); err != nil {
+		return opts, err
+	}
+	if opts.Sheets, err = f.read.selector(); err != nil {
+		return opts, err
+	}
+	opts.Column = f.column
+	opts.FileName = f.name
+	opts.BlankName = f.blank
+	opts.DropColumn = f.dropColumn
+	if f.slug {
+		opts.Slug = SlugOptions{Replacement: "-", ReplaceSpaces: true, Lower: true, ASCII: true}
+	}
+	opts.Style = converters.StyleOptions{StyleHeader: f.styleHeader, FreezeHeader: f.styleHeader, AutoWidth: f.autoWidth, CoerceTypes: f.types}
+	opts.Format, err = converters.ParseSplitFormat(f.format)
+	return opts, err
+}
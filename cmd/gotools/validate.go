@@ -0,0 +1,72 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"GoTools/pkg/converters"
+	"github.com/spf13/cobra"
+)
+
+// validateFlags holds the flags of the validate subcommand.
+type validateFlags struct {
+	read   readFlags
+	schema string
+	root   string
+	row    string
+	attrs  bool
+}
+
+// newValidateCommand returns the validate subcommand, checking the XML documents worksheets convert into
+// against an XML Schema without writing them.
+func newValidateCommand() *cobra.Command {
+	var f validateFlags
+	cmd := &cobra.Command{
+		Use:   "validate [flags] <workbook>",
+		Short: "Check that worksheets convert into XML conforming to a schema",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := f.read.checkInput(args[0]); err != nil {
+				return err
+			}
+			opts, optsErr := f.options()
+			if optsErr != nil {
+				return inputError(optsErr)
+			}
+			ctx, cancel := commandContext(cmd)
+			defer cancel()
+			if err := converters.XlsxToXml(ctx, io.Discard, args[0], opts); err != nil {
+				return conversionError(err)
+			}
+			if _, err := fmt.Fprintf(cmd.OutOrStdout(), "%s conforms to %s\n", args[0], f.schema); err != nil {
+				return outputError(err)
+			}
+			return nil
+		},
+	}
+	flags := cmd.Flags()
+	f.read.register(flags, "validate")
+	flags.StringVar(&f.schema, "schema", "", "Path to the XML Schema the documents must conform to")
+	flags.StringVar(&f.root, "root", "", "Name of the element holding the rows of a worksheet (default DataTable)")
+	flags.StringVar(&f.row, "row", "", "Name of the element of each row (default Row)")
+	flags.BoolVar(&f.attrs, "attributes", false, "Emit each column as an attribute of the row element instead of a child element")
+	return cmd
+}
+
+// options turns the flags into the options of a conversion validating its output and streaming it.
+func (f *validateFlags) options() (opts converters.Options, err error) {
+	if len(f.schema) < 1 {
+		return opts, errors.New("no schema provided with the --schema flag")
+	}
+	if opts.ReadOptions, err = f.read.readOptions(); err != nil {
+		return opts, err
+	}
+	if opts.Sheets, err = f.read.selector(); err != nil {
+		return opts, err
+	}
+	opts.Streaming = true
+	opts.Xml = converters.XmlOptions{RootName: f.root, RowName: f.row, Attributes: f.attrs}
+	opts.Validate, err = converters.LoadSchema(f.schema)
+	return opts, err
+}
@@ -0,0 +1,316 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"GoTools/pkg/converters"
+	. "GoTools/pkg/helpers"
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/cobra"
+)
+
+// watchFlags holds the flags of the watch subcommand.
+type watchFlags struct {
+	read         readFlags
+	outDir       string
+	format       string
+	processedDir string
+	failedDir    string
+	patterns     string
+	settle       time.Duration
+	concurrency  int
+}
+
+// newWatchCommand returns the watch subcommand, converting the files dropped into a folder as they arrive.
+func newWatchCommand() *cobra.Command {
+	var f watchFlags
+	cmd := &cobra.Command{
+		Use:   "watch [flags] <folder>",
+		Short: "Convert the workbooks dropped into a folder as they arrive",
+		Long: "Watch a folder and convert every workbook dropped into it, and those already there, into the --out " +
+			"directory. A file is converted once it has not changed for --settle, so files still being copied are " +
+			"left alone. Converted files are moved into the processed folder, the others into the failed folder " +
+			"next to a .error file telling why. The command runs until interrupted.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			folder, folderErr := f.hotFolder(args[0], cmd.OutOrStdout())
+			if folderErr != nil {
+				return inputError(folderErr)
+			}
+			ctx, cancel := commandContext(cmd)
+			defer cancel()
+			if err := folder.watch(ctx); err != nil {
+				return exitError{code: ErrReadFile, err: err}
+			}
+			return nil
+		},
+	}
+	flags := cmd.Flags()
+	f.read.register(flags, "convert")
+	flags.StringVar(&f.outDir, "out", "", "Directory to write the converted files into")
+	flags.StringVar(&f.format, "format", "xml", "Format of the converted files: xml, csv, json, parquet or xlsx")
+	flags.StringVar(&f.processedDir, "processed", "", "Folder the converted files are moved into (default <folder>/processed)")
+	flags.StringVar(&f.failedDir, "failed", "", "Folder the files which fail to convert are moved into (default <folder>/failed)")
+	flags.StringVar(&f.patterns, "pattern", "*.xlsx,*.xls,*.ods", "Comma separated patterns the names of the files converted match")
+	flags.DurationVar(&f.settle, "settle", 2*time.Second, "How long a file must stay unchanged before it is converted")
+	flags.IntVar(&f.concurrency, "concurrency", 0, "Number of files converted at a time (default the number of CPUs)")
+	return cmd
+}
+
+// hotFolder converts the files dropped into a folder.
+type hotFolder struct {
+	dir          string
+	outDir       string
+	processedDir string
+	failedDir    string
+	ext          string
+	patterns     []string
+	settle       time.Duration
+	concurrency  int
+	read         *readFlags
+	opts         converters.ReadOptions
+	sheets       converters.SheetSelector
+	openSink     func(path string) (converters.RowSink, error)
+	report       io.Writer
+	pending      map[string]*arrival
+}
+
+// arrival is a file of the folder waiting to be complete: its size and modification time when last seen and
+// when they last changed.
+type arrival struct {
+	size    int64
+	modTime time.Time
+	changed time.Time
+}
+
+// hotFolder turns the flags into the hot folder dir, reporting the files converted to report.
+func (f *watchFlags) hotFolder(dir string, report io.Writer) (*hotFolder, error) {
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("'%s' is not a folder", dir)
+	}
+	openSink, known := batchFormats[f.format]
+	if !known {
+		return nil, fmt.Errorf("unknown output format '%s'", f.format)
+	}
+	if len(strings.TrimSpace(f.outDir)) < 1 {
+		return nil, errors.New("no output directory provided with the --out flag")
+	}
+	if sameDir(f.outDir, dir) {
+		return nil, fmt.Errorf("the output directory '%s' must not be the watched folder", f.outDir)
+	}
+	if f.settle < 0 || f.concurrency < 0 {
+		return nil, errors.New("--settle and --concurrency must not be negative")
+	}
+	folder := &hotFolder{
+		dir:          dir,
+		outDir:       f.outDir,
+		processedDir: f.processedDir,
+		failedDir:    f.failedDir,
+		ext:          "." + f.format,
+		settle:       f.settle,
+		concurrency:  f.concurrency,
+		read:         &f.read,
+		openSink:     openSink,
+		report:       report,
+		pending:      make(map[string]*arrival),
+	}
+	if len(folder.processedDir) < 1 {
+		folder.processedDir = filepath.Join(dir, "processed")
+	}
+	if len(folder.failedDir) < 1 {
+		folder.failedDir = filepath.Join(dir, "failed")
+	}
+	for _, pattern := range strings.Split(f.patterns, ",") {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("pattern '%s': %w", pattern, err)
+		}
+		folder.patterns = append(folder.patterns, strings.TrimSpace(pattern))
+	}
+	var err error
+	if folder.opts, err = f.read.readOptions(); err != nil {
+		return nil, err
+	}
+	folder.sheets, err = f.read.selector()
+	return folder, err
+}
+
+// watch converts the files of the folder, those there already and those dropped into it, until ctx is done.
+func (h *hotFolder) watch(ctx context.Context) error {
+	for _, dir := range []string{h.outDir, h.processedDir, h.failedDir} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+	watcher, watcherErr := fsnotify.NewWatcher()
+	if watcherErr != nil {
+		return watcherErr
+	}
+	defer func(watcher *fsnotify.Watcher) {
+		_ = watcher.Close()
+	}(watcher)
+	if err := watcher.Add(h.dir); err != nil {
+		return err
+	}
+	entries, readErr := os.ReadDir(h.dir)
+	if readErr != nil {
+		return readErr
+	}
+	for _, entry := range entries {
+		h.notice(filepath.Join(h.dir, entry.Name()))
+	}
+
+	ticker := time.NewTicker(max(h.settle/4, 50*time.Millisecond))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, open := <-watcher.Events:
+			if !open {
+				return nil
+			}
+			if event.Has(fsnotify.Create) || event.Has(fsnotify.Write) {
+				h.notice(event.Name)
+			}
+		case err, open := <-watcher.Errors:
+			if !open {
+				return nil
+			}
+			Logger().Warn("Watching the folder failed", "folder", h.dir, "error", err)
+		case <-ticker.C:
+			if ready := h.ready(time.Now()); len(ready) > 0 {
+				h.convert(ctx, ready)
+			}
+		}
+	}
+}
+
+// notice starts waiting for the file at path to be complete, unless it is not a file to convert. Hidden files
+// and the lock files of Office, whose names start with ~$, are left alone.
+func (h *hotFolder) notice(path string) {
+	name := filepath.Base(path)
+	if _, waiting := h.pending[path]; waiting || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "~$") {
+		return
+	}
+	matched := false
+	for _, pattern := range h.patterns {
+		if ok, _ := filepath.Match(pattern, name); ok {
+			matched = true
+			break
+		}
+	}
+	if info, err := os.Stat(path); matched && err == nil && info.Mode().IsRegular() {
+		h.pending[path] = &arrival{size: info.Size(), modTime: info.ModTime(), changed: time.Now()}
+	}
+}
+
+// ready returns the files waiting which have not changed for the settle time by now, in the order they arrived.
+// Files which are gone are forgotten.
+func (h *hotFolder) ready(now time.Time) []string {
+	var paths []string
+	for path, file := range h.pending {
+		info, err := os.Stat(path)
+		switch {
+		case err != nil:
+			delete(h.pending, path)
+		case info.Size() != file.size || !info.ModTime().Equal(file.modTime):
+			file.size, file.modTime, file.changed = info.Size(), info.ModTime(), now
+		case now.Sub(file.changed) >= h.settle:
+			paths = append(paths, path)
+		}
+	}
+	slices.SortFunc(paths, func(a, b string) int {
+		return cmp.Or(h.pending[a].changed.Compare(h.pending[b].changed), strings.Compare(a, b))
+	})
+	for _, path := range paths {
+		delete(h.pending, path)
+	}
+	return paths
+}
+
+// convert converts the files at paths, moving each into the processed or the failed folder and reporting it.
+// A file whose output path is taken, by an earlier file or by another of paths sharing its name but not its
+// extension, is numbered as freePath does. Files whose conversion was cancelled are left in the folder, to be
+// converted when the command runs again.
+func (h *hotFolder) convert(ctx context.Context, paths []string) {
+	jobs := make([]converters.Job, len(paths))
+	outPaths := make([]string, len(paths))
+	taken := make(map[string]bool)
+	for index, path := range paths {
+		name := filepath.Base(path)
+		outPaths[index] = freePath(filepath.Join(h.outDir, strings.TrimSuffix(name, filepath.Ext(name))+h.ext), taken)
+		taken[outPaths[index]] = true
+		jobs[index] = h.read.conversionJob(path, outPaths[index], h.sheets, h.opts, h.openSink)
+	}
+	results, _ := converters.RunJobs(ctx, jobs, converters.EngineOptions{Concurrency: h.concurrency})
+	for index, result := range results {
+		if errors.Is(result.Err, context.Canceled) || errors.Is(result.Err, context.DeadlineExceeded) {
+			continue
+		}
+		if err := h.file(result); err != nil {
+			Logger().Error("Moving the file failed", "path", result.Name, "error", err)
+		}
+		if _, err := io.WriteString(h.report, resultLine(result, outPaths[index])); err != nil {
+			Logger().Error("Reporting the file failed", "path", result.Name, "error", err)
+		}
+	}
+}
+
+// file moves the file of result into the processed folder when it converted and into the failed folder with a
+// .error file holding the error otherwise.
+func (h *hotFolder) file(result converters.JobResult) error {
+	dir := h.processedDir
+	if result.Err != nil {
+		dir = h.failedDir
+	}
+	target := freePath(filepath.Join(dir, filepath.Base(result.Name)), nil)
+	if err := moveFile(result.Name, target); err != nil {
+		return err
+	}
+	if result.Err == nil {
+		return nil
+	}
+	return os.WriteFile(target+".error", []byte(result.Err.Error()+"\n"), 0o644)
+}
+
+// freePath returns path, or path with a number appended to its name when a file is there already or the path is
+// taken, such as orders-2.xlsx for orders.xlsx.
+func freePath(path string, taken map[string]bool) string {
+	ext := filepath.Ext(path)
+	candidate := path
+	for number := 2; ; number++ {
+		if exists, err := PathExists(candidate); !taken[candidate] && (err != nil || !exists) {
+			return candidate
+		}
+		candidate = fmt.Sprintf("%s-%d%s", strings.TrimSuffix(path, ext), number, ext)
+	}
+}
+
+// sameDir reports whether the paths a and b name the same directory.
+func sameDir(a, b string) bool {
+	aInfo, aErr := os.Stat(a)
+	bInfo, bErr := os.Stat(b)
+	if aErr == nil && bErr == nil {
+		return os.SameFile(aInfo, bInfo)
+	}
+	aAbs, aAbsErr := filepath.Abs(a)
+	bAbs, bAbsErr := filepath.Abs(b)
+	return aAbsErr == nil && bAbsErr == nil && aAbs == bAbs
+}
+
+// moveFile moves the file at src to dst, copying it when they are on different file systems.
+func moveFile(src, dst string) error {
+	if err := os.Rename(src, dst); err == nil {
+		return nil
+	}
+	return MoveFile(src, dst)
+}
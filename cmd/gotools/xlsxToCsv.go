@@ -0,0 +1,88 @@
+package main
+
+import (
+	"io"
+	"strings"
+
+	"GoTools/pkg/converters"
+	. "GoTools/pkg/helpers"
+	"github.com/spf13/cobra"
+)
+
+// csvFlags holds the flags of the xlsx2csv subcommand.
+type csvFlags struct {
+	read          readFlags
+	outDir        string
+	delimiter     string
+	quote         string
+	crlf          bool
+	noHeader      bool
+	encoding      string
+	bom           bool
+	transliterate bool
+}
+
+// newXlsxToCsvCommand returns the xlsx2csv subcommand, converting worksheets into CSV.
+func newXlsxToCsvCommand() *cobra.Command {
+	var f csvFlags
+	cmd := &cobra.Command{
+		Use:   "xlsx2csv [flags] <workbook>",
+		Short: "Convert worksheets into CSV files",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := f.read.checkInput(args[0]); err != nil {
+				return err
+			}
+			opts, optsErr := f.options()
+			if optsErr != nil {
+				return inputError(optsErr)
+			}
+			ctx, cancel := commandContext(cmd)
+			defer cancel()
+			if len(f.outDir) > 0 {
+				if _, err := converters.XlsxToCsvFiles(ctx, f.outDir, args[0], opts); err != nil {
+					return conversionError(err)
+				}
+				return nil
+			}
+			return writeOutput(cmd, func(w io.Writer) error {
+				return converters.XlsxToCsv(ctx, w, args[0], opts)
+			})
+		},
+	}
+	flags := cmd.Flags()
+	f.read.register(flags, "convert")
+	flags.StringVar(&f.outDir, "out", "", "Directory to write one .csv file per worksheet into, instead of stdout")
+	flags.StringVar(&f.delimiter, "delimiter", ",", `Field delimiter, "\t" for a tab`)
+	flags.StringVar(&f.quote, "quote", `"`, "Character enclosing fields that need quoting")
+	flags.BoolVar(&f.crlf, "crlf", false, "End records with CRLF instead of LF")
+	flags.BoolVar(&f.noHeader, "no-header", false, "Leave out the header row")
+	flags.StringVar(&f.encoding, "encoding", "UTF-8", "Output encoding: UTF-8, UTF-16, UTF-16LE, UTF-16BE, ISO-8859-1 or Windows-1252")
+	flags.BoolVar(&f.bom, "bom", false, "Start UTF-8 output with a byte order mark")
+	flags.BoolVar(&f.transliterate, "transliterate", false, "Spell accented letters of the header row in ASCII, e.g. Größe as Grosse")
+	return cmd
+}
+
+// options turns the flags into converter options.
+func (f *csvFlags) options() (opts converters.CsvOptions, err error) {
+	if opts.ReadOptions, err = f.read.readOptions(); err != nil {
+		return opts, err
+	}
+	if opts.Sheets, err = f.read.selector(); err != nil {
+		return opts, err
+	}
+	if opts.Delimiter, err = SingleRune("--delimiter", strings.ReplaceAll(f.delimiter, `\t`, "\t")); err != nil {
+		return opts, err
+	}
+	if opts.Quote, err = SingleRune("--quote", f.quote); err != nil {
+		return opts, err
+	}
+	if f.crlf {
+		opts.Newline = "\r\n"
+	}
+	opts.OmitHeader = f.noHeader
+	opts.Encoding = f.encoding
+	opts.ByteOrderMark = f.bom
+	opts.TransliterateHeaders = f.transliterate
+	return opts, nil
+}
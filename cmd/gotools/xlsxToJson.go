@@ -0,0 +1,63 @@
+package main
+
+import (
+	"io"
+
+	"GoTools/pkg/converters"
+	"github.com/spf13/cobra"
+)
+
+// jsonFlags holds the flags of the xlsx2json subcommand.
+type jsonFlags struct {
+	read    readFlags
+	combine bool
+	layout  string
+	types   bool
+	lines   bool
+}
+
+// newXlsxToJsonCommand returns the xlsx2json subcommand, converting worksheets into JSON.
+func newXlsxToJsonCommand() *cobra.Command {
+	var f jsonFlags
+	cmd := &cobra.Command{
+		Use:   "xlsx2json [flags] <workbook>",
+		Short: "Convert worksheets into JSON",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := f.read.checkInput(args[0]); err != nil {
+				return err
+			}
+			opts, optsErr := f.options()
+			if optsErr != nil {
+				return inputError(optsErr)
+			}
+			ctx, cancel := commandContext(cmd)
+			defer cancel()
+			return writeOutput(cmd, func(w io.Writer) error {
+				return converters.XlsxToJson(ctx, w, args[0], opts)
+			})
+		},
+	}
+	flags := cmd.Flags()
+	f.read.register(flags, "convert")
+	flags.BoolVar(&f.combine, "combine", false, "Combine the worksheets into a single object keyed by worksheet name")
+	flags.StringVar(&f.layout, "layout", "records", "Layout of each worksheet: records (array of objects) or columns (object of arrays)")
+	flags.BoolVar(&f.types, "types", false, "Write numbers and booleans as JSON numbers and booleans, empty cells as null")
+	flags.BoolVar(&f.lines, "ndjson", false, "Write newline delimited JSON, one record per line")
+	return cmd
+}
+
+// options turns the flags into converter options.
+func (f *jsonFlags) options() (opts converters.JsonOptions, err error) {
+	if opts.ReadOptions, err = f.read.readOptions(); err != nil {
+		return opts, err
+	}
+	if opts.Sheets, err = f.read.selector(); err != nil {
+		return opts, err
+	}
+	opts.Combine = f.combine
+	opts.InferTypes = f.types
+	opts.Lines = f.lines
+	opts.Layout, err = converters.ParseJsonLayout(f.layout)
+	return opts, err
+}
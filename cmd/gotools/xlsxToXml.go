@@ -0,0 +1,106 @@
+package main
+
+import (
+	"io"
+	"strings"
+
+	"GoTools/pkg/converters"
+	"github.com/spf13/cobra"
+)
+
+// xmlFlags holds the flags of the xlsx2xml subcommand.
+type xmlFlags struct {
+	read     readFlags
+	outDir   string
+	combine  bool
+	stream   bool
+	root     string
+	row      string
+	attrs    bool
+	types    bool
+	empty    string
+	indent   string
+	compact  bool
+	encoding string
+	bom      bool
+	decl     bool
+	validate string
+}
+
+// newXlsxToXmlCommand returns the xlsx2xml subcommand, converting worksheets into DataTable documents.
+func newXlsxToXmlCommand() *cobra.Command {
+	var f xmlFlags
+	cmd := &cobra.Command{
+		Use:   "xlsx2xml [flags] <workbook>",
+		Short: "Convert worksheets into XML documents",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := f.read.checkInput(args[0]); err != nil {
+				return err
+			}
+			opts, optsErr := f.options()
+			if optsErr != nil {
+				return inputError(optsErr)
+			}
+			ctx, cancel := commandContext(cmd)
+			defer cancel()
+			if len(f.outDir) > 0 {
+				if _, err := converters.XlsxToXmlFiles(ctx, f.outDir, args[0], opts); err != nil {
+					return conversionError(err)
+				}
+				return nil
+			}
+			return writeOutput(cmd, func(w io.Writer) error {
+				return converters.XlsxToXml(ctx, w, args[0], opts)
+			})
+		},
+	}
+	flags := cmd.Flags()
+	f.read.register(flags, "convert")
+	flags.StringVar(&f.outDir, "out", "", "Directory to write one .xml file per worksheet into, instead of stdout")
+	flags.BoolVar(&f.combine, "combine", false, "Combine the worksheets into a single document with a <Sheet> element per worksheet")
+	flags.BoolVar(&f.stream, "stream", false, "Write rows as they are read to keep memory bounded on very large worksheets")
+	flags.StringVar(&f.root, "root", "", "Name of the element holding the rows of a worksheet (default DataTable)")
+	flags.StringVar(&f.row, "row", "", "Name of the element of each row (default Row)")
+	flags.BoolVar(&f.attrs, "attributes", false, "Emit each column as an attribute of the row element instead of a child element")
+	flags.BoolVar(&f.types, "types", false, "Annotate cells with the xsi:type inferred from their value")
+	flags.StringVar(&f.empty, "empty", "empty", "Empty cells: empty (empty element), omit or nil (xsi:nil=\"true\")")
+	flags.StringVar(&f.indent, "indent", "", `Indentation of every nesting level, spaces or "\t" for a tab (default two spaces)`)
+	flags.BoolVar(&f.compact, "compact", false, "Write each document on a single line without indentation")
+	flags.StringVar(&f.encoding, "encoding", "UTF-8", "Output encoding: UTF-8, UTF-16, UTF-16LE, UTF-16BE or ISO-8859-1")
+	flags.BoolVar(&f.bom, "bom", false, "Start UTF-8 output with a byte order mark")
+	flags.BoolVar(&f.decl, "declaration", false, "Write the XML declaration, always written for encodings other than UTF-8")
+	flags.StringVar(&f.validate, "validate", "", "Path to an XML Schema the output must conform to")
+	return cmd
+}
+
+// options turns the flags into converter options.
+func (f *xmlFlags) options() (opts converters.Options, err error) {
+	if opts.ReadOptions, err = f.read.readOptions(); err != nil {
+		return opts, err
+	}
+	if opts.Sheets, err = f.read.selector(); err != nil {
+		return opts, err
+	}
+	opts.Combine = f.combine
+	opts.Streaming = f.stream
+	if len(f.validate) > 0 {
+		if opts.Validate, err = converters.LoadSchema(f.validate); err != nil {
+			return opts, err
+		}
+	}
+	opts.Xml, err = f.xmlOptions()
+	return opts, err
+}
+
+// xmlOptions turns the flags shaping the documents into XML options.
+func (f *xmlFlags) xmlOptions() (opts converters.XmlOptions, err error) {
+	opts = converters.XmlOptions{RootName: f.root, RowName: f.row, Attributes: f.attrs, InferTypes: f.types}
+	opts.Indent = strings.ReplaceAll(f.indent, `\t`, "\t")
+	opts.Compact = f.compact
+	opts.Encoding = f.encoding
+	opts.ByteOrderMark = f.bom
+	opts.Declaration = f.decl
+	opts.EmptyCells, err = converters.ParseEmptyCellMode(f.empty)
+	return opts, err
+}
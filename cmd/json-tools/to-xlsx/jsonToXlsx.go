@@ -0,0 +1,93 @@
+// This program builds a .xlsx workbook from a file of JSON records, flattening nested objects into columns.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"GoTools/pkg/converters"
+	. "GoTools/pkg/helpers"
+)
+
+// cliOptions holds the command line flags.
+type cliOptions struct {
+	path           string
+	out            string
+	sheet          string
+	separator      string
+	arrays         string
+	arrayDelimiter string
+	styleHeader    bool
+	autoWidth      bool
+	types          bool
+	timeout        time.Duration
+}
+
+var cli cliOptions
+
+func main() {
+	processingErr := ErrMsg{Code: Success}
+	defer func() {
+		processingErr.Exit()
+	}()
+	flag.StringVar(&cli.path, "path", "", "The path to the JSON or newline delimited JSON file to convert")
+	flag.StringVar(&cli.out, "out", "", "The path of the .xlsx file to write")
+	flag.StringVar(&cli.sheet, "sheet", "", "The name of the worksheet (default the file name)")
+	flag.StringVar(&cli.separator, "separator", ".", "Separator joining the keys of nested objects into column names")
+	flag.StringVar(&cli.arrays, "arrays", "join", "Layout of arrays: join (delimited cells) or explode (repeated rows)")
+	flag.StringVar(&cli.arrayDelimiter, "array-delimiter", "; ", "Delimiter joining the elements of arrays")
+	flag.BoolVar(&cli.styleHeader, "style-header", false, "Write the header row in bold and freeze it")
+	flag.BoolVar(&cli.autoWidth, "autowidth", false, "Size every column to its longest value")
+	flag.BoolVar(&cli.types, "types", false, "Write strings holding numbers, booleans and dates as such instead of as text")
+	flag.DurationVar(&cli.timeout, "timeout", 0, "Abort the conversion once it has run this long, e.g. 90s or 10m (default no limit)")
+	flag.Parse()
+
+	filePath := strings.TrimSpace(cli.path)
+	if len(filePath) < 1 {
+		processingErr = ErrMsg{Err: errors.New("no JSON path provided with the -path flag"), Code: ErrNoInput}
+		return
+	}
+	if exists, pathErr := PathExists(filePath); pathErr != nil || !exists {
+		processingErr = ErrMsg{Err: pathErr, Code: ErrNoFile}
+		return
+	}
+	outPath := strings.TrimSpace(cli.out)
+	if !CheckExtension(outPath, ".xlsx") {
+		processingErr = ErrMsg{Err: errors.New("the -out flag must name a .xlsx file"), Code: ErrInvalidFileType}
+		return
+	}
+	arrays, arraysErr := converters.ParseArrayMode(cli.arrays)
+	if arraysErr != nil {
+		processingErr = ErrMsg{Err: arraysErr, Code: ErrNoInput}
+		return
+	}
+	opts := converters.JsonToXlsxOptions{
+		SheetName:      cli.sheet,
+		Separator:      cli.separator,
+		Arrays:         arrays,
+		ArrayDelimiter: cli.arrayDelimiter,
+		StyleHeader:    cli.styleHeader,
+		AutoWidth:      cli.autoWidth,
+		CoerceTypes:    cli.types,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if cli.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cli.timeout)
+		defer cancel()
+	}
+	if convertErr := converters.JsonToXlsx(ctx, outPath, filePath, opts); convertErr != nil {
+		code := ErrParse
+		if errors.Is(convertErr, context.Canceled) || errors.Is(convertErr, context.DeadlineExceeded) {
+			code = ErrCancelled
+		}
+		processingErr = ErrMsg{Err: convertErr, Code: code}
+	}
+}
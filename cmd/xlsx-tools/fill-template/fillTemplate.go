@@ -0,0 +1,96 @@
+// This program fills the {{placeholders}} of a .xlsx template with the values of a JSON file.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"GoTools/pkg/converters"
+	. "GoTools/pkg/helpers"
+)
+
+// cliOptions holds the command line flags.
+type cliOptions struct {
+	data    string
+	out     string
+	types   bool
+	strict  bool
+	timeout time.Duration
+}
+
+var cli cliOptions
+
+func main() {
+	processingErr := ErrMsg{Code: Success}
+	defer func() {
+		processingErr.Exit()
+	}()
+	flag.StringVar(&cli.data, "data", "", "The path of the JSON file holding the values; arrays of objects fill repeated rows")
+	flag.StringVar(&cli.out, "out", "", "The path of the .xlsx file to write")
+	flag.BoolVar(&cli.types, "types", false, "Write text values holding numbers, booleans and dates as such")
+	flag.BoolVar(&cli.strict, "strict", false, "Fail on placeholders without a value instead of emptying them")
+	flag.DurationVar(&cli.timeout, "timeout", 0, "Abort the filling once it has run this long, e.g. 90s or 10m (default no limit)")
+	flag.Parse()
+
+	templatePath := strings.TrimSpace(flag.Arg(0))
+	if len(templatePath) < 1 {
+		processingErr = ErrMsg{Err: errors.New("no template provided"), Code: ErrNoInput}
+		return
+	}
+	dataPath := strings.TrimSpace(cli.data)
+	for _, path := range []string{templatePath, dataPath} {
+		if exists, pathErr := PathExists(path); pathErr != nil || !exists {
+			processingErr = ErrMsg{Err: pathErr, Code: ErrNoFile}
+			return
+		}
+	}
+	if !CheckExtension(templatePath, ".xlsx") {
+		processingErr = ErrMsg{Err: errors.New("the template must be a .xlsx file"), Code: ErrInvalidFileType}
+		return
+	}
+	outPath := strings.TrimSpace(cli.out)
+	if !CheckExtension(outPath, ".xlsx") {
+		processingErr = ErrMsg{Err: errors.New("the -out flag must name a .xlsx file"), Code: ErrInvalidFileType}
+		return
+	}
+	data, dataErr := loadData(dataPath)
+	if dataErr != nil {
+		processingErr = ErrMsg{Err: dataErr, Code: ErrParse}
+		return
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if cli.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cli.timeout)
+		defer cancel()
+	}
+	opts := converters.TemplateOptions{CoerceTypes: cli.types, Strict: cli.strict}
+	if fillErr := converters.FillTemplate(ctx, outPath, templatePath, data, opts); fillErr != nil {
+		code := ErrParse
+		if errors.Is(fillErr, context.Canceled) || errors.Is(fillErr, context.DeadlineExceeded) {
+			code = ErrCancelled
+		}
+		processingErr = ErrMsg{Err: fillErr, Code: code}
+	}
+}
+
+// loadData reads the template data from the JSON file at path.
+func loadData(path string) (data converters.TemplateData, loadErr error) {
+	file, openErr := os.Open(path)
+	if openErr != nil {
+		return data, openErr
+	}
+	defer func(file *os.File) {
+		if err := file.Close(); err != nil && loadErr == nil {
+			loadErr = err
+		}
+	}(file)
+	return converters.ParseTemplateData(file)
+}
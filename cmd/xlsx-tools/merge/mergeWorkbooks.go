@@ -0,0 +1,154 @@
+// This program merges the worksheets of several workbooks with matching headers into a single worksheet.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"GoTools/pkg/converters"
+	. "GoTools/pkg/helpers"
+)
+
+// cliOptions holds the command line flags.
+type cliOptions struct {
+	out          string
+	sheets       string
+	dataRange    string
+	region       bool
+	nullTokens   string
+	headers      string
+	aliases      string
+	sourceColumn string
+	sheetColumn  string
+	report       string
+	styleHeader  bool
+	autoWidth    bool
+	types        bool
+	header       int
+	password     string
+	timeout      time.Duration
+}
+
+var cli cliOptions
+
+func main() {
+	processingErr := ErrMsg{Code: Success}
+	defer func() {
+		processingErr.Exit()
+	}()
+	flag.StringVar(&cli.out, "out", "", "The path of the merged .xlsx file to write")
+	flag.StringVar(&cli.sheets, "sheets", "", "Worksheets to merge from every workbook: all, index:<n>, regex:<expr> or name:<name>")
+	flag.StringVar(&cli.dataRange, "range", "", "Named range or Excel table to read instead of the whole worksheet, found on whichever worksheet holds it")
+	flag.BoolVar(&cli.region, "detect-region", false, "Detect where the data starts and ends, skipping blank leading rows and columns, blank rows and footer notes")
+	flag.StringVar(&cli.nullTokens, "null-tokens", "", "Comma separated values, such as N/A or #N/A, read as empty cells; default for the common ones")
+	flag.StringVar(&cli.headers, "headers", "union", "How the columns are reconciled: union, intersect or strict")
+	flag.StringVar(&cli.aliases, "aliases", "", "Comma separated alternative column names, e.g. Qty=Quantity,Cust=Customer")
+	flag.StringVar(&cli.sourceColumn, "source-column", "", "Name of a column added to hold the file every record comes from")
+	flag.StringVar(&cli.sheetColumn, "sheet-column", "", "Name of a column added to hold the worksheet every record comes from")
+	flag.StringVar(&cli.report, "report", "", "Path of a JSON file to write the columns every worksheet lacks or holds in excess to")
+	flag.BoolVar(&cli.styleHeader, "style-header", false, "Write the header row in bold and freeze it")
+	flag.BoolVar(&cli.autoWidth, "autowidth", false, "Size every column to its longest value")
+	flag.BoolVar(&cli.types, "types", false, "Write numbers, booleans and dates as such instead of as text")
+	flag.IntVar(&cli.header, "header", 0, "Zero-based index of the row holding the column names")
+	flag.StringVar(&cli.password, "password-env", "XLSX_PASSWORD", "Environment variable holding the password of encrypted workbooks")
+	flag.DurationVar(&cli.timeout, "timeout", 0, "Abort the merge once it has run this long, e.g. 90s or 10m (default no limit)")
+	flag.Parse()
+
+	paths := flag.Args()
+	if len(paths) < 1 {
+		processingErr = ErrMsg{Err: errors.New("no workbooks provided"), Code: ErrNoInput}
+		return
+	}
+	for _, path := range paths {
+		if exists, pathErr := PathExists(path); pathErr != nil || !exists {
+			processingErr = ErrMsg{Err: pathErr, Code: ErrNoFile}
+			return
+		}
+		if !CheckExtension(path, ".xlsx") && !CheckExtension(path, ".xls") && !CheckExtension(path, ".ods") {
+			processingErr = ErrMsg{Err: fmt.Errorf("invalid file type: %s", path), Code: ErrInvalidFileType}
+			return
+		}
+	}
+	outPath := strings.TrimSpace(cli.out)
+	if !CheckExtension(outPath, ".xlsx") {
+		processingErr = ErrMsg{Err: errors.New("the -out flag must name a .xlsx file"), Code: ErrInvalidFileType}
+		return
+	}
+	opts, optsErr := buildOptions()
+	if optsErr != nil {
+		processingErr = ErrMsg{Err: optsErr, Code: ErrNoInput}
+		return
+	}
+	style := converters.StyleOptions{StyleHeader: cli.styleHeader, FreezeHeader: cli.styleHeader, AutoWidth: cli.autoWidth, CoerceTypes: cli.types}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if cli.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cli.timeout)
+		defer cancel()
+	}
+	report, mergeErr := converters.MergeWorkbooks(ctx, outPath, paths, opts, style)
+	if mergeErr != nil {
+		code := ErrParse
+		if errors.Is(mergeErr, context.Canceled) || errors.Is(mergeErr, context.DeadlineExceeded) {
+			code = ErrCancelled
+		}
+		processingErr = ErrMsg{Err: mergeErr, Code: code}
+		return
+	}
+	if len(cli.report) > 0 {
+		if err := writeReport(cli.report, report); err != nil {
+			processingErr = ErrMsg{Err: err, Code: ErrWriteFile}
+		}
+	}
+}
+
+// buildOptions turns the command line flags into merge options.
+func buildOptions() (opts converters.MergeOptions, err error) {
+	opts.SourceColumn = cli.sourceColumn
+	opts.SheetColumn = cli.sheetColumn
+	opts.HeaderRowIndex = cli.header
+	opts.Range = cli.dataRange
+	opts.Region.Detect = cli.region
+	opts.NullTokens = ParseNullTokens(cli.nullTokens)
+	opts.Password = converters.PasswordFromEnv(cli.password)
+	if len(cli.aliases) > 0 {
+		opts.HeaderAliases = make(map[string]string)
+		for _, pair := range strings.Split(cli.aliases, ",") {
+			alias, column, found := strings.Cut(pair, "=")
+			if !found || len(strings.TrimSpace(alias)) < 1 || len(strings.TrimSpace(column)) < 1 {
+				return opts, fmt.Errorf("-aliases expects alias=column pairs, got '%s'", pair)
+			}
+			opts.HeaderAliases[strings.TrimSpace(alias)] = strings.TrimSpace(column)
+		}
+	}
+	if opts.Headers, err = converters.ParseHeaderMode(cli.headers); err != nil {
+		return opts, err
+	}
+	opts.Sheets, err = converters.ParseSheetSelector(cli.sheets)
+	return opts, err
+}
+
+// writeReport writes the merge report as JSON to the file at path.
+func writeReport(path string, report converters.MergeReport) (writeErr error) {
+	file, createErr := os.Create(path)
+	if createErr != nil {
+		return createErr
+	}
+	defer func(file *os.File) {
+		if err := file.Close(); err != nil && writeErr == nil {
+			writeErr = err
+		}
+	}(file)
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
@@ -0,0 +1,199 @@
+// This program converts the worksheets of a .xlsx file into CSV files.
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"GoTools/pkg/converters"
+	"GoTools/pkg/fixedwidth"
+	. "GoTools/pkg/helpers"
+	"GoTools/pkg/transforms"
+)
+
+// cliOptions holds the command line flags.
+type cliOptions struct {
+	path           string
+	sheet          string
+	sheets         string
+	dataRange      string
+	region         bool
+	nullTokens     string
+	headerMap      string
+	transforms     string
+	outDir         string
+	delimiter      string
+	quote          string
+	crlf           bool
+	noHeader       bool
+	encoding       string
+	bom            bool
+	transliterate  bool
+	merged         bool
+	skipHiddenRows bool
+	skipHiddenCols bool
+	formulas       string
+	header         int
+	skip           int
+	footer         int
+	password       string
+	fixedWidth     string
+	fixedEncoding  string
+	googleSheet    string
+	googleRanges   string
+	googleKey      string
+	timeout        time.Duration
+}
+
+var cli cliOptions
+
+func main() {
+	processingErr := ErrMsg{Code: Success}
+	defer func() {
+		processingErr.Exit()
+	}()
+	flag.StringVar(&cli.path, "path", "", "The path to the .xlsx file to convert")
+	flag.StringVar(&cli.sheet, "sheet", "", "The name of the worksheet to convert")
+	flag.StringVar(&cli.sheets, "sheets", "", "Worksheets to convert: all, index:<n>, regex:<expr> or name:<name>")
+	flag.StringVar(&cli.dataRange, "range", "", "Named range or Excel table to read instead of the whole worksheet, found on whichever worksheet holds it")
+	flag.BoolVar(&cli.region, "detect-region", false, "Detect where the data starts and ends, skipping blank leading rows and columns, blank rows and footer notes")
+	flag.StringVar(&cli.nullTokens, "null-tokens", "", "Comma separated values, such as N/A or #N/A, read as empty cells; default for the common ones")
+	flag.StringVar(&cli.headerMap, "header-map", "", "Path to a JSON file renaming the headers to canonical columns and their aliases; missing columns fail the conversion")
+	flag.StringVar(&cli.transforms, "transforms", "", "Path to a YAML or JSON file configuring the transforms cleaning the values of columns, such as trim, date and map")
+	flag.StringVar(&cli.outDir, "out", "", "Directory to write one .csv file per worksheet into, instead of stdout")
+	flag.StringVar(&cli.delimiter, "delimiter", ",", `Field delimiter, "\t" for a tab`)
+	flag.StringVar(&cli.quote, "quote", `"`, "Character enclosing fields that need quoting")
+	flag.BoolVar(&cli.crlf, "crlf", false, "End records with CRLF instead of LF")
+	flag.BoolVar(&cli.noHeader, "no-header", false, "Leave out the header row")
+	flag.StringVar(&cli.encoding, "encoding", "UTF-8", "Output encoding: UTF-8, UTF-16, UTF-16LE, UTF-16BE, ISO-8859-1 or Windows-1252")
+	flag.BoolVar(&cli.bom, "bom", false, "Start UTF-8 output with a byte order mark")
+	flag.BoolVar(&cli.transliterate, "transliterate", false, "Spell accented letters of the header row in ASCII, e.g. Größe as Grosse")
+	flag.BoolVar(&cli.merged, "merged", false, "Copy the value of merged cells into every cell they cover")
+	flag.BoolVar(&cli.skipHiddenRows, "skip-hidden-rows", false, "Leave out the rows hidden in the workbook; the header row is always read")
+	flag.BoolVar(&cli.skipHiddenCols, "skip-hidden-cols", false, "Leave out the columns hidden in the workbook, which loads the whole worksheet into memory")
+	flag.StringVar(&cli.formulas, "formulas", "cached", "Value of formula cells: cached, text or evaluate")
+	flag.IntVar(&cli.header, "header", 0, "Zero-based index of the row holding the column names")
+	flag.IntVar(&cli.skip, "skip", 0, "Number of rows to skip directly below the header row")
+	flag.IntVar(&cli.footer, "skip-footer", 0, "Number of rows to skip at the end of the worksheet")
+	flag.StringVar(&cli.password, "password-env", "XLSX_PASSWORD", "Environment variable holding the password of an encrypted workbook")
+	flag.StringVar(&cli.fixedWidth, "fixed-width", "", "Layout file of the fields of a fixed-width text file to read instead of a workbook")
+	flag.StringVar(&cli.fixedEncoding, "fixed-width-encoding", "UTF-8", "Encoding of the fixed-width text file: UTF-8, UTF-16, UTF-16LE, UTF-16BE, ISO-8859-1 or Windows-1252")
+	flag.StringVar(&cli.googleSheet, "google-sheet", "", "ID of a Google Sheets spreadsheet to read instead of a file, shared with the service account")
+	flag.StringVar(&cli.googleRanges, "google-ranges", "", "Comma separated ranges of the spreadsheet to read, e.g. Orders!A1:F (default every sheet)")
+	flag.StringVar(&cli.googleKey, "google-key", os.Getenv("GOOGLE_APPLICATION_CREDENTIALS"), "Path to the key file of the service account reading the spreadsheet")
+	flag.DurationVar(&cli.timeout, "timeout", 0, "Abort the conversion once it has run this long, e.g. 90s or 10m (default no limit)")
+	flag.Parse()
+
+	filePath := strings.TrimSpace(cli.path)
+	if len(cli.googleSheet) > 0 {
+		filePath = cli.googleSheet
+	}
+	if len(filePath) < 1 {
+		processingErr = ErrMsg{Err: errors.New("no .xlsx path provided with the -path flag"), Code: ErrNoInput}
+		return
+	}
+	if exists, pathErr := PathExists(filePath); len(cli.googleSheet) < 1 && (pathErr != nil || !exists) {
+		processingErr = ErrMsg{Err: pathErr, Code: ErrNoFile}
+		return
+	}
+	if len(cli.fixedWidth) < 1 && len(cli.googleSheet) < 1 && !CheckExtension(filePath, ".xlsx") && !CheckExtension(filePath, ".xls") && !CheckExtension(filePath, ".ods") {
+		processingErr = ErrMsg{Err: errors.New("invalid file type"), Code: ErrInvalidFileType}
+		return
+	}
+	opts, optsErr := buildOptions()
+	if optsErr != nil {
+		processingErr = ErrMsg{Err: optsErr, Code: ErrNoInput}
+		return
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if cli.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cli.timeout)
+		defer cancel()
+	}
+	if len(cli.outDir) > 0 {
+		if _, parseErr := converters.XlsxToCsvFiles(ctx, cli.outDir, filePath, opts); parseErr != nil {
+			processingErr = ErrMsg{Err: parseErr, Code: ConversionCode(parseErr)}
+		}
+		return
+	}
+	writer := bufio.NewWriter(os.Stdout)
+	if parseErr := converters.XlsxToCsv(ctx, writer, filePath, opts); parseErr != nil {
+		processingErr = ErrMsg{Err: parseErr, Code: ConversionCode(parseErr)}
+	} else if writeErr := writer.Flush(); writeErr != nil {
+		processingErr = ErrMsg{Err: writeErr, Code: ErrStdout}
+	}
+}
+
+// buildOptions turns the command line flags into converter options.
+// A worksheet name given with -sheet takes precedence over a -sheets selector.
+func buildOptions() (opts converters.CsvOptions, err error) {
+	if opts.Delimiter, err = SingleRune("-delimiter", strings.ReplaceAll(cli.delimiter, `\t`, "\t")); err != nil {
+		return opts, err
+	}
+	if opts.Quote, err = SingleRune("-quote", cli.quote); err != nil {
+		return opts, err
+	}
+	if cli.crlf {
+		opts.Newline = "\r\n"
+	}
+	opts.OmitHeader = cli.noHeader
+	opts.Encoding = cli.encoding
+	opts.ByteOrderMark = cli.bom
+	opts.TransliterateHeaders = cli.transliterate
+	opts.ResolveMergedCells = cli.merged
+	opts.SkipHiddenRows = cli.skipHiddenRows
+	opts.SkipHiddenColumns = cli.skipHiddenCols
+	opts.Password = converters.PasswordFromEnv(cli.password)
+	opts.HeaderRowIndex = cli.header
+	opts.Range = cli.dataRange
+	opts.Region.Detect = cli.region
+	opts.NullTokens = ParseNullTokens(cli.nullTokens)
+	if len(cli.headerMap) > 0 {
+		mapping, loadErr := converters.LoadHeaderMapping(cli.headerMap)
+		if loadErr != nil {
+			return opts, loadErr
+		}
+		mapping.Report = converters.ReportHeaderMapping
+		opts.HeaderMapping = &mapping
+	}
+	if len(cli.transforms) > 0 {
+		if opts.Transforms, err = transforms.LoadConfig(cli.transforms); err != nil {
+			return opts, err
+		}
+	}
+	opts.SkipRows = cli.skip
+	opts.SkipFooterRows = cli.footer
+	if len(cli.fixedWidth) > 0 {
+		if opts.FixedWidth, err = fixedwidth.LoadLayout(cli.fixedWidth); err != nil {
+			return opts, err
+		}
+		opts.FixedWidthEncoding = cli.fixedEncoding
+	}
+	if len(cli.googleSheet) > 0 {
+		var ranges []string
+		if len(cli.googleRanges) > 0 {
+			ranges = strings.Split(cli.googleRanges, ",")
+		}
+		if opts.GoogleSheet, err = converters.NewGoogleSheet(cli.googleKey, cli.googleSheet, ranges); err != nil {
+			return opts, err
+		}
+	}
+	if opts.Formulas, err = converters.ParseFormulaMode(cli.formulas); err != nil {
+		return opts, err
+	}
+	if len(cli.sheet) > 0 {
+		opts.Sheets = converters.SheetByName(cli.sheet)
+		return opts, nil
+	}
+	opts.Sheets, err = converters.ParseSheetSelector(cli.sheets)
+	return opts, err
+}
@@ -0,0 +1,146 @@
+// This program renders the worksheets of a .xlsx file as HTML tables.
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"GoTools/pkg/converters"
+	"GoTools/pkg/fixedwidth"
+	. "GoTools/pkg/helpers"
+)
+
+// cliOptions holds the command line flags.
+type cliOptions struct {
+	path           string
+	sheet          string
+	sheets         string
+	dataRange      string
+	region         bool
+	nullTokens     string
+	captions       bool
+	class          string
+	css            bool
+	document       bool
+	align          bool
+	maxRows        int
+	merged         bool
+	skipHiddenRows bool
+	skipHiddenCols bool
+	formulas       string
+	header         int
+	skip           int
+	footer         int
+	password       string
+	fixedWidth     string
+	fixedEncoding  string
+	timeout        time.Duration
+}
+
+var cli cliOptions
+
+func main() {
+	processingErr := ErrMsg{Code: Success}
+	defer func() {
+		processingErr.Exit()
+	}()
+	flag.StringVar(&cli.path, "path", "", "The path to the .xlsx file to convert")
+	flag.StringVar(&cli.sheet, "sheet", "", "The name of the worksheet to convert")
+	flag.StringVar(&cli.sheets, "sheets", "", "Worksheets to convert: all, index:<n>, regex:<expr> or name:<name>")
+	flag.StringVar(&cli.dataRange, "range", "", "Named range or Excel table to read instead of the whole worksheet, found on whichever worksheet holds it")
+	flag.BoolVar(&cli.region, "detect-region", false, "Detect where the data starts and ends, skipping blank leading rows and columns, blank rows and footer notes")
+	flag.StringVar(&cli.nullTokens, "null-tokens", "", "Comma separated values, such as N/A or #N/A, read as empty cells; default for the common ones")
+	flag.BoolVar(&cli.captions, "captions", false, "Write the worksheet name as the caption of every table")
+	flag.StringVar(&cli.class, "class", "sheet", "Class of the rendered tables")
+	flag.BoolVar(&cli.css, "css", false, "Embed a <style> element styling the tables")
+	flag.BoolVar(&cli.document, "document", false, "Write a complete HTML document instead of a fragment")
+	flag.BoolVar(&cli.align, "align-numbers", false, "Right-align the columns holding only numbers")
+	flag.IntVar(&cli.maxRows, "max-rows", 0, "Maximum number of records rendered per worksheet (default no limit)")
+	flag.BoolVar(&cli.merged, "merged", false, "Copy the value of merged cells into every cell they cover")
+	flag.BoolVar(&cli.skipHiddenRows, "skip-hidden-rows", false, "Leave out the rows hidden in the workbook; the header row is always read")
+	flag.BoolVar(&cli.skipHiddenCols, "skip-hidden-cols", false, "Leave out the columns hidden in the workbook, which loads the whole worksheet into memory")
+	flag.StringVar(&cli.formulas, "formulas", "cached", "Value of formula cells: cached, text or evaluate")
+	flag.IntVar(&cli.header, "header", 0, "Zero-based index of the row holding the column names")
+	flag.IntVar(&cli.skip, "skip", 0, "Number of rows to skip directly below the header row")
+	flag.IntVar(&cli.footer, "skip-footer", 0, "Number of rows to skip at the end of the worksheet")
+	flag.StringVar(&cli.password, "password-env", "XLSX_PASSWORD", "Environment variable holding the password of an encrypted workbook")
+	flag.StringVar(&cli.fixedWidth, "fixed-width", "", "Layout file of the fields of a fixed-width text file to read instead of a workbook")
+	flag.StringVar(&cli.fixedEncoding, "fixed-width-encoding", "UTF-8", "Encoding of the fixed-width text file: UTF-8, UTF-16, UTF-16LE, UTF-16BE, ISO-8859-1 or Windows-1252")
+	flag.DurationVar(&cli.timeout, "timeout", 0, "Abort the conversion once it has run this long, e.g. 90s or 10m (default no limit)")
+	flag.Parse()
+
+	filePath := strings.TrimSpace(cli.path)
+	if len(filePath) < 1 {
+		processingErr = ErrMsg{Err: errors.New("no .xlsx path provided with the -path flag"), Code: ErrNoInput}
+		return
+	}
+	if exists, pathErr := PathExists(filePath); pathErr != nil || !exists {
+		processingErr = ErrMsg{Err: pathErr, Code: ErrNoFile}
+		return
+	}
+	if len(cli.fixedWidth) < 1 && !CheckExtension(filePath, ".xlsx") && !CheckExtension(filePath, ".xls") && !CheckExtension(filePath, ".ods") {
+		processingErr = ErrMsg{Err: errors.New("invalid file type"), Code: ErrInvalidFileType}
+		return
+	}
+	opts, optsErr := buildOptions()
+	if optsErr != nil {
+		processingErr = ErrMsg{Err: optsErr, Code: ErrNoInput}
+		return
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if cli.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cli.timeout)
+		defer cancel()
+	}
+	writer := bufio.NewWriter(os.Stdout)
+	if parseErr := converters.XlsxToHtml(ctx, writer, filePath, opts); parseErr != nil {
+		processingErr = ErrMsg{Err: parseErr, Code: ConversionCode(parseErr)}
+	} else if writeErr := writer.Flush(); writeErr != nil {
+		processingErr = ErrMsg{Err: writeErr, Code: ErrStdout}
+	}
+}
+
+// buildOptions turns the command line flags into converter options.
+// A worksheet name given with -sheet takes precedence over a -sheets selector.
+func buildOptions() (opts converters.HtmlOptions, err error) {
+	opts.Captions = cli.captions
+	opts.Class = cli.class
+	opts.EmbedCss = cli.css
+	opts.Document = cli.document
+	opts.AlignNumbers = cli.align
+	opts.MaxRows = cli.maxRows
+	opts.ResolveMergedCells = cli.merged
+	opts.SkipHiddenRows = cli.skipHiddenRows
+	opts.SkipHiddenColumns = cli.skipHiddenCols
+	opts.Password = converters.PasswordFromEnv(cli.password)
+	opts.HeaderRowIndex = cli.header
+	opts.Range = cli.dataRange
+	opts.Region.Detect = cli.region
+	opts.NullTokens = ParseNullTokens(cli.nullTokens)
+	opts.SkipRows = cli.skip
+	opts.SkipFooterRows = cli.footer
+	if len(cli.fixedWidth) > 0 {
+		if opts.FixedWidth, err = fixedwidth.LoadLayout(cli.fixedWidth); err != nil {
+			return opts, err
+		}
+		opts.FixedWidthEncoding = cli.fixedEncoding
+	}
+	if opts.Formulas, err = converters.ParseFormulaMode(cli.formulas); err != nil {
+		return opts, err
+	}
+	if len(cli.sheet) > 0 {
+		opts.Sheets = converters.SheetByName(cli.sheet)
+		return opts, nil
+	}
+	opts.Sheets, err = converters.ParseSheetSelector(cli.sheets)
+	return opts, err
+}
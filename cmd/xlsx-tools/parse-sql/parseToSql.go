@@ -0,0 +1,200 @@
+// This program converts a worksheet of a .xlsx file into an SQL script of INSERT or upsert statements.
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"GoTools/pkg/converters"
+	"GoTools/pkg/fixedwidth"
+	. "GoTools/pkg/helpers"
+)
+
+// cliOptions holds the command line flags.
+type cliOptions struct {
+	path           string
+	sheet          string
+	sheets         string
+	dataRange      string
+	region         bool
+	nullTokens     string
+	table          string
+	dialect        string
+	statement      string
+	keys           string
+	columns        string
+	columnTypes    string
+	types          bool
+	sample         int
+	confidence     float64
+	batch          int
+	createTable    bool
+	merged         bool
+	skipHiddenRows bool
+	skipHiddenCols bool
+	formulas       string
+	header         int
+	skip           int
+	footer         int
+	password       string
+	fixedWidth     string
+	fixedEncoding  string
+	timeout        time.Duration
+}
+
+var cli cliOptions
+
+func main() {
+	processingErr := ErrMsg{Code: Success}
+	defer func() {
+		processingErr.Exit()
+	}()
+	flag.StringVar(&cli.path, "path", "", "The path to the .xlsx file to convert")
+	flag.StringVar(&cli.sheet, "sheet", "", "The name of the worksheet to convert")
+	flag.StringVar(&cli.sheets, "sheets", "", "Worksheet to convert: index:<n>, regex:<expr> or name:<name>")
+	flag.StringVar(&cli.dataRange, "range", "", "Named range or Excel table to read instead of the whole worksheet, found on whichever worksheet holds it")
+	flag.BoolVar(&cli.region, "detect-region", false, "Detect where the data starts and ends, skipping blank leading rows and columns, blank rows and footer notes")
+	flag.StringVar(&cli.nullTokens, "null-tokens", "", "Comma separated values, such as N/A or #N/A, read as empty cells; default for the common ones")
+	flag.StringVar(&cli.table, "table", "", "The name of the table, optionally qualified by its schema (default the worksheet name)")
+	flag.StringVar(&cli.dialect, "dialect", "postgres", "SQL dialect: postgres, mysql or sqlserver")
+	flag.StringVar(&cli.statement, "statement", "insert", "Statements to generate: insert or upsert")
+	flag.StringVar(&cli.keys, "keys", "", "Comma separated header names of the key columns, required for upserts")
+	flag.StringVar(&cli.columns, "columns", "", "Comma separated <header>=<column> mappings; only mapped columns are written")
+	flag.StringVar(&cli.columnTypes, "column-types", "", "Comma separated <header>=<type> pairs: string, integer, decimal, double, boolean, date or datetime")
+	flag.BoolVar(&cli.types, "types", false, "Infer the type of the other columns from their values")
+	flag.IntVar(&cli.sample, "sample", 0, "Number of rows the types are inferred from (default every row)")
+	flag.Float64Var(&cli.confidence, "min-confidence", 1, "Share of the sampled values of a column which must be valid for its inferred type")
+	flag.IntVar(&cli.batch, "batch", 1, "Number of records inserted by each statement")
+	flag.BoolVar(&cli.createTable, "create-table", false, "Start the script with a CREATE TABLE statement")
+	flag.BoolVar(&cli.merged, "merged", false, "Copy the value of merged cells into every cell they cover")
+	flag.BoolVar(&cli.skipHiddenRows, "skip-hidden-rows", false, "Leave out the rows hidden in the workbook; the header row is always read")
+	flag.BoolVar(&cli.skipHiddenCols, "skip-hidden-cols", false, "Leave out the columns hidden in the workbook, which loads the whole worksheet into memory")
+	flag.StringVar(&cli.formulas, "formulas", "cached", "Value of formula cells: cached, text or evaluate")
+	flag.IntVar(&cli.header, "header", 0, "Zero-based index of the row holding the column names")
+	flag.IntVar(&cli.skip, "skip", 0, "Number of rows to skip directly below the header row")
+	flag.IntVar(&cli.footer, "skip-footer", 0, "Number of rows to skip at the end of the worksheet")
+	flag.StringVar(&cli.password, "password-env", "XLSX_PASSWORD", "Environment variable holding the password of an encrypted workbook")
+	flag.StringVar(&cli.fixedWidth, "fixed-width", "", "Layout file of the fields of a fixed-width text file to read instead of a workbook")
+	flag.StringVar(&cli.fixedEncoding, "fixed-width-encoding", "UTF-8", "Encoding of the fixed-width text file: UTF-8, UTF-16, UTF-16LE, UTF-16BE, ISO-8859-1 or Windows-1252")
+	flag.DurationVar(&cli.timeout, "timeout", 0, "Abort the conversion once it has run this long, e.g. 90s or 10m (default no limit)")
+	flag.Parse()
+
+	filePath := strings.TrimSpace(cli.path)
+	if len(filePath) < 1 {
+		processingErr = ErrMsg{Err: errors.New("no .xlsx path provided with the -path flag"), Code: ErrNoInput}
+		return
+	}
+	if exists, pathErr := PathExists(filePath); pathErr != nil || !exists {
+		processingErr = ErrMsg{Err: pathErr, Code: ErrNoFile}
+		return
+	}
+	if len(cli.fixedWidth) < 1 && !CheckExtension(filePath, ".xlsx") && !CheckExtension(filePath, ".xls") && !CheckExtension(filePath, ".ods") {
+		processingErr = ErrMsg{Err: errors.New("invalid file type"), Code: ErrInvalidFileType}
+		return
+	}
+	opts, optsErr := buildOptions()
+	if optsErr != nil {
+		processingErr = ErrMsg{Err: optsErr, Code: ErrNoInput}
+		return
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if cli.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cli.timeout)
+		defer cancel()
+	}
+	writer := bufio.NewWriter(os.Stdout)
+	if parseErr := converters.XlsxToSql(ctx, writer, filePath, opts); parseErr != nil {
+		code := ErrParse
+		if errors.Is(parseErr, context.Canceled) || errors.Is(parseErr, context.DeadlineExceeded) {
+			code = ErrCancelled
+		}
+		processingErr = ErrMsg{Err: parseErr, Code: code}
+	} else if writeErr := writer.Flush(); writeErr != nil {
+		processingErr = ErrMsg{Err: writeErr, Code: ErrStdout}
+	}
+}
+
+// buildOptions turns the command line flags into converter options.
+// A worksheet name given with -sheet takes precedence over a -sheets selector.
+func buildOptions() (opts converters.SqlOptions, err error) {
+	opts.Table = cli.table
+	if opts.Dialect, err = converters.ParseSqlDialect(cli.dialect); err != nil {
+		return opts, err
+	}
+	if opts.Statement, err = converters.ParseSqlStatement(cli.statement); err != nil {
+		return opts, err
+	}
+	if len(cli.keys) > 0 {
+		opts.KeyColumns = strings.Split(cli.keys, ",")
+	}
+	if opts.ColumnNames, err = parsePairs("-columns", cli.columns); err != nil {
+		return opts, err
+	}
+	columnTypes, typesErr := parsePairs("-column-types", cli.columnTypes)
+	if typesErr != nil {
+		return opts, typesErr
+	}
+	for header, name := range columnTypes {
+		if opts.ColumnTypes == nil {
+			opts.ColumnTypes = make(map[string]converters.CellType)
+		}
+		if opts.ColumnTypes[header], err = converters.ParseCellType(name); err != nil {
+			return opts, err
+		}
+	}
+	opts.InferTypes = cli.types
+	opts.Inference = converters.InferenceOptions{SampleSize: cli.sample, MinConfidence: cli.confidence}
+	opts.BatchSize = cli.batch
+	opts.CreateTable = cli.createTable
+	opts.ResolveMergedCells = cli.merged
+	opts.SkipHiddenRows = cli.skipHiddenRows
+	opts.SkipHiddenColumns = cli.skipHiddenCols
+	opts.Password = converters.PasswordFromEnv(cli.password)
+	opts.HeaderRowIndex = cli.header
+	opts.Range = cli.dataRange
+	opts.Region.Detect = cli.region
+	opts.NullTokens = ParseNullTokens(cli.nullTokens)
+	opts.SkipRows = cli.skip
+	opts.SkipFooterRows = cli.footer
+	if len(cli.fixedWidth) > 0 {
+		if opts.FixedWidth, err = fixedwidth.LoadLayout(cli.fixedWidth); err != nil {
+			return opts, err
+		}
+		opts.FixedWidthEncoding = cli.fixedEncoding
+	}
+	if opts.Formulas, err = converters.ParseFormulaMode(cli.formulas); err != nil {
+		return opts, err
+	}
+	if len(cli.sheet) > 0 {
+		opts.Sheets = converters.SheetByName(cli.sheet)
+		return opts, nil
+	}
+	opts.Sheets, err = converters.ParseSheetSelector(cli.sheets)
+	return opts, err
+}
+
+// parsePairs parses the comma separated <key>=<value> pairs of the value of the named flag.
+func parsePairs(name, value string) (map[string]string, error) {
+	if len(value) < 1 {
+		return nil, nil
+	}
+	pairs := make(map[string]string)
+	for _, pair := range strings.Split(value, ",") {
+		key, mapped, found := strings.Cut(pair, "=")
+		if !found || len(key) < 1 || len(mapped) < 1 {
+			return nil, fmt.Errorf("%s expects <header>=<value> pairs, got '%s'", name, pair)
+		}
+		pairs[key] = mapped
+	}
+	return pairs, nil
+}
@@ -4,28 +4,82 @@ package main
 
 import (
 	"bufio"
-	"encoding/xml"
+	"bytes"
+	"context"
 	"errors"
 	"flag"
+	"fmt"
 	"os"
+	"os/signal"
+	"regexp"
 	"strings"
+	"time"
 
+	"GoTools/pkg/converters"
+	"GoTools/pkg/fixedwidth"
 	. "GoTools/pkg/helpers"
-	"github.com/xuri/excelize/v2"
+	"GoTools/pkg/validators"
+	"github.com/charmbracelet/log"
 )
 
-type DataColumn struct {
-	XMLName xml.Name
-	Value   string `xml:",chardata"`
+// cliOptions holds the command line flags beyond the file path and sheet name returned by getInput.
+type cliOptions struct {
+	sheets         string
+	dataRange      string
+	region         bool
+	nullTokens     string
+	combine        bool
+	outDir         string
+	stream         bool
+	root           string
+	row            string
+	attrs          bool
+	types          bool
+	merged         bool
+	skipHiddenRows bool
+	skipHiddenCols bool
+	comments       string
+	links          string
+	richText       string
+	empty          string
+	order          string
+	indent         string
+	crlf           bool
+	compact        bool
+	encoding       string
+	bom            bool
+	transliterate  bool
+	decl           bool
+	formulas       string
+	header         int
+	skip           int
+	footer         int
+	include        string
+	exclude        string
+	pattern        string
+	where          string
+	check          string
+	xsd            bool
+	validate       string
+	cdata          string
+	autoCData      bool
+	namespace      string
+	prefixes       map[string]string
+	qualified      map[string]string
+	chunk          int
+	compress       string
+	progress       int
+	timeout        time.Duration
+	report         string
+	password       string
+	fixedWidth     string
+	fixedEncoding  string
+	googleSheet    string
+	googleRanges   string
+	googleKey      string
 }
 
-type DataRow struct {
-	Columns []DataColumn `xml:",any"`
-}
-
-type DataTable struct {
-	Rows []DataRow `xml:"Row"`
-}
+var cli cliOptions
 
 // getInput retrieves user input for the file path and sheet name.
 // It uses command line flags to get the user input, and falls back to standard input if no arguments are provided.
@@ -34,6 +88,59 @@ type DataTable struct {
 func getInput() (filePath, sheetName string, inputErr error) {
 	flag.StringVar(&filePath, "path", "", "The path to the .xlsx file to parse")
 	flag.StringVar(&sheetName, "sheet", "", "The name of the worksheet to parse")
+	flag.StringVar(&cli.sheets, "sheets", "", "Worksheets to parse: all, index:<n>, regex:<expr> or name:<name>")
+	flag.StringVar(&cli.dataRange, "range", "", "Named range or Excel table to read instead of the whole worksheet, found on whichever worksheet holds it")
+	flag.BoolVar(&cli.region, "detect-region", false, "Detect where the data starts and ends, skipping blank leading rows and columns, blank rows and footer notes")
+	flag.StringVar(&cli.nullTokens, "null-tokens", "", "Comma separated values, such as N/A or #N/A, read as empty cells; default for the common ones")
+	flag.BoolVar(&cli.combine, "combine", false, "Combine the worksheets into a single document with a <Sheet> element per worksheet")
+	flag.StringVar(&cli.outDir, "out", "", "Directory to write one .xml file per worksheet into, instead of stdout")
+	flag.StringVar(&cli.root, "root", "", "Name of the element holding the rows of a worksheet (default DataTable)")
+	flag.StringVar(&cli.row, "row", "", "Name of the element of each row (default Row)")
+	flag.BoolVar(&cli.attrs, "attributes", false, "Emit each column as an attribute of the row element instead of a child element")
+	flag.BoolVar(&cli.types, "types", false, "Annotate cells with the xsi:type inferred from their value")
+	flag.StringVar(&cli.empty, "empty", "empty", "Empty cells: empty (empty element), omit or nil (xsi:nil=\"true\")")
+	flag.StringVar(&cli.indent, "indent", "", `Indentation of every nesting level, spaces or "\t" for a tab (default two spaces)`)
+	flag.BoolVar(&cli.crlf, "crlf", false, "End lines with CRLF instead of LF")
+	flag.BoolVar(&cli.compact, "compact", false, "Write each document on a single line without indentation")
+	flag.StringVar(&cli.encoding, "encoding", "UTF-8", "Output encoding: UTF-8, UTF-16, UTF-16LE, UTF-16BE or ISO-8859-1")
+	flag.BoolVar(&cli.bom, "bom", false, "Start UTF-8 output with a byte order mark")
+	flag.BoolVar(&cli.transliterate, "transliterate", false, "Spell accented letters of the headers in ASCII, e.g. Größe as Grosse, in element names")
+	flag.BoolVar(&cli.decl, "declaration", false, "Write the XML declaration, always written for encodings other than UTF-8")
+	flag.StringVar(&cli.cdata, "cdata", "", "Comma separated list of columns whose values are written as CDATA sections")
+	flag.StringVar(&cli.comments, "comments", "none", "Cell comments: none, elements (<Name_comment>) or attributes (comment=\"...\")")
+	flag.StringVar(&cli.links, "links", "", "Comma separated list of columns whose hyperlink targets are written into a <Name_link> column")
+	flag.BoolVar(&cli.autoCData, "cdata-auto", false, "Write values holding '<', '>' or '&' as CDATA sections")
+	flag.StringVar(&cli.namespace, "namespace", "", "Default namespace of the output document")
+	flag.Func("ns", "Declare a prefixed namespace as prefix=uri, may be repeated", keyValueFlag(&cli.prefixes))
+	flag.Func("column-ns", "Place a column in a declared namespace as column=prefix, may be repeated", keyValueFlag(&cli.qualified))
+	flag.StringVar(&cli.password, "password-env", "XLSX_PASSWORD", "Environment variable holding the password of an encrypted workbook")
+	flag.StringVar(&cli.fixedWidth, "fixed-width", "", "Layout file of the fields of a fixed-width text file to read instead of a workbook")
+	flag.StringVar(&cli.fixedEncoding, "fixed-width-encoding", "UTF-8", "Encoding of the fixed-width text file: UTF-8, UTF-16, UTF-16LE, UTF-16BE, ISO-8859-1 or Windows-1252")
+	flag.StringVar(&cli.googleSheet, "google-sheet", "", "ID of a Google Sheets spreadsheet to read instead of a file, shared with the service account")
+	flag.StringVar(&cli.googleRanges, "google-ranges", "", "Comma separated ranges of the spreadsheet to read, e.g. Orders!A1:F (default every sheet)")
+	flag.StringVar(&cli.googleKey, "google-key", os.Getenv("GOOGLE_APPLICATION_CREDENTIALS"), "Path to the key file of the service account reading the spreadsheet")
+	flag.BoolVar(&cli.merged, "merged", false, "Copy the value of merged cells into every cell they cover")
+	flag.BoolVar(&cli.skipHiddenRows, "skip-hidden-rows", false, "Leave out the rows hidden in the workbook; the header row is always read")
+	flag.BoolVar(&cli.skipHiddenCols, "skip-hidden-cols", false, "Leave out the columns hidden in the workbook, which loads the whole worksheet into memory")
+	flag.StringVar(&cli.richText, "rich-text", "plain", "Flattening of rich text cells: plain, markdown or xml (<Run> elements)")
+	flag.StringVar(&cli.formulas, "formulas", "cached", "Value of formula cells: cached, text or evaluate")
+	flag.IntVar(&cli.header, "header", 0, "Zero-based index of the row holding the column names")
+	flag.IntVar(&cli.skip, "skip", 0, "Number of rows to skip directly below the header row")
+	flag.IntVar(&cli.footer, "skip-footer", 0, "Number of rows to skip at the end of the worksheet")
+	flag.StringVar(&cli.include, "include", "", "Comma separated list of the only column headers to convert")
+	flag.StringVar(&cli.exclude, "exclude", "", "Comma separated list of column headers to leave out")
+	flag.StringVar(&cli.pattern, "columns", "", "Regular expression column headers must match to be converted")
+	flag.StringVar(&cli.order, "order", "sheet", "Column order: sheet, alphabetical or names:<column>,<column>,...")
+	flag.StringVar(&cli.where, "where", "", `Only convert rows matching the expression, e.g. 'Status != "Cancelled"'`)
+	flag.StringVar(&cli.check, "check", "", "Drop rows holding invalid identifiers, as comma separated <column>=<validator> pairs, e.g. IBAN=iban. Validators: "+strings.Join(validators.Names(), ", "))
+	flag.IntVar(&cli.chunk, "chunk", 0, "Split each document into numbered files of at most this many rows, requires -out")
+	flag.StringVar(&cli.compress, "compress", "none", "Output compression: none, gzip (.gz files) or zip (a single archive, requires -out)")
+	flag.BoolVar(&cli.xsd, "xsd", false, "Write an XML Schema next to each document, requires -out")
+	flag.StringVar(&cli.report, "report", "none", "Report problems found in cells into <workbook>_errors.json or .csv: none, json or csv, requires -out")
+	flag.StringVar(&cli.validate, "validate", "", "Path to an XML Schema the output must conform to")
+	flag.IntVar(&cli.progress, "progress", 0, "Log the percentage of rows converted and the time remaining every this many rows to stderr")
+	flag.DurationVar(&cli.timeout, "timeout", 0, "Abort the conversion once it has run this long, e.g. 90s or 10m (default no limit)")
+	flag.BoolVar(&cli.stream, "stream", false, "Write rows as they are read to keep memory bounded on very large worksheets")
 	flag.Parse()
 
 	if len(filePath) > 0 {
@@ -62,6 +169,9 @@ func main() {
 		processingErr.Exit()
 	}()
 	filePath, sheetName, inputErr := getInput()
+	if len(cli.googleSheet) > 0 {
+		filePath = cli.googleSheet
+	}
 	// Get user input
 	if inputErr != nil {
 		processingErr = ErrMsg{Err: inputErr, Code: ErrStdin}
@@ -72,126 +182,263 @@ func main() {
 	}
 	// Validate file path
 	exists, pathErr := PathExists(filePath)
-	if pathErr != nil || !exists {
+	if len(cli.googleSheet) < 1 && (pathErr != nil || !exists) {
 		processingErr = ErrMsg{Err: pathErr, Code: ErrNoFile}
 	}
 	// Validate file type
-	if !isXlsxFile(filePath) {
+	if len(cli.fixedWidth) < 1 && len(cli.googleSheet) < 1 && !isXlsxFile(filePath) {
 		processingErr = ErrMsg{
 			Err:  errors.New("invalid file type"),
 			Code: ErrInvalidFileType,
 		}
+		return
+	}
+	// Select the worksheets to parse
+	opts, optsErr := buildOptions(sheetName)
+	if optsErr != nil {
+		processingErr = ErrMsg{Err: optsErr, Code: ErrNoInput}
+		return
+	}
+	// Stop the conversion on interrupt or once the timeout elapses
+	ctx, cancel := conversionContext()
+	defer cancel()
+	// Write one file per worksheet when an output directory was provided
+	if len(cli.outDir) > 0 {
+		if _, parseErr := converters.XlsxToXmlFiles(ctx, cli.outDir, filePath, opts); parseErr != nil {
+			processingErr = ErrMsg{Err: parseErr, Code: conversionCode(parseErr)}
+		}
+		return
+	}
+	// Stream the XML straight to stdout as the rows are read
+	if opts.Streaming {
+		writer := bufio.NewWriter(os.Stdout)
+		if parseErr := converters.XlsxToXml(ctx, writer, filePath, opts); parseErr != nil {
+			processingErr = ErrMsg{Err: parseErr, Code: conversionCode(parseErr)}
+		} else if writeErr := writer.Flush(); writeErr != nil {
+			processingErr = ErrMsg{Err: writeErr, Code: ErrStdout}
+		}
+		return
 	}
 	// Parse the file as XML
-	output, parseErr := parseXlsxFile(filePath, sheetName)
-	if parseErr != nil {
-		processingErr = ErrMsg{Err: parseErr, Code: ErrParse}
+	var output bytes.Buffer
+	if parseErr := converters.XlsxToXml(ctx, &output, filePath, opts); parseErr != nil {
+		processingErr = ErrMsg{Err: parseErr, Code: conversionCode(parseErr)}
 	} else {
 		// Write the output to stdout
-		_, writeErr := os.Stdout.Write(output)
+		_, writeErr := os.Stdout.Write(output.Bytes())
 		if writeErr != nil {
 			processingErr = ErrMsg{Err: writeErr, Code: ErrStdout}
 		}
 	}
 }
 
-// CheckExtension checks if the given file path has the specified extension.
-// It adds a dot to the beginning of the extension if it's missing.
-// Returns true if the file extension matches the specified extension, and false otherwise.
-func isXlsxFile(path string) bool {
-	return CheckExtension(path, ".xlsx")
+// buildOptions turns the command line flags into converter options.
+// A worksheet name given with -sheet takes precedence over a -sheets selector.
+func buildOptions(sheetName string) (opts converters.Options, err error) {
+	opts.Combine = cli.combine
+	if cli.xsd && len(cli.outDir) < 1 {
+		return opts, errors.New("-xsd requires -out")
+	}
+	opts.GenerateXSD = cli.xsd
+	if cli.chunk > 0 && len(cli.outDir) < 1 {
+		return opts, errors.New("-chunk requires -out")
+	}
+	opts.ChunkSize = cli.chunk
+	if opts.ErrorReport, err = converters.ParseReportFormat(cli.report); err != nil {
+		return opts, err
+	}
+	if opts.ErrorReport != converters.NoReport && len(cli.outDir) < 1 {
+		return opts, errors.New("-report requires -out")
+	}
+	if opts.Compression, err = converters.ParseCompression(cli.compress); err != nil {
+		return opts, err
+	}
+	if opts.Compression == converters.ZipCompression && len(cli.outDir) < 1 {
+		return opts, errors.New("-compress zip requires -out")
+	}
+	if len(cli.validate) > 0 {
+		if opts.Validate, err = converters.LoadSchema(cli.validate); err != nil {
+			return opts, err
+		}
+	}
+	opts.Streaming = cli.stream
+	if cli.progress > 0 {
+		opts.Progress, opts.ProgressInterval = logProgress(time.Now()), cli.progress
+	}
+	opts.ResolveMergedCells = cli.merged
+	opts.SkipHiddenRows = cli.skipHiddenRows
+	opts.SkipHiddenColumns = cli.skipHiddenCols
+	opts.Password = converters.PasswordFromEnv(cli.password)
+	opts.HeaderRowIndex = cli.header
+	opts.Range = cli.dataRange
+	opts.Region.Detect = cli.region
+	opts.NullTokens = ParseNullTokens(cli.nullTokens)
+	opts.SkipRows = cli.skip
+	opts.SkipFooterRows = cli.footer
+	if len(cli.fixedWidth) > 0 {
+		if opts.FixedWidth, err = fixedwidth.LoadLayout(cli.fixedWidth); err != nil {
+			return opts, err
+		}
+		opts.FixedWidthEncoding = cli.fixedEncoding
+	}
+	if len(cli.googleSheet) > 0 {
+		var ranges []string
+		if len(cli.googleRanges) > 0 {
+			ranges = strings.Split(cli.googleRanges, ",")
+		}
+		if opts.GoogleSheet, err = converters.NewGoogleSheet(cli.googleKey, cli.googleSheet, ranges); err != nil {
+			return opts, err
+		}
+	}
+	if opts.Columns, err = buildColumnFilter(); err != nil {
+		return opts, err
+	}
+	if opts.ColumnOrder, err = converters.ParseColumnOrder(cli.order); err != nil {
+		return opts, err
+	}
+	if len(cli.where) > 0 {
+		if opts.RowFilter, err = converters.ParseRowFilter(cli.where); err != nil {
+			return opts, err
+		}
+	}
+	if len(cli.check) > 0 {
+		rules, rulesErr := validators.ParseRules(cli.check)
+		if rulesErr != nil {
+			return opts, rulesErr
+		}
+		check := validators.RowFilter(rules, func(column string, err error) {
+			log.Warn("Dropping row", "column", column, "error", err)
+		})
+		if where := opts.RowFilter; where != nil {
+			opts.RowFilter = func(record map[string]string) bool { return where(record) && check(record) }
+		} else {
+			opts.RowFilter = check
+		}
+	}
+	if opts.Formulas, err = converters.ParseFormulaMode(cli.formulas); err != nil {
+		return opts, err
+	}
+	if opts.RichText, err = converters.ParseRichTextMode(cli.richText); err != nil {
+		return opts, err
+	}
+	opts.Xml = converters.XmlOptions{RootName: cli.root, RowName: cli.row, Attributes: cli.attrs, InferTypes: cli.types}
+	if len(cli.cdata) > 0 {
+		opts.Xml.CDataColumns = strings.Split(cli.cdata, ",")
+	}
+	opts.Xml.AutoCData = cli.autoCData
+	opts.Xml.Indent = strings.ReplaceAll(cli.indent, `\t`, "\t")
+	opts.Xml.Compact = cli.compact
+	opts.Xml.Encoding = cli.encoding
+	opts.Xml.ByteOrderMark = cli.bom
+	opts.Xml.Transliterate = cli.transliterate
+	opts.Xml.Declaration = cli.decl
+	if cli.crlf {
+		opts.Xml.Newline = "\r\n"
+	}
+	opts.Xml.Namespace = cli.namespace
+	opts.Xml.Namespaces = cli.prefixes
+	opts.Xml.ColumnPrefixes = cli.qualified
+	if len(cli.links) > 0 {
+		opts.Xml.LinkColumns = strings.Split(cli.links, ",")
+	}
+	if opts.Xml.EmptyCells, err = converters.ParseEmptyCellMode(cli.empty); err != nil {
+		return opts, err
+	}
+	if opts.Xml.Comments, err = converters.ParseCommentMode(cli.comments); err != nil {
+		return opts, err
+	}
+	if len(sheetName) > 0 {
+		opts.Sheets = converters.SheetByName(sheetName)
+		return opts, nil
+	}
+	opts.Sheets, err = converters.ParseSheetSelector(cli.sheets)
+	return opts, err
 }
 
-func parseXlsxFile(path, targetSheet string) (output []byte, parseErr error) {
-	// Open the .xlsx file
-	file, openFileErr := excelize.OpenFile(path)
-	if openFileErr != nil {
-		return nil, openFileErr
-	}
-	defer func(file *excelize.File) {
-		err := file.Close()
-		if err != nil {
-			parseErr = err
-		}
-	}(file)
+// logProgress returns a progress callback logging the percentage of rows converted
+// and the time remaining, estimated from the rate since start.
+func logProgress(start time.Time) func(done, total int) {
+	return func(done, total int) {
+		if total < 1 {
+			return
+		}
+		elapsed := time.Since(start)
+		remaining := time.Duration(float64(elapsed) * float64(total-done) / float64(done))
+		log.Info("Converting", "rows", done, "total", total,
+			"progress", fmt.Sprintf("%.1f%%", 100*float64(done)/float64(total)), "eta", remaining.Round(time.Second))
+	}
+}
 
-	// Get the target sheet, or the default if no target was provided
-	var rows *excelize.Rows
-	var rowsErr error
+// keyValueFlag returns a flag handler collecting repeated key=value arguments into the map at target.
+func keyValueFlag(target *map[string]string) func(string) error {
+	return func(argument string) error {
+		key, value, found := strings.Cut(argument, "=")
+		if !found {
+			return fmt.Errorf("'%s' is not of the form key=value", argument)
+		}
+		if *target == nil {
+			*target = make(map[string]string)
+		}
+		(*target)[key] = value
+		return nil
+	}
+}
 
-	if len(targetSheet) > 1 {
-		rows, rowsErr = file.Rows(targetSheet)
-	} else {
-		rows, rowsErr = file.Rows(file.GetSheetName(0))
+// conversionContext returns the context of the conversion, cancelled on interrupt and once -timeout elapses.
+func conversionContext() (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	if cli.timeout <= 0 {
+		return ctx, stop
 	}
-	if rowsErr != nil {
-		return nil, rowsErr
+	ctx, cancel := context.WithTimeout(ctx, cli.timeout)
+	return ctx, func() {
+		cancel()
+		stop()
 	}
-	// Marshal the data into XML
-	xmlOutput, marshalErr := xml.MarshalIndent(buildDataTable(rows), "", "  ")
-	if marshalErr != nil {
-		return nil, marshalErr
-	} else {
-		output = xmlOutput
+}
+
+// conversionCode returns the exit code of a failed conversion, telling schema violations
+// and cancelled conversions apart from other errors.
+func conversionCode(err error) int {
+	var validationErr *converters.ValidationError
+	if errors.As(err, &validationErr) {
+		return ErrValidation
 	}
-	return output, nil
+	return ConversionCode(err)
 }
 
-// cleanHeader takes a pointer to a string `header` as input and modifies it.
-// It calls the FixXMLTags function to clean the `header`, replacing any invalid XML characters.
-// The modified `header` is then assigned back to the original pointer.
-// Example usage:
-//
-//	header := "<Hello World!>"
-//	cleanHeader(&header)
-//	fmt.Println(header)
-//	// Output: "Hello World"
-func cleanHeader(header *string) {
-	newHeader := *header
-	newHeader = FixXMLTags(newHeader)
-	*header = newHeader
+// buildColumnFilter turns the column flags into a converters.ColumnFilter.
+func buildColumnFilter() (filter converters.ColumnFilter, err error) {
+	if len(cli.include) > 0 {
+		filter.Include = strings.Split(cli.include, ",")
+	}
+	if len(cli.exclude) > 0 {
+		filter.Exclude = strings.Split(cli.exclude, ",")
+	}
+	if len(cli.pattern) > 0 {
+		filter.Pattern, err = regexp.Compile(cli.pattern)
+	}
+	return filter, err
 }
 
-// buildDataTable takes an excelize.Rows pointer as input and converts it into a DataTable struct.
-// It iterates over each row in the rows and converts each row into a DataRow struct.
-// If the rows pointer is nil, it returns an empty DataTable struct.
-// For the first row, it renames any duplicate headers using the RenameDuplicates function.
-// It then calls the cleanHeader function to clean each header.
-// For subsequent rows, it converts each column into a DataColumn struct and appends it to the DataRow struct.
-// The DataRow struct is then appended to the Rows field of the DataTable struct.
-// The function returns the populated DataTable struct.
-func buildDataTable(rows *excelize.Rows) DataTable {
-	var dataTable DataTable
-	var headerRow []string
-	var rowIndex int
-	if rows == nil {
-		return dataTable
-	}
-	for rows.Next() {
-		columns, colErr := rows.Columns()
-		if colErr != nil {
-			return DataTable{}
-		}
-		if rowIndex == 0 {
-			headerRow = RenameDuplicates(columns, false)
-			for headerIndex := range headerRow {
-				cleanHeader(&headerRow[headerIndex])
-			}
-		} else {
-			// Dirty workaround because `(*rows).Columns()` doesn't do what it says it does.
-			for len(columns) < len(headerRow) {
-				columns = append(columns, "")
-			}
-			var dataRow DataRow
-			for columnIndex := range columns {
-				columnName := headerRow[columnIndex]
-				columnValue := ConvertToISO8601(columns[columnIndex])
-				column := DataColumn{XMLName: xml.Name{Local: columnName}, Value: columnValue}
-				dataRow.Columns = append(dataRow.Columns, column)
-			}
-			dataTable.Rows = append(dataTable.Rows, dataRow)
-		}
-		rowIndex++
+// CheckExtension checks if the given file path has the specified extension.
+// It adds a dot to the beginning of the extension if it's missing.
+// Returns true if the file extension matches the specified extension, and false otherwise.
+// Legacy .xls workbooks and OpenDocument spreadsheets are accepted as well.
+func isXlsxFile(path string) bool {
+	return CheckExtension(path, ".xlsx") || CheckExtension(path, ".xls") || CheckExtension(path, ".ods")
+}
+
+// parseXlsxFile converts a single worksheet of the .xlsx file at path into XML.
+// The first worksheet is used if no target sheet was provided.
+func parseXlsxFile(path, targetSheet string) ([]byte, error) {
+	var opts converters.Options
+	if len(targetSheet) > 0 {
+		opts.Sheets = converters.SheetByName(targetSheet)
+	}
+	var output bytes.Buffer
+	if err := converters.XlsxToXml(context.Background(), &output, path, opts); err != nil {
+		return nil, err
 	}
-	return dataTable
+	return output.Bytes(), nil
 }
@@ -82,7 +82,11 @@ func createOutputXmlFile(fileName string, xmlData []byte) (string, error) {
 		fileName = strings.TrimSuffix(fileName, suffix) + ".xml"
 	}
 	dir, _ := os.Getwd()
-	filePath := filepath.Join(dir, "test-results", fileName)
+	resultsDir := filepath.Join(dir, "test-results")
+	if err := os.MkdirAll(resultsDir, 0755); err != nil {
+		return "", err
+	}
+	filePath := filepath.Join(resultsDir, fileName)
 
 	// write output to xml file
 	xmlFile, xmlFileErr := os.Create(filePath)
@@ -0,0 +1,187 @@
+// This program prints the first, the last or a random sample of the records of a worksheet as an aligned table,
+// to look at a large file quickly without converting it.
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"os"
+	"os/signal"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"GoTools/pkg/converters"
+	"GoTools/pkg/datatable"
+	"GoTools/pkg/fixedwidth"
+	. "GoTools/pkg/helpers"
+)
+
+// cliOptions holds the command line flags.
+type cliOptions struct {
+	path           string
+	sheet          string
+	dataRange      string
+	region         bool
+	nullTokens     string
+	rows           int
+	mode           string
+	seed           int64
+	types          bool
+	merged         bool
+	skipHiddenRows bool
+	skipHiddenCols bool
+	formulas       string
+	header         int
+	skip           int
+	footer         int
+	password       string
+	fixedWidth     string
+	fixedEncoding  string
+	timeout        time.Duration
+}
+
+var cli cliOptions
+
+func main() {
+	processingErr := ErrMsg{Code: Success}
+	defer func() {
+		processingErr.Exit()
+	}()
+	flag.StringVar(&cli.path, "path", "", "The path to the .xlsx file to preview")
+	flag.StringVar(&cli.sheet, "sheet", "", "The name of the worksheet to preview (default the first one)")
+	flag.StringVar(&cli.dataRange, "range", "", "Named range or Excel table to read instead of the whole worksheet, found on whichever worksheet holds it")
+	flag.BoolVar(&cli.region, "detect-region", false, "Detect where the data starts and ends, skipping blank leading rows and columns, blank rows and footer notes")
+	flag.StringVar(&cli.nullTokens, "null-tokens", "", "Comma separated values, such as N/A or #N/A, read as empty cells; default for the common ones")
+	flag.IntVar(&cli.rows, "rows", 20, "Number of records shown")
+	flag.StringVar(&cli.mode, "mode", "head", "Records shown: head for the first ones, tail for the last ones or sample for a random sample")
+	flag.Int64Var(&cli.seed, "seed", 0, "Seed of the random sample; the same seed shows the same records (default the current time)")
+	flag.BoolVar(&cli.types, "types", false, "Write the type inferred for every column below its name")
+	flag.BoolVar(&cli.merged, "merged", false, "Copy the value of merged cells into every cell they cover")
+	flag.BoolVar(&cli.skipHiddenRows, "skip-hidden-rows", false, "Leave out the rows hidden in the workbook; the header row is always read")
+	flag.BoolVar(&cli.skipHiddenCols, "skip-hidden-cols", false, "Leave out the columns hidden in the workbook, which loads the whole worksheet into memory")
+	flag.StringVar(&cli.formulas, "formulas", "cached", "Value of formula cells: cached, text or evaluate")
+	flag.IntVar(&cli.header, "header", 0, "Zero-based index of the row holding the column names")
+	flag.IntVar(&cli.skip, "skip", 0, "Number of rows to skip directly below the header row")
+	flag.IntVar(&cli.footer, "skip-footer", 0, "Number of rows to skip at the end of the worksheet")
+	flag.StringVar(&cli.password, "password-env", "XLSX_PASSWORD", "Environment variable holding the password of an encrypted workbook")
+	flag.StringVar(&cli.fixedWidth, "fixed-width", "", "Layout file of the fields of a fixed-width text file to read instead of a workbook")
+	flag.StringVar(&cli.fixedEncoding, "fixed-width-encoding", "UTF-8", "Encoding of the fixed-width text file: UTF-8, UTF-16, UTF-16LE, UTF-16BE, ISO-8859-1 or Windows-1252")
+	flag.DurationVar(&cli.timeout, "timeout", 0, "Abort the preview once it has run this long, e.g. 90s or 10m (default no limit)")
+	flag.Parse()
+
+	filePath := strings.TrimSpace(cli.path)
+	if len(filePath) < 1 {
+		processingErr = ErrMsg{Err: errors.New("no .xlsx path provided with the -path flag"), Code: ErrNoInput}
+		return
+	}
+	if exists, pathErr := PathExists(filePath); pathErr != nil || !exists {
+		processingErr = ErrMsg{Err: pathErr, Code: ErrNoFile}
+		return
+	}
+	if len(cli.fixedWidth) < 1 && !CheckExtension(filePath, ".xlsx") && !CheckExtension(filePath, ".xls") && !CheckExtension(filePath, ".ods") {
+		processingErr = ErrMsg{Err: errors.New("invalid file type"), Code: ErrInvalidFileType}
+		return
+	}
+	opts, optsErr := buildOptions()
+	if optsErr != nil {
+		processingErr = ErrMsg{Err: optsErr, Code: ErrNoInput}
+		return
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if cli.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cli.timeout)
+		defer cancel()
+	}
+	table, previewErr := converters.PreviewTable(ctx, filePath, opts)
+	if previewErr != nil {
+		processingErr = ErrMsg{Err: previewErr, Code: ConversionCode(previewErr)}
+		return
+	}
+	writer := bufio.NewWriter(os.Stdout)
+	if writeErr := writeTable(writer, table); writeErr != nil {
+		processingErr = ErrMsg{Err: writeErr, Code: ErrStdout}
+	} else if writeErr = writer.Flush(); writeErr != nil {
+		processingErr = ErrMsg{Err: writeErr, Code: ErrStdout}
+	}
+}
+
+// buildOptions turns the command line flags into preview options.
+func buildOptions() (opts converters.PreviewOptions, err error) {
+	opts.Rows = cli.rows
+	if opts.Mode, err = converters.ParsePreviewMode(cli.mode); err != nil {
+		return opts, err
+	}
+	opts.Seed = cli.seed
+	if opts.Mode == converters.PreviewSample && !flagSet("seed") {
+		opts.Seed = time.Now().UnixNano()
+	}
+	opts.InferTypes = cli.types
+	opts.ResolveMergedCells = cli.merged
+	opts.SkipHiddenRows = cli.skipHiddenRows
+	opts.SkipHiddenColumns = cli.skipHiddenCols
+	opts.Password = converters.PasswordFromEnv(cli.password)
+	opts.HeaderRowIndex = cli.header
+	opts.Range = cli.dataRange
+	opts.Region.Detect = cli.region
+	opts.NullTokens = ParseNullTokens(cli.nullTokens)
+	opts.SkipRows = cli.skip
+	opts.SkipFooterRows = cli.footer
+	if len(cli.fixedWidth) > 0 {
+		if opts.FixedWidth, err = fixedwidth.LoadLayout(cli.fixedWidth); err != nil {
+			return opts, err
+		}
+		opts.FixedWidthEncoding = cli.fixedEncoding
+	}
+	if opts.Formulas, err = converters.ParseFormulaMode(cli.formulas); err != nil {
+		return opts, err
+	}
+	if len(cli.sheet) > 0 {
+		opts.Sheets = converters.SheetByName(cli.sheet)
+	}
+	return opts, nil
+}
+
+// flagSet reports whether the named flag was given on the command line.
+func flagSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		set = set || f.Name == name
+	})
+	return set
+}
+
+// writeTable writes the columns and the records of table to w as columns aligned with spaces, with the types of
+// the columns below their names when -types is set.
+func writeTable(w *bufio.Writer, table *datatable.DataTable) error {
+	aligned := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	line := func(values []string) error {
+		for index, value := range values {
+			values[index] = strings.NewReplacer("\t", " ", "\r\n", " ", "\n", " ").Replace(value)
+		}
+		_, err := aligned.Write([]byte(strings.Join(values, "\t") + "\n"))
+		return err
+	}
+	if err := line(table.ColumnNames()); err != nil {
+		return err
+	}
+	if cli.types {
+		columns := table.Columns()
+		types := make([]string, len(columns))
+		for index, column := range columns {
+			types[index] = "(" + column.Type.String() + ")"
+		}
+		if err := line(types); err != nil {
+			return err
+		}
+	}
+	if err := table.Each(func(row datatable.Row) error { return line(row.Values()) }); err != nil {
+		return err
+	}
+	return aligned.Flush()
+}
@@ -0,0 +1,82 @@
+// This program builds a .xlsx workbook from the records of an XML document.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"GoTools/pkg/converters"
+	. "GoTools/pkg/helpers"
+)
+
+// cliOptions holds the command line flags.
+type cliOptions struct {
+	path        string
+	out         string
+	records     string
+	sheet       string
+	styleHeader bool
+	autoWidth   bool
+	types       bool
+	timeout     time.Duration
+}
+
+var cli cliOptions
+
+func main() {
+	processingErr := ErrMsg{Code: Success}
+	defer func() {
+		processingErr.Exit()
+	}()
+	flag.StringVar(&cli.path, "path", "", "The path to the XML file to convert")
+	flag.StringVar(&cli.out, "out", "", "The path of the .xlsx file to write")
+	flag.StringVar(&cli.records, "records", "", "Path of the record elements, e.g. /DataTable/Row or //Row (default the children of the root)")
+	flag.StringVar(&cli.sheet, "sheet", "", "The name of the worksheet (default the file name)")
+	flag.BoolVar(&cli.styleHeader, "style-header", false, "Write the header row in bold and freeze it")
+	flag.BoolVar(&cli.autoWidth, "autowidth", false, "Size every column to its longest value")
+	flag.BoolVar(&cli.types, "types", false, "Write numbers, booleans and dates as such instead of as text")
+	flag.DurationVar(&cli.timeout, "timeout", 0, "Abort the conversion once it has run this long, e.g. 90s or 10m (default no limit)")
+	flag.Parse()
+
+	filePath := strings.TrimSpace(cli.path)
+	if len(filePath) < 1 {
+		processingErr = ErrMsg{Err: errors.New("no XML path provided with the -path flag"), Code: ErrNoInput}
+		return
+	}
+	if exists, pathErr := PathExists(filePath); pathErr != nil || !exists {
+		processingErr = ErrMsg{Err: pathErr, Code: ErrNoFile}
+		return
+	}
+	outPath := strings.TrimSpace(cli.out)
+	if !CheckExtension(outPath, ".xlsx") {
+		processingErr = ErrMsg{Err: errors.New("the -out flag must name a .xlsx file"), Code: ErrInvalidFileType}
+		return
+	}
+	opts := converters.XmlToXlsxOptions{
+		Records:     cli.records,
+		SheetName:   cli.sheet,
+		StyleHeader: cli.styleHeader,
+		AutoWidth:   cli.autoWidth,
+		CoerceTypes: cli.types,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if cli.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cli.timeout)
+		defer cancel()
+	}
+	if convertErr := converters.XmlToXlsx(ctx, outPath, filePath, opts); convertErr != nil {
+		code := ErrParse
+		if errors.Is(convertErr, context.Canceled) || errors.Is(convertErr, context.DeadlineExceeded) {
+			code = ErrCancelled
+		}
+		processingErr = ErrMsg{Err: convertErr, Code: code}
+	}
+}
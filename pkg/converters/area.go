@@ -0,0 +1,104 @@
+package converters
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// cellArea is the cell range of a worksheet covered by a named range or an Excel table,
+// with one-based, inclusive coordinates.
+type cellArea struct {
+	sheet              string
+	startCol, startRow int
+	endCol, endRow     int
+}
+
+// findArea returns the cell range of the defined name or the Excel table of file called name, matched regardless
+// of case as Excel does. Defined names are looked up first; of those scoped to a worksheet, the first one wins.
+func findArea(file *excelize.File, name string) (cellArea, error) {
+	for _, definedName := range file.GetDefinedName() {
+		if strings.EqualFold(definedName.Name, name) {
+			area, refErr := parseAreaReference(definedName.RefersTo)
+			if refErr != nil {
+				return cellArea{}, fmt.Errorf("named range '%s': %w", name, refErr)
+			}
+			return area, nil
+		}
+	}
+	for _, sheet := range file.GetSheetList() {
+		tables, tablesErr := file.GetTables(sheet)
+		if tablesErr != nil {
+			return cellArea{}, tablesErr
+		}
+		for _, table := range tables {
+			if !strings.EqualFold(table.Name, name) {
+				continue
+			}
+			if table.ShowHeaderRow != nil && !*table.ShowHeaderRow {
+				return cellArea{}, fmt.Errorf("table '%s' has no header row", name)
+			}
+			area := cellArea{sheet: sheet}
+			if err := area.setRange(table.Range); err != nil {
+				return cellArea{}, fmt.Errorf("table '%s': %w", name, err)
+			}
+			return area, nil
+		}
+	}
+	return cellArea{}, fmt.Errorf("no named range or table called '%s'", name)
+}
+
+// parseAreaReference parses the "Sheet!$A$1:$C$10" reference a defined name refers to,
+// the worksheet name being quoted when it holds spaces or punctuation.
+func parseAreaReference(reference string) (cellArea, error) {
+	reference = strings.TrimPrefix(strings.TrimSpace(reference), "=")
+	separator := strings.LastIndex(reference, "!")
+	if separator < 1 || strings.Contains(reference, ",") {
+		return cellArea{}, fmt.Errorf("'%s' does not refer to a single cell range of a worksheet", reference)
+	}
+	sheet := reference[:separator]
+	if len(sheet) > 1 && strings.HasPrefix(sheet, "'") && strings.HasSuffix(sheet, "'") {
+		sheet = strings.ReplaceAll(sheet[1:len(sheet)-1], "''", "'")
+	}
+	area := cellArea{sheet: sheet}
+	if err := area.setRange(reference[separator+1:]); err != nil {
+		return cellArea{}, err
+	}
+	return area, nil
+}
+
+// setRange sets the coordinates of the area from a "A1:C10" range, which may hold absolute references,
+// or from the name of a single cell.
+func (a *cellArea) setRange(cellRange string) error {
+	start, end, isRange := strings.Cut(strings.ReplaceAll(cellRange, "$", ""), ":")
+	if !isRange {
+		end = start
+	}
+	var startErr, endErr error
+	a.startCol, a.startRow, startErr = excelize.CellNameToCoordinates(start)
+	a.endCol, a.endRow, endErr = excelize.CellNameToCoordinates(end)
+	if startErr != nil || endErr != nil {
+		return fmt.Errorf("'%s' is not a cell range", cellRange)
+	}
+	if a.startCol > a.endCol {
+		a.startCol, a.endCol = a.endCol, a.startCol
+	}
+	if a.startRow > a.endRow {
+		a.startRow, a.endRow = a.endRow, a.startRow
+	}
+	return nil
+}
+
+// selectSheets returns the worksheets of file to read: the one holding Range when it is set, whatever
+// sheets selects, and those selected by sheets otherwise.
+func (o ReadOptions) selectSheets(file *excelize.File, sheets SheetSelector) ([]string, error) {
+	if len(o.Range) < 1 {
+		return sheets.resolve(file)
+	}
+	area, areaErr := findArea(file, o.Range)
+	if areaErr != nil {
+		return nil, areaErr
+	}
+	return []string{area.sheet}, nil
+}
@@ -0,0 +1,122 @@
+package converters
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// createAreaWorkbook saves a workbook whose data blocks float within their worksheets: the named range Orders
+// on the worksheet Report and the table Stock on the worksheet called Side Sheet, both surrounded by stray cells.
+func createAreaWorkbook(t *testing.T) string {
+	t.Helper()
+	file := excelize.NewFile()
+	defer func() {
+		_ = file.Close()
+	}()
+	steps := []func() error{
+		func() error { return file.SetSheetName("Sheet1", "Report") },
+		func() error { return file.SetCellStr("Report", "A1", "Monthly orders") },
+		func() error { return file.SetSheetRow("Report", "C3", &[]any{"Id", "Item"}) },
+		func() error { return file.SetSheetRow("Report", "C4", &[]any{1, "Bolt", "note"}) },
+		func() error { return file.SetSheetRow("Report", "C5", &[]any{2, "Nut"}) },
+		func() error { return file.SetCellStr("Report", "C7", "Printed by accounting") },
+		func() error {
+			return file.SetDefinedName(&excelize.DefinedName{Name: "Orders", RefersTo: "Report!$C$3:$D$5"})
+		},
+		func() error { _, err := file.NewSheet("Side Sheet"); return err },
+		func() error { return file.SetCellStr("Side Sheet", "A1", "Stock levels") },
+		func() error { return file.SetSheetRow("Side Sheet", "B2", &[]any{"Part", "Qty"}) },
+		func() error { return file.SetSheetRow("Side Sheet", "B3", &[]any{"Washer", 40}) },
+		func() error { return file.AddTable("Side Sheet", &excelize.Table{Range: "B2:C3", Name: "Stock"}) },
+	}
+	for _, step := range steps {
+		if err := step(); err != nil {
+			t.Fatalf("Error building the test workbook: %v", err)
+		}
+	}
+	path := filepath.Join(t.TempDir(), "areas.xlsx")
+	if err := file.SaveAs(path); err != nil {
+		t.Fatalf("Error saving the test workbook: %v", err)
+	}
+	return path
+}
+
+func TestReadRange(t *testing.T) {
+	filePath := createAreaWorkbook(t)
+
+	tests := []struct {
+		name    string
+		opts    CsvOptions
+		want    string
+		wantErr string
+	}{
+		{
+			name: "Named Range",
+			opts: CsvOptions{ReadOptions: ReadOptions{Range: "orders"}},
+			want: "Id,Item\n1,Bolt\n2,Nut\n",
+		},
+		{
+			name: "Table",
+			opts: CsvOptions{ReadOptions: ReadOptions{Range: "Stock"}},
+			want: "Part,Qty\nWasher,40\n",
+		},
+		{
+			name: "Header Within Range",
+			opts: CsvOptions{ReadOptions: ReadOptions{Range: "Orders", HeaderRowIndex: 1}},
+			want: "1,Bolt\n2,Nut\n",
+		},
+		{
+			name:    "Unknown Name",
+			opts:    CsvOptions{ReadOptions: ReadOptions{Range: "Missing"}},
+			wantErr: "no named range or table called 'Missing'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output bytes.Buffer
+			err := XlsxToCsv(context.Background(), &output, filePath, tt.opts)
+			if len(tt.wantErr) > 0 {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("XlsxToCsv() error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("XlsxToCsv() error = %v", err)
+			}
+			if output.String() != tt.want {
+				t.Errorf("XlsxToCsv() output = %q, want %q", output.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestParseAreaReference(t *testing.T) {
+	tests := []struct {
+		reference string
+		want      cellArea
+		wantErr   bool
+	}{
+		{reference: "Data!$B$2:$D$9", want: cellArea{sheet: "Data", startCol: 2, startRow: 2, endCol: 4, endRow: 9}},
+		{reference: "='It''s here'!C5", want: cellArea{sheet: "It's here", startCol: 3, startRow: 5, endCol: 3, endRow: 5}},
+		{reference: "Data!$A$1:$A$2,Data!$C$1", wantErr: true},
+		{reference: "Data!$A:$C", wantErr: true},
+		{reference: "42", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.reference, func(t *testing.T) {
+			got, err := parseAreaReference(tt.reference)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseAreaReference() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("parseAreaReference() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
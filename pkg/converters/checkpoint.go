@@ -0,0 +1,186 @@
+package converters
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+)
+
+// defaultCheckpointInterval is the number of records written between checkpoints when
+// CheckpointOptions.Interval is not set.
+const defaultCheckpointInterval = 10000
+
+// ResumableSink is a RowSink which can continue an output it was interrupted writing, see ConvertResumable.
+// Resume is called instead of WriteHeader, with the same header, when the output already holds the header and
+// written records, so the sink appends the records following them. Flush writes the records the sink holds back
+// to its output, so a checkpoint taken after it covers them.
+type ResumableSink interface {
+	RowSink
+	Resume(header []string, written int) error
+	Flush() error
+}
+
+// CheckpointOptions configures checkpointing a conversion. Path names the sidecar file the checkpoint is kept in,
+// the output path followed by ".checkpoint" by default, and Interval is the number of records written between
+// checkpoints, 10000 by default.
+type CheckpointOptions struct {
+	Path     string
+	Interval int
+}
+
+// checkpoint is how far a conversion got: the header of its source, the number of records written and the size
+// of the output holding them.
+type checkpoint struct {
+	Header  []string `json:"header"`
+	Records int      `json:"records"`
+	Offset  int64    `json:"offset"`
+}
+
+// loadCheckpoint returns the checkpoint saved at path, or nil when there is none.
+func loadCheckpoint(path string) (*checkpoint, error) {
+	content, readErr := os.ReadFile(path)
+	if errors.Is(readErr, os.ErrNotExist) {
+		return nil, nil
+	}
+	if readErr != nil {
+		return nil, readErr
+	}
+	var saved checkpoint
+	if err := json.Unmarshal(content, &saved); err != nil {
+		return nil, fmt.Errorf("checkpoint %s: %w", path, err)
+	}
+	return &saved, nil
+}
+
+// save writes the checkpoint to path, replacing the previous one only once it is complete.
+func (c checkpoint) save(path string) error {
+	content, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path+".tmp", content, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+// ConvertResumable copies the records of source into the sink newSink returns for the file at outPath, as Convert
+// does, checkpointing how far it got every opts.Interval records, so a conversion of a large file which crashes
+// or is cancelled can be run again and resume after the last checkpoint instead of starting over.
+// When a checkpoint is found, the output is cut back to the size it had then and the records it covers are read
+// but skipped; the source must hand on the same header and records as before. Without one the output is created.
+// newSink must return a ResumableSink, such as those of CsvSink, JsonSink in RecordsLayout and SqlSink. When the
+// conversion fails the sink is flushed and the checkpoint saved rather than the sink closed, so its output can be
+// continued; when the sink itself failed, its output may end in a partly written record and the last checkpoint
+// saved is kept instead. The checkpoint is removed once the conversion completes.
+func ConvertResumable(ctx context.Context, source RowSource, outPath string, newSink func(w io.Writer) RowSink, opts CheckpointOptions) (convertErr error) {
+	if opts.Interval < 0 {
+		return fmt.Errorf("invalid checkpoint interval %d", opts.Interval)
+	}
+	interval := opts.Interval
+	if interval < 1 {
+		interval = defaultCheckpointInterval
+	}
+	checkpointPath := opts.Path
+	if len(checkpointPath) < 1 {
+		checkpointPath = outPath + ".checkpoint"
+	}
+	saved, loadErr := loadCheckpoint(checkpointPath)
+	if loadErr != nil {
+		return loadErr
+	}
+	flags := os.O_RDWR | os.O_CREATE | os.O_TRUNC
+	if saved != nil {
+		flags = os.O_RDWR
+	}
+	output, openErr := os.OpenFile(outPath, flags, 0o644)
+	if openErr != nil {
+		return openErr
+	}
+	defer func(output *os.File) {
+		if err := output.Close(); err != nil && convertErr == nil {
+			convertErr = err
+		}
+	}(output)
+	if saved != nil {
+		if err := output.Truncate(saved.Offset); err != nil {
+			return err
+		}
+		if _, err := output.Seek(saved.Offset, io.SeekStart); err != nil {
+			return err
+		}
+	}
+	sink, resumable := newSink(output).(ResumableSink)
+	if !resumable {
+		return errors.New("the sink cannot resume its output")
+	}
+
+	current := checkpoint{}
+	if saved != nil {
+		current = *saved
+	}
+	// sinkErr is the error of the sink, past which its output cannot be trusted to end on a whole record.
+	var sinkErr error
+	save := func() error {
+		if sinkErr = sink.Flush(); sinkErr != nil {
+			return sinkErr
+		}
+		offset, err := output.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return err
+		}
+		current.Offset = offset
+		return current.save(checkpointPath)
+	}
+	read := 0
+	started := false
+	copyErr := source.Rows(ctx, func(header []string) error {
+		if saved == nil {
+			current.Header = header
+			if sinkErr = sink.WriteHeader(header); sinkErr != nil {
+				return sinkErr
+			}
+			started = true
+			return save()
+		}
+		if !slices.Equal(header, saved.Header) {
+			return fmt.Errorf("the header %v differs from the header %v of checkpoint %s", header, saved.Header, checkpointPath)
+		}
+		started = true
+		sinkErr = sink.Resume(header, saved.Records)
+		return sinkErr
+	}, func(record []string) error {
+		if read++; read <= current.Records {
+			return nil
+		}
+		if sinkErr = sink.WriteRecord(record); sinkErr != nil {
+			return sinkErr
+		}
+		if current.Records++; current.Records%interval == 0 {
+			return save()
+		}
+		return nil
+	})
+	if copyErr != nil {
+		if started && sinkErr == nil {
+			if err := save(); err != nil {
+				return errors.Join(copyErr, err)
+			}
+		}
+		return copyErr
+	}
+	if saved != nil && read < saved.Records {
+		return fmt.Errorf("the source holds %d records, checkpoint %s covers %d", read, checkpointPath, saved.Records)
+	}
+	if err := sink.Close(); err != nil {
+		return err
+	}
+	if err := os.Remove(checkpointPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
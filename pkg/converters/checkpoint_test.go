@@ -0,0 +1,169 @@
+package converters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"GoTools/pkg/datatable"
+)
+
+// errInterrupted stands for the failure interrupting a conversion.
+var errInterrupted = errors.New("interrupted")
+
+// interruptedSource hands on the records of source until the record numbered after, failing there.
+type interruptedSource struct {
+	source RowSource
+	after  int
+}
+
+func (s interruptedSource) Rows(ctx context.Context, onHeader func(header []string) error, onRecord func(record []string) error) error {
+	count := 0
+	return s.source.Rows(ctx, onHeader, func(record []string) error {
+		if count++; count > s.after {
+			return errInterrupted
+		}
+		return onRecord(record)
+	})
+}
+
+// checkpointTestTable returns a table of count records.
+func checkpointTestTable(t *testing.T, count int) *datatable.DataTable {
+	t.Helper()
+	records := make([][]string, count)
+	for index := range records {
+		records[index] = []string{fmt.Sprint(index + 1), fmt.Sprintf("Name %d", index+1)}
+	}
+	table, err := datatable.FromRecords([]string{"Id", "Name"}, records)
+	if err != nil {
+		t.Fatalf("FromRecords() error = %v", err)
+	}
+	return table
+}
+
+func TestConvertResumable(t *testing.T) {
+	tests := []struct {
+		name string
+		sink func(w io.Writer) RowSink
+	}{
+		{name: "Csv", sink: func(w io.Writer) RowSink { return CsvSink(w, CsvOptions{ByteOrderMark: true}) }},
+		{name: "Csv UTF-16", sink: func(w io.Writer) RowSink { return CsvSink(w, CsvOptions{Encoding: "UTF-16"}) }},
+		{name: "Json", sink: func(w io.Writer) RowSink { return JsonSink(w, JsonOptions{}) }},
+		{name: "Json Lines", sink: func(w io.Writer) RowSink { return JsonSink(w, JsonOptions{Lines: true}) }},
+		{
+			name: "Sql",
+			sink: func(w io.Writer) RowSink {
+				return SqlSink(w, SqlOptions{Table: "people", CreateTable: true})
+			},
+		},
+	}
+	ctx := context.Background()
+	table := checkpointTestTable(t, 25)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			wantPath, outPath := filepath.Join(dir, "want"), filepath.Join(dir, "out")
+			if err := ConvertResumable(ctx, TableSource(table), wantPath, tt.sink, CheckpointOptions{}); err != nil {
+				t.Fatalf("ConvertResumable() error = %v", err)
+			}
+			opts := CheckpointOptions{Interval: 5}
+			for _, after := range []int{7, 18} {
+				err := ConvertResumable(ctx, interruptedSource{source: TableSource(table), after: after}, outPath, tt.sink, opts)
+				if !errors.Is(err, errInterrupted) {
+					t.Fatalf("ConvertResumable() after %d records error = %v, want %v", after, err, errInterrupted)
+				}
+				saved, loadErr := loadCheckpoint(outPath + ".checkpoint")
+				if loadErr != nil || saved == nil || saved.Records != after {
+					t.Fatalf("loadCheckpoint() = %+v, %v, want %d records", saved, loadErr, after)
+				}
+			}
+			if err := ConvertResumable(ctx, TableSource(table), outPath, tt.sink, opts); err != nil {
+				t.Fatalf("ConvertResumable() error = %v", err)
+			}
+			want, _ := os.ReadFile(wantPath)
+			got, _ := os.ReadFile(outPath)
+			if string(got) != string(want) {
+				t.Errorf("resumed output = %q, want %q", got, want)
+			}
+			if _, err := os.Stat(outPath + ".checkpoint"); !errors.Is(err, os.ErrNotExist) {
+				t.Errorf("the checkpoint is kept after the conversion completed: %v", err)
+			}
+		})
+	}
+}
+
+func TestConvertResumableMismatch(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	outPath := filepath.Join(dir, "out.csv")
+	newSink := func(w io.Writer) RowSink { return CsvSink(w, CsvOptions{}) }
+	err := ConvertResumable(ctx, interruptedSource{source: TableSource(checkpointTestTable(t, 10)), after: 3}, outPath, newSink, CheckpointOptions{})
+	if !errors.Is(err, errInterrupted) {
+		t.Fatalf("ConvertResumable() error = %v, want %v", err, errInterrupted)
+	}
+	other, _ := datatable.FromRecords([]string{"Code", "Name"}, [][]string{{"1", "A"}})
+	if err := ConvertResumable(ctx, TableSource(other), outPath, newSink, CheckpointOptions{}); err == nil {
+		t.Error("ConvertResumable() resumed with a different header")
+	}
+	if err := ConvertResumable(ctx, TableSource(checkpointTestTable(t, 2)), outPath, newSink, CheckpointOptions{}); err == nil {
+		t.Error("ConvertResumable() resumed a source holding fewer records than checkpointed")
+	}
+	xmlSink := func(w io.Writer) RowSink { return XmlSink(w, XmlOptions{}) }
+	if err := ConvertResumable(ctx, TableSource(other), filepath.Join(dir, "out.xml"), xmlSink, CheckpointOptions{}); err == nil {
+		t.Error("ConvertResumable() accepted a sink which cannot resume")
+	}
+}
+
+// failingSink writes the records of a ResumableSink until the record numbered after, of which it writes a part
+// straight into w before failing, as a sink running out of disk space would.
+type failingSink struct {
+	ResumableSink
+	w       io.Writer
+	after   int
+	written int
+}
+
+func (s *failingSink) WriteRecord(record []string) error {
+	if s.written++; s.written > s.after {
+		if err := s.Flush(); err != nil {
+			return err
+		}
+		_, _ = io.WriteString(s.w, "partial")
+		return errInterrupted
+	}
+	return s.ResumableSink.WriteRecord(record)
+}
+
+func TestConvertResumableSinkFailure(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	wantPath, outPath := filepath.Join(dir, "want.csv"), filepath.Join(dir, "out.csv")
+	table := checkpointTestTable(t, 12)
+	newSink := func(w io.Writer) RowSink { return CsvSink(w, CsvOptions{}) }
+	if err := ConvertResumable(ctx, TableSource(table), wantPath, newSink, CheckpointOptions{}); err != nil {
+		t.Fatalf("ConvertResumable() error = %v", err)
+	}
+	failing := func(w io.Writer) RowSink {
+		return &failingSink{ResumableSink: CsvSink(w, CsvOptions{}).(ResumableSink), w: w, after: 7}
+	}
+	opts := CheckpointOptions{Interval: 5}
+	if err := ConvertResumable(ctx, TableSource(table), outPath, failing, opts); !errors.Is(err, errInterrupted) {
+		t.Fatalf("ConvertResumable() error = %v, want %v", err, errInterrupted)
+	}
+	saved, loadErr := loadCheckpoint(outPath + ".checkpoint")
+	if loadErr != nil || saved == nil || saved.Records != 5 {
+		t.Fatalf("loadCheckpoint() = %+v, %v, want the checkpoint of 5 records", saved, loadErr)
+	}
+	if err := ConvertResumable(ctx, TableSource(table), outPath, newSink, opts); err != nil {
+		t.Fatalf("ConvertResumable() error = %v", err)
+	}
+	want, _ := os.ReadFile(wantPath)
+	got, _ := os.ReadFile(outPath)
+	if string(got) != string(want) {
+		t.Errorf("resumed output = %q, want %q", got, want)
+	}
+}
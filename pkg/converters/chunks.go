@@ -0,0 +1,228 @@
+package converters
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Manifest indexes the chunk files a document was split into, in order.
+type Manifest struct {
+	XMLName   xml.Name        `xml:"Manifest"`
+	Source    string          `xml:"source,attr"`
+	ChunkSize int             `xml:"chunkSize,attr"`
+	Rows      int             `xml:"rows,attr"`
+	Chunks    []ManifestChunk `xml:"Chunk"`
+}
+
+// ManifestChunk describes a single chunk file and the worksheet rows it holds.
+type ManifestChunk struct {
+	File   string          `xml:"file,attr"`
+	Rows   int             `xml:"rows,attr"`
+	Ranges []ManifestRange `xml:"Range"`
+}
+
+// ManifestRange is a run of consecutive records of one worksheet within a chunk,
+// identified by the one-based worksheet row numbers of the first and last record.
+type ManifestRange struct {
+	Sheet    string `xml:"sheet,attr"`
+	FirstRow int    `xml:"firstRow,attr"`
+	LastRow  int    `xml:"lastRow,attr"`
+}
+
+// chunkWriter streams a document into a sequence of files holding at most size rows each.
+// Every chunk is a standalone document: the elements enclosing the rows are closed at the end of a chunk
+// and opened again at the start of the next one.
+type chunkWriter struct {
+	base     string
+	size     int
+	format   XmlOptions
+	files    *outputFiles
+	out      *outputFile
+	encoder  *documentEncoder
+	open     []xml.StartElement
+	manifest Manifest
+}
+
+// newChunkWriter prepares the chunks of the document at path, which are numbered path_0001.xml, path_0002.xml...
+// laid out as configured in format and created through files.
+func newChunkWriter(files *outputFiles, path, source string, size int, format XmlOptions) *chunkWriter {
+	return &chunkWriter{
+		base:     strings.TrimSuffix(path, filepath.Ext(path)),
+		size:     size,
+		format:   format,
+		files:    files,
+		manifest: Manifest{Source: source, ChunkSize: size},
+	}
+}
+
+// startChunk creates the next chunk file and opens the elements currently enclosing the rows in it.
+func (c *chunkWriter) startChunk() error {
+	path := fmt.Sprintf("%s_%04d.xml", c.base, len(c.manifest.Chunks)+1)
+	out, createErr := c.files.create(path)
+	if createErr != nil {
+		return createErr
+	}
+	c.out = out
+	encoder, encoderErr := c.format.newEncoder(out)
+	if encoderErr != nil {
+		c.abort()
+		return encoderErr
+	}
+	c.encoder = encoder
+	c.manifest.Chunks = append(c.manifest.Chunks, ManifestChunk{File: out.name})
+	for _, element := range c.open {
+		if err := c.encoder.EncodeToken(element); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// endChunk closes the elements enclosing the rows and the current chunk file.
+func (c *chunkWriter) endChunk() error {
+	for index := len(c.open) - 1; index >= 0; index-- {
+		if err := c.encoder.EncodeToken(c.open[index].End()); err != nil {
+			c.abort()
+			return err
+		}
+	}
+	if err := c.encoder.finish(); err != nil {
+		c.abort()
+		return err
+	}
+	out := c.out
+	c.out = nil
+	return out.close()
+}
+
+// abort closes the current chunk file, if any, after a failed conversion.
+func (c *chunkWriter) abort() {
+	if c.out != nil {
+		c.out.abort()
+		c.out = nil
+	}
+}
+
+// rotate moves on to the next chunk when the current one is full.
+func (c *chunkWriter) rotate() error {
+	if c.manifest.Chunks[len(c.manifest.Chunks)-1].Rows < c.size {
+		return nil
+	}
+	if err := c.endChunk(); err != nil {
+		return err
+	}
+	return c.startChunk()
+}
+
+// push opens an element enclosing the following rows, in the next chunk if the current one is full.
+func (c *chunkWriter) push(element xml.StartElement) error {
+	if err := c.rotate(); err != nil {
+		return err
+	}
+	c.open = append(c.open, element)
+	return c.encoder.EncodeToken(element)
+}
+
+// pop closes the innermost element enclosing the rows.
+func (c *chunkWriter) pop() error {
+	element := c.open[len(c.open)-1]
+	c.open = c.open[:len(c.open)-1]
+	return c.encoder.EncodeToken(element.End())
+}
+
+// writeRow encodes a row of the named worksheet, moving on to the next chunk first when the current one is full.
+func (c *chunkWriter) writeRow(sheet string, rowNumber int, dataRow DataRow, element xml.StartElement) error {
+	if err := c.rotate(); err != nil {
+		return err
+	}
+	chunk := &c.manifest.Chunks[len(c.manifest.Chunks)-1]
+	if last := len(chunk.Ranges) - 1; last >= 0 && chunk.Ranges[last].Sheet == sheet {
+		chunk.Ranges[last].LastRow = rowNumber
+	} else {
+		chunk.Ranges = append(chunk.Ranges, ManifestRange{Sheet: sheet, FirstRow: rowNumber, LastRow: rowNumber})
+	}
+	chunk.Rows++
+	c.manifest.Rows++
+	return c.encoder.EncodeElement(dataRow, element)
+}
+
+// writeManifest writes the manifest indexing the chunks next to them.
+func (c *chunkWriter) writeManifest() error {
+	return c.files.writeFile(c.base+"_manifest.xml", func(w io.Writer) error {
+		return writeXml(w, c.manifest, c.format)
+	})
+}
+
+// writeXmlChunks converts the named worksheets into chunk files of at most opts.ChunkSize rows next to path,
+// followed by the manifest indexing them.
+func writeXmlChunks(ctx context.Context, files *outputFiles, path string, file *excelize.File, sheets []string, opts Options, watchers observers) error {
+	chunks := newChunkWriter(files, path, filepath.Base(file.Path), opts.ChunkSize, opts.Xml)
+	if err := chunkSheets(ctx, chunks, file, sheets, opts, watchers); err != nil {
+		chunks.abort()
+		return err
+	}
+	if err := chunks.endChunk(); err != nil {
+		return err
+	}
+	return chunks.writeManifest()
+}
+
+// chunkSheets streams the named worksheets into the chunks, leaving the last chunk open.
+func chunkSheets(ctx context.Context, chunks *chunkWriter, file *excelize.File, sheets []string, opts Options, watchers observers) error {
+	if err := chunks.startChunk(); err != nil {
+		return err
+	}
+	if opts.Combine {
+		if err := chunks.push(opts.workbookStart()); err != nil {
+			return err
+		}
+	}
+	for _, sheet := range sheets {
+		if err := chunkSheet(ctx, chunks, file, sheet, opts, watchers); err != nil {
+			return err
+		}
+	}
+	if opts.Combine {
+		return chunks.pop()
+	}
+	return nil
+}
+
+// chunkSheet streams the rows of the named worksheet into the chunks.
+func chunkSheet(ctx context.Context, chunks *chunkWriter, file *excelize.File, sheet string, opts Options, watchers observers) error {
+	reader, readerErr := opts.newSheetReader(file, sheet)
+	if readerErr != nil {
+		return readerErr
+	}
+	if opts.Combine {
+		if err := chunks.push(sheetStart(sheet)); err != nil {
+			return err
+		}
+	}
+	if err := chunks.push(opts.tableStart()); err != nil {
+		return err
+	}
+	rowElement := opts.rowStart()
+	watchers.startTable(sheet)
+	rowErr := eachDataRow(ctx, reader, func(rowNumber int, dataRow DataRow) error {
+		opts.Xml.shapeRow(&dataRow)
+		watchers.observe(rowNumber, dataRow)
+		return chunks.writeRow(sheet, rowNumber, dataRow, rowElement)
+	})
+	if rowErr != nil {
+		return rowErr
+	}
+	if err := chunks.pop(); err != nil {
+		return err
+	}
+	if opts.Combine {
+		return chunks.pop()
+	}
+	return nil
+}
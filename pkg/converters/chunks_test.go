@@ -0,0 +1,148 @@
+package converters
+
+import (
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"encoding/xml"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestXlsxToXmlFilesChunks(t *testing.T) {
+	filePath := createTestWorkbook(t, map[string][][]any{
+		"Orders": {{"Id"}, {1}, {2}, {3}, {4}, {5}},
+	}, "Orders")
+	dir := t.TempDir()
+	written, err := XlsxToXmlFiles(context.Background(), dir, filePath, Options{ChunkSize: 2})
+	if err != nil {
+		t.Fatalf("XlsxToXmlFiles() error = %v", err)
+	}
+	wantFiles := []string{"Orders_0001.xml", "Orders_0002.xml", "Orders_0003.xml", "Orders_manifest.xml"}
+	for index, want := range wantFiles {
+		wantFiles[index] = filepath.Join(dir, want)
+	}
+	if !reflect.DeepEqual(written, wantFiles) {
+		t.Fatalf("XlsxToXmlFiles() written = %v, want %v", written, wantFiles)
+	}
+
+	var rows int
+	for _, path := range written[:3] {
+		content, readErr := os.ReadFile(path)
+		if readErr != nil {
+			t.Fatalf("Error reading chunk: %v", readErr)
+		}
+		var chunk DataTable
+		if unmarshalErr := xml.Unmarshal(content, &chunk); unmarshalErr != nil {
+			t.Fatalf("Chunk %s is not a standalone document: %v", path, unmarshalErr)
+		}
+		rows += len(chunk.Rows)
+	}
+	if rows != 5 {
+		t.Errorf("Chunks hold %d rows, want 5", rows)
+	}
+
+	content, readErr := os.ReadFile(written[3])
+	if readErr != nil {
+		t.Fatalf("Error reading manifest: %v", readErr)
+	}
+	var manifest Manifest
+	if unmarshalErr := xml.Unmarshal(content, &manifest); unmarshalErr != nil {
+		t.Fatalf("Error parsing manifest: %v", unmarshalErr)
+	}
+	want := []ManifestChunk{
+		{File: "Orders_0001.xml", Rows: 2, Ranges: []ManifestRange{{Sheet: "Orders", FirstRow: 2, LastRow: 3}}},
+		{File: "Orders_0002.xml", Rows: 2, Ranges: []ManifestRange{{Sheet: "Orders", FirstRow: 4, LastRow: 5}}},
+		{File: "Orders_0003.xml", Rows: 1, Ranges: []ManifestRange{{Sheet: "Orders", FirstRow: 6, LastRow: 6}}},
+	}
+	if manifest.Rows != 5 || !reflect.DeepEqual(manifest.Chunks, want) {
+		t.Errorf("Manifest = %+v, want 5 rows in %+v", manifest, want)
+	}
+}
+
+func TestXlsxToXmlFilesCompression(t *testing.T) {
+	filePath := createTestWorkbook(t, map[string][][]any{
+		"Orders": {{"Id"}, {1}, {2}, {3}},
+	}, "Orders")
+
+	t.Run("Gzip", func(t *testing.T) {
+		dir := t.TempDir()
+		written, err := XlsxToXmlFiles(context.Background(), dir, filePath, Options{ChunkSize: 2, Compression: GzipCompression})
+		if err != nil {
+			t.Fatalf("XlsxToXmlFiles() error = %v", err)
+		}
+		wantFiles := []string{"Orders_0001.xml.gz", "Orders_0002.xml.gz", "Orders_manifest.xml.gz"}
+		for index, want := range wantFiles {
+			wantFiles[index] = filepath.Join(dir, want)
+		}
+		if !reflect.DeepEqual(written, wantFiles) {
+			t.Fatalf("XlsxToXmlFiles() written = %v, want %v", written, wantFiles)
+		}
+		outFile, openErr := os.Open(written[2])
+		if openErr != nil {
+			t.Fatalf("Error opening manifest: %v", openErr)
+		}
+		defer outFile.Close()
+		reader, gzipErr := gzip.NewReader(outFile)
+		if gzipErr != nil {
+			t.Fatalf("Manifest is not a gzip stream: %v", gzipErr)
+		}
+		var manifest Manifest
+		if decodeErr := xml.NewDecoder(reader).Decode(&manifest); decodeErr != nil {
+			t.Fatalf("Error parsing manifest: %v", decodeErr)
+		}
+		if len(manifest.Chunks) != 2 || manifest.Chunks[0].File != "Orders_0001.xml.gz" {
+			t.Errorf("Manifest chunks = %+v, want Orders_0001.xml.gz and Orders_0002.xml.gz", manifest.Chunks)
+		}
+	})
+
+	t.Run("Zip", func(t *testing.T) {
+		dir := t.TempDir()
+		written, err := XlsxToXmlFiles(context.Background(), dir, filePath, Options{ChunkSize: 2, Compression: ZipCompression})
+		if err != nil {
+			t.Fatalf("XlsxToXmlFiles() error = %v", err)
+		}
+		if want := []string{filepath.Join(dir, "TestWorkbook.zip")}; !reflect.DeepEqual(written, want) {
+			t.Fatalf("XlsxToXmlFiles() written = %v, want %v", written, want)
+		}
+		archive, openErr := zip.OpenReader(written[0])
+		if openErr != nil {
+			t.Fatalf("Error opening archive: %v", openErr)
+		}
+		defer archive.Close()
+		var names []string
+		var rows int
+		for _, entry := range archive.File {
+			names = append(names, entry.Name)
+			content, readErr := readZipEntry(entry)
+			if readErr != nil {
+				t.Fatalf("Error reading %s: %v", entry.Name, readErr)
+			}
+			if entry.Name == "Orders_manifest.xml" {
+				continue
+			}
+			var chunk DataTable
+			if unmarshalErr := xml.Unmarshal(content, &chunk); unmarshalErr != nil {
+				t.Fatalf("Chunk %s is not a standalone document: %v", entry.Name, unmarshalErr)
+			}
+			rows += len(chunk.Rows)
+		}
+		wantNames := []string{"Orders_0001.xml", "Orders_0002.xml", "Orders_manifest.xml"}
+		if !reflect.DeepEqual(names, wantNames) || rows != 3 {
+			t.Errorf("Archive holds %v with %d rows, want %v with 3 rows", names, rows, wantNames)
+		}
+	})
+}
+
+// readZipEntry returns the content of an archive entry.
+func readZipEntry(entry *zip.File) ([]byte, error) {
+	reader, openErr := entry.Open()
+	if openErr != nil {
+		return nil, openErr
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
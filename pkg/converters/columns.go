@@ -0,0 +1,124 @@
+package converters
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// ColumnFilter selects the columns of a worksheet by the name in their header cell, before any cleanup.
+// Include keeps only the listed columns and Exclude drops the listed columns.
+// Pattern, when set, keeps only the columns whose header matches it.
+// The conditions combine, so a column must pass all of them to be kept.
+// The zero value keeps every column.
+type ColumnFilter struct {
+	Include []string
+	Exclude []string
+	Pattern *regexp.Regexp
+}
+
+// active reports whether the filter drops any column at all.
+func (f ColumnFilter) active() bool {
+	return len(f.Include) > 0 || len(f.Exclude) > 0 || f.Pattern != nil
+}
+
+// keep reports whether the column with the given header passes the filter.
+func (f ColumnFilter) keep(header string) bool {
+	if len(f.Include) > 0 && !slices.Contains(f.Include, header) {
+		return false
+	}
+	if slices.Contains(f.Exclude, header) {
+		return false
+	}
+	return f.Pattern == nil || f.Pattern.MatchString(header)
+}
+
+// indexes returns the positions of the header columns that pass the filter.
+func (f ColumnFilter) indexes(header []string) []int {
+	var kept []int
+	for index, name := range header {
+		if f.keep(name) {
+			kept = append(kept, index)
+		}
+	}
+	return kept
+}
+
+// ColumnOrderMode selects the order the columns of a worksheet are written in.
+type ColumnOrderMode int
+
+const (
+	// SheetOrder keeps the columns in the order they appear in the worksheet. It is the default.
+	SheetOrder ColumnOrderMode = iota
+	// AlphabeticalOrder sorts the columns by the name in their header cell, ignoring case.
+	AlphabeticalOrder
+	// ExplicitOrder writes the columns named in ColumnOrder.Names first, in that order,
+	// followed by every other column in sheet order. Names without a matching column are ignored.
+	ExplicitOrder
+)
+
+// ColumnOrder orders the columns of a worksheet by the name in their header cell, before any cleanup.
+// The zero value keeps the sheet order.
+type ColumnOrder struct {
+	Mode  ColumnOrderMode
+	Names []string
+}
+
+// ParseColumnOrder returns the ColumnOrder called sheet or alphabetical, or the explicit order of the
+// comma separated column names following "names:".
+func ParseColumnOrder(order string) (ColumnOrder, error) {
+	switch {
+	case order == "" || order == "sheet":
+		return ColumnOrder{}, nil
+	case order == "alphabetical":
+		return ColumnOrder{Mode: AlphabeticalOrder}, nil
+	case strings.HasPrefix(order, "names:"):
+		return ColumnOrder{Mode: ExplicitOrder, Names: strings.Split(strings.TrimPrefix(order, "names:"), ",")}, nil
+	}
+	return ColumnOrder{}, fmt.Errorf("unknown column order '%s'", order)
+}
+
+// sort reorders positions, which index into header, according to the order.
+func (o ColumnOrder) sort(header []string, positions []int) []int {
+	sorted := slices.Clone(positions)
+	switch o.Mode {
+	case AlphabeticalOrder:
+		slices.SortStableFunc(sorted, func(a, b int) int {
+			return strings.Compare(strings.ToLower(header[a]), strings.ToLower(header[b]))
+		})
+	case ExplicitOrder:
+		rank := func(position int) int {
+			if index := slices.Index(o.Names, header[position]); index >= 0 {
+				return index
+			}
+			return len(o.Names)
+		}
+		slices.SortStableFunc(sorted, func(a, b int) int {
+			return rank(a) - rank(b)
+		})
+	}
+	return sorted
+}
+
+// project returns the cells of columns at the given positions, blank cells standing in for missing trailing cells.
+func project(columns []string, indexes []int) []string {
+	projected := make([]string, len(indexes))
+	for position, index := range indexes {
+		if index < len(columns) {
+			projected[position] = columns[index]
+		}
+	}
+	return projected
+}
+
+// projectIndexes keeps the entries of indexes at the given positions, in order.
+func projectIndexes(indexes, positions []int) []int {
+	projected := make([]int, 0, len(positions))
+	for _, position := range positions {
+		if position < len(indexes) {
+			projected = append(projected, indexes[position])
+		}
+	}
+	return projected
+}
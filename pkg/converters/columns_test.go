@@ -0,0 +1,40 @@
+package converters
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestColumnFilter(t *testing.T) {
+	header := []string{"Id", "Name", "City", "Notes"}
+	tests := []struct {
+		name       string
+		filter     ColumnFilter
+		wantActive bool
+		want       []int
+	}{
+		{name: "Zero Value", filter: ColumnFilter{}, want: []int{0, 1, 2, 3}},
+		{name: "Include", filter: ColumnFilter{Include: []string{"Name", "Id"}}, wantActive: true, want: []int{0, 1}},
+		{name: "Exclude", filter: ColumnFilter{Exclude: []string{"City"}}, wantActive: true, want: []int{0, 1, 3}},
+		{name: "Pattern", filter: ColumnFilter{Pattern: regexp.MustCompile(`^N`)}, wantActive: true, want: []int{1, 3}},
+		{
+			name:       "Combined",
+			filter:     ColumnFilter{Include: []string{"Id", "Name", "Notes"}, Exclude: []string{"Id"}, Pattern: regexp.MustCompile(`e`)},
+			wantActive: true,
+			want:       []int{1, 3},
+		},
+		{name: "Unknown Include Name", filter: ColumnFilter{Include: []string{"Name", "Country"}}, wantActive: true, want: []int{1}},
+		{name: "Only Unknown Include Names", filter: ColumnFilter{Include: []string{"Country"}}, wantActive: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.active(); got != tt.wantActive {
+				t.Errorf("active() = %v, want %v", got, tt.wantActive)
+			}
+			if got := tt.filter.indexes(header); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("indexes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package converters
+
+import (
+	"encoding/xml"
+	"fmt"
+)
+
+// CommentMode selects whether and how the comments, or notes, attached to cells are written.
+type CommentMode int
+
+const (
+	// NoComments leaves cell comments out.
+	NoComments CommentMode = iota
+	// CommentElements writes the comments of a column into a sibling element named after it with a "_comment"
+	// suffix (<Name_comment>...</Name_comment>), or a sibling attribute in attribute mode. The sibling is written
+	// on every row, empty for cells without a comment, for every column holding at least one comment below the header.
+	CommentElements
+	// CommentAttributes writes the comment of a cell into a comment attribute of its column element
+	// (<Name comment="...">...</Name>). It cannot be combined with attribute mode.
+	CommentAttributes
+)
+
+// commentSuffix is appended to the name of a column to name the sibling holding its comments.
+const commentSuffix = "_comment"
+
+// ParseCommentMode returns the CommentMode called none, elements or attributes.
+func ParseCommentMode(mode string) (CommentMode, error) {
+	switch mode {
+	case "", "none":
+		return NoComments, nil
+	case "elements":
+		return CommentElements, nil
+	case "attributes":
+		return CommentAttributes, nil
+	}
+	return NoComments, fmt.Errorf("unknown comment mode '%s'", mode)
+}
+
+// writeComments adds the comments of the cells in the row as configured by the CommentMode.
+func (o XmlOptions) writeComments(dataRow *DataRow) {
+	if o.Comments == CommentAttributes {
+		for columnIndex, column := range dataRow.Columns {
+			if len(column.comment) > 0 {
+				dataRow.Columns[columnIndex].Attributes = append(column.Attributes,
+					xml.Attr{Name: xml.Name{Local: "comment"}, Value: column.comment})
+			}
+		}
+		return
+	}
+	columns := make([]DataColumn, 0, len(dataRow.Columns))
+	for _, column := range dataRow.Columns {
+		columns = append(columns, column)
+		if column.commented {
+			columns = append(columns, DataColumn{
+				XMLName: xml.Name{Local: column.XMLName.Local + commentSuffix},
+				Value:   column.comment,
+			})
+		}
+	}
+	dataRow.Columns = columns
+}
@@ -0,0 +1,353 @@
+package converters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// sheetCopier copies a worksheet between workbooks, registering every style of the source workbook it meets
+// with the target workbook once.
+type sheetCopier struct {
+	src, dst           *excelize.File
+	srcSheet, dstSheet string
+	styles             map[int]int
+	conditionalStyles  map[int]int
+}
+
+// CopySheet copies the worksheet called srcSheet of src into the worksheet called dstSheet of dst, which is
+// created when missing and otherwise overwritten cell by cell. The copy keeps the values and their types,
+// formulas, rich text, hyperlinks, cell, row and column styles, column widths, row heights, hidden rows and
+// columns, merged cells, frozen panes, conditional formats, data validations and the tab color; images, charts,
+// comments and tables are not copied. Formulas are copied as written, so those referring to other worksheets
+// refer to the worksheets of dst with the same names.
+// The copy stops with the error of ctx as soon as it is cancelled or its deadline passes.
+func CopySheet(ctx context.Context, dst *excelize.File, dstSheet string, src *excelize.File, srcSheet string) error {
+	if index, _ := src.GetSheetIndex(srcSheet); index < 0 {
+		return fmt.Errorf("no worksheet named '%s'", srcSheet)
+	}
+	if index, _ := dst.GetSheetIndex(dstSheet); index < 0 {
+		if _, err := dst.NewSheet(dstSheet); err != nil {
+			return err
+		}
+	}
+	copier := sheetCopier{src: src, dst: dst, srcSheet: srcSheet, dstSheet: dstSheet,
+		styles: map[int]int{0: 0}, conditionalStyles: make(map[int]int)}
+	rows, rowsErr := copier.readRows()
+	if rowsErr != nil {
+		return rowsErr
+	}
+	width, height := copier.extent(rows)
+	if err := copier.copyColumns(width); err != nil {
+		return err
+	}
+	for rowNumber := 1; rowNumber <= height; rowNumber++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := copier.copyRow(rowNumber, width, cellsAt(rows, rowNumber-1)); err != nil {
+			return err
+		}
+	}
+	return copier.copyLayout()
+}
+
+// CopySheetFile copies the worksheet called srcSheet of the workbook at srcPath into the worksheet called
+// dstSheet of the workbook at dstPath as CopySheet does, creating the workbook when it does not exist.
+// An empty dstSheet names the copy after the source worksheet.
+func CopySheetFile(ctx context.Context, dstPath, dstSheet, srcPath, srcSheet string) (copyErr error) {
+	src, openErr := excelize.OpenFile(srcPath)
+	if openErr != nil {
+		return openErr
+	}
+	defer func(file *excelize.File) {
+		if err := file.Close(); err != nil && copyErr == nil {
+			copyErr = err
+		}
+	}(src)
+	if len(dstSheet) < 1 {
+		dstSheet = srcSheet
+	}
+	dst, dstErr := excelize.OpenFile(dstPath)
+	created := false
+	if dstErr != nil {
+		if _, statErr := os.Stat(dstPath); !errors.Is(statErr, fs.ErrNotExist) {
+			return dstErr
+		}
+		dst, created = excelize.NewFile(), true
+	}
+	defer func(file *excelize.File) {
+		if err := file.Close(); err != nil && copyErr == nil {
+			copyErr = err
+		}
+	}(dst)
+	if created {
+		if err := createSheet(dst, 0, dstSheet); err != nil {
+			return err
+		}
+	}
+	if err := CopySheet(ctx, dst, dstSheet, src, srcSheet); err != nil {
+		return err
+	}
+	return dst.SaveAs(dstPath)
+}
+
+// readRows returns the raw values of the rows of the source worksheet, including the rows only holding styled
+// cells, which GetRows leaves out at the end of the worksheet.
+func (c sheetCopier) readRows() (values [][]string, readErr error) {
+	rows, rowsErr := c.src.Rows(c.srcSheet)
+	if rowsErr != nil {
+		return nil, rowsErr
+	}
+	defer func(rows *excelize.Rows) {
+		if err := rows.Close(); err != nil && readErr == nil {
+			readErr = err
+		}
+	}(rows)
+	for rows.Next() {
+		columns, err := rows.Columns(excelize.Options{RawCellValue: true})
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, columns)
+	}
+	return values, rows.Error()
+}
+
+// cellsAt returns the row at index, nil past the last row.
+func cellsAt(rows [][]string, index int) []string {
+	if index < len(rows) {
+		return rows[index]
+	}
+	return nil
+}
+
+// extent returns the number of columns and rows to copy: those of the rows, or covered by merged cells or by the
+// dimension of the worksheet, which spans the styled cells without values of the workbooks Excel saves.
+func (c sheetCopier) extent(rows [][]string) (width, height int) {
+	height = len(rows)
+	for _, row := range rows {
+		width = max(width, len(row))
+	}
+	var ranges []string
+	if dimension, err := c.src.GetSheetDimension(c.srcSheet); err == nil && len(dimension) > 0 {
+		ranges = append(ranges, dimension)
+	}
+	if merged, err := c.src.GetMergeCells(c.srcSheet); err == nil {
+		for _, cell := range merged {
+			ranges = append(ranges, cell.GetEndAxis())
+		}
+	}
+	for _, ref := range ranges {
+		last := ref[strings.LastIndex(ref, ":")+1:]
+		if column, row, err := excelize.CellNameToCoordinates(last); err == nil {
+			width, height = max(width, column), max(height, row)
+		}
+	}
+	return width, height
+}
+
+// style returns the style of dst matching the style srcStyle of src.
+func (c sheetCopier) style(srcStyle int) (int, error) {
+	if dstStyle, known := c.styles[srcStyle]; known {
+		return dstStyle, nil
+	}
+	style, styleErr := c.src.GetStyle(srcStyle)
+	if styleErr != nil {
+		return 0, styleErr
+	}
+	dstStyle, newErr := c.dst.NewStyle(style)
+	if newErr != nil {
+		return 0, newErr
+	}
+	c.styles[srcStyle] = dstStyle
+	return dstStyle, nil
+}
+
+// copyColumns copies the widths, the visibility and the styles of the first width columns.
+func (c sheetCopier) copyColumns(width int) error {
+	for columnNumber := 1; columnNumber <= width; columnNumber++ {
+		column, _ := excelize.ColumnNumberToName(columnNumber)
+		columnWidth, widthErr := c.src.GetColWidth(c.srcSheet, column)
+		if widthErr != nil {
+			return widthErr
+		}
+		if err := c.dst.SetColWidth(c.dstSheet, column, column, columnWidth); err != nil {
+			return err
+		}
+		if visible, _ := c.src.GetColVisible(c.srcSheet, column); !visible {
+			if err := c.dst.SetColVisible(c.dstSheet, column, false); err != nil {
+				return err
+			}
+		}
+		if srcStyle, _ := c.src.GetColStyle(c.srcSheet, column); srcStyle != 0 {
+			dstStyle, styleErr := c.style(srcStyle)
+			if styleErr != nil {
+				return styleErr
+			}
+			if err := c.dst.SetColStyle(c.dstSheet, column, dstStyle); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// copyRow copies the height and the visibility of the row at rowNumber and its first width cells,
+// whose raw values are values.
+func (c sheetCopier) copyRow(rowNumber, width int, values []string) error {
+	if rowHeight, err := c.src.GetRowHeight(c.srcSheet, rowNumber); err == nil && rowHeight != c.defaultRowHeight() {
+		if err := c.dst.SetRowHeight(c.dstSheet, rowNumber, rowHeight); err != nil {
+			return err
+		}
+	}
+	if visible, _ := c.src.GetRowVisible(c.srcSheet, rowNumber); !visible {
+		if err := c.dst.SetRowVisible(c.dstSheet, rowNumber, false); err != nil {
+			return err
+		}
+	}
+	for columnNumber := 1; columnNumber <= width; columnNumber++ {
+		cell, _ := excelize.CoordinatesToCellName(columnNumber, rowNumber)
+		if err := c.copyCell(cell, cellAt(values, columnNumber-1)); err != nil {
+			return fmt.Errorf("cell %s: %w", cell, err)
+		}
+	}
+	return nil
+}
+
+// defaultRowHeight returns the height of the rows of the source worksheet without a height of their own.
+func (c sheetCopier) defaultRowHeight() float64 {
+	if props, err := c.src.GetSheetProps(c.srcSheet); err == nil && props.DefaultRowHeight != nil {
+		return *props.DefaultRowHeight
+	}
+	return 15
+}
+
+// copyCell copies the raw value or the formula, the hyperlink and the style of a cell. The value is not read
+// with GetCellValue, which returns the value of a merged range for every cell it covers.
+func (c sheetCopier) copyCell(cell, value string) error {
+	formula, _ := c.src.GetCellFormula(c.srcSheet, cell)
+	cellType, typeErr := c.src.GetCellType(c.srcSheet, cell)
+	if typeErr != nil {
+		return typeErr
+	}
+	runs, _ := c.src.GetCellRichText(c.srcSheet, cell)
+	var setErr error
+	switch {
+	case len(formula) > 0:
+		setErr = c.dst.SetCellFormula(c.dstSheet, cell, formula)
+	case len(runs) > 1 || len(runs) == 1 && runs[0].Font != nil:
+		setErr = c.dst.SetCellRichText(c.dstSheet, cell, runs)
+	case len(value) < 1:
+	case cellType == excelize.CellTypeBool:
+		setErr = c.dst.SetCellBool(c.dstSheet, cell, value == "1" || strings.EqualFold(value, "true"))
+	case cellType == excelize.CellTypeSharedString || cellType == excelize.CellTypeInlineString || cellType == excelize.CellTypeError:
+		setErr = c.dst.SetCellStr(c.dstSheet, cell, value)
+	default:
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			setErr = c.dst.SetCellStr(c.dstSheet, cell, value)
+		} else {
+			setErr = c.dst.SetCellDefault(c.dstSheet, cell, value)
+		}
+	}
+	if setErr != nil {
+		return setErr
+	}
+	if linked, target, _ := c.src.GetCellHyperLink(c.srcSheet, cell); linked && len(target) > 0 {
+		linkType := "Location"
+		if strings.Contains(target, "://") || strings.HasPrefix(target, "mailto:") {
+			linkType = "External"
+		}
+		if err := c.dst.SetCellHyperLink(c.dstSheet, cell, target, linkType); err != nil {
+			return err
+		}
+	}
+	srcStyle, styleErr := c.src.GetCellStyle(c.srcSheet, cell)
+	if styleErr != nil || srcStyle == 0 {
+		return styleErr
+	}
+	dstStyle, mapErr := c.style(srcStyle)
+	if mapErr != nil {
+		return mapErr
+	}
+	return c.dst.SetCellStyle(c.dstSheet, cell, cell, dstStyle)
+}
+
+// copyLayout copies the merged cells, the panes, the conditional formats, the data validations
+// and the tab color of the worksheet.
+func (c sheetCopier) copyLayout() error {
+	merged, mergedErr := c.src.GetMergeCells(c.srcSheet)
+	if mergedErr != nil {
+		return mergedErr
+	}
+	for _, cell := range merged {
+		if err := c.dst.MergeCell(c.dstSheet, cell.GetStartAxis(), cell.GetEndAxis()); err != nil {
+			return err
+		}
+	}
+	if panes, err := c.src.GetPanes(c.srcSheet); err == nil && (panes.Freeze || panes.Split) {
+		if err := c.dst.SetPanes(c.dstSheet, &panes); err != nil {
+			return err
+		}
+	}
+	formats, formatsErr := c.src.GetConditionalFormats(c.srcSheet)
+	if formatsErr != nil {
+		return formatsErr
+	}
+	for ref, rules := range formats {
+		for index := range rules {
+			switch rules[index].Type {
+			case "2_color_scale", "3_color_scale", "data_bar", "icon_set":
+				// These rules draw the cells themselves instead of styling them.
+				continue
+			}
+			format, formatErr := c.conditionalStyle(rules[index].Format)
+			if formatErr != nil {
+				return formatErr
+			}
+			rules[index].Format = format
+		}
+		if err := c.dst.SetConditionalFormat(c.dstSheet, ref, rules); err != nil {
+			return err
+		}
+	}
+	validations, validationsErr := c.src.GetDataValidations(c.srcSheet)
+	if validationsErr != nil {
+		return validationsErr
+	}
+	for _, validation := range validations {
+		if err := c.dst.AddDataValidation(c.dstSheet, validation); err != nil {
+			return err
+		}
+	}
+	props, propsErr := c.src.GetSheetProps(c.srcSheet)
+	if propsErr != nil {
+		return propsErr
+	}
+	return c.dst.SetSheetProps(c.dstSheet, &excelize.SheetPropsOptions{TabColorRGB: props.TabColorRGB,
+		TabColorTheme: props.TabColorTheme, TabColorIndexed: props.TabColorIndexed, TabColorTint: props.TabColorTint,
+		DefaultRowHeight: props.DefaultRowHeight, DefaultColWidth: props.DefaultColWidth})
+}
+
+// conditionalStyle returns the conditional format style of dst matching the style srcStyle of src.
+func (c sheetCopier) conditionalStyle(srcStyle int) (int, error) {
+	if dstStyle, known := c.conditionalStyles[srcStyle]; known {
+		return dstStyle, nil
+	}
+	style, styleErr := c.src.GetConditionalStyle(srcStyle)
+	if styleErr != nil {
+		return 0, styleErr
+	}
+	dstStyle, newErr := c.dst.NewConditionalStyle(style)
+	if newErr != nil {
+		return 0, newErr
+	}
+	c.conditionalStyles[srcStyle] = dstStyle
+	return dstStyle, nil
+}
@@ -0,0 +1,168 @@
+package converters
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// createStyledWorkbook saves a worksheet called Report holding styled, merged, hidden and formula cells.
+func createStyledWorkbook(t *testing.T) string {
+	t.Helper()
+	file := excelize.NewFile()
+	defer func() {
+		_ = file.Close()
+	}()
+	sheet := "Report"
+	if err := file.SetSheetName("Sheet1", sheet); err != nil {
+		t.Fatalf("Error renaming test sheet: %v", err)
+	}
+	steps := []func() error{
+		func() error { return file.SetCellStr(sheet, "A1", "Quarterly report") },
+		func() error { return file.MergeCell(sheet, "A1", "C1") },
+		func() error { return file.SetSheetRow(sheet, "A2", &[]any{"Item", "Qty", "Shipped"}) },
+		func() error { return file.SetSheetRow(sheet, "A3", &[]any{"Bolt", 3, true}) },
+		func() error { return file.SetSheetRow(sheet, "A4", &[]any{"Nut", 12, false}) },
+		func() error { return file.SetCellFormula(sheet, "B5", "SUM(B3:B4)") },
+		func() error { return file.SetCellValue(sheet, "D3", "00123") },
+		func() error { return file.SetColWidth(sheet, "A", "A", 24) },
+		func() error { return file.SetRowHeight(sheet, 1, 30) },
+		func() error { return file.SetRowVisible(sheet, 4, false) },
+		func() error {
+			return file.SetPanes(sheet, &excelize.Panes{Freeze: true, YSplit: 2, TopLeftCell: "A3", ActivePane: "bottomLeft"})
+		},
+		func() error {
+			return file.SetSheetProps(sheet, &excelize.SheetPropsOptions{TabColorRGB: stringPointer("FF0000")})
+		},
+	}
+	for _, step := range steps {
+		if err := step(); err != nil {
+			t.Fatalf("Error building the test workbook: %v", err)
+		}
+	}
+	header, _ := file.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true, Color: "FFFFFF"},
+		Fill: excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"1F4E78"}}})
+	if err := file.SetCellStyle(sheet, "A2", "C2", header); err != nil {
+		t.Fatalf("Error styling the test workbook: %v", err)
+	}
+	border, _ := file.NewStyle(&excelize.Style{Border: []excelize.Border{{Type: "bottom", Color: "000000", Style: 1}}})
+	if err := file.SetCellStyle(sheet, "D6", "D6", border); err != nil {
+		t.Fatalf("Error styling the test workbook: %v", err)
+	}
+	highlight, _ := file.NewConditionalStyle(&excelize.Style{Fill: excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"FFC7CE"}}})
+	rule := []excelize.ConditionalFormatOptions{{Type: "cell", Criteria: ">", Value: "10", Format: highlight}}
+	if err := file.SetConditionalFormat(sheet, "B3:B4", rule); err != nil {
+		t.Fatalf("Error adding the test conditional format: %v", err)
+	}
+	validation := excelize.NewDataValidation(true)
+	validation.Sqref = "C3:C4"
+	if err := validation.SetDropList([]string{"TRUE", "FALSE"}); err != nil {
+		t.Fatalf("Error building the test validation: %v", err)
+	}
+	if err := file.AddDataValidation(sheet, validation); err != nil {
+		t.Fatalf("Error adding the test validation: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "styled.xlsx")
+	if err := file.SaveAs(path); err != nil {
+		t.Fatalf("Error saving the test workbook: %v", err)
+	}
+	return path
+}
+
+// stringPointer returns a pointer to value.
+func stringPointer(value string) *string {
+	return &value
+}
+
+func TestCopySheetFile(t *testing.T) {
+	srcPath := createStyledWorkbook(t)
+	existing := createTestWorkbook(t, map[string][][]any{"Data": {{"Id"}, {1}}}, "Data")
+	tests := []struct {
+		name       string
+		dstPath    string
+		dstSheet   string
+		wantSheets []string
+	}{
+		{name: "New Workbook", dstPath: filepath.Join(t.TempDir(), "copy.xlsx"), wantSheets: []string{"Report"}},
+		{name: "Existing Workbook", dstPath: existing, dstSheet: "Copy", wantSheets: []string{"Data", "Copy"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := CopySheetFile(context.Background(), tt.dstPath, tt.dstSheet, srcPath, "Report"); err != nil {
+				t.Fatalf("CopySheetFile() error = %v", err)
+			}
+			file, openErr := excelize.OpenFile(tt.dstPath)
+			if openErr != nil {
+				t.Fatalf("Error opening the copy: %v", openErr)
+			}
+			defer func() {
+				_ = file.Close()
+			}()
+			if sheets := file.GetSheetList(); !reflect.DeepEqual(sheets, tt.wantSheets) {
+				t.Fatalf("sheets = %v, want %v", sheets, tt.wantSheets)
+			}
+			sheet := tt.wantSheets[len(tt.wantSheets)-1]
+			rows, _ := file.GetRows(sheet)
+			want := [][]string{{"Quarterly report"}, {"Item", "Qty", "Shipped"}, {"Bolt", "3", "TRUE", "00123"}, {"Nut", "12", "FALSE"}}
+			if !reflect.DeepEqual(rows[:4], want) {
+				t.Errorf("rows = %v, want %v", rows[:4], want)
+			}
+			types := map[string]excelize.CellType{}
+			for _, cell := range []string{"B3", "C3", "D3"} {
+				types[cell], _ = file.GetCellType(sheet, cell)
+			}
+			if types["B3"] != excelize.CellTypeUnset || types["C3"] != excelize.CellTypeBool || types["D3"] == excelize.CellTypeUnset {
+				t.Errorf("cell types = %v, want a number, a boolean and a string", types)
+			}
+			if formula, _ := file.GetCellFormula(sheet, "B5"); formula != "SUM(B3:B4)" {
+				t.Errorf("B5 formula = %s, want SUM(B3:B4)", formula)
+			}
+			styleID, _ := file.GetCellStyle(sheet, "B2")
+			style, _ := file.GetStyle(styleID)
+			if style == nil || !style.Font.Bold || style.Fill.Color[0] != "1F4E78" {
+				t.Errorf("B2 style = %+v, want the bold header style", style)
+			}
+			if borderID, _ := file.GetCellStyle(sheet, "D6"); borderID == 0 {
+				t.Errorf("D6 lost its border")
+			}
+			if merged, _ := file.GetMergeCells(sheet); len(merged) != 1 || merged[0].GetStartAxis() != "A1" || merged[0].GetEndAxis() != "C1" {
+				t.Errorf("merged cells = %v, want A1:C1", merged)
+			}
+			if width, _ := file.GetColWidth(sheet, "A"); width != 24 {
+				t.Errorf("width of A = %g, want 24", width)
+			}
+			if height, _ := file.GetRowHeight(sheet, 1); height != 30 {
+				t.Errorf("height of row 1 = %g, want 30", height)
+			}
+			if visible, _ := file.GetRowVisible(sheet, 4); visible {
+				t.Errorf("row 4 is visible, want it hidden")
+			}
+			if panes, _ := file.GetPanes(sheet); !panes.Freeze || panes.YSplit != 2 {
+				t.Errorf("panes = %+v, want two frozen rows", panes)
+			}
+			formats, _ := file.GetConditionalFormats(sheet)
+			if rules := formats["B3:B4"]; len(rules) != 1 || rules[0].Value != "10" {
+				t.Errorf("conditional formats = %+v, want the rule on B3:B4", formats)
+			} else if highlight, _ := file.GetConditionalStyle(rules[0].Format); highlight == nil || highlight.Fill.Color[0] != "FFC7CE" {
+				t.Errorf("conditional style = %+v, want the FFC7CE fill", highlight)
+			}
+			if validations, _ := file.GetDataValidations(sheet); len(validations) != 1 || validations[0].Sqref != "C3:C4" {
+				t.Errorf("data validations = %+v, want the drop list on C3:C4", validations)
+			}
+			if props, _ := file.GetSheetProps(sheet); props.TabColorRGB == nil || *props.TabColorRGB != "FF0000" {
+				t.Errorf("tab color = %v, want FF0000", props.TabColorRGB)
+			}
+		})
+	}
+}
+
+func TestCopySheetUnknown(t *testing.T) {
+	srcPath := createStyledWorkbook(t)
+	err := CopySheetFile(context.Background(), filepath.Join(t.TempDir(), "copy.xlsx"), "", srcPath, "Missing")
+	if err == nil || err.Error() != "no worksheet named 'Missing'" {
+		t.Errorf("CopySheetFile() error = %v, want no worksheet named 'Missing'", err)
+	}
+}
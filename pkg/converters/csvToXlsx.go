@@ -0,0 +1,181 @@
+package converters
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/xuri/excelize/v2"
+	"golang.org/x/text/transform"
+)
+
+// CsvToXlsxOptions configures building a workbook from CSV files.
+// Delimiter separates the fields, a comma by default, and Encoding names the encoding of the files as in CsvOptions;
+// a leading UTF-8 byte order mark is skipped.
+// SheetNames names the worksheet of each file in order; files without a name get a worksheet named after the file,
+// stripped of the characters Excel does not allow and shortened to 31 characters.
+// The first record of every file is its header row, unless NoHeader is set. StyleHeader writes it in bold on
+// a grey background and freezes it above the records.
+// AutoWidth sizes every column to its longest value, which reads every file one extra time.
+// CoerceTypes writes numbers, booleans and dates as such instead of as text, dates being recognised as in the
+// XML conversion; integers with more digits than Excel keeps, such as account numbers, and values with leading
+// zeros stay text.
+type CsvToXlsxOptions struct {
+	Delimiter   rune
+	Encoding    string
+	SheetNames  []string
+	NoHeader    bool
+	StyleHeader bool
+	AutoWidth   bool
+	CoerceTypes bool
+}
+
+// validate reports options that cannot read the files.
+func (o CsvToXlsxOptions) validate(paths []string) error {
+	if len(paths) < 1 {
+		return errors.New("no CSV file to convert")
+	}
+	if len(o.SheetNames) > len(paths) {
+		return fmt.Errorf("%d worksheet names given for %d CSV files", len(o.SheetNames), len(paths))
+	}
+	if o.Delimiter == '"' || o.Delimiter == '\r' || o.Delimiter == '\n' || o.Delimiter == utf8.RuneError {
+		return fmt.Errorf("invalid delimiter %q", o.Delimiter)
+	}
+	_, encodingErr := csvEncoding(o.Encoding)
+	return encodingErr
+}
+
+// CsvToXlsx builds the .xlsx workbook at outPath holding a worksheet per CSV file at paths, in order.
+// The records are streamed into the workbook, so the files are never held in memory.
+// The conversion stops with the error of ctx as soon as it is cancelled or its deadline passes.
+func CsvToXlsx(ctx context.Context, outPath string, paths []string, opts CsvToXlsxOptions) (convertErr error) {
+	if optsErr := opts.validate(paths); optsErr != nil {
+		return optsErr
+	}
+	file := excelize.NewFile()
+	defer func(file *excelize.File) {
+		if err := file.Close(); err != nil && convertErr == nil {
+			convertErr = err
+		}
+	}(file)
+
+	styles, stylesErr := newWorkbookStyles(file)
+	if stylesErr != nil {
+		return stylesErr
+	}
+	used := make(map[string]bool, len(paths))
+	for index, path := range paths {
+		name := sheetNameFor(path, index, used)
+		if index < len(opts.SheetNames) {
+			name = opts.SheetNames[index]
+		}
+		used[strings.ToLower(name)] = true
+		if err := createSheet(file, index, name); err != nil {
+			return err
+		}
+		if err := opts.writeSheet(ctx, file, name, path, styles); err != nil {
+			return fmt.Errorf("%s: %w", filepath.Base(path), err)
+		}
+	}
+	return file.SaveAs(outPath)
+}
+
+// writeSheet streams the records of the CSV file at path into the named worksheet.
+func (o CsvToXlsxOptions) writeSheet(ctx context.Context, file *excelize.File, sheet, path string, styles workbookStyles) error {
+	stream, streamErr := file.NewStreamWriter(sheet)
+	if streamErr != nil {
+		return streamErr
+	}
+	if o.AutoWidth {
+		var widths []int
+		widthErr := o.eachCsvRecord(ctx, path, func(_ int, record []string) error {
+			for index, field := range record {
+				widths = measureColumn(widths, index, field)
+			}
+			return nil
+		})
+		if widthErr != nil {
+			return widthErr
+		}
+		if err := setColumnWidths(stream, widths); err != nil {
+			return err
+		}
+	}
+	if o.StyleHeader && !o.NoHeader {
+		if err := freezeHeader(stream); err != nil {
+			return err
+		}
+	}
+	rowErr := o.eachCsvRecord(ctx, path, func(rowNumber int, record []string) error {
+		values := make([]any, len(record))
+		for index, field := range record {
+			switch {
+			case len(field) < 1:
+			case rowNumber == 1 && !o.NoHeader && o.StyleHeader:
+				values[index] = excelize.Cell{StyleID: styles.header, Value: field}
+			case rowNumber == 1 && !o.NoHeader:
+				values[index] = field
+			case o.CoerceTypes:
+				values[index] = coerceCell(field, styles)
+			default:
+				values[index] = field
+			}
+		}
+		cell, _ := excelize.CoordinatesToCellName(1, rowNumber)
+		return stream.SetRow(cell, values)
+	})
+	if rowErr != nil {
+		return rowErr
+	}
+	return stream.Flush()
+}
+
+// eachCsvRecord hands every record of the CSV file at path to yield together with its one-based row number.
+// Records may hold different numbers of fields.
+func (o CsvToXlsxOptions) eachCsvRecord(ctx context.Context, path string, yield func(rowNumber int, record []string) error) (readErr error) {
+	csvFile, openErr := os.Open(path)
+	if openErr != nil {
+		return openErr
+	}
+	defer func(csvFile *os.File) {
+		if err := csvFile.Close(); err != nil && readErr == nil {
+			readErr = err
+		}
+	}(csvFile)
+
+	var input io.Reader = csvFile
+	if source, _ := csvEncoding(o.Encoding); source != nil {
+		input = transform.NewReader(input, source.NewDecoder())
+	}
+	buffered := bufio.NewReader(input)
+	if head, _ := buffered.Peek(len(utf8BOM)); string(head) == string(utf8BOM) {
+		_, _ = buffered.Discard(len(utf8BOM))
+	}
+	reader := csv.NewReader(buffered)
+	reader.FieldsPerRecord = -1
+	if o.Delimiter != 0 {
+		reader.Comma = o.Delimiter
+	}
+	for rowNumber := 1; ; rowNumber++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		record, recordErr := reader.Read()
+		if recordErr == io.EOF {
+			return nil
+		}
+		if recordErr != nil {
+			return recordErr
+		}
+		if err := yield(rowNumber, record); err != nil {
+			return err
+		}
+	}
+}
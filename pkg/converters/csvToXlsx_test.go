@@ -0,0 +1,111 @@
+package converters
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+	"golang.org/x/text/encoding/charmap"
+)
+
+func TestCsvToXlsx(t *testing.T) {
+	dir := t.TempDir()
+	writeCsv := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("Error writing CSV file: %v", err)
+		}
+		return path
+	}
+	people := writeCsv("people.csv", "\xEF\xBB\xBFName,Age,Joined,Active,Account\n"+
+		"Ann,42,12-25-20,true,1234567890123456\n"+
+		"Bob,007,2021-03-04 10:30:00,false,3.5\n")
+	orders := writeCsv("orders[2024].csv", "Id;Amount\n1;9.99\n")
+	latin, _ := charmap.Windows1252.NewEncoder().String("Name\nJosé\n")
+	copied := writeCsv("people.txt", latin)
+
+	outPath := filepath.Join(dir, "out.xlsx")
+	opts := CsvToXlsxOptions{StyleHeader: true, AutoWidth: true, CoerceTypes: true}
+	if err := CsvToXlsx(context.Background(), outPath, []string{people}, opts); err != nil {
+		t.Fatalf("CsvToXlsx() error = %v", err)
+	}
+	file, openErr := excelize.OpenFile(outPath)
+	if openErr != nil {
+		t.Fatalf("Error opening workbook: %v", openErr)
+	}
+	defer file.Close()
+
+	tests := []struct {
+		name     string
+		cell     string
+		want     string
+		wantType excelize.CellType
+	}{
+		{name: "Header", cell: "A1", want: "Name", wantType: excelize.CellTypeInlineString},
+		{name: "Integer", cell: "B2", want: "42", wantType: excelize.CellTypeUnset},
+		{name: "Leading Zeros", cell: "B3", want: "007", wantType: excelize.CellTypeInlineString},
+		{name: "Date", cell: "C2", want: "12/25/20 00:00", wantType: excelize.CellTypeUnset},
+		{name: "Boolean", cell: "D3", want: "FALSE", wantType: excelize.CellTypeBool},
+		{name: "Long Integer", cell: "E2", want: "1234567890123456", wantType: excelize.CellTypeInlineString},
+		{name: "Decimal", cell: "E3", want: "3.5", wantType: excelize.CellTypeUnset},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, _ := file.GetCellValue("people", tt.cell)
+			if value != tt.want {
+				t.Errorf("CsvToXlsx() %s = %q, want %q", tt.cell, value, tt.want)
+			}
+			if cellType, _ := file.GetCellType("people", tt.cell); cellType != tt.wantType {
+				t.Errorf("CsvToXlsx() %s type = %v, want %v", tt.cell, cellType, tt.wantType)
+			}
+		})
+	}
+	if style, _ := file.GetCellStyle("people", "A1"); style == 0 {
+		t.Errorf("CsvToXlsx() left the header unstyled")
+	}
+	if width, _ := file.GetColWidth("people", "E"); width != 18 {
+		t.Errorf("CsvToXlsx() column E width = %v, want 18", width)
+	}
+	if panes, _ := file.GetPanes("people"); !panes.Freeze || panes.YSplit != 1 {
+		t.Errorf("CsvToXlsx() panes = %+v, want the header row frozen", panes)
+	}
+
+	opts = CsvToXlsxOptions{SheetNames: []string{"Orders", "Copy"}}
+	if err := CsvToXlsx(context.Background(), outPath, []string{orders}, opts); err == nil {
+		t.Errorf("CsvToXlsx() accepted more worksheet names than files")
+	}
+	opts = CsvToXlsxOptions{Delimiter: ';'}
+	if err := CsvToXlsx(context.Background(), outPath, []string{orders, orders}, opts); err != nil {
+		t.Fatalf("CsvToXlsx() error = %v", err)
+	}
+	if names := readSheetNames(t, outPath); !reflect.DeepEqual(names, []string{"orders2024", "orders2024 (2)"}) {
+		t.Errorf("CsvToXlsx() sheets = %v", names)
+	}
+
+	opts = CsvToXlsxOptions{Encoding: "Windows-1252", SheetNames: []string{"Latin"}}
+	if err := CsvToXlsx(context.Background(), outPath, []string{copied}, opts); err != nil {
+		t.Fatalf("CsvToXlsx() error = %v", err)
+	}
+	latinFile, _ := excelize.OpenFile(outPath)
+	defer latinFile.Close()
+	if value, _ := latinFile.GetCellValue("Latin", "A2"); value != "José" {
+		t.Errorf("CsvToXlsx() Windows-1252 value = %q, want %q", value, "José")
+	}
+	if err := CsvToXlsx(context.Background(), outPath, nil, CsvToXlsxOptions{}); err == nil {
+		t.Errorf("CsvToXlsx() accepted no CSV files")
+	}
+}
+
+// readSheetNames returns the worksheet names of the workbook at path.
+func readSheetNames(t *testing.T, path string) []string {
+	t.Helper()
+	file, err := excelize.OpenFile(path)
+	if err != nil {
+		t.Fatalf("Error opening workbook: %v", err)
+	}
+	defer file.Close()
+	return file.GetSheetList()
+}
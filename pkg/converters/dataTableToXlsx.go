@@ -0,0 +1,297 @@
+package converters
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// defaultHeaderFill is the fill color of a styled header row when StyleOptions.HeaderFill is empty.
+const defaultHeaderFill = "D9D9D9"
+
+// colorPattern matches a hex RGB color such as D9D9D9, optionally starting with #.
+var colorPattern = regexp.MustCompile(`^#?[0-9A-Fa-f]{6}$`)
+
+// ConditionalRule highlights the cells of a column meeting a condition.
+// Operator compares every cell with Value: one of >, >=, <, <=, = and !=, or between and not between, which
+// compare with the range from Value to MaxValue. Values are numbers or quoted text, as in Excel formulas,
+// e.g. 100 or "done". The operator contains highlights the cells holding the text Value, duplicate and unique
+// the values met more than once or only once, and data bar draws a bar in the Fill color sized to the value.
+// Fill and FontColor are hex colors such as FFC7CE; Bold writes the highlighted cells in bold.
+type ConditionalRule struct {
+	Column    string
+	Operator  string
+	Value     string
+	MaxValue  string
+	Fill      string
+	FontColor string
+	Bold      bool
+}
+
+// options returns the conditional format of the rule, using the highlighting style format.
+func (r ConditionalRule) options(format int) (excelize.ConditionalFormatOptions, error) {
+	opts := excelize.ConditionalFormatOptions{Type: "cell", Criteria: r.Operator, Format: format, Value: r.Value}
+	switch r.Operator {
+	case ">", ">=", "<", "<=", "=", "!=":
+	case "between", "not between":
+		opts.Value, opts.MinValue, opts.MaxValue = "", r.Value, r.MaxValue
+	case "contains":
+		opts.Type, opts.Criteria = "text", "containing"
+	case "duplicate", "unique":
+		opts.Type, opts.Criteria, opts.Value = r.Operator, "=", ""
+	case "data bar":
+		return excelize.ConditionalFormatOptions{Type: "data_bar", Criteria: "=", MinType: "min", MaxType: "max",
+			BarColor: "#" + strings.TrimPrefix(r.Fill, "#")}, nil
+	default:
+		return opts, fmt.Errorf("unknown conditional operator '%s'", r.Operator)
+	}
+	return opts, nil
+}
+
+// style returns the style of the cells the rule highlights.
+func (r ConditionalRule) style() *excelize.Style {
+	style := &excelize.Style{Font: &excelize.Font{Bold: r.Bold, Color: strings.TrimPrefix(r.FontColor, "#")}}
+	if len(r.Fill) > 0 {
+		style.Fill = excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{strings.TrimPrefix(r.Fill, "#")}}
+	}
+	return style
+}
+
+// StyleOptions configures the workbook DataTable.WriteXlsx writes.
+// SheetName names the worksheet, after the table element, or Sheet1, by default.
+// StyleHeader writes the header row in bold on a shaded fill; HeaderFill and HeaderFontColor are the hex colors
+// of its fill and font. FreezeHeader keeps the header row in view while scrolling, and AutoFilter adds filter
+// buttons to it.
+// AutoWidth sizes every column to its longest value; ColumnWidths sets the width of the named columns, in
+// characters, instead. CoerceTypes writes the values holding numbers, booleans and dates as such, as
+// CsvToXlsxOptions does. NumberFormats sets the Excel number format of the named columns, such as #,##0.00
+// or yyyy-mm-dd, which only applies to numbers and dates. Conditions highlights the cells meeting their rules.
+// Columns are named as the elements of the row columns.
+type StyleOptions struct {
+	SheetName       string
+	StyleHeader     bool
+	HeaderFill      string
+	HeaderFontColor string
+	FreezeHeader    bool
+	AutoFilter      bool
+	AutoWidth       bool
+	ColumnWidths    map[string]float64
+	CoerceTypes     bool
+	NumberFormats   map[string]string
+	Conditions      []ConditionalRule
+}
+
+// validate reports colors that are not hex colors, invalid widths, unknown conditional operators and options
+// naming unknown columns.
+func (o StyleOptions) validate(layout *sheetLayout) error {
+	colors := []string{o.HeaderFill, o.HeaderFontColor}
+	for _, rule := range o.Conditions {
+		colors = append(colors, rule.Fill, rule.FontColor)
+	}
+	for _, color := range colors {
+		if len(color) > 0 && !colorPattern.MatchString(color) {
+			return fmt.Errorf("invalid color '%s'", color)
+		}
+	}
+	for column, width := range o.ColumnWidths {
+		if _, known := layout.indexes[column]; !known {
+			return fmt.Errorf("unknown column '%s'", column)
+		}
+		if width < 0 || width > 255 {
+			return fmt.Errorf("invalid width %g of the column '%s'", width, column)
+		}
+	}
+	for column := range o.NumberFormats {
+		if _, known := layout.indexes[column]; !known {
+			return fmt.Errorf("unknown column '%s'", column)
+		}
+	}
+	for _, rule := range o.Conditions {
+		if _, known := layout.indexes[rule.Column]; !known {
+			return fmt.Errorf("unknown column '%s'", rule.Column)
+		}
+		if _, err := rule.options(0); err != nil {
+			return err
+		}
+		if rule.Operator == "data bar" && len(rule.Fill) < 1 {
+			return fmt.Errorf("the data bar of the column '%s' has no fill color", rule.Column)
+		}
+	}
+	return nil
+}
+
+// WriteXlsx writes the rows of the table to a worksheet of the .xlsx workbook at path, under a header row holding
+// the names of its columns in the order they are first met, styled as set by opts.
+func (t DataTable) WriteXlsx(path string, opts StyleOptions) (writeErr error) {
+	layout := newSheetLayout(opts.SheetName)
+	rows := make([]flatRow, len(t.Rows))
+	for index, dataRow := range t.Rows {
+		for _, column := range dataRow.Columns {
+			rows[index] = append(rows[index], flatCell{column: column.XMLName.Local, value: column.text()})
+		}
+		layout.add(rows[index], opts.AutoWidth)
+	}
+	if err := opts.validate(layout); err != nil {
+		return err
+	}
+	if len(layout.name) < 1 {
+		layout.name = t.XMLName.Local
+	}
+	layout.name = validSheetName(layout.name, 0, nil)
+
+	file := excelize.NewFile()
+	defer func(file *excelize.File) {
+		if err := file.Close(); err != nil && writeErr == nil {
+			writeErr = err
+		}
+	}(file)
+	if err := createSheet(file, 0, layout.name); err != nil {
+		return err
+	}
+	if err := opts.writeSheet(file, layout, rows); err != nil {
+		return err
+	}
+	return file.SaveAs(path)
+}
+
+// writeSheet streams the header and the rows into the worksheet of layout.
+func (o StyleOptions) writeSheet(file *excelize.File, layout *sheetLayout, rows []flatRow) error {
+	styles, stylesErr := newWorkbookStyles(file)
+	if stylesErr != nil {
+		return stylesErr
+	}
+	if o.StyleHeader {
+		fill, fontColor := o.HeaderFill, o.HeaderFontColor
+		if len(fill) < 1 {
+			fill = defaultHeaderFill
+		}
+		var err error
+		styles.header, err = file.NewStyle(&excelize.Style{
+			Font: &excelize.Font{Bold: true, Color: strings.TrimPrefix(fontColor, "#")},
+			Fill: excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{strings.TrimPrefix(fill, "#")}},
+		})
+		if err != nil {
+			return err
+		}
+	}
+	formats := make([]int, len(layout.columns))
+	for column, format := range o.NumberFormats {
+		var err error
+		if formats[layout.indexes[column]], err = file.NewStyle(&excelize.Style{CustomNumFmt: &format}); err != nil {
+			return err
+		}
+	}
+
+	stream, streamErr := file.NewStreamWriter(layout.name)
+	if streamErr != nil {
+		return streamErr
+	}
+	for index, column := range layout.columns {
+		width, set := o.ColumnWidths[column]
+		if !set && o.AutoWidth {
+			width, set = float64(layout.widths[index]+2), true
+		}
+		if !set {
+			continue
+		}
+		if err := stream.SetColWidth(index+1, index+1, width); err != nil {
+			return err
+		}
+	}
+	if o.FreezeHeader && len(layout.columns) > 0 {
+		if err := freezeHeader(stream); err != nil {
+			return err
+		}
+	}
+	if len(layout.columns) > 0 {
+		header := make([]any, len(layout.columns))
+		for index, column := range layout.columns {
+			header[index] = column
+			if o.StyleHeader {
+				header[index] = excelize.Cell{StyleID: styles.header, Value: column}
+			}
+		}
+		if err := stream.SetRow("A1", header); err != nil {
+			return err
+		}
+	}
+	for rowIndex, row := range rows {
+		values := layout.values(row, func(value any) any {
+			return o.cellValue(value.(string), styles)
+		})
+		for index, format := range formats {
+			if format == 0 || values[index] == nil {
+				continue
+			}
+			if cell, isCell := values[index].(excelize.Cell); isCell {
+				values[index] = cell.Value
+			}
+			values[index] = excelize.Cell{StyleID: format, Value: values[index]}
+		}
+		cellName, _ := excelize.CoordinatesToCellName(1, rowIndex+2)
+		if err := stream.SetRow(cellName, values); err != nil {
+			return err
+		}
+	}
+	if err := o.formatSheet(file, layout, len(rows)); err != nil {
+		return err
+	}
+	return stream.Flush()
+}
+
+// cellValue returns the value a text field is written as, nil for an empty field.
+func (o StyleOptions) cellValue(field string, styles workbookStyles) any {
+	switch {
+	case len(field) < 1:
+		return nil
+	case o.CoerceTypes:
+		return coerceCell(field, styles)
+	}
+	return field
+}
+
+// formatSheet adds the auto filter and the conditional formats to the worksheet of layout, holding rowCount rows
+// below the header. It must be called before the stream writer of the worksheet is flushed, which writes out
+// the settings of the worksheet along with its rows.
+func (o StyleOptions) formatSheet(file *excelize.File, layout *sheetLayout, rowCount int) error {
+	if len(layout.columns) < 1 {
+		return nil
+	}
+	if o.AutoFilter {
+		lastCell, _ := excelize.CoordinatesToCellName(len(layout.columns), rowCount+1)
+		if err := file.AutoFilter(layout.name, "A1:"+lastCell, nil); err != nil {
+			return err
+		}
+	}
+	if rowCount < 1 {
+		return nil
+	}
+	// The rules of a column are set together, since setting the formats of a range replaces those it had.
+	conditionals := make(map[string][]excelize.ConditionalFormatOptions)
+	var columns []string
+	for _, rule := range o.Conditions {
+		format, styleErr := file.NewConditionalStyle(rule.style())
+		if styleErr != nil {
+			return styleErr
+		}
+		conditional, ruleErr := rule.options(format)
+		if ruleErr != nil {
+			return ruleErr
+		}
+		if _, seen := conditionals[rule.Column]; !seen {
+			columns = append(columns, rule.Column)
+		}
+		conditionals[rule.Column] = append(conditionals[rule.Column], conditional)
+	}
+	for _, column := range columns {
+		columnNumber := layout.indexes[column] + 1
+		firstCell, _ := excelize.CoordinatesToCellName(columnNumber, 2)
+		lastCell, _ := excelize.CoordinatesToCellName(columnNumber, rowCount+1)
+		if err := file.SetConditionalFormat(layout.name, firstCell+":"+lastCell, conditionals[column]); err != nil {
+			return fmt.Errorf("the conditions on the column '%s': %w", column, err)
+		}
+	}
+	return nil
+}
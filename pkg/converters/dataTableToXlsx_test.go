@@ -0,0 +1,136 @@
+package converters
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestDataTableWriteXlsx(t *testing.T) {
+	columns := []string{"Name", "Amount", "Date"}
+	table := dataTable(columns, []string{"Bolt", "1200.5", "2024-03-01"}, []string{"Nut", "15", ""})
+	table.XMLName.Local = "Orders"
+	tests := []struct {
+		name    string
+		opts    StyleOptions
+		check   func(t *testing.T, file *excelize.File, sheet string)
+		wantErr string
+	}{
+		{
+			name: "Plain",
+			opts: StyleOptions{},
+			check: func(t *testing.T, file *excelize.File, sheet string) {
+				if sheet != "Orders" {
+					t.Errorf("sheet = %s, want Orders", sheet)
+				}
+				rows, _ := file.GetRows(sheet)
+				want := [][]string{{"Name", "Amount", "Date"}, {"Bolt", "1200.5", "2024-03-01"}, {"Nut", "15"}}
+				if !reflect.DeepEqual(rows, want) {
+					t.Errorf("rows = %v, want %v", rows, want)
+				}
+				if cellType, _ := file.GetCellType(sheet, "B2"); cellType == excelize.CellTypeNumber {
+					t.Errorf("B2 was written as a number without CoerceTypes")
+				}
+			},
+		},
+		{
+			name: "Styled",
+			opts: StyleOptions{SheetName: "Report", StyleHeader: true, HeaderFill: "#1F4E78", HeaderFontColor: "FFFFFF",
+				FreezeHeader: true, AutoFilter: true, AutoWidth: true, ColumnWidths: map[string]float64{"Name": 30},
+				CoerceTypes: true, NumberFormats: map[string]string{"Amount": "#,##0.00", "Date": "dd/mm/yyyy"}},
+			check: func(t *testing.T, file *excelize.File, sheet string) {
+				if sheet != "Report" {
+					t.Errorf("sheet = %s, want Report", sheet)
+				}
+				headerStyle, _ := file.GetCellStyle(sheet, "A1")
+				style, _ := file.GetStyle(headerStyle)
+				if style == nil || !style.Font.Bold || style.Font.Color != "FFFFFF" || style.Fill.Color[0] != "1F4E78" {
+					t.Errorf("header style = %+v, want bold white on 1F4E78", style)
+				}
+				if amount, _ := file.GetCellValue(sheet, "B2"); amount != "1,200.50" {
+					t.Errorf("B2 = %s, want 1,200.50", amount)
+				}
+				if date, _ := file.GetCellValue(sheet, "C2"); date != "01/03/2024" {
+					t.Errorf("C2 = %s, want 01/03/2024", date)
+				}
+				if width, _ := file.GetColWidth(sheet, "A"); width != 30 {
+					t.Errorf("width of A = %g, want 30", width)
+				}
+				if width, _ := file.GetColWidth(sheet, "C"); width != 12 {
+					t.Errorf("width of C = %g, want 12", width)
+				}
+				filtered := false
+				for _, name := range file.GetDefinedName() {
+					filtered = filtered || strings.HasSuffix(name.RefersTo, "$A$1:$C$3")
+				}
+				if !filtered {
+					t.Errorf("defined names = %+v, want a filter on A1:C3", file.GetDefinedName())
+				}
+				panes, _ := file.GetPanes(sheet)
+				if !panes.Freeze || panes.YSplit != 1 {
+					t.Errorf("panes = %+v, want the header frozen", panes)
+				}
+			},
+		},
+		{
+			name: "Conditions",
+			opts: StyleOptions{CoerceTypes: true, Conditions: []ConditionalRule{
+				{Column: "Amount", Operator: ">", Value: "100", Fill: "FFC7CE", FontColor: "9C0006"},
+				{Column: "Amount", Operator: "between", Value: "1", MaxValue: "20", Bold: true},
+				{Column: "Name", Operator: "duplicate", Fill: "FFEB9C"},
+				{Column: "Amount", Operator: "data bar", Fill: "638EC6"},
+			}},
+			check: func(t *testing.T, file *excelize.File, sheet string) {
+				formats, _ := file.GetConditionalFormats(sheet)
+				if len(formats["B2:B3"]) != 3 || len(formats["A2:A3"]) != 1 {
+					t.Fatalf("conditional formats = %+v, want 3 on B2:B3 and 1 on A2:A3", formats)
+				}
+				first := formats["B2:B3"][0]
+				if first.Type != "cell" || first.Criteria != "greater than" || first.Value != "100" {
+					t.Errorf("first rule = %+v, want cells greater than 100", first)
+				}
+			},
+		},
+		{
+			name:    "Unknown Column",
+			opts:    StyleOptions{NumberFormats: map[string]string{"Price": "0.00"}},
+			wantErr: "unknown column 'Price'",
+		},
+		{
+			name:    "Unknown Operator",
+			opts:    StyleOptions{Conditions: []ConditionalRule{{Column: "Amount", Operator: "~"}}},
+			wantErr: "unknown conditional operator '~'",
+		},
+		{
+			name:    "Invalid Color",
+			opts:    StyleOptions{StyleHeader: true, HeaderFill: "blue"},
+			wantErr: "invalid color 'blue'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "table.xlsx")
+			err := table.WriteXlsx(path, tt.opts)
+			if len(tt.wantErr) > 0 {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("WriteXlsx() error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("WriteXlsx() error = %v", err)
+			}
+			file, openErr := excelize.OpenFile(path)
+			if openErr != nil {
+				t.Fatalf("Error opening the workbook: %v", openErr)
+			}
+			defer func() {
+				_ = file.Close()
+			}()
+			tt.check(t, file, file.GetSheetName(0))
+		})
+	}
+}
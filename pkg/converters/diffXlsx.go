@@ -0,0 +1,152 @@
+package converters
+
+import (
+	"strings"
+
+	"GoTools/pkg/datatable"
+	"github.com/xuri/excelize/v2"
+)
+
+// Default fills of the rows and cells of an annotated diff.
+const (
+	defaultAddedFill   = "C6EFCE"
+	defaultRemovedFill = "FFC7CE"
+	defaultChangedFill = "FFEB9C"
+)
+
+// DiffXlsxOptions configures writing the outcome of datatable.Compare as an annotated worksheet.
+// SheetName names the worksheet, "Diff" by default. AddedFill, RemovedFill and ChangedFill are the hex colours
+// of the added and removed rows and of the changed cells, green, red and yellow by default. Author signs the
+// comments holding the old values of the changed cells.
+type DiffXlsxOptions struct {
+	SheetName   string
+	AddedFill   string
+	RemovedFill string
+	ChangedFill string
+	Author      string
+}
+
+// WriteDiffXlsx writes current to a worksheet of the .xlsx workbook at path, annotated with diff, the outcome of
+// datatable.Compare(old, current): a leading Change column tells whether a row was added or changed, added rows
+// are filled with opts.AddedFill and changed cells with opts.ChangedFill, commented with their old value.
+// The rows removed from old follow, filled with opts.RemovedFill, and so do the columns removed from old.
+func WriteDiffXlsx(path string, old, current *datatable.DataTable, diff *datatable.Diff, opts DiffXlsxOptions) (writeErr error) {
+	columns := append(current.ColumnNames(), diff.RemovedColumns...)
+	file := excelize.NewFile()
+	defer func(file *excelize.File) {
+		if err := file.Close(); err != nil && writeErr == nil {
+			writeErr = err
+		}
+	}(file)
+	sheet := validSheetName(defaultName(opts.SheetName, "Diff"), 0, nil)
+	if err := createSheet(file, 0, sheet); err != nil {
+		return err
+	}
+	fill := func(color string) (int, error) {
+		return file.NewStyle(&excelize.Style{Fill: excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{strings.TrimPrefix(color, "#")}}})
+	}
+	added, err := fill(defaultName(opts.AddedFill, defaultAddedFill))
+	if err != nil {
+		return err
+	}
+	removed, err := fill(defaultName(opts.RemovedFill, defaultRemovedFill))
+	if err != nil {
+		return err
+	}
+	changed, err := fill(defaultName(opts.ChangedFill, defaultChangedFill))
+	if err != nil {
+		return err
+	}
+	header, err := file.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		return err
+	}
+
+	line := 1
+	writeRow := func(change string, row datatable.Row, style int) error {
+		values := []any{change}
+		for _, column := range columns {
+			values = append(values, row.Get(column))
+		}
+		first, _ := excelize.CoordinatesToCellName(1, line)
+		if err := file.SetSheetRow(sheet, first, &values); err != nil {
+			return err
+		}
+		if style > 0 {
+			last, _ := excelize.CoordinatesToCellName(len(values), line)
+			return file.SetCellStyle(sheet, first, last, style)
+		}
+		return nil
+	}
+	headerRow := append([]any{"Change"}, anySlice(columns)...)
+	if err := file.SetSheetRow(sheet, "A1", &headerRow); err != nil {
+		return err
+	}
+	lastHeader, _ := excelize.CoordinatesToCellName(len(headerRow), 1)
+	if err := file.SetCellStyle(sheet, "A1", lastHeader, header); err != nil {
+		return err
+	}
+
+	byNewRow := make(map[int]datatable.RowChange)
+	for _, change := range diff.Changes {
+		if change.NewRow > 0 {
+			byNewRow[change.NewRow] = change
+		}
+	}
+	position := make(map[string]int, len(columns))
+	for index, column := range columns {
+		position[column] = index + 2
+	}
+	for index := 0; index < current.Len(); index++ {
+		line++
+		change, found := byNewRow[index+1]
+		switch {
+		case !found:
+			err = writeRow("", current.Row(index), 0)
+		case change.Kind == datatable.RowAdded:
+			err = writeRow(change.Kind.String(), current.Row(index), added)
+		default:
+			err = writeRow(change.Kind.String(), current.Row(index), 0)
+		}
+		if err != nil {
+			return err
+		}
+		for _, cellChange := range change.Cells {
+			cell, _ := excelize.CoordinatesToCellName(position[cellChange.Column], line)
+			if err := file.SetCellStyle(sheet, cell, cell, changed); err != nil {
+				return err
+			}
+			comment := excelize.Comment{Cell: cell, Author: opts.Author, Text: "Was: " + cellChange.Old}
+			if err := file.AddComment(sheet, comment); err != nil {
+				return err
+			}
+		}
+	}
+	for _, change := range diff.Changes {
+		if change.Kind != datatable.RowRemoved {
+			continue
+		}
+		line++
+		if err := writeRow(change.Kind.String(), old.Row(change.OldRow-1), removed); err != nil {
+			return err
+		}
+	}
+	return file.SaveAs(path)
+}
+
+// anySlice returns values as a slice of any, as excelize takes the values of a row.
+func anySlice(values []string) []any {
+	slice := make([]any, len(values))
+	for index, value := range values {
+		slice[index] = value
+	}
+	return slice
+}
+
+// defaultName returns name, or fallback when it is blank.
+func defaultName(name, fallback string) string {
+	if len(strings.TrimSpace(name)) < 1 {
+		return fallback
+	}
+	return name
+}
@@ -0,0 +1,68 @@
+package converters
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"GoTools/pkg/datatable"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestWriteDiffXlsx(t *testing.T) {
+	old, err := datatable.FromRecords([]string{"Id", "Name", "Note"}, [][]string{{"1", "Ann", "a"}, {"2", "Bob", "b"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	current, err := datatable.FromRecords([]string{"Id", "Name"}, [][]string{{"1", "Anne"}, {"3", "Cid"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	diff, err := datatable.Compare(old, current, datatable.CompareOptions{Keys: []string{"Id"}})
+	if err != nil {
+		t.Fatalf("Compare() error = %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "diff.xlsx")
+	if err := WriteDiffXlsx(path, old, current, diff, DiffXlsxOptions{Author: "Audit"}); err != nil {
+		t.Fatalf("WriteDiffXlsx() error = %v", err)
+	}
+
+	file, err := excelize.OpenFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	rows, err := file.GetRows("Diff")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		{"Change", "Id", "Name", "Note"},
+		{"changed", "1", "Anne"},
+		{"added", "3", "Cid"},
+		{"removed", "2", "Bob", "b"},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("WriteDiffXlsx() rows = %v, want %v", rows, want)
+	}
+	comments, err := file.GetComments("Diff")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(comments) != 1 || comments[0].Cell != "C2" || comments[0].Text != "Was: Ann" {
+		t.Errorf("WriteDiffXlsx() comments = %+v, want the old name on C2", comments)
+	}
+	for cell, wantFill := range map[string]string{"C2": defaultChangedFill, "A3": defaultAddedFill, "D4": defaultRemovedFill} {
+		styleID, err := file.GetCellStyle("Diff", cell)
+		if err != nil {
+			t.Fatal(err)
+		}
+		style, err := file.GetStyle(styleID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(style.Fill.Color) != 1 || style.Fill.Color[0] != wantFill {
+			t.Errorf("WriteDiffXlsx() fill of %s = %v, want %s", cell, style.Fill.Color, wantFill)
+		}
+	}
+}
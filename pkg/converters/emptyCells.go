@@ -0,0 +1,67 @@
+package converters
+
+import (
+	"encoding/xml"
+	"fmt"
+)
+
+// EmptyCellMode selects how cells without a value are written.
+type EmptyCellMode int
+
+const (
+	// EmptyElements writes an empty element (<Name></Name>), or an empty attribute in attribute mode.
+	EmptyElements EmptyCellMode = iota
+	// OmitEmpty leaves the element, or attribute, of an empty cell out altogether.
+	// Empty cells carrying a comment attribute are kept, so the comment is not lost.
+	OmitEmpty
+	// NilEmpty marks the element of an empty cell with xsi:nil="true". It cannot be combined with attribute mode.
+	NilEmpty
+)
+
+// ParseEmptyCellMode returns the EmptyCellMode called empty, omit or nil.
+func ParseEmptyCellMode(mode string) (EmptyCellMode, error) {
+	switch mode {
+	case "", "empty":
+		return EmptyElements, nil
+	case "omit":
+		return OmitEmpty, nil
+	case "nil":
+		return NilEmpty, nil
+	}
+	return EmptyElements, fmt.Errorf("unknown empty cell mode '%s'", mode)
+}
+
+// isEmpty reports whether the column holds no value.
+func (c DataColumn) isEmpty() bool {
+	return len(c.text()) < 1
+}
+
+// annotated reports whether the column carries attributes other than its xsi:type.
+func (c DataColumn) annotated() bool {
+	for _, attr := range c.Attributes {
+		if attr.Name.Local != "xsi:type" {
+			return true
+		}
+	}
+	return false
+}
+
+// writeEmptyCells leaves out or marks the empty columns of the row as configured by the EmptyCellMode.
+func (o XmlOptions) writeEmptyCells(dataRow *DataRow) {
+	if o.EmptyCells == NilEmpty {
+		for columnIndex, column := range dataRow.Columns {
+			if column.isEmpty() {
+				dataRow.Columns[columnIndex].Attributes = append(column.Attributes,
+					xml.Attr{Name: xml.Name{Local: "xsi:nil"}, Value: "true"})
+			}
+		}
+		return
+	}
+	columns := dataRow.Columns[:0]
+	for _, column := range dataRow.Columns {
+		if !column.isEmpty() || column.annotated() {
+			columns = append(columns, column)
+		}
+	}
+	dataRow.Columns = columns
+}
@@ -0,0 +1,225 @@
+package converters
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+// utf8BOM is the byte order mark some consumers expect at the start of UTF-8 files.
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// canonicalEncoding returns the name an encoding is declared with, or an error for unsupported encodings.
+func canonicalEncoding(name string) (string, error) {
+	switch strings.ToUpper(strings.ReplaceAll(name, "_", "-")) {
+	case "", "UTF-8", "UTF8":
+		return "UTF-8", nil
+	case "UTF-16", "UTF16":
+		return "UTF-16", nil
+	case "UTF-16LE":
+		return "UTF-16LE", nil
+	case "UTF-16BE":
+		return "UTF-16BE", nil
+	case "ISO-8859-1", "LATIN1", "LATIN-1":
+		return "ISO-8859-1", nil
+	}
+	return "", fmt.Errorf("unsupported output encoding '%s'", name)
+}
+
+// validateEncoding reports encoding options that cannot be honoured.
+func (o XmlOptions) validateEncoding() error {
+	encoding, err := canonicalEncoding(o.Encoding)
+	if err != nil {
+		return err
+	}
+	if o.ByteOrderMark && encoding != "UTF-8" {
+		return fmt.Errorf("a byte order mark can only be requested for UTF-8, %s output has its own", encoding)
+	}
+	return nil
+}
+
+// documentEncoder encodes a document into the configured output encoding.
+// finish must be called once the document is complete, to flush the transcoded output.
+type documentEncoder struct {
+	*xml.Encoder
+	out io.WriteCloser
+}
+
+// newEncoder returns an encoder writing to w with the configured layout and encoding, which has already written
+// the byte order mark and the XML declaration if they are requested. The declaration is always written for
+// encodings other than UTF-8, which consumers could not detect otherwise.
+func (o XmlOptions) newEncoder(w io.Writer) (*documentEncoder, error) {
+	encoding, encodingErr := canonicalEncoding(o.Encoding)
+	if encodingErr != nil {
+		return nil, encodingErr
+	}
+	if o.ByteOrderMark {
+		if _, err := w.Write(utf8BOM); err != nil {
+			return nil, err
+		}
+	}
+	document := &documentEncoder{}
+	switch encoding {
+	case "UTF-16":
+		document.out = transform.NewWriter(w, unicode.UTF16(unicode.LittleEndian, unicode.UseBOM).NewEncoder())
+	case "UTF-16LE":
+		document.out = transform.NewWriter(w, unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM).NewEncoder())
+	case "UTF-16BE":
+		document.out = transform.NewWriter(w, unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM).NewEncoder())
+	case "ISO-8859-1":
+		document.out = &latin1Escaper{w: transform.NewWriter(w, charmap.ISO8859_1.NewEncoder())}
+	}
+	if document.out != nil {
+		w = document.out
+	}
+	if o.Newline == "\r\n" {
+		w = crlfWriter{w: w}
+	}
+	document.Encoder = xml.NewEncoder(w)
+	switch {
+	case o.Compact:
+	case len(o.Indent) > 0:
+		document.Indent("", o.Indent)
+	default:
+		document.Indent("", defaultIndent)
+	}
+	if o.Declaration || encoding != "UTF-8" {
+		declaration := xml.ProcInst{Target: "xml", Inst: []byte(`version="1.0" encoding="` + encoding + `"`)}
+		if err := document.EncodeToken(declaration); err != nil {
+			return nil, err
+		}
+		if !o.Compact {
+			if err := document.EncodeToken(xml.CharData("\n")); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return document, nil
+}
+
+// finish flushes the document and completes its transcoding.
+func (d *documentEncoder) finish() error {
+	if err := d.Flush(); err != nil {
+		return err
+	}
+	if d.out != nil {
+		return d.out.Close()
+	}
+	return nil
+}
+
+// Scanning states of the latin1Escaper.
+const (
+	latin1Text = iota
+	latin1TagOpen
+	latin1Tag
+	latin1Quoted
+	latin1CData
+)
+
+// cdataOpen follows the '<' opening a CDATA section.
+var cdataOpen = []byte("![CDATA[")
+
+// latin1Escaper replaces the characters ISO-8859-1 cannot represent with character references before the output
+// is transcoded. References are only valid in character data and attribute values, so CDATA sections are closed
+// around them, and such characters in element or attribute names fail the conversion.
+type latin1Escaper struct {
+	w       io.WriteCloser
+	state   int
+	quote   byte
+	matched int
+	pending []byte
+}
+
+func (e *latin1Escaper) Write(p []byte) (int, error) {
+	data := append(e.pending, p...)
+	e.pending = nil
+	var out bytes.Buffer
+	for index := 0; index < len(data); {
+		char := data[index]
+		if char >= utf8.RuneSelf {
+			if !utf8.FullRune(data[index:]) {
+				e.pending = append([]byte(nil), data[index:]...)
+				break
+			}
+			r, size := utf8.DecodeRune(data[index:])
+			index += size
+			if r <= 0xFF {
+				out.WriteRune(r)
+				e.matched = 0
+				continue
+			}
+			switch e.state {
+			case latin1Tag, latin1TagOpen:
+				return 0, fmt.Errorf("the name holding '%c' cannot be encoded in ISO-8859-1", r)
+			case latin1CData:
+				fmt.Fprintf(&out, "]]>&#x%X;<![CDATA[", r)
+			default:
+				fmt.Fprintf(&out, "&#x%X;", r)
+			}
+			e.matched = 0
+			continue
+		}
+		out.WriteByte(char)
+		index++
+		e.advance(char)
+	}
+	if _, err := e.w.Write(out.Bytes()); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// advance moves the scanning state past a single ASCII byte of the output.
+func (e *latin1Escaper) advance(char byte) {
+	switch e.state {
+	case latin1Text:
+		if char == '<' {
+			e.state, e.matched = latin1TagOpen, 0
+		}
+	case latin1TagOpen:
+		if char == cdataOpen[e.matched] {
+			if e.matched++; e.matched == len(cdataOpen) {
+				e.state, e.matched = latin1CData, 0
+			}
+			return
+		}
+		e.state = latin1Tag
+		e.advance(char)
+	case latin1Tag:
+		switch char {
+		case '"', '\'':
+			e.state, e.quote = latin1Quoted, char
+		case '>':
+			e.state = latin1Text
+		}
+	case latin1Quoted:
+		if char == e.quote {
+			e.state = latin1Tag
+		}
+	case latin1CData:
+		switch {
+		case char == '>' && e.matched == 2:
+			e.state, e.matched = latin1Text, 0
+		case char == ']':
+			e.matched = min(e.matched+1, 2)
+		default:
+			e.matched = 0
+		}
+	}
+}
+
+// Close reports output ending in the middle of a character and closes the transcoder.
+func (e *latin1Escaper) Close() error {
+	if len(e.pending) > 0 {
+		return io.ErrUnexpectedEOF
+	}
+	return e.w.Close()
+}
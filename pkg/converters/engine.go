@@ -0,0 +1,185 @@
+package converters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Job is a conversion run by RunJobs: the records of Source copied into the sink OpenSink returns, as Convert
+// does. OpenSink is called only when the job starts, so the outputs of the jobs waiting are not opened yet.
+// Name identifies the job in its result and its error, such as the worksheet or the file it converts.
+type Job struct {
+	Name     string
+	Source   RowSource
+	OpenSink func() (RowSink, error)
+}
+
+// JobResult is the outcome of a job: the number of records it wrote, how long it took and the error it failed
+// with, nil when it succeeded.
+type JobResult struct {
+	Name     string
+	Records  int
+	Duration time.Duration
+	Err      error
+}
+
+// EngineOptions configures running jobs. Concurrency is the number of jobs run at a time, the number of CPUs by
+// default. FailFast cancels the jobs running and skips the ones waiting as soon as a job fails; the others run
+// to the end by default. OnResult, when set, is called with the result of every job as it ends, one call at a
+// time, such as to log the progress of a batch.
+type EngineOptions struct {
+	Concurrency int
+	FailFast    bool
+	OnResult    func(result JobResult)
+}
+
+// RunJobs runs jobs on a pool of opts.Concurrency workers and returns their results in the order of jobs, with
+// the errors of the jobs which failed joined, each prefixed with the name of its job, or nil when all of them
+// succeeded. The jobs which did not start, because ctx was cancelled or another job failed with FailFast, fail
+// with the error of the context.
+func RunJobs(ctx context.Context, jobs []Job, opts EngineOptions) ([]JobResult, error) {
+	if opts.Concurrency < 0 {
+		return nil, fmt.Errorf("invalid concurrency %d", opts.Concurrency)
+	}
+	concurrency := opts.Concurrency
+	if concurrency < 1 {
+		concurrency = runtime.NumCPU()
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]JobResult, len(jobs))
+	indexes := make(chan int)
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	for range min(concurrency, len(jobs)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				result := runJob(ctx, jobs[index])
+				mutex.Lock()
+				results[index] = result
+				if opts.OnResult != nil {
+					opts.OnResult(result)
+				}
+				mutex.Unlock()
+				if result.Err != nil && opts.FailFast {
+					cancel()
+				}
+			}
+		}()
+	}
+	for index := range jobs {
+		indexes <- index
+	}
+	close(indexes)
+	wg.Wait()
+
+	var errs []error
+	for _, result := range results {
+		if result.Err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", result.Name, result.Err))
+		}
+	}
+	return results, errors.Join(errs...)
+}
+
+// runJob runs job, counting the records it writes.
+func runJob(ctx context.Context, job Job) JobResult {
+	result := JobResult{Name: job.Name}
+	start := time.Now()
+	defer func() {
+		result.Duration = time.Since(start)
+	}()
+	if err := ctx.Err(); err != nil {
+		result.Err = err
+		return result
+	}
+	sink, openErr := job.OpenSink()
+	if openErr != nil {
+		result.Err = openErr
+		return result
+	}
+	result.Err = Convert(ctx, job.Source, &countingSink{RowSink: sink, records: &result.Records})
+	return result
+}
+
+// countingSink counts the records written to the sink it wraps.
+type countingSink struct {
+	RowSink
+	records *int
+}
+
+func (s *countingSink) WriteRecord(record []string) error {
+	if err := s.RowSink.WriteRecord(record); err != nil {
+		return err
+	}
+	*s.records++
+	return nil
+}
+
+// fileSink is a sink writing to a file it closes.
+type fileSink struct {
+	RowSink
+	file *os.File
+}
+
+// FileSink creates the file at path and returns the sink newSink returns for it, closing the file when the sink
+// is closed, such as
+//
+//	sink, err := FileSink(filepath.Join(dir, sheet+".csv"), func(w io.Writer) RowSink { return CsvSink(w, opts) })
+func FileSink(path string, newSink func(w io.Writer) RowSink) (RowSink, error) {
+	file, createErr := os.Create(path)
+	if createErr != nil {
+		return nil, createErr
+	}
+	return &fileSink{RowSink: newSink(file), file: file}, nil
+}
+
+func (s *fileSink) Close() error {
+	closeErr := s.RowSink.Close()
+	if err := s.file.Close(); err != nil && closeErr == nil {
+		closeErr = err
+	}
+	return closeErr
+}
+
+// SheetJobs returns a job per worksheet of the workbook at path selected by sheets, each reading its worksheet
+// with opts, as SheetSource does, into the sink openSink returns for it, and named after it. RunJobs converts
+// them concurrently, each job opening the workbook on its own.
+func SheetJobs(ctx context.Context, path string, sheets SheetSelector, opts ReadOptions, openSink func(sheet string) (RowSink, error)) (jobs []Job, jobsErr error) {
+	if optsErr := opts.validate(); optsErr != nil {
+		return nil, optsErr
+	}
+	file, openErr := openWorkbook(ctx, path, opts)
+	if openErr != nil {
+		return nil, openErr
+	}
+	defer func(file *excelize.File) {
+		if err := file.Close(); err != nil && jobsErr == nil {
+			jobsErr = err
+		}
+	}(file)
+
+	names, selectErr := opts.selectSheets(file, sheets)
+	if selectErr != nil {
+		return nil, selectErr
+	}
+	for _, name := range names {
+		jobs = append(jobs, Job{
+			Name:     name,
+			Source:   SheetSource(path, SheetByName(name), opts),
+			OpenSink: func() (RowSink, error) { return openSink(name) },
+		})
+	}
+	return jobs, nil
+}
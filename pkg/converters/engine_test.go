@@ -0,0 +1,148 @@
+package converters
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSheetJobs(t *testing.T) {
+	path := createTestWorkbook(t, map[string][][]any{
+		"People": {{"Name", "Age"}, {"Ann", 31}, {"Bob", 42}},
+		"Orders": {{"Id", "Amount"}, {1, 9.99}},
+		"Empty":  {{"Id"}},
+	}, "People", "Orders", "Empty")
+	dir := t.TempDir()
+	ctx := context.Background()
+	jobs, jobsErr := SheetJobs(ctx, path, AllSheets(), ReadOptions{}, func(sheet string) (RowSink, error) {
+		return FileSink(filepath.Join(dir, sheet+".csv"), func(w io.Writer) RowSink { return CsvSink(w, CsvOptions{}) })
+	})
+	if jobsErr != nil {
+		t.Fatalf("SheetJobs() error = %v", jobsErr)
+	}
+	var reported []string
+	results, err := RunJobs(ctx, jobs, EngineOptions{Concurrency: 2, OnResult: func(result JobResult) {
+		reported = append(reported, result.Name)
+	}})
+	if err != nil {
+		t.Fatalf("RunJobs() error = %v", err)
+	}
+	wantRecords := map[string]int{"People": 2, "Orders": 1, "Empty": 0}
+	for index, result := range results {
+		if result.Name != jobs[index].Name || result.Records != wantRecords[result.Name] || result.Err != nil {
+			t.Errorf("result %d = %+v, want %d records of %s", index, result, wantRecords[jobs[index].Name], jobs[index].Name)
+		}
+	}
+	if len(reported) != len(jobs) {
+		t.Errorf("OnResult called for %v, want every job", reported)
+	}
+	content, _ := os.ReadFile(filepath.Join(dir, "People.csv"))
+	if want := "Name,Age\nAnn,31\nBob,42\n"; string(content) != want {
+		t.Errorf("People.csv = %q, want %q", content, want)
+	}
+}
+
+// blockingSource counts the sources running at once, failing when fail is set.
+type blockingSource struct {
+	running, peak *atomic.Int32
+	fail          bool
+}
+
+func (s blockingSource) Rows(ctx context.Context, onHeader func(header []string) error, onRecord func(record []string) error) error {
+	current := s.running.Add(1)
+	defer s.running.Add(-1)
+	for peak := s.peak.Load(); current > peak && !s.peak.CompareAndSwap(peak, current); peak = s.peak.Load() {
+	}
+	if s.fail {
+		return errors.New("broken source")
+	}
+	select {
+	case <-time.After(20 * time.Millisecond):
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	if err := onHeader([]string{"Id"}); err != nil {
+		return err
+	}
+	return onRecord([]string{"1"})
+}
+
+func TestRunJobs(t *testing.T) {
+	newJobs := func(running, peak *atomic.Int32, failing int) []Job {
+		jobs := make([]Job, 8)
+		for index := range jobs {
+			jobs[index] = Job{
+				Name:     string(rune('a' + index)),
+				Source:   blockingSource{running: running, peak: peak, fail: index == failing},
+				OpenSink: func() (RowSink, error) { return CsvSink(io.Discard, CsvOptions{}), nil },
+			}
+		}
+		return jobs
+	}
+	ctx := context.Background()
+
+	t.Run("Concurrency", func(t *testing.T) {
+		var running, peak atomic.Int32
+		results, err := RunJobs(ctx, newJobs(&running, &peak, -1), EngineOptions{Concurrency: 3})
+		if err != nil {
+			t.Fatalf("RunJobs() error = %v", err)
+		}
+		if peak.Load() != 3 {
+			t.Errorf("%d jobs ran at once, want 3", peak.Load())
+		}
+		for _, result := range results {
+			if result.Records != 1 {
+				t.Errorf("job %s wrote %d records, want 1", result.Name, result.Records)
+			}
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		var running, peak atomic.Int32
+		results, err := RunJobs(ctx, newJobs(&running, &peak, 2), EngineOptions{Concurrency: 2})
+		if err == nil || err.Error() != "c: broken source" {
+			t.Fatalf("RunJobs() error = %v, want c: broken source", err)
+		}
+		for index, result := range results {
+			if (result.Err != nil) != (index == 2) {
+				t.Errorf("job %s error = %v", result.Name, result.Err)
+			}
+		}
+	})
+
+	t.Run("FailFast", func(t *testing.T) {
+		var running, peak atomic.Int32
+		results, err := RunJobs(ctx, newJobs(&running, &peak, 0), EngineOptions{Concurrency: 2, FailFast: true})
+		if err == nil {
+			t.Fatal("RunJobs() succeeded, want an error")
+		}
+		if last := results[len(results)-1]; !errors.Is(last.Err, context.Canceled) {
+			t.Errorf("last job error = %v, want %v", last.Err, context.Canceled)
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		if _, err := RunJobs(ctx, nil, EngineOptions{Concurrency: -1}); err == nil {
+			t.Error("RunJobs() accepted a negative concurrency")
+		}
+	})
+}
+
+func TestFileSinkClosesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.json")
+	sink, err := FileSink(path, func(w io.Writer) RowSink { return JsonSink(w, JsonOptions{Lines: true}) })
+	if err != nil {
+		t.Fatalf("FileSink() error = %v", err)
+	}
+	if err := Convert(context.Background(), TableSource(sinkTestTable(t)), sink); err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+	if err := sink.(*fileSink).file.Close(); err == nil {
+		t.Error("the file is still open after the sink was closed")
+	}
+}
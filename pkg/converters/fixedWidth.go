@@ -0,0 +1,79 @@
+package converters
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"os"
+
+	"GoTools/pkg/fixedwidth"
+	"github.com/xuri/excelize/v2"
+	"golang.org/x/text/transform"
+)
+
+// openFixedWidth reads the fixed-width text file at path into an in-memory workbook holding a single worksheet
+// named after the file, cut into the fields of opts.FixedWidth below a header row holding their names.
+// Every value is written as a string, so numbers and dates are read back exactly as they appear in the file.
+func openFixedWidth(path string, opts ReadOptions) (file *excelize.File, openErr error) {
+	source, encodingErr := csvEncoding(opts.FixedWidthEncoding)
+	if encodingErr != nil {
+		return nil, encodingErr
+	}
+	textFile, textErr := os.Open(path)
+	if textErr != nil {
+		return nil, textErr
+	}
+	defer func(textFile *os.File) {
+		if err := textFile.Close(); err != nil && openErr == nil {
+			openErr = err
+		}
+	}(textFile)
+
+	var input io.Reader = bufio.NewReader(textFile)
+	if source != nil {
+		input = transform.NewReader(input, source.NewDecoder())
+	}
+	file = excelize.NewFile()
+	file.Path = path
+	if loadErr := loadFixedWidth(file, sheetNameFor(path, 0, nil), fixedwidth.NewReader(input, opts.FixedWidth), opts.FixedWidth); loadErr != nil {
+		_ = file.Close()
+		return nil, loadErr
+	}
+	return file, nil
+}
+
+// loadFixedWidth writes the header row and the records read by reader into the named worksheet of the empty
+// workbook file.
+func loadFixedWidth(file *excelize.File, sheet string, reader *fixedwidth.Reader, layout fixedwidth.Layout) error {
+	if err := createSheet(file, 0, sheet); err != nil {
+		return err
+	}
+	stream, streamErr := file.NewStreamWriter(sheet)
+	if streamErr != nil {
+		return streamErr
+	}
+	cells := make([]any, len(layout))
+	for index, name := range layout.Names() {
+		cells[index] = name
+	}
+	if err := stream.SetRow("A1", cells); err != nil {
+		return err
+	}
+	for rowNumber := 2; ; rowNumber++ {
+		values, readErr := reader.Read()
+		if errors.Is(readErr, io.EOF) {
+			break
+		}
+		if readErr != nil {
+			return readErr
+		}
+		for index, value := range values {
+			cells[index] = value
+		}
+		cell, _ := excelize.CoordinatesToCellName(1, rowNumber)
+		if err := stream.SetRow(cell, cells); err != nil {
+			return err
+		}
+	}
+	return stream.Flush()
+}
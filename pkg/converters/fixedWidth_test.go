@@ -0,0 +1,62 @@
+package converters
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"GoTools/pkg/fixedwidth"
+)
+
+func TestFixedWidth(t *testing.T) {
+	layout := fixedwidth.Layout{{Name: "ID", Start: 1, Width: 5}, {Name: "NAME", Start: 6, Width: 10}, {Name: "BALANCE", Start: 16, Width: 8}}
+	filePath := filepath.Join(t.TempDir(), "ACCOUNTS.DAT")
+	// ISO-8859-1 encodes é as the single byte 0xE9.
+	content := "00001Ren\xe9      00012.50\r\n00002Ann       00100.00\r\n"
+	if err := os.WriteFile(filePath, []byte(content), 0o644); err != nil {
+		t.Fatalf("Error writing test file: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		opts    CsvOptions
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "Latin1",
+			opts: CsvOptions{ReadOptions: ReadOptions{FixedWidth: layout, FixedWidthEncoding: "ISO-8859-1"}},
+			want: "ID,NAME,BALANCE\n00001,René,00012.50\n00002,Ann,00100.00\n",
+		},
+		{
+			name: "Selected Columns",
+			opts: CsvOptions{ReadOptions: ReadOptions{FixedWidth: layout, FixedWidthEncoding: "ISO-8859-1",
+				SkipFooterRows: 1, Columns: ColumnFilter{Include: []string{"ID", "BALANCE"}}}},
+			want: "ID,BALANCE\n00001,00012.50\n",
+		},
+		{
+			name:    "Invalid Layout",
+			opts:    CsvOptions{ReadOptions: ReadOptions{FixedWidth: fixedwidth.Layout{{Name: "ID", Start: 1}}}},
+			wantErr: true,
+		},
+		{
+			name:    "Unknown Encoding",
+			opts:    CsvOptions{ReadOptions: ReadOptions{FixedWidth: layout, FixedWidthEncoding: "EBCDIC-1"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output bytes.Buffer
+			err := XlsxToCsv(context.Background(), &output, filePath, tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("XlsxToCsv() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && output.String() != tt.want {
+				t.Errorf("XlsxToCsv() output = %q, want %q", output.String(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package converters
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+)
+
+// defaultIndent indents every level of the output by two spaces.
+const defaultIndent = "  "
+
+// validateFormat reports layout options that would add anything but whitespace between the elements.
+func (o XmlOptions) validateFormat() error {
+	if strings.Trim(o.Indent, " \t") != "" {
+		return errors.New("the indentation may only hold spaces and tabs")
+	}
+	if o.Newline != "" && o.Newline != "\n" && o.Newline != "\r\n" {
+		return errors.New(`the line break must be "\n" or "\r\n"`)
+	}
+	if o.Compact && len(o.Indent) > 0 {
+		return errors.New("compact output cannot be indented")
+	}
+	return nil
+}
+
+// crlfWriter turns the line feeds written to it into carriage return and line feed pairs.
+// Line feeds within values are escaped by the encoder, except in CDATA sections, where XML parsers
+// normalize the pair back into a line feed.
+type crlfWriter struct {
+	w io.Writer
+}
+
+func (c crlfWriter) Write(p []byte) (int, error) {
+	if _, err := c.w.Write(bytes.ReplaceAll(p, []byte("\n"), []byte("\r\n"))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
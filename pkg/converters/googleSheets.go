@@ -0,0 +1,118 @@
+package converters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"GoTools/pkg/gsheets"
+	"github.com/xuri/excelize/v2"
+)
+
+// GoogleSheet reads the input of a conversion from a Google Sheets spreadsheet instead of a file.
+// Client authenticates the requests and SpreadsheetID identifies the spreadsheet, as found in its URL.
+// Ranges lists the ranges to read in A1 notation, such as Orders!A1:F or just a sheet title, each loaded into
+// a worksheet named after its sheet; the first row of a range is its first row in the worksheet. Every sheet is
+// read when Ranges is empty, hidden sheets being hidden in the workbook too.
+type GoogleSheet struct {
+	Client        *gsheets.Client
+	SpreadsheetID string
+	Ranges        []string
+}
+
+// NewGoogleSheet returns the source reading the ranges of the spreadsheet, every sheet when there are none,
+// authenticating with the service account key file at keyFile.
+func NewGoogleSheet(keyFile, spreadsheetID string, ranges []string) (*GoogleSheet, error) {
+	account, accountErr := gsheets.LoadServiceAccount(keyFile)
+	if accountErr != nil {
+		return nil, accountErr
+	}
+	client, clientErr := gsheets.NewClient(account, nil)
+	if clientErr != nil {
+		return nil, clientErr
+	}
+	return &GoogleSheet{Client: client, SpreadsheetID: spreadsheetID, Ranges: ranges}, nil
+}
+
+// validate reports a source that cannot be read.
+func (g GoogleSheet) validate() error {
+	if g.Client == nil {
+		return errors.New("reading a Google Sheet requires a client")
+	}
+	if len(g.SpreadsheetID) < 1 {
+		return errors.New("reading a Google Sheet requires a spreadsheet ID")
+	}
+	return nil
+}
+
+// openGoogleSheet reads the ranges of the spreadsheet into an in-memory workbook, so it goes through the same
+// pipeline as a workbook file. Values are read as they are displayed in the spreadsheet, with their number
+// and date formats applied, and written as strings; path only names the workbook.
+func openGoogleSheet(ctx context.Context, path string, source GoogleSheet) (*excelize.File, error) {
+	if err := source.validate(); err != nil {
+		return nil, err
+	}
+	ranges := source.Ranges
+	hidden := make(map[string]bool)
+	if len(ranges) < 1 {
+		sheets, sheetsErr := source.Client.Sheets(ctx, source.SpreadsheetID)
+		if sheetsErr != nil {
+			return nil, sheetsErr
+		}
+		for _, sheet := range sheets {
+			ranges = append(ranges, gsheets.QuoteSheet(sheet.Title))
+			hidden[sheet.Title] = sheet.Hidden
+		}
+	}
+	if len(ranges) < 1 {
+		return nil, fmt.Errorf("the spreadsheet %s holds no sheets", source.SpreadsheetID)
+	}
+	valueRanges, valuesErr := source.Client.Values(ctx, source.SpreadsheetID, ranges...)
+	if valuesErr != nil {
+		return nil, valuesErr
+	}
+	file := excelize.NewFile()
+	file.Path = path
+	if loadErr := loadGoogleSheet(file, valueRanges, hidden); loadErr != nil {
+		_ = file.Close()
+		return nil, loadErr
+	}
+	return file, nil
+}
+
+// loadGoogleSheet writes every range into a worksheet of the empty workbook file, named after its sheet as far as
+// Excel allows, hiding the worksheets of the hidden sheets. Ranges of the same sheet get worksheets of their own.
+func loadGoogleSheet(file *excelize.File, valueRanges []gsheets.ValueRange, hidden map[string]bool) error {
+	used := make(map[string]bool, len(valueRanges))
+	for index, valueRange := range valueRanges {
+		sheet := validSheetName(valueRange.Sheet(), index, used)
+		used[strings.ToLower(sheet)] = true
+		if err := createSheet(file, index, sheet); err != nil {
+			return err
+		}
+		stream, streamErr := file.NewStreamWriter(sheet)
+		if streamErr != nil {
+			return streamErr
+		}
+		for rowIndex, row := range valueRange.Values {
+			cells := make([]any, len(row))
+			for columnIndex, value := range row {
+				cells[columnIndex] = value
+			}
+			cell, _ := excelize.CoordinatesToCellName(1, rowIndex+1)
+			if err := stream.SetRow(cell, cells); err != nil {
+				return err
+			}
+		}
+		if err := stream.Flush(); err != nil {
+			return err
+		}
+		if hidden[valueRange.Sheet()] {
+			if err := file.SetSheetVisible(sheet, false); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package converters
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+
+	"GoTools/pkg/fixedwidth"
+	"GoTools/pkg/gsheets"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestLoadGoogleSheet(t *testing.T) {
+	tests := []struct {
+		name       string
+		ranges     []gsheets.ValueRange
+		hidden     map[string]bool
+		wantRows   map[string][][]string
+		wantHidden []string
+		wantErr    bool
+	}{
+		{
+			name: "Sheets",
+			ranges: []gsheets.ValueRange{
+				{Range: "Orders!A1:B3", Values: [][]string{{"Name", "Qty"}, {"Ann", "1,200"}, {"Bob"}}},
+				{Range: "'Q1 ''Plan'''!A1:A2", Values: [][]string{{"Goal"}, {"Grow"}}},
+			},
+			hidden:     map[string]bool{"Q1 'Plan'": true},
+			wantRows:   map[string][][]string{"Orders": {{"Name", "Qty"}, {"Ann", "1,200"}, {"Bob"}}, "Q1 'Plan": {{"Goal"}, {"Grow"}}},
+			wantHidden: []string{"Q1 'Plan"},
+		},
+		{
+			name:     "Empty Range",
+			ranges:   []gsheets.ValueRange{{Range: "Orders!A1:B3"}},
+			wantRows: map[string][][]string{"Orders": {}},
+		},
+		{
+			name: "Same Sheet Twice",
+			ranges: []gsheets.ValueRange{{Range: "Orders!A1:A2", Values: [][]string{{"Name"}, {"Ann"}}},
+				{Range: "orders!D1:D2", Values: [][]string{{"Total"}, {"3"}}}},
+			wantRows: map[string][][]string{"Orders": {{"Name"}, {"Ann"}}, "orders (2)": {{"Total"}, {"3"}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := excelize.NewFile()
+			defer func() {
+				_ = file.Close()
+			}()
+			err := loadGoogleSheet(file, tt.ranges, tt.hidden)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("loadGoogleSheet() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			for sheet, want := range tt.wantRows {
+				rows, rowsErr := file.GetRows(sheet)
+				if rowsErr != nil {
+					t.Fatalf("GetRows(%s) error = %v", sheet, rowsErr)
+				}
+				if !reflect.DeepEqual(rows, want) {
+					t.Errorf("GetRows(%s) = %q, want %q", sheet, rows, want)
+				}
+			}
+			var hidden []string
+			for _, sheet := range file.GetSheetList() {
+				if visible, _ := file.GetSheetVisible(sheet); !visible {
+					hidden = append(hidden, sheet)
+				}
+			}
+			if !reflect.DeepEqual(hidden, tt.wantHidden) {
+				t.Errorf("hidden worksheets = %v, want %v", hidden, tt.wantHidden)
+			}
+		})
+	}
+}
+
+func TestGoogleSheetValidate(t *testing.T) {
+	var output bytes.Buffer
+	tests := []struct {
+		name string
+		opts ReadOptions
+	}{
+		{name: "No Client", opts: ReadOptions{GoogleSheet: &GoogleSheet{SpreadsheetID: "sheet-id"}}},
+		{name: "No Spreadsheet", opts: ReadOptions{GoogleSheet: &GoogleSheet{Client: &gsheets.Client{}}}},
+		{name: "Fixed Width", opts: ReadOptions{GoogleSheet: &GoogleSheet{Client: &gsheets.Client{}, SpreadsheetID: "sheet-id"},
+			FixedWidth: fixedwidth.Layout{{Name: "ID", Start: 1, Width: 5}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := XlsxToCsv(context.Background(), &output, "sheet-id", CsvOptions{ReadOptions: tt.opts}); err == nil {
+				t.Errorf("XlsxToCsv() accepted an invalid Google Sheet source")
+			}
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package converters
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	. "GoTools/pkg/helpers"
+)
+
+// HeaderMatchKind tells how a header was matched to a canonical column.
+type HeaderMatchKind int
+
+const (
+	// ExactMatch matched the name of the column or one of its aliases as written.
+	ExactMatch HeaderMatchKind = iota
+	// CaseInsensitiveMatch matched it regardless of case and of surrounding spaces.
+	CaseInsensitiveMatch
+	// FuzzyMatch matched it approximately, see FindBestMatch.
+	FuzzyMatch
+)
+
+// String returns exact, case-insensitive or fuzzy.
+func (k HeaderMatchKind) String() string {
+	switch k {
+	case CaseInsensitiveMatch:
+		return "case-insensitive"
+	case FuzzyMatch:
+		return "fuzzy"
+	}
+	return "exact"
+}
+
+// CanonicalColumn is a column name incoming headers are renamed to: Name itself or any of Aliases, such as
+// "Customer" for the headers "Client" or "Cust. Name", matches it. The mapping reports it as missing when no
+// header matches it, unless Optional is set.
+type CanonicalColumn struct {
+	Name     string   `json:"name"`
+	Aliases  []string `json:"aliases,omitempty"`
+	Optional bool     `json:"optional,omitempty"`
+}
+
+// HeaderMapping renames the headers of a worksheet to the canonical set of Columns, so workbooks from different
+// sources convert to the same columns.
+// Headers are first matched exactly, then regardless of case, and then, when Fuzzy is above 0, to the most
+// similar name or alias at least Fuzzy similar, from 0 to 1; every column is matched by one header at most.
+// Headers matching no column keep their name.
+// Report, when set, is called with the outcome of the mapping of every worksheet; a non-nil error aborts
+// the conversion, so it can fail on missing columns.
+type HeaderMapping struct {
+	Columns []CanonicalColumn                                    `json:"columns"`
+	Fuzzy   float64                                              `json:"fuzzy,omitempty"`
+	Report  func(sheet string, result HeaderMappingResult) error `json:"-" yaml:"-"`
+}
+
+// HeaderMatch is a header renamed to the canonical column Column. Score is the similarity of a fuzzy match.
+type HeaderMatch struct {
+	Header string          `json:"header"`
+	Column string          `json:"column"`
+	Kind   HeaderMatchKind `json:"-"`
+	Score  float64         `json:"score,omitempty"`
+}
+
+// HeaderMappingResult is the outcome of a HeaderMapping: the headers matched, in the order of the headers,
+// those matching no column, and the non-optional columns no header matched.
+type HeaderMappingResult struct {
+	Matches  []HeaderMatch `json:"matches,omitempty"`
+	Unmapped []string      `json:"unmapped,omitempty"`
+	Missing  []string      `json:"missing,omitempty"`
+}
+
+// validate reports mappings naming a column twice or with a similarity threshold out of range.
+func (m HeaderMapping) validate() error {
+	if m.Fuzzy < 0 || m.Fuzzy > 1 {
+		return fmt.Errorf("the fuzzy header similarity must be between 0 and 1, got %v", m.Fuzzy)
+	}
+	names := make(map[string]bool)
+	for _, column := range m.Columns {
+		if len(strings.TrimSpace(column.Name)) < 1 {
+			return fmt.Errorf("a canonical column has no name")
+		}
+		if names[column.Name] {
+			return fmt.Errorf("the canonical column '%s' is defined twice", column.Name)
+		}
+		names[column.Name] = true
+	}
+	return nil
+}
+
+// Map returns header with every header matching a canonical column renamed to it, and the outcome of the mapping.
+// header is not modified.
+// Example usage:
+//
+//	mapping := HeaderMapping{Columns: []CanonicalColumn{{Name: "Customer", Aliases: []string{"Client"}}}}
+//	mapping.Map([]string{"CLIENT", "Amount"})
+//	// Output: [Customer Amount], {Matches: [{CLIENT Customer case-insensitive 0}], Unmapped: [Amount]}
+func (m HeaderMapping) Map(header []string) ([]string, HeaderMappingResult) {
+	mapped := append([]string(nil), header...)
+	matches := make([]*HeaderMatch, len(header))
+	claimed := make([]bool, len(m.Columns))
+	spellings := func(column CanonicalColumn) []string {
+		return append([]string{column.Name}, column.Aliases...)
+	}
+	pass := func(kind HeaderMatchKind, equal func(a, b string) bool) {
+		for index, name := range header {
+			if matches[index] != nil {
+				continue
+			}
+		columns:
+			for position, column := range m.Columns {
+				if claimed[position] {
+					continue
+				}
+				for _, spelling := range spellings(column) {
+					if equal(name, spelling) {
+						matches[index] = &HeaderMatch{Header: name, Column: column.Name, Kind: kind}
+						claimed[position] = true
+						break columns
+					}
+				}
+			}
+		}
+	}
+	pass(ExactMatch, func(a, b string) bool { return a == b })
+	pass(CaseInsensitiveMatch, func(a, b string) bool { return strings.EqualFold(strings.TrimSpace(a), strings.TrimSpace(b)) })
+	if m.Fuzzy > 0 {
+		for index, name := range header {
+			if matches[index] != nil || len(strings.TrimSpace(name)) < 1 {
+				continue
+			}
+			var candidates []string
+			owners := make(map[string]int)
+			for position, column := range m.Columns {
+				if claimed[position] {
+					continue
+				}
+				for _, spelling := range spellings(column) {
+					if _, known := owners[spelling]; !known {
+						owners[spelling] = position
+						candidates = append(candidates, spelling)
+					}
+				}
+			}
+			if spelling, score, found := FindBestMatch(name, candidates, m.Fuzzy); found {
+				position := owners[spelling]
+				matches[index] = &HeaderMatch{Header: name, Column: m.Columns[position].Name, Kind: FuzzyMatch, Score: score}
+				claimed[position] = true
+			}
+		}
+	}
+
+	var result HeaderMappingResult
+	for index, match := range matches {
+		if match == nil {
+			result.Unmapped = append(result.Unmapped, header[index])
+			continue
+		}
+		mapped[index] = match.Column
+		result.Matches = append(result.Matches, *match)
+	}
+	for position, column := range m.Columns {
+		if !claimed[position] && !column.Optional {
+			result.Missing = append(result.Missing, column.Name)
+		}
+	}
+	return mapped, result
+}
+
+// ParseHeaderMapping reads a HeaderMapping from its JSON form, such as
+// {"fuzzy": 0.9, "columns": [{"name": "Customer", "aliases": ["Client"]}, {"name": "Notes", "optional": true}]}.
+func ParseHeaderMapping(r io.Reader) (HeaderMapping, error) {
+	var mapping HeaderMapping
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&mapping); err != nil {
+		return HeaderMapping{}, fmt.Errorf("invalid header mapping: %w", err)
+	}
+	return mapping, mapping.validate()
+}
+
+// LoadHeaderMapping reads the header mapping file at path, see ParseHeaderMapping.
+func LoadHeaderMapping(path string) (mapping HeaderMapping, loadErr error) {
+	file, openErr := os.Open(path)
+	if openErr != nil {
+		return HeaderMapping{}, openErr
+	}
+	defer func(file *os.File) {
+		if err := file.Close(); err != nil && loadErr == nil {
+			loadErr = err
+		}
+	}(file)
+	return ParseHeaderMapping(file)
+}
+
+// ReportHeaderMapping is a HeaderMapping Report logging the headers of a worksheet matching no canonical column
+// and failing the conversion of worksheets missing some.
+func ReportHeaderMapping(sheet string, result HeaderMappingResult) error {
+	if len(result.Unmapped) > 0 {
+		Logger().Warn("Headers matching no canonical column", "sheet", sheet, "headers", result.Unmapped)
+	}
+	if len(result.Missing) > 0 {
+		return fmt.Errorf("worksheet '%s' has no header for the columns %s", sheet, strings.Join(result.Missing, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package converters
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestHeaderMappingMap(t *testing.T) {
+	mapping := HeaderMapping{
+		Columns: []CanonicalColumn{
+			{Name: "Customer", Aliases: []string{"Client", "Cust. Name"}},
+			{Name: "Amount", Aliases: []string{"Total"}},
+			{Name: "Invoice Date", Aliases: []string{"Date"}},
+			{Name: "Notes", Optional: true},
+			{Name: "Currency"},
+		},
+		Fuzzy: 0.9,
+	}
+	header := []string{"client", "Totl", "Invoice date ", "Region", "Amount"}
+	got, result := mapping.Map(header)
+	if want := []string{"Customer", "Totl", "Invoice Date", "Region", "Amount"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Map() = %v, want %v", got, want)
+	}
+	wantMatches := []HeaderMatch{
+		{Header: "client", Column: "Customer", Kind: CaseInsensitiveMatch},
+		{Header: "Invoice date ", Column: "Invoice Date", Kind: CaseInsensitiveMatch},
+		{Header: "Amount", Column: "Amount", Kind: ExactMatch},
+	}
+	if !reflect.DeepEqual(result.Matches, wantMatches) {
+		t.Errorf("Map() matches = %+v, want %+v", result.Matches, wantMatches)
+	}
+	if want := []string{"Totl", "Region"}; !reflect.DeepEqual(result.Unmapped, want) {
+		t.Errorf("Map() unmapped = %v, want %v", result.Unmapped, want)
+	}
+	if want := []string{"Currency"}; !reflect.DeepEqual(result.Missing, want) {
+		t.Errorf("Map() missing = %v, want %v", result.Missing, want)
+	}
+	if header[0] != "client" {
+		t.Errorf("Map() modified its input: %v", header)
+	}
+
+	got, result = mapping.Map([]string{"Custmer Name", "Totals", "Curency"})
+	if want := []string{"Customer", "Amount", "Currency"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Map() fuzzy = %v, want %v", got, want)
+	}
+	for _, match := range result.Matches {
+		if match.Kind != FuzzyMatch || match.Score < mapping.Fuzzy {
+			t.Errorf("Map() fuzzy match = %+v", match)
+		}
+	}
+}
+
+func TestParseHeaderMapping(t *testing.T) {
+	mapping, err := ParseHeaderMapping(strings.NewReader(`{"fuzzy": 0.85, "columns": [{"name": "Customer", "aliases": ["Client"]}, {"name": "Notes", "optional": true}]}`))
+	if err != nil {
+		t.Fatalf("ParseHeaderMapping() error = %v", err)
+	}
+	want := HeaderMapping{Fuzzy: 0.85, Columns: []CanonicalColumn{{Name: "Customer", Aliases: []string{"Client"}}, {Name: "Notes", Optional: true}}}
+	if !reflect.DeepEqual(mapping, want) {
+		t.Errorf("ParseHeaderMapping() = %+v, want %+v", mapping, want)
+	}
+	for input, wantErr := range map[string]string{
+		`{"columns": [{"name": "A"}, {"name": "A"}]}`: "the canonical column 'A' is defined twice",
+		`{"fuzzy": 2, "columns": []}`:                 "between 0 and 1",
+		`{"column": []}`:                              "invalid header mapping",
+	} {
+		if _, err := ParseHeaderMapping(strings.NewReader(input)); err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Errorf("ParseHeaderMapping(%s) error = %v, want %s", input, err, wantErr)
+		}
+	}
+}
+
+func TestXlsxToJsonHeaderMapping(t *testing.T) {
+	filePath := createTestWorkbook(t, map[string][][]any{
+		"Invoices": {{"CLIENT", "Total", "Region"}, {"Acme", "10", "North"}, {"Initech", "20", "South"}},
+	}, "Invoices")
+
+	var reported []HeaderMappingResult
+	mapping := &HeaderMapping{
+		Columns: []CanonicalColumn{{Name: "Customer", Aliases: []string{"Client"}}, {Name: "Amount", Aliases: []string{"Total"}}},
+		Report: func(sheet string, result HeaderMappingResult) error {
+			reported = append(reported, result)
+			return nil
+		},
+	}
+	var output bytes.Buffer
+	opts := JsonOptions{ReadOptions: ReadOptions{HeaderMapping: mapping, RowFilter: func(record map[string]string) bool { return record["Customer"] != "Initech" }}, Lines: true}
+	if err := XlsxToJson(context.Background(), &output, filePath, opts); err != nil {
+		t.Fatalf("XlsxToJson() error = %v", err)
+	}
+	if want := `{"Customer":"Acme","Amount":"10","Region":"North"}` + "\n"; output.String() != want {
+		t.Errorf("XlsxToJson() = %s, want %s", output.String(), want)
+	}
+	if len(reported) != 1 || !reflect.DeepEqual(reported[0].Unmapped, []string{"Region"}) {
+		t.Errorf("Report() got %+v", reported)
+	}
+
+	errMissing := errors.New("missing columns")
+	mapping.Columns = append(mapping.Columns, CanonicalColumn{Name: "Currency"})
+	mapping.Report = func(sheet string, result HeaderMappingResult) error {
+		if len(result.Missing) > 0 {
+			return errMissing
+		}
+		return nil
+	}
+	if err := XlsxToJson(context.Background(), &output, filePath, opts); !errors.Is(err, errMissing) {
+		t.Errorf("XlsxToJson() error = %v, want %v", err, errMissing)
+	}
+}
@@ -0,0 +1,115 @@
+package converters
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	. "GoTools/pkg/helpers"
+)
+
+// defaultHttpBatchSize is the number of records an HttpSink sends per request when HttpOptions.BatchSize is not set.
+const defaultHttpBatchSize = 100
+
+// HttpOptions configures sending records to an HTTP endpoint.
+// Method is the method of the requests, POST by default, and Headers are added to every request, such as an
+// Authorization header. BatchSize is the number of records sent per request, 100 by default.
+// InferTypes writes the values as XlsxToJson does with InferTypes. Client sends the requests,
+// http.DefaultClient by default.
+type HttpOptions struct {
+	Method     string
+	Headers    map[string]string
+	BatchSize  int
+	InferTypes bool
+	Client     *http.Client
+}
+
+// httpSink sends records to an HTTP endpoint.
+type httpSink struct {
+	ctx    context.Context
+	url    string
+	opts   HttpOptions
+	header []string
+	batch  []string
+}
+
+// HttpSink returns a RowSink sending the records to the endpoint at url, in batches of opts.BatchSize records,
+// each the body of a request holding a JSON array of objects keyed by column name. Every response but a 2xx
+// status fails the conversion. The requests are made with ctx.
+func HttpSink(ctx context.Context, url string, opts HttpOptions) RowSink {
+	return &httpSink{ctx: ctx, url: url, opts: opts}
+}
+
+func (s *httpSink) WriteHeader(header []string) error {
+	if s.opts.BatchSize < 0 {
+		return fmt.Errorf("invalid batch size %d", s.opts.BatchSize)
+	}
+	s.header = RenameDuplicates(header, false)
+	return nil
+}
+
+func (s *httpSink) WriteRecord(record []string) error {
+	cells := JsonOptions{InferTypes: s.opts.InferTypes}
+	var object strings.Builder
+	object.WriteString("{")
+	for index, name := range s.header {
+		if index > 0 {
+			object.WriteString(",")
+		}
+		object.WriteString(jsonString(name) + ":")
+		object.Write(cells.jsonCell(record, index))
+	}
+	object.WriteString("}")
+	batchSize := s.opts.BatchSize
+	if batchSize < 1 {
+		batchSize = defaultHttpBatchSize
+	}
+	if s.batch = append(s.batch, object.String()); len(s.batch) < batchSize {
+		return nil
+	}
+	return s.send()
+}
+
+func (s *httpSink) Close() error {
+	if len(s.batch) < 1 {
+		return nil
+	}
+	return s.send()
+}
+
+// send sends the records of the batch and empties it.
+func (s *httpSink) send() error {
+	method := s.opts.Method
+	if len(method) < 1 {
+		method = http.MethodPost
+	}
+	body := "[" + strings.Join(s.batch, ",") + "]"
+	s.batch = s.batch[:0]
+	request, requestErr := http.NewRequestWithContext(s.ctx, method, s.url, bytes.NewBufferString(body))
+	if requestErr != nil {
+		return requestErr
+	}
+	request.Header.Set("Content-Type", "application/json")
+	for name, value := range s.opts.Headers {
+		request.Header.Set(name, value)
+	}
+	client := s.opts.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	response, responseErr := client.Do(request)
+	if responseErr != nil {
+		return responseErr
+	}
+	defer func(body io.ReadCloser) {
+		_ = body.Close()
+	}(response.Body)
+	_, _ = io.Copy(io.Discard, response.Body)
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("%s %s: %s", method, s.url, response.Status)
+	}
+	return nil
+}
@@ -0,0 +1,18 @@
+package converters
+
+import "GoTools/pkg/datatable"
+
+// InferenceOptions configures inferring the type of a column from its values; see datatable.InferenceOptions.
+type InferenceOptions = datatable.InferenceOptions
+
+// TypeInference is the type inferred for a column; see datatable.TypeInference.
+type TypeInference = datatable.TypeInference
+
+// TypeInferrer infers the type of a column from the values added to it, as tables read with InferTypes do;
+// see datatable.TypeInferrer.
+type TypeInferrer = datatable.TypeInferrer
+
+// NewTypeInferrer returns a TypeInferrer without any value.
+func NewTypeInferrer(opts InferenceOptions) *TypeInferrer {
+	return datatable.NewTypeInferrer(opts)
+}
@@ -0,0 +1,24 @@
+package converters
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestXlsxToSqlSampledTypes(t *testing.T) {
+	filePath := createTestWorkbook(t, map[string][][]any{
+		"Scores": {{"Id", "Score"}, {1, 10}, {2, 20}, {3, "n/a"}},
+	}, "Scores")
+
+	var script strings.Builder
+	err := XlsxToSql(context.Background(), &script, filePath, SqlOptions{InferTypes: true, Inference: InferenceOptions{SampleSize: 2}})
+	if err == nil || !strings.Contains(err.Error(), "'n/a' in column 'Score' is not a valid BIGINT") {
+		t.Errorf("XlsxToSql() error = %v, want the value beyond the sample rejected", err)
+	}
+
+	err = XlsxToSql(context.Background(), &script, filePath, SqlOptions{InferTypes: true, Inference: InferenceOptions{MinConfidence: 2}})
+	if err == nil || err.Error() != "confidence 2 is not between 0 and 1" {
+		t.Errorf("XlsxToSql() error = %v, want the confidence rejected", err)
+	}
+}
@@ -0,0 +1,364 @@
+package converters
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// ArrayMode selects how JSON arrays are laid out in a worksheet.
+type ArrayMode int
+
+const (
+	// JoinArrays writes the elements of an array into a single cell, separated by the array delimiter.
+	// The values of the same field of an array of objects are joined likewise. It is the default.
+	JoinArrays ArrayMode = iota
+	// ExplodeArrays repeats the record in a row per element of the array.
+	// A record holding several arrays gets a row per combination of their elements.
+	ExplodeArrays
+)
+
+// ParseArrayMode returns the ArrayMode called join or explode.
+func ParseArrayMode(name string) (ArrayMode, error) {
+	switch name {
+	case "", "join":
+		return JoinArrays, nil
+	case "explode":
+		return ExplodeArrays, nil
+	}
+	return JoinArrays, fmt.Errorf("unknown array mode '%s'", name)
+}
+
+// JsonToXlsxOptions configures building a workbook from JSON records.
+// SheetName names the worksheet, after the JSON file by default.
+// Nested objects are flattened into a column per field, named by the path to the field with the keys joined by
+// Separator, "." by default. Arrays are laid out as selected by Arrays, joined by ArrayDelimiter, "; " by default.
+// The columns appear in the order their fields are first met; a top-level value that is not an object is written
+// into a column called value.
+// StyleHeader, AutoWidth and CoerceTypes work as in CsvToXlsxOptions, CoerceTypes only applying to JSON strings;
+// JSON numbers and booleans are always written as such, except integers with more digits than Excel keeps.
+type JsonToXlsxOptions struct {
+	SheetName      string
+	Separator      string
+	Arrays         ArrayMode
+	ArrayDelimiter string
+	StyleHeader    bool
+	AutoWidth      bool
+	CoerceTypes    bool
+}
+
+// separators returns the path separator and the array delimiter, falling back to their defaults.
+func (o JsonToXlsxOptions) separators() (separator, delimiter string) {
+	separator, delimiter = o.Separator, o.ArrayDelimiter
+	if len(separator) < 1 {
+		separator = "."
+	}
+	if len(delimiter) < 1 {
+		delimiter = "; "
+	}
+	return separator, delimiter
+}
+
+// JsonToXlsx builds the .xlsx workbook at outPath holding the records of the JSON file at path in a worksheet.
+// The file holds either an array of records or newline delimited JSON, one record per line.
+// The file is read twice, once to collect the columns and once to write the records, so it is never held in memory.
+// The conversion stops with the error of ctx as soon as it is cancelled or its deadline passes.
+func JsonToXlsx(ctx context.Context, outPath, path string, opts JsonToXlsxOptions) (convertErr error) {
+	file := excelize.NewFile()
+	defer func(file *excelize.File) {
+		if err := file.Close(); err != nil && convertErr == nil {
+			convertErr = err
+		}
+	}(file)
+
+	styles, stylesErr := newWorkbookStyles(file)
+	if stylesErr != nil {
+		return stylesErr
+	}
+	sheet := opts.SheetName
+	if len(sheet) < 1 {
+		sheet = sheetNameFor(path, 0, nil)
+	}
+	if err := createSheet(file, 0, sheet); err != nil {
+		return err
+	}
+	if err := opts.writeSheet(ctx, file, sheet, path, styles); err != nil {
+		return err
+	}
+	return file.SaveAs(outPath)
+}
+
+// writeSheet streams the flattened records of the JSON file at path into the named worksheet.
+func (o JsonToXlsxOptions) writeSheet(ctx context.Context, file *excelize.File, sheet, path string, styles workbookStyles) error {
+	layout := newSheetLayout(sheet)
+	columnErr := o.eachJsonRow(ctx, path, func(row flatRow) error {
+		layout.add(row, o.AutoWidth)
+		return nil
+	})
+	if columnErr != nil {
+		return columnErr
+	}
+
+	stream, streamErr := file.NewStreamWriter(sheet)
+	if streamErr != nil {
+		return streamErr
+	}
+	if err := layout.writeHeader(stream, styles, o.StyleHeader, o.AutoWidth); err != nil {
+		return err
+	}
+	rowNumber := 1
+	rowErr := o.eachJsonRow(ctx, path, func(row flatRow) error {
+		rowNumber++
+		values := layout.values(row, func(value any) any {
+			return o.cellValue(value, styles)
+		})
+		cellName, _ := excelize.CoordinatesToCellName(1, rowNumber)
+		return stream.SetRow(cellName, values)
+	})
+	if rowErr != nil {
+		return rowErr
+	}
+	return stream.Flush()
+}
+
+// cellValue returns the value a flattened JSON value is written as.
+func (o JsonToXlsxOptions) cellValue(value any, styles workbookStyles) any {
+	switch typed := value.(type) {
+	case json.Number:
+		digits := strings.TrimLeft(typed.String(), "-")
+		if integer, err := typed.Int64(); err == nil && len(digits) <= maxSignificantDigits {
+			return integer
+		}
+		if strings.ContainsAny(digits, ".eE") {
+			number, _ := typed.Float64()
+			return number
+		}
+		return typed.String()
+	case string:
+		if o.CoerceTypes && len(typed) > 0 {
+			return coerceCell(typed, styles)
+		}
+	}
+	return value
+}
+
+// jsonField is a field of a JSON object, which keeps its fields in their order.
+type jsonField struct {
+	key   string
+	value any
+}
+
+// jsonObject is a JSON object decoded in field order.
+type jsonObject []jsonField
+
+// eachJsonRow hands every row of the flattened records of the JSON file at path to yield.
+func (o JsonToXlsxOptions) eachJsonRow(ctx context.Context, path string, yield func(row flatRow) error) (readErr error) {
+	jsonFile, openErr := os.Open(path)
+	if openErr != nil {
+		return openErr
+	}
+	defer func(jsonFile *os.File) {
+		if err := jsonFile.Close(); err != nil && readErr == nil {
+			readErr = err
+		}
+	}(jsonFile)
+
+	decoder := json.NewDecoder(bufio.NewReader(jsonFile))
+	decoder.UseNumber()
+	emit := func(record any) error {
+		for _, row := range o.flatten(record, "") {
+			if err := yield(row); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for first := true; ; first = false {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		token, tokenErr := decoder.Token()
+		if tokenErr == io.EOF {
+			return nil
+		}
+		if tokenErr != nil {
+			return tokenErr
+		}
+		if first && token == json.Delim('[') {
+			return eachJsonElement(ctx, decoder, emit)
+		}
+		record, decodeErr := decodeJsonValue(decoder, token)
+		if decodeErr != nil {
+			return decodeErr
+		}
+		if err := emit(record); err != nil {
+			return err
+		}
+	}
+}
+
+// eachJsonElement hands every element of the array whose opening bracket decoder has just read to yield.
+func eachJsonElement(ctx context.Context, decoder *json.Decoder, yield func(record any) error) error {
+	for decoder.More() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		token, tokenErr := decoder.Token()
+		if tokenErr != nil {
+			return tokenErr
+		}
+		record, decodeErr := decodeJsonValue(decoder, token)
+		if decodeErr != nil {
+			return decodeErr
+		}
+		if err := yield(record); err != nil {
+			return err
+		}
+	}
+	if _, err := decoder.Token(); err != nil {
+		return err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return errors.New("unexpected data after the JSON array")
+	}
+	return nil
+}
+
+// decodeJsonValue decodes the JSON value starting with token, keeping the fields of objects in their order.
+func decodeJsonValue(decoder *json.Decoder, token json.Token) (any, error) {
+	switch token {
+	case json.Delim('{'):
+		var object jsonObject
+		for decoder.More() {
+			key, keyErr := decoder.Token()
+			if keyErr != nil {
+				return nil, keyErr
+			}
+			value, valueErr := decodeNextJsonValue(decoder)
+			if valueErr != nil {
+				return nil, valueErr
+			}
+			object = append(object, jsonField{key: key.(string), value: value})
+		}
+		_, err := decoder.Token()
+		return object, err
+	case json.Delim('['):
+		array := []any{}
+		for decoder.More() {
+			value, valueErr := decodeNextJsonValue(decoder)
+			if valueErr != nil {
+				return nil, valueErr
+			}
+			array = append(array, value)
+		}
+		_, err := decoder.Token()
+		return array, err
+	}
+	return token, nil
+}
+
+// decodeNextJsonValue decodes the next JSON value of decoder.
+func decodeNextJsonValue(decoder *json.Decoder) (any, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	return decodeJsonValue(decoder, token)
+}
+
+// flatten returns the rows a JSON value found at path lays out into, a single row unless arrays are exploded.
+func (o JsonToXlsxOptions) flatten(value any, path string) []flatRow {
+	separator, delimiter := o.separators()
+	switch typed := value.(type) {
+	case jsonObject:
+		rows := []flatRow{{}}
+		for _, field := range typed {
+			column := field.key
+			if len(path) > 0 {
+				column = path + separator + field.key
+			}
+			rows = combineRows(rows, o.flatten(field.value, column))
+		}
+		return rows
+	case []any:
+		if o.Arrays == ExplodeArrays {
+			var rows []flatRow
+			for _, element := range typed {
+				rows = append(rows, o.flatten(element, path)...)
+			}
+			if len(rows) < 1 {
+				return []flatRow{{}}
+			}
+			return rows
+		}
+		return []flatRow{o.joinElements(typed, path, delimiter)}
+	}
+	if len(path) < 1 {
+		path = "value"
+	}
+	return []flatRow{{{column: path, value: value}}}
+}
+
+// joinElements flattens the elements of an array found at path into a single row,
+// joining the values of every column with delimiter.
+func (o JsonToXlsxOptions) joinElements(elements []any, path, delimiter string) flatRow {
+	var joined flatRow
+	indexes := make(map[string]int)
+	parts := make(map[string][]string)
+	for _, element := range elements {
+		for _, row := range o.flatten(element, path) {
+			for _, cell := range row {
+				if _, known := indexes[cell.column]; !known {
+					indexes[cell.column] = len(joined)
+					joined = append(joined, flatCell{column: cell.column})
+				}
+				if cell.value != nil {
+					parts[cell.column] = append(parts[cell.column], jsonText(cell.value))
+				}
+			}
+		}
+	}
+	for index, cell := range joined {
+		if values := parts[cell.column]; len(values) > 0 {
+			joined[index].value = strings.Join(values, delimiter)
+		}
+	}
+	return joined
+}
+
+// jsonText returns the text of a JSON scalar.
+func jsonText(value any) string {
+	switch typed := value.(type) {
+	case string:
+		return typed
+	case bool:
+		return strconv.FormatBool(typed)
+	}
+	return fmt.Sprint(value)
+}
+
+// combineRows returns a row for every combination of a row of rows followed by a row of next.
+func combineRows(rows, next []flatRow) []flatRow {
+	if len(next) == 1 {
+		for index := range rows {
+			rows[index] = append(rows[index], next[0]...)
+		}
+		return rows
+	}
+	combined := make([]flatRow, 0, len(rows)*len(next))
+	for _, row := range rows {
+		for _, tail := range next {
+			joined := make(flatRow, 0, len(row)+len(tail))
+			combined = append(combined, append(append(joined, row...), tail...))
+		}
+	}
+	return combined
+}
@@ -0,0 +1,97 @@
+package converters
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestJsonToXlsx(t *testing.T) {
+	dir := t.TempDir()
+	array := filepath.Join(dir, "orders.json")
+	content := `[
+		{"id": 1, "customer": {"name": "Ann", "address": {"city": "Oslo"}}, "tags": ["new", "vip"],
+		 "items": [{"sku": "A1", "qty": 2}, {"sku": "B2", "qty": 1}]},
+		{"id": 12345678901234567, "customer": {"name": "Bob"}, "paid": true, "total": 9.5, "note": null}
+	]`
+	if err := os.WriteFile(array, []byte(content), 0o644); err != nil {
+		t.Fatalf("Error writing JSON file: %v", err)
+	}
+	lines := filepath.Join(dir, "events.ndjson")
+	if err := os.WriteFile(lines, []byte("{\"at\": \"2024-01-31\"}\n{\"at\": \"01-02-24\", \"extra\": 3}\n"), 0o644); err != nil {
+		t.Fatalf("Error writing JSON file: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		path  string
+		opts  JsonToXlsxOptions
+		sheet string
+		want  [][]string
+	}{
+		{
+			name:  "Joined Arrays",
+			path:  array,
+			opts:  JsonToXlsxOptions{},
+			sheet: "orders",
+			want: [][]string{
+				{"id", "customer.name", "customer.address.city", "tags", "items.sku", "items.qty", "paid", "total", "note"},
+				{"1", "Ann", "Oslo", "new; vip", "A1; B2", "2; 1"},
+				{"12345678901234567", "Bob", "", "", "", "", "TRUE", "9.5"},
+			},
+		},
+		{
+			name:  "Exploded Arrays",
+			path:  array,
+			opts:  JsonToXlsxOptions{SheetName: "Lines", Separator: "/", Arrays: ExplodeArrays},
+			sheet: "Lines",
+			want: [][]string{
+				{"id", "customer/name", "customer/address/city", "tags", "items/sku", "items/qty", "paid", "total", "note"},
+				{"1", "Ann", "Oslo", "new", "A1", "2"},
+				{"1", "Ann", "Oslo", "new", "B2", "1"},
+				{"1", "Ann", "Oslo", "vip", "A1", "2"},
+				{"1", "Ann", "Oslo", "vip", "B2", "1"},
+				{"12345678901234567", "Bob", "", "", "", "", "TRUE", "9.5"},
+			},
+		},
+		{
+			name:  "Newline Delimited",
+			path:  lines,
+			opts:  JsonToXlsxOptions{CoerceTypes: true, StyleHeader: true},
+			sheet: "events",
+			want:  [][]string{{"at", "extra"}, {"01-31-24"}, {"1/2/24 00:00", "3"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outPath := filepath.Join(dir, "out.xlsx")
+			if err := JsonToXlsx(context.Background(), outPath, tt.path, tt.opts); err != nil {
+				t.Fatalf("JsonToXlsx() error = %v", err)
+			}
+			file, openErr := excelize.OpenFile(outPath)
+			if openErr != nil {
+				t.Fatalf("Error opening workbook: %v", openErr)
+			}
+			defer file.Close()
+			rows, _ := file.GetRows(tt.sheet)
+			if !reflect.DeepEqual(rows, tt.want) {
+				t.Errorf("JsonToXlsx() rows = %q, want %q", rows, tt.want)
+			}
+		})
+	}
+
+	broken := filepath.Join(dir, "broken.json")
+	if err := os.WriteFile(broken, []byte(`[{"id": 1}, {"id":`), 0o644); err != nil {
+		t.Fatalf("Error writing JSON file: %v", err)
+	}
+	if err := JsonToXlsx(context.Background(), filepath.Join(dir, "out.xlsx"), broken, JsonToXlsxOptions{}); err == nil {
+		t.Errorf("JsonToXlsx() accepted truncated JSON")
+	}
+	if _, err := ParseArrayMode("spread"); err == nil {
+		t.Errorf("ParseArrayMode() accepted an unknown mode")
+	}
+}
@@ -0,0 +1,21 @@
+package converters
+
+import "encoding/xml"
+
+// linkSuffix is appended to the name of a column to name the sibling holding its hyperlink targets.
+const linkSuffix = "_link"
+
+// writeLinks adds a sibling holding the hyperlink target after every column of the row that is a link column.
+func (o XmlOptions) writeLinks(dataRow *DataRow) {
+	columns := make([]DataColumn, 0, len(dataRow.Columns))
+	for _, column := range dataRow.Columns {
+		columns = append(columns, column)
+		if column.linked {
+			columns = append(columns, DataColumn{
+				XMLName: xml.Name{Local: column.XMLName.Local + linkSuffix},
+				Value:   column.link,
+			})
+		}
+	}
+	dataRow.Columns = columns
+}
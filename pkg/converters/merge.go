@@ -0,0 +1,286 @@
+package converters
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	. "GoTools/pkg/helpers"
+	"github.com/xuri/excelize/v2"
+)
+
+// HeaderMode selects how the columns of merged worksheets are reconciled.
+type HeaderMode int
+
+const (
+	// UnionHeaders keeps every column met in any worksheet, in the order they are first met, leaving the cells
+	// of the worksheets lacking a column empty. It is the default.
+	UnionHeaders HeaderMode = iota
+	// IntersectHeaders only keeps the columns every worksheet holds, in the order of the first worksheet.
+	IntersectHeaders
+	// StrictHeaders fails the merge when a worksheet does not hold the columns of the first one.
+	StrictHeaders
+)
+
+// ParseHeaderMode returns the HeaderMode called union, intersect or strict.
+func ParseHeaderMode(name string) (HeaderMode, error) {
+	switch name {
+	case "", "union":
+		return UnionHeaders, nil
+	case "intersect":
+		return IntersectHeaders, nil
+	case "strict":
+		return StrictHeaders, nil
+	}
+	return UnionHeaders, fmt.Errorf("unknown header mode '%s'", name)
+}
+
+// MergeOptions configures merging the worksheets of several workbooks into a single table.
+// Sheets selects the worksheets merged from every workbook, and Headers how their columns are reconciled.
+// Column names match regardless of case and of surrounding and repeated spaces, so "Unit Price" matches
+// "unit  price"; HeaderAliases maps other names onto the name of a column, such as "Qty" onto "Quantity",
+// and the merged column takes that name. Other merged columns are named as in the first worksheet holding them.
+// SourceColumn and SheetColumn, when set, name columns added in front of the others holding the file name
+// and the worksheet name every record comes from.
+// The embedded ReadOptions control how the rows of every worksheet are read.
+type MergeOptions struct {
+	ReadOptions
+	Sheets        SheetSelector
+	Headers       HeaderMode
+	HeaderAliases map[string]string
+	SourceColumn  string
+	SheetColumn   string
+}
+
+// MergeSource describes a merged worksheet: the number of records it holds, the merged columns it lacks,
+// left empty in its records, and its columns left out of the merged table by IntersectHeaders.
+type MergeSource struct {
+	Path    string   `json:"path"`
+	Sheet   string   `json:"sheet"`
+	Rows    int      `json:"rows"`
+	Missing []string `json:"missing,omitempty"`
+	Extra   []string `json:"extra,omitempty"`
+}
+
+// MergeReport describes a merge: the columns of the merged table, without the source columns,
+// and the merged worksheets in the order they were read.
+type MergeReport struct {
+	Columns []string      `json:"columns"`
+	Sources []MergeSource `json:"sources"`
+}
+
+// Mismatched tells whether the columns of any merged worksheet differ from the merged columns.
+func (r MergeReport) Mismatched() bool {
+	for _, source := range r.Sources {
+		if len(source.Missing) > 0 || len(source.Extra) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeHeader returns name in lower case, without surrounding spaces and with inner spaces collapsed.
+func normalizeHeader(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// headerKey returns the name the column called name is matched on.
+func (o MergeOptions) headerKey(name string) string {
+	key := normalizeHeader(name)
+	for alias, column := range o.HeaderAliases {
+		if normalizeHeader(alias) == key {
+			return normalizeHeader(column)
+		}
+	}
+	return key
+}
+
+// headerName returns the name of the merged column called name in a worksheet.
+func (o MergeOptions) headerName(name string) string {
+	key := o.headerKey(name)
+	for _, column := range o.HeaderAliases {
+		if normalizeHeader(column) == key {
+			return column
+		}
+	}
+	return strings.TrimSpace(name)
+}
+
+// mergedSheet holds the records of a merged worksheet together with the keys and the merged names
+// of the columns of its header.
+type mergedSheet struct {
+	source  MergeSource
+	keys    []string
+	names   []string
+	records [][]string
+}
+
+// MergeToTable merges the worksheets selected by opts.Sheets of the workbooks at paths into a DataTable,
+// whose rows hold the merged columns in order, and reports the columns every worksheet lacks or holds in excess.
+// Legacy .xls workbooks and OpenDocument spreadsheets (.ods) are read as well.
+// Column names of the table are the merged column names, which need not be valid XML names.
+// The merge stops with the error of ctx as soon as it is cancelled or its deadline passes.
+func MergeToTable(ctx context.Context, paths []string, opts MergeOptions) (DataTable, MergeReport, error) {
+	var report MergeReport
+	if optsErr := opts.ReadOptions.validate(); optsErr != nil {
+		return DataTable{}, report, optsErr
+	}
+	if len(paths) < 1 {
+		return DataTable{}, report, errors.New("no workbooks to merge")
+	}
+	var sheets []mergedSheet
+	for _, path := range paths {
+		read, readErr := opts.readWorkbook(ctx, path)
+		if readErr != nil {
+			return DataTable{}, report, fmt.Errorf("%s: %w", path, readErr)
+		}
+		sheets = append(sheets, read...)
+	}
+
+	keys, names := opts.mergedColumns(sheets)
+	for index := range sheets {
+		sheet := &sheets[index]
+		held := make(map[string]bool, len(sheet.keys))
+		for _, key := range sheet.keys {
+			held[key] = true
+		}
+		kept := make(map[string]bool, len(keys))
+		for position, key := range keys {
+			kept[key] = true
+			if !held[key] {
+				sheet.source.Missing = append(sheet.source.Missing, names[position])
+			}
+		}
+		for position, key := range sheet.keys {
+			if !kept[key] {
+				sheet.source.Extra = append(sheet.source.Extra, sheet.names[position])
+			}
+		}
+		if opts.Headers == StrictHeaders && (len(sheet.source.Missing) > 0 || len(sheet.source.Extra) > 0) {
+			return DataTable{}, report, fmt.Errorf("%s [%s]: the columns differ from those of %s [%s]: missing %v, extra %v",
+				sheet.source.Path, sheet.source.Sheet, sheets[0].source.Path, sheets[0].source.Sheet,
+				sheet.source.Missing, sheet.source.Extra)
+		}
+	}
+
+	var table DataTable
+	for _, sheet := range sheets {
+		positions := make(map[string]int, len(sheet.keys))
+		for position, key := range sheet.keys {
+			if _, seen := positions[key]; !seen {
+				positions[key] = position
+			}
+		}
+		for _, record := range sheet.records {
+			var row DataRow
+			if len(opts.SourceColumn) > 0 {
+				row.Columns = append(row.Columns, DataColumn{XMLName: xml.Name{Local: opts.SourceColumn}, Value: filepath.Base(sheet.source.Path)})
+			}
+			if len(opts.SheetColumn) > 0 {
+				row.Columns = append(row.Columns, DataColumn{XMLName: xml.Name{Local: opts.SheetColumn}, Value: sheet.source.Sheet})
+			}
+			for index, key := range keys {
+				var value string
+				if position, held := positions[key]; held {
+					value = cellAt(record, position)
+				}
+				row.Columns = append(row.Columns, DataColumn{XMLName: xml.Name{Local: names[index]}, Value: value})
+			}
+			table.Rows = append(table.Rows, row)
+		}
+		report.Sources = append(report.Sources, sheet.source)
+	}
+	report.Columns = names
+	return table, report, nil
+}
+
+// MergeWorkbooks merges the worksheets of the workbooks at paths as MergeToTable does and writes the merged table
+// to the .xlsx workbook at outPath, styled as set by style.
+func MergeWorkbooks(ctx context.Context, outPath string, paths []string, opts MergeOptions, style StyleOptions) (MergeReport, error) {
+	table, report, mergeErr := MergeToTable(ctx, paths, opts)
+	if mergeErr != nil {
+		return report, mergeErr
+	}
+	if len(style.SheetName) < 1 {
+		style.SheetName = "Merged"
+	}
+	return report, table.WriteXlsx(outPath, style)
+}
+
+// readWorkbook reads the records of the selected worksheets of the workbook at path.
+func (o MergeOptions) readWorkbook(ctx context.Context, path string) (sheets []mergedSheet, readErr error) {
+	file, openErr := openWorkbook(ctx, path, o.ReadOptions)
+	if openErr != nil {
+		return nil, openErr
+	}
+	defer func(file *excelize.File) {
+		if err := file.Close(); err != nil && readErr == nil {
+			readErr = err
+		}
+	}(file)
+
+	names, selectErr := o.selectSheets(file, o.Sheets)
+	if selectErr != nil {
+		return nil, selectErr
+	}
+	for _, name := range names {
+		reader, readerErr := newSheetReader(file, name, o.ReadOptions)
+		if readerErr != nil {
+			return nil, readerErr
+		}
+		sheet := mergedSheet{source: MergeSource{Path: path, Sheet: name}}
+		onHeader := func(columns []string, _ []int) error {
+			for _, column := range RenameDuplicates(columns, false) {
+				sheet.keys = append(sheet.keys, o.headerKey(column))
+				sheet.names = append(sheet.names, o.headerName(column))
+			}
+			return nil
+		}
+		rowErr := reader.eachRecord(ctx, onHeader, func(_ int, columns []string) error {
+			sheet.records = append(sheet.records, columns)
+			return nil
+		})
+		if rowErr != nil {
+			return nil, fmt.Errorf("worksheet '%s': %w", name, rowErr)
+		}
+		sheet.source.Rows = len(sheet.records)
+		sheets = append(sheets, sheet)
+	}
+	return sheets, nil
+}
+
+// mergedColumns returns the keys and the names of the merged columns of sheets.
+func (o MergeOptions) mergedColumns(sheets []mergedSheet) (keys, names []string) {
+	seen := make(map[string]bool)
+	for index, sheet := range sheets {
+		if o.Headers != UnionHeaders && index > 0 {
+			break
+		}
+		for position, key := range sheet.keys {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+				names = append(names, sheet.names[position])
+			}
+		}
+	}
+	if o.Headers != IntersectHeaders {
+		return keys, names
+	}
+	var commonKeys, commonNames []string
+	for position, key := range keys {
+		common := true
+		for _, sheet := range sheets[1:] {
+			common = common && slices.Contains(sheet.keys, key)
+		}
+		if common {
+			commonKeys = append(commonKeys, key)
+			commonNames = append(commonNames, names[position])
+		}
+	}
+	return commonKeys, commonNames
+}
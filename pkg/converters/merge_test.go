@@ -0,0 +1,145 @@
+package converters
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// tableRows returns the column names and values of every row of table.
+func tableRows(table DataTable) [][]string {
+	var rows [][]string
+	for _, row := range table.Rows {
+		var values []string
+		for _, column := range row.Columns {
+			values = append(values, column.XMLName.Local+"="+column.Value)
+		}
+		rows = append(rows, values)
+	}
+	return rows
+}
+
+func TestMergeToTable(t *testing.T) {
+	north := createTestWorkbook(t, map[string][][]any{
+		"Q1": {{"Region", "Unit Price", "Qty"}, {"North", 10, 2}},
+		"Q2": {{"region", "unit  price", "Qty"}, {"North", 12, 1}},
+	}, "Q1", "Q2")
+	south := createTestWorkbook(t, map[string][][]any{
+		"Q1": {{"Region", "Quantity", "Note"}, {"South", 7, "late"}},
+	}, "Q1")
+	aliases := map[string]string{"Qty": "Quantity"}
+
+	tests := []struct {
+		name        string
+		paths       []string
+		opts        MergeOptions
+		wantRows    [][]string
+		wantColumns []string
+		wantMissing [][]string
+		wantExtra   [][]string
+		wantErr     string
+	}{
+		{
+			name:  "Union",
+			paths: []string{north, south},
+			opts:  MergeOptions{Sheets: AllSheets(), HeaderAliases: aliases, SheetColumn: "Sheet"},
+			wantRows: [][]string{
+				{"Sheet=Q1", "Region=North", "Unit Price=10", "Quantity=2", "Note="},
+				{"Sheet=Q2", "Region=North", "Unit Price=12", "Quantity=1", "Note="},
+				{"Sheet=Q1", "Region=South", "Unit Price=", "Quantity=7", "Note=late"},
+			},
+			wantColumns: []string{"Region", "Unit Price", "Quantity", "Note"},
+			wantMissing: [][]string{{"Note"}, {"Note"}, {"Unit Price"}},
+			wantExtra:   [][]string{nil, nil, nil},
+		},
+		{
+			name:  "Intersect",
+			paths: []string{north, south},
+			opts:  MergeOptions{Sheets: AllSheets(), Headers: IntersectHeaders, HeaderAliases: aliases, SourceColumn: "File"},
+			wantRows: [][]string{
+				{"File=TestWorkbook.xlsx", "Region=North", "Quantity=2"},
+				{"File=TestWorkbook.xlsx", "Region=North", "Quantity=1"},
+				{"File=TestWorkbook.xlsx", "Region=South", "Quantity=7"},
+			},
+			wantColumns: []string{"Region", "Quantity"},
+			wantMissing: [][]string{nil, nil, nil},
+			wantExtra:   [][]string{{"Unit Price"}, {"unit  price"}, {"Note"}},
+		},
+		{
+			name:        "Strict Reconcilable",
+			paths:       []string{north},
+			opts:        MergeOptions{Sheets: AllSheets(), Headers: StrictHeaders},
+			wantRows:    [][]string{{"Region=North", "Unit Price=10", "Qty=2"}, {"Region=North", "Unit Price=12", "Qty=1"}},
+			wantColumns: []string{"Region", "Unit Price", "Qty"},
+			wantMissing: [][]string{nil, nil},
+			wantExtra:   [][]string{nil, nil},
+		},
+		{
+			name:    "Strict Mismatch",
+			paths:   []string{north, south},
+			opts:    MergeOptions{Headers: StrictHeaders},
+			wantErr: "missing [Unit Price Qty], extra [Quantity Note]",
+		},
+		{
+			name:    "No Workbooks",
+			wantErr: "no workbooks to merge",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table, report, err := MergeToTable(context.Background(), tt.paths, tt.opts)
+			if len(tt.wantErr) > 0 {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("MergeToTable() error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("MergeToTable() error = %v", err)
+			}
+			if got := tableRows(table); !reflect.DeepEqual(got, tt.wantRows) {
+				t.Errorf("MergeToTable() rows = %v, want %v", got, tt.wantRows)
+			}
+			if !reflect.DeepEqual(report.Columns, tt.wantColumns) {
+				t.Errorf("MergeToTable() columns = %v, want %v", report.Columns, tt.wantColumns)
+			}
+			var missing, extra [][]string
+			for _, source := range report.Sources {
+				missing = append(missing, source.Missing)
+				extra = append(extra, source.Extra)
+			}
+			if !reflect.DeepEqual(missing, tt.wantMissing) || !reflect.DeepEqual(extra, tt.wantExtra) {
+				t.Errorf("MergeToTable() missing = %v, extra = %v, want %v and %v", missing, extra, tt.wantMissing, tt.wantExtra)
+			}
+		})
+	}
+}
+
+func TestMergeWorkbooks(t *testing.T) {
+	first := createTestWorkbook(t, map[string][][]any{"Data": {{"Id", "Name"}, {1, "Ann"}}}, "Data")
+	second := createTestWorkbook(t, map[string][][]any{"Data": {{"Name", "Id"}, {"Bob", 2}}}, "Data")
+	outPath := filepath.Join(t.TempDir(), "merged.xlsx")
+	report, err := MergeWorkbooks(context.Background(), outPath, []string{first, second}, MergeOptions{}, StyleOptions{})
+	if err != nil {
+		t.Fatalf("MergeWorkbooks() error = %v", err)
+	}
+	if report.Mismatched() {
+		t.Errorf("MergeWorkbooks() reported a mismatch: %+v", report)
+	}
+	file, openErr := excelize.OpenFile(outPath)
+	if openErr != nil {
+		t.Fatalf("Error opening the merged workbook: %v", openErr)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	rows, _ := file.GetRows("Merged")
+	want := [][]string{{"Id", "Name"}, {"1", "Ann"}, {"2", "Bob"}}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("MergeWorkbooks() rows = %v, want %v", rows, want)
+	}
+}
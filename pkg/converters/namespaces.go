@@ -0,0 +1,76 @@
+package converters
+
+import (
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strings"
+
+	. "GoTools/pkg/helpers"
+)
+
+// validateNamespaces reports namespace options that would produce malformed or ambiguous XML.
+func (o XmlOptions) validateNamespaces() error {
+	for prefix, uri := range o.Namespaces {
+		if len(prefix) < 1 || strings.Contains(prefix, ":") || FixXMLTags(prefix) != prefix {
+			return fmt.Errorf("'%s' is not a valid namespace prefix", prefix)
+		}
+		if strings.HasPrefix(strings.ToLower(prefix), "xml") {
+			return fmt.Errorf("namespace prefix '%s' is reserved", prefix)
+		}
+		if o.typed() && (prefix == "xsi" || prefix == "xs") {
+			return fmt.Errorf("namespace prefix '%s' is reserved for type annotations", prefix)
+		}
+		if o.EmptyCells == NilEmpty && prefix == "xsi" {
+			return fmt.Errorf("namespace prefix '%s' is reserved for nil cells", prefix)
+		}
+		if len(uri) < 1 {
+			return fmt.Errorf("namespace prefix '%s' is bound to an empty namespace name", prefix)
+		}
+	}
+	for column, prefix := range o.ColumnPrefixes {
+		if _, declared := o.Namespaces[prefix]; !declared {
+			return fmt.Errorf("column %s uses the undeclared namespace prefix '%s'", column, prefix)
+		}
+	}
+	return nil
+}
+
+// namespaceDecls returns the declarations of the default and prefixed namespaces, ordered by prefix
+// so the output is stable.
+func (o XmlOptions) namespaceDecls() []xml.Attr {
+	var decls []xml.Attr
+	if len(o.Namespace) > 0 {
+		decls = append(decls, xml.Attr{Name: xml.Name{Local: "xmlns"}, Value: o.Namespace})
+	}
+	prefixes := make([]string, 0, len(o.Namespaces))
+	for prefix := range o.Namespaces {
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Strings(prefixes)
+	for _, prefix := range prefixes {
+		decls = append(decls, xml.Attr{Name: xml.Name{Local: "xmlns:" + prefix}, Value: o.Namespaces[prefix]})
+	}
+	return decls
+}
+
+// qualifyColumn prefixes the element name of a column mapped to a namespace in ColumnPrefixes.
+func (o XmlOptions) qualifyColumn(column *DataColumn) {
+	if prefix, mapped := o.ColumnPrefixes[column.XMLName.Local]; mapped {
+		column.XMLName.Local = prefix + ":" + column.XMLName.Local
+	}
+}
+
+// tableAttrs returns the attributes of the table element. Namespaces are declared on the root of the document,
+// which is the table element unless worksheets are combined into a Workbook.
+func (o Options) tableAttrs() []xml.Attr {
+	if o.Combine {
+		return o.Xml.namespaceAttrs()
+	}
+	return append(o.Xml.namespaceDecls(), o.Xml.namespaceAttrs()...)
+}
+
+// workbookStart returns the start element of a combined document, declaring the configured namespaces.
+func (o Options) workbookStart() xml.StartElement {
+	return xml.StartElement{Name: workbookElement.Name, Attr: o.Xml.namespaceDecls()}
+}
@@ -0,0 +1,145 @@
+package converters
+
+import (
+	"archive/zip"
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Compression selects how the output of a conversion is compressed.
+type Compression int
+
+const (
+	// NoCompression writes plain files.
+	NoCompression Compression = iota
+	// GzipCompression compresses every file written into a gzip stream, appending ".gz" to its name.
+	// XlsxToXml compresses the document written to w.
+	GzipCompression
+	// ZipCompression writes every file XlsxToXmlFiles would write into a single zip archive named after
+	// the workbook, for instance the chunks of a document together with their manifest.
+	ZipCompression
+)
+
+// ParseCompression returns the Compression called none, gzip or zip.
+func ParseCompression(name string) (Compression, error) {
+	switch name {
+	case "", "none":
+		return NoCompression, nil
+	case "gzip":
+		return GzipCompression, nil
+	case "zip":
+		return ZipCompression, nil
+	}
+	return NoCompression, fmt.Errorf("unknown compression '%s'", name)
+}
+
+// outputFiles creates the files of a conversion, compressing them as configured, and records the paths written.
+// In ZipCompression mode the files are entries of a single archive, which close completes.
+type outputFiles struct {
+	compression Compression
+	archive     *zip.Writer
+	archiveFile *os.File
+	archiveBuf  *bufio.Writer
+	written     []string
+}
+
+// newOutputFiles prepares the output of converting the workbook at path, creating the archive in dir
+// in ZipCompression mode.
+func newOutputFiles(dir, path string, compression Compression) (*outputFiles, error) {
+	files := &outputFiles{compression: compression}
+	if compression != ZipCompression {
+		return files, nil
+	}
+	archivePath := filepath.Join(dir, strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))+".zip")
+	archiveFile, createErr := os.Create(archivePath)
+	if createErr != nil {
+		return nil, createErr
+	}
+	files.archiveFile, files.archiveBuf = archiveFile, bufio.NewWriter(archiveFile)
+	files.archive = zip.NewWriter(files.archiveBuf)
+	files.written = append(files.written, archivePath)
+	return files, nil
+}
+
+// outputFile is a single file being written. name is how the file is referred to next to the other files,
+// its base name including any compression suffix. close completes the file and abort gives up on it.
+type outputFile struct {
+	io.Writer
+	name  string
+	close func() error
+	abort func()
+}
+
+// create starts the file at path, or the archive entry named after it.
+func (f *outputFiles) create(path string) (*outputFile, error) {
+	if f.compression == ZipCompression {
+		entry, entryErr := f.archive.Create(filepath.Base(path))
+		if entryErr != nil {
+			return nil, entryErr
+		}
+		return &outputFile{Writer: entry, name: filepath.Base(path), close: func() error { return nil }, abort: func() {}}, nil
+	}
+	if f.compression == GzipCompression {
+		path += ".gz"
+	}
+	outFile, createErr := os.Create(path)
+	if createErr != nil {
+		return nil, createErr
+	}
+	f.written = append(f.written, path)
+	buffer := bufio.NewWriter(outFile)
+	out := &outputFile{Writer: buffer, name: filepath.Base(path), abort: func() { _ = outFile.Close() }}
+	var compressor *gzip.Writer
+	if f.compression == GzipCompression {
+		compressor = gzip.NewWriter(buffer)
+		out.Writer = compressor
+	}
+	out.close = func() error {
+		if compressor != nil {
+			if err := compressor.Close(); err != nil {
+				_ = outFile.Close()
+				return err
+			}
+		}
+		if err := buffer.Flush(); err != nil {
+			_ = outFile.Close()
+			return err
+		}
+		return outFile.Close()
+	}
+	return out, nil
+}
+
+// writeFile creates the file at path and hands it to write.
+func (f *outputFiles) writeFile(path string, write func(w io.Writer) error) error {
+	out, createErr := f.create(path)
+	if createErr != nil {
+		return createErr
+	}
+	if writeErr := write(out); writeErr != nil {
+		out.abort()
+		return writeErr
+	}
+	return out.close()
+}
+
+// close completes the archive, if any.
+func (f *outputFiles) close() error {
+	if f.archive == nil {
+		return nil
+	}
+	if err := f.archive.Close(); err != nil {
+		_ = f.archiveFile.Close()
+		return err
+	}
+	if err := f.archiveBuf.Flush(); err != nil {
+		_ = f.archiveFile.Close()
+		return err
+	}
+	return f.archiveFile.Close()
+}
@@ -0,0 +1,394 @@
+package converters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"GoTools/pkg/fixedwidth"
+	"GoTools/pkg/transforms"
+	"gopkg.in/yaml.v3"
+)
+
+// PipelineConfig describes a conversion in a YAML file, so it can be versioned along with the workbooks it
+// converts: the worksheet Source reads, the canonical columns its headers are renamed to, see HeaderMapping, the
+// columns kept, the transforms cleaning their values, see transforms.Config, the rows kept, see ParseRowFilter, and
+// the Sink the records are written to. JSON files are read as well, JSON being YAML too.
+//
+//	source:
+//	  path: in/customers.xlsx
+//	  sheet: Customers
+//	  headerRow: 2
+//	headers:
+//	  fuzzy: 0.85
+//	  columns:
+//	    - name: Customer
+//	      aliases: [Client, Cust. Name]
+//	    - name: Country
+//	columns:
+//	  include: [Customer, Country, Total]
+//	transforms:
+//	  - column: Customer
+//	    transforms: [trim, title]
+//	where: Total > 0
+//	sink:
+//	  path: out/customers.xml
+//	  xml: {root: Customers, row: Customer}
+//
+// LoadPipelineConfig reads the source, layout and sink paths relative to the directory of the configuration file,
+// so a configuration can be run from anywhere; the paths of lookup tables are relative to the working directory.
+type PipelineConfig struct {
+	Source     SourceConfig       `yaml:"source" json:"source"`
+	Headers    *HeaderMapping     `yaml:"headers" json:"headers"`
+	Columns    ColumnsConfig      `yaml:"columns" json:"columns"`
+	Transforms []transforms.Stage `yaml:"transforms" json:"transforms"`
+	Where      string             `yaml:"where" json:"where"`
+	Sink       SinkConfig         `yaml:"sink" json:"sink"`
+}
+
+// SourceConfig is the worksheet a PipelineConfig reads: the worksheet called Sheet, or selected by Sheets as
+// ParseSheetSelector reads it, of the workbook at Path, the first one by default, read as the fields of the same
+// name of ReadOptions say. Formulas is read by ParseFormulaMode and PasswordEnv names the environment variable
+// holding the password of an encrypted workbook. FixedWidth is the path of the layout of a fixed-width text file
+// read instead of a workbook, see fixedwidth.LoadLayout, in the encoding Encoding, UTF-8 by default.
+type SourceConfig struct {
+	Path              string   `yaml:"path" json:"path"`
+	Sheet             string   `yaml:"sheet" json:"sheet"`
+	Sheets            string   `yaml:"sheets" json:"sheets"`
+	Range             string   `yaml:"range" json:"range"`
+	DetectRegion      bool     `yaml:"detectRegion" json:"detectRegion"`
+	HeaderRow         int      `yaml:"headerRow" json:"headerRow"`
+	SkipRows          int      `yaml:"skipRows" json:"skipRows"`
+	SkipFooterRows    int      `yaml:"skipFooterRows" json:"skipFooterRows"`
+	MergedCells       bool     `yaml:"mergedCells" json:"mergedCells"`
+	SkipHiddenRows    bool     `yaml:"skipHiddenRows" json:"skipHiddenRows"`
+	SkipHiddenColumns bool     `yaml:"skipHiddenColumns" json:"skipHiddenColumns"`
+	Formulas          string   `yaml:"formulas" json:"formulas"`
+	NullTokens        []string `yaml:"nullTokens" json:"nullTokens"`
+	PasswordEnv       string   `yaml:"passwordEnv" json:"passwordEnv"`
+	FixedWidth        string   `yaml:"fixedWidth" json:"fixedWidth"`
+	Encoding          string   `yaml:"encoding" json:"encoding"`
+}
+
+// ColumnsConfig is the columns a PipelineConfig keeps, see ColumnFilter, Pattern being a regular expression, and
+// their order, read by ParseColumnOrder.
+type ColumnsConfig struct {
+	Include []string `yaml:"include" json:"include"`
+	Exclude []string `yaml:"exclude" json:"exclude"`
+	Pattern string   `yaml:"pattern" json:"pattern"`
+	Order   string   `yaml:"order" json:"order"`
+}
+
+// SinkConfig is the file a PipelineConfig writes: the file at Path in the format Format, one of xml, csv, json,
+// xlsx, sql or parquet, taken from the extension of Path by default. The section named after the format
+// configures it; the others are ignored.
+type SinkConfig struct {
+	Path    string            `yaml:"path" json:"path"`
+	Format  string            `yaml:"format" json:"format"`
+	Xml     XmlSinkConfig     `yaml:"xml" json:"xml"`
+	Csv     CsvSinkConfig     `yaml:"csv" json:"csv"`
+	Json    JsonSinkConfig    `yaml:"json" json:"json"`
+	Xlsx    XlsxSinkConfig    `yaml:"xlsx" json:"xlsx"`
+	Sql     SqlSinkConfig     `yaml:"sql" json:"sql"`
+	Parquet ParquetSinkConfig `yaml:"parquet" json:"parquet"`
+}
+
+// XmlSinkConfig configures an XML sink, see XmlOptions.
+type XmlSinkConfig struct {
+	Root       string `yaml:"root" json:"root"`
+	Row        string `yaml:"row" json:"row"`
+	Attributes bool   `yaml:"attributes" json:"attributes"`
+	InferTypes bool   `yaml:"inferTypes" json:"inferTypes"`
+	Indent     string `yaml:"indent" json:"indent"`
+	Compact    bool   `yaml:"compact" json:"compact"`
+	Encoding   string `yaml:"encoding" json:"encoding"`
+}
+
+// CsvSinkConfig configures a CSV sink, see CsvOptions; Delimiter is a single character, a comma by default.
+type CsvSinkConfig struct {
+	Delimiter     string `yaml:"delimiter" json:"delimiter"`
+	OmitHeader    bool   `yaml:"omitHeader" json:"omitHeader"`
+	Encoding      string `yaml:"encoding" json:"encoding"`
+	ByteOrderMark bool   `yaml:"byteOrderMark" json:"byteOrderMark"`
+}
+
+// JsonSinkConfig configures a JSON sink, see JsonOptions; Layout is read by ParseJsonLayout.
+type JsonSinkConfig struct {
+	Layout     string `yaml:"layout" json:"layout"`
+	InferTypes bool   `yaml:"inferTypes" json:"inferTypes"`
+	Lines      bool   `yaml:"lines" json:"lines"`
+}
+
+// XlsxSinkConfig configures an .xlsx sink, see StyleOptions.
+type XlsxSinkConfig struct {
+	SheetName    string `yaml:"sheetName" json:"sheetName"`
+	StyleHeader  bool   `yaml:"styleHeader" json:"styleHeader"`
+	FreezeHeader bool   `yaml:"freezeHeader" json:"freezeHeader"`
+	AutoFilter   bool   `yaml:"autoFilter" json:"autoFilter"`
+	AutoWidth    bool   `yaml:"autoWidth" json:"autoWidth"`
+	CoerceTypes  bool   `yaml:"coerceTypes" json:"coerceTypes"`
+}
+
+// SqlSinkConfig configures an SQL sink, see SqlOptions; Dialect and Statement are read by ParseSqlDialect and
+// ParseSqlStatement.
+type SqlSinkConfig struct {
+	Table       string   `yaml:"table" json:"table"`
+	Dialect     string   `yaml:"dialect" json:"dialect"`
+	Statement   string   `yaml:"statement" json:"statement"`
+	KeyColumns  []string `yaml:"keyColumns" json:"keyColumns"`
+	BatchSize   int      `yaml:"batchSize" json:"batchSize"`
+	CreateTable bool     `yaml:"createTable" json:"createTable"`
+}
+
+// ParquetSinkConfig configures a Parquet sink; Compression is read by ParseParquetCodec.
+type ParquetSinkConfig struct {
+	Compression string `yaml:"compression" json:"compression"`
+}
+
+// Pipeline is a conversion configured by a PipelineConfig: Source reads the worksheet at Input and OpenSink
+// opens the sink writing the file at Output, creating its directory.
+type Pipeline struct {
+	Input    string
+	Output   string
+	Source   RowSource
+	OpenSink func() (RowSink, error)
+}
+
+// Run runs the conversion, see Convert.
+func (p *Pipeline) Run(ctx context.Context) error {
+	sink, openErr := p.OpenSink()
+	if openErr != nil {
+		return openErr
+	}
+	return Convert(ctx, p.Source, sink)
+}
+
+// Job returns the conversion as a Job named after its input, for RunJobs to run along with others.
+func (p *Pipeline) Job() Job {
+	return Job{Name: p.Input, Source: p.Source, OpenSink: p.OpenSink}
+}
+
+// Pipeline returns the conversion configured by c, failing on any setting it cannot read. The files are only
+// opened once the conversion runs.
+func (c PipelineConfig) Pipeline() (*Pipeline, error) {
+	if len(strings.TrimSpace(c.Source.Path)) < 1 {
+		return nil, errors.New("the pipeline has no source path")
+	}
+	opts, optsErr := c.readOptions()
+	if optsErr != nil {
+		return nil, optsErr
+	}
+	sheets, sheetsErr := c.Source.selector()
+	if sheetsErr != nil {
+		return nil, sheetsErr
+	}
+	openSink, sinkErr := c.Sink.opener()
+	if sinkErr != nil {
+		return nil, fmt.Errorf("sink: %w", sinkErr)
+	}
+	return &Pipeline{
+		Input:    c.Source.Path,
+		Output:   c.Sink.Path,
+		Source:   SheetSource(c.Source.Path, sheets, opts),
+		OpenSink: openSink,
+	}, nil
+}
+
+// readOptions returns the options reading the source of c.
+func (c PipelineConfig) readOptions() (opts ReadOptions, err error) {
+	source := c.Source
+	opts = ReadOptions{
+		ResolveMergedCells: source.MergedCells,
+		HeaderRowIndex:     source.HeaderRow,
+		SkipRows:           source.SkipRows,
+		SkipFooterRows:     source.SkipFooterRows,
+		SkipHiddenRows:     source.SkipHiddenRows,
+		SkipHiddenColumns:  source.SkipHiddenColumns,
+		Range:              source.Range,
+		Region:             RegionOptions{Detect: source.DetectRegion},
+		NullTokens:         source.NullTokens,
+		HeaderMapping:      c.Headers,
+		Columns:            ColumnFilter{Include: c.Columns.Include, Exclude: c.Columns.Exclude},
+	}
+	if len(source.PasswordEnv) > 0 {
+		opts.Password = PasswordFromEnv(source.PasswordEnv)
+	}
+	if opts.Formulas, err = ParseFormulaMode(source.Formulas); err != nil {
+		return opts, err
+	}
+	if len(source.FixedWidth) > 0 {
+		if opts.FixedWidth, err = fixedwidth.LoadLayout(source.FixedWidth); err != nil {
+			return opts, err
+		}
+		opts.FixedWidthEncoding = source.Encoding
+	}
+	if len(c.Columns.Pattern) > 0 {
+		if opts.Columns.Pattern, err = regexp.Compile(c.Columns.Pattern); err != nil {
+			return opts, err
+		}
+	}
+	if opts.ColumnOrder, err = ParseColumnOrder(c.Columns.Order); err != nil {
+		return opts, err
+	}
+	if len(c.Transforms) > 0 {
+		if opts.Transforms, err = (transforms.Config{Stages: c.Transforms}).Pipeline(); err != nil {
+			return opts, fmt.Errorf("transforms: %w", err)
+		}
+	}
+	if len(c.Where) > 0 {
+		if opts.RowFilter, err = ParseRowFilter(c.Where); err != nil {
+			return opts, err
+		}
+	}
+	return opts, opts.validate()
+}
+
+// selector returns the worksheet selected by c, a name given with Sheet taking precedence over Sheets.
+func (c SourceConfig) selector() (SheetSelector, error) {
+	if len(c.Sheet) > 0 {
+		return SheetByName(c.Sheet), nil
+	}
+	return ParseSheetSelector(c.Sheets)
+}
+
+// format returns the format of the sink, taken from the extension of its path when left out.
+func (c SinkConfig) format() string {
+	if len(c.Format) > 0 {
+		return strings.ToLower(c.Format)
+	}
+	return strings.ToLower(strings.TrimPrefix(filepath.Ext(c.Path), "."))
+}
+
+// opener returns the function opening the sink configured by c.
+func (c SinkConfig) opener() (func() (RowSink, error), error) {
+	if len(strings.TrimSpace(c.Path)) < 1 {
+		return nil, errors.New("no path")
+	}
+	var newSink func(w io.Writer) RowSink
+	var style StyleOptions
+	switch format := c.format(); format {
+	case "xml":
+		opts := XmlOptions{RootName: c.Xml.Root, RowName: c.Xml.Row, Attributes: c.Xml.Attributes, InferTypes: c.Xml.InferTypes,
+			Indent: c.Xml.Indent, Compact: c.Xml.Compact, Encoding: c.Xml.Encoding}
+		if err := opts.validate(); err != nil {
+			return nil, err
+		}
+		newSink = func(w io.Writer) RowSink { return XmlSink(w, opts) }
+	case "csv":
+		opts := CsvOptions{OmitHeader: c.Csv.OmitHeader, Encoding: c.Csv.Encoding, ByteOrderMark: c.Csv.ByteOrderMark}
+		if len(c.Csv.Delimiter) > 0 {
+			if utf8.RuneCountInString(c.Csv.Delimiter) != 1 {
+				return nil, fmt.Errorf("the delimiter must be a single character, got '%s'", c.Csv.Delimiter)
+			}
+			opts.Delimiter, _ = utf8.DecodeRuneInString(c.Csv.Delimiter)
+		}
+		if _, err := csvEncoding(opts.Encoding); err != nil {
+			return nil, err
+		}
+		newSink = func(w io.Writer) RowSink { return CsvSink(w, opts) }
+	case "json":
+		opts := JsonOptions{InferTypes: c.Json.InferTypes, Lines: c.Json.Lines}
+		var err error
+		if opts.Layout, err = ParseJsonLayout(c.Json.Layout); err != nil {
+			return nil, err
+		}
+		if err := opts.validate(); err != nil {
+			return nil, err
+		}
+		newSink = func(w io.Writer) RowSink { return JsonSink(w, opts) }
+	case "sql":
+		opts := SqlOptions{Table: c.Sql.Table, KeyColumns: c.Sql.KeyColumns, BatchSize: c.Sql.BatchSize, CreateTable: c.Sql.CreateTable}
+		var err error
+		if opts.Dialect, err = ParseSqlDialect(c.Sql.Dialect); err != nil {
+			return nil, err
+		}
+		if opts.Statement, err = ParseSqlStatement(c.Sql.Statement); err != nil {
+			return nil, err
+		}
+		if err := opts.validate(); err != nil {
+			return nil, err
+		}
+		newSink = func(w io.Writer) RowSink { return SqlSink(w, opts) }
+	case "parquet":
+		var opts ParquetOptions
+		var err error
+		if opts.Compression, err = ParseParquetCodec(c.Parquet.Compression); err != nil {
+			return nil, err
+		}
+		newSink = func(w io.Writer) RowSink { return ParquetSink(w, opts) }
+	case "xlsx":
+		// The workbook is written by the sink itself.
+		style = StyleOptions{SheetName: c.Xlsx.SheetName, StyleHeader: c.Xlsx.StyleHeader, FreezeHeader: c.Xlsx.FreezeHeader,
+			AutoFilter: c.Xlsx.AutoFilter, AutoWidth: c.Xlsx.AutoWidth, CoerceTypes: c.Xlsx.CoerceTypes}
+	default:
+		return nil, fmt.Errorf("unknown format '%s'", format)
+	}
+	return func() (RowSink, error) {
+		if err := os.MkdirAll(filepath.Dir(c.Path), 0o755); err != nil {
+			return nil, err
+		}
+		if newSink == nil {
+			return XlsxSink(c.Path, style), nil
+		}
+		return FileSink(c.Path, newSink)
+	}, nil
+}
+
+// ParsePipelineConfig reads a PipelineConfig from YAML or JSON.
+func ParsePipelineConfig(r io.Reader) (PipelineConfig, error) {
+	var config PipelineConfig
+	decoder := yaml.NewDecoder(r)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&config); err != nil && !errors.Is(err, io.EOF) {
+		return PipelineConfig{}, fmt.Errorf("pipeline: %w", err)
+	}
+	return config, nil
+}
+
+// LoadPipelineConfig reads the pipeline configuration file at path, see ParsePipelineConfig, resolving the
+// relative source, layout and sink paths against the directory of the file.
+func LoadPipelineConfig(path string) (config PipelineConfig, loadErr error) {
+	file, openErr := os.Open(path)
+	if openErr != nil {
+		return PipelineConfig{}, openErr
+	}
+	defer func(file *os.File) {
+		if err := file.Close(); err != nil && loadErr == nil {
+			loadErr = err
+		}
+	}(file)
+	if config, loadErr = ParsePipelineConfig(file); loadErr != nil {
+		return PipelineConfig{}, loadErr
+	}
+	config.resolvePaths(filepath.Dir(path))
+	return config, nil
+}
+
+// resolvePaths makes the relative source, layout and sink paths of c relative to dir.
+func (c *PipelineConfig) resolvePaths(dir string) {
+	for _, path := range []*string{&c.Source.Path, &c.Source.FixedWidth, &c.Sink.Path} {
+		if len(*path) > 0 && !filepath.IsAbs(*path) {
+			*path = filepath.Join(dir, *path)
+		}
+	}
+}
+
+// LoadPipeline returns the conversion configured by the YAML or JSON file at path, see PipelineConfig, such as
+//
+//	pipeline, err := LoadPipeline("customers.yaml")
+//	if err == nil {
+//		err = pipeline.Run(ctx)
+//	}
+func LoadPipeline(path string) (*Pipeline, error) {
+	config, err := LoadPipelineConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	return config.Pipeline()
+}
@@ -0,0 +1,128 @@
+package converters
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadPipeline(t *testing.T) {
+	path := createTestWorkbook(t, map[string][][]any{
+		"Notes":  {{"Text"}, {"ignored"}},
+		"Orders": {{"Client", "Status", "Total"}, {" ann smith ", "open", 10}, {"bob", "cancelled", 5}, {"carl", "open", 0}},
+	}, "Notes", "Orders")
+	dir := t.TempDir()
+	out := filepath.Join(dir, "out", "orders.csv")
+	config := `source:
+  path: ` + path + `
+  sheet: Orders
+headers:
+  columns:
+    - name: Customer
+      aliases: [Client]
+    - name: Total
+columns:
+  exclude: [Status]
+transforms:
+  - column: Customer
+    transforms: [trim, title]
+where: Status != "cancelled" && Total > 0
+sink:
+  path: ` + out + `
+  csv: {delimiter: ";"}
+`
+	configPath := filepath.Join(dir, "orders.yaml")
+	if err := os.WriteFile(configPath, []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	pipeline, loadErr := LoadPipeline(configPath)
+	if loadErr != nil {
+		t.Fatalf("LoadPipeline() error = %v", loadErr)
+	}
+	if pipeline.Input != path || pipeline.Output != out {
+		t.Errorf("pipeline converts %s to %s, want %s to %s", pipeline.Input, pipeline.Output, path, out)
+	}
+	if err := pipeline.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	content, _ := os.ReadFile(out)
+	if want := "Customer;Total\nAnn Smith;10\n"; string(content) != want {
+		t.Errorf("output = %q, want %q", content, want)
+	}
+}
+
+// TestLoadPipelineRelativePaths loads a configuration from another directory than the working one, whose relative
+// paths are resolved against the directory of the configuration file.
+func TestLoadPipelineRelativePaths(t *testing.T) {
+	workbook := createTestWorkbook(t, map[string][][]any{"Orders": {{"Client", "Total"}, {"ann", 10}}}, "Orders")
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "in"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	content, _ := os.ReadFile(workbook)
+	if err := os.WriteFile(filepath.Join(dir, "in", "orders.xlsx"), content, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	configPath := filepath.Join(dir, "orders.yaml")
+	if err := os.WriteFile(configPath, []byte("source: {path: in/orders.xlsx}\nsink: {path: out/orders.csv}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	pipeline, loadErr := LoadPipeline(configPath)
+	if loadErr != nil {
+		t.Fatalf("LoadPipeline() error = %v", loadErr)
+	}
+	input, output := filepath.Join(dir, "in", "orders.xlsx"), filepath.Join(dir, "out", "orders.csv")
+	if pipeline.Input != input || pipeline.Output != output {
+		t.Errorf("pipeline converts %s to %s, want %s to %s", pipeline.Input, pipeline.Output, input, output)
+	}
+	if err := pipeline.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if written, _ := os.ReadFile(output); string(written) != "Client,Total\nann,10\n" {
+		t.Errorf("output = %q", written)
+	}
+
+	layoutConfig := filepath.Join(dir, "text.yaml")
+	if err := os.WriteFile(layoutConfig, []byte("source: {path: "+workbook+", fixedWidth: layouts/text.yaml}\nsink: {path: text.csv}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	config, err := LoadPipelineConfig(layoutConfig)
+	if err != nil {
+		t.Fatalf("LoadPipelineConfig() error = %v", err)
+	}
+	if want := filepath.Join(dir, "layouts", "text.yaml"); config.Source.FixedWidth != want {
+		t.Errorf("layout path = %s, want %s", config.Source.FixedWidth, want)
+	}
+	if config.Source.Path != workbook {
+		t.Errorf("absolute source path = %s, want it kept as %s", config.Source.Path, workbook)
+	}
+}
+
+func TestPipelineConfigErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		want   string
+	}{
+		{name: "Unknown field", config: "source: {path: a.xlsx, sheeet: A}\nsink: {path: a.csv}", want: "sheeet"},
+		{name: "No source", config: "sink: {path: a.csv}", want: "no source path"},
+		{name: "No sink", config: "source: {path: a.xlsx}", want: "sink: no path"},
+		{name: "Unknown format", config: "source: {path: a.xlsx}\nsink: {path: a.pdf}", want: "unknown format 'pdf'"},
+		{name: "Invalid delimiter", config: "source: {path: a.xlsx}\nsink: {path: a.csv, csv: {delimiter: ab}}", want: "single character"},
+		{name: "Invalid transform", config: "source: {path: a.xlsx}\ntransforms: [{column: A, transforms: [nope]}]\nsink: {path: a.csv}", want: "transforms: stage 1"},
+		{name: "Invalid filter", config: "source: {path: a.xlsx}\nwhere: 'A =='\nsink: {path: a.csv}", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := ParsePipelineConfig(strings.NewReader(tt.config))
+			if err == nil {
+				_, err = config.Pipeline()
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("pipeline error = %v, want one mentioning %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package converters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"GoTools/pkg/datatable"
+)
+
+// PreviewMode tells which records of a worksheet a preview shows.
+type PreviewMode int
+
+const (
+	// PreviewHead shows the first records, reading no further. It is the default.
+	PreviewHead PreviewMode = iota
+	// PreviewTail shows the last records.
+	PreviewTail
+	// PreviewSample shows records picked at random, see datatable.Reservoir.
+	PreviewSample
+)
+
+// ParsePreviewMode returns the PreviewMode called head, tail or sample.
+func ParsePreviewMode(name string) (PreviewMode, error) {
+	switch strings.ToLower(name) {
+	case "", "head":
+		return PreviewHead, nil
+	case "tail":
+		return PreviewTail, nil
+	case "sample":
+		return PreviewSample, nil
+	}
+	return PreviewHead, fmt.Errorf("unknown preview mode '%s'", name)
+}
+
+// errEnoughRows stops reading a worksheet once a preview holds the records it shows.
+var errEnoughRows = errors.New("enough rows read")
+
+// PreviewOptions configures previewing a worksheet. Rows is the number of records shown, 20 when it is zero, and
+// Mode which ones; Seed picks the records of PreviewSample, the same seed picking the same records.
+// The embedded TableOptions select the worksheet and control how its rows are read.
+type PreviewOptions struct {
+	TableOptions
+	Rows int
+	Mode PreviewMode
+	Seed int64
+}
+
+// PreviewTable reads some of the records of the worksheet of the file at path selected by opts.Sheets into a
+// datatable.DataTable, to look at a large file quickly. The first records are read without reading the rest of
+// the worksheet; the last ones and a sample read it all while only holding the records they show.
+func PreviewTable(ctx context.Context, path string, opts PreviewOptions) (*datatable.DataTable, error) {
+	rows := opts.Rows
+	switch {
+	case rows < 0:
+		return nil, fmt.Errorf("invalid preview row count %d", rows)
+	case rows == 0:
+		rows = 20
+	}
+	var held [][]string
+	var reservoir *datatable.Reservoir
+	onRecord := func(table *datatable.DataTable, columns []string) error {
+		switch opts.Mode {
+		case PreviewTail:
+			if len(held) == rows {
+				held = held[1:]
+			}
+			held = append(held, append([]string(nil), columns...))
+			return nil
+		case PreviewSample:
+			if reservoir == nil {
+				reservoir = datatable.NewReservoir(rows, opts.Seed)
+			}
+			reservoir.Add(columns)
+			return nil
+		}
+		if err := table.AddRow(columns...); err != nil {
+			return err
+		}
+		if table.Len() >= rows {
+			return errEnoughRows
+		}
+		return nil
+	}
+	inferTypes := opts.InferTypes
+	opts.InferTypes = false
+	table, err := readTable(ctx, path, opts.TableOptions, onRecord)
+	if err != nil {
+		return nil, err
+	}
+	if reservoir != nil {
+		held = reservoir.Records()
+	}
+	for _, record := range held {
+		if err := table.AddRow(record...); err != nil {
+			return nil, err
+		}
+	}
+	if inferTypes {
+		table.InferTypes()
+	}
+	return table, nil
+}
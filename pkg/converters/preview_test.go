@@ -0,0 +1,58 @@
+package converters
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestPreviewTable(t *testing.T) {
+	rows := [][]any{{"N", "Square"}}
+	for number := 1; number <= 50; number++ {
+		rows = append(rows, []any{number, number * number})
+	}
+	filePath := createTestWorkbook(t, map[string][][]any{"Numbers": rows}, "Numbers")
+
+	head, err := PreviewTable(context.Background(), filePath, PreviewOptions{Rows: 2})
+	if err != nil {
+		t.Fatalf("PreviewTable() error = %v", err)
+	}
+	if want := [][]string{{"1", "1"}, {"2", "4"}}; !reflect.DeepEqual(head.Records(), want) {
+		t.Errorf("PreviewTable(head) = %v, want %v", head.Records(), want)
+	}
+
+	tail, err := PreviewTable(context.Background(), filePath, PreviewOptions{Rows: 2, Mode: PreviewTail})
+	if err != nil {
+		t.Fatalf("PreviewTable() error = %v", err)
+	}
+	if want := [][]string{{"49", "2401"}, {"50", "2500"}}; !reflect.DeepEqual(tail.Records(), want) {
+		t.Errorf("PreviewTable(tail) = %v, want %v", tail.Records(), want)
+	}
+
+	sample, err := PreviewTable(context.Background(), filePath, PreviewOptions{Rows: 5, Mode: PreviewSample, Seed: 3})
+	if err != nil {
+		t.Fatalf("PreviewTable() error = %v", err)
+	}
+	full, err := ReadTable(context.Background(), filePath, TableOptions{})
+	if err != nil {
+		t.Fatalf("ReadTable() error = %v", err)
+	}
+	if want := full.Sample(5, 3); !reflect.DeepEqual(sample.Records(), want.Records()) {
+		t.Errorf("PreviewTable(sample) = %v, want %v", sample.Records(), want.Records())
+	}
+
+	all, err := PreviewTable(context.Background(), filePath, PreviewOptions{TableOptions: TableOptions{InferTypes: true}})
+	if err != nil {
+		t.Fatalf("PreviewTable() error = %v", err)
+	}
+	if all.Len() != 20 || all.Row(19).Get("N") != strconv.Itoa(20) {
+		t.Errorf("PreviewTable() read %d rows, want the first 20", all.Len())
+	}
+	if column, _ := all.Column("Square"); column.Type.String() != "integer" {
+		t.Errorf("PreviewTable() column type = %v, want integer", column.Type)
+	}
+	if _, err := PreviewTable(context.Background(), filePath, PreviewOptions{Rows: -1}); err == nil {
+		t.Error("PreviewTable() should fail for a negative row count")
+	}
+}
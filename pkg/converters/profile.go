@@ -0,0 +1,287 @@
+package converters
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"math/bits"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	. "GoTools/pkg/helpers"
+	"github.com/xuri/excelize/v2"
+)
+
+// ProfileOptions configures profiling worksheets. Sheets selects the worksheets to profile, each on its own.
+// Inference configures inferring the type of every column, see InferenceOptions; its SampleSize only limits the
+// values the type is inferred from, every value being counted. The embedded ReadOptions control how the rows of
+// every worksheet are read.
+type ProfileOptions struct {
+	ReadOptions
+	Sheets    SheetSelector
+	Inference InferenceOptions
+}
+
+// Profile describes the worksheets of a workbook, to size up an unfamiliar file before building a workflow on it.
+type Profile struct {
+	Source string         `json:"source"`
+	Sheets []SheetProfile `json:"sheets"`
+}
+
+// SheetProfile describes the columns of a worksheet holding Rows records.
+type SheetProfile struct {
+	Name    string          `json:"name"`
+	Rows    int             `json:"rows"`
+	Columns []ColumnProfile `json:"columns"`
+}
+
+// ColumnProfile describes the values of a column. Type is inferred from them, see TypeInference, dates being
+// converted as in the XML conversion. Nulls counts the blank values and Distinct the different other ones.
+// Min and Max are the least and the greatest values other than blanks, compared as numbers in number columns
+// and as text otherwise, dates in ISO 8601 comparing in order. MinLength, MaxLength and MeanLength describe the
+// length of the values other than blanks in characters, and Lengths how many of them have a length in each range.
+type ColumnProfile struct {
+	Name       string         `json:"name"`
+	Type       string         `json:"type"`
+	Confidence float64        `json:"confidence"`
+	Nulls      int            `json:"nulls"`
+	Distinct   int            `json:"distinct"`
+	Min        string         `json:"min,omitempty"`
+	Max        string         `json:"max,omitempty"`
+	MinLength  int            `json:"minLength"`
+	MaxLength  int            `json:"maxLength"`
+	MeanLength float64        `json:"meanLength"`
+	Lengths    []LengthBucket `json:"lengths"`
+}
+
+// LengthBucket counts the values of a column whose length in characters is between Min and Max.
+type LengthBucket struct {
+	Min   int `json:"min"`
+	Max   int `json:"max"`
+	Count int `json:"count"`
+}
+
+// ProfileWorkbook profiles the worksheets of the .xlsx file at path selected by opts.Sheets, reading each once.
+// Legacy .xls workbooks, OpenDocument spreadsheets (.ods) and the other sources of ReadOptions are read as well.
+// The profiling stops with the error of ctx as soon as it is cancelled or its deadline passes.
+func ProfileWorkbook(ctx context.Context, path string, opts ProfileOptions) (profile Profile, profileErr error) {
+	if optsErr := opts.ReadOptions.validate(); optsErr != nil {
+		return Profile{}, optsErr
+	}
+	if inferenceErr := opts.Inference.Validate(); inferenceErr != nil {
+		return Profile{}, inferenceErr
+	}
+	file, openErr := openWorkbook(ctx, path, opts.ReadOptions)
+	if openErr != nil {
+		return Profile{}, openErr
+	}
+	defer func(file *excelize.File) {
+		if err := file.Close(); err != nil && profileErr == nil {
+			profileErr = err
+		}
+	}(file)
+
+	sheets, selectErr := opts.selectSheets(file, opts.Sheets)
+	if selectErr != nil {
+		return Profile{}, selectErr
+	}
+	profile.Source = path
+	for _, sheet := range sheets {
+		sheetProfile, err := profileSheet(ctx, file, sheet, opts)
+		if err != nil {
+			return Profile{}, fmt.Errorf("worksheet '%s': %w", sheet, err)
+		}
+		profile.Sheets = append(profile.Sheets, sheetProfile)
+	}
+	return profile, nil
+}
+
+// profileSheet profiles the named worksheet.
+func profileSheet(ctx context.Context, file *excelize.File, sheet string, opts ProfileOptions) (SheetProfile, error) {
+	reader, readerErr := newSheetReader(file, sheet, opts.ReadOptions)
+	if readerErr != nil {
+		return SheetProfile{}, readerErr
+	}
+	profile := SheetProfile{Name: sheet}
+	var profilers []*columnProfiler
+	onHeader := func(columns []string, _ []int) error {
+		for _, name := range RenameDuplicates(columns, false) {
+			profilers = append(profilers, newColumnProfiler(name, opts.Inference))
+		}
+		return nil
+	}
+	rowErr := reader.eachRecord(ctx, onHeader, func(_ int, record []string) error {
+		profile.Rows++
+		for index, profiler := range profilers {
+			profiler.add(ConvertToISO8601(cellAt(record, index)))
+		}
+		return nil
+	})
+	if rowErr != nil {
+		return SheetProfile{}, rowErr
+	}
+	profile.Columns = make([]ColumnProfile, len(profilers))
+	for index, profiler := range profilers {
+		profile.Columns[index] = profiler.result()
+	}
+	return profile, nil
+}
+
+// columnProfiler gathers the profile of a column from its values.
+type columnProfiler struct {
+	name       string
+	inferrer   *TypeInferrer
+	nulls      int
+	distinct   map[string]struct{}
+	minText    string
+	maxText    string
+	numbers    int
+	minNumber  float64
+	maxNumber  float64
+	values     int
+	minLength  int
+	maxLength  int
+	totalChars int
+	lengths    map[int]int
+}
+
+// newColumnProfiler returns the profiler of the column called name, inferring its type as inference says.
+func newColumnProfiler(name string, inference InferenceOptions) *columnProfiler {
+	return &columnProfiler{
+		name:     name,
+		inferrer: NewTypeInferrer(inference),
+		distinct: make(map[string]struct{}),
+		lengths:  make(map[int]int),
+	}
+}
+
+// add counts value, a value of the column.
+func (p *columnProfiler) add(value string) {
+	p.inferrer.Add(value)
+	if len(strings.TrimSpace(value)) < 1 {
+		p.nulls++
+		return
+	}
+	if len(p.distinct) < 1 || value < p.minText {
+		p.minText = value
+	}
+	if len(p.distinct) < 1 || value > p.maxText {
+		p.maxText = value
+	}
+	p.distinct[value] = struct{}{}
+	if number, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+		if p.numbers < 1 || number < p.minNumber {
+			p.minNumber = number
+		}
+		if p.numbers < 1 || number > p.maxNumber {
+			p.maxNumber = number
+		}
+		p.numbers++
+	}
+	length := utf8.RuneCountInString(value)
+	if p.values < 1 || length < p.minLength {
+		p.minLength = length
+	}
+	p.maxLength = max(p.maxLength, length)
+	p.totalChars += length
+	p.values++
+	p.lengths[lengthBucket(length)]++
+}
+
+// lengthBucket returns the index of the bucket of length: 0 for 1 character, 1 for 2 to 3, 2 for 4 to 7 and so
+// on, each bucket twice as wide as the previous one.
+func lengthBucket(length int) int {
+	return bits.Len(uint(length)) - 1
+}
+
+// result returns the profile of the values added.
+func (p *columnProfiler) result() ColumnProfile {
+	inference := p.inferrer.Result()
+	profile := ColumnProfile{
+		Name:       p.name,
+		Type:       inference.Type.String(),
+		Confidence: inference.Confidence,
+		Nulls:      p.nulls,
+		Distinct:   len(p.distinct),
+		Min:        p.minText,
+		Max:        p.maxText,
+		MinLength:  p.minLength,
+		MaxLength:  p.maxLength,
+	}
+	if p.values > 0 {
+		profile.MeanLength = float64(p.totalChars) / float64(p.values)
+	}
+	switch inference.Type {
+	case IntegerCell, DecimalCell, DoubleCell:
+		if p.numbers > 0 {
+			profile.Min = strconv.FormatFloat(p.minNumber, 'f', -1, 64)
+			profile.Max = strconv.FormatFloat(p.maxNumber, 'f', -1, 64)
+		}
+	}
+	profile.Lengths = []LengthBucket{}
+	for bucket := 0; bucket <= lengthBucket(p.maxLength); bucket++ {
+		if count := p.lengths[bucket]; count > 0 {
+			profile.Lengths = append(profile.Lengths, LengthBucket{Min: 1 << bucket, Max: 1<<(bucket+1) - 1, Count: count})
+		}
+	}
+	return profile
+}
+
+// WriteJson writes the profile to w as indented JSON.
+func (p Profile) WriteJson(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(p)
+}
+
+// WriteHtml writes the profile to w as an HTML document holding a table per worksheet, styled as the tables of
+// XlsxToHtml with EmbedCss.
+func (p Profile) WriteHtml(w io.Writer) error {
+	writer := bufio.NewWriter(w)
+	var document strings.Builder
+	document.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	document.WriteString("<title>Profile of " + html.EscapeString(p.Source) + "</title>\n")
+	document.WriteString("<style>\n" + tableCss + "</style>\n</head>\n<body>\n")
+	headings := []string{"Column", "Type", "Confidence", "Nulls", "Distinct", "Min", "Max", "Min length", "Max length", "Mean length", "Lengths"}
+	for _, sheet := range p.Sheets {
+		document.WriteString("<table class=\"" + defaultTableClass + "\">\n<caption>")
+		document.WriteString(html.EscapeString(fmt.Sprintf("%s (%d rows)", sheet.Name, sheet.Rows)) + "</caption>\n<thead>\n<tr>")
+		for _, heading := range headings {
+			document.WriteString("<th>" + heading + "</th>")
+		}
+		document.WriteString("</tr>\n</thead>\n<tbody>\n")
+		for _, column := range sheet.Columns {
+			lengths := make([]string, len(column.Lengths))
+			for index, bucket := range column.Lengths {
+				lengths[index] = fmt.Sprintf("%d-%d: %d", bucket.Min, bucket.Max, bucket.Count)
+			}
+			cells := []string{
+				profileCell(column.Name), profileCell(column.Type), numberCell(strconv.FormatFloat(column.Confidence, 'f', 2, 64)),
+				numberCell(strconv.Itoa(column.Nulls)), numberCell(strconv.Itoa(column.Distinct)), profileCell(column.Min),
+				profileCell(column.Max), numberCell(strconv.Itoa(column.MinLength)), numberCell(strconv.Itoa(column.MaxLength)),
+				numberCell(strconv.FormatFloat(column.MeanLength, 'f', 1, 64)), profileCell(strings.Join(lengths, "\n")),
+			}
+			document.WriteString("<tr>" + strings.Join(cells, "") + "</tr>\n")
+		}
+		document.WriteString("</tbody>\n</table>\n")
+	}
+	document.WriteString("</body>\n</html>\n")
+	if _, err := writer.WriteString(document.String()); err != nil {
+		return err
+	}
+	return writer.Flush()
+}
+
+// profileCell returns value as a table cell.
+func profileCell(value string) string {
+	return "<td>" + htmlCell(value) + "</td>"
+}
+
+// numberCell returns value as a right-aligned table cell.
+func numberCell(value string) string {
+	return "<td class=\"number\">" + html.EscapeString(value) + "</td>"
+}
@@ -0,0 +1,61 @@
+package converters
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestProfileWorkbook(t *testing.T) {
+	filePath := createTestWorkbook(t, map[string][][]any{
+		"Orders": {
+			{"ID", "Customer", "Amount", "Ordered"},
+			{"1", "Acme", "9.5", "2024-03-01"},
+			{"2", "Initech Corporation", "120", "2024-01-15"},
+			{"3", "Acme", "", "2024-02-10"},
+			{"4", "", "n/a", ""},
+		},
+	}, "Orders")
+	profile, err := ProfileWorkbook(context.Background(), filePath, ProfileOptions{Inference: InferenceOptions{MinConfidence: 0.6}})
+	if err != nil {
+		t.Fatalf("ProfileWorkbook() error = %v", err)
+	}
+	if len(profile.Sheets) != 1 || profile.Sheets[0].Name != "Orders" || profile.Sheets[0].Rows != 4 {
+		t.Fatalf("ProfileWorkbook() sheets = %+v", profile.Sheets)
+	}
+	columns := profile.Sheets[0].Columns
+	want := []ColumnProfile{
+		{Name: "ID", Type: "integer", Confidence: 1, Distinct: 4, Min: "1", Max: "4", MinLength: 1, MaxLength: 1, MeanLength: 1,
+			Lengths: []LengthBucket{{Min: 1, Max: 1, Count: 4}}},
+		{Name: "Customer", Type: "string", Confidence: 1, Nulls: 1, Distinct: 2, Min: "Acme", Max: "Initech Corporation", MinLength: 4, MaxLength: 19, MeanLength: 9,
+			Lengths: []LengthBucket{{Min: 4, Max: 7, Count: 2}, {Min: 16, Max: 31, Count: 1}}},
+		{Name: "Amount", Type: "decimal", Confidence: 2.0 / 3, Nulls: 1, Distinct: 3, Min: "9.5", Max: "120", MinLength: 3, MaxLength: 3, MeanLength: 3,
+			Lengths: []LengthBucket{{Min: 2, Max: 3, Count: 3}}},
+		{Name: "Ordered", Type: "date", Confidence: 1, Nulls: 1, Distinct: 3, Min: "2024-01-15", Max: "2024-03-01", MinLength: 10, MaxLength: 10, MeanLength: 10,
+			Lengths: []LengthBucket{{Min: 8, Max: 15, Count: 3}}},
+	}
+	if !reflect.DeepEqual(columns, want) {
+		t.Errorf("ProfileWorkbook() columns =\n%+v\nwant\n%+v", columns, want)
+	}
+
+	var output bytes.Buffer
+	if err := profile.WriteJson(&output); err != nil {
+		t.Fatalf("WriteJson() error = %v", err)
+	}
+	var decoded Profile
+	if err := json.Unmarshal(output.Bytes(), &decoded); err != nil || !reflect.DeepEqual(decoded, profile) {
+		t.Errorf("WriteJson() = %s, %v", output.String(), err)
+	}
+	output.Reset()
+	if err := profile.WriteHtml(&output); err != nil {
+		t.Fatalf("WriteHtml() error = %v", err)
+	}
+	for _, part := range []string{"<caption>Orders (4 rows)</caption>", "<td>Initech Corporation</td>", "<td>4-7: 2<br>16-31: 1</td>"} {
+		if !strings.Contains(output.String(), part) {
+			t.Errorf("WriteHtml() lacks %s:\n%s", part, output.String())
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package converters
+
+import (
+	"context"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// defaultProgressInterval is the number of worksheet rows read between progress reports when no interval is set.
+const defaultProgressInterval = 1000
+
+// progressTracker counts the worksheet rows read by a conversion and reports them every interval rows.
+// A nil tracker reports nothing.
+type progressTracker struct {
+	report   func(done, total int)
+	interval int
+	done     int
+	total    int
+	reported int
+}
+
+// newProgressTracker returns the tracker reporting the progress of converting the named worksheets,
+// or nil if no progress is reported. It counts the rows of the worksheets up front to know the total.
+func (o Options) newProgressTracker(ctx context.Context, file *excelize.File, sheets []string) (*progressTracker, error) {
+	if o.Progress == nil {
+		return nil, nil
+	}
+	tracker := &progressTracker{report: o.Progress, interval: o.ProgressInterval}
+	if tracker.interval <= 0 {
+		tracker.interval = defaultProgressInterval
+	}
+	for _, sheet := range sheets {
+		rows, countErr := countRows(ctx, file, sheet)
+		if countErr != nil {
+			return nil, countErr
+		}
+		tracker.total += rows
+	}
+	return tracker, nil
+}
+
+// countRows returns the number of rows of the named worksheet, as read by a sheetReader.
+func countRows(ctx context.Context, file *excelize.File, sheet string) (int, error) {
+	rows, rowsErr := file.Rows(sheet)
+	if rowsErr != nil {
+		return 0, rowsErr
+	}
+	defer func(rows *excelize.Rows) {
+		_ = rows.Close()
+	}(rows)
+	count := 0
+	for rows.Next() {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		count++
+	}
+	return count, rows.Error()
+}
+
+// advance counts a worksheet row read, reporting the progress when another interval is complete.
+func (p *progressTracker) advance() {
+	if p == nil {
+		return
+	}
+	p.done++
+	if p.done%p.interval == 0 {
+		p.reported = p.done
+		p.report(p.done, p.total)
+	}
+}
+
+// finish reports the final count once the conversion is complete, unless it has just been reported.
+func (p *progressTracker) finish() {
+	if p == nil || p.reported == p.done {
+		return
+	}
+	p.reported = p.done
+	p.report(p.done, p.total)
+}
@@ -0,0 +1,561 @@
+package converters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"GoTools/pkg/fixedwidth"
+	. "GoTools/pkg/helpers"
+	"GoTools/pkg/transforms"
+	"github.com/xuri/excelize/v2"
+)
+
+// FormulaMode selects the value read from cells holding a formula.
+type FormulaMode int
+
+const (
+	// CachedFormulaValue reads the result Excel stored when the workbook was last calculated.
+	CachedFormulaValue FormulaMode = iota
+	// FormulaText reads the formula itself, prefixed with "=".
+	FormulaText
+	// EvaluatedFormula recalculates the formula with the Excelize calculation engine.
+	EvaluatedFormula
+)
+
+// ParseFormulaMode returns the FormulaMode called cached, text or evaluate.
+func ParseFormulaMode(mode string) (FormulaMode, error) {
+	switch mode {
+	case "", "cached":
+		return CachedFormulaValue, nil
+	case "text":
+		return FormulaText, nil
+	case "evaluate":
+		return EvaluatedFormula, nil
+	}
+	return CachedFormulaValue, fmt.Errorf("unknown formula mode '%s'", mode)
+}
+
+// ReadOptions configures how the rows of a worksheet are read, independently of the output format.
+// ResolveMergedCells copies the value of a merged range into every cell it covers,
+// instead of leaving all but the top-left cell blank.
+// Formulas selects the value read from formula cells. Any mode other than CachedFormulaValue
+// looks up the formula of every cell, which loads the whole worksheet into memory.
+// HeaderRowIndex is the zero-based index of the row holding the column names; every row above it is ignored,
+// which skips title banners. SkipRows ignores that many rows directly below the header and
+// SkipFooterRows ignores that many rows at the end of the worksheet, such as totals or notes.
+// Columns restricts the columns read to those whose header passes the filter.
+// ColumnOrder orders the columns read; they keep their sheet order by default.
+// RowFilter, when set, drops every record it returns false for; it sees all columns, including filtered out ones.
+// Password supplies the password of an encrypted workbook.
+// Rows and columns hidden in the workbook are read like any other, unless SkipHiddenRows or SkipHiddenColumns
+// leaves them out, so the output holds what Excel displays. The header row is always read, even when hidden.
+// Leaving out hidden columns looks up the column definitions, which loads the whole worksheet into memory.
+// RichText selects how cells made of differently formatted runs are flattened; any mode other than PlainRichText
+// looks up the runs of every cell, which loads the whole worksheet into memory too.
+// FixedWidth, when set, reads the input as a fixed-width text file instead of a workbook, cut into the fields
+// of the layout: it is loaded into a worksheet named after the file, with a header row holding the names of the
+// fields, so it goes through the same pipeline as a workbook. FixedWidthEncoding names its encoding as in
+// CsvOptions, UTF-8 by default.
+// GoogleSheet, when set, reads the input from a Google Sheets spreadsheet instead, see GoogleSheet.
+// Range, when set, names a defined name or an Excel table to read instead of the whole worksheet, so data blocks
+// which move around the worksheet are still found: the worksheet holding it is read whatever Sheets selects,
+// only its cells are read, and HeaderRowIndex counts from its first row.
+// Region detects where the data of the worksheet starts and ends, see RegionOptions.
+// NullTokens lists the values, such as "N/A" or "#N/A", read as empty cells in the records, so every output writes
+// them as it writes missing values; see IsNullToken and DefaultNullTokens. The header row is read as it is.
+// HeaderMapping, when set, renames the headers to a canonical set of columns before anything else sees them, so
+// Columns, ColumnOrder and RowFilter name the canonical columns; see HeaderMapping.
+// Transforms, when set, runs every record through the pipeline once its null tokens are empty cells and before
+// RowFilter sees it, so the output needs no further cleaning; its stages name the columns as HeaderMapping left
+// them, see transforms.Pipeline. The columns its splits and merges add are the ones Columns, ColumnOrder and
+// RowFilter see.
+type ReadOptions struct {
+	ResolveMergedCells bool
+	Formulas           FormulaMode
+	HeaderRowIndex     int
+	SkipRows           int
+	SkipFooterRows     int
+	Columns            ColumnFilter
+	ColumnOrder        ColumnOrder
+	RowFilter          RowFilter
+	Password           PasswordSource
+	SkipHiddenRows     bool
+	SkipHiddenColumns  bool
+	RichText           RichTextMode
+	FixedWidth         fixedwidth.Layout
+	FixedWidthEncoding string
+	GoogleSheet        *GoogleSheet
+	Range              string
+	Region             RegionOptions
+	NullTokens         []string
+	HeaderMapping      *HeaderMapping
+	Transforms         *transforms.Pipeline
+}
+
+// validate reports options that cannot describe a worksheet.
+func (o ReadOptions) validate() error {
+	if o.HeaderRowIndex < 0 || o.SkipRows < 0 || o.SkipFooterRows < 0 {
+		return errors.New("header row index and skipped row counts must not be negative")
+	}
+	if err := o.Region.validate(); err != nil {
+		return err
+	}
+	if o.HeaderMapping != nil {
+		if err := o.HeaderMapping.validate(); err != nil {
+			return err
+		}
+	}
+	if o.GoogleSheet != nil {
+		if len(o.FixedWidth) > 0 {
+			return errors.New("a Google Sheet cannot be read with a fixed-width layout")
+		}
+		if err := o.GoogleSheet.validate(); err != nil {
+			return err
+		}
+	}
+	if len(o.FixedWidth) > 0 {
+		if err := o.FixedWidth.Validate(); err != nil {
+			return err
+		}
+		if _, err := csvEncoding(o.FixedWidthEncoding); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergedRange is a merged cell range with one-based, inclusive coordinates.
+type mergedRange struct {
+	startCol, startRow int
+	endCol, endRow     int
+	value              string
+}
+
+// sheetReader reads the rows of a single worksheet as plain string slices, applying the ReadOptions.
+type sheetReader struct {
+	file        *excelize.File
+	sheet       string
+	opts        ReadOptions
+	merged      []mergedRange
+	colVisible  map[int]bool
+	comments    map[int]map[int]string
+	commented   map[int]bool
+	linkColumns map[string]bool
+	area        *cellArea
+	tags        TagOptions
+	runs        map[[2]int][]TextRun
+	progress    *progressTracker
+	report      *problemReport
+	// raggedRows hands the records holding values beyond the last header column on as they are, for their
+	// reader to fit them to the header itself.
+	raggedRows bool
+}
+
+// newSheetReader prepares a reader for the named worksheet, loading whatever the ReadOptions require up front.
+func newSheetReader(file *excelize.File, sheet string, opts ReadOptions) (*sheetReader, error) {
+	reader := &sheetReader{file: file, sheet: sheet, opts: opts, colVisible: make(map[int]bool), tags: XmlOptions{}.tagOptions()}
+	if len(opts.Range) > 0 {
+		area, areaErr := findArea(file, opts.Range)
+		if areaErr != nil {
+			return nil, areaErr
+		}
+		if area.sheet != sheet {
+			return nil, fmt.Errorf("'%s' lies on worksheet '%s', not '%s'", opts.Range, area.sheet, sheet)
+		}
+		reader.area = &area
+	}
+	if opts.ResolveMergedCells {
+		mergeCells, mergeErr := file.GetMergeCells(sheet)
+		if mergeErr != nil {
+			return nil, mergeErr
+		}
+		for _, mergeCell := range mergeCells {
+			startCol, startRow, startErr := excelize.CellNameToCoordinates(mergeCell.GetStartAxis())
+			if startErr != nil {
+				return nil, startErr
+			}
+			endCol, endRow, endErr := excelize.CellNameToCoordinates(mergeCell.GetEndAxis())
+			if endErr != nil {
+				return nil, endErr
+			}
+			reader.merged = append(reader.merged, mergedRange{
+				startCol: startCol, startRow: startRow,
+				endCol: endCol, endRow: endRow,
+				value: mergeCell.GetCellValue(),
+			})
+		}
+	}
+	return reader, nil
+}
+
+// each hands every row of the worksheet to yield together with its one-based row number and whether it is hidden.
+// Hidden columns are already left out when SkipHiddenColumns is set, as are the rows and columns outside
+// the Range; indexes then holds the zero-based index each remaining column has in the worksheet,
+// it is nil when no column is left out.
+// Iteration stops at the first error returned by the worksheet or by yield, and with the error of ctx once it is done.
+func (r *sheetReader) each(ctx context.Context, yield func(rowNumber int, hidden bool, columns []string, indexes []int) error) error {
+	rows, rowsErr := r.file.Rows(r.sheet)
+	if rowsErr != nil {
+		return rowsErr
+	}
+	defer func(rows *excelize.Rows) {
+		_ = rows.Close()
+	}(rows)
+
+	rowNumber := 0
+	for rows.Next() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		rowNumber++
+		r.progress.advance()
+		if r.area != nil && rowNumber > r.area.endRow {
+			break
+		}
+		if r.area != nil && rowNumber < r.area.startRow {
+			continue
+		}
+		columns, colErr := rows.Columns()
+		if colErr != nil {
+			return colErr
+		}
+		if r.opts.Formulas != CachedFormulaValue {
+			if columns, colErr = r.applyFormulas(rowNumber, columns); colErr != nil {
+				return colErr
+			}
+		}
+		if r.opts.RichText != PlainRichText {
+			if columns, colErr = r.applyRichText(rowNumber, columns); colErr != nil {
+				return colErr
+			}
+		}
+		columns = r.expandMerged(rowNumber, columns)
+		var indexes []int
+		if r.area != nil || r.opts.SkipHiddenColumns {
+			if indexes, colErr = r.columnIndexes(len(columns)); colErr != nil {
+				return colErr
+			}
+			columns = project(columns, indexes)
+		}
+		if yieldErr := yield(rowNumber, rows.GetRowOpts().Hidden, columns, indexes); yieldErr != nil {
+			return yieldErr
+		}
+	}
+	return rows.Error()
+}
+
+// eachRecord splits the worksheet into its header row, handed to onHeader together with the zero-based index
+// every header column has in the worksheet, and the records below it,
+// handed to onRecord together with their one-based row number, honouring HeaderRowIndex, SkipRows, SkipFooterRows,
+// the data region, the hidden rows, the null tokens, the column filter, the column order and the row filter.
+// Footer rows are held back until enough rows have been read to know they are not part of the footer,
+// so at most SkipFooterRows records are buffered, besides the rows which may be footer notes of the region.
+func (r *sheetReader) eachRecord(ctx context.Context, onHeader func(header []string, indexes []int) error, onRecord func(rowNumber int, columns []string) error) error {
+	type record struct {
+		rowNumber int
+		hidden    bool
+		columns   []string
+		indexes   []int
+	}
+	region := r.opts.Region
+	headerNumber := r.headerNumber()
+	var held, notes []record
+	var header []string
+	var kept []int
+	var projected bool
+	var leading int
+	var run *transforms.Run
+	var width int
+	emit := func(next record) error {
+		held = append(held, next)
+		if len(held) <= r.opts.SkipFooterRows {
+			return nil
+		}
+		next = held[0]
+		held = held[1:]
+		if next.hidden && r.opts.SkipHiddenRows {
+			return nil
+		}
+		if len(r.opts.NullTokens) > 0 {
+			for index, value := range next.columns {
+				next.columns[index] = NormalizeNull(value, r.opts.NullTokens)
+			}
+		}
+		if run != nil {
+			if len(next.columns) > width && !r.raggedRows && !projected {
+				if err := r.truncate(next.rowNumber, next.columns, next.indexes, width); err != nil {
+					return err
+				}
+				next.columns = next.columns[:width]
+			}
+			var err error
+			if next.columns, err = run.Record(next.columns); err != nil {
+				return fmt.Errorf("worksheet '%s', row %d: %w", r.sheet, next.rowNumber, err)
+			}
+		}
+		if r.opts.RowFilter != nil && !r.opts.RowFilter(recordMap(header, next.columns)) {
+			return nil
+		}
+		if projected {
+			next.columns = project(next.columns, kept)
+		} else if len(next.columns) > len(header) && !r.raggedRows {
+			if err := r.truncate(next.rowNumber, next.columns, next.indexes, len(header)); err != nil {
+				return err
+			}
+			next.columns = next.columns[:len(header)]
+		}
+		return onRecord(next.rowNumber, next.columns)
+	}
+	eachErr := r.each(ctx, func(rowNumber int, hidden bool, columns []string, indexes []int) error {
+		switch {
+		case rowNumber < headerNumber:
+			return nil
+		case rowNumber == headerNumber:
+			if region.skipsBlankRow(columns) {
+				headerNumber++
+				return nil
+			}
+			leading = region.leadingColumns(columns)
+			columns, indexes = dropLeading(columns, indexes, leading)
+			if mapping := r.opts.HeaderMapping; mapping != nil {
+				var result HeaderMappingResult
+				columns, result = mapping.Map(columns)
+				if mapping.Report != nil {
+					if err := mapping.Report(r.sheet, result); err != nil {
+						return err
+					}
+				}
+			}
+			if indexes == nil {
+				indexes = make([]int, len(columns))
+				for position := range indexes {
+					indexes[position] = position
+				}
+			}
+			if r.opts.Transforms != nil {
+				var err error
+				if run, err = r.opts.Transforms.Start(columns); err != nil {
+					return fmt.Errorf("worksheet '%s': %w", r.sheet, err)
+				}
+				width = len(columns)
+				columns = run.Header()
+				indexes = projectIndexes(indexes, run.Sources())
+			}
+			header = columns
+			if projected = r.opts.Columns.active() || r.opts.ColumnOrder.Mode != SheetOrder; projected {
+				kept = r.opts.Columns.indexes(columns)
+				kept = r.opts.ColumnOrder.sort(columns, kept)
+				columns = project(columns, kept)
+				indexes = projectIndexes(indexes, kept)
+			}
+			return onHeader(columns, indexes)
+		case rowNumber <= headerNumber+r.opts.SkipRows:
+			return nil
+		}
+		columns, indexes = dropLeading(columns, indexes, leading)
+		next := record{rowNumber: rowNumber, hidden: hidden, columns: columns, indexes: indexes}
+		if region.endsRegion(columns) {
+			return errRegionEnd
+		}
+		if region.mayBeNote(columns, len(header)) {
+			notes = append(notes, next)
+			return nil
+		}
+		for _, note := range notes {
+			if err := emit(note); err != nil {
+				return err
+			}
+		}
+		notes = nil
+		return emit(next)
+	})
+	if errors.Is(eachErr, errRegionEnd) {
+		return nil
+	}
+	return eachErr
+}
+
+// truncate handles the cells of a record beyond the width of the header row, which are left out.
+// Their values are reported, or fail the conversion when no report is collected; empty cells are dropped silently.
+// indexes holds the zero-based index of each cell in the worksheet, it is nil when it matches the position.
+func (r *sheetReader) truncate(rowNumber int, columns []string, indexes []int, width int) error {
+	for position := width; position < len(columns); position++ {
+		value := columns[position]
+		if len(value) < 1 {
+			continue
+		}
+		colIndex := position
+		if indexes != nil {
+			colIndex = indexes[position]
+		}
+		if r.report == nil {
+			cell, _ := excelize.CoordinatesToCellName(colIndex+1, rowNumber)
+			return fmt.Errorf("cell %s of worksheet '%s' holds a value beyond the last header column", cell, r.sheet)
+		}
+		r.report.add(r.sheet, rowNumber, colIndex, "", TruncatedProblem, value, "value is beyond the last header column and is left out")
+	}
+	return nil
+}
+
+// recordMap keys the cells of a record by their header, keeping the first column of repeated headers.
+func recordMap(header, columns []string) map[string]string {
+	record := make(map[string]string, len(header))
+	for index, name := range header {
+		if _, exists := record[name]; exists {
+			continue
+		}
+		if index < len(columns) {
+			record[name] = columns[index]
+		} else {
+			record[name] = ""
+		}
+	}
+	return record
+}
+
+// expandMerged fills the cells of the row covered by a merged range with the value of that range.
+func (r *sheetReader) expandMerged(rowNumber int, columns []string) []string {
+	for _, merged := range r.merged {
+		if rowNumber < merged.startRow || rowNumber > merged.endRow {
+			continue
+		}
+		for len(columns) < merged.endCol {
+			columns = append(columns, "")
+		}
+		for col := merged.startCol; col <= merged.endCol; col++ {
+			columns[col-1] = merged.value
+		}
+	}
+	return columns
+}
+
+// loadComments reads the comments of the worksheet, which comment and hasComments then look up.
+func (r *sheetReader) loadComments() error {
+	comments, commentsErr := r.file.GetComments(r.sheet)
+	if commentsErr != nil {
+		return commentsErr
+	}
+	r.comments = make(map[int]map[int]string)
+	r.commented = make(map[int]bool)
+	for _, comment := range comments {
+		col, row, cellErr := excelize.CellNameToCoordinates(comment.Cell)
+		if cellErr != nil {
+			return cellErr
+		}
+		if r.comments[row] == nil {
+			r.comments[row] = make(map[int]string)
+		}
+		r.comments[row][col-1] = commentText(comment)
+		if row > r.headerNumber() {
+			r.commented[col-1] = true
+		}
+	}
+	return nil
+}
+
+// comment returns the comment of the cell at the one-based row number and zero-based column index, if any.
+func (r *sheetReader) comment(rowNumber, colIndex int) string {
+	return r.comments[rowNumber][colIndex]
+}
+
+// hasComments reports whether any cell below the header row in the zero-based column index holds a comment.
+func (r *sheetReader) hasComments(colIndex int) bool {
+	return r.commented[colIndex]
+}
+
+// hyperlink returns the target of the hyperlink in the cell at the one-based row number and zero-based column index,
+// the URL of external links or the "Sheet!A1" location of links into the workbook; it is empty without a link.
+func (r *sheetReader) hyperlink(rowNumber, colIndex int) (string, error) {
+	cellName, nameErr := excelize.CoordinatesToCellName(colIndex+1, rowNumber)
+	if nameErr != nil {
+		return "", nameErr
+	}
+	exists, target, linkErr := r.file.GetCellHyperLink(r.sheet, cellName)
+	if linkErr != nil || !exists {
+		return "", linkErr
+	}
+	return target, nil
+}
+
+// commentText joins the text runs of a comment, dropping the "Author:" line Excel starts notes with.
+func commentText(comment excelize.Comment) string {
+	text := comment.Text
+	for _, run := range comment.Paragraph {
+		text += run.Text
+	}
+	if len(comment.Author) > 0 && strings.HasPrefix(text, comment.Author+":") {
+		text = strings.TrimLeft(strings.TrimPrefix(text, comment.Author+":"), "\r\n")
+	}
+	return strings.TrimSpace(text)
+}
+
+// headerNumber returns the one-based number of the header row in the worksheet.
+func (r *sheetReader) headerNumber() int {
+	if r.area != nil {
+		return r.area.startRow + r.opts.HeaderRowIndex
+	}
+	return r.opts.HeaderRowIndex + 1
+}
+
+// columnIndexes returns the zero-based indexes of the columns to read among the first width columns: those
+// within the Range, if any, which are not hidden when SkipHiddenColumns is set.
+// The visibility of every column is looked up once and kept for the following rows.
+func (r *sheetReader) columnIndexes(width int) ([]int, error) {
+	first := 0
+	if r.area != nil {
+		first = r.area.startCol - 1
+		width = min(width, r.area.endCol)
+	}
+	indexes := make([]int, 0, max(width-first, 0))
+	for colIndex := first; colIndex < width; colIndex++ {
+		if !r.opts.SkipHiddenColumns {
+			indexes = append(indexes, colIndex)
+			continue
+		}
+		isVisible, known := r.colVisible[colIndex]
+		if !known {
+			colName, nameErr := excelize.ColumnNumberToName(colIndex + 1)
+			if nameErr != nil {
+				return nil, nameErr
+			}
+			var visibleErr error
+			if isVisible, visibleErr = r.file.GetColVisible(r.sheet, colName); visibleErr != nil {
+				return nil, visibleErr
+			}
+			r.colVisible[colIndex] = isVisible
+		}
+		if isVisible {
+			indexes = append(indexes, colIndex)
+		}
+	}
+	return indexes, nil
+}
+
+// applyFormulas replaces the cached value of every formula cell in the row according to the FormulaMode.
+func (r *sheetReader) applyFormulas(rowNumber int, columns []string) ([]string, error) {
+	for colIndex := range columns {
+		cellName, nameErr := excelize.CoordinatesToCellName(colIndex+1, rowNumber)
+		if nameErr != nil {
+			return nil, nameErr
+		}
+		formula, formulaErr := r.file.GetCellFormula(r.sheet, cellName)
+		if formulaErr != nil {
+			return nil, formulaErr
+		}
+		if len(formula) < 1 {
+			continue
+		}
+		if r.opts.Formulas == FormulaText {
+			columns[colIndex] = "=" + formula
+			continue
+		}
+		value, calcErr := r.file.CalcCellValue(r.sheet, cellName)
+		if calcErr != nil {
+			return nil, fmt.Errorf("evaluating formula in %s!%s: %w", r.sheet, cellName, calcErr)
+		}
+		columns[colIndex] = value
+	}
+	return columns, nil
+}
@@ -0,0 +1,98 @@
+package converters
+
+import (
+	"errors"
+	"strings"
+)
+
+// RegionOptions configures the detection of the data region of a worksheet, for workbooks where the table
+// does not start in the first cell or is followed by remarks. Detection is off unless Detect is set; every
+// heuristic then applies unless overridden:
+// the header row is the first row holding a value at or below HeaderRowIndex, unless KeepLeadingBlankRows is set;
+// the columns left of the first value of the header row are left out, unless KeepLeadingBlankColumns is set;
+// the region ends at the first fully blank row below the header, unless KeepBlankRows is set;
+// the rows at the end of the region holding at most NoteCells values, one by default, are taken for footer notes
+// and left out, unless KeepFooterNotes is set. Sparse rows followed by a fuller one are still read as records.
+// Footer notes are only looked for when the header row holds more than NoteCells columns.
+type RegionOptions struct {
+	Detect                  bool
+	KeepLeadingBlankRows    bool
+	KeepLeadingBlankColumns bool
+	KeepBlankRows           bool
+	KeepFooterNotes         bool
+	NoteCells               int
+}
+
+// errRegionEnd stops reading a worksheet at the end of its data region.
+var errRegionEnd = errors.New("end of the data region")
+
+// validate reports region options that cannot describe a data region.
+func (o RegionOptions) validate() error {
+	if o.NoteCells < 0 {
+		return errors.New("the number of cells of footer notes must not be negative")
+	}
+	return nil
+}
+
+// noteCells returns the largest number of values a footer note row holds.
+func (o RegionOptions) noteCells() int {
+	if o.NoteCells < 1 {
+		return 1
+	}
+	return o.NoteCells
+}
+
+// skipsBlankRow reports whether the header is looked for below a row holding columns.
+func (o RegionOptions) skipsBlankRow(columns []string) bool {
+	return o.Detect && !o.KeepLeadingBlankRows && filledCells(columns) < 1
+}
+
+// leadingColumns returns the number of blank cells the header row starts with which are left out.
+func (o RegionOptions) leadingColumns(header []string) int {
+	if !o.Detect || o.KeepLeadingBlankColumns {
+		return 0
+	}
+	for position, value := range header {
+		if len(strings.TrimSpace(value)) > 0 {
+			return position
+		}
+	}
+	return 0
+}
+
+// endsRegion reports whether a record holding columns is past the end of the data region.
+func (o RegionOptions) endsRegion(columns []string) bool {
+	return o.Detect && !o.KeepBlankRows && filledCells(columns) < 1
+}
+
+// mayBeNote reports whether a record holding columns below a header of width columns may be a footer note.
+func (o RegionOptions) mayBeNote(columns []string, width int) bool {
+	return o.Detect && !o.KeepFooterNotes && width > o.noteCells() && filledCells(columns) <= o.noteCells()
+}
+
+// filledCells returns the number of cells holding more than spaces.
+func filledCells(columns []string) int {
+	filled := 0
+	for _, value := range columns {
+		if len(strings.TrimSpace(value)) > 0 {
+			filled++
+		}
+	}
+	return filled
+}
+
+// dropLeading leaves the first count cells out of a row whose cells have the zero-based worksheet indexes,
+// nil when they match the position, and returns the remaining cells with their indexes.
+func dropLeading(columns []string, indexes []int, count int) ([]string, []int) {
+	if count < 1 {
+		return columns, indexes
+	}
+	if indexes == nil {
+		indexes = make([]int, len(columns))
+		for position := range indexes {
+			indexes[position] = position
+		}
+	}
+	count = min(count, len(columns))
+	return columns[count:], indexes[count:]
+}
@@ -0,0 +1,85 @@
+package converters
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func TestRegionDetection(t *testing.T) {
+	filePath := createTestWorkbook(t, map[string][][]any{
+		"Report": {
+			{},
+			{},
+			{nil, "Id", "Item", "Qty"},
+			{nil, 1, "Bolt", 3},
+			{nil, 2, nil, nil},
+			{nil, 3, "Nut", 12},
+			{nil, "Source: stock count"},
+			{},
+			{nil, 9, "Washer", 1},
+			{"Printed 2024-01-31"},
+		},
+	}, "Report")
+
+	tests := []struct {
+		name    string
+		opts    CsvOptions
+		want    string
+		wantErr string
+	}{
+		{
+			name: "Detected",
+			opts: CsvOptions{ReadOptions: ReadOptions{Region: RegionOptions{Detect: true}}},
+			want: "Id,Item,Qty\n1,Bolt,3\n2,,\n3,Nut,12\n",
+		},
+		{
+			name: "Footer Notes Kept",
+			opts: CsvOptions{ReadOptions: ReadOptions{Region: RegionOptions{Detect: true, KeepFooterNotes: true}}},
+			want: "Id,Item,Qty\n1,Bolt,3\n2,,\n3,Nut,12\nSource: stock count,,\n",
+		},
+		{
+			name: "Blank Rows Kept",
+			opts: CsvOptions{ReadOptions: ReadOptions{Region: RegionOptions{Detect: true, KeepBlankRows: true}}},
+			want: "Id,Item,Qty\n1,Bolt,3\n2,,\n3,Nut,12\nSource: stock count,,\n,,\n9,Washer,1\n",
+		},
+		{
+			name: "Leading Columns Kept",
+			opts: CsvOptions{ReadOptions: ReadOptions{Region: RegionOptions{Detect: true, KeepLeadingBlankColumns: true}}},
+			want: ",Id,Item,Qty\n,1,Bolt,3\n,2,,\n,3,Nut,12\n",
+		},
+		{
+			name: "Notes As Wide As The Header",
+			opts: CsvOptions{ReadOptions: ReadOptions{Region: RegionOptions{Detect: true, NoteCells: 3}}},
+			want: "Id,Item,Qty\n1,Bolt,3\n2,,\n3,Nut,12\nSource: stock count,,\n",
+		},
+		{
+			name: "Not Detected",
+			opts: CsvOptions{ReadOptions: ReadOptions{HeaderRowIndex: 2}},
+			want: ",Id,Item,Qty\n,1,Bolt,3\n,2,,\n,3,Nut,12\n,Source: stock count,,\n,,,\n,9,Washer,1\nPrinted 2024-01-31,,,\n",
+		},
+		{
+			name:    "Negative Note Cells",
+			opts:    CsvOptions{ReadOptions: ReadOptions{Region: RegionOptions{Detect: true, NoteCells: -1}}},
+			wantErr: "the number of cells of footer notes must not be negative",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output bytes.Buffer
+			err := XlsxToCsv(context.Background(), &output, filePath, tt.opts)
+			if len(tt.wantErr) > 0 {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("XlsxToCsv() error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("XlsxToCsv() error = %v", err)
+			}
+			if output.String() != tt.want {
+				t.Errorf("XlsxToCsv() output = %q, want %q", output.String(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package converters
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// ReportFormat selects whether and in which format the problems found in the cells of a conversion are reported.
+type ReportFormat int
+
+const (
+	// NoReport reports nothing. Values beyond the header row fail the conversion, other problems go unnoticed.
+	NoReport ReportFormat = iota
+	// JsonReport writes an object holding the source workbook and the list of problems.
+	JsonReport
+	// CsvReport writes a CSV file with a header row and one row per problem.
+	CsvReport
+)
+
+// ParseReportFormat returns the ReportFormat called none, json or csv.
+func ParseReportFormat(name string) (ReportFormat, error) {
+	switch name {
+	case "", "none":
+		return NoReport, nil
+	case "json":
+		return JsonReport, nil
+	case "csv":
+		return CsvReport, nil
+	}
+	return NoReport, fmt.Errorf("unknown report format '%s'", name)
+}
+
+// extension returns the file extension of the report format.
+func (f ReportFormat) extension() string {
+	if f == CsvReport {
+		return ".csv"
+	}
+	return ".json"
+}
+
+// The kinds of problems found in cells.
+const (
+	// DateProblem is a value shaped like a date which is not a valid date, written as is.
+	DateProblem = "date"
+	// TypeProblem is a value which is not valid for the type forced by XmlOptions.ColumnTypes, written without a type.
+	TypeProblem = "type"
+	// CharacterProblem is a value holding characters XML does not allow, which are replaced by U+FFFD.
+	CharacterProblem = "character"
+	// TruncatedProblem is a value beyond the last column of the header row, which is left out.
+	TruncatedProblem = "truncated"
+)
+
+// Problem is a single problem found in a cell. Row is the one-based worksheet row number and Cell the reference
+// of the cell, such as B7. Column is the element name of the column, empty for values beyond the header row.
+type Problem struct {
+	Sheet   string `json:"sheet"`
+	Row     int    `json:"row"`
+	Cell    string `json:"cell"`
+	Column  string `json:"column"`
+	Kind    string `json:"kind"`
+	Value   string `json:"value"`
+	Message string `json:"message"`
+}
+
+// datePattern matches the values ConvertToISO8601 attempts to read as dates.
+var datePattern = regexp.MustCompile(`^\d{1,2}[-/]\d{1,2}[-/]\d{2}( \d{1,2}:\d{2}(:\d{2})?)?$`)
+
+// problemReport collects the problems found in the cells of a conversion. A nil report collects nothing.
+type problemReport struct {
+	format      ReportFormat
+	columnTypes map[string]CellType
+	problems    []Problem
+}
+
+// newProblemReport returns the report collecting the problems of a conversion, or nil if none is requested.
+func (o Options) newProblemReport() *problemReport {
+	if o.ErrorReport == NoReport {
+		return nil
+	}
+	return &problemReport{format: o.ErrorReport, columnTypes: o.Xml.ColumnTypes}
+}
+
+// add records a problem found in the cell at the zero-based column index of the one-based row number.
+func (p *problemReport) add(sheet string, rowNumber, colIndex int, column, kind, value, message string) {
+	cell, _ := excelize.CoordinatesToCellName(colIndex+1, rowNumber)
+	p.problems = append(p.problems, Problem{
+		Sheet: sheet, Row: rowNumber, Cell: cell, Column: column, Kind: kind, Value: value, Message: message,
+	})
+}
+
+// check records the problems of a cell value read from the worksheet, converted is the value written.
+func (p *problemReport) check(sheet string, rowNumber, colIndex int, column, value, converted string) {
+	if p == nil {
+		return
+	}
+	if invalid := strings.IndexFunc(value, func(r rune) bool { return !isXmlChar(r) }); invalid >= 0 {
+		p.add(sheet, rowNumber, colIndex, column, CharacterProblem, value,
+			fmt.Sprintf("character %U is not allowed in XML and is replaced by U+FFFD", []rune(value[invalid:])[0]))
+	}
+	if converted == value && datePattern.MatchString(value) {
+		p.add(sheet, rowNumber, colIndex, column, DateProblem, value, "value looks like a date but is not a valid date")
+	}
+	if cellType, configured := p.columnTypes[column]; configured && len(converted) > 0 {
+		if _, ok := cellType.Canonical(converted); !ok {
+			p.add(sheet, rowNumber, colIndex, column, TypeProblem, value,
+				fmt.Sprintf("value is not a valid %s and is written without a type", xsdType(cellType)))
+		}
+	}
+}
+
+// isXmlChar reports whether r may appear in an XML 1.0 document.
+func isXmlChar(r rune) bool {
+	return r == '\t' || r == '\n' || r == '\r' ||
+		r >= 0x20 && r <= 0xD7FF || r >= 0xE000 && r <= 0xFFFD || r >= 0x10000 && r <= 0x10FFFF
+}
+
+// write writes the report on the problems of converting the workbook called source to w.
+func (p *problemReport) write(w io.Writer, source string) error {
+	if p.format == CsvReport {
+		writer := csv.NewWriter(w)
+		records := [][]string{{"sheet", "row", "cell", "column", "kind", "value", "message"}}
+		for _, problem := range p.problems {
+			records = append(records, []string{
+				problem.Sheet, strconv.Itoa(problem.Row), problem.Cell, problem.Column, problem.Kind, problem.Value, problem.Message,
+			})
+		}
+		return writer.WriteAll(records)
+	}
+	report := struct {
+		Source   string    `json:"source"`
+		Problems []Problem `json:"problems"`
+	}{Source: source, Problems: p.problems}
+	if report.Problems == nil {
+		report.Problems = []Problem{}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", defaultIndent)
+	return encoder.Encode(report)
+}
+
+// reportPath returns the path of the report on converting the workbook at path, written into dir.
+func (p *problemReport) reportPath(dir, path string) string {
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	return filepath.Join(dir, name+"_errors"+p.format.extension())
+}
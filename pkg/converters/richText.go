@@ -0,0 +1,154 @@
+package converters
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// RichTextMode selects how cells whose text is made of differently formatted runs are flattened into a value.
+type RichTextMode int
+
+const (
+	// PlainRichText concatenates the text of the runs, dropping their formatting.
+	PlainRichText RichTextMode = iota
+	// MarkdownRichText wraps bold runs in "**", italic runs in "_" and struck through runs in "~~".
+	MarkdownRichText
+	// XmlRichText writes the runs as <Run> child elements of the cell, whose bold, italic, underline,
+	// strike and color attributes carry the formatting of the run. It cannot be combined with attribute mode.
+	XmlRichText
+)
+
+// ParseRichTextMode returns the RichTextMode called plain, markdown or xml.
+func ParseRichTextMode(mode string) (RichTextMode, error) {
+	switch mode {
+	case "", "plain":
+		return PlainRichText, nil
+	case "markdown":
+		return MarkdownRichText, nil
+	case "xml":
+		return XmlRichText, nil
+	}
+	return PlainRichText, fmt.Errorf("unknown rich text mode '%s'", mode)
+}
+
+// TextRun is a single run of a rich text cell written in XmlRichText mode.
+type TextRun struct {
+	Bold      bool   `xml:"bold,attr,omitempty"`
+	Italic    bool   `xml:"italic,attr,omitempty"`
+	Underline string `xml:"underline,attr,omitempty"`
+	Strike    bool   `xml:"strike,attr,omitempty"`
+	Color     string `xml:"color,attr,omitempty"`
+	Text      string `xml:",chardata"`
+}
+
+// isRichText reports whether the runs carry any formatting, plain strings being read as a single unformatted run.
+func isRichText(runs []excelize.RichTextRun) bool {
+	for _, run := range runs {
+		if run.Font != nil {
+			return true
+		}
+	}
+	return len(runs) > 1
+}
+
+// markdownRichText flattens the runs into text with markdown markers around the formatted runs.
+// Markers enclose the run without its surrounding whitespace, which markdown would not treat as emphasis.
+func markdownRichText(runs []excelize.RichTextRun) string {
+	var text strings.Builder
+	for _, run := range runs {
+		trimmed := strings.TrimSpace(run.Text)
+		if run.Font == nil || len(trimmed) < 1 {
+			text.WriteString(run.Text)
+			continue
+		}
+		var marker string
+		if run.Font.Strike {
+			marker += "~~"
+		}
+		if run.Font.Bold {
+			marker += "**"
+		}
+		if run.Font.Italic {
+			marker += "_"
+		}
+		start := strings.Index(run.Text, trimmed)
+		text.WriteString(run.Text[:start])
+		text.WriteString(marker + trimmed + reverseMarker(marker))
+		text.WriteString(run.Text[start+len(trimmed):])
+	}
+	return text.String()
+}
+
+// reverseMarker returns the closing counterpart of a combined opening marker.
+func reverseMarker(marker string) string {
+	var closing strings.Builder
+	for index := len(marker); index > 0; {
+		switch {
+		case strings.HasSuffix(marker[:index], "~~"), strings.HasSuffix(marker[:index], "**"):
+			closing.WriteString(marker[index-2 : index])
+			index -= 2
+		default:
+			closing.WriteString(marker[index-1 : index])
+			index--
+		}
+	}
+	return closing.String()
+}
+
+// convertRuns converts the runs into the TextRun elements written in XmlRichText mode.
+func convertRuns(runs []excelize.RichTextRun) []TextRun {
+	converted := make([]TextRun, 0, len(runs))
+	for _, run := range runs {
+		textRun := TextRun{Text: run.Text}
+		if run.Font != nil {
+			textRun.Bold, textRun.Italic, textRun.Strike = run.Font.Bold, run.Font.Italic, run.Font.Strike
+			if run.Font.Underline != "none" {
+				textRun.Underline = run.Font.Underline
+			}
+			textRun.Color = strings.TrimPrefix(run.Font.Color, "#")
+		}
+		converted = append(converted, textRun)
+	}
+	return converted
+}
+
+// applyRichText flattens the rich text cells of the row according to the RichTextMode.
+// In XmlRichText mode the cell keeps its plain text and the runs are kept aside for textRuns to look up.
+func (r *sheetReader) applyRichText(rowNumber int, columns []string) ([]string, error) {
+	for colIndex := range columns {
+		if len(columns[colIndex]) < 1 {
+			continue
+		}
+		cellName, nameErr := excelize.CoordinatesToCellName(colIndex+1, rowNumber)
+		if nameErr != nil {
+			return nil, nameErr
+		}
+		runs, runsErr := r.file.GetCellRichText(r.sheet, cellName)
+		if runsErr != nil {
+			return nil, runsErr
+		}
+		if !isRichText(runs) {
+			continue
+		}
+		if r.opts.RichText == MarkdownRichText {
+			columns[colIndex] = markdownRichText(runs)
+			continue
+		}
+		if r.runs == nil {
+			r.runs = make(map[[2]int][]TextRun)
+		}
+		r.runs[[2]int{rowNumber, colIndex}] = convertRuns(runs)
+	}
+	return columns, nil
+}
+
+// textRuns returns the runs of the cell at the one-based row number and zero-based column index,
+// which are only kept in XmlRichText mode for cells holding formatted runs.
+func (r *sheetReader) textRuns(rowNumber, colIndex int) []TextRun {
+	key := [2]int{rowNumber, colIndex}
+	runs := r.runs[key]
+	delete(r.runs, key)
+	return runs
+}
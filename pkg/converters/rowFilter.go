@@ -0,0 +1,276 @@
+package converters
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// RowFilter decides whether a record is converted. It receives the cells of the record keyed by their header,
+// before any cleanup; when headers repeat, the first column with that header is used.
+// Returning false drops the record.
+type RowFilter func(record map[string]string) bool
+
+// ParseRowFilter compiles a filter expression into a RowFilter.
+// An expression compares columns with literals or other columns and combines comparisons with && and ||,
+// optionally negated with ! and grouped with parentheses:
+//
+//	Status != "Cancelled" && (Amount >= 100 || [Customer Type] == "VIP")
+//	Email ~= "@example\\.com$"
+//
+// Bare words and names in square brackets refer to columns, quoted strings and numbers written in decimal digits
+// are literals, so words such as NaN or Inf name columns.
+// == and != compare text, <, <=, > and >= compare numerically when both sides are numbers and as text otherwise,
+// and ~= matches the left side against a regular expression.
+func ParseRowFilter(expr string) (RowFilter, error) {
+	tokens, tokenErr := tokenizeFilter(expr)
+	if tokenErr != nil {
+		return nil, tokenErr
+	}
+	parser := &filterParser{tokens: tokens}
+	filter, parseErr := parser.parseOr()
+	if parseErr != nil {
+		return nil, parseErr
+	}
+	if parser.pos < len(parser.tokens) {
+		return nil, fmt.Errorf("unexpected '%s' in row filter", parser.tokens[parser.pos].text)
+	}
+	return filter, nil
+}
+
+type filterTokenKind int
+
+const (
+	columnToken filterTokenKind = iota
+	literalToken
+	operatorToken
+)
+
+type filterToken struct {
+	kind filterTokenKind
+	text string
+}
+
+// tokenizeFilter splits a filter expression into columns, literals and operators.
+func tokenizeFilter(expr string) ([]filterToken, error) {
+	var tokens []filterToken
+	runes := []rune(expr)
+	for pos := 0; pos < len(runes); {
+		char := runes[pos]
+		switch {
+		case unicode.IsSpace(char):
+			pos++
+		case char == '"':
+			var literal strings.Builder
+			pos++
+			for ; pos < len(runes) && runes[pos] != '"'; pos++ {
+				if runes[pos] == '\\' && pos+1 < len(runes) {
+					pos++
+				}
+				literal.WriteRune(runes[pos])
+			}
+			if pos >= len(runes) {
+				return nil, fmt.Errorf("unterminated string in row filter")
+			}
+			pos++
+			tokens = append(tokens, filterToken{kind: literalToken, text: literal.String()})
+		case char == '[':
+			end := pos + 1
+			for end < len(runes) && runes[end] != ']' {
+				end++
+			}
+			if end >= len(runes) {
+				return nil, fmt.Errorf("unterminated column name in row filter")
+			}
+			tokens = append(tokens, filterToken{kind: columnToken, text: string(runes[pos+1 : end])})
+			pos = end + 1
+		case strings.ContainsRune("=!<>~&|()", char):
+			operator := string(char)
+			if pos+1 < len(runes) {
+				switch pair := string(runes[pos : pos+2]); pair {
+				case "==", "!=", "<=", ">=", "~=", "&&", "||":
+					operator = pair
+				}
+			}
+			switch operator {
+			case "=", "~", "&", "|":
+				return nil, fmt.Errorf("unknown operator '%s' in row filter", operator)
+			}
+			tokens = append(tokens, filterToken{kind: operatorToken, text: operator})
+			pos += len(operator)
+		default:
+			start := pos
+			for pos < len(runes) && !unicode.IsSpace(runes[pos]) && !strings.ContainsRune("=!<>~&|()\"[", runes[pos]) {
+				pos++
+			}
+			word := string(runes[start:pos])
+			if _, isNumber := parseNumber(word); isNumber {
+				tokens = append(tokens, filterToken{kind: literalToken, text: word})
+			} else {
+				tokens = append(tokens, filterToken{kind: columnToken, text: word})
+			}
+		}
+	}
+	return tokens, nil
+}
+
+// filterParser is a recursive descent parser over the tokens of a filter expression.
+type filterParser struct {
+	tokens []filterToken
+	pos    int
+}
+
+// accept consumes the next token if it is the given operator.
+func (p *filterParser) accept(operator string) bool {
+	if p.pos < len(p.tokens) && p.tokens[p.pos].kind == operatorToken && p.tokens[p.pos].text == operator {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *filterParser) parseOr() (RowFilter, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("||") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		first, second := left, right
+		left = func(record map[string]string) bool { return first(record) || second(record) }
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseAnd() (RowFilter, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("&&") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		first, second := left, right
+		left = func(record map[string]string) bool { return first(record) && second(record) }
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseUnary() (RowFilter, error) {
+	if p.accept("!") {
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return func(record map[string]string) bool { return !inner(record) }, nil
+	}
+	if p.accept("(") {
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.accept(")") {
+			return nil, fmt.Errorf("missing ')' in row filter")
+		}
+		return inner, nil
+	}
+	return p.parseComparison()
+}
+
+// operand returns a function resolving the next column or literal token against a record.
+func (p *filterParser) operand() (func(map[string]string) string, filterToken, error) {
+	if p.pos >= len(p.tokens) {
+		return nil, filterToken{}, fmt.Errorf("row filter ends unexpectedly")
+	}
+	token := p.tokens[p.pos]
+	p.pos++
+	switch token.kind {
+	case columnToken:
+		return func(record map[string]string) string { return record[token.text] }, token, nil
+	case literalToken:
+		return func(map[string]string) string { return token.text }, token, nil
+	}
+	return nil, token, fmt.Errorf("unexpected '%s' in row filter", token.text)
+}
+
+func (p *filterParser) parseComparison() (RowFilter, error) {
+	left, _, err := p.operand()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos >= len(p.tokens) || p.tokens[p.pos].kind != operatorToken {
+		return nil, fmt.Errorf("missing comparison operator in row filter")
+	}
+	operator := p.tokens[p.pos].text
+	p.pos++
+	right, rightToken, err := p.operand()
+	if err != nil {
+		return nil, err
+	}
+	switch operator {
+	case "==":
+		return func(record map[string]string) bool { return left(record) == right(record) }, nil
+	case "!=":
+		return func(record map[string]string) bool { return left(record) != right(record) }, nil
+	case "<", "<=", ">", ">=":
+		return func(record map[string]string) bool {
+			return compareValues(left(record), right(record), operator)
+		}, nil
+	case "~=":
+		if rightToken.kind != literalToken {
+			return nil, fmt.Errorf("~= needs a quoted regular expression in row filter")
+		}
+		pattern, patternErr := regexp.Compile(rightToken.text)
+		if patternErr != nil {
+			return nil, fmt.Errorf("invalid regular expression in row filter: %w", patternErr)
+		}
+		return func(record map[string]string) bool { return pattern.MatchString(left(record)) }, nil
+	}
+	return nil, fmt.Errorf("unknown comparison operator '%s' in row filter", operator)
+}
+
+// compareValues orders two values numerically when both are numbers and as text otherwise.
+func compareValues(left, right, operator string) bool {
+	var order int
+	leftNumber, leftIsNumber := parseNumber(strings.TrimSpace(left))
+	rightNumber, rightIsNumber := parseNumber(strings.TrimSpace(right))
+	numbers := leftIsNumber && rightIsNumber
+	switch {
+	case numbers && leftNumber < rightNumber:
+		order = -1
+	case numbers && leftNumber > rightNumber:
+		order = 1
+	case numbers:
+		order = 0
+	default:
+		order = strings.Compare(left, right)
+	}
+	switch operator {
+	case "<":
+		return order < 0
+	case "<=":
+		return order <= 0
+	case ">":
+		return order > 0
+	default:
+		return order >= 0
+	}
+}
+
+// parseNumber reads value as a number written in decimal digits, with an optional sign, point and exponent, as
+// DoubleCell reads it. Words such as Inf or NaN and hexadecimal numbers, which strconv.ParseFloat accepts, are not
+// numbers.
+func parseNumber(value string) (float64, bool) {
+	if _, ok := DoubleCell.Canonical(value); !ok {
+		return 0, false
+	}
+	number, err := strconv.ParseFloat(value, 64)
+	return number, err == nil
+}
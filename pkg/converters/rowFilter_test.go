@@ -0,0 +1,42 @@
+package converters
+
+import "testing"
+
+func TestParseRowFilter(t *testing.T) {
+	record := map[string]string{"Status": "Cancelled", "Amount": "250", "Customer Type": "VIP", "Email": "ann@example.com",
+		"Score": "NaN", "Inf": "5"}
+	tests := []struct {
+		expr    string
+		want    bool
+		wantErr bool
+	}{
+		{expr: `Status == "Cancelled"`, want: true},
+		{expr: `Status != "Cancelled"`, want: false},
+		{expr: `Amount > 99`, want: true},
+		{expr: `Amount <= 100`, want: false},
+		{expr: `[Customer Type] == "VIP" && Amount >= 250`, want: true},
+		{expr: `Status == "Open" || Amount < 300`, want: true},
+		{expr: `!(Status == "Open" || Amount < 300)`, want: false},
+		{expr: `Email ~= "@example\\.com$"`, want: true},
+		{expr: `Missing == ""`, want: true},
+		{expr: `Amount > 1.5e2`, want: true},
+		{expr: `Inf == "5"`, want: true},
+		{expr: `Score > 100`, want: true},
+		{expr: `Score < "NaN0"`, want: true},
+		{expr: `Status = "Cancelled"`, wantErr: true},
+		{expr: `Status == "Cancelled`, wantErr: true},
+		{expr: `(Status == "Cancelled"`, wantErr: true},
+		{expr: `Email ~= Status`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			filter, err := ParseRowFilter(tt.expr)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRowFilter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && filter(record) != tt.want {
+				t.Errorf("filter() = %v, want %v", !tt.want, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,456 @@
+package converters
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+
+	. "GoTools/pkg/helpers"
+	"github.com/parquet-go/parquet-go"
+	"github.com/xuri/excelize/v2"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+// RowSink is a target records are written to, such as an XML, CSV, JSON, .xlsx, SQL or Parquet output or an HTTP
+// endpoint, the counterpart of RowSource: a conversion copies a source, whose records may run through transforms
+// on the way, into a sink with Convert, so a new output format only implements RowSink.
+// WriteHeader is called once with the header, then WriteRecord with every record, holding a value per column,
+// and Close once they are written, completing the output. Close is called even when the conversion fails, so the
+// sink releases what it holds; a sink closed before its header is written writes nothing.
+type RowSink interface {
+	WriteHeader(header []string) error
+	WriteRecord(record []string) error
+	Close() error
+}
+
+// Convert copies the records of source into sink and closes it, such as
+//
+//	err := Convert(ctx, TransformSource(CsvSource(path, CsvToXlsxOptions{}), pipeline), XmlSink(w, XmlOptions{}))
+//
+// The conversion stops with the error of ctx as soon as it is cancelled or its deadline passes.
+func Convert(ctx context.Context, source RowSource, sink RowSink) error {
+	copyErr := source.Rows(ctx, sink.WriteHeader, sink.WriteRecord)
+	closeErr := sink.Close()
+	if copyErr != nil {
+		return copyErr
+	}
+	return closeErr
+}
+
+// csvSink writes CSV.
+type csvSink struct {
+	w          io.Writer
+	opts       CsvOptions
+	writer     *csvWriter
+	transcoder *transform.Writer
+}
+
+// CsvSink returns a RowSink writing CSV to w in the dialect and the encoding of opts, as XlsxToCsv does; its
+// ReadOptions and Sheets are ignored.
+func CsvSink(w io.Writer, opts CsvOptions) RowSink {
+	return &csvSink{w: w, opts: opts}
+}
+
+// open prepares the writer of the records, writing the byte order marks only when the output is not resumed.
+func (s *csvSink) open(resume bool) error {
+	if err := s.opts.validate(); err != nil {
+		return err
+	}
+	w := s.w
+	if s.opts.ByteOrderMark && !resume {
+		if _, err := w.Write(utf8BOM); err != nil {
+			return err
+		}
+	}
+	if target, _ := csvEncoding(s.opts.Encoding); target != nil {
+		if canonical, _ := canonicalEncoding(s.opts.Encoding); canonical == "UTF-16" && resume {
+			target = unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM)
+		}
+		s.transcoder = transform.NewWriter(w, target.NewEncoder())
+		w = s.transcoder
+	}
+	s.writer = &csvWriter{w: bufio.NewWriter(w)}
+	s.writer.delimiter, s.writer.quote, s.writer.newline = s.opts.dialect()
+	return nil
+}
+
+func (s *csvSink) WriteHeader(header []string) error {
+	if err := s.open(false); err != nil {
+		return err
+	}
+	if s.opts.OmitHeader {
+		return nil
+	}
+	if s.opts.TransliterateHeaders {
+		names := make([]string, len(header))
+		for index, name := range header {
+			names[index] = Transliterate(name)
+		}
+		header = names
+	}
+	return s.writer.write(header)
+}
+
+func (s *csvSink) WriteRecord(record []string) error {
+	values := make([]string, len(record))
+	for index, value := range record {
+		values[index] = ConvertToISO8601(value)
+	}
+	return s.writer.write(values)
+}
+
+func (s *csvSink) Resume([]string, int) error {
+	return s.open(true)
+}
+
+func (s *csvSink) Flush() error {
+	return s.writer.w.Flush()
+}
+
+func (s *csvSink) Close() error {
+	if s.writer == nil {
+		return nil
+	}
+	if err := s.Flush(); err != nil {
+		return err
+	}
+	if s.transcoder != nil {
+		return s.transcoder.Close()
+	}
+	return nil
+}
+
+// xmlSink writes an XML document.
+type xmlSink struct {
+	w       io.Writer
+	opts    XmlOptions
+	encoder *documentEncoder
+	table   xml.StartElement
+	names   []string
+}
+
+// XmlSink returns a RowSink writing a DataTable document to w, shaped by opts as XlsxToXml shapes the document of
+// a single worksheet, with an element per column named after the cleaned header.
+func XmlSink(w io.Writer, opts XmlOptions) RowSink {
+	return &xmlSink{w: w, opts: opts}
+}
+
+func (s *xmlSink) WriteHeader(header []string) error {
+	if err := s.opts.validate(); err != nil {
+		return err
+	}
+	encoder, encoderErr := s.opts.newEncoder(s.w)
+	if encoderErr != nil {
+		return encoderErr
+	}
+	s.encoder = encoder
+	s.names = cleanHeaders(header, s.opts.tagOptions())
+	s.table = xml.StartElement{Name: xml.Name{Local: s.opts.rootName()}, Attr: append(s.opts.namespaceDecls(), s.opts.namespaceAttrs()...)}
+	return s.encoder.EncodeToken(s.table)
+}
+
+func (s *xmlSink) WriteRecord(record []string) error {
+	var dataRow DataRow
+	for index, name := range s.names {
+		var value string
+		if index < len(record) {
+			value = ConvertToISO8601(record[index])
+		}
+		dataRow.Columns = append(dataRow.Columns, DataColumn{XMLName: xml.Name{Local: name}, Value: value})
+	}
+	s.opts.shapeRow(&dataRow)
+	return s.encoder.EncodeElement(dataRow, xml.StartElement{Name: xml.Name{Local: s.opts.rowName()}})
+}
+
+func (s *xmlSink) Close() error {
+	if s.encoder == nil {
+		return nil
+	}
+	if err := s.encoder.EncodeToken(s.table.End()); err != nil {
+		return err
+	}
+	return s.encoder.finish()
+}
+
+// jsonSink writes JSON.
+type jsonSink struct {
+	w       *bufio.Writer
+	opts    JsonOptions
+	header  []string
+	values  [][]json.RawMessage
+	written int
+	started bool
+}
+
+// JsonSink returns a RowSink writing JSON to w in the layout of opts, as XlsxToJson writes a single worksheet;
+// its ReadOptions, Sheets and Combine are ignored. ColumnsLayout holds the records in memory until the sink is
+// closed.
+func JsonSink(w io.Writer, opts JsonOptions) RowSink {
+	return &jsonSink{w: bufio.NewWriter(w), opts: opts}
+}
+
+func (s *jsonSink) WriteHeader(header []string) error {
+	s.opts.Combine = false
+	if err := s.opts.validate(); err != nil {
+		return err
+	}
+	s.header = RenameDuplicates(header, false)
+	s.values = make([][]json.RawMessage, len(s.header))
+	s.started = true
+	return nil
+}
+
+func (s *jsonSink) Resume(header []string, written int) error {
+	if s.opts.Layout == ColumnsLayout {
+		return errors.New("a JSON output in the columns layout cannot be resumed")
+	}
+	if err := s.WriteHeader(header); err != nil {
+		return err
+	}
+	s.written = written
+	return nil
+}
+
+func (s *jsonSink) Flush() error {
+	return s.w.Flush()
+}
+
+func (s *jsonSink) WriteRecord(record []string) error {
+	if s.opts.Layout == ColumnsLayout {
+		for index := range s.header {
+			s.values[index] = append(s.values[index], s.opts.jsonCell(record, index))
+		}
+		return nil
+	}
+	var object bytes.Buffer
+	switch {
+	case s.opts.Lines:
+	case s.written > 0:
+		object.WriteString(",\n" + defaultIndent)
+	default:
+		object.WriteString("[\n" + defaultIndent)
+	}
+	object.WriteString("{")
+	for index, name := range s.header {
+		if index > 0 {
+			object.WriteString(",")
+		}
+		object.WriteString(jsonString(name) + ":")
+		object.Write(s.opts.jsonCell(record, index))
+	}
+	object.WriteString("}")
+	if s.opts.Lines {
+		object.WriteString("\n")
+	}
+	s.written++
+	_, err := s.w.Write(object.Bytes())
+	return err
+}
+
+func (s *jsonSink) Close() error {
+	if !s.started {
+		return nil
+	}
+	var end string
+	switch {
+	case s.opts.Layout == ColumnsLayout:
+		if err := writeJsonColumns(s.w, s.header, s.values, ""); err != nil {
+			return err
+		}
+		end = "\n"
+	case s.opts.Lines:
+	case s.written == 0:
+		end = "[]\n"
+	default:
+		end = "\n]\n"
+	}
+	if _, err := s.w.WriteString(end); err != nil {
+		return err
+	}
+	return s.w.Flush()
+}
+
+// xlsxSink writes a workbook.
+type xlsxSink struct {
+	path   string
+	opts   StyleOptions
+	layout *sheetLayout
+	rows   []flatRow
+}
+
+// XlsxSink returns a RowSink writing the .xlsx workbook at path, holding the records in a worksheet styled as set
+// by opts, as DataTable.WriteXlsx does; the worksheet is called Sheet1 unless opts names it. The records are held
+// in memory until the sink is closed.
+func XlsxSink(path string, opts StyleOptions) RowSink {
+	return &xlsxSink{path: path, opts: opts}
+}
+
+func (s *xlsxSink) WriteHeader(header []string) error {
+	name := s.opts.SheetName
+	if len(name) < 1 {
+		name = "Sheet1"
+	}
+	layout := newSheetLayout(validSheetName(name, 0, nil))
+	for _, column := range header {
+		layout.add(flatRow{{column: column}}, false)
+	}
+	if err := s.opts.validate(layout); err != nil {
+		return err
+	}
+	s.layout = layout
+	return nil
+}
+
+func (s *xlsxSink) WriteRecord(record []string) error {
+	row := make(flatRow, 0, len(record))
+	for index, value := range record {
+		if index < len(s.layout.columns) {
+			row = append(row, flatCell{column: s.layout.columns[index], value: ConvertToISO8601(value)})
+		}
+	}
+	if s.opts.AutoWidth {
+		s.layout.add(row, true)
+	}
+	s.rows = append(s.rows, row)
+	return nil
+}
+
+func (s *xlsxSink) Close() (closeErr error) {
+	if s.layout == nil {
+		return nil
+	}
+	file := excelize.NewFile()
+	defer func(file *excelize.File) {
+		if err := file.Close(); err != nil && closeErr == nil {
+			closeErr = err
+		}
+	}(file)
+	if err := createSheet(file, 0, s.layout.name); err != nil {
+		return err
+	}
+	if err := s.opts.writeSheet(file, s.layout, s.rows); err != nil {
+		return err
+	}
+	return file.SaveAs(s.path)
+}
+
+// sqlSink writes an SQL script.
+type sqlSink struct {
+	w      *bufio.Writer
+	opts   SqlOptions
+	script sqlScript
+	batch  [][]string
+	count  int
+}
+
+// SqlSink returns a RowSink writing the SQL script inserting or upserting the records into the table opts.Table
+// to w, as XlsxToSql does. Types are not inferred: the columns opts.ColumnTypes does not type are strings. Its
+// ReadOptions, Sheets, InferTypes and Inference are ignored.
+func SqlSink(w io.Writer, opts SqlOptions) RowSink {
+	return &sqlSink{w: bufio.NewWriter(w), opts: opts}
+}
+
+func (s *sqlSink) WriteHeader(header []string) error {
+	if err := s.opts.validate(); err != nil {
+		return err
+	}
+	if len(s.opts.Table) < 1 {
+		return errors.New("no table to write the records into")
+	}
+	columns, columnsErr := s.opts.tableColumns(header, make([]CellType, len(header)))
+	if columnsErr != nil {
+		return columnsErr
+	}
+	s.script = sqlScript{w: s.w, dialect: s.opts.Dialect, statement: s.opts.Statement, table: s.opts.Table, columns: columns}
+	if s.opts.CreateTable {
+		return s.script.createTable()
+	}
+	return nil
+}
+
+func (s *sqlSink) WriteRecord(record []string) error {
+	s.count++
+	values, err := s.script.literals(record)
+	if err != nil {
+		return fmt.Errorf("record %d: %w", s.count, err)
+	}
+	if s.batch = append(s.batch, values); len(s.batch) < max(s.opts.BatchSize, 1) {
+		return nil
+	}
+	err = s.script.write(s.batch)
+	s.batch = s.batch[:0]
+	return err
+}
+
+func (s *sqlSink) Resume(header []string, written int) error {
+	s.opts.CreateTable = false
+	if err := s.WriteHeader(header); err != nil {
+		return err
+	}
+	s.count = written
+	return nil
+}
+
+// Flush writes the statements of the records batched so far, ending the batch early.
+func (s *sqlSink) Flush() error {
+	if len(s.batch) > 0 {
+		err := s.script.write(s.batch)
+		s.batch = s.batch[:0]
+		if err != nil {
+			return err
+		}
+	}
+	return s.w.Flush()
+}
+
+func (s *sqlSink) Close() error {
+	if s.script.w == nil {
+		return nil
+	}
+	return s.Flush()
+}
+
+// parquetSink writes a Parquet file.
+type parquetSink struct {
+	w      io.Writer
+	opts   ParquetOptions
+	table  parquetTable
+	writer *parquet.Writer
+	count  int
+}
+
+// ParquetSink returns a RowSink writing the records to w as a Parquet file, as XlsxToParquet does. Types are not
+// inferred: the columns opts.ColumnTypes does not type are strings. Its ReadOptions, Sheets, InferTypes and
+// Inference are ignored.
+func ParquetSink(w io.Writer, opts ParquetOptions) RowSink {
+	return &parquetSink{w: w, opts: opts}
+}
+
+func (s *parquetSink) WriteHeader(header []string) error {
+	s.table = newParquetTable("records", header, make([]CellType, len(header)), s.opts.ColumnTypes)
+	s.writer = parquet.NewWriter(s.w, s.table.schema, parquet.Compression(s.opts.Compression.codec()))
+	return nil
+}
+
+func (s *parquetSink) WriteRecord(record []string) error {
+	s.count++
+	row, err := s.table.row(record)
+	if err != nil {
+		return fmt.Errorf("record %d: %w", s.count, err)
+	}
+	_, err = s.writer.WriteRows([]parquet.Row{row})
+	return err
+}
+
+func (s *parquetSink) Close() error {
+	if s.writer == nil {
+		return nil
+	}
+	return s.writer.Close()
+}
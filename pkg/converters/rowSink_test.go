@@ -0,0 +1,145 @@
+package converters
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"GoTools/pkg/datatable"
+)
+
+// sinkTestTable returns the table the sinks are tested with.
+func sinkTestTable(t *testing.T) *datatable.DataTable {
+	t.Helper()
+	table, err := datatable.FromRecords([]string{"Id", "Name", "Joined"}, [][]string{{"1", "O'Brien", "12-25-20"}, {"2", "Bob, Jr.", ""}})
+	if err != nil {
+		t.Fatalf("FromRecords() error = %v", err)
+	}
+	return table
+}
+
+func TestWriterSinks(t *testing.T) {
+	tests := []struct {
+		name string
+		sink func(w io.Writer) RowSink
+		want string
+	}{
+		{
+			name: "Csv",
+			sink: func(w io.Writer) RowSink { return CsvSink(w, CsvOptions{}) },
+			want: "Id,Name,Joined\n1,O'Brien,2020-12-25 00:00:00\n2,\"Bob, Jr.\",\n",
+		},
+		{
+			name: "Xml",
+			sink: func(w io.Writer) RowSink {
+				return XmlSink(w, XmlOptions{RootName: "People", RowName: "Person", Compact: true})
+			},
+			want: "<People><Person><Id>1</Id><Name>O&#39;Brien</Name><Joined>2020-12-25 00:00:00</Joined></Person>" +
+				"<Person><Id>2</Id><Name>Bob, Jr.</Name><Joined></Joined></Person></People>",
+		},
+		{
+			name: "Json",
+			sink: func(w io.Writer) RowSink { return JsonSink(w, JsonOptions{InferTypes: true}) },
+			want: "[\n  {\"Id\":1,\"Name\":\"O'Brien\",\"Joined\":\"2020-12-25T00:00:00\"},\n  {\"Id\":2,\"Name\":\"Bob, Jr.\",\"Joined\":null}\n]\n",
+		},
+		{
+			name: "Json Lines",
+			sink: func(w io.Writer) RowSink { return JsonSink(w, JsonOptions{Lines: true}) },
+			want: "{\"Id\":\"1\",\"Name\":\"O'Brien\",\"Joined\":\"2020-12-25 00:00:00\"}\n{\"Id\":\"2\",\"Name\":\"Bob, Jr.\",\"Joined\":\"\"}\n",
+		},
+		{
+			name: "Sql",
+			sink: func(w io.Writer) RowSink {
+				return SqlSink(w, SqlOptions{Table: "people", ColumnTypes: map[string]CellType{"Id": IntegerCell}, BatchSize: 5})
+			},
+			want: "INSERT INTO \"people\" (\"Id\", \"Name\", \"Joined\") VALUES\n  (1, 'O''Brien', '2020-12-25 00:00:00'),\n  (2, 'Bob, Jr.', NULL);\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output bytes.Buffer
+			if err := Convert(context.Background(), TableSource(sinkTestTable(t)), tt.sink(&output)); err != nil {
+				t.Fatalf("Convert() error = %v", err)
+			}
+			if output.String() != tt.want {
+				t.Errorf("Convert() = %q, want %q", output.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestSqlSinkInvalidValue(t *testing.T) {
+	var output bytes.Buffer
+	err := Convert(context.Background(), TableSource(sinkTestTable(t)), SqlSink(&output, SqlOptions{Table: "people", ColumnTypes: map[string]CellType{"Name": IntegerCell}}))
+	if err == nil || !strings.Contains(err.Error(), "record 1: 'O'Brien' in column 'Name' is not a valid BIGINT") {
+		t.Errorf("Convert() error = %v", err)
+	}
+}
+
+func TestFileSinks(t *testing.T) {
+	outPath := filepath.Join(t.TempDir(), "people.xlsx")
+	if err := Convert(context.Background(), TableSource(sinkTestTable(t)), XlsxSink(outPath, StyleOptions{SheetName: "People", CoerceTypes: true})); err != nil {
+		t.Fatalf("Convert(XlsxSink) error = %v", err)
+	}
+	table, err := ReadTable(context.Background(), outPath, TableOptions{Sheets: SheetByName("People")})
+	if err != nil {
+		t.Fatalf("ReadTable() error = %v", err)
+	}
+	if want := []string{"Id", "Name", "Joined"}; !reflect.DeepEqual(table.ColumnNames(), want) || table.Len() != 2 {
+		t.Errorf("ReadTable() = %v, %d rows", table.ColumnNames(), table.Len())
+	}
+
+	var output bytes.Buffer
+	if err := Convert(context.Background(), TableSource(sinkTestTable(t)), ParquetSink(&output, ParquetOptions{ColumnTypes: map[string]CellType{"Id": IntegerCell}})); err != nil {
+		t.Fatalf("Convert(ParquetSink) error = %v", err)
+	}
+	schema, rows := readParquet(t, output.Bytes())
+	if schema["Id"] != "INT64" || schema["Name"] != "BYTE_ARRAY" || len(rows) != 2 {
+		t.Errorf("ParquetSink schema = %v, rows = %v", schema, rows)
+	}
+}
+
+func TestHttpSink(t *testing.T) {
+	var mutex sync.Mutex
+	var batches [][]map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" || r.Method != http.MethodPut {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var batch []map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		mutex.Lock()
+		batches = append(batches, batch)
+		mutex.Unlock()
+	}))
+	defer server.Close()
+
+	opts := HttpOptions{Method: http.MethodPut, Headers: map[string]string{"Authorization": "Bearer token"}, BatchSize: 1, InferTypes: true}
+	if err := Convert(context.Background(), TableSource(sinkTestTable(t)), HttpSink(context.Background(), server.URL, opts)); err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+	want := [][]map[string]any{
+		{{"Id": float64(1), "Name": "O'Brien", "Joined": "2020-12-25T00:00:00"}},
+		{{"Id": float64(2), "Name": "Bob, Jr.", "Joined": nil}},
+	}
+	if !reflect.DeepEqual(batches, want) {
+		t.Errorf("HttpSink batches = %v, want %v", batches, want)
+	}
+
+	err := Convert(context.Background(), TableSource(sinkTestTable(t)), HttpSink(context.Background(), server.URL, HttpOptions{}))
+	if err == nil || !strings.Contains(err.Error(), "401 Unauthorized") {
+		t.Errorf("Convert() error = %v, want 401 Unauthorized", err)
+	}
+}
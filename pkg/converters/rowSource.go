@@ -0,0 +1,398 @@
+package converters
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"GoTools/pkg/datatable"
+	"GoTools/pkg/fixedwidth"
+	"GoTools/pkg/transforms"
+	"github.com/xuri/excelize/v2"
+	"golang.org/x/text/transform"
+)
+
+// RowSource is a source of records under a header row, such as a worksheet, a CSV, JSON or fixed-width text file
+// or the result of a database query, so the same code reads them all. Sources are read one record at a time and
+// can be chained, such as through TransformSource and FilterSource, without holding the records in memory.
+// Rows hands the header to onHeader, then every record to onRecord, each holding a value per column, stopping at
+// the first error they return and with the error of ctx once it is done. Every call reads the source from its
+// start. Blank header names are called Column<n>, n being their one-based position, and repeated ones are renamed
+// as RenameDuplicates does.
+type RowSource interface {
+	Rows(ctx context.Context, onHeader func(header []string) error, onRecord func(record []string) error) error
+}
+
+// fitRecord returns the record numbered number padded with blank values to width, failing when it holds values
+// beyond the last column; empty ones are dropped.
+func fitRecord(number int, record []string, width int) ([]string, error) {
+	if len(record) > width {
+		if len(strings.Join(record[width:], "")) > 0 {
+			return nil, fmt.Errorf("record %d has %d values for %d columns", number, len(record), width)
+		}
+		return record[:width], nil
+	}
+	for len(record) < width {
+		record = append(record, "")
+	}
+	return record, nil
+}
+
+// sheetSource reads a worksheet.
+type sheetSource struct {
+	path   string
+	sheets SheetSelector
+	opts   ReadOptions
+	// ragged hands records on without fitting them to the header, for their reader to fit them itself.
+	ragged bool
+}
+
+// SheetSource returns the worksheet of the .xlsx file at path selected by sheets, which must select a single one,
+// as a RowSource, read as opts says. Legacy .xls workbooks, OpenDocument spreadsheets (.ods) and the other sources
+// of ReadOptions are read as well.
+func SheetSource(path string, sheets SheetSelector, opts ReadOptions) RowSource {
+	return sheetSource{path: path, sheets: sheets, opts: opts}
+}
+
+func (s sheetSource) Rows(ctx context.Context, onHeader func(header []string) error, onRecord func(record []string) error) (readErr error) {
+	if optsErr := s.opts.validate(); optsErr != nil {
+		return optsErr
+	}
+	file, openErr := openWorkbook(ctx, s.path, s.opts)
+	if openErr != nil {
+		return openErr
+	}
+	defer func(file *excelize.File) {
+		if err := file.Close(); err != nil && readErr == nil {
+			readErr = err
+		}
+	}(file)
+
+	sheets, selectErr := s.opts.selectSheets(file, s.sheets)
+	if selectErr != nil {
+		return selectErr
+	}
+	if len(sheets) != 1 {
+		return fmt.Errorf("a single worksheet is read at a time, %d are selected", len(sheets))
+	}
+	reader, readerErr := newSheetReader(file, sheets[0], s.opts)
+	if readerErr != nil {
+		return readerErr
+	}
+	reader.raggedRows = s.ragged
+	width := -1
+	number := 0
+	rowErr := reader.eachRecord(ctx, func(columns []string, _ []int) error {
+		header := tableColumnNames(columns)
+		width = len(header)
+		return onHeader(header)
+	}, func(_ int, columns []string) error {
+		number++
+		if !s.ragged {
+			var err error
+			if columns, err = fitRecord(number, columns, width); err != nil {
+				return err
+			}
+		}
+		return onRecord(columns)
+	})
+	if rowErr != nil {
+		return fmt.Errorf("worksheet '%s': %w", sheets[0], rowErr)
+	}
+	if width < 0 {
+		return fmt.Errorf("worksheet '%s' has no header row", sheets[0])
+	}
+	return nil
+}
+
+// csvSource reads a CSV file.
+type csvSource struct {
+	path string
+	opts CsvToXlsxOptions
+}
+
+// CsvSource returns the CSV file at path as a RowSource, read with the delimiter and the encoding of opts. Its first
+// record is the header unless opts.NoHeader is set, the columns then being called Column<n> after the number of
+// values of the first record. The other fields of opts are ignored.
+func CsvSource(path string, opts CsvToXlsxOptions) RowSource {
+	return csvSource{path: path, opts: opts}
+}
+
+func (s csvSource) Rows(ctx context.Context, onHeader func(header []string) error, onRecord func(record []string) error) error {
+	if optsErr := s.opts.validate([]string{s.path}); optsErr != nil {
+		return optsErr
+	}
+	width := -1
+	readErr := s.opts.eachCsvRecord(ctx, s.path, func(rowNumber int, record []string) error {
+		if width < 0 {
+			width = len(record)
+			if !s.opts.NoHeader {
+				return onHeader(tableColumnNames(record))
+			}
+			if err := onHeader(tableColumnNames(make([]string, width))); err != nil {
+				return err
+			}
+		}
+		fitted, err := fitRecord(rowNumber, record, width)
+		if err != nil {
+			return err
+		}
+		return onRecord(fitted)
+	})
+	if readErr != nil {
+		return fmt.Errorf("%s: %w", s.path, readErr)
+	}
+	if width < 0 {
+		return fmt.Errorf("%s has no header row", s.path)
+	}
+	return nil
+}
+
+// jsonSource reads a JSON file.
+type jsonSource struct {
+	path string
+	opts JsonToXlsxOptions
+}
+
+// JsonSource returns the records of the JSON file at path as a RowSource, flattened as JsonToXlsx flattens them
+// with opts, the columns appearing in the order their fields are first met. The file is read twice, once to
+// collect the columns and once to read the records. Numbers and booleans are read as their JSON text and nulls as
+// blank values.
+func JsonSource(path string, opts JsonToXlsxOptions) RowSource {
+	return jsonSource{path: path, opts: opts}
+}
+
+func (s jsonSource) Rows(ctx context.Context, onHeader func(header []string) error, onRecord func(record []string) error) error {
+	layout := newSheetLayout("")
+	columnErr := s.opts.eachJsonRow(ctx, s.path, func(row flatRow) error {
+		layout.add(row, false)
+		return nil
+	})
+	if columnErr != nil {
+		return fmt.Errorf("%s: %w", s.path, columnErr)
+	}
+	if err := onHeader(tableColumnNames(layout.columns)); err != nil {
+		return err
+	}
+	rowErr := s.opts.eachJsonRow(ctx, s.path, func(row flatRow) error {
+		record := make([]string, len(layout.columns))
+		for _, cell := range row {
+			if cell.value != nil {
+				record[layout.indexes[cell.column]] = jsonText(cell.value)
+			}
+		}
+		return onRecord(record)
+	})
+	if rowErr != nil {
+		return fmt.Errorf("%s: %w", s.path, rowErr)
+	}
+	return nil
+}
+
+// fixedWidthSource reads a fixed-width text file.
+type fixedWidthSource struct {
+	path     string
+	layout   fixedwidth.Layout
+	encoding string
+}
+
+// FixedWidthSource returns the fixed-width text file at path as a RowSource, cut into the fields of layout, which
+// name the columns. encoding names its encoding as in CsvOptions, UTF-8 when it is empty. Unlike the FixedWidth
+// read option, the file is read line by line rather than loaded into a worksheet.
+func FixedWidthSource(path string, layout fixedwidth.Layout, encoding string) RowSource {
+	return fixedWidthSource{path: path, layout: layout, encoding: encoding}
+}
+
+func (s fixedWidthSource) Rows(ctx context.Context, onHeader func(header []string) error, onRecord func(record []string) error) (readErr error) {
+	if err := s.layout.Validate(); err != nil {
+		return err
+	}
+	source, encodingErr := csvEncoding(s.encoding)
+	if encodingErr != nil {
+		return encodingErr
+	}
+	textFile, openErr := os.Open(s.path)
+	if openErr != nil {
+		return openErr
+	}
+	defer func(textFile *os.File) {
+		if err := textFile.Close(); err != nil && readErr == nil {
+			readErr = err
+		}
+	}(textFile)
+
+	var input io.Reader = bufio.NewReader(textFile)
+	if source != nil {
+		input = transform.NewReader(input, source.NewDecoder())
+	}
+	if err := onHeader(tableColumnNames(s.layout.Names())); err != nil {
+		return err
+	}
+	reader := fixedwidth.NewReader(input, s.layout)
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		record, recordErr := reader.Read()
+		if errors.Is(recordErr, io.EOF) {
+			return nil
+		}
+		if recordErr != nil {
+			return fmt.Errorf("%s: %w", s.path, recordErr)
+		}
+		if err := onRecord(record); err != nil {
+			return err
+		}
+	}
+}
+
+// querySource reads the result of a database query.
+type querySource struct {
+	db    *sql.DB
+	query string
+	args  []any
+}
+
+// QuerySource returns the result of query, run on db with args, as a RowSource whose columns are those of the
+// result. Every value is read as the text the driver converts it to, dates and times in RFC 3339, and NULL as a
+// blank value. The query is run again every time the source is read.
+func QuerySource(db *sql.DB, query string, args ...any) RowSource {
+	return querySource{db: db, query: query, args: args}
+}
+
+func (s querySource) Rows(ctx context.Context, onHeader func(header []string) error, onRecord func(record []string) error) (readErr error) {
+	rows, queryErr := s.db.QueryContext(ctx, s.query, s.args...)
+	if queryErr != nil {
+		return queryErr
+	}
+	defer func(rows *sql.Rows) {
+		if err := rows.Close(); err != nil && readErr == nil {
+			readErr = err
+		}
+	}(rows)
+
+	columns, columnsErr := rows.Columns()
+	if columnsErr != nil {
+		return columnsErr
+	}
+	if err := onHeader(tableColumnNames(columns)); err != nil {
+		return err
+	}
+	values := make([]sql.NullString, len(columns))
+	targets := make([]any, len(columns))
+	for index := range values {
+		targets[index] = &values[index]
+	}
+	for rows.Next() {
+		if err := rows.Scan(targets...); err != nil {
+			return err
+		}
+		record := make([]string, len(values))
+		for index, value := range values {
+			record[index] = value.String
+		}
+		if err := onRecord(record); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// tableSource reads a datatable.DataTable.
+type tableSource struct {
+	table *datatable.DataTable
+}
+
+// TableSource returns table as a RowSource, such as a table built in memory or read with ReadTable.
+func TableSource(table *datatable.DataTable) RowSource {
+	return tableSource{table: table}
+}
+
+func (s tableSource) Rows(ctx context.Context, onHeader func(header []string) error, onRecord func(record []string) error) error {
+	if err := onHeader(s.table.ColumnNames()); err != nil {
+		return err
+	}
+	return s.table.Each(func(row datatable.Row) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		return onRecord(row.Values())
+	})
+}
+
+// transformSource runs the records of a source through a pipeline.
+type transformSource struct {
+	source   RowSource
+	pipeline *transforms.Pipeline
+}
+
+// TransformSource returns a RowSource running the records of source through pipeline as they are read, its
+// header being the columns the pipeline leaves, see transforms.Pipeline.
+func TransformSource(source RowSource, pipeline *transforms.Pipeline) RowSource {
+	return transformSource{source: source, pipeline: pipeline}
+}
+
+func (s transformSource) Rows(ctx context.Context, onHeader func(header []string) error, onRecord func(record []string) error) error {
+	var run *transforms.Run
+	number := 0
+	return s.source.Rows(ctx, func(header []string) error {
+		var err error
+		if run, err = s.pipeline.Start(header); err != nil {
+			return err
+		}
+		return onHeader(run.Header())
+	}, func(record []string) error {
+		number++
+		values, err := run.Record(record)
+		if err != nil {
+			return fmt.Errorf("record %d: %w", number, err)
+		}
+		return onRecord(values)
+	})
+}
+
+// filterSource drops the records of a source a filter rejects.
+type filterSource struct {
+	source RowSource
+	filter RowFilter
+}
+
+// FilterSource returns a RowSource holding the records of source filter returns true for, see RowFilter.
+func FilterSource(source RowSource, filter RowFilter) RowSource {
+	return filterSource{source: source, filter: filter}
+}
+
+func (s filterSource) Rows(ctx context.Context, onHeader func(header []string) error, onRecord func(record []string) error) error {
+	var header []string
+	return s.source.Rows(ctx, func(columns []string) error {
+		header = columns
+		return onHeader(columns)
+	}, func(record []string) error {
+		if !s.filter(recordMap(header, record)) {
+			return nil
+		}
+		return onRecord(record)
+	})
+}
+
+// ReadSource reads the records of source into a datatable.DataTable, whose columns are all datatable.StringType.
+// The records are held in memory.
+func ReadSource(ctx context.Context, source RowSource) (*datatable.DataTable, error) {
+	var table *datatable.DataTable
+	readErr := source.Rows(ctx, func(header []string) error {
+		var err error
+		table, err = datatable.New(header...)
+		return err
+	}, func(record []string) error {
+		return table.AddRow(record...)
+	})
+	if readErr != nil {
+		return nil, readErr
+	}
+	return table, nil
+}
@@ -0,0 +1,99 @@
+package converters
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"GoTools/pkg/fixedwidth"
+	"GoTools/pkg/transforms"
+)
+
+// writeTestFile writes content into the file called name in a temporary directory and returns its path.
+func writeTestFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("Error writing test file: %v", err)
+	}
+	return path
+}
+
+func TestRowSources(t *testing.T) {
+	workbook := createTestWorkbook(t, map[string][][]any{
+		"Items": {{"Item", "Qty", "Column3"}, {"Bolt", 3}, {"Nut", 10}},
+	}, "Items")
+	layout := fixedwidth.Layout{{Name: "Item", Start: 1, Width: 6}, {Name: "Qty", Start: 7, Width: 3}, {Name: "Column3", Start: 10, Width: 1}}
+	tests := []struct {
+		name    string
+		source  RowSource
+		want    [][]string
+		wantErr string
+	}{
+		{name: "Sheet", source: SheetSource(workbook, SheetSelector{}, ReadOptions{})},
+		{name: "Csv", source: CsvSource(writeTestFile(t, "items.csv", "Item;Qty;\nBolt;3\nNut;10;\n"), CsvToXlsxOptions{Delimiter: ';'})},
+		{
+			name:   "Csv Without Header",
+			source: CsvSource(writeTestFile(t, "items.csv", "Bolt,3,\nNut,10,\n"), CsvToXlsxOptions{NoHeader: true}),
+			want:   [][]string{{"Column1", "Column2", "Column3"}, {"Bolt", "3", ""}, {"Nut", "10", ""}},
+		},
+		{
+			name:    "Csv Beyond Header",
+			source:  CsvSource(writeTestFile(t, "items.csv", "Item,Qty,Column3\nBolt,3,,x\n"), CsvToXlsxOptions{}),
+			wantErr: "record 2 has 4 values for 3 columns",
+		},
+		{
+			name:   "Json",
+			source: JsonSource(writeTestFile(t, "items.json", `[{"Item": "Bolt", "Qty": 3, "Column3": null}, {"Item": "Nut", "Qty": 10}]`), JsonToXlsxOptions{}),
+		},
+		{name: "Fixed Width", source: FixedWidthSource(writeTestFile(t, "items.txt", "Bolt    3\nNut    10\n"), layout, "")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table, err := ReadSource(context.Background(), tt.source)
+			if len(tt.wantErr) > 0 {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ReadSource() error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ReadSource() error = %v", err)
+			}
+			want := tt.want
+			if want == nil {
+				want = [][]string{{"Item", "Qty", "Column3"}, {"Bolt", "3", ""}, {"Nut", "10", ""}}
+			}
+			if got := append([][]string{table.ColumnNames()}, table.Records()...); !reflect.DeepEqual(got, want) {
+				t.Errorf("ReadSource() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestChainedSources(t *testing.T) {
+	source := CsvSource(writeTestFile(t, "items.csv", "Item,Qty\n bolt ,3\nnut,10\nwasher,0\n"), CsvToXlsxOptions{})
+	pipeline := transforms.New().Column("Item", transforms.Trim, transforms.Title)
+	filter, err := ParseRowFilter("Qty > 0")
+	if err != nil {
+		t.Fatalf("ParseRowFilter() error = %v", err)
+	}
+	table, err := ReadSource(context.Background(), FilterSource(TransformSource(source, pipeline), filter))
+	if err != nil {
+		t.Fatalf("ReadSource() error = %v", err)
+	}
+	if want := [][]string{{"Bolt", "3"}, {"Nut", "10"}}; !reflect.DeepEqual(table.Records(), want) {
+		t.Errorf("ReadSource() = %v, want %v", table.Records(), want)
+	}
+
+	again, err := ReadSource(context.Background(), TableSource(table))
+	if err != nil {
+		t.Fatalf("ReadSource(TableSource) error = %v", err)
+	}
+	if !reflect.DeepEqual(again.Records(), table.Records()) || !reflect.DeepEqual(again.ColumnNames(), []string{"Item", "Qty"}) {
+		t.Errorf("ReadSource(TableSource) = %v %v", again.ColumnNames(), again.Records())
+	}
+}
@@ -0,0 +1,320 @@
+package converters
+
+import (
+	"encoding/xml"
+	"io"
+	"regexp"
+	"time"
+)
+
+type xsdSchema struct {
+	XMLName            xml.Name     `xml:"xs:schema"`
+	XmlnsXs            string       `xml:"xmlns:xs,attr"`
+	Xmlns              string       `xml:"xmlns,attr,omitempty"`
+	TargetNamespace    string       `xml:"targetNamespace,attr,omitempty"`
+	ElementFormDefault string       `xml:"elementFormDefault,attr"`
+	Elements           []xsdElement `xml:"xs:element"`
+}
+
+type xsdElement struct {
+	Name        string          `xml:"name,attr"`
+	Type        string          `xml:"type,attr,omitempty"`
+	Nillable    string          `xml:"nillable,attr,omitempty"`
+	MinOccurs   string          `xml:"minOccurs,attr,omitempty"`
+	MaxOccurs   string          `xml:"maxOccurs,attr,omitempty"`
+	SimpleType  *xsdSimpleType  `xml:"xs:simpleType,omitempty"`
+	ComplexType *xsdComplexType `xml:"xs:complexType,omitempty"`
+}
+
+type xsdComplexType struct {
+	Sequence   *xsdGroup      `xml:"xs:sequence,omitempty"`
+	Choice     *xsdGroup      `xml:"xs:choice,omitempty"`
+	Attributes []xsdAttribute `xml:"xs:attribute"`
+}
+
+type xsdGroup struct {
+	MinOccurs string       `xml:"minOccurs,attr,omitempty"`
+	MaxOccurs string       `xml:"maxOccurs,attr,omitempty"`
+	Elements  []xsdElement `xml:"xs:element"`
+}
+
+type xsdAttribute struct {
+	Name       string         `xml:"name,attr"`
+	Type       string         `xml:"type,attr,omitempty"`
+	Use        string         `xml:"use,attr,omitempty"`
+	SimpleType *xsdSimpleType `xml:"xs:simpleType,omitempty"`
+}
+
+// xsdSimpleType declares a type accepting either a value of MemberTypes or an empty string.
+type xsdSimpleType struct {
+	Union struct {
+		MemberTypes string `xml:"memberTypes,attr"`
+		Empty       struct {
+			Restriction struct {
+				Base   string `xml:"base,attr"`
+				Length struct {
+					Value string `xml:"value,attr"`
+				} `xml:"xs:length"`
+			} `xml:"xs:restriction"`
+		} `xml:"xs:simpleType"`
+	} `xml:"xs:union"`
+}
+
+// schemaTypes lists the types a column can be declared as, from the most to the least specific.
+var schemaTypes = []CellType{IntegerCell, DecimalCell, DoubleCell, DateCell, DateTimeCell, BooleanCell}
+
+var (
+	xsdDatePattern     = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}(Z|[+-]\d{2}:\d{2})?$`)
+	xsdDateTimePattern = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2})?$`)
+)
+
+// lexicallyValid reports whether value, exactly as written, is a valid lexical form of the cell type.
+func lexicallyValid(cellType CellType, value string) bool {
+	switch cellType {
+	case IntegerCell, DecimalCell, DoubleCell:
+		return cellType.Valid(value)
+	case BooleanCell:
+		return value == "true" || value == "false"
+	case DateCell:
+		if !xsdDatePattern.MatchString(value) {
+			return false
+		}
+		_, err := time.Parse(time.DateOnly, value[:len(time.DateOnly)])
+		return err == nil
+	case DateTimeCell:
+		if !xsdDateTimePattern.MatchString(value) {
+			return false
+		}
+		_, err := time.Parse("2006-01-02T15:04:05", value[:len("2006-01-02T15:04:05")])
+		return err == nil
+	}
+	return true
+}
+
+// columnSchema accumulates what has been seen of the values of a single column.
+// Untyped cells narrow the candidate types to those their value is a valid lexical form of,
+// annotated cells record their xsi:type.
+type columnSchema struct {
+	name       string
+	candidates map[CellType]bool
+	annotated  map[string]bool
+	empty      bool
+	filled     bool
+}
+
+// observe records a single value of the column, with its xsi:type annotation if it has one.
+func (c *columnSchema) observe(value, annotation string) {
+	if len(value) < 1 {
+		c.empty = true
+		return
+	}
+	c.filled = true
+	if len(annotation) > 0 {
+		c.annotated[annotation] = true
+		return
+	}
+	for cellType := range c.candidates {
+		if !lexicallyValid(cellType, value) {
+			delete(c.candidates, cellType)
+		}
+	}
+}
+
+// xsdType returns the type the column is declared as.
+// Columns holding cells annotated with unrelated types are declared as xs:anySimpleType,
+// since no single declared type would accept every annotation. Columns without any value are declared as strings.
+func (c *columnSchema) xsdType() string {
+	if !c.filled {
+		return xsdType(StringCell)
+	}
+	if len(c.annotated) > 0 {
+		switch {
+		case len(c.annotated) == 1:
+			for annotation := range c.annotated {
+				return annotation
+			}
+		case len(c.annotated) == 2 && c.annotated[xsdType(IntegerCell)] && c.annotated[xsdType(DecimalCell)]:
+			return xsdType(DecimalCell)
+		}
+		return "xs:anySimpleType"
+	}
+	for _, cellType := range schemaTypes {
+		if c.candidates[cellType] {
+			return xsdType(cellType)
+		}
+	}
+	return xsdType(StringCell)
+}
+
+// emptyUnion returns a simple type accepting the declared type or an empty value, for columns with empty cells
+// whose declared type would reject empty content. It returns nil when the declared type accepts empty content.
+func (c *columnSchema) emptyUnion() *xsdSimpleType {
+	declared := c.xsdType()
+	if !c.empty || declared == xsdType(StringCell) || declared == "xs:anySimpleType" {
+		return nil
+	}
+	union := &xsdSimpleType{}
+	union.Union.MemberTypes = declared
+	union.Union.Empty.Restriction.Base = xsdType(StringCell)
+	union.Union.Empty.Restriction.Length.Value = "0"
+	return union
+}
+
+// tableSchema accumulates the columns of the rows of a single worksheet, in the order they first appear.
+type tableSchema struct {
+	columns []*columnSchema
+	index   map[string]*columnSchema
+}
+
+// column returns the schema of the named column, adding it if it has not been seen yet.
+func (t *tableSchema) column(name string) *columnSchema {
+	if column, exists := t.index[name]; exists {
+		return column
+	}
+	column := &columnSchema{name: name, candidates: make(map[CellType]bool), annotated: make(map[string]bool)}
+	for _, cellType := range schemaTypes {
+		column.candidates[cellType] = true
+	}
+	t.columns = append(t.columns, column)
+	t.index[name] = column
+	return column
+}
+
+// schemaBuilder infers an XML Schema describing the rows written by a conversion.
+type schemaBuilder struct {
+	opts   XmlOptions
+	tables []*tableSchema
+}
+
+// newSchemaBuilder returns a builder for documents shaped by opts.
+func newSchemaBuilder(opts XmlOptions) *schemaBuilder {
+	return &schemaBuilder{opts: opts}
+}
+
+// startTable begins accumulating the rows of the next worksheet.
+func (b *schemaBuilder) startTable(string) {
+	b.tables = append(b.tables, &tableSchema{index: make(map[string]*columnSchema)})
+}
+
+// observe records a row of the current worksheet after it has been shaped for output.
+func (b *schemaBuilder) observe(_ int, dataRow DataRow) {
+	table := b.tables[len(b.tables)-1]
+	if b.opts.Attributes {
+		for _, attr := range dataRow.Attributes {
+			table.column(attr.Name.Local).observe(attr.Value, "")
+		}
+		return
+	}
+	for _, column := range dataRow.Columns {
+		var annotation string
+		for _, attr := range column.Attributes {
+			if attr.Name.Local == "xsi:type" {
+				annotation = attr.Value
+			}
+		}
+		table.column(column.XMLName.Local).observe(column.text(), annotation)
+	}
+}
+
+// write marshals the inferred schema to w.
+// A combined schema merges the columns of every worksheet, since every Row element shares a single declaration.
+func (b *schemaBuilder) write(w io.Writer, combine bool) error {
+	schema := xsdSchema{
+		XmlnsXs:            xsNamespace,
+		Xmlns:              b.opts.Namespace,
+		TargetNamespace:    b.opts.Namespace,
+		ElementFormDefault: "qualified",
+	}
+	if !combine {
+		schema.Elements = []xsdElement{b.tableElement(b.tables[0], false)}
+	} else {
+		merged := &tableSchema{index: make(map[string]*columnSchema)}
+		for _, table := range b.tables {
+			for _, column := range table.columns {
+				mergeColumn(merged.column(column.name), column)
+			}
+		}
+		sheet := xsdElement{
+			Name:      "Sheet",
+			MinOccurs: "0",
+			MaxOccurs: "unbounded",
+			ComplexType: &xsdComplexType{
+				Sequence:   &xsdGroup{Elements: []xsdElement{b.tableElement(merged, true)}},
+				Attributes: []xsdAttribute{{Name: "name", Type: xsdType(StringCell), Use: "required"}},
+			},
+		}
+		schema.Elements = []xsdElement{{
+			Name:        workbookElement.Name.Local,
+			ComplexType: &xsdComplexType{Sequence: &xsdGroup{Elements: []xsdElement{sheet}}},
+		}}
+	}
+	output, marshalErr := xml.MarshalIndent(schema, "", "  ")
+	if marshalErr != nil {
+		return marshalErr
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	_, writeErr := w.Write(output)
+	return writeErr
+}
+
+// mergeColumn folds what was seen of a column in one worksheet into the merged column.
+func mergeColumn(merged, column *columnSchema) {
+	merged.empty = merged.empty || column.empty
+	merged.filled = merged.filled || column.filled
+	for cellType := range merged.candidates {
+		if !column.candidates[cellType] {
+			delete(merged.candidates, cellType)
+		}
+	}
+	for annotation := range column.annotated {
+		merged.annotated[annotation] = true
+	}
+}
+
+// tableElement declares the table element of a worksheet and the Row elements it holds.
+// Loose declarations make every column optional and order independent, for rows of different worksheets
+// or rows whose empty cells are omitted.
+func (b *schemaBuilder) tableElement(table *tableSchema, loose bool) xsdElement {
+	loose = loose || b.opts.EmptyCells == OmitEmpty
+	row := &xsdComplexType{}
+	if b.opts.Attributes {
+		for _, column := range table.columns {
+			attribute := xsdAttribute{Name: column.name, Use: "required", SimpleType: column.emptyUnion()}
+			if loose {
+				attribute.Use = "optional"
+			}
+			if attribute.SimpleType == nil {
+				attribute.Type = column.xsdType()
+			}
+			row.Attributes = append(row.Attributes, attribute)
+		}
+	} else {
+		var elements []xsdElement
+		for _, column := range table.columns {
+			element := xsdElement{Name: column.name, SimpleType: column.emptyUnion()}
+			if element.SimpleType == nil {
+				element.Type = column.xsdType()
+			}
+			if column.empty {
+				element.Nillable = "true"
+			}
+			elements = append(elements, element)
+		}
+		if loose {
+			row.Choice = &xsdGroup{MinOccurs: "0", MaxOccurs: "unbounded", Elements: elements}
+		} else {
+			row.Sequence = &xsdGroup{Elements: elements}
+		}
+	}
+	return xsdElement{
+		Name: b.opts.rootName(),
+		ComplexType: &xsdComplexType{Sequence: &xsdGroup{Elements: []xsdElement{{
+			Name:        b.opts.rowName(),
+			MinOccurs:   "0",
+			MaxOccurs:   "unbounded",
+			ComplexType: row,
+		}}}},
+	}
+}
@@ -0,0 +1,106 @@
+package converters
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestXlsxToXmlSchema(t *testing.T) {
+	filePath := createTestWorkbook(t, map[string][][]any{
+		"Orders": {{"Name", "Age", "Price", "Note"}, {"Ann", 31, 9.5, "vip"}, {"Bob", "", 12, ""}},
+	}, "Orders")
+	ageUnion := `<xs:union memberTypes="xs:long">`
+	tests := []struct {
+		name string
+		xml  XmlOptions
+		want []string
+	}{
+		{
+			name: "Elements",
+			want: []string{
+				`<xs:element name="Row" minOccurs="0" maxOccurs="unbounded">`,
+				`<xs:element name="Name" type="xs:string"></xs:element>`,
+				`<xs:element name="Age" nillable="true">`,
+				ageUnion,
+				`<xs:element name="Price" type="xs:decimal"></xs:element>`,
+				`<xs:element name="Note" type="xs:string" nillable="true"></xs:element>`,
+			},
+		},
+		{
+			name: "Attributes",
+			xml:  XmlOptions{Attributes: true, RootName: "Orders", RowName: "Order"},
+			want: []string{
+				`<xs:element name="Orders">`,
+				`<xs:element name="Order" minOccurs="0" maxOccurs="unbounded">`,
+				`<xs:attribute name="Name" type="xs:string" use="required"></xs:attribute>`,
+				`<xs:attribute name="Age" use="required">`,
+				ageUnion,
+				`<xs:attribute name="Price" type="xs:decimal" use="required"></xs:attribute>`,
+				`<xs:attribute name="Note" type="xs:string" use="required"></xs:attribute>`,
+			},
+		},
+		{
+			name: "Annotated Types",
+			xml:  XmlOptions{InferTypes: true},
+			want: []string{
+				`<xs:element name="Age" nillable="true">`,
+				ageUnion,
+				`<xs:element name="Price" type="xs:decimal"></xs:element>`,
+			},
+		},
+		{
+			name: "Omitted Empty Cells",
+			xml:  XmlOptions{EmptyCells: OmitEmpty},
+			want: []string{
+				`<xs:choice minOccurs="0" maxOccurs="unbounded">`,
+				`<xs:element name="Note" type="xs:string"></xs:element>`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output, schema bytes.Buffer
+			opts := Options{Xml: tt.xml, GenerateXSD: true, SchemaOutput: &schema}
+			if err := XlsxToXml(context.Background(), &output, filePath, opts); err != nil {
+				t.Fatalf("XlsxToXml() error = %v", err)
+			}
+			for _, declaration := range tt.want {
+				if !strings.Contains(schema.String(), declaration) {
+					t.Errorf("schema lacks %s:\n%s", declaration, schema.String())
+				}
+			}
+
+			parsed, parseErr := ParseSchema(&schema)
+			if parseErr != nil {
+				t.Fatalf("ParseSchema() error = %v", parseErr)
+			}
+			validated := Options{Xml: tt.xml, Validate: parsed}
+			if err := XlsxToXml(context.Background(), &bytes.Buffer{}, filePath, validated); err != nil {
+				t.Errorf("the document does not conform to its own schema: %v", err)
+			}
+		})
+	}
+}
+
+func TestXlsxToXmlSchemaMismatch(t *testing.T) {
+	filePath := createTestWorkbook(t, map[string][][]any{
+		"Orders": {{"Name", "Age"}, {"Ann", 31}},
+	}, "Orders")
+	var schema bytes.Buffer
+	opts := Options{Xml: XmlOptions{Attributes: true}, GenerateXSD: true, SchemaOutput: &schema}
+	if err := XlsxToXml(context.Background(), &bytes.Buffer{}, filePath, opts); err != nil {
+		t.Fatalf("XlsxToXml() error = %v", err)
+	}
+	parsed, parseErr := ParseSchema(&schema)
+	if parseErr != nil {
+		t.Fatalf("ParseSchema() error = %v", parseErr)
+	}
+	err := XlsxToXml(context.Background(), &bytes.Buffer{}, filePath, Options{Validate: parsed})
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Errorf("validating elements against the schema of attributes error = %v, want a *ValidationError", err)
+	}
+}
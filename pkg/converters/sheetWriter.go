@@ -0,0 +1,222 @@
+package converters
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"GoTools/pkg/datatable"
+	. "GoTools/pkg/helpers"
+	"github.com/xuri/excelize/v2"
+)
+
+// maxAutoWidth caps the width AutoWidth gives a column, in characters.
+const maxAutoWidth = 80
+
+// maxSignificantDigits is the number of digits Excel keeps of a number; longer integers stay text.
+const maxSignificantDigits = 15
+
+// workbookStyles holds the styles of the cells written into a workbook.
+type workbookStyles struct {
+	header   int
+	date     int
+	dateTime int
+}
+
+// newWorkbookStyles registers the header, date and date and time styles with the workbook.
+func newWorkbookStyles(file *excelize.File) (workbookStyles, error) {
+	var styles workbookStyles
+	var err error
+	styles.header, err = file.NewStyle(&excelize.Style{
+		Font: &excelize.Font{Bold: true},
+		Fill: excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"D9D9D9"}},
+	})
+	if err != nil {
+		return styles, err
+	}
+	if styles.date, err = file.NewStyle(&excelize.Style{NumFmt: 14}); err != nil {
+		return styles, err
+	}
+	styles.dateTime, err = file.NewStyle(&excelize.Style{NumFmt: 22})
+	return styles, err
+}
+
+// flatCell is a value of a flattened record together with the column it belongs to.
+type flatCell struct {
+	column string
+	value  any
+}
+
+// flatRow is a row of a flattened record, with its cells in field order.
+type flatRow []flatCell
+
+// sheetLayout collects the columns of a worksheet built from flattened records, in the order they are first met,
+// together with the width of their longest value.
+type sheetLayout struct {
+	name    string
+	columns []string
+	indexes map[string]int
+	widths  []int
+}
+
+// newSheetLayout returns the empty layout of the named worksheet.
+func newSheetLayout(name string) *sheetLayout {
+	return &sheetLayout{name: name, indexes: make(map[string]int)}
+}
+
+// add adds the columns of row missing from the layout, measuring the values of row when measure is set.
+func (l *sheetLayout) add(row flatRow, measure bool) {
+	for _, cell := range row {
+		index, known := l.indexes[cell.column]
+		if !known {
+			index = len(l.columns)
+			l.indexes[cell.column] = index
+			l.columns = append(l.columns, cell.column)
+			l.widths = measureColumn(l.widths, index, cell.column)
+		}
+		if measure && cell.value != nil {
+			l.widths = measureColumn(l.widths, index, fmt.Sprint(cell.value))
+		}
+	}
+}
+
+// writeHeader sizes the columns when autoWidth is set and writes the header row, styled and frozen when
+// styleHeader is set.
+func (l *sheetLayout) writeHeader(stream *excelize.StreamWriter, styles workbookStyles, styleHeader, autoWidth bool) error {
+	if autoWidth {
+		if err := setColumnWidths(stream, l.widths); err != nil {
+			return err
+		}
+	}
+	header := make([]any, len(l.columns))
+	for index, column := range l.columns {
+		header[index] = column
+		if styleHeader {
+			header[index] = excelize.Cell{StyleID: styles.header, Value: column}
+		}
+	}
+	if styleHeader {
+		if err := freezeHeader(stream); err != nil {
+			return err
+		}
+	}
+	return stream.SetRow("A1", header)
+}
+
+// values returns the cells of row in column order, converted by convert.
+func (l *sheetLayout) values(row flatRow, convert func(value any) any) []any {
+	values := make([]any, len(l.columns))
+	for _, cell := range row {
+		values[l.indexes[cell.column]] = convert(cell.value)
+	}
+	return values
+}
+
+// measureColumn widens the width of the column at index to fit text, growing widths as needed, and returns widths.
+func measureColumn(widths []int, index int, text string) []int {
+	for len(widths) <= index {
+		widths = append(widths, 0)
+	}
+	widths[index] = max(widths[index], min(utf8.RuneCountInString(text), maxAutoWidth))
+	return widths
+}
+
+// setColumnWidths sets the width of every column to its measured width plus some padding.
+// It must be called before the first row is written.
+func setColumnWidths(stream *excelize.StreamWriter, widths []int) error {
+	for index, width := range widths {
+		if err := stream.SetColWidth(index+1, index+1, float64(width+2)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// freezeHeader freezes the first row of the worksheet above the others.
+func freezeHeader(stream *excelize.StreamWriter) error {
+	return stream.SetPanes(&excelize.Panes{Freeze: true, YSplit: 1, TopLeftCell: "A2", ActivePane: "bottomLeft"})
+}
+
+// coerceCell returns the number, boolean or date a text field holds, or the field itself, dates being styled
+// with the date formats of styles.
+// Integers with more digits than Excel keeps and values with leading zeros stay text.
+func coerceCell(field string, styles workbookStyles) any {
+	value := coerceValue(field)
+	if date, isDate := value.(time.Time); isDate {
+		if datatable.InferCellType(ConvertToISO8601(field)) == DateCell {
+			return excelize.Cell{StyleID: styles.date, Value: date}
+		}
+		return excelize.Cell{StyleID: styles.dateTime, Value: date}
+	}
+	return value
+}
+
+// coerceValue returns the int64, float64, bool or time.Time a text field holds, or the field itself.
+// Integers with more digits than Excel keeps and values with leading zeros stay text.
+func coerceValue(field string) any {
+	value := ConvertToISO8601(field)
+	cellType := datatable.InferCellType(value)
+	canonical, _ := cellType.Canonical(value)
+	switch cellType {
+	case IntegerCell:
+		if len(strings.TrimLeft(canonical, "-")) > maxSignificantDigits {
+			return field
+		}
+		integer, _ := strconv.ParseInt(canonical, 10, 64)
+		return integer
+	case DecimalCell, DoubleCell:
+		number, _ := strconv.ParseFloat(value, 64)
+		return number
+	case BooleanCell:
+		return canonical == "true"
+	case DateCell:
+		date, _ := time.Parse(time.DateOnly, canonical)
+		return date
+	case DateTimeCell:
+		dateTime, err := time.Parse("2006-01-02T15:04:05", canonical)
+		if err != nil {
+			dateTime, _ = time.Parse(time.RFC3339Nano, canonical)
+		}
+		return dateTime
+	}
+	return field
+}
+
+// sheetNameFor returns the name of the worksheet holding the file at path, the index-th file of the workbook,
+// derived from the file name and distinct from the names already used.
+func sheetNameFor(path string, index int, used map[string]bool) string {
+	return validSheetName(strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)), index, used)
+}
+
+// validSheetName returns name as a valid name of the index-th worksheet of a workbook: without the characters
+// Excel rejects, at most 31 characters long and distinct from the names already used, which are lower case.
+func validSheetName(name string, index int, used map[string]bool) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.Trim(name, "'")
+	if len(name) < 1 {
+		name = "Sheet" + strconv.Itoa(index+1)
+	}
+	base := truncateRunes(name, excelize.MaxSheetNameLength)
+	name = base
+	for suffix := 2; used[strings.ToLower(name)]; suffix++ {
+		tail := " (" + strconv.Itoa(suffix) + ")"
+		name = truncateRunes(base, excelize.MaxSheetNameLength-len(tail)) + tail
+	}
+	return name
+}
+
+// truncateRunes shortens value to at most limit characters.
+func truncateRunes(value string, limit int) string {
+	if utf8.RuneCountInString(value) <= limit {
+		return value
+	}
+	return string([]rune(value)[:limit])
+}
@@ -0,0 +1,119 @@
+package converters
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// SheetSelector describes which worksheets of a workbook should be converted.
+// The zero value selects the first worksheet, which matches the historical behaviour of the converter.
+// Use SheetByName, SheetByIndex, SheetsMatching or AllSheets to build a selector,
+// or ParseSheetSelector to build one from command line input.
+type SheetSelector struct {
+	name    string
+	index   int
+	pattern *regexp.Regexp
+	all     bool
+}
+
+// SheetByName selects the worksheet with the given name.
+func SheetByName(name string) SheetSelector {
+	return SheetSelector{name: name}
+}
+
+// SheetByIndex selects the worksheet at the given zero-based position in the workbook. A negative index selects
+// no worksheet, failing the conversion.
+func SheetByIndex(index int) SheetSelector {
+	return SheetSelector{index: index}
+}
+
+// SheetsMatching selects every worksheet whose name matches the given regular expression.
+func SheetsMatching(pattern *regexp.Regexp) SheetSelector {
+	return SheetSelector{pattern: pattern}
+}
+
+// AllSheets selects every worksheet in the workbook.
+func AllSheets() SheetSelector {
+	return SheetSelector{all: true}
+}
+
+// ParseSheetSelector builds a SheetSelector from a textual expression.
+// The supported expressions are:
+//
+//	all            every worksheet
+//	index:<n>      the worksheet at zero-based position n
+//	regex:<expr>   every worksheet whose name matches expr
+//	name:<name>    the worksheet called name
+//	<name>         the worksheet called name
+//
+// An empty expression selects the first worksheet.
+func ParseSheetSelector(expr string) (SheetSelector, error) {
+	switch {
+	case expr == "":
+		return SheetSelector{}, nil
+	case strings.EqualFold(expr, "all"):
+		return AllSheets(), nil
+	case strings.HasPrefix(expr, "index:"):
+		index, err := strconv.Atoi(strings.TrimPrefix(expr, "index:"))
+		if err != nil || index < 0 {
+			return SheetSelector{}, fmt.Errorf("invalid sheet index in '%s'", expr)
+		}
+		return SheetByIndex(index), nil
+	case strings.HasPrefix(expr, "regex:"):
+		pattern, err := regexp.Compile(strings.TrimPrefix(expr, "regex:"))
+		if err != nil {
+			return SheetSelector{}, fmt.Errorf("invalid sheet pattern in '%s': %w", expr, err)
+		}
+		return SheetsMatching(pattern), nil
+	default:
+		return SheetByName(strings.TrimPrefix(expr, "name:")), nil
+	}
+}
+
+// resolve returns the names of the worksheets in file that match the selector, in workbook order.
+// It returns an error if the selector does not match any worksheet.
+func (s SheetSelector) resolve(file *excelize.File) ([]string, error) {
+	if s.index < 0 {
+		return nil, fmt.Errorf("invalid sheet index %d", s.index)
+	}
+	sheetList := file.GetSheetList()
+	var selected []string
+	switch {
+	case s.all:
+		selected = sheetList
+	case s.pattern != nil:
+		for _, sheet := range sheetList {
+			if s.pattern.MatchString(sheet) {
+				selected = append(selected, sheet)
+			}
+		}
+	case len(s.name) > 0:
+		if index, _ := file.GetSheetIndex(s.name); index >= 0 {
+			selected = []string{s.name}
+		}
+	case s.index < len(sheetList):
+		selected = []string{sheetList[s.index]}
+	}
+	if len(selected) < 1 {
+		return nil, fmt.Errorf("no worksheet matches %s", s)
+	}
+	return selected, nil
+}
+
+// String returns the selector in the form accepted by ParseSheetSelector.
+func (s SheetSelector) String() string {
+	switch {
+	case s.all:
+		return "all"
+	case s.pattern != nil:
+		return "regex:" + s.pattern.String()
+	case len(s.name) > 0:
+		return "name:" + s.name
+	default:
+		return "index:" + strconv.Itoa(s.index)
+	}
+}
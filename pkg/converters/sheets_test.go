@@ -0,0 +1,88 @@
+package converters
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestSheetSelector(t *testing.T) {
+	path := createTestWorkbook(t, map[string][][]any{
+		"Summary":  {{"Total"}},
+		"Data2023": {{"Id"}},
+		"Data2024": {{"Id"}},
+	}, "Summary", "Data2023", "Data2024")
+	file, openErr := excelize.OpenFile(path)
+	if openErr != nil {
+		t.Fatalf("Error opening test workbook: %v", openErr)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	tests := []struct {
+		name     string
+		selector SheetSelector
+		want     []string
+		wantErr  string
+	}{
+		{name: "Default", selector: SheetSelector{}, want: []string{"Summary"}},
+		{name: "By name", selector: SheetByName("Data2023"), want: []string{"Data2023"}},
+		{name: "Unknown name", selector: SheetByName("Missing"), wantErr: "no worksheet matches name:Missing"},
+		{name: "By index", selector: SheetByIndex(2), want: []string{"Data2024"}},
+		{name: "Index out of range", selector: SheetByIndex(3), wantErr: "no worksheet matches index:3"},
+		{name: "Negative index", selector: SheetByIndex(-1), wantErr: "invalid sheet index -1"},
+		{name: "Regex", selector: SheetsMatching(regexp.MustCompile(`^Data\d+$`)), want: []string{"Data2023", "Data2024"}},
+		{name: "Regex matching nothing", selector: SheetsMatching(regexp.MustCompile(`^Report`)), wantErr: "no worksheet matches regex:^Report"},
+		{name: "All", selector: AllSheets(), want: []string{"Summary", "Data2023", "Data2024"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.selector.resolve(file)
+			if len(tt.wantErr) > 0 {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("resolve() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolve() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("resolve() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSheetSelector(t *testing.T) {
+	tests := []struct {
+		expr    string
+		want    string
+		wantErr bool
+	}{
+		{expr: "", want: "index:0"},
+		{expr: "all", want: "all"},
+		{expr: "ALL", want: "all"},
+		{expr: "index:2", want: "index:2"},
+		{expr: "index:-1", wantErr: true},
+		{expr: "index:two", wantErr: true},
+		{expr: "regex:^Data", want: "regex:^Data"},
+		{expr: "regex:[", wantErr: true},
+		{expr: "name:index:1", want: "name:index:1"},
+		{expr: "Summary", want: "name:Summary"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := ParseSheetSelector(tt.expr)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSheetSelector(%q) error = %v, wantErr %v", tt.expr, err, tt.wantErr)
+			}
+			if err == nil && got.String() != tt.want {
+				t.Errorf("ParseSheetSelector(%q) = %s, want %s", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,276 @@
+package converters
+
+import (
+	"bufio"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	. "GoTools/pkg/helpers"
+	"github.com/xuri/excelize/v2"
+)
+
+// SplitFormat selects the format of the files a worksheet is split into.
+type SplitFormat int
+
+const (
+	// XlsxSplit writes every part into a workbook of its own. It is the default.
+	XlsxSplit SplitFormat = iota
+	// CsvSplit writes every part into a CSV file with a header row.
+	CsvSplit
+	// XmlSplit writes every part into an XML document holding a DataTable, as XlsxToXml does.
+	XmlSplit
+)
+
+// ParseSplitFormat returns the SplitFormat called xlsx, csv or xml.
+func ParseSplitFormat(name string) (SplitFormat, error) {
+	switch name {
+	case "", "xlsx":
+		return XlsxSplit, nil
+	case "csv":
+		return CsvSplit, nil
+	case "xml":
+		return XmlSplit, nil
+	}
+	return XlsxSplit, fmt.Errorf("unknown split format '%s'", name)
+}
+
+// extension returns the file extension of the split format.
+func (f SplitFormat) extension() string {
+	switch f {
+	case CsvSplit:
+		return ".csv"
+	case XmlSplit:
+		return ".xml"
+	}
+	return ".xlsx"
+}
+
+// defaultBlankPart names the part holding the records whose key column is blank when SplitOptions.BlankName is empty.
+const defaultBlankPart = "blank"
+
+// SplitOptions configures splitting a worksheet into a file per value of one of its columns.
+// Sheets selects the worksheet to split, which must be a single one, and Column names the column whose values
+// key the parts, matched regardless of case and of surrounding and repeated spaces as in MergeOptions.
+// Format selects the format of the files. FileName names them, {value} standing for the value of the part,
+// "<workbook>_{value}" by default; Slug makes the names safe as file names, replacing the characters file names
+// cannot hold by underscores by default, see Slugify.
+// The records whose key column is blank go to the part called BlankName, "blank" by default.
+// DropColumn leaves the key column out of the parts.
+// Style styles the workbooks written by XlsxSplit; their worksheet is named after the split worksheet by default.
+// The embedded ReadOptions control how the rows of the worksheet are read.
+type SplitOptions struct {
+	ReadOptions
+	Sheets     SheetSelector
+	Column     string
+	Format     SplitFormat
+	FileName   string
+	BlankName  string
+	DropColumn bool
+	Style      StyleOptions
+	Slug       SlugOptions
+}
+
+// SplitPart describes a file written by SplitWorkbook: the value of its key column and the number of its records.
+type SplitPart struct {
+	Value string
+	Path  string
+	Rows  int
+}
+
+// splitGroup collects the records of a part.
+type splitGroup struct {
+	value   string
+	records [][]string
+}
+
+// SplitWorkbook splits the worksheet of the .xlsx file at path selected by opts.Sheets into a file in dir per
+// distinct value of opts.Column, such as one file per Region, each holding the header row and the records
+// with that value in worksheet order. Legacy .xls workbooks and OpenDocument spreadsheets (.ods) are read as well.
+// The parts are returned in the order their values are first met; the worksheet is held in memory while split.
+// The split stops with the error of ctx as soon as it is cancelled, leaving the files written so far.
+func SplitWorkbook(ctx context.Context, dir, path string, opts SplitOptions) (parts []SplitPart, splitErr error) {
+	if optsErr := opts.ReadOptions.validate(); optsErr != nil {
+		return nil, optsErr
+	}
+	if len(strings.TrimSpace(opts.Column)) < 1 {
+		return nil, errors.New("no column to split on")
+	}
+	if len(opts.FileName) > 0 && !strings.Contains(opts.FileName, "{value}") {
+		return nil, fmt.Errorf("the file name '%s' does not hold {value}", opts.FileName)
+	}
+	file, openErr := openWorkbook(ctx, path, opts.ReadOptions)
+	if openErr != nil {
+		return nil, openErr
+	}
+	defer func(file *excelize.File) {
+		if err := file.Close(); err != nil && splitErr == nil {
+			splitErr = err
+		}
+	}(file)
+
+	sheets, selectErr := opts.selectSheets(file, opts.Sheets)
+	if selectErr != nil {
+		return nil, selectErr
+	}
+	if len(sheets) > 1 {
+		return nil, fmt.Errorf("a single worksheet is split at a time, %d are selected", len(sheets))
+	}
+	header, groups, readErr := opts.readGroups(ctx, file, sheets[0])
+	if readErr != nil {
+		return nil, fmt.Errorf("worksheet '%s': %w", sheets[0], readErr)
+	}
+
+	name := opts.FileName
+	if len(name) < 1 {
+		name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)) + "_{value}"
+	}
+	used := make(map[string]bool, len(groups))
+	for _, group := range groups {
+		if err := ctx.Err(); err != nil {
+			return parts, err
+		}
+		partName := splitFileName(strings.ReplaceAll(name, "{value}", group.value), opts.Slug, used)
+		partPath := filepath.Join(dir, partName+opts.Format.extension())
+		if err := opts.writePart(partPath, sheets[0], header, group.records); err != nil {
+			return parts, err
+		}
+		parts = append(parts, SplitPart{Value: group.value, Path: partPath, Rows: len(group.records)})
+	}
+	return parts, nil
+}
+
+// readGroups reads the header of the named worksheet and its records grouped by the value of the key column,
+// leaving the key column out when DropColumn is set.
+func (o SplitOptions) readGroups(ctx context.Context, file *excelize.File, sheet string) ([]string, []*splitGroup, error) {
+	reader, readerErr := newSheetReader(file, sheet, o.ReadOptions)
+	if readerErr != nil {
+		return nil, nil, readerErr
+	}
+	blank := o.BlankName
+	if len(blank) < 1 {
+		blank = defaultBlankPart
+	}
+	var header []string
+	key := -1
+	onHeader := func(columns []string, _ []int) error {
+		header = RenameDuplicates(columns, false)
+		for index, column := range header {
+			if normalizeHeader(column) == normalizeHeader(o.Column) {
+				key = index
+				return nil
+			}
+		}
+		return fmt.Errorf("unknown column '%s'", o.Column)
+	}
+	var groups []*splitGroup
+	byValue := make(map[string]*splitGroup)
+	rowErr := reader.eachRecord(ctx, onHeader, func(_ int, columns []string) error {
+		value := strings.TrimSpace(cellAt(columns, key))
+		if len(value) < 1 {
+			value = blank
+		}
+		group, known := byValue[value]
+		if !known {
+			group = &splitGroup{value: value}
+			byValue[value] = group
+			groups = append(groups, group)
+		}
+		record := make([]string, 0, len(header))
+		for index := range header {
+			if index != key || !o.DropColumn {
+				record = append(record, cellAt(columns, index))
+			}
+		}
+		group.records = append(group.records, record)
+		return nil
+	})
+	if rowErr != nil {
+		return nil, nil, rowErr
+	}
+	if o.DropColumn && key >= 0 {
+		header = append(header[:key:key], header[key+1:]...)
+	}
+	return header, groups, nil
+}
+
+// writePart writes the records of a part under header into the file at path, in the configured format.
+func (o SplitOptions) writePart(path, sheet string, header []string, records [][]string) error {
+	switch o.Format {
+	case CsvSplit:
+		return writeSplitFile(path, func(w io.Writer) error {
+			writer := &csvWriter{w: bufio.NewWriter(w), delimiter: ',', quote: '"', newline: "\n"}
+			if err := writer.write(header); err != nil {
+				return err
+			}
+			for _, record := range records {
+				converted := make([]string, len(record))
+				for index, value := range record {
+					converted[index] = ConvertToISO8601(value)
+				}
+				if err := writer.write(converted); err != nil {
+					return err
+				}
+			}
+			return writer.w.Flush()
+		})
+	case XmlSplit:
+		names := cleanHeaders(header, XmlOptions{}.tagOptions())
+		table := DataTable{XMLName: xml.Name{Local: XmlOptions{}.rootName()}}
+		for _, record := range records {
+			row := DataRow{XMLName: xml.Name{Local: XmlOptions{}.rowName()}}
+			for index, name := range names {
+				row.Columns = append(row.Columns, DataColumn{XMLName: xml.Name{Local: name}, Value: ConvertToISO8601(cellAt(record, index))})
+			}
+			table.Rows = append(table.Rows, row)
+		}
+		return writeSplitFile(path, func(w io.Writer) error {
+			return writeXml(w, table, XmlOptions{})
+		})
+	}
+	var table DataTable
+	for _, record := range records {
+		var row DataRow
+		for index, name := range header {
+			row.Columns = append(row.Columns, DataColumn{XMLName: xml.Name{Local: name}, Value: cellAt(record, index)})
+		}
+		table.Rows = append(table.Rows, row)
+	}
+	style := o.Style
+	if len(style.SheetName) < 1 {
+		style.SheetName = sheet
+	}
+	return table.WriteXlsx(path, style)
+}
+
+// writeSplitFile creates the file at path and hands it to write, removing the file when write fails.
+func writeSplitFile(path string, write func(w io.Writer) error) error {
+	files := &outputFiles{}
+	if err := files.writeFile(path, write); err != nil {
+		_ = os.Remove(path)
+		return err
+	}
+	return nil
+}
+
+// splitFileName returns name made safe as a file name by Slugify with slug, distinct regardless of case from
+// the names already used, to which it is added.
+func splitFileName(name string, slug SlugOptions, used map[string]bool) string {
+	slug.Target = FileNameSlug
+	name = Slugify(name, slug)
+	if len(name) < 1 {
+		name = "_"
+	}
+	base := name
+	for suffix := 2; used[strings.ToLower(name)]; suffix++ {
+		name = base + " (" + strconv.Itoa(suffix) + ")"
+	}
+	used[strings.ToLower(name)] = true
+	return name
+}
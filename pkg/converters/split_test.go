@@ -0,0 +1,137 @@
+package converters
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	. "GoTools/pkg/helpers"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestSplitWorkbook(t *testing.T) {
+	filePath := createTestWorkbook(t, map[string][][]any{
+		"Sales": {{"Region", "Rep", "Amount"}, {"North", "Ann", 10}, {"South/East", "Bob", 20}, {"North", "Cy", 30}, {"", "Di", 40}},
+		"Notes": {{"Text"}, {"hello"}},
+	}, "Sales", "Notes")
+
+	tests := []struct {
+		name      string
+		opts      SplitOptions
+		wantParts []SplitPart
+		wantFiles map[string]string
+		wantErr   string
+	}{
+		{
+			name: "Csv",
+			opts: SplitOptions{Column: "region", Format: CsvSplit},
+			wantParts: []SplitPart{
+				{Value: "North", Path: "TestWorkbook_North.csv", Rows: 2},
+				{Value: "South/East", Path: "TestWorkbook_South_East.csv", Rows: 1},
+				{Value: "blank", Path: "TestWorkbook_blank.csv", Rows: 1},
+			},
+			wantFiles: map[string]string{
+				"TestWorkbook_North.csv":      "Region,Rep,Amount\nNorth,Ann,10\nNorth,Cy,30\n",
+				"TestWorkbook_South_East.csv": "Region,Rep,Amount\nSouth/East,Bob,20\n",
+				"TestWorkbook_blank.csv":      "Region,Rep,Amount\n,Di,40\n",
+			},
+		},
+		{
+			name: "Xml Dropped Column",
+			opts: SplitOptions{Column: "Region", Format: XmlSplit, FileName: "{value}", BlankName: "none", DropColumn: true},
+			wantParts: []SplitPart{
+				{Value: "North", Path: "North.xml", Rows: 2},
+				{Value: "South/East", Path: "South_East.xml", Rows: 1},
+				{Value: "none", Path: "none.xml", Rows: 1},
+			},
+			wantFiles: map[string]string{
+				"none.xml": "<DataTable>\n  <Row>\n    <Rep>Di</Rep>\n    <Amount>40</Amount>\n  </Row>\n</DataTable>",
+			},
+		},
+		{
+			name: "Slugs",
+			opts: SplitOptions{Column: "Region", Format: CsvSplit, FileName: "Sales {value}", Slug: SlugOptions{Replacement: "-", ReplaceSpaces: true, Lower: true}},
+			wantParts: []SplitPart{
+				{Value: "North", Path: "sales-north.csv", Rows: 2},
+				{Value: "South/East", Path: "sales-south-east.csv", Rows: 1},
+				{Value: "blank", Path: "sales-blank.csv", Rows: 1},
+			},
+		},
+		{
+			name:    "Unknown Column",
+			opts:    SplitOptions{Column: "Country"},
+			wantErr: "unknown column 'Country'",
+		},
+		{
+			name:    "Several Sheets",
+			opts:    SplitOptions{Column: "Region", Sheets: AllSheets()},
+			wantErr: "a single worksheet is split at a time",
+		},
+		{
+			name:    "File Name Without Value",
+			opts:    SplitOptions{Column: "Region", FileName: "part"},
+			wantErr: "does not hold {value}",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			parts, err := SplitWorkbook(context.Background(), dir, filePath, tt.opts)
+			if len(tt.wantErr) > 0 {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("SplitWorkbook() error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SplitWorkbook() error = %v", err)
+			}
+			for index := range parts {
+				parts[index].Path = filepath.Base(parts[index].Path)
+			}
+			if !reflect.DeepEqual(parts, tt.wantParts) {
+				t.Errorf("SplitWorkbook() parts = %+v, want %+v", parts, tt.wantParts)
+			}
+			for name, want := range tt.wantFiles {
+				got, readErr := os.ReadFile(filepath.Join(dir, name))
+				if readErr != nil {
+					t.Fatalf("Error reading %s: %v", name, readErr)
+				}
+				if !strings.Contains(string(got), want) {
+					t.Errorf("%s = %q, want it to hold %q", name, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSplitWorkbookXlsx(t *testing.T) {
+	filePath := createTestWorkbook(t, map[string][][]any{
+		"Sales": {{"Region", "Amount"}, {"north", 10}, {"North", 20}},
+	}, "Sales")
+	dir := t.TempDir()
+	parts, err := SplitWorkbook(context.Background(), dir, filePath, SplitOptions{Column: "Region", Style: StyleOptions{CoerceTypes: true}})
+	if err != nil {
+		t.Fatalf("SplitWorkbook() error = %v", err)
+	}
+	if len(parts) != 2 || filepath.Base(parts[1].Path) != "TestWorkbook_North (2).xlsx" {
+		t.Fatalf("SplitWorkbook() parts = %+v, want distinct file names for north and North", parts)
+	}
+	file, openErr := excelize.OpenFile(parts[1].Path)
+	if openErr != nil {
+		t.Fatalf("Error opening the part: %v", openErr)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	rows, _ := file.GetRows("Sales")
+	if want := [][]string{{"Region", "Amount"}, {"North", "20"}}; !reflect.DeepEqual(rows, want) {
+		t.Errorf("SplitWorkbook() rows = %v, want %v", rows, want)
+	}
+	if cellType, _ := file.GetCellType("Sales", "B2"); cellType != excelize.CellTypeUnset && cellType != excelize.CellTypeNumber {
+		t.Errorf("B2 type = %v, want a number", cellType)
+	}
+}
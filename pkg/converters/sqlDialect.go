@@ -0,0 +1,112 @@
+package converters
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SqlDialect selects the SQL dialect generated statements are written in.
+type SqlDialect int
+
+const (
+	// PostgresDialect writes PostgreSQL statements. It is the default.
+	PostgresDialect SqlDialect = iota
+	// MySqlDialect writes MySQL and MariaDB statements.
+	MySqlDialect
+	// SqlServerDialect writes Microsoft SQL Server statements.
+	SqlServerDialect
+)
+
+// ParseSqlDialect returns the SqlDialect called postgres, mysql or sqlserver.
+func ParseSqlDialect(name string) (SqlDialect, error) {
+	switch strings.ToLower(name) {
+	case "", "postgres", "postgresql":
+		return PostgresDialect, nil
+	case "mysql", "mariadb":
+		return MySqlDialect, nil
+	case "sqlserver", "mssql":
+		return SqlServerDialect, nil
+	}
+	return PostgresDialect, fmt.Errorf("unknown SQL dialect '%s'", name)
+}
+
+// maxSqlServerRows is the number of rows SQL Server accepts in a single VALUES clause.
+const maxSqlServerRows = 1000
+
+// quoteIdentifier quotes each dot separated part of a table name.
+func (d SqlDialect) quoteIdentifier(name string) string {
+	parts := strings.Split(name, ".")
+	for index, part := range parts {
+		parts[index] = d.quoteColumn(part)
+	}
+	return strings.Join(parts, ".")
+}
+
+// quoteColumn quotes a column name, which unlike a table name is never qualified.
+func (d SqlDialect) quoteColumn(name string) string {
+	switch d {
+	case MySqlDialect:
+		return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+	case SqlServerDialect:
+		return "[" + strings.ReplaceAll(name, "]", "]]") + "]"
+	}
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// stringLiteral returns value as a string literal. MySQL treats backslashes as escapes,
+// and SQL Server literals are prefixed with N to keep characters outside the code page of the database.
+func (d SqlDialect) stringLiteral(value string) string {
+	switch d {
+	case MySqlDialect:
+		replacer := strings.NewReplacer(`\`, `\\`, "'", "''", "\x00", `\0`, "\x1a", `\Z`)
+		return "'" + replacer.Replace(value) + "'"
+	case SqlServerDialect:
+		return "N'" + strings.ReplaceAll(value, "'", "''") + "'"
+	}
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+// literal returns the literal of the canonical form of a value of the cell type.
+func (d SqlDialect) literal(cellType CellType, canonical string) string {
+	switch cellType {
+	case IntegerCell, DecimalCell, DoubleCell:
+		return canonical
+	case BooleanCell:
+		if d == SqlServerDialect {
+			if canonical == "true" {
+				return "1"
+			}
+			return "0"
+		}
+		return strings.ToUpper(canonical)
+	case DateCell:
+		if d == SqlServerDialect {
+			return "'" + canonical + "'"
+		}
+		return "DATE '" + canonical + "'"
+	case DateTimeCell:
+		if d == SqlServerDialect {
+			return "'" + canonical + "'"
+		}
+		return "TIMESTAMP '" + strings.Replace(canonical, "T", " ", 1) + "'"
+	}
+	return d.stringLiteral(canonical)
+}
+
+// columnType returns the column type values of the cell type are stored in. Strings in key columns
+// get a bounded type, since neither MySQL nor SQL Server index unbounded text.
+func (d SqlDialect) columnType(cellType CellType, key bool) string {
+	switch d {
+	case MySqlDialect:
+		if key && cellType == StringCell {
+			return "VARCHAR(255)"
+		}
+		return [...]string{"TEXT", "BIGINT", "DECIMAL(38,10)", "DOUBLE", "BOOLEAN", "DATE", "DATETIME"}[cellType]
+	case SqlServerDialect:
+		if key && cellType == StringCell {
+			return "NVARCHAR(450)"
+		}
+		return [...]string{"NVARCHAR(MAX)", "BIGINT", "DECIMAL(38,10)", "FLOAT", "BIT", "DATE", "DATETIME2"}[cellType]
+	}
+	return [...]string{"TEXT", "BIGINT", "NUMERIC", "DOUBLE PRECISION", "BOOLEAN", "DATE", "TIMESTAMP"}[cellType]
+}
@@ -0,0 +1,91 @@
+package converters
+
+import (
+	"context"
+	"encoding/xml"
+	"io"
+
+	"github.com/xuri/excelize/v2"
+)
+
+var workbookElement = xml.StartElement{Name: xml.Name{Local: "Workbook"}}
+
+// streamXml writes the named worksheets to w one row at a time, producing the same document as the
+// in-memory path without ever holding more than a single row.
+// A Workbook document is written when opts.Combine is set, otherwise the DataTable of the first worksheet.
+// Every row written is also handed to the observers.
+func streamXml(ctx context.Context, w io.Writer, file *excelize.File, sheets []string, opts Options, watchers observers) error {
+	encoder, encoderErr := opts.Xml.newEncoder(w)
+	if encoderErr != nil {
+		return encoderErr
+	}
+
+	if !opts.Combine {
+		if err := streamSheet(ctx, encoder.Encoder, file, sheets[0], opts, watchers); err != nil {
+			return err
+		}
+		return encoder.finish()
+	}
+	workbookStart := opts.workbookStart()
+	if err := encoder.EncodeToken(workbookStart); err != nil {
+		return err
+	}
+	for _, sheet := range sheets {
+		sheetElement := sheetStart(sheet)
+		if err := encoder.EncodeToken(sheetElement); err != nil {
+			return err
+		}
+		if err := streamSheet(ctx, encoder.Encoder, file, sheet, opts, watchers); err != nil {
+			return err
+		}
+		if err := encoder.EncodeToken(sheetElement.End()); err != nil {
+			return err
+		}
+	}
+	if err := encoder.EncodeToken(workbookStart.End()); err != nil {
+		return err
+	}
+	return encoder.finish()
+}
+
+// streamSheet encodes the table element of the named worksheet, writing each row as it is read.
+func streamSheet(ctx context.Context, encoder *xml.Encoder, file *excelize.File, sheet string, opts Options, watchers observers) error {
+	reader, readerErr := opts.newSheetReader(file, sheet)
+	if readerErr != nil {
+		return readerErr
+	}
+
+	dataTableElement := opts.tableStart()
+	rowElement := opts.rowStart()
+	if err := encoder.EncodeToken(dataTableElement); err != nil {
+		return err
+	}
+	watchers.startTable(sheet)
+	rowErr := eachDataRow(ctx, reader, func(rowNumber int, dataRow DataRow) error {
+		opts.Xml.shapeRow(&dataRow)
+		watchers.observe(rowNumber, dataRow)
+		return encoder.EncodeElement(dataRow, rowElement)
+	})
+	if rowErr != nil {
+		return rowErr
+	}
+	return encoder.EncodeToken(dataTableElement.End())
+}
+
+// sheetStart returns the start element wrapping the table of the named worksheet in a combined document.
+func sheetStart(sheet string) xml.StartElement {
+	return xml.StartElement{
+		Name: xml.Name{Local: "Sheet"},
+		Attr: []xml.Attr{{Name: xml.Name{Local: "name"}, Value: sheet}},
+	}
+}
+
+// tableStart returns the start element of the table holding the rows of a worksheet.
+func (o Options) tableStart() xml.StartElement {
+	return xml.StartElement{Name: xml.Name{Local: o.Xml.rootName()}, Attr: o.tableAttrs()}
+}
+
+// rowStart returns the start element of each row.
+func (o Options) rowStart() xml.StartElement {
+	return xml.StartElement{Name: xml.Name{Local: o.Xml.rowName()}}
+}
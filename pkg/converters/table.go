@@ -0,0 +1,130 @@
+package converters
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"strconv"
+	"strings"
+
+	"GoTools/pkg/datatable"
+	. "GoTools/pkg/helpers"
+)
+
+// TableOptions configures reading a worksheet into a datatable.DataTable.
+// Sheets selects the worksheet to read, which must be a single one; the first worksheet by default.
+// InferTypes sets the type of every column from its values, see datatable.InferType; every column is
+// datatable.StringType otherwise. RowPolicy fits the rows without a value per column to the header, see
+// datatable.RowPolicy; the table counts the rows it adjusted in its RowStats.
+// The embedded ReadOptions control how the rows of the worksheet are read.
+type TableOptions struct {
+	ReadOptions
+	Sheets     SheetSelector
+	InferTypes bool
+	RowPolicy  datatable.RowPolicy
+}
+
+// ReadTable reads the worksheet of the .xlsx file at path selected by opts.Sheets into a datatable.DataTable,
+// the model the transforms of the datatable package work on. Legacy .xls workbooks, OpenDocument spreadsheets
+// (.ods) and the other sources of ReadOptions are read as well. Repeated headers are renamed as RenameDuplicates
+// does and blank ones are called Column<n>, n being their one-based position. The worksheet is held in memory.
+func ReadTable(ctx context.Context, path string, opts TableOptions) (*datatable.DataTable, error) {
+	return readTable(ctx, path, opts, func(table *datatable.DataTable, columns []string) error {
+		return table.AddRow(columns...)
+	})
+}
+
+// readTable reads the worksheet of the file at path selected by opts.Sheets, handing the table of its header and
+// every record to onRecord, which adds the records it keeps to the table and may stop the reading early by
+// returning errEnoughRows.
+func readTable(ctx context.Context, path string, opts TableOptions, onRecord func(table *datatable.DataTable, columns []string) error) (*datatable.DataTable, error) {
+	var table *datatable.DataTable
+	source := sheetSource{path: path, sheets: opts.Sheets, opts: opts.ReadOptions, ragged: true}
+	readErr := source.Rows(ctx, func(header []string) error {
+		var err error
+		table, err = datatable.New(header...)
+		if err == nil {
+			table.SetRowPolicy(opts.RowPolicy)
+		}
+		return err
+	}, func(record []string) error {
+		return onRecord(table, record)
+	})
+	if readErr != nil && !errors.Is(readErr, errEnoughRows) {
+		return nil, readErr
+	}
+	if opts.InferTypes {
+		table.InferTypes()
+	}
+	return table, nil
+}
+
+// tableColumnNames returns header with blank names replaced by Column<n> and repeated ones renamed.
+func tableColumnNames(header []string) []string {
+	names := make([]string, len(header))
+	for position, name := range header {
+		if names[position] = strings.TrimSpace(name); len(names[position]) < 1 {
+			names[position] = "Column" + strconv.Itoa(position+1)
+		}
+	}
+	return RenameDuplicates(names, false)
+}
+
+// FromTable returns table as a DataTable, with a row element per row and a column element per column,
+// so it can be written as XML or with WriteXlsx. Blank values are kept as empty elements.
+func FromTable(table *datatable.DataTable) DataTable {
+	result := DataTable{XMLName: xml.Name{Local: XmlOptions{}.rootName()}}
+	names := table.ColumnNames()
+	_ = table.Each(func(row datatable.Row) error {
+		dataRow := DataRow{XMLName: xml.Name{Local: XmlOptions{}.rowName()}}
+		for position, value := range row.Values() {
+			dataRow.Columns = append(dataRow.Columns, DataColumn{XMLName: xml.Name{Local: names[position]}, Value: value})
+		}
+		result.Rows = append(result.Rows, dataRow)
+		return nil
+	})
+	return result
+}
+
+// Table returns t as a datatable.DataTable whose columns are those of the rows of t in the order they are
+// first met, so the transforms of the datatable package, such as Pivot or Crosstab, can reshape it before
+// FromTable turns it back. Values written as CDATA sections or rich text are read as their text, and rows missing
+// a column hold a blank value for it.
+func (t DataTable) Table() (*datatable.DataTable, error) {
+	table, err := datatable.New(t.columnNames()...)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range t.Rows {
+		if err := table.AddRecord(row.values()); err != nil {
+			return nil, err
+		}
+	}
+	return table, nil
+}
+
+// columnNames returns the names of the columns of the table, in the order they are first met.
+func (t DataTable) columnNames() []string {
+	var names []string
+	known := make(map[string]bool)
+	for _, row := range t.Rows {
+		for _, column := range row.Columns {
+			if !known[column.XMLName.Local] {
+				known[column.XMLName.Local] = true
+				names = append(names, column.XMLName.Local)
+			}
+		}
+	}
+	return names
+}
+
+// values keys the text of the columns of the row by column name, keeping the first of repeated columns.
+func (r DataRow) values() map[string]string {
+	values := make(map[string]string, len(r.Columns))
+	for _, column := range r.Columns {
+		if _, exists := values[column.XMLName.Local]; !exists {
+			values[column.XMLName.Local] = column.text()
+		}
+	}
+	return values
+}
@@ -0,0 +1,132 @@
+package converters
+
+import (
+	"context"
+	"encoding/xml"
+	"reflect"
+	"strings"
+	"testing"
+
+	"GoTools/pkg/datatable"
+)
+
+func TestReadTable(t *testing.T) {
+	filePath := createTestWorkbook(t, map[string][][]any{
+		"Stock": {{"Item", "Qty", "", "Item"}, {"Bolt", 3, "a", "x"}, {"Nut", 12}},
+		"Notes": {{"Text"}, {"hello"}},
+	}, "Stock", "Notes")
+
+	table, err := ReadTable(context.Background(), filePath, TableOptions{InferTypes: true})
+	if err != nil {
+		t.Fatalf("ReadTable() error = %v", err)
+	}
+	if want := []string{"Item", "Qty", "Column3", "Item_2"}; !reflect.DeepEqual(table.ColumnNames(), want) {
+		t.Errorf("ReadTable() columns = %v, want %v", table.ColumnNames(), want)
+	}
+	if want := [][]string{{"Bolt", "3", "a", "x"}, {"Nut", "12", "", ""}}; !reflect.DeepEqual(table.Records(), want) {
+		t.Errorf("ReadTable() records = %v, want %v", table.Records(), want)
+	}
+	if column, _ := table.Column("Qty"); column.Type != datatable.IntegerType {
+		t.Errorf("ReadTable() Qty type = %v, want integer", column.Type)
+	}
+
+	notes, err := ReadTable(context.Background(), filePath, TableOptions{Sheets: SheetByName("Notes")})
+	if err != nil || notes.Len() != 1 || notes.Row(0).Get("Text") != "hello" {
+		t.Errorf("ReadTable(Notes) = %v, %v", notes, err)
+	}
+	if _, err := ReadTable(context.Background(), filePath, TableOptions{Sheets: AllSheets()}); err == nil || !strings.Contains(err.Error(), "2 are selected") {
+		t.Errorf("ReadTable(all) error = %v", err)
+	}
+}
+
+// TestReadTableTypesMatchProfile checks that ReadTable and ProfileWorkbook infer the same type for every column,
+// mixed numbers and mixed dates and times included.
+func TestReadTableTypesMatchProfile(t *testing.T) {
+	filePath := createTestWorkbook(t, map[string][][]any{
+		"Orders": {
+			{"Zip", "Qty", "Price", "Rate", "Paid", "Due", "Stamp", "Note"},
+			{"01234", 3, "2.5", "1.5e3", "true", "2024-03-01", "2024-03-01", "x"},
+			{"98765", "", "4", "2", "FALSE", "2024-04-01", "2024-04-01 08:00:00", "NaN"},
+		},
+	}, "Orders")
+
+	table, err := ReadTable(context.Background(), filePath, TableOptions{InferTypes: true})
+	if err != nil {
+		t.Fatalf("ReadTable() error = %v", err)
+	}
+	profile, err := ProfileWorkbook(context.Background(), filePath, ProfileOptions{})
+	if err != nil {
+		t.Fatalf("ProfileWorkbook() error = %v", err)
+	}
+	var tableTypes, profileTypes []datatable.ColumnType
+	for index, column := range table.Columns() {
+		tableTypes = append(tableTypes, column.Type)
+		cellType, err := ParseCellType(profile.Sheets[0].Columns[index].Type)
+		if err != nil {
+			t.Fatalf("ParseCellType() error = %v", err)
+		}
+		profileTypes = append(profileTypes, cellType.ColumnType())
+	}
+	want := []datatable.ColumnType{datatable.StringType, datatable.IntegerType, datatable.DecimalType, datatable.DecimalType,
+		datatable.BooleanType, datatable.DateType, datatable.DateTimeType, datatable.StringType}
+	if !reflect.DeepEqual(tableTypes, want) || !reflect.DeepEqual(profileTypes, want) {
+		t.Errorf("ReadTable() types = %v, ProfileWorkbook() types = %v, want %v", tableTypes, profileTypes, want)
+	}
+}
+
+func TestFromTable(t *testing.T) {
+	table, err := datatable.FromRecords([]string{"Region", "Amount"}, [][]string{{"North", "10"}, {"South"}})
+	if err != nil {
+		t.Fatalf("FromRecords() error = %v", err)
+	}
+	converted := FromTable(table)
+	if want := [][]string{{"Region=North", "Amount=10"}, {"Region=South", "Amount="}}; !reflect.DeepEqual(tableRows(converted), want) {
+		t.Errorf("FromTable() rows = %v, want %v", tableRows(converted), want)
+	}
+	back, err := dataTable([]string{"Region", "Amount"}, []string{"North", "10"}, []string{"South"}).Table()
+	if err != nil {
+		t.Fatalf("Table() error = %v", err)
+	}
+	if !reflect.DeepEqual(back.Records(), table.Records()) {
+		t.Errorf("Table() records = %v, want %v", back.Records(), table.Records())
+	}
+}
+
+// TestTableTransforms reshapes a DataTable through the transforms of the datatable package, reading the values
+// written as CDATA sections and rich text as their text.
+func TestTableTransforms(t *testing.T) {
+	long := dataTable([]string{"Region", "Variable", "Value"}, []string{"North", "Jan", "10"}, []string{"North", "Feb", ""})
+	long.Rows[0].Columns[0] = DataColumn{XMLName: xml.Name{Local: "Region"}, CData: "North"}
+	long.Rows[1].Columns[2] = DataColumn{XMLName: xml.Name{Local: "Value"}, Runs: []TextRun{{Text: "2"}, {Text: "0", Bold: true}}}
+	table, err := long.Table()
+	if err != nil {
+		t.Fatalf("Table() error = %v", err)
+	}
+	wide, err := table.Pivot(datatable.PivotOptions{})
+	if err != nil {
+		t.Fatalf("Pivot() error = %v", err)
+	}
+	if want := [][]string{{"Region=North", "Jan=10", "Feb=20"}}; !reflect.DeepEqual(tableRows(FromTable(wide)), want) {
+		t.Errorf("FromTable(Pivot()) rows = %v, want %v", tableRows(FromTable(wide)), want)
+	}
+}
+
+func TestReadTableRowPolicy(t *testing.T) {
+	filePath := createTestWorkbook(t, map[string][][]any{
+		"Stock": {{"Item", "Qty"}, {"Bolt", 3, "stray"}, {"Nut"}, {"Washer", 1}},
+	}, "Stock")
+
+	if _, err := ReadTable(context.Background(), filePath, TableOptions{}); err == nil || !strings.Contains(err.Error(), "row 1 has 3 values for 2 columns") {
+		t.Errorf("ReadTable(pad) error = %v", err)
+	}
+	table, err := ReadTable(context.Background(), filePath, TableOptions{RowPolicy: datatable.TruncateRows})
+	if err != nil {
+		t.Fatalf("ReadTable(truncate) error = %v", err)
+	}
+	if want := [][]string{{"Bolt", "3"}, {"Nut", ""}, {"Washer", "1"}}; !reflect.DeepEqual(table.Records(), want) {
+		t.Errorf("ReadTable(truncate) records = %v, want %v", table.Records(), want)
+	}
+	if want := (datatable.RowStats{Rows: 3, Padded: 1, Truncated: 1}); table.RowStats() != want {
+		t.Errorf("ReadTable(truncate) stats = %+v, want %+v", table.RowStats(), want)
+	}
+}
@@ -0,0 +1,308 @@
+package converters
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// TemplateData holds the values filled into a workbook template.
+// Values fills the {{name}} placeholders of every worksheet. Every table of Tables fills a template row holding
+// {{table.column}} placeholders, naming the table and one of its columns: the row is repeated once per DataRow of
+// the table, each copy filled with the values of its row, and emptied when the table holds no rows. Values may be
+// strings, numbers, booleans or time.Time values; the values of a DataTable are strings.
+type TemplateData struct {
+	Values map[string]any
+	Tables map[string]DataTable
+}
+
+// TemplateOptions configures the filling of a workbook template.
+// CoerceTypes writes the string filling a cell made of a single placeholder as the number, boolean or date it holds,
+// as CsvToXlsxOptions does, so number formats apply; strings are written as text otherwise. Placeholders within
+// other text are always replaced by the text of their value.
+// Strict fails on placeholders for which no value is given, which are emptied otherwise.
+type TemplateOptions struct {
+	CoerceTypes bool
+	Strict      bool
+}
+
+// placeholderPattern matches a {{name}} placeholder, capturing its name without surrounding spaces.
+var placeholderPattern = regexp.MustCompile(`\{\{\s*([^{}]*?)\s*\}\}`)
+
+// FillTemplate fills the placeholders of the .xlsx template at templatePath with data and saves the populated
+// workbook at outPath. Everything else in the template is kept: styles, merged cells, formulas, charts, images and
+// the layout of the page. The copies of a repeated row keep its styles and formulas, whose relative references
+// are moved to the copy, and the references of formulas and ranges below the row move down with it; a formula
+// such as a total grows with the table when its range spans the template row and the row below it.
+// Formula cells are not filled, and cached formula results are cleared so Excel recalculates them on open.
+// The filling stops with the error of ctx as soon as it is cancelled or its deadline passes.
+func FillTemplate(ctx context.Context, outPath, templatePath string, data TemplateData, opts TemplateOptions) (fillErr error) {
+	file, openErr := excelize.OpenFile(templatePath)
+	if openErr != nil {
+		return openErr
+	}
+	defer func(file *excelize.File) {
+		if err := file.Close(); err != nil && fillErr == nil {
+			fillErr = err
+		}
+	}(file)
+
+	filler := templateFiller{file: file, data: data, opts: opts}
+	for _, sheet := range file.GetSheetList() {
+		if err := filler.fillSheet(ctx, sheet); err != nil {
+			return fmt.Errorf("worksheet '%s': %w", sheet, err)
+		}
+	}
+	if err := file.UpdateLinkedValue(); err != nil {
+		return err
+	}
+	return file.SaveAs(outPath)
+}
+
+// templateFiller fills the worksheets of a template.
+type templateFiller struct {
+	file *excelize.File
+	data TemplateData
+	opts TemplateOptions
+}
+
+// fillSheet fills the placeholders of the named worksheet, working from the last row up so repeating a row
+// never moves the rows still to be filled.
+func (f templateFiller) fillSheet(ctx context.Context, sheet string) error {
+	rows, rowsErr := f.file.GetRows(sheet, excelize.Options{RawCellValue: true})
+	if rowsErr != nil {
+		return rowsErr
+	}
+	width := 0
+	for _, row := range rows {
+		width = max(width, len(row))
+	}
+	for rowIndex := len(rows) - 1; rowIndex >= 0; rowIndex-- {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		rowNumber := rowIndex + 1
+		table, tableErr := f.rowTable(rowNumber, rows[rowIndex])
+		if tableErr != nil {
+			return tableErr
+		}
+		if len(table) < 1 {
+			if err := f.fillRow(sheet, rowNumber, rows[rowIndex], "", DataRow{}); err != nil {
+				return err
+			}
+			continue
+		}
+		records := f.data.Tables[table].Rows
+		if len(records) < 1 {
+			if err := f.clearRow(sheet, rowNumber, width); err != nil {
+				return err
+			}
+			continue
+		}
+		for copyIndex := 1; copyIndex < len(records); copyIndex++ {
+			if err := f.file.DuplicateRowTo(sheet, rowNumber, rowNumber+copyIndex); err != nil {
+				return err
+			}
+		}
+		for recordIndex, record := range records {
+			if err := f.fillRow(sheet, rowNumber+recordIndex, rows[rowIndex], table, record); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// clearRow empties the values and formulas of the first width cells of the row at rowNumber, keeping their styles.
+// The row is not removed, since removing it would move the start of the ranges starting on it up a row.
+func (f templateFiller) clearRow(sheet string, rowNumber, width int) error {
+	for columnNumber := 1; columnNumber <= width; columnNumber++ {
+		cell, _ := excelize.CoordinatesToCellName(columnNumber, rowNumber)
+		if formula, _ := f.file.GetCellFormula(sheet, cell); len(formula) > 0 {
+			if err := f.file.SetCellFormula(sheet, cell, ""); err != nil {
+				return err
+			}
+		}
+		if err := f.file.SetCellValue(sheet, cell, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rowTable returns the name of the table whose placeholders the template row holds, empty for a row only holding
+// value placeholders. A row cannot hold the placeholders of two tables.
+func (f templateFiller) rowTable(rowNumber int, cells []string) (string, error) {
+	var table string
+	for _, text := range cells {
+		for _, match := range placeholderPattern.FindAllStringSubmatch(text, -1) {
+			name, _, qualified := strings.Cut(match[1], ".")
+			if _, known := f.data.Tables[name]; !qualified || !known {
+				continue
+			}
+			if len(table) > 0 && table != name {
+				return "", fmt.Errorf("row %d holds placeholders of the tables '%s' and '%s'", rowNumber, table, name)
+			}
+			table = name
+		}
+	}
+	return table, nil
+}
+
+// fillRow fills the placeholders of the cells of a template row into the row at rowNumber, taking the values
+// of the placeholders of table from record.
+func (f templateFiller) fillRow(sheet string, rowNumber int, cells []string, table string, record DataRow) error {
+	for columnIndex, text := range cells {
+		if !strings.Contains(text, "{{") {
+			continue
+		}
+		cell, _ := excelize.CoordinatesToCellName(columnIndex+1, rowNumber)
+		if formula, _ := f.file.GetCellFormula(sheet, cell); len(formula) > 0 {
+			continue
+		}
+		value, fillErr := f.cellValue(cell, text, table, record)
+		if fillErr != nil {
+			return fillErr
+		}
+		if err := f.file.SetCellValue(sheet, cell, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cellValue returns the value of a template cell holding text after filling its placeholders: the value itself for
+// a cell made of a single placeholder, the text with every placeholder replaced otherwise.
+func (f templateFiller) cellValue(cell, text, table string, record DataRow) (any, error) {
+	var missing []string
+	lookup := func(name string) any {
+		value, found := f.lookup(name, table, record)
+		if !found {
+			missing = append(missing, name)
+		}
+		return value
+	}
+	var value any
+	if match := placeholderPattern.FindStringSubmatch(text); match != nil && match[0] == text {
+		value = lookup(match[1])
+		if text, isText := value.(string); isText && f.opts.CoerceTypes {
+			value = coerceValue(text)
+		}
+	} else {
+		value = placeholderPattern.ReplaceAllStringFunc(text, func(placeholder string) string {
+			return templateText(lookup(placeholderPattern.FindStringSubmatch(placeholder)[1]))
+		})
+	}
+	if len(missing) > 0 && f.opts.Strict {
+		return nil, fmt.Errorf("no value for the placeholder '{{%s}}' in %s", missing[0], cell)
+	}
+	return value, nil
+}
+
+// lookup returns the value of the placeholder called name, taken from record when it names a column of table,
+// and whether any value is given for it.
+func (f templateFiller) lookup(name, table string, record DataRow) (any, bool) {
+	if column, isColumn := strings.CutPrefix(name, table+"."); len(table) > 0 && isColumn {
+		for _, dataColumn := range record.Columns {
+			if dataColumn.XMLName.Local == column {
+				return dataColumn.text(), true
+			}
+		}
+		return nil, false
+	}
+	value, found := f.data.Values[name]
+	return value, found
+}
+
+// templateText returns the text a value is written as within other text.
+func templateText(value any) string {
+	switch typed := value.(type) {
+	case nil:
+		return ""
+	case time.Time:
+		if hour, minute, second := typed.Clock(); hour == 0 && minute == 0 && second == 0 && typed.Nanosecond() == 0 {
+			return typed.Format(time.DateOnly)
+		}
+		return typed.Format(time.DateTime)
+	}
+	return fmt.Sprint(value)
+}
+
+// ParseTemplateData reads the data filling a template from the JSON object read from r. Arrays of objects become
+// tables, each object a row holding its members as columns, whose nested values are written as JSON. Every other
+// member becomes a value, nested objects being flattened with their keys joined by dots, as in {{customer.name}}.
+func ParseTemplateData(r io.Reader) (TemplateData, error) {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+	var document map[string]any
+	if err := decoder.Decode(&document); err != nil {
+		return TemplateData{}, fmt.Errorf("reading the template data: %w", err)
+	}
+	data := TemplateData{Values: make(map[string]any), Tables: make(map[string]DataTable)}
+	data.addJson("", document)
+	return data, nil
+}
+
+// addJson adds the members of a JSON object, their names prefixed with prefix, to the data.
+func (d TemplateData) addJson(prefix string, object map[string]any) {
+	for name, member := range object {
+		switch typed := member.(type) {
+		case map[string]any:
+			d.addJson(prefix+name+".", typed)
+		case []any:
+			if table, isTable := jsonTable(typed); isTable {
+				d.Tables[prefix+name] = table
+				continue
+			}
+			d.Values[prefix+name] = templateJson(member)
+		case json.Number:
+			if integer, err := strconv.ParseInt(typed.String(), 10, 64); err == nil {
+				d.Values[prefix+name] = integer
+			} else {
+				d.Values[prefix+name], _ = typed.Float64()
+			}
+		default:
+			d.Values[prefix+name] = member
+		}
+	}
+}
+
+// jsonTable returns the table of a JSON array holding nothing but objects.
+func jsonTable(array []any) (DataTable, bool) {
+	var table DataTable
+	for _, element := range array {
+		object, isObject := element.(map[string]any)
+		if !isObject {
+			return DataTable{}, false
+		}
+		var row DataRow
+		for name, member := range object {
+			row.Columns = append(row.Columns, DataColumn{XMLName: xml.Name{Local: name}, Value: templateJson(member)})
+		}
+		table.Rows = append(table.Rows, row)
+	}
+	return table, len(array) > 0
+}
+
+// templateJson returns the text of a JSON value: strings and numbers as they are, null as empty text
+// and arrays and objects encoded as JSON.
+func templateJson(value any) string {
+	switch typed := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return typed
+	case json.Number, bool:
+		return fmt.Sprint(typed)
+	}
+	encoded, _ := json.Marshal(value)
+	return string(encoded)
+}
@@ -0,0 +1,171 @@
+package converters
+
+import (
+	"context"
+	"encoding/xml"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// createTestTemplate saves a template with a title, an order table, a total row and a footer at a temporary path.
+func createTestTemplate(t *testing.T) string {
+	t.Helper()
+	file := excelize.NewFile()
+	defer func() {
+		_ = file.Close()
+	}()
+	sheet := file.GetSheetName(0)
+	cells := map[string]any{
+		"A1": "Invoice {{number}} for {{customer}}",
+		"A2": "{{date}}",
+		"A4": "Item", "B4": "Qty", "C4": "Price", "D4": "Amount",
+		"A5": "{{lines.Item}}", "B5": "{{lines.Qty}}", "C5": "{{lines.Price}}",
+		"A7": "Total",
+		"A8": "{{note}}",
+	}
+	for cell, value := range cells {
+		if err := file.SetCellValue(sheet, cell, value); err != nil {
+			t.Fatalf("Error setting template cell: %v", err)
+		}
+	}
+	if err := file.SetCellFormula(sheet, "D5", "B5*C5"); err != nil {
+		t.Fatalf("Error setting template formula: %v", err)
+	}
+	if err := file.SetCellFormula(sheet, "D7", "SUM(D5:D6)"); err != nil {
+		t.Fatalf("Error setting template formula: %v", err)
+	}
+	style, styleErr := file.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}, NumFmt: 4})
+	if styleErr != nil {
+		t.Fatalf("Error creating template style: %v", styleErr)
+	}
+	if err := file.SetCellStyle(sheet, "C5", "C5", style); err != nil {
+		t.Fatalf("Error styling template: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "template.xlsx")
+	if err := file.SaveAs(path); err != nil {
+		t.Fatalf("Error saving template: %v", err)
+	}
+	return path
+}
+
+// dataTable builds a DataTable from rows of column names and values.
+func dataTable(columns []string, rows ...[]string) DataTable {
+	var table DataTable
+	for _, values := range rows {
+		var row DataRow
+		for index, value := range values {
+			row.Columns = append(row.Columns, DataColumn{XMLName: xml.Name{Local: columns[index]}, Value: value})
+		}
+		table.Rows = append(table.Rows, row)
+	}
+	return table
+}
+
+func TestFillTemplate(t *testing.T) {
+	templatePath := createTestTemplate(t)
+	columns := []string{"Item", "Qty", "Price"}
+	values := map[string]any{"number": 42, "customer": "ACME", "date": time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), "note": "Thanks"}
+
+	tests := []struct {
+		name     string
+		data     TemplateData
+		opts     TemplateOptions
+		want     map[string]string
+		formulas map[string]string
+		wantErr  bool
+	}{
+		{
+			name: "Rows",
+			data: TemplateData{Values: values, Tables: map[string]DataTable{
+				"lines": dataTable(columns, []string{"Bolt", "3", "1.5"}, []string{"Nut", "10", "0.25"}),
+			}},
+			opts: TemplateOptions{CoerceTypes: true},
+			want: map[string]string{"A1": "Invoice 42 for ACME", "A5": "Bolt", "B5": "3", "C5": "1.50",
+				"A6": "Nut", "B6": "10", "C6": "0.25", "A8": "Total", "A9": "Thanks"},
+			formulas: map[string]string{"D5": "B5*C5", "D6": "B6*C6", "D8": "SUM(D5:D7)"},
+		},
+		{
+			name:     "No Rows",
+			data:     TemplateData{Values: values, Tables: map[string]DataTable{"lines": {}}},
+			want:     map[string]string{"A4": "Item", "A5": "", "D5": "", "A7": "Total", "A8": "Thanks"},
+			formulas: map[string]string{"D5": "", "D7": "SUM(D5:D6)"},
+		},
+		{
+			name: "Missing",
+			data: TemplateData{Values: map[string]any{"number": 7}},
+			want: map[string]string{"A1": "Invoice 7 for ", "A2": "", "A5": ""},
+		},
+		{
+			name:    "Strict",
+			data:    TemplateData{Values: map[string]any{"number": 7}},
+			opts:    TemplateOptions{Strict: true},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outPath := filepath.Join(t.TempDir(), "filled.xlsx")
+			err := FillTemplate(context.Background(), outPath, templatePath, tt.data, tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FillTemplate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			file, openErr := excelize.OpenFile(outPath)
+			if openErr != nil {
+				t.Fatalf("Error opening filled workbook: %v", openErr)
+			}
+			defer func() {
+				_ = file.Close()
+			}()
+			sheet := file.GetSheetName(0)
+			got := make(map[string]string)
+			for cell := range tt.want {
+				got[cell], _ = file.GetCellValue(sheet, cell)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FillTemplate() cells = %v, want %v", got, tt.want)
+			}
+			for cell, want := range tt.formulas {
+				if formula, _ := file.GetCellFormula(sheet, cell); formula != want {
+					t.Errorf("FillTemplate() formula of %s = %q, want %q", cell, formula, want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseTemplateData(t *testing.T) {
+	input := `{"number": 42, "rate": 0.5, "paid": true, "customer": {"name": "ACME", "city": null},
+		"tags": ["a", "b"], "lines": [{"Item": "Bolt", "Qty": 3, "Meta": {"color": "red"}}], "empty": []}`
+	data, err := ParseTemplateData(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseTemplateData() error = %v", err)
+	}
+	wantValues := map[string]any{"number": int64(42), "rate": 0.5, "paid": true, "customer.name": "ACME",
+		"customer.city": nil, "tags": `["a","b"]`, "empty": "[]"}
+	if !reflect.DeepEqual(data.Values, wantValues) {
+		t.Errorf("ParseTemplateData() values = %v, want %v", data.Values, wantValues)
+	}
+	lines, found := data.Tables["lines"]
+	if !found || len(data.Tables) != 1 || len(lines.Rows) != 1 {
+		t.Fatalf("ParseTemplateData() tables = %v, want a single row in lines", data.Tables)
+	}
+	got := make(map[string]string)
+	for _, column := range lines.Rows[0].Columns {
+		got[column.XMLName.Local] = column.Value
+	}
+	want := map[string]string{"Item": "Bolt", "Qty": "3", "Meta": `{"color":"red"}`}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseTemplateData() row = %v, want %v", got, want)
+	}
+	if _, err := ParseTemplateData(strings.NewReader(`[1, 2]`)); err == nil {
+		t.Errorf("ParseTemplateData() accepted an array")
+	}
+}
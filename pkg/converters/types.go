@@ -0,0 +1,139 @@
+package converters
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+
+	"GoTools/pkg/datatable"
+	. "GoTools/pkg/helpers"
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	xsiNamespace = "http://www.w3.org/2001/XMLSchema-instance"
+	xsNamespace  = "http://www.w3.org/2001/XMLSchema"
+)
+
+// CellType is the XML Schema type a cell value is serialized as. It is the type datatable infers columns with,
+// so that conversions and tables agree on the type of a value.
+type CellType = datatable.CellType
+
+const (
+	StringCell   = datatable.StringCell
+	IntegerCell  = datatable.IntegerCell
+	DecimalCell  = datatable.DecimalCell
+	DoubleCell   = datatable.DoubleCell
+	BooleanCell  = datatable.BooleanCell
+	DateCell     = datatable.DateCell
+	DateTimeCell = datatable.DateTimeCell
+)
+
+// ParseCellType returns the CellType called string, integer, decimal, double, boolean, date or datetime.
+func ParseCellType(name string) (CellType, error) {
+	return datatable.ParseCellType(name)
+}
+
+// xsdType returns the qualified xsi:type value of the cell type.
+func xsdType(cellType CellType) string {
+	switch cellType {
+	case IntegerCell:
+		return "xs:long"
+	case DecimalCell:
+		return "xs:decimal"
+	case DoubleCell:
+		return "xs:double"
+	case BooleanCell:
+		return "xs:boolean"
+	case DateCell:
+		return "xs:date"
+	case DateTimeCell:
+		return "xs:dateTime"
+	default:
+		return "xs:string"
+	}
+}
+
+// typed reports whether cells are annotated with xsi:type attributes.
+func (o XmlOptions) typed() bool {
+	return o.InferTypes || len(o.ColumnTypes) > 0
+}
+
+// namespaceAttrs returns the namespace declarations the root element needs for the configured output.
+// Nil cells only need the instance namespace.
+func (o XmlOptions) namespaceAttrs() []xml.Attr {
+	if !o.typed() {
+		if o.EmptyCells == NilEmpty && !o.Attributes {
+			return []xml.Attr{{Name: xml.Name{Local: "xmlns:xsi"}, Value: xsiNamespace}}
+		}
+		return nil
+	}
+	return []xml.Attr{
+		{Name: xml.Name{Local: "xmlns:xsi"}, Value: xsiNamespace},
+		{Name: xml.Name{Local: "xmlns:xs"}, Value: xsNamespace},
+	}
+}
+
+// annotateType rewrites the column value into the canonical form of its type and adds the matching xsi:type attribute.
+// Columns listed in ColumnTypes use the configured type, other columns are inferred when InferTypes is set.
+// A value that is not valid for its configured type is left untouched and emitted without an annotation.
+func (o XmlOptions) annotateType(column *DataColumn) {
+	cellType, configured := o.ColumnTypes[column.XMLName.Local]
+	if !configured {
+		if !o.InferTypes || len(column.Value) < 1 {
+			return
+		}
+		cellType = datatable.InferCellType(column.Value)
+	}
+	value, ok := cellType.Canonical(column.Value)
+	if !ok {
+		return
+	}
+	column.Value = value
+	column.Attributes = append(column.Attributes, xml.Attr{Name: xml.Name{Local: "xsi:type"}, Value: xsdType(cellType)})
+}
+
+// errHeaderRead stops reading a worksheet once its header row has been read.
+var errHeaderRead = errors.New("header read")
+
+// sheetColumnTypes returns the header of the named worksheet, with duplicates renamed as in the XML conversion,
+// and the type of every header column. When infer is set the type is inferred from the values of the column,
+// dates being converted as in the XML conversion, with a TypeInferrer configured by inference; reading stops
+// once the sample is full. Otherwise only the header row is read and every column is a string.
+func sheetColumnTypes(ctx context.Context, file *excelize.File, sheet string, opts ReadOptions, infer bool, inference InferenceOptions) ([]string, []CellType, error) {
+	reader, readerErr := newSheetReader(file, sheet, opts)
+	if readerErr != nil {
+		return nil, nil, readerErr
+	}
+	var header []string
+	var inferrers []*TypeInferrer
+	onHeader := func(columns []string, _ []int) error {
+		header = RenameDuplicates(columns, false)
+		inferrers = make([]*TypeInferrer, len(header))
+		for index := range inferrers {
+			inferrers[index] = NewTypeInferrer(inference)
+		}
+		return nil
+	}
+	rowErr := reader.eachRecord(ctx, onHeader, func(_ int, record []string) error {
+		if !infer {
+			return errHeaderRead
+		}
+		more := len(inferrers) > 0
+		for index, inferrer := range inferrers {
+			more = inferrer.Add(ConvertToISO8601(cellAt(record, index))) && more
+		}
+		if !more {
+			return errHeaderRead
+		}
+		return nil
+	})
+	if rowErr != nil && !errors.Is(rowErr, errHeaderRead) {
+		return nil, nil, rowErr
+	}
+	types := make([]CellType, len(header))
+	for index, inferrer := range inferrers {
+		types[index] = inferrer.Result().Type
+	}
+	return header, types, nil
+}
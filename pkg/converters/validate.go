@@ -0,0 +1,763 @@
+package converters
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxViolations caps the violations kept by a validation, so a badly mismatched schema cannot exhaust memory.
+const maxViolations = 1000
+
+// Violation is a single way a converted row breaks the schema it is validated against.
+// Row is the one-based row number in the worksheet and Column the element or attribute at fault,
+// empty when the row as a whole is at fault.
+type Violation struct {
+	Sheet  string
+	Row    int
+	Column string
+	Reason string
+}
+
+func (v Violation) Error() string {
+	if len(v.Column) > 0 {
+		return fmt.Sprintf("%s row %d, %s: %s", v.Sheet, v.Row, v.Column, v.Reason)
+	}
+	return fmt.Sprintf("%s row %d: %s", v.Sheet, v.Row, v.Reason)
+}
+
+// ValidationError is returned by a conversion whose output does not conform to the schema it is validated against.
+// It holds the first violations found, Total counts all of them.
+type ValidationError struct {
+	Violations []Violation
+	Total      int
+}
+
+func (e *ValidationError) Error() string {
+	var message strings.Builder
+	fmt.Fprintf(&message, "output does not conform to the schema, %d violations", e.Total)
+	for _, violation := range e.Violations[:min(len(e.Violations), 10)] {
+		message.WriteString("\n\t")
+		message.WriteString(violation.Error())
+	}
+	if e.Total > 10 {
+		fmt.Fprintf(&message, "\n\t... and %d more", e.Total-10)
+	}
+	return message.String()
+}
+
+// Schema is an XML Schema loaded to validate conversion output against.
+// It understands the subset of XML Schema that describes tabular documents: element and attribute declarations,
+// named and anonymous complex types with sequence, choice and all groups, occurrence bounds, nillable elements,
+// and simple types built from the common built-in types through restrictions, unions and lists.
+// Wildcards, substitution groups and identity constraints are not supported, and schemas importing or including
+// other schemas or deriving complex types with complexContent are rejected rather than partly validated.
+type Schema struct {
+	elements map[string]*schemaNode
+	types    map[string]*schemaNode
+	patterns map[string]*regexp.Regexp
+}
+
+// schemaNode is a generic element of a schema document.
+type schemaNode struct {
+	XMLName  xml.Name
+	Attrs    []xml.Attr   `xml:",any,attr"`
+	Children []schemaNode `xml:",any"`
+}
+
+// attr returns the value of the named attribute, or an empty string.
+func (n *schemaNode) attr(name string) string {
+	for _, attr := range n.Attrs {
+		if attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+// child returns the first child with the given local name, or nil.
+func (n *schemaNode) child(name string) *schemaNode {
+	for index := range n.Children {
+		if n.Children[index].XMLName.Local == name {
+			return &n.Children[index]
+		}
+	}
+	return nil
+}
+
+// LoadSchema reads the XML Schema at path.
+func LoadSchema(path string) (*Schema, error) {
+	file, openErr := os.Open(path)
+	if openErr != nil {
+		return nil, openErr
+	}
+	defer func(file *os.File) {
+		_ = file.Close()
+	}(file)
+	return ParseSchema(file)
+}
+
+// ParseSchema reads an XML Schema from r. Schemas using constructs the validation does not understand, pattern
+// facets using XML Schema regular expression syntax Go does not support, such as \i, \c or \p{IsBasicLatin}, and
+// malformed length facets are rejected, so a document is never reported valid against a schema only partly read.
+func ParseSchema(r io.Reader) (*Schema, error) {
+	var root schemaNode
+	if err := xml.NewDecoder(r).Decode(&root); err != nil {
+		return nil, fmt.Errorf("reading schema: %w", err)
+	}
+	if root.XMLName.Local != "schema" || root.XMLName.Space != xsNamespace {
+		return nil, fmt.Errorf("reading schema: root element is not an XML Schema")
+	}
+	schema := &Schema{elements: make(map[string]*schemaNode), types: make(map[string]*schemaNode), patterns: make(map[string]*regexp.Regexp)}
+	if err := schema.compileNode(&root); err != nil {
+		return nil, fmt.Errorf("reading schema: %w", err)
+	}
+	for index := range root.Children {
+		node := &root.Children[index]
+		switch node.XMLName.Local {
+		case "element":
+			schema.elements[node.attr("name")] = node
+		case "simpleType", "complexType":
+			schema.types[node.attr("name")] = node
+		}
+	}
+	return schema, nil
+}
+
+// compileNode rejects the unsupported constructs of the schema document below node, checks its length facets
+// and compiles its pattern facets. Annotations are left alone, as they may hold anything.
+func (s *Schema) compileNode(node *schemaNode) error {
+	if node.XMLName.Space != xsNamespace {
+		return nil
+	}
+	value := node.attr("value")
+	switch node.XMLName.Local {
+	case "annotation":
+		return nil
+	case "import", "include", "redefine", "override", "complexContent":
+		return fmt.Errorf("xs:%s is not supported", node.XMLName.Local)
+	case "pattern":
+		if _, compiled := s.patterns[value]; compiled {
+			break
+		}
+		// Go reads character class subtraction, [a-z-[aeiou]], as a different class rather than failing.
+		if strings.Contains(value, "-[") {
+			return fmt.Errorf("the pattern %s subtracts character classes, which is not supported", value)
+		}
+		pattern, err := regexp.Compile("^(?:" + value + ")$")
+		if err != nil {
+			return fmt.Errorf("the pattern %s is not supported: %w", value, err)
+		}
+		s.patterns[value] = pattern
+	case "length", "minLength", "maxLength":
+		if length, err := strconv.Atoi(value); err != nil || length < 0 {
+			return fmt.Errorf("the %s facet '%s' is not a length", node.XMLName.Local, value)
+		}
+	}
+	for index := range node.Children {
+		if err := s.compileNode(&node.Children[index]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// localName strips the namespace prefix of a QName.
+func localName(qname string) string {
+	if _, local, found := strings.Cut(qname, ":"); found {
+		return local
+	}
+	return qname
+}
+
+// rowValidator checks rows against the declaration of the row element of a schema.
+type rowValidator struct {
+	schema     *Schema
+	row        *rowModel
+	sheet      string
+	violations []Violation
+	total      int
+}
+
+// rowModel is the compiled content model of the row element.
+type rowModel struct {
+	group        string
+	repeat       bool
+	particles    []particle
+	attributes   map[string]attributeDecl
+	anyAttribute bool
+}
+
+type particle struct {
+	name      string
+	minOccurs int
+	maxOccurs int
+	nillable  bool
+	simple    *simpleType
+}
+
+type attributeDecl struct {
+	required bool
+	simple   *simpleType
+}
+
+// simpleType is a compiled simple type: a built-in type, a restriction of a base type, a union or a list.
+// The pattern facets of a restriction are held in patterns, a value matching any one of them.
+type simpleType struct {
+	builtin  string
+	base     *simpleType
+	facets   []schemaNode
+	patterns []*regexp.Regexp
+	union    []*simpleType
+	list     *simpleType
+}
+
+// newRowValidator finds the declaration of the row element in the schema. The row element is looked up
+// within the declaration of the root element, which is the Workbook element for combined documents.
+func newRowValidator(schema *Schema, opts Options) (*rowValidator, error) {
+	rootName := opts.Xml.rootName()
+	if opts.Combine {
+		rootName = workbookElement.Name.Local
+	}
+	root, declared := schema.elements[rootName]
+	if !declared {
+		return nil, fmt.Errorf("schema does not declare a %s root element", rootName)
+	}
+	rowDecl := schema.findElement(root, opts.Xml.rowName(), 0)
+	if rowDecl == nil {
+		return nil, fmt.Errorf("schema does not declare a %s element within %s", opts.Xml.rowName(), rootName)
+	}
+	model, modelErr := schema.compileRow(rowDecl)
+	if modelErr != nil {
+		return nil, modelErr
+	}
+	return &rowValidator{schema: schema, row: model}, nil
+}
+
+// findElement searches the declaration tree below node for the declaration of the named element.
+func (s *Schema) findElement(node *schemaNode, name string, depth int) *schemaNode {
+	if depth > 32 {
+		return nil
+	}
+	for index := range node.Children {
+		child := &node.Children[index]
+		if child.XMLName.Local == "element" {
+			if ref := child.attr("ref"); len(ref) > 0 {
+				child = s.elements[localName(ref)]
+				if child == nil {
+					continue
+				}
+			}
+			if child.attr("name") == name && depth > 0 {
+				return child
+			}
+		}
+		if found := s.findElement(child, name, depth+1); found != nil {
+			return found
+		}
+	}
+	if typeName := node.attr("type"); node.XMLName.Local == "element" && len(typeName) > 0 {
+		if named, exists := s.types[localName(typeName)]; exists {
+			return s.findElement(named, name, depth+1)
+		}
+	}
+	return nil
+}
+
+// complexTypeOf returns the complex type of an element declaration, inline or named, or nil for simple elements.
+func (s *Schema) complexTypeOf(decl *schemaNode) *schemaNode {
+	if inline := decl.child("complexType"); inline != nil {
+		return inline
+	}
+	if named, exists := s.types[localName(decl.attr("type"))]; exists && named.XMLName.Local == "complexType" {
+		return named
+	}
+	return nil
+}
+
+// compileRow compiles the content model of the row element declaration.
+func (s *Schema) compileRow(decl *schemaNode) (*rowModel, error) {
+	model := &rowModel{attributes: make(map[string]attributeDecl)}
+	complexType := s.complexTypeOf(decl)
+	if complexType == nil {
+		return model, nil
+	}
+	for index := range complexType.Children {
+		child := &complexType.Children[index]
+		switch child.XMLName.Local {
+		case "sequence", "choice", "all":
+			model.group = child.XMLName.Local
+			model.repeat = child.attr("maxOccurs") == "unbounded" || atoiDefault(child.attr("maxOccurs"), 1) > 1
+			for particleIndex := range child.Children {
+				element := &child.Children[particleIndex]
+				if element.XMLName.Local != "element" {
+					return nil, fmt.Errorf("schema row content uses unsupported %s", element.XMLName.Local)
+				}
+				compiled, particleErr := s.compileParticle(element)
+				if particleErr != nil {
+					return nil, particleErr
+				}
+				model.particles = append(model.particles, compiled)
+			}
+		case "attribute":
+			name := child.attr("name")
+			if ref := child.attr("ref"); len(ref) > 0 {
+				name = localName(ref)
+			}
+			simple, typeErr := s.compileSimple(child)
+			if typeErr != nil {
+				return nil, typeErr
+			}
+			model.attributes[name] = attributeDecl{required: child.attr("use") == "required", simple: simple}
+		case "anyAttribute":
+			model.anyAttribute = true
+		}
+	}
+	return model, nil
+}
+
+// compileParticle compiles an element declaration within the row content model.
+func (s *Schema) compileParticle(element *schemaNode) (particle, error) {
+	decl := element
+	if ref := element.attr("ref"); len(ref) > 0 {
+		if decl = s.elements[localName(ref)]; decl == nil {
+			return particle{}, fmt.Errorf("schema references undeclared element %s", ref)
+		}
+	}
+	compiled := particle{
+		name:      decl.attr("name"),
+		minOccurs: atoiDefault(element.attr("minOccurs"), 1),
+		maxOccurs: atoiDefault(element.attr("maxOccurs"), 1),
+		nillable:  decl.attr("nillable") == "true",
+	}
+	if element.attr("maxOccurs") == "unbounded" {
+		compiled.maxOccurs = -1
+	}
+	simple, typeErr := s.compileSimple(decl)
+	if typeErr != nil {
+		return particle{}, typeErr
+	}
+	compiled.simple = simple
+	return compiled, nil
+}
+
+// compileSimple compiles the simple type of an element or attribute declaration, inline or named.
+// Declarations without a type accept any value.
+func (s *Schema) compileSimple(decl *schemaNode) (*simpleType, error) {
+	if inline := decl.child("simpleType"); inline != nil {
+		return s.compileSimpleNode(inline, 0)
+	}
+	if typeName := decl.attr("type"); len(typeName) > 0 {
+		return s.resolveSimple(typeName, 0)
+	}
+	return &simpleType{builtin: "anySimpleType"}, nil
+}
+
+// resolveSimple resolves a type name to a named simple type of the schema or a built-in type.
+func (s *Schema) resolveSimple(typeName string, depth int) (*simpleType, error) {
+	if named, exists := s.types[localName(typeName)]; exists {
+		if named.XMLName.Local != "simpleType" {
+			return nil, fmt.Errorf("schema uses complex type %s for a column", typeName)
+		}
+		return s.compileSimpleNode(named, depth+1)
+	}
+	return &simpleType{builtin: localName(typeName)}, nil
+}
+
+// compileSimpleNode compiles a simpleType element.
+func (s *Schema) compileSimpleNode(node *schemaNode, depth int) (*simpleType, error) {
+	if depth > 32 {
+		return nil, fmt.Errorf("schema simple types are nested too deeply")
+	}
+	compiled := &simpleType{}
+	var compileErr error
+	if restriction := node.child("restriction"); restriction != nil {
+		if base := restriction.attr("base"); len(base) > 0 {
+			compiled.base, compileErr = s.resolveSimple(base, depth)
+		} else if inline := restriction.child("simpleType"); inline != nil {
+			compiled.base, compileErr = s.compileSimpleNode(inline, depth+1)
+		}
+		for _, facet := range restriction.Children {
+			switch facet.XMLName.Local {
+			case "simpleType":
+			case "pattern":
+				compiled.patterns = append(compiled.patterns, s.patterns[facet.attr("value")])
+			default:
+				compiled.facets = append(compiled.facets, facet)
+			}
+		}
+	} else if union := node.child("union"); union != nil {
+		for _, member := range strings.Fields(union.attr("memberTypes")) {
+			resolved, resolveErr := s.resolveSimple(member, depth)
+			if resolveErr != nil {
+				return nil, resolveErr
+			}
+			compiled.union = append(compiled.union, resolved)
+		}
+		for index := range union.Children {
+			if union.Children[index].XMLName.Local == "simpleType" {
+				member, memberErr := s.compileSimpleNode(&union.Children[index], depth+1)
+				if memberErr != nil {
+					return nil, memberErr
+				}
+				compiled.union = append(compiled.union, member)
+			}
+		}
+	} else if list := node.child("list"); list != nil {
+		if itemType := list.attr("itemType"); len(itemType) > 0 {
+			compiled.list, compileErr = s.resolveSimple(itemType, depth)
+		} else if inline := list.child("simpleType"); inline != nil {
+			compiled.list, compileErr = s.compileSimpleNode(inline, depth+1)
+		}
+	} else {
+		compiled.builtin = "anySimpleType"
+	}
+	return compiled, compileErr
+}
+
+func atoiDefault(value string, fallback int) int {
+	if parsed, err := strconv.Atoi(value); err == nil {
+		return parsed
+	}
+	return fallback
+}
+
+// startTable records the worksheet the following rows belong to.
+func (v *rowValidator) startTable(sheet string) {
+	v.sheet = sheet
+}
+
+// observe validates a row after it has been shaped for output.
+func (v *rowValidator) observe(rowNumber int, dataRow DataRow) {
+	report := func(column, reason string) {
+		v.total++
+		if len(v.violations) < maxViolations {
+			v.violations = append(v.violations, Violation{Sheet: v.sheet, Row: rowNumber, Column: column, Reason: reason})
+		}
+	}
+	v.validateAttributes(dataRow.Attributes, report)
+	v.validateElements(dataRow.Columns, report)
+}
+
+// err returns the ValidationError for the violations found, or nil if every row conformed.
+func (v *rowValidator) err() error {
+	if v.total < 1 {
+		return nil
+	}
+	return &ValidationError{Violations: v.violations, Total: v.total}
+}
+
+// validateAttributes checks the attributes of a row against the declared attributes.
+func (v *rowValidator) validateAttributes(attrs []xml.Attr, report func(column, reason string)) {
+	seen := make(map[string]bool, len(attrs))
+	for _, attr := range attrs {
+		name := localName(attr.Name.Local)
+		seen[name] = true
+		decl, declared := v.row.attributes[name]
+		if !declared {
+			if !v.row.anyAttribute {
+				report(name, "attribute is not declared")
+			}
+			continue
+		}
+		if reason := decl.simple.validate(attr.Value); len(reason) > 0 {
+			report(name, reason)
+		}
+	}
+	for name, decl := range v.row.attributes {
+		if decl.required && !seen[name] {
+			report(name, "required attribute is missing")
+		}
+	}
+}
+
+// validateElements checks the child elements of a row against the declared content model.
+func (v *rowValidator) validateElements(columns []DataColumn, report func(column, reason string)) {
+	byName := make(map[string]*particle, len(v.row.particles))
+	for index := range v.row.particles {
+		byName[v.row.particles[index].name] = &v.row.particles[index]
+	}
+	switch {
+	case v.row.group == "sequence" && !v.row.repeat:
+		position := 0
+		for index := range v.row.particles {
+			expected := &v.row.particles[index]
+			count := 0
+			for position < len(columns) && localName(columns[position].XMLName.Local) == expected.name &&
+				(expected.maxOccurs < 0 || count < expected.maxOccurs) {
+				v.validateColumn(expected, columns[position], report)
+				position++
+				count++
+			}
+			if count < expected.minOccurs {
+				report(expected.name, "required element is missing or out of order")
+			}
+		}
+		for ; position < len(columns); position++ {
+			report(columns[position].XMLName.Local, "element is not expected here")
+		}
+	default:
+		counts := make(map[string]int, len(columns))
+		for _, column := range columns {
+			expected, declared := byName[localName(column.XMLName.Local)]
+			if !declared {
+				report(column.XMLName.Local, "element is not declared")
+				continue
+			}
+			counts[expected.name]++
+			if v.row.group == "all" && counts[expected.name] > 1 {
+				report(expected.name, "element appears more than once")
+			}
+			v.validateColumn(expected, column, report)
+		}
+		if v.row.group == "all" {
+			for _, expected := range v.row.particles {
+				if expected.minOccurs > 0 && counts[expected.name] < 1 {
+					report(expected.name, "required element is missing")
+				}
+			}
+		}
+	}
+}
+
+// validateColumn checks the value of a single element, honouring xsi:nil and xsi:type annotations.
+func (v *rowValidator) validateColumn(expected *particle, column DataColumn, report func(column, reason string)) {
+	simple := expected.simple
+	for _, attr := range column.Attributes {
+		switch attr.Name.Local {
+		case "xsi:nil":
+			if attr.Value != "true" {
+				continue
+			}
+			if !expected.nillable {
+				report(expected.name, "element is not nillable")
+			} else if len(column.text()) > 0 {
+				report(expected.name, "nil element has content")
+			}
+			return
+		case "xsi:type":
+			annotated, _ := v.schema.resolveSimple(attr.Value, 0)
+			if annotated == nil {
+				continue
+			}
+			if !expected.simple.admits(annotated) {
+				report(expected.name, fmt.Sprintf("xsi:type %s is not derived from the declared type", attr.Value))
+				return
+			}
+			simple = annotated
+		}
+	}
+	if reason := simple.validate(column.text()); len(reason) > 0 {
+		report(expected.name, reason)
+	}
+}
+
+// validate returns why value is not valid for the simple type, or an empty string if it is.
+func (t *simpleType) validate(value string) string {
+	switch {
+	case len(t.union) > 0:
+		for _, member := range t.union {
+			if len(member.validate(value)) < 1 {
+				return ""
+			}
+		}
+		return fmt.Sprintf("'%s' matches none of the union member types", value)
+	case t.list != nil:
+		for _, item := range strings.Fields(value) {
+			if reason := t.list.validate(item); len(reason) > 0 {
+				return reason
+			}
+		}
+		return ""
+	case t.base != nil:
+		if reason := t.base.validate(value); len(reason) > 0 {
+			return reason
+		}
+		return t.checkFacets(value)
+	}
+	if !builtinValid(t.builtin, value) {
+		return fmt.Sprintf("'%s' is not a valid xs:%s", value, t.builtin)
+	}
+	return ""
+}
+
+// admits reports whether an xsi:type annotation may replace the type, which requires the annotated type
+// to be derived from it or, for unions, from one of its members. Built-in types are compared by their primitive.
+func (t *simpleType) admits(annotated *simpleType) bool {
+	if len(t.union) > 0 {
+		for _, member := range t.union {
+			if member.admits(annotated) {
+				return true
+			}
+		}
+		return false
+	}
+	declared, actual := t.primitive(), annotated.primitive()
+	integral := isNumericBuiltin(actual) && actual != "decimal" && actual != "double" && actual != "float"
+	return declared == "anySimpleType" || declared == actual ||
+		((declared == "decimal" || declared == "integer") && integral)
+}
+
+// primitive returns the built-in type a simple type is ultimately derived from.
+func (t *simpleType) primitive() string {
+	for current := t; current != nil; current = current.base {
+		if len(current.builtin) > 0 {
+			return current.builtin
+		}
+	}
+	return "anySimpleType"
+}
+
+// checkFacets returns why value breaks one of the facets of a restriction, or an empty string.
+func (t *simpleType) checkFacets(value string) string {
+	var enumerations []string
+	numeric := isNumericBuiltin(t.primitive())
+	if numeric || t.primitive() != "string" {
+		value = strings.TrimSpace(value)
+	}
+	for _, facet := range t.facets {
+		facetValue := facet.attr("value")
+		length := len([]rune(value))
+		switch facet.XMLName.Local {
+		case "enumeration":
+			enumerations = append(enumerations, facetValue)
+		case "length":
+			if expected, _ := strconv.Atoi(facetValue); length != expected {
+				return fmt.Sprintf("'%s' is not %s characters long", value, facetValue)
+			}
+		case "minLength":
+			if minimum, _ := strconv.Atoi(facetValue); length < minimum {
+				return fmt.Sprintf("'%s' is shorter than %s characters", value, facetValue)
+			}
+		case "maxLength":
+			if maximum, _ := strconv.Atoi(facetValue); length > maximum {
+				return fmt.Sprintf("'%s' is longer than %s characters", value, facetValue)
+			}
+		case "minInclusive", "maxInclusive", "minExclusive", "maxExclusive":
+			order, comparable := compareBound(t.primitive(), value, facetValue)
+			if !comparable {
+				continue
+			}
+			if (facet.XMLName.Local == "minInclusive" && order < 0) ||
+				(facet.XMLName.Local == "maxInclusive" && order > 0) ||
+				(facet.XMLName.Local == "minExclusive" && order <= 0) ||
+				(facet.XMLName.Local == "maxExclusive" && order >= 0) {
+				return fmt.Sprintf("'%s' is out of the %s bound %s", value, facet.XMLName.Local, facetValue)
+			}
+		}
+	}
+	if len(t.patterns) > 0 && !slices.ContainsFunc(t.patterns, func(pattern *regexp.Regexp) bool {
+		return pattern.MatchString(value)
+	}) {
+		patterns := make([]string, len(t.patterns))
+		for index, pattern := range t.patterns {
+			patterns[index] = strings.TrimSuffix(strings.TrimPrefix(pattern.String(), "^(?:"), ")$")
+		}
+		return fmt.Sprintf("'%s' does not match the pattern %s", value, strings.Join(patterns, " or "))
+	}
+	if len(enumerations) > 0 {
+		for _, allowed := range enumerations {
+			if value == allowed {
+				return ""
+			}
+		}
+		return fmt.Sprintf("'%s' is not one of the allowed values", value)
+	}
+	return ""
+}
+
+// compareBound orders a value against a range facet bound, numerically for numbers and chronologically for dates.
+func compareBound(primitive, value, bound string) (int, bool) {
+	if isNumericBuiltin(primitive) {
+		valueNumber, valueOk := new(big.Rat).SetString(value)
+		boundNumber, boundOk := new(big.Rat).SetString(bound)
+		if !valueOk || !boundOk {
+			return 0, false
+		}
+		return valueNumber.Cmp(boundNumber), true
+	}
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05", time.DateOnly} {
+		valueTime, valueErr := time.Parse(layout, value)
+		boundTime, boundErr := time.Parse(layout, bound)
+		if valueErr == nil && boundErr == nil {
+			return valueTime.Compare(boundTime), true
+		}
+	}
+	return 0, false
+}
+
+func isNumericBuiltin(builtin string) bool {
+	switch builtin {
+	case "decimal", "integer", "long", "int", "short", "byte", "double", "float",
+		"nonNegativeInteger", "positiveInteger", "nonPositiveInteger", "negativeInteger",
+		"unsignedLong", "unsignedInt", "unsignedShort", "unsignedByte":
+		return true
+	}
+	return false
+}
+
+var (
+	xsdDecimalPattern = regexp.MustCompile(`^[+-]?(\d+(\.\d*)?|\.\d+)$`)
+	xsdDoublePattern  = regexp.MustCompile(`^([+-]?(\d+(\.\d*)?|\.\d+)([eE][+-]?\d+)?|-?INF|NaN)$`)
+	xsdTimePattern    = regexp.MustCompile(`^\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2})?$`)
+	xsdIntegerRanges  = map[string][2]string{
+		"long":               {"-9223372036854775808", "9223372036854775807"},
+		"int":                {"-2147483648", "2147483647"},
+		"short":              {"-32768", "32767"},
+		"byte":               {"-128", "127"},
+		"nonNegativeInteger": {"0", ""},
+		"positiveInteger":    {"1", ""},
+		"nonPositiveInteger": {"", "0"},
+		"negativeInteger":    {"", "-1"},
+		"unsignedLong":       {"0", "18446744073709551615"},
+		"unsignedInt":        {"0", "4294967295"},
+		"unsignedShort":      {"0", "65535"},
+		"unsignedByte":       {"0", "255"},
+	}
+)
+
+// builtinValid reports whether value is a valid lexical form of the named built-in type.
+// Built-in types that are not checked accept any value.
+func builtinValid(builtin, value string) bool {
+	if builtin != "string" && builtin != "anySimpleType" {
+		value = strings.TrimSpace(value)
+	}
+	switch builtin {
+	case "boolean":
+		return value == "true" || value == "false" || value == "1" || value == "0"
+	case "decimal":
+		return xsdDecimalPattern.MatchString(value)
+	case "double", "float":
+		return xsdDoublePattern.MatchString(value)
+	case "integer":
+		_, ok := new(big.Int).SetString(value, 10)
+		return ok
+	case "date":
+		return lexicallyValid(DateCell, value)
+	case "dateTime":
+		return lexicallyValid(DateTimeCell, value)
+	case "time":
+		_, err := time.Parse("15:04:05", value[:min(len(value), 8)])
+		return err == nil && xsdTimePattern.MatchString(value)
+	}
+	if bounds, ranged := xsdIntegerRanges[builtin]; ranged {
+		integer, ok := new(big.Int).SetString(strings.TrimPrefix(value, "+"), 10)
+		if !ok {
+			return false
+		}
+		if lower, bounded := new(big.Int).SetString(bounds[0], 10); bounded && integer.Cmp(lower) < 0 {
+			return false
+		}
+		if upper, bounded := new(big.Int).SetString(bounds[1], 10); bounded && integer.Cmp(upper) > 0 {
+			return false
+		}
+	}
+	return true
+}
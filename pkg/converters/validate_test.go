@@ -0,0 +1,180 @@
+package converters
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testSchema = `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema">
+  <xs:simpleType name="Status">
+    <xs:restriction base="xs:string">
+      <xs:enumeration value="Open"/>
+      <xs:enumeration value="Closed"/>
+    </xs:restriction>
+  </xs:simpleType>
+  <xs:element name="DataTable">
+    <xs:complexType>
+      <xs:sequence>
+        <xs:element name="Row" minOccurs="0" maxOccurs="unbounded">
+          <xs:complexType>
+            <xs:sequence>
+              <xs:element name="Id" type="xs:positiveInteger"/>
+              <xs:element name="Status" type="Status"/>
+              <xs:element name="Amount" minOccurs="0">
+                <xs:simpleType>
+                  <xs:restriction base="xs:decimal">
+                    <xs:maxInclusive value="100"/>
+                  </xs:restriction>
+                </xs:simpleType>
+              </xs:element>
+            </xs:sequence>
+          </xs:complexType>
+        </xs:element>
+      </xs:sequence>
+    </xs:complexType>
+  </xs:element>
+</xs:schema>`
+
+func TestXlsxToXmlValidate(t *testing.T) {
+	filePath := createTestWorkbook(t, map[string][][]any{
+		"Orders": {{"Id", "Status", "Amount"}, {1, "Open", 10}, {0, "Pending", 250}, {3, "Closed", 99.5}},
+		"Extra":  {{"Id", "Status", "Note"}, {1, "Open", "late"}},
+	}, "Orders", "Extra")
+	schema, schemaErr := ParseSchema(strings.NewReader(testSchema))
+	if schemaErr != nil {
+		t.Fatalf("ParseSchema() error = %v", schemaErr)
+	}
+
+	tests := []struct {
+		name string
+		opts Options
+		want []Violation
+	}{
+		{
+			name: "Facets",
+			opts: Options{Validate: schema},
+			want: []Violation{
+				{Sheet: "Orders", Row: 3, Column: "Id", Reason: "'0' is not a valid xs:positiveInteger"},
+				{Sheet: "Orders", Row: 3, Column: "Status", Reason: "'Pending' is not one of the allowed values"},
+				{Sheet: "Orders", Row: 3, Column: "Amount", Reason: "'250' is out of the maxInclusive bound 100"},
+			},
+		},
+		{
+			name: "Undeclared Element",
+			opts: Options{Sheets: SheetByName("Extra"), Validate: schema},
+			want: []Violation{{Sheet: "Extra", Row: 2, Column: "Note", Reason: "element is not expected here"}},
+		},
+		{
+			name: "Conforming Rows",
+			opts: Options{Validate: schema, ReadOptions: ReadOptions{RowFilter: func(record map[string]string) bool {
+				return record["Status"] != "Pending"
+			}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output bytes.Buffer
+			err := XlsxToXml(context.Background(), &output, filePath, tt.opts)
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				if err != nil || tt.want != nil {
+					t.Fatalf("XlsxToXml() error = %v, want violations %v", err, tt.want)
+				}
+				return
+			}
+			if !reflect.DeepEqual(validationErr.Violations, tt.want) {
+				t.Errorf("XlsxToXml() violations = %v, want %v", validationErr.Violations, tt.want)
+			}
+			if output.Len() < 1 {
+				t.Errorf("XlsxToXml() wrote no output alongside the violations")
+			}
+		})
+	}
+}
+
+// schemaWith returns a schema declaring rows of a single Code column of the given simple type.
+func schemaWith(simpleType string) string {
+	return `<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema">
+  <xs:element name="DataTable">
+    <xs:complexType>
+      <xs:sequence>
+        <xs:element name="Row" minOccurs="0" maxOccurs="unbounded">
+          <xs:complexType>
+            <xs:sequence>
+              <xs:element name="Code">` + simpleType + `</xs:element>
+            </xs:sequence>
+          </xs:complexType>
+        </xs:element>
+      </xs:sequence>
+    </xs:complexType>
+  </xs:element>
+</xs:schema>`
+}
+
+func TestXlsxToXmlValidatePatterns(t *testing.T) {
+	filePath := createTestWorkbook(t, map[string][][]any{
+		"Codes": {{"Code"}, {"AB12"}, {"1234"}, {"x1"}, {"12345"}},
+	}, "Codes")
+	schema, schemaErr := ParseSchema(strings.NewReader(schemaWith(`<xs:simpleType>
+  <xs:restriction base="xs:string">
+    <xs:pattern value="[A-Z]{2}\d{2}"/>
+    <xs:pattern value="\d+"/>
+    <xs:maxLength value="4"/>
+  </xs:restriction>
+</xs:simpleType>`)))
+	if schemaErr != nil {
+		t.Fatalf("ParseSchema() error = %v", schemaErr)
+	}
+	var output bytes.Buffer
+	err := XlsxToXml(context.Background(), &output, filePath, Options{Validate: schema})
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("XlsxToXml() error = %v, want violations", err)
+	}
+	want := []Violation{
+		{Sheet: "Codes", Row: 4, Column: "Code", Reason: `'x1' does not match the pattern [A-Z]{2}\d{2} or \d+`},
+		{Sheet: "Codes", Row: 5, Column: "Code", Reason: "'12345' is longer than 4 characters"},
+	}
+	if !reflect.DeepEqual(validationErr.Violations, want) {
+		t.Errorf("XlsxToXml() violations = %v, want %v", validationErr.Violations, want)
+	}
+}
+
+func TestParseSchemaUnsupported(t *testing.T) {
+	restriction := func(facet string) string {
+		return schemaWith(`<xs:simpleType><xs:restriction base="xs:string">` + facet + `</xs:restriction></xs:simpleType>`)
+	}
+	tests := []struct {
+		name   string
+		schema string
+		want   string
+	}{
+		{name: "Name Character Escape", schema: restriction(`<xs:pattern value="\i\c*"/>`), want: `the pattern \i\c* is not supported`},
+		{name: "Unicode Block", schema: restriction(`<xs:pattern value="\p{IsBasicLatin}+"/>`), want: `the pattern \p{IsBasicLatin}+ is not supported`},
+		{name: "Class Subtraction", schema: restriction(`<xs:pattern value="[a-z-[aeiou]]+"/>`), want: "subtracts character classes"},
+		{name: "Malformed Length", schema: restriction(`<xs:length value="four"/>`), want: "the length facet 'four' is not a length"},
+		{name: "Negative Length", schema: restriction(`<xs:maxLength value="-1"/>`), want: "the maxLength facet '-1' is not a length"},
+		{name: "Import", schema: strings.Replace(schemaWith(""), "<xs:element name=\"DataTable\">",
+			`<xs:import namespace="urn:other" schemaLocation="other.xsd"/><xs:element name="DataTable">`, 1), want: "xs:import is not supported"},
+		{name: "Include", schema: strings.Replace(schemaWith(""), "<xs:element name=\"DataTable\">",
+			`<xs:include schemaLocation="other.xsd"/><xs:element name="DataTable">`, 1), want: "xs:include is not supported"},
+		{name: "Complex Content", schema: schemaWith(`<xs:complexType><xs:complexContent><xs:extension base="Base"/></xs:complexContent></xs:complexType>`),
+			want: "xs:complexContent is not supported"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseSchema(strings.NewReader(tt.schema)); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ParseSchema() error = %v, want one mentioning %q", err, tt.want)
+			}
+		})
+	}
+	annotated := schemaWith(`<xs:annotation><xs:appinfo><pattern value="\i"/></xs:appinfo></xs:annotation>`)
+	if _, err := ParseSchema(strings.NewReader(annotated)); err != nil {
+		t.Errorf("ParseSchema(annotated) error = %v", err)
+	}
+}
@@ -0,0 +1,296 @@
+package converters
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"GoTools/pkg/ods"
+	"GoTools/pkg/xls"
+	"github.com/xuri/excelize/v2"
+)
+
+// PasswordSource supplies the password of an encrypted workbook. It is only called when the workbook is opened,
+// so the password can be fetched from a secret store at that point instead of being kept in the options.
+// An empty password opens the workbook as unencrypted.
+type PasswordSource func() (string, error)
+
+// StaticPassword returns a PasswordSource supplying the given password.
+func StaticPassword(password string) PasswordSource {
+	return func() (string, error) {
+		return password, nil
+	}
+}
+
+// PasswordFromEnv returns a PasswordSource reading the password from the named environment variable.
+// An unset variable supplies no password.
+func PasswordFromEnv(name string) PasswordSource {
+	return func() (string, error) {
+		return os.Getenv(name), nil
+	}
+}
+
+// openWorkbook opens the workbook at path, decrypting it with the password supplied by the ReadOptions, if any.
+// Legacy .xls workbooks and OpenDocument spreadsheets are loaded into an in-memory workbook, so they go through the
+// same pipeline as .xlsx files, as are fixed-width text files read with a layout and Google Sheets,
+// whose values are fetched until ctx is done.
+func openWorkbook(ctx context.Context, path string, opts ReadOptions) (*excelize.File, error) {
+	if opts.GoogleSheet != nil {
+		return openGoogleSheet(ctx, path, *opts.GoogleSheet)
+	}
+	if len(opts.FixedWidth) > 0 {
+		return openFixedWidth(path, opts)
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".xls":
+		return openLegacyWorkbook(path)
+	case ".ods":
+		return openOpenDocument(path)
+	}
+	var password string
+	if opts.Password != nil {
+		var passwordErr error
+		if password, passwordErr = opts.Password(); passwordErr != nil {
+			return nil, fmt.Errorf("reading the workbook password: %w", passwordErr)
+		}
+	}
+	file, openErr := excelize.OpenFile(path, excelize.Options{Password: password})
+	if openErr != nil && len(password) < 1 && isCompoundFile(path) {
+		return nil, fmt.Errorf("%w, the workbook may be password protected", openErr)
+	}
+	return file, openErr
+}
+
+// compoundFileSignature starts every OLE compound file, which is how encrypted workbooks are stored.
+var compoundFileSignature = []byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1}
+
+// isCompoundFile reports whether the file at path is an OLE compound file rather than a zip package.
+func isCompoundFile(path string) bool {
+	file, openErr := os.Open(path)
+	if openErr != nil {
+		return false
+	}
+	defer func(file *os.File) {
+		_ = file.Close()
+	}(file)
+	signature := make([]byte, len(compoundFileSignature))
+	if _, readErr := io.ReadFull(file, signature); readErr != nil {
+		return false
+	}
+	return bytes.Equal(signature, compoundFileSignature)
+}
+
+// openLegacyWorkbook reads the Excel 97-2003 workbook at path into an in-memory workbook.
+// Cells keep their number format, so numbers and dates read as they would from the same workbook saved as .xlsx.
+// Formulas are not decoded, formula cells always read as the result cached when the workbook was last calculated.
+func openLegacyWorkbook(path string) (*excelize.File, error) {
+	book, readErr := xls.Open(path)
+	if readErr != nil {
+		return nil, readErr
+	}
+	file := excelize.NewFile()
+	file.Path = path
+	loadErr := loadLegacyWorkbook(file, book)
+	if loadErr != nil {
+		_ = file.Close()
+		return nil, loadErr
+	}
+	return file, nil
+}
+
+// loadLegacyWorkbook writes the worksheets of book into the empty workbook file.
+func loadLegacyWorkbook(file *excelize.File, book *xls.Workbook) error {
+	if len(book.Sheets) < 1 {
+		return fmt.Errorf("%s holds no worksheets", filepath.Base(file.Path))
+	}
+	if err := file.SetWorkbookProps(&excelize.WorkbookPropsOptions{Date1904: &book.Date1904}); err != nil {
+		return err
+	}
+	styles := make(map[int]int)
+	for index, sheet := range book.Sheets {
+		if err := createSheet(file, index, sheet.Name); err != nil {
+			return err
+		}
+		lastRow, lastCol := -1, -1
+		for _, cell := range sheet.Cells {
+			if err := setLegacyCell(file, sheet.Name, cell, styles); err != nil {
+				return err
+			}
+			lastRow, lastCol = max(lastRow, cell.Row), max(lastCol, cell.Col)
+		}
+		for _, merged := range sheet.Merged {
+			if err := mergeRange(file, sheet.Name, merged.FirstRow, merged.FirstCol, merged.LastRow, merged.LastCol); err != nil {
+				return err
+			}
+			lastRow, lastCol = max(lastRow, merged.LastRow), max(lastCol, merged.LastCol)
+		}
+		if err := hideSpans(file, sheet.Name, sheet.HiddenRows, sheet.HiddenCols, lastRow, lastCol); err != nil {
+			return err
+		}
+	}
+	for _, sheet := range book.Sheets {
+		if sheet.Hidden {
+			if err := file.SetSheetVisible(sheet.Name, false); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// createSheet names the sheet at index of a workbook being loaded, reusing the sheet every new workbook starts with.
+func createSheet(file *excelize.File, index int, name string) error {
+	if index == 0 {
+		return file.SetSheetName(file.GetSheetName(0), name)
+	}
+	_, err := file.NewSheet(name)
+	return err
+}
+
+// mergeRange merges the cells between the given zero-based, inclusive coordinates.
+func mergeRange(file *excelize.File, sheet string, firstRow, firstCol, lastRow, lastCol int) error {
+	topLeft, topLeftErr := excelize.CoordinatesToCellName(firstCol+1, firstRow+1)
+	if topLeftErr != nil {
+		return topLeftErr
+	}
+	bottomRight, bottomRightErr := excelize.CoordinatesToCellName(lastCol+1, lastRow+1)
+	if bottomRightErr != nil {
+		return bottomRightErr
+	}
+	return file.MergeCell(sheet, topLeft, bottomRight)
+}
+
+// hideSpans hides the rows and the columns of the sheet within the runs read from an .xls or .ods file, so that
+// SkipHiddenRows and SkipHiddenColumns leave them out as they do for .xlsx files. Runs are cut at lastRow and
+// lastCol, past which the sheet holds no cell, as OpenDocument repeats its last definitions to the edge of the sheet.
+func hideSpans[S xls.Span | ods.Span](file *excelize.File, sheet string, rows, cols []S, lastRow, lastCol int) error {
+	for _, run := range rows {
+		bounds := xls.Span(run)
+		for row := bounds.First; row <= min(bounds.Last, lastRow); row++ {
+			if err := file.SetRowVisible(sheet, row+1, false); err != nil {
+				return err
+			}
+		}
+	}
+	for _, run := range cols {
+		bounds := xls.Span(run)
+		if bounds.First > lastCol {
+			continue
+		}
+		first, firstErr := excelize.ColumnNumberToName(bounds.First + 1)
+		if firstErr != nil {
+			return firstErr
+		}
+		last, lastErr := excelize.ColumnNumberToName(min(bounds.Last, lastCol) + 1)
+		if lastErr != nil {
+			return lastErr
+		}
+		if err := file.SetColVisible(sheet, first+":"+last, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setLegacyCell writes a single cell, styled with its number format. Styles are shared between cells through
+// styles, which maps number format ids to style ids.
+func setLegacyCell(file *excelize.File, sheet string, cell xls.Cell, styles map[int]int) error {
+	cellName, nameErr := excelize.CoordinatesToCellName(cell.Col+1, cell.Row+1)
+	if nameErr != nil {
+		return nameErr
+	}
+	switch cell.Kind {
+	case xls.NumberCell:
+		if err := file.SetCellFloat(sheet, cellName, cell.Number, -1, 64); err != nil {
+			return err
+		}
+	case xls.BoolCell:
+		return file.SetCellBool(sheet, cellName, cell.Bool)
+	default:
+		return file.SetCellStr(sheet, cellName, cell.Text)
+	}
+	if cell.FormatID == 0 {
+		return nil
+	}
+	styleID, exists := styles[cell.FormatID]
+	if !exists {
+		style := &excelize.Style{NumFmt: cell.FormatID}
+		if len(cell.Format) > 0 {
+			style = &excelize.Style{CustomNumFmt: &cell.Format}
+		}
+		var styleErr error
+		if styleID, styleErr = file.NewStyle(style); styleErr != nil {
+			return styleErr
+		}
+		styles[cell.FormatID] = styleID
+	}
+	return file.SetCellStyle(sheet, cellName, cellName, styleID)
+}
+
+// openOpenDocument reads the OpenDocument spreadsheet at path into an in-memory workbook.
+// Plain numbers read as their full-precision value and dates as ISO 8601, every other cell reads as the text it is
+// displayed with, so percentages, currencies and times keep their formatting.
+func openOpenDocument(path string) (*excelize.File, error) {
+	book, readErr := ods.Open(path)
+	if readErr != nil {
+		return nil, readErr
+	}
+	if len(book.Sheets) < 1 {
+		return nil, fmt.Errorf("%s holds no sheets", filepath.Base(path))
+	}
+	file := excelize.NewFile()
+	file.Path = path
+	if loadErr := loadOpenDocument(file, book); loadErr != nil {
+		_ = file.Close()
+		return nil, loadErr
+	}
+	return file, nil
+}
+
+// loadOpenDocument writes the sheets of book into the empty workbook file.
+func loadOpenDocument(file *excelize.File, book *ods.Workbook) error {
+	for index, sheet := range book.Sheets {
+		if err := createSheet(file, index, sheet.Name); err != nil {
+			return err
+		}
+		lastRow, lastCol := -1, -1
+		for _, cell := range sheet.Cells {
+			cellName, nameErr := excelize.CoordinatesToCellName(cell.Col+1, cell.Row+1)
+			if nameErr != nil {
+				return nameErr
+			}
+			value := cell.Text
+			switch cell.Type {
+			case "float", "date":
+				value = cell.Value
+			case "boolean":
+				value = strings.ToUpper(cell.Value)
+			}
+			if err := file.SetCellStr(sheet.Name, cellName, value); err != nil {
+				return err
+			}
+			lastRow, lastCol = max(lastRow, cell.Row), max(lastCol, cell.Col)
+		}
+		for _, merged := range sheet.Merged {
+			if err := mergeRange(file, sheet.Name, merged.FirstRow, merged.FirstCol, merged.LastRow, merged.LastCol); err != nil {
+				return err
+			}
+			lastRow, lastCol = max(lastRow, merged.LastRow), max(lastCol, merged.LastCol)
+		}
+		if err := hideSpans(file, sheet.Name, sheet.HiddenRows, sheet.HiddenCols, lastRow, lastCol); err != nil {
+			return err
+		}
+	}
+	for _, sheet := range book.Sheets {
+		if sheet.Hidden {
+			if err := file.SetSheetVisible(sheet.Name, false); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
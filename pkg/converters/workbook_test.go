@@ -0,0 +1,108 @@
+package converters
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"GoTools/pkg/ods"
+	"GoTools/pkg/xls"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestXlsxToXmlPassword(t *testing.T) {
+	file := excelize.NewFile()
+	if err := file.SetSheetRow("Sheet1", "A1", &[]any{"Secret"}); err != nil {
+		t.Fatalf("Error writing test row: %v", err)
+	}
+	if err := file.SetSheetRow("Sheet1", "A2", &[]any{"42"}); err != nil {
+		t.Fatalf("Error writing test row: %v", err)
+	}
+	filePath := filepath.Join(t.TempDir(), "Encrypted.xlsx")
+	if err := file.SaveAs(filePath, excelize.Options{Password: "hunter2"}); err != nil {
+		t.Fatalf("Error saving encrypted test workbook: %v", err)
+	}
+	_ = file.Close()
+
+	var output bytes.Buffer
+	if err := XlsxToXml(context.Background(), &output, filePath, Options{}); err == nil || !strings.Contains(err.Error(), "password protected") {
+		t.Errorf("XlsxToXml() without password error = %v, want a password hint", err)
+	}
+	wrong := Options{ReadOptions: ReadOptions{Password: StaticPassword("wrong")}}
+	if err := XlsxToXml(context.Background(), &output, filePath, wrong); !errors.Is(err, excelize.ErrWorkbookPassword) {
+		t.Errorf("XlsxToXml() with wrong password error = %v, want %v", err, excelize.ErrWorkbookPassword)
+	}
+	t.Setenv("TEST_XLSX_PASSWORD", "hunter2")
+	right := Options{ReadOptions: ReadOptions{Password: PasswordFromEnv("TEST_XLSX_PASSWORD")}}
+	if err := XlsxToXml(context.Background(), &output, filePath, right); err != nil {
+		t.Fatalf("XlsxToXml() error = %v", err)
+	}
+	if !strings.Contains(output.String(), "<Secret>42</Secret>") {
+		t.Errorf("XlsxToXml() output = %s, want the decrypted row", output.String())
+	}
+}
+
+func TestLegacyHiddenRowsAndColumns(t *testing.T) {
+	legacy := &xls.Workbook{Sheets: []xls.Sheet{{
+		Name: "Sheet1",
+		Cells: []xls.Cell{
+			{Row: 0, Col: 0, Kind: xls.TextCell, Text: "Id"}, {Row: 0, Col: 1, Kind: xls.TextCell, Text: "Secret"},
+			{Row: 0, Col: 2, Kind: xls.TextCell, Text: "Name"},
+			{Row: 1, Col: 0, Number: 1}, {Row: 1, Col: 1, Kind: xls.TextCell, Text: "x"}, {Row: 1, Col: 2, Kind: xls.TextCell, Text: "Ann"},
+			{Row: 2, Col: 0, Number: 2}, {Row: 2, Col: 1, Kind: xls.TextCell, Text: "y"}, {Row: 2, Col: 2, Kind: xls.TextCell, Text: "Bob"},
+		},
+		HiddenRows: []xls.Span{{First: 2, Last: 2}},
+		HiddenCols: []xls.Span{{First: 1, Last: 1}},
+	}}}
+	document := &ods.Workbook{Sheets: []ods.Sheet{{
+		Name: "Sheet1",
+		Cells: []ods.Cell{
+			{Row: 0, Col: 0, Type: "string", Text: "Id"}, {Row: 0, Col: 1, Type: "string", Text: "Secret"},
+			{Row: 0, Col: 2, Type: "string", Text: "Name"},
+			{Row: 1, Col: 0, Type: "float", Value: "1"}, {Row: 1, Col: 1, Type: "string", Text: "x"}, {Row: 1, Col: 2, Type: "string", Text: "Ann"},
+			{Row: 2, Col: 0, Type: "float", Value: "2"}, {Row: 2, Col: 1, Type: "string", Text: "y"}, {Row: 2, Col: 2, Type: "string", Text: "Bob"},
+		},
+		// OpenDocument repeats the last definitions to the edge of the sheet.
+		HiddenRows: []ods.Span{{First: 2, Last: 1048575}},
+		HiddenCols: []ods.Span{{First: 1, Last: 1}, {First: 3, Last: 16383}},
+	}}}
+
+	tests := []struct {
+		name string
+		load func(file *excelize.File) error
+	}{
+		{name: "Excel 97-2003", load: func(file *excelize.File) error { return loadLegacyWorkbook(file, legacy) }},
+		{name: "OpenDocument", load: func(file *excelize.File) error { return loadOpenDocument(file, document) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := excelize.NewFile()
+			file.Path = "legacy"
+			if err := tt.load(file); err != nil {
+				t.Fatalf("load error = %v", err)
+			}
+			reader, readerErr := newSheetReader(file, "Sheet1", ReadOptions{SkipHiddenRows: true, SkipHiddenColumns: true})
+			if readerErr != nil {
+				t.Fatalf("newSheetReader() error = %v", readerErr)
+			}
+			var records [][]string
+			err := reader.eachRecord(context.Background(), func(header []string, _ []int) error {
+				records = append(records, header)
+				return nil
+			}, func(_ int, columns []string) error {
+				records = append(records, columns)
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("eachRecord() error = %v", err)
+			}
+			if want := [][]string{{"Id", "Name"}, {"1", "Ann"}}; !reflect.DeepEqual(records, want) {
+				t.Errorf("records = %v, want %v", records, want)
+			}
+		})
+	}
+}
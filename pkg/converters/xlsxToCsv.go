@@ -0,0 +1,260 @@
+package converters
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	. "GoTools/pkg/helpers"
+	"github.com/xuri/excelize/v2"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+// CsvOptions configures the conversion of worksheets into CSV.
+// Sheets selects the worksheets to convert; XlsxToCsv only converts a single one.
+// Delimiter separates the fields and Quote encloses the fields holding the delimiter, the quote, a line break or
+// leading spaces, doubled within them. They default to a comma and a double quote.
+// Newline ends every record, "\n" by default or "\r\n".
+// OmitHeader leaves out the header row, which is written first otherwise. Dates are converted as in the XML conversion.
+// Encoding selects the output encoding, UTF-8 by default, UTF-16 (little endian with a byte order mark),
+// UTF-16LE, UTF-16BE, ISO-8859-1 or Windows-1252; characters the encoding cannot represent fail the conversion.
+// ByteOrderMark starts UTF-8 output with a byte order mark, which Excel needs to detect UTF-8.
+// TransliterateHeaders spells the accented and special Latin letters of the header row in ASCII, "Größe"
+// becoming "Grosse", which also keeps them representable in ISO-8859-1 and Windows-1252.
+// The embedded ReadOptions control how the rows of every worksheet are read.
+type CsvOptions struct {
+	ReadOptions
+	Sheets               SheetSelector
+	Delimiter            rune
+	Quote                rune
+	Newline              string
+	OmitHeader           bool
+	Encoding             string
+	ByteOrderMark        bool
+	TransliterateHeaders bool
+}
+
+// dialect returns the delimiter, the quote and the line ending, falling back to their defaults.
+func (o CsvOptions) dialect() (delimiter, quote rune, newline string) {
+	delimiter, quote, newline = o.Delimiter, o.Quote, o.Newline
+	if delimiter == 0 {
+		delimiter = ','
+	}
+	if quote == 0 {
+		quote = '"'
+	}
+	if len(newline) < 1 {
+		newline = "\n"
+	}
+	return delimiter, quote, newline
+}
+
+// validate reports options that cannot produce a readable conversion.
+func (o CsvOptions) validate() error {
+	if err := o.ReadOptions.validate(); err != nil {
+		return err
+	}
+	delimiter, quote, newline := o.dialect()
+	if delimiter == quote {
+		return errors.New("the delimiter and the quote must differ")
+	}
+	if strings.ContainsRune("\r\n", delimiter) || strings.ContainsRune("\r\n", quote) {
+		return errors.New("the delimiter and the quote cannot be line breaks")
+	}
+	if newline != "\n" && newline != "\r\n" {
+		return fmt.Errorf("unsupported line ending %q, use \"\\n\" or \"\\r\\n\"", newline)
+	}
+	target, encodingErr := csvEncoding(o.Encoding)
+	if encodingErr != nil {
+		return encodingErr
+	}
+	if o.ByteOrderMark && target != nil {
+		return errors.New("a byte order mark can only be requested for UTF-8")
+	}
+	return nil
+}
+
+// csvEncoding returns the encoding called name, or nil for UTF-8.
+func csvEncoding(name string) (encoding.Encoding, error) {
+	switch strings.ToUpper(strings.ReplaceAll(name, "_", "-")) {
+	case "WINDOWS-1252", "CP1252":
+		return charmap.Windows1252, nil
+	}
+	canonical, err := canonicalEncoding(name)
+	if err != nil {
+		return nil, err
+	}
+	switch canonical {
+	case "UTF-16":
+		return unicode.UTF16(unicode.LittleEndian, unicode.UseBOM), nil
+	case "UTF-16LE":
+		return unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM), nil
+	case "UTF-16BE":
+		return unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM), nil
+	case "ISO-8859-1":
+		return charmap.ISO8859_1, nil
+	}
+	return nil, nil
+}
+
+// XlsxToCsv converts the worksheet of the .xlsx file at path selected by opts.Sheets and writes the CSV to w.
+// Legacy .xls workbooks and OpenDocument spreadsheets (.ods) are read as well.
+// Use XlsxToCsvFiles to convert several worksheets.
+// The conversion stops with the error of ctx as soon as it is cancelled or its deadline passes.
+func XlsxToCsv(ctx context.Context, w io.Writer, path string, opts CsvOptions) (convertErr error) {
+	if optsErr := opts.validate(); optsErr != nil {
+		return optsErr
+	}
+	file, openErr := openWorkbook(ctx, path, opts.ReadOptions)
+	if openErr != nil {
+		return openErr
+	}
+	defer func(file *excelize.File) {
+		if err := file.Close(); err != nil && convertErr == nil {
+			convertErr = err
+		}
+	}(file)
+
+	sheets, selectErr := opts.selectSheets(file, opts.Sheets)
+	if selectErr != nil {
+		return selectErr
+	}
+	if len(sheets) > 1 {
+		return errors.New("multiple worksheets selected, write one file per worksheet")
+	}
+	return writeCsvSheet(ctx, w, file, sheets[0], opts)
+}
+
+// XlsxToCsvFiles converts the worksheets of the .xlsx file at path selected by opts.Sheets
+// and writes one CSV file per worksheet into dir, named after the worksheet.
+// The conversion stops with the error of ctx as soon as it is cancelled, leaving the files written so far.
+// It returns the paths of the files written.
+func XlsxToCsvFiles(ctx context.Context, dir, path string, opts CsvOptions) (written []string, convertErr error) {
+	if optsErr := opts.validate(); optsErr != nil {
+		return nil, optsErr
+	}
+	file, openErr := openWorkbook(ctx, path, opts.ReadOptions)
+	if openErr != nil {
+		return nil, openErr
+	}
+	defer func(file *excelize.File) {
+		if err := file.Close(); err != nil && convertErr == nil {
+			convertErr = err
+		}
+	}(file)
+
+	sheets, selectErr := opts.selectSheets(file, opts.Sheets)
+	if selectErr != nil {
+		return nil, selectErr
+	}
+	files := &outputFiles{}
+	for _, sheet := range sheets {
+		writeErr := files.writeFile(filepath.Join(dir, sheet+".csv"), func(w io.Writer) error {
+			return writeCsvSheet(ctx, w, file, sheet, opts)
+		})
+		if writeErr != nil {
+			return files.written, writeErr
+		}
+	}
+	return files.written, nil
+}
+
+// writeCsvSheet writes the header and the records of the named worksheet to w in the configured dialect and encoding.
+func writeCsvSheet(ctx context.Context, w io.Writer, file *excelize.File, sheet string, opts CsvOptions) error {
+	reader, readerErr := newSheetReader(file, sheet, opts.ReadOptions)
+	if readerErr != nil {
+		return readerErr
+	}
+	if opts.ByteOrderMark {
+		if _, err := w.Write(utf8BOM); err != nil {
+			return err
+		}
+	}
+	target, _ := csvEncoding(opts.Encoding)
+	var transcoder *transform.Writer
+	if target != nil {
+		transcoder = transform.NewWriter(w, target.NewEncoder())
+		w = transcoder
+	}
+	writer := &csvWriter{w: bufio.NewWriter(w)}
+	writer.delimiter, writer.quote, writer.newline = opts.dialect()
+
+	var width int
+	onHeader := func(columns []string, _ []int) error {
+		width = len(columns)
+		if opts.OmitHeader {
+			return nil
+		}
+		if opts.TransliterateHeaders {
+			header := make([]string, len(columns))
+			for index, column := range columns {
+				header[index] = Transliterate(column)
+			}
+			columns = header
+		}
+		return writer.write(columns)
+	}
+	rowErr := reader.eachRecord(ctx, onHeader, func(_ int, columns []string) error {
+		record := make([]string, width)
+		for columnIndex := range record {
+			if columnIndex < len(columns) {
+				record[columnIndex] = ConvertToISO8601(columns[columnIndex])
+			}
+		}
+		return writer.write(record)
+	})
+	if rowErr != nil {
+		return rowErr
+	}
+	if err := writer.w.Flush(); err != nil {
+		return err
+	}
+	if transcoder != nil {
+		return transcoder.Close()
+	}
+	return nil
+}
+
+// csvWriter writes records with a configurable quote character, which encoding/csv does not support.
+type csvWriter struct {
+	w         *bufio.Writer
+	delimiter rune
+	quote     rune
+	newline   string
+}
+
+// write writes a single record, quoting the fields that need it.
+func (c *csvWriter) write(record []string) error {
+	for index, field := range record {
+		if index > 0 {
+			if _, err := c.w.WriteRune(c.delimiter); err != nil {
+				return err
+			}
+		}
+		if !c.needsQuotes(field) {
+			if _, err := c.w.WriteString(field); err != nil {
+				return err
+			}
+			continue
+		}
+		quote := string(c.quote)
+		if _, err := c.w.WriteString(quote + strings.ReplaceAll(field, quote, quote+quote) + quote); err != nil {
+			return err
+		}
+	}
+	_, err := c.w.WriteString(c.newline)
+	return err
+}
+
+// needsQuotes reports whether a field must be quoted to be read back unchanged.
+func (c *csvWriter) needsQuotes(field string) bool {
+	return strings.ContainsRune(field, c.delimiter) || strings.ContainsRune(field, c.quote) ||
+		strings.ContainsAny(field, "\r\n") || strings.HasPrefix(field, " ")
+}
@@ -0,0 +1,140 @@
+package converters
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	. "GoTools/pkg/helpers"
+	"GoTools/pkg/transforms"
+	"golang.org/x/text/encoding/charmap"
+)
+
+func TestXlsxToCsv(t *testing.T) {
+	filePath := createTestWorkbook(t, map[string][][]any{
+		"People": {{"Name", "Note", "Joined"}, {"Ann", "says \"hi\"; bye", "12-25-20"}, {"Bob", " padded"}},
+		"Orders": {{"Id", "Amount"}, {1, 9.99}},
+	}, "People", "Orders")
+
+	tests := []struct {
+		name string
+		opts CsvOptions
+		want string
+	}{
+		{
+			name: "Default Dialect",
+			opts: CsvOptions{},
+			want: "Name,Note,Joined\n" +
+				"Ann,\"says \"\"hi\"\"; bye\",2020-12-25 00:00:00\n" +
+				"Bob,\" padded\",\n",
+		},
+		{
+			name: "Custom Dialect",
+			opts: CsvOptions{Delimiter: ';', Quote: '\'', Newline: "\r\n", OmitHeader: true},
+			want: "Ann;'says \"hi\"; bye';2020-12-25 00:00:00\r\n" +
+				"Bob;' padded';\r\n",
+		},
+		{
+			name: "Byte Order Mark",
+			opts: CsvOptions{ByteOrderMark: true, Sheets: SheetByName("Orders")},
+			want: "\xEF\xBB\xBFId,Amount\n1,9.99\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output bytes.Buffer
+			if err := XlsxToCsv(context.Background(), &output, filePath, tt.opts); err != nil {
+				t.Fatalf("XlsxToCsv() error = %v", err)
+			}
+			if output.String() != tt.want {
+				t.Errorf("XlsxToCsv() output = %q, want %q", output.String(), tt.want)
+			}
+		})
+	}
+
+	var output bytes.Buffer
+	if err := XlsxToCsv(context.Background(), &output, filePath, CsvOptions{Encoding: "Windows-1252", Sheets: SheetByName("Orders")}); err != nil {
+		t.Fatalf("XlsxToCsv() error = %v", err)
+	}
+	if decoded, _ := charmap.Windows1252.NewDecoder().String(output.String()); decoded != "Id,Amount\n1,9.99\n" {
+		t.Errorf("XlsxToCsv() Windows-1252 output = %q", decoded)
+	}
+	if err := XlsxToCsv(context.Background(), &output, filePath, CsvOptions{Delimiter: '"'}); err == nil {
+		t.Errorf("XlsxToCsv() accepted the quote as delimiter")
+	}
+
+	dir := t.TempDir()
+	written, err := XlsxToCsvFiles(context.Background(), dir, filePath, CsvOptions{Sheets: AllSheets()})
+	if err != nil {
+		t.Fatalf("XlsxToCsvFiles() error = %v", err)
+	}
+	wantFiles := []string{filepath.Join(dir, "People.csv"), filepath.Join(dir, "Orders.csv")}
+	if !reflect.DeepEqual(written, wantFiles) {
+		t.Fatalf("XlsxToCsvFiles() written = %v, want %v", written, wantFiles)
+	}
+	content, readErr := os.ReadFile(written[1])
+	if readErr != nil {
+		t.Fatalf("Error reading CSV file: %v", readErr)
+	}
+	if string(content) != "Id,Amount\n1,9.99\n" {
+		t.Errorf("XlsxToCsvFiles() Orders.csv = %q", content)
+	}
+}
+
+func TestXlsxToCsvTransliteratedHeaders(t *testing.T) {
+	filePath := createTestWorkbook(t, map[string][][]any{
+		"Mesures": {{"Größe", "Prénom"}, {"groß", "Zoé"}},
+	}, "Mesures")
+	var output bytes.Buffer
+	opts := CsvOptions{TransliterateHeaders: true, Encoding: "ISO-8859-1"}
+	if err := XlsxToCsv(context.Background(), &output, filePath, opts); err != nil {
+		t.Fatalf("XlsxToCsv() error = %v", err)
+	}
+	decoded, _ := charmap.ISO8859_1.NewDecoder().String(output.String())
+	if want := "Grosse,Prenom\ngroß,Zoé\n"; decoded != want {
+		t.Errorf("XlsxToCsv() output = %q, want %q", decoded, want)
+	}
+}
+
+func TestXlsxToCsvTransforms(t *testing.T) {
+	filePath := createTestWorkbook(t, map[string][][]any{
+		"Orders": {{"Customer", "Status", "Ordered"}, {"  acme ", "o", "25/12/20"}, {"initech", "c", "NULL"}},
+	}, "Orders")
+	pipeline := transforms.New().
+		Column("Customer", transforms.Trim, transforms.Title).
+		Column("Status", transforms.Upper, transforms.Map(map[string]string{"O": "Open", "C": "Closed"})).
+		Column("Ordered", transforms.Date(DateOptions{Order: DayFirst}))
+	var output bytes.Buffer
+	opts := CsvOptions{ReadOptions: ReadOptions{Transforms: pipeline, NullTokens: []string{"NULL"}, RowFilter: func(record map[string]string) bool { return record["Status"] == "Open" }}}
+	if err := XlsxToCsv(context.Background(), &output, filePath, opts); err != nil {
+		t.Fatalf("XlsxToCsv() error = %v", err)
+	}
+	if want := "Customer,Status,Ordered\nAcme,Open,2020-12-25\n"; output.String() != want {
+		t.Errorf("XlsxToCsv() output = %q, want %q", output.String(), want)
+	}
+
+	opts.Transforms = transforms.New().Column("Shipped", transforms.Trim)
+	if err := XlsxToCsv(context.Background(), &output, filePath, opts); err == nil {
+		t.Error("XlsxToCsv() should fail for a transform of an unknown column")
+	}
+}
+
+func TestXlsxToCsvSplitMerge(t *testing.T) {
+	filePath := createTestWorkbook(t, map[string][][]any{
+		"Contacts": {{"Contact", "Street", "City"}, {"Doe, Jane", "1 Main St", "Springfield"}, {"Roe", "", "Shelbyville"}},
+	}, "Contacts")
+	pipeline := transforms.New().
+		Split("Contact", []string{"Last", "First"}, transforms.SplitOptions{Delimiter: ","}).
+		Merge([]string{"Street", "City"}, "Address", transforms.MergeOptions{Separator: ", "})
+	var output bytes.Buffer
+	opts := CsvOptions{ReadOptions: ReadOptions{Transforms: pipeline}}
+	if err := XlsxToCsv(context.Background(), &output, filePath, opts); err != nil {
+		t.Fatalf("XlsxToCsv() error = %v", err)
+	}
+	if want := "Last,First,Address\nDoe,Jane,\"1 Main St, Springfield\"\nRoe,,Shelbyville\n"; output.String() != want {
+		t.Errorf("XlsxToCsv() output = %q, want %q", output.String(), want)
+	}
+}
@@ -0,0 +1,174 @@
+package converters
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+
+	. "GoTools/pkg/helpers"
+	"github.com/xuri/excelize/v2"
+)
+
+// defaultTableClass is the class of the rendered tables when HtmlOptions.Class is empty.
+const defaultTableClass = "sheet"
+
+// tableCss styles the tables of defaultTableClass; the class name is substituted for other classes.
+const tableCss = `table.sheet { border-collapse: collapse; font-family: Arial, Helvetica, sans-serif; font-size: 10pt; }
+table.sheet caption { font-weight: bold; text-align: left; padding: 4px 0; }
+table.sheet th, table.sheet td { border: 1px solid #BFBFBF; padding: 3px 6px; vertical-align: top; }
+table.sheet th { background-color: #D9D9D9; text-align: left; }
+table.sheet tbody tr:nth-child(even) { background-color: #F2F2F2; }
+table.sheet td.number { text-align: right; }
+table.sheet tfoot td { font-style: italic; border: none; }
+`
+
+// HtmlOptions configures the rendering of worksheets as HTML tables.
+// Sheets selects the worksheets to render, each as a table of its own; Captions writes the worksheet name
+// as the caption of its table. Class sets the class of the tables, "sheet" by default.
+// EmbedCss writes a <style> element styling the tables with borders, a shaded header and striped rows before
+// the first table, and Document wraps the output in a complete HTML document instead of writing a fragment.
+// AlignNumbers marks the cells of the columns holding only numbers with the "number" class, right-aligned by
+// the embedded CSS, inferring the type of every column from its values, which reads every worksheet twice.
+// Dates are converted as in the XML conversion, and line breaks within cells are kept.
+// MaxRows stops a table after that many records and notes how many were left out, no limit when zero.
+// The embedded ReadOptions control how the rows of every worksheet are read.
+type HtmlOptions struct {
+	ReadOptions
+	Sheets       SheetSelector
+	Captions     bool
+	Class        string
+	EmbedCss     bool
+	Document     bool
+	AlignNumbers bool
+	MaxRows      int
+}
+
+// XlsxToHtml renders the worksheets of the .xlsx file at path selected by opts.Sheets as HTML tables
+// written to w. Legacy .xls workbooks and OpenDocument spreadsheets (.ods) are read as well.
+// The conversion stops with the error of ctx as soon as it is cancelled or its deadline passes.
+func XlsxToHtml(ctx context.Context, w io.Writer, path string, opts HtmlOptions) (convertErr error) {
+	if optsErr := opts.ReadOptions.validate(); optsErr != nil {
+		return optsErr
+	}
+	if opts.MaxRows < 0 {
+		return fmt.Errorf("invalid maximum row count %d", opts.MaxRows)
+	}
+	if strings.ContainsAny(opts.Class, " \t\"'<>{}") {
+		return fmt.Errorf("invalid table class '%s'", opts.Class)
+	}
+	if len(opts.Class) < 1 {
+		opts.Class = defaultTableClass
+	}
+	file, openErr := openWorkbook(ctx, path, opts.ReadOptions)
+	if openErr != nil {
+		return openErr
+	}
+	defer func(file *excelize.File) {
+		if err := file.Close(); err != nil && convertErr == nil {
+			convertErr = err
+		}
+	}(file)
+
+	sheets, selectErr := opts.selectSheets(file, opts.Sheets)
+	if selectErr != nil {
+		return selectErr
+	}
+	writer := bufio.NewWriter(w)
+	var head strings.Builder
+	if opts.Document {
+		head.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+		head.WriteString("<title>" + html.EscapeString(strings.Join(sheets, ", ")) + "</title>\n")
+	}
+	if opts.EmbedCss {
+		head.WriteString("<style>\n" + strings.ReplaceAll(tableCss, "table."+defaultTableClass, "table."+opts.Class) + "</style>\n")
+	}
+	if opts.Document {
+		head.WriteString("</head>\n<body>\n")
+	}
+	if _, err := writer.WriteString(head.String()); err != nil {
+		return err
+	}
+	for _, sheet := range sheets {
+		if err := writeHtmlSheet(ctx, writer, file, sheet, opts); err != nil {
+			return fmt.Errorf("worksheet '%s': %w", sheet, err)
+		}
+	}
+	if opts.Document {
+		if _, err := writer.WriteString("</body>\n</html>\n"); err != nil {
+			return err
+		}
+	}
+	return writer.Flush()
+}
+
+// writeHtmlSheet writes the records of the named worksheet to w as an HTML table.
+func writeHtmlSheet(ctx context.Context, w *bufio.Writer, file *excelize.File, sheet string, opts HtmlOptions) error {
+	numeric, numericErr := numericColumns(ctx, file, sheet, opts.ReadOptions, opts.AlignNumbers)
+	if numericErr != nil {
+		return numericErr
+	}
+	reader, readerErr := newSheetReader(file, sheet, opts.ReadOptions)
+	if readerErr != nil {
+		return readerErr
+	}
+	var width int
+	started := false
+	onHeader := func(columns []string, _ []int) error {
+		width, started = len(columns), true
+		var table strings.Builder
+		table.WriteString("<table class=\"" + opts.Class + "\">\n")
+		if opts.Captions {
+			table.WriteString("<caption>" + html.EscapeString(sheet) + "</caption>\n")
+		}
+		table.WriteString("<thead>\n<tr>")
+		for _, column := range columns {
+			table.WriteString("<th>" + htmlCell(column) + "</th>")
+		}
+		table.WriteString("</tr>\n</thead>\n<tbody>\n")
+		_, err := w.WriteString(table.String())
+		return err
+	}
+	written, omitted := 0, 0
+	rowErr := reader.eachRecord(ctx, onHeader, func(_ int, columns []string) error {
+		if opts.MaxRows > 0 && written >= opts.MaxRows {
+			omitted++
+			return nil
+		}
+		var row strings.Builder
+		row.WriteString("<tr>")
+		for index := 0; index < width; index++ {
+			if index < len(numeric) && numeric[index] {
+				row.WriteString("<td class=\"number\">")
+			} else {
+				row.WriteString("<td>")
+			}
+			row.WriteString(htmlCell(ConvertToISO8601(cellAt(columns, index))) + "</td>")
+		}
+		written++
+		_, err := w.WriteString(row.String() + "</tr>\n")
+		return err
+	})
+	if rowErr != nil {
+		return rowErr
+	}
+	if !started {
+		if err := onHeader(nil, nil); err != nil {
+			return err
+		}
+	}
+	footer := "</tbody>\n"
+	if omitted > 0 {
+		footer += fmt.Sprintf("<tfoot>\n<tr><td colspan=\"%d\">%d more rows not shown.</td></tr>\n</tfoot>\n", max(width, 1), omitted)
+	}
+	_, err := w.WriteString(footer + "</table>\n")
+	return err
+}
+
+// htmlCell returns value escaped for an HTML table cell, with line breaks written as <br>.
+func htmlCell(value string) string {
+	value = strings.ReplaceAll(strings.ReplaceAll(value, "\r\n", "\n"), "\r", "\n")
+	return strings.ReplaceAll(html.EscapeString(value), "\n", "<br>")
+}
@@ -0,0 +1,63 @@
+package converters
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestXlsxToHtml(t *testing.T) {
+	filePath := createTestWorkbook(t, map[string][][]any{
+		"Orders": {{"Item", "Qty"}, {"<b>&</b>", 2}, {"two\nlines", 10.5}},
+	}, "Orders")
+
+	tests := []struct {
+		name     string
+		opts     HtmlOptions
+		want     string
+		contains []string
+	}{
+		{
+			name: "Fragment",
+			opts: HtmlOptions{},
+			want: "<table class=\"sheet\">\n<thead>\n<tr><th>Item</th><th>Qty</th></tr>\n</thead>\n<tbody>\n" +
+				"<tr><td>&lt;b&gt;&amp;&lt;/b&gt;</td><td>2</td></tr>\n<tr><td>two<br>lines</td><td>10.5</td></tr>\n" +
+				"</tbody>\n</table>\n",
+		},
+		{
+			name: "Aligned",
+			opts: HtmlOptions{Class: "report", Captions: true, AlignNumbers: true, MaxRows: 1},
+			want: "<table class=\"report\">\n<caption>Orders</caption>\n<thead>\n<tr><th>Item</th><th>Qty</th></tr>\n</thead>\n<tbody>\n" +
+				"<tr><td>&lt;b&gt;&amp;&lt;/b&gt;</td><td class=\"number\">2</td></tr>\n</tbody>\n" +
+				"<tfoot>\n<tr><td colspan=\"2\">1 more rows not shown.</td></tr>\n</tfoot>\n</table>\n",
+		},
+		{
+			name: "Document",
+			opts: HtmlOptions{Class: "report", EmbedCss: true, Document: true},
+			contains: []string{"<!DOCTYPE html>\n", "<title>Orders</title>", "<style>\ntable.report {",
+				"table.report td.number", "</style>\n</head>\n<body>\n<table class=\"report\">", "</table>\n</body>\n</html>\n"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output bytes.Buffer
+			if err := XlsxToHtml(context.Background(), &output, filePath, tt.opts); err != nil {
+				t.Fatalf("XlsxToHtml() error = %v", err)
+			}
+			if len(tt.want) > 0 && output.String() != tt.want {
+				t.Errorf("XlsxToHtml() output = \n%s\nwant:\n%s", output.String(), tt.want)
+			}
+			for _, part := range tt.contains {
+				if !strings.Contains(output.String(), part) {
+					t.Errorf("XlsxToHtml() output = \n%s\nmissing %q", output.String(), part)
+				}
+			}
+		})
+	}
+
+	var output bytes.Buffer
+	if err := XlsxToHtml(context.Background(), &output, filePath, HtmlOptions{Class: "a\"b"}); err == nil {
+		t.Errorf("XlsxToHtml() accepted an invalid class")
+	}
+}
@@ -0,0 +1,251 @@
+package converters
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"GoTools/pkg/datatable"
+	. "GoTools/pkg/helpers"
+	"github.com/xuri/excelize/v2"
+)
+
+// JsonLayout selects how the records of a worksheet are laid out in JSON.
+type JsonLayout int
+
+const (
+	// RecordsLayout writes an array holding an object per record, keyed by column name. It is the default.
+	RecordsLayout JsonLayout = iota
+	// ColumnsLayout writes an object keyed by column name holding the values of the column in an array.
+	// The whole worksheet is held in memory until it has been read.
+	ColumnsLayout
+)
+
+// ParseJsonLayout returns the JsonLayout called records or columns.
+func ParseJsonLayout(name string) (JsonLayout, error) {
+	switch name {
+	case "", "records":
+		return RecordsLayout, nil
+	case "columns":
+		return ColumnsLayout, nil
+	}
+	return RecordsLayout, fmt.Errorf("unknown JSON layout '%s'", name)
+}
+
+// JsonOptions configures the conversion of worksheets into JSON.
+// Sheets selects the worksheets to convert, and Combine writes them into a single object keyed by worksheet name;
+// selecting more than one worksheet requires it.
+// Layout selects between an array of records and an object of columns, see JsonLayout.
+// The keys are the names in the header cells, with duplicates renamed as in the XML conversion.
+// InferTypes writes numbers and booleans as JSON numbers and booleans, dates in their canonical form and empty
+// cells as null; every value is a string otherwise.
+// Lines writes newline delimited JSON instead, one record object per line without any enclosing array,
+// which suits very large worksheets; it cannot be combined with ColumnsLayout or Combine.
+// Records are written as they are read except in ColumnsLayout.
+// The embedded ReadOptions control how the rows of every worksheet are read.
+type JsonOptions struct {
+	ReadOptions
+	Sheets     SheetSelector
+	Combine    bool
+	Layout     JsonLayout
+	InferTypes bool
+	Lines      bool
+}
+
+// validate reports options that cannot produce a valid conversion.
+func (o JsonOptions) validate() error {
+	if err := o.ReadOptions.validate(); err != nil {
+		return err
+	}
+	if o.Lines && o.Layout == ColumnsLayout {
+		return errors.New("newline delimited JSON can only hold records")
+	}
+	if o.Lines && o.Combine {
+		return errors.New("newline delimited JSON cannot combine worksheets")
+	}
+	return nil
+}
+
+// XlsxToJson converts the worksheets of the .xlsx file at path selected by opts.Sheets and writes the JSON to w.
+// Legacy .xls workbooks and OpenDocument spreadsheets (.ods) are read as well.
+// The conversion stops with the error of ctx as soon as it is cancelled or its deadline passes.
+func XlsxToJson(ctx context.Context, w io.Writer, path string, opts JsonOptions) (convertErr error) {
+	if optsErr := opts.validate(); optsErr != nil {
+		return optsErr
+	}
+	file, openErr := openWorkbook(ctx, path, opts.ReadOptions)
+	if openErr != nil {
+		return openErr
+	}
+	defer func(file *excelize.File) {
+		if err := file.Close(); err != nil && convertErr == nil {
+			convertErr = err
+		}
+	}(file)
+
+	sheets, selectErr := opts.selectSheets(file, opts.Sheets)
+	if selectErr != nil {
+		return selectErr
+	}
+	if len(sheets) > 1 && !opts.Combine {
+		return errors.New("multiple worksheets selected, combine them into a single object")
+	}
+	if !opts.Combine {
+		if err := writeJsonSheet(ctx, w, file, sheets[0], opts, ""); err != nil || opts.Lines {
+			return err
+		}
+		_, err := io.WriteString(w, "\n")
+		return err
+	}
+	if _, err := io.WriteString(w, "{"); err != nil {
+		return err
+	}
+	for index, sheet := range sheets {
+		separator := "\n" + defaultIndent
+		if index > 0 {
+			separator = "," + separator
+		}
+		if _, err := io.WriteString(w, separator+jsonString(sheet)+": "); err != nil {
+			return err
+		}
+		if err := writeJsonSheet(ctx, w, file, sheet, opts, defaultIndent); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(w, "\n}\n")
+	return err
+}
+
+// writeJsonSheet writes the records of the named worksheet to w in the configured layout,
+// indenting the lines after the first one by indent. No line break follows the output, except after every
+// record of newline delimited JSON.
+func writeJsonSheet(ctx context.Context, w io.Writer, file *excelize.File, sheet string, opts JsonOptions, indent string) error {
+	reader, readerErr := newSheetReader(file, sheet, opts.ReadOptions)
+	if readerErr != nil {
+		return readerErr
+	}
+	var header []string
+	onHeader := func(columns []string, _ []int) error {
+		header = RenameDuplicates(columns, false)
+		return nil
+	}
+	if opts.Layout == ColumnsLayout {
+		var values [][]json.RawMessage
+		rowErr := reader.eachRecord(ctx, onHeader, func(_ int, columns []string) error {
+			if values == nil {
+				values = make([][]json.RawMessage, len(header))
+			}
+			for columnIndex := range header {
+				values[columnIndex] = append(values[columnIndex], opts.jsonCell(columns, columnIndex))
+			}
+			return nil
+		})
+		if rowErr != nil {
+			return rowErr
+		}
+		return writeJsonColumns(w, header, values, indent)
+	}
+
+	written := 0
+	rowErr := reader.eachRecord(ctx, onHeader, func(_ int, columns []string) error {
+		var record bytes.Buffer
+		switch {
+		case opts.Lines:
+		case written > 0:
+			record.WriteString(",\n" + indent + defaultIndent)
+		default:
+			record.WriteString("[\n" + indent + defaultIndent)
+		}
+		record.WriteString("{")
+		for columnIndex, name := range header {
+			if columnIndex > 0 {
+				record.WriteString(",")
+			}
+			record.WriteString(jsonString(name) + ":")
+			record.Write(opts.jsonCell(columns, columnIndex))
+		}
+		record.WriteString("}")
+		if opts.Lines {
+			record.WriteString("\n")
+		}
+		written++
+		_, err := w.Write(record.Bytes())
+		return err
+	})
+	if rowErr != nil || opts.Lines {
+		return rowErr
+	}
+	end := "\n" + indent + "]"
+	if written == 0 {
+		end = "[]"
+	}
+	_, err := io.WriteString(w, end)
+	return err
+}
+
+// writeJsonColumns writes an object holding the values of each header column to w.
+func writeJsonColumns(w io.Writer, header []string, values [][]json.RawMessage, indent string) error {
+	if len(header) == 0 {
+		_, err := io.WriteString(w, "{}")
+		return err
+	}
+	var object bytes.Buffer
+	object.WriteString("{")
+	for columnIndex, name := range header {
+		if columnIndex > 0 {
+			object.WriteString(",")
+		}
+		object.WriteString("\n" + indent + defaultIndent + jsonString(name) + ": [")
+		if values != nil {
+			for valueIndex, value := range values[columnIndex] {
+				if valueIndex > 0 {
+					object.WriteString(",")
+				}
+				object.Write(value)
+			}
+		}
+		object.WriteString("]")
+	}
+	object.WriteString("\n" + indent + "}")
+	_, err := w.Write(object.Bytes())
+	return err
+}
+
+// jsonCell returns the JSON value of the cell at columnIndex of a record, dates being converted as in the
+// XML conversion. Missing trailing cells are empty.
+func (o JsonOptions) jsonCell(columns []string, columnIndex int) json.RawMessage {
+	var value string
+	if columnIndex < len(columns) {
+		value = ConvertToISO8601(columns[columnIndex])
+	}
+	if !o.InferTypes {
+		return json.RawMessage(jsonString(value))
+	}
+	if len(value) < 1 {
+		return json.RawMessage("null")
+	}
+	cellType := datatable.InferCellType(value)
+	canonical, _ := cellType.Canonical(value)
+	switch cellType {
+	case IntegerCell, DecimalCell, DoubleCell:
+		return json.RawMessage(canonical)
+	case BooleanCell:
+		return json.RawMessage(strings.ToLower(value))
+	case DateCell, DateTimeCell:
+		return json.RawMessage(jsonString(canonical))
+	}
+	return json.RawMessage(jsonString(value))
+}
+
+// jsonString returns the JSON string literal of value, leaving HTML characters unescaped.
+func jsonString(value string) string {
+	var literal bytes.Buffer
+	encoder := json.NewEncoder(&literal)
+	encoder.SetEscapeHTML(false)
+	_ = encoder.Encode(value)
+	return strings.TrimSuffix(literal.String(), "\n")
+}
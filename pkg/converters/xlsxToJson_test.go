@@ -0,0 +1,105 @@
+package converters
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	. "GoTools/pkg/helpers"
+)
+
+func TestXlsxToJson(t *testing.T) {
+	filePath := createTestWorkbook(t, map[string][][]any{
+		"People": {{"Name", "Age", "Name", "Active"}, {"Ann <A>", 31, "A", "TRUE"}, {"Bob", "", "007"}},
+		"Orders": {{"Id", "Amount"}},
+	}, "People", "Orders")
+
+	tests := []struct {
+		name string
+		opts JsonOptions
+		want string
+	}{
+		{
+			name: "Records",
+			opts: JsonOptions{},
+			want: "[\n" +
+				"  {\"Name\":\"Ann <A>\",\"Age\":\"31\",\"Name_2\":\"A\",\"Active\":\"TRUE\"},\n" +
+				"  {\"Name\":\"Bob\",\"Age\":\"\",\"Name_2\":\"007\",\"Active\":\"\"}\n" +
+				"]\n",
+		},
+		{
+			name: "Typed Records",
+			opts: JsonOptions{InferTypes: true},
+			want: "[\n" +
+				"  {\"Name\":\"Ann <A>\",\"Age\":31,\"Name_2\":\"A\",\"Active\":true},\n" +
+				"  {\"Name\":\"Bob\",\"Age\":null,\"Name_2\":\"007\",\"Active\":null}\n" +
+				"]\n",
+		},
+		{
+			name: "Columns",
+			opts: JsonOptions{Layout: ColumnsLayout, InferTypes: true},
+			want: "{\n" +
+				"  \"Name\": [\"Ann <A>\",\"Bob\"],\n" +
+				"  \"Age\": [31,null],\n" +
+				"  \"Name_2\": [\"A\",\"007\"],\n" +
+				"  \"Active\": [true,null]\n" +
+				"}\n",
+		},
+		{
+			name: "Lines",
+			opts: JsonOptions{Lines: true, InferTypes: true},
+			want: "{\"Name\":\"Ann <A>\",\"Age\":31,\"Name_2\":\"A\",\"Active\":true}\n" +
+				"{\"Name\":\"Bob\",\"Age\":null,\"Name_2\":\"007\",\"Active\":null}\n",
+		},
+		{
+			name: "Combined Sheets",
+			opts: JsonOptions{Sheets: AllSheets(), Combine: true, ReadOptions: ReadOptions{Columns: ColumnFilter{Include: []string{"Name", "Id"}}}},
+			want: "{\n" +
+				"  \"People\": [\n" +
+				"    {\"Name\":\"Ann <A>\",\"Name_2\":\"A\"},\n" +
+				"    {\"Name\":\"Bob\",\"Name_2\":\"007\"}\n" +
+				"  ],\n" +
+				"  \"Orders\": []\n" +
+				"}\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output bytes.Buffer
+			if err := XlsxToJson(context.Background(), &output, filePath, tt.opts); err != nil {
+				t.Fatalf("XlsxToJson() error = %v", err)
+			}
+			if output.String() != tt.want {
+				t.Errorf("XlsxToJson() output = \n%s\nwant:\n%s", output.String(), tt.want)
+			}
+			if !tt.opts.Lines && !json.Valid(output.Bytes()) {
+				t.Errorf("XlsxToJson() output is not valid JSON")
+			}
+		})
+	}
+
+	var output bytes.Buffer
+	if err := XlsxToJson(context.Background(), &output, filePath, JsonOptions{Lines: true, Layout: ColumnsLayout}); err == nil {
+		t.Errorf("XlsxToJson() accepted newline delimited columns")
+	}
+	if err := XlsxToJson(context.Background(), &output, filePath, JsonOptions{Sheets: AllSheets()}); err == nil {
+		t.Errorf("XlsxToJson() accepted several worksheets without combining them")
+	}
+}
+
+func TestXlsxToJsonNullTokens(t *testing.T) {
+	filePath := createTestWorkbook(t, map[string][][]any{
+		"Stock": {{"Item", "Qty", "N/A"}, {"Bolt", "N/A", "x"}, {"#N/A", " - ", "-3"}},
+	}, "Stock")
+
+	var output bytes.Buffer
+	opts := JsonOptions{ReadOptions: ReadOptions{NullTokens: DefaultNullTokens}, InferTypes: true, Lines: true}
+	if err := XlsxToJson(context.Background(), &output, filePath, opts); err != nil {
+		t.Fatalf("XlsxToJson() error = %v", err)
+	}
+	want := `{"Item":"Bolt","Qty":null,"N/A":"x"}` + "\n" + `{"Item":null,"Qty":null,"N/A":-3}` + "\n"
+	if output.String() != want {
+		t.Errorf("XlsxToJson() = %s, want %s", output.String(), want)
+	}
+}
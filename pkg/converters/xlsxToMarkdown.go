@@ -0,0 +1,157 @@
+package converters
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	. "GoTools/pkg/helpers"
+	"github.com/xuri/excelize/v2"
+)
+
+// MarkdownOptions configures the rendering of worksheets as GitHub-flavored Markdown tables.
+// Sheets selects the worksheets to render, each as a table of its own, separated by a blank line; SheetHeadings
+// writes a heading holding the worksheet name above each table.
+// AlignNumbers right-aligns the columns holding only numbers, inferring the type of every column from its values,
+// which reads every worksheet twice. Dates are converted as in the XML conversion.
+// MaxRows stops a table after that many records and notes how many were left out, no limit when zero.
+// The embedded ReadOptions control how the rows of every worksheet are read.
+type MarkdownOptions struct {
+	ReadOptions
+	Sheets        SheetSelector
+	SheetHeadings bool
+	AlignNumbers  bool
+	MaxRows       int
+}
+
+// XlsxToMarkdown renders the worksheets of the .xlsx file at path selected by opts.Sheets as Markdown tables
+// written to w. Legacy .xls workbooks and OpenDocument spreadsheets (.ods) are read as well.
+// The conversion stops with the error of ctx as soon as it is cancelled or its deadline passes.
+func XlsxToMarkdown(ctx context.Context, w io.Writer, path string, opts MarkdownOptions) (convertErr error) {
+	if optsErr := opts.ReadOptions.validate(); optsErr != nil {
+		return optsErr
+	}
+	if opts.MaxRows < 0 {
+		return fmt.Errorf("invalid maximum row count %d", opts.MaxRows)
+	}
+	file, openErr := openWorkbook(ctx, path, opts.ReadOptions)
+	if openErr != nil {
+		return openErr
+	}
+	defer func(file *excelize.File) {
+		if err := file.Close(); err != nil && convertErr == nil {
+			convertErr = err
+		}
+	}(file)
+
+	sheets, selectErr := opts.selectSheets(file, opts.Sheets)
+	if selectErr != nil {
+		return selectErr
+	}
+	writer := bufio.NewWriter(w)
+	for index, sheet := range sheets {
+		if index > 0 {
+			if _, err := writer.WriteString("\n"); err != nil {
+				return err
+			}
+		}
+		if opts.SheetHeadings {
+			if _, err := writer.WriteString("## " + markdownCell(sheet) + "\n\n"); err != nil {
+				return err
+			}
+		}
+		if err := writeMarkdownSheet(ctx, writer, file, sheet, opts); err != nil {
+			return fmt.Errorf("worksheet '%s': %w", sheet, err)
+		}
+	}
+	return writer.Flush()
+}
+
+// writeMarkdownSheet writes the records of the named worksheet to w as a Markdown table.
+func writeMarkdownSheet(ctx context.Context, w *bufio.Writer, file *excelize.File, sheet string, opts MarkdownOptions) error {
+	numeric, numericErr := numericColumns(ctx, file, sheet, opts.ReadOptions, opts.AlignNumbers)
+	if numericErr != nil {
+		return numericErr
+	}
+	reader, readerErr := newSheetReader(file, sheet, opts.ReadOptions)
+	if readerErr != nil {
+		return readerErr
+	}
+	var width int
+	started := false
+	onHeader := func(columns []string, _ []int) error {
+		width, started = len(columns), true
+		var table strings.Builder
+		table.WriteString("|")
+		for _, column := range columns {
+			table.WriteString(" " + markdownCell(column) + " |")
+		}
+		table.WriteString("\n|")
+		for index := range columns {
+			if index < len(numeric) && numeric[index] {
+				table.WriteString(" ---: |")
+			} else {
+				table.WriteString(" --- |")
+			}
+		}
+		_, err := w.WriteString(table.String() + "\n")
+		return err
+	}
+	written, omitted := 0, 0
+	rowErr := reader.eachRecord(ctx, onHeader, func(_ int, columns []string) error {
+		if opts.MaxRows > 0 && written >= opts.MaxRows {
+			omitted++
+			return nil
+		}
+		var row strings.Builder
+		row.WriteString("|")
+		for index := 0; index < width; index++ {
+			row.WriteString(" " + markdownCell(ConvertToISO8601(cellAt(columns, index))) + " |")
+		}
+		written++
+		_, err := w.WriteString(row.String() + "\n")
+		return err
+	})
+	if rowErr != nil {
+		return rowErr
+	}
+	if !started {
+		// A Markdown table cannot be written without a header row.
+		_, err := w.WriteString("_Empty worksheet._\n")
+		return err
+	}
+	if omitted > 0 {
+		_, err := fmt.Fprintf(w, "\n_%d more rows not shown._\n", omitted)
+		return err
+	}
+	return nil
+}
+
+// numericColumns reports for every header column of the named worksheet whether it only holds numbers,
+// without reading the records unless infer is set.
+func numericColumns(ctx context.Context, file *excelize.File, sheet string, opts ReadOptions, infer bool) ([]bool, error) {
+	if !infer {
+		return nil, nil
+	}
+	_, types, err := sheetColumnTypes(ctx, file, sheet, opts, true, InferenceOptions{})
+	if err != nil {
+		return nil, err
+	}
+	numeric := make([]bool, len(types))
+	for index, cellType := range types {
+		numeric[index] = cellType == IntegerCell || cellType == DecimalCell || cellType == DoubleCell
+	}
+	return numeric, nil
+}
+
+// markdownReplacer escapes the characters that would end a table cell or start inline markup,
+// writing line breaks as <br> since a table row cannot span lines.
+var markdownReplacer = strings.NewReplacer(`\`, `\\`, "|", `\|`, "*", `\*`, "_", `\_`, "`", "\\`",
+	"<", "&lt;", "\r\n", "<br>", "\n", "<br>", "\r", "<br>")
+
+// markdownCell returns value escaped for a Markdown table cell.
+func markdownCell(value string) string {
+	return markdownReplacer.Replace(strings.TrimSpace(value))
+}
@@ -0,0 +1,49 @@
+package converters
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func TestXlsxToMarkdown(t *testing.T) {
+	filePath := createTestWorkbook(t, map[string][][]any{
+		"Orders": {{"Item", "Qty", "Note"}, {"a|b", 2, "*bold*"}, {"c", 10.5, "line\nbreak"}, {"d", 1}},
+		"Empty":  {},
+	}, "Orders", "Empty")
+
+	tests := []struct {
+		name string
+		opts MarkdownOptions
+		want string
+	}{
+		{
+			name: "Table",
+			opts: MarkdownOptions{},
+			want: "| Item | Qty | Note |\n| --- | --- | --- |\n" +
+				"| a\\|b | 2 | \\*bold\\* |\n| c | 10.5 | line<br>break |\n| d | 1 |  |\n",
+		},
+		{
+			name: "Aligned",
+			opts: MarkdownOptions{AlignNumbers: true, MaxRows: 1},
+			want: "| Item | Qty | Note |\n| --- | ---: | --- |\n| a\\|b | 2 | \\*bold\\* |\n\n_2 more rows not shown._\n",
+		},
+		{
+			name: "Headings",
+			opts: MarkdownOptions{Sheets: AllSheets(), SheetHeadings: true, MaxRows: 1},
+			want: "## Orders\n\n| Item | Qty | Note |\n| --- | --- | --- |\n| a\\|b | 2 | \\*bold\\* |\n\n_2 more rows not shown._\n" +
+				"\n## Empty\n\n_Empty worksheet._\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output bytes.Buffer
+			if err := XlsxToMarkdown(context.Background(), &output, filePath, tt.opts); err != nil {
+				t.Fatalf("XlsxToMarkdown() error = %v", err)
+			}
+			if output.String() != tt.want {
+				t.Errorf("XlsxToMarkdown() output = \n%s\nwant:\n%s", output.String(), tt.want)
+			}
+		})
+	}
+}
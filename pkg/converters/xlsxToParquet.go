@@ -0,0 +1,293 @@
+package converters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	. "GoTools/pkg/helpers"
+	"github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/compress"
+	"github.com/xuri/excelize/v2"
+)
+
+// ParquetCodec selects the compression codec of the column chunks of a Parquet file.
+type ParquetCodec int
+
+const (
+	// SnappyCodec compresses with Snappy, fast and supported by every Parquet reader. It is the default.
+	SnappyCodec ParquetCodec = iota
+	// UncompressedCodec leaves the column chunks uncompressed.
+	UncompressedCodec
+	// GzipCodec compresses with gzip.
+	GzipCodec
+	// ZstdCodec compresses with Zstandard, which compresses better than Snappy at a similar speed.
+	ZstdCodec
+	// Lz4Codec compresses with LZ4 in its raw format.
+	Lz4Codec
+	// BrotliCodec compresses with Brotli.
+	BrotliCodec
+)
+
+// ParseParquetCodec returns the ParquetCodec called snappy, none, gzip, zstd, lz4 or brotli.
+func ParseParquetCodec(name string) (ParquetCodec, error) {
+	switch name {
+	case "", "snappy":
+		return SnappyCodec, nil
+	case "none", "uncompressed":
+		return UncompressedCodec, nil
+	case "gzip":
+		return GzipCodec, nil
+	case "zstd":
+		return ZstdCodec, nil
+	case "lz4":
+		return Lz4Codec, nil
+	case "brotli":
+		return BrotliCodec, nil
+	}
+	return SnappyCodec, fmt.Errorf("unknown Parquet compression codec '%s'", name)
+}
+
+// codec returns the compression codec implementing the ParquetCodec.
+func (c ParquetCodec) codec() compress.Codec {
+	switch c {
+	case UncompressedCodec:
+		return &parquet.Uncompressed
+	case GzipCodec:
+		return &parquet.Gzip
+	case ZstdCodec:
+		return &parquet.Zstd
+	case Lz4Codec:
+		return &parquet.Lz4Raw
+	case BrotliCodec:
+		return &parquet.Brotli
+	}
+	return &parquet.Snappy
+}
+
+// ParquetOptions configures the conversion of worksheets into Parquet files.
+// Sheets selects the worksheets to convert; XlsxToParquet only converts a single one.
+// Every header column becomes an optional column of the file, empty cells being null. The columns are stored
+// ordered by name, which is how Parquet groups built at runtime order their fields.
+// InferTypes infers the type of every column from its values, reading every worksheet twice, or a sample of it as
+// configured by Inference: integers are stored
+// as INT64, decimals and doubles as DOUBLE, booleans as BOOLEAN, dates as DATE and dates and times as local
+// millisecond TIMESTAMPs; the other columns are stored as UTF-8 strings. ColumnTypes forces the type of the named
+// columns, keyed on the header name with duplicates renamed as in the XML conversion; a value that is not valid
+// for the type of its column fails the conversion. Dates are converted as in the XML conversion.
+// Compression selects the codec compressing the column chunks, see ParquetCodec.
+// The embedded ReadOptions control how the rows of every worksheet are read.
+type ParquetOptions struct {
+	ReadOptions
+	Sheets      SheetSelector
+	InferTypes  bool
+	Inference   InferenceOptions
+	ColumnTypes map[string]CellType
+	Compression ParquetCodec
+}
+
+// validate reports options that cannot produce a valid conversion.
+func (o ParquetOptions) validate() error {
+	if err := o.ReadOptions.validate(); err != nil {
+		return err
+	}
+	return o.Inference.Validate()
+}
+
+// XlsxToParquet converts the worksheet of the .xlsx file at path selected by opts.Sheets and writes the Parquet
+// file to w. Legacy .xls workbooks and OpenDocument spreadsheets (.ods) are read as well.
+// Use XlsxToParquetFiles to convert several worksheets.
+// The conversion stops with the error of ctx as soon as it is cancelled or its deadline passes.
+func XlsxToParquet(ctx context.Context, w io.Writer, path string, opts ParquetOptions) (convertErr error) {
+	if optsErr := opts.validate(); optsErr != nil {
+		return optsErr
+	}
+	file, openErr := openWorkbook(ctx, path, opts.ReadOptions)
+	if openErr != nil {
+		return openErr
+	}
+	defer func(file *excelize.File) {
+		if err := file.Close(); err != nil && convertErr == nil {
+			convertErr = err
+		}
+	}(file)
+
+	sheets, selectErr := opts.selectSheets(file, opts.Sheets)
+	if selectErr != nil {
+		return selectErr
+	}
+	if len(sheets) > 1 {
+		return errors.New("multiple worksheets selected, write one file per worksheet")
+	}
+	return writeParquetSheet(ctx, w, file, sheets[0], opts)
+}
+
+// XlsxToParquetFiles converts the worksheets of the .xlsx file at path selected by opts.Sheets
+// and writes one Parquet file per worksheet into dir, named after the worksheet.
+// The conversion stops with the error of ctx as soon as it is cancelled, leaving the files written so far.
+// It returns the paths of the files written.
+func XlsxToParquetFiles(ctx context.Context, dir, path string, opts ParquetOptions) (written []string, convertErr error) {
+	if optsErr := opts.validate(); optsErr != nil {
+		return nil, optsErr
+	}
+	file, openErr := openWorkbook(ctx, path, opts.ReadOptions)
+	if openErr != nil {
+		return nil, openErr
+	}
+	defer func(file *excelize.File) {
+		if err := file.Close(); err != nil && convertErr == nil {
+			convertErr = err
+		}
+	}(file)
+
+	sheets, selectErr := opts.selectSheets(file, opts.Sheets)
+	if selectErr != nil {
+		return nil, selectErr
+	}
+	files := &outputFiles{}
+	for _, sheet := range sheets {
+		writeErr := files.writeFile(filepath.Join(dir, sheet+".parquet"), func(w io.Writer) error {
+			return writeParquetSheet(ctx, w, file, sheet, opts)
+		})
+		if writeErr != nil {
+			return files.written, writeErr
+		}
+	}
+	return files.written, nil
+}
+
+// writeParquetSheet writes the records of the named worksheet to w as a Parquet file.
+func writeParquetSheet(ctx context.Context, w io.Writer, file *excelize.File, sheet string, opts ParquetOptions) error {
+	header, types, headerErr := sheetColumnTypes(ctx, file, sheet, opts.ReadOptions, opts.InferTypes, opts.Inference)
+	if headerErr != nil {
+		return headerErr
+	}
+	table := newParquetTable(sheet, header, types, opts.ColumnTypes)
+	writer := parquet.NewWriter(w, table.schema, parquet.Compression(opts.Compression.codec()))
+	reader, readerErr := newSheetReader(file, sheet, opts.ReadOptions)
+	if readerErr != nil {
+		return readerErr
+	}
+	onHeader := func([]string, []int) error { return nil }
+	rowErr := reader.eachRecord(ctx, onHeader, func(rowNumber int, record []string) error {
+		row, err := table.row(record)
+		if err != nil {
+			return fmt.Errorf("row %d: %w", rowNumber, err)
+		}
+		_, err = writer.WriteRows([]parquet.Row{row})
+		return err
+	})
+	if rowErr != nil {
+		return rowErr
+	}
+	return writer.Close()
+}
+
+// parquetTable holds the schema of the Parquet file of a table together with the type of every header column and
+// the leaf of the schema it is stored in.
+type parquetTable struct {
+	schema *parquet.Schema
+	header []string
+	types  []CellType
+	leaves []int
+}
+
+// newParquetTable returns the schema of the table called name under header, whose columns are of the types in
+// types unless forced names theirs.
+func newParquetTable(name string, header []string, types []CellType, forced map[string]CellType) parquetTable {
+	table := parquetTable{header: header, types: types, leaves: make([]int, len(header))}
+	group := make(parquet.Group, len(header))
+	for index, column := range header {
+		if cellType, ok := forced[column]; ok {
+			types[index] = cellType
+		}
+		group[column] = parquet.Optional(parquetNode(types[index]))
+	}
+	table.schema = parquet.NewSchema(name, group)
+	for leaf, field := range table.schema.Fields() {
+		for index, column := range header {
+			if column == field.Name() {
+				table.leaves[index] = leaf
+			}
+		}
+	}
+	return table
+}
+
+// row returns record as a row of the table, dates being converted as in the XML conversion. It fails on a value
+// that is not valid for the type of its column.
+func (t parquetTable) row(record []string) (parquet.Row, error) {
+	row := make(parquet.Row, len(t.header))
+	for index, cellType := range t.types {
+		var value string
+		if index < len(record) {
+			value = ConvertToISO8601(record[index])
+		}
+		parquetValue, ok := parquetCell(cellType, value)
+		if !ok {
+			return nil, fmt.Errorf("'%s' in column '%s' is not a valid %s", value, t.header[index], parquetNode(cellType).Type())
+		}
+		definition := 1
+		if parquetValue.IsNull() {
+			definition = 0
+		}
+		row[t.leaves[index]] = parquetValue.Level(0, definition, t.leaves[index])
+	}
+	return row, nil
+}
+
+// parquetNode returns the Parquet node values of the cell type are stored as.
+func parquetNode(cellType CellType) parquet.Node {
+	switch cellType {
+	case IntegerCell:
+		return parquet.Int(64)
+	case DecimalCell, DoubleCell:
+		return parquet.Leaf(parquet.DoubleType)
+	case BooleanCell:
+		return parquet.Leaf(parquet.BooleanType)
+	case DateCell:
+		return parquet.Date()
+	case DateTimeCell:
+		return parquet.TimestampAdjusted(parquet.Millisecond, false)
+	}
+	return parquet.String()
+}
+
+// parquetCell returns the Parquet value of a value of the cell type, null for an empty value.
+// It returns false if value is not a valid value of the cell type.
+func parquetCell(cellType CellType, value string) (parquet.Value, bool) {
+	if len(value) < 1 {
+		return parquet.NullValue(), true
+	}
+	canonical, ok := cellType.Canonical(value)
+	if !ok {
+		return parquet.Value{}, false
+	}
+	switch cellType {
+	case IntegerCell:
+		integer, _ := strconv.ParseInt(canonical, 10, 64)
+		return parquet.Int64Value(integer), true
+	case DecimalCell, DoubleCell:
+		double, _ := strconv.ParseFloat(canonical, 64)
+		return parquet.DoubleValue(double), true
+	case BooleanCell:
+		return parquet.BooleanValue(canonical == "true"), true
+	case DateCell:
+		date, _ := time.Parse(time.DateOnly, canonical)
+		return parquet.Int32Value(int32(date.Unix() / 86400)), true
+	case DateTimeCell:
+		dateTime, err := time.Parse("2006-01-02T15:04:05", canonical)
+		if err != nil {
+			// Values with a time zone are stored as the local time they were written in.
+			zoned, _ := time.Parse(time.RFC3339Nano, canonical)
+			dateTime = time.Date(zoned.Year(), zoned.Month(), zoned.Day(), zoned.Hour(), zoned.Minute(),
+				zoned.Second(), zoned.Nanosecond(), time.UTC)
+		}
+		return parquet.Int64Value(dateTime.UnixMilli()), true
+	}
+	return parquet.ByteArrayValue([]byte(canonical)), true
+}
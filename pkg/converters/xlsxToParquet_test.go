@@ -0,0 +1,125 @@
+package converters
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+func TestXlsxToParquet(t *testing.T) {
+	filePath := createTestWorkbook(t, map[string][][]any{
+		"People": {{"Name", "Age", "Score", "Joined", "Active"}, {"Ann", 31, 9.5, "12-25-20", "true"}, {"Bob", "", 7, "", "false"}},
+		"Codes":  {{"Code"}, {"007"}},
+	}, "People", "Codes")
+
+	tests := []struct {
+		name       string
+		opts       ParquetOptions
+		wantSchema map[string]string
+		want       []map[string]any
+	}{
+		{
+			name:       "Strings",
+			opts:       ParquetOptions{Compression: UncompressedCodec},
+			wantSchema: map[string]string{"Name": "BYTE_ARRAY", "Age": "BYTE_ARRAY", "Score": "BYTE_ARRAY", "Joined": "BYTE_ARRAY", "Active": "BYTE_ARRAY"},
+			want: []map[string]any{
+				{"Name": "Ann", "Age": "31", "Score": "9.5", "Joined": "2020-12-25 00:00:00", "Active": "true"},
+				{"Name": "Bob", "Age": nil, "Score": "7", "Joined": nil, "Active": "false"},
+			},
+		},
+		{
+			name:       "Inferred Types",
+			opts:       ParquetOptions{InferTypes: true, Compression: ZstdCodec, ColumnTypes: map[string]CellType{"Score": StringCell}},
+			wantSchema: map[string]string{"Name": "BYTE_ARRAY", "Age": "INT64", "Score": "BYTE_ARRAY", "Joined": "INT64", "Active": "BOOLEAN"},
+			want: []map[string]any{
+				{"Name": "Ann", "Age": int64(31), "Score": "9.5", "Joined": time.Date(2020, 12, 25, 0, 0, 0, 0, time.UTC).UnixMilli(), "Active": true},
+				{"Name": "Bob", "Age": nil, "Score": "7", "Joined": nil, "Active": false},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output bytes.Buffer
+			if err := XlsxToParquet(context.Background(), &output, filePath, tt.opts); err != nil {
+				t.Fatalf("XlsxToParquet() error = %v", err)
+			}
+			schema, rows := readParquet(t, output.Bytes())
+			if !reflect.DeepEqual(schema, tt.wantSchema) {
+				t.Errorf("XlsxToParquet() schema = %v, want %v", schema, tt.wantSchema)
+			}
+			if !reflect.DeepEqual(rows, tt.want) {
+				t.Errorf("XlsxToParquet() rows = %v, want %v", rows, tt.want)
+			}
+		})
+	}
+
+	var output bytes.Buffer
+	if err := XlsxToParquet(context.Background(), &output, filePath, ParquetOptions{ColumnTypes: map[string]CellType{"Name": IntegerCell}}); err == nil {
+		t.Errorf("XlsxToParquet() accepted a name in an integer column")
+	}
+
+	dir := t.TempDir()
+	written, err := XlsxToParquetFiles(context.Background(), dir, filePath, ParquetOptions{Sheets: AllSheets(), InferTypes: true})
+	if err != nil {
+		t.Fatalf("XlsxToParquetFiles() error = %v", err)
+	}
+	if want := []string{filepath.Join(dir, "People.parquet"), filepath.Join(dir, "Codes.parquet")}; !reflect.DeepEqual(written, want) {
+		t.Fatalf("XlsxToParquetFiles() written = %v, want %v", written, want)
+	}
+	content, readErr := os.ReadFile(written[1])
+	if readErr != nil {
+		t.Fatalf("Error reading Parquet file: %v", readErr)
+	}
+	if _, rows := readParquet(t, content); !reflect.DeepEqual(rows, []map[string]any{{"Code": "007"}}) {
+		t.Errorf("XlsxToParquetFiles() Codes rows = %v", rows)
+	}
+}
+
+// readParquet returns the physical type of every column of a Parquet file and its rows keyed by column name.
+func readParquet(t *testing.T, content []byte) (map[string]string, []map[string]any) {
+	t.Helper()
+	file, err := parquet.OpenFile(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		t.Fatalf("Error opening Parquet file: %v", err)
+	}
+	fields := file.Schema().Fields()
+	schema := make(map[string]string, len(fields))
+	for _, field := range fields {
+		schema[field.Name()] = field.Type().Kind().String()
+	}
+	reader := parquet.NewReader(bytes.NewReader(content))
+	defer reader.Close()
+	var rows []map[string]any
+	buffer := make([]parquet.Row, 1)
+	for {
+		count, readErr := reader.ReadRows(buffer)
+		if count < 1 {
+			break
+		}
+		row := make(map[string]any, len(fields))
+		for _, value := range buffer[0] {
+			name := fields[value.Column()].Name()
+			switch {
+			case value.IsNull():
+				row[name] = nil
+			case value.Kind() == parquet.ByteArray:
+				row[name] = string(value.ByteArray())
+			case value.Kind() == parquet.Boolean:
+				row[name] = value.Boolean()
+			default:
+				row[name] = value.Int64()
+			}
+		}
+		rows = append(rows, row)
+		if readErr != nil {
+			break
+		}
+	}
+	return schema, rows
+}
@@ -0,0 +1,365 @@
+package converters
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	. "GoTools/pkg/helpers"
+	"github.com/xuri/excelize/v2"
+)
+
+// SqlStatement selects the statements generated from the records of a worksheet.
+type SqlStatement int
+
+const (
+	// InsertStatement writes INSERT statements. It is the default.
+	InsertStatement SqlStatement = iota
+	// UpsertStatement writes statements inserting new records and updating the records whose key columns
+	// match an existing row: INSERT ... ON CONFLICT for PostgreSQL, INSERT ... ON DUPLICATE KEY UPDATE for MySQL
+	// and MERGE for SQL Server.
+	UpsertStatement
+)
+
+// ParseSqlStatement returns the SqlStatement called insert or upsert.
+func ParseSqlStatement(name string) (SqlStatement, error) {
+	switch name {
+	case "", "insert":
+		return InsertStatement, nil
+	case "upsert", "merge":
+		return UpsertStatement, nil
+	}
+	return InsertStatement, fmt.Errorf("unknown SQL statement '%s'", name)
+}
+
+// SqlOptions configures the generation of an SQL script from a worksheet.
+// Sheets selects the worksheet to convert, and Table names the table, after the worksheet by default; a dot
+// separates the schema from the table name. Dialect selects the SQL dialect, see SqlDialect.
+// Statement selects between plain inserts and upserts, which require KeyColumns naming the columns identifying
+// a record.
+// ColumnNames maps header names to the names of the table columns; when it is set only the mapped columns are
+// written.
+// ColumnTypes forces the type of the named columns and InferTypes infers the type of the others from their values,
+// reading the worksheet twice, or a sample of it as configured by Inference; the other columns are written as
+// strings. Empty cells are written as NULL and a value that is not valid for the type of its column fails the
+// conversion. KeyColumns and ColumnTypes are keyed on the header name, with duplicates renamed as in the XML
+// conversion.
+// BatchSize is the number of records inserted by each statement, one by default; SQL Server accepts up to 1000.
+// CreateTable starts the script with a CREATE TABLE statement declaring the columns with their types and the key
+// columns as the primary key.
+// The embedded ReadOptions control how the rows of the worksheet are read.
+type SqlOptions struct {
+	ReadOptions
+	Sheets      SheetSelector
+	Table       string
+	Dialect     SqlDialect
+	Statement   SqlStatement
+	KeyColumns  []string
+	ColumnNames map[string]string
+	ColumnTypes map[string]CellType
+	InferTypes  bool
+	Inference   InferenceOptions
+	BatchSize   int
+	CreateTable bool
+}
+
+// validate reports options that cannot produce a valid script.
+func (o SqlOptions) validate() error {
+	if err := o.ReadOptions.validate(); err != nil {
+		return err
+	}
+	if err := o.Inference.Validate(); err != nil {
+		return err
+	}
+	if o.BatchSize < 0 {
+		return fmt.Errorf("invalid batch size %d", o.BatchSize)
+	}
+	if o.Dialect == SqlServerDialect && o.BatchSize > maxSqlServerRows {
+		return fmt.Errorf("SQL Server accepts at most %d rows per statement", maxSqlServerRows)
+	}
+	if o.Statement == UpsertStatement && len(o.KeyColumns) < 1 {
+		return errors.New("upserts require key columns")
+	}
+	return nil
+}
+
+// XlsxToSql converts the worksheet of the .xlsx file at path selected by opts.Sheets into an SQL script written
+// to w. Legacy .xls workbooks and OpenDocument spreadsheets (.ods) are read as well.
+// The conversion stops with the error of ctx as soon as it is cancelled or its deadline passes.
+func XlsxToSql(ctx context.Context, w io.Writer, path string, opts SqlOptions) (convertErr error) {
+	if optsErr := opts.validate(); optsErr != nil {
+		return optsErr
+	}
+	file, openErr := openWorkbook(ctx, path, opts.ReadOptions)
+	if openErr != nil {
+		return openErr
+	}
+	defer func(file *excelize.File) {
+		if err := file.Close(); err != nil && convertErr == nil {
+			convertErr = err
+		}
+	}(file)
+
+	sheets, selectErr := opts.selectSheets(file, opts.Sheets)
+	if selectErr != nil {
+		return selectErr
+	}
+	if len(sheets) > 1 {
+		return errors.New("multiple worksheets selected, convert one worksheet per script")
+	}
+	writer := bufio.NewWriter(w)
+	if err := writeSqlSheet(ctx, writer, file, sheets[0], opts); err != nil {
+		return err
+	}
+	return writer.Flush()
+}
+
+// sqlColumn is a header column written into the table.
+type sqlColumn struct {
+	index    int
+	header   string
+	name     string
+	cellType CellType
+	key      bool
+}
+
+// writeSqlSheet writes the statements converting the records of the named worksheet to w.
+func writeSqlSheet(ctx context.Context, w *bufio.Writer, file *excelize.File, sheet string, opts SqlOptions) error {
+	columns, columnsErr := sqlColumns(ctx, file, sheet, opts)
+	if columnsErr != nil {
+		return columnsErr
+	}
+	table := opts.Table
+	if len(table) < 1 {
+		table = sheet
+	}
+	script := sqlScript{w: w, dialect: opts.Dialect, statement: opts.Statement, table: table, columns: columns}
+	if opts.CreateTable {
+		if err := script.createTable(); err != nil {
+			return err
+		}
+	}
+	reader, readerErr := newSheetReader(file, sheet, opts.ReadOptions)
+	if readerErr != nil {
+		return readerErr
+	}
+	batchSize := max(opts.BatchSize, 1)
+	var batch [][]string
+	onHeader := func([]string, []int) error { return nil }
+	rowErr := reader.eachRecord(ctx, onHeader, func(rowNumber int, record []string) error {
+		values, err := script.literals(record)
+		if err != nil {
+			return fmt.Errorf("row %d: %w", rowNumber, err)
+		}
+		if batch = append(batch, values); len(batch) < batchSize {
+			return nil
+		}
+		err = script.write(batch)
+		batch = batch[:0]
+		return err
+	})
+	if rowErr != nil {
+		return rowErr
+	}
+	if len(batch) > 0 {
+		return script.write(batch)
+	}
+	return nil
+}
+
+// sqlColumns returns the columns of the named worksheet written into the table, with their types.
+// The types of the columns without a forced type are inferred from a first read of the worksheet.
+func sqlColumns(ctx context.Context, file *excelize.File, sheet string, opts SqlOptions) ([]sqlColumn, error) {
+	header, inferred, headerErr := sheetColumnTypes(ctx, file, sheet, opts.ReadOptions, opts.InferTypes, opts.Inference)
+	if headerErr != nil {
+		return nil, headerErr
+	}
+	return opts.tableColumns(header, inferred)
+}
+
+// tableColumns returns the columns of header written into the table, of the types in types unless
+// opts.ColumnTypes forces theirs.
+func (o SqlOptions) tableColumns(header []string, types []CellType) ([]sqlColumn, error) {
+	var columns []sqlColumn
+	for index, name := range header {
+		column := sqlColumn{index: index, header: name, name: name, cellType: types[index]}
+		if len(o.ColumnNames) > 0 {
+			mapped, selected := o.ColumnNames[name]
+			if !selected {
+				continue
+			}
+			column.name = mapped
+		}
+		if forced, ok := o.ColumnTypes[name]; ok {
+			column.cellType = forced
+		}
+		column.key = slices.Contains(o.KeyColumns, name)
+		columns = append(columns, column)
+	}
+	for _, key := range o.KeyColumns {
+		if !slices.ContainsFunc(columns, func(column sqlColumn) bool { return column.header == key }) {
+			return nil, fmt.Errorf("key column '%s' is not written into the table", key)
+		}
+	}
+	return columns, nil
+}
+
+// sqlScript writes the statements of a table.
+type sqlScript struct {
+	w         *bufio.Writer
+	dialect   SqlDialect
+	statement SqlStatement
+	table     string
+	columns   []sqlColumn
+}
+
+// literal returns the literal of value in a column of the cell type, NULL for an empty value.
+// It returns false if value is not a valid value of the cell type.
+func (s sqlScript) literal(cellType CellType, value string) (string, bool) {
+	if len(value) < 1 {
+		return "NULL", true
+	}
+	canonical, ok := cellType.Canonical(value)
+	if !ok {
+		return "", false
+	}
+	return s.dialect.literal(cellType, canonical), true
+}
+
+// literals returns the literals of the columns of record written into the table, dates being converted as in the
+// XML conversion. It fails on a value that is not valid for the type of its column.
+func (s sqlScript) literals(record []string) ([]string, error) {
+	values := make([]string, len(s.columns))
+	for index, column := range s.columns {
+		var value string
+		if column.index < len(record) {
+			value = ConvertToISO8601(record[column.index])
+		}
+		literal, ok := s.literal(column.cellType, value)
+		if !ok {
+			return nil, fmt.Errorf("'%s' in column '%s' is not a valid %s", value, column.header,
+				s.dialect.columnType(column.cellType, false))
+		}
+		values[index] = literal
+	}
+	return values, nil
+}
+
+// columnList returns the quoted names of the columns, each prefixed by prefix, separated by commas.
+func (s sqlScript) columnList(prefix string) string {
+	names := make([]string, len(s.columns))
+	for index, column := range s.columns {
+		names[index] = prefix + s.dialect.quoteColumn(column.name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// assignments returns the assignments of the non-key columns to the value named by value, separated by commas.
+func (s sqlScript) assignments(value func(column string) string) string {
+	var assignments []string
+	for _, column := range s.columns {
+		if !column.key {
+			quoted := s.dialect.quoteColumn(column.name)
+			assignments = append(assignments, quoted+" = "+value(quoted))
+		}
+	}
+	return strings.Join(assignments, ", ")
+}
+
+// updatesColumns reports whether an upsert updates any column, that is whether any column is not a key column.
+func (s sqlScript) updatesColumns() bool {
+	return slices.ContainsFunc(s.columns, func(column sqlColumn) bool { return !column.key })
+}
+
+// createTable writes the CREATE TABLE statement of the table.
+func (s sqlScript) createTable() error {
+	var statement strings.Builder
+	statement.WriteString("CREATE TABLE " + s.dialect.quoteIdentifier(s.table) + " (")
+	var keys []string
+	for index, column := range s.columns {
+		if index > 0 {
+			statement.WriteString(",")
+		}
+		statement.WriteString("\n  " + s.dialect.quoteColumn(column.name) + " " + s.dialect.columnType(column.cellType, column.key))
+		if column.key {
+			keys = append(keys, s.dialect.quoteColumn(column.name))
+		}
+	}
+	if len(keys) > 0 {
+		statement.WriteString(",\n  PRIMARY KEY (" + strings.Join(keys, ", ") + ")")
+	}
+	statement.WriteString("\n);\n\n")
+	_, err := s.w.WriteString(statement.String())
+	return err
+}
+
+// write writes the statement inserting or upserting a batch of records, given as the literals of their columns.
+func (s sqlScript) write(batch [][]string) error {
+	var rows strings.Builder
+	for index, values := range batch {
+		if index > 0 {
+			rows.WriteString(",")
+		}
+		if len(batch) > 1 {
+			rows.WriteString("\n  ")
+		}
+		rows.WriteString("(" + strings.Join(values, ", ") + ")")
+	}
+	table, columns := s.dialect.quoteIdentifier(s.table), s.columnList("")
+	values := " VALUES " + rows.String()
+	if len(batch) > 1 {
+		values = " VALUES" + rows.String()
+	}
+	updates := s.updatesColumns()
+	var statement string
+	switch {
+	case s.statement == InsertStatement:
+		statement = "INSERT INTO " + table + " (" + columns + ")" + values
+	case s.dialect == MySqlDialect && !updates:
+		statement = "INSERT IGNORE INTO " + table + " (" + columns + ")" + values
+	case s.dialect == MySqlDialect:
+		statement = "INSERT INTO " + table + " (" + columns + ")" + values + "\nON DUPLICATE KEY UPDATE " +
+			s.assignments(func(column string) string { return "VALUES(" + column + ")" })
+	case s.dialect == SqlServerDialect:
+		statement = s.merge(table, columns, rows.String())
+	default:
+		var keys []string
+		for _, column := range s.columns {
+			if column.key {
+				keys = append(keys, s.dialect.quoteColumn(column.name))
+			}
+		}
+		action := "DO NOTHING"
+		if updates {
+			action = "DO UPDATE SET " + s.assignments(func(column string) string { return "EXCLUDED." + column })
+		}
+		statement = "INSERT INTO " + table + " (" + columns + ")" + values +
+			"\nON CONFLICT (" + strings.Join(keys, ", ") + ") " + action
+	}
+	_, err := s.w.WriteString(statement + ";\n")
+	return err
+}
+
+// merge returns the SQL Server MERGE statement upserting the given rows into table.
+func (s sqlScript) merge(table, columns, rows string) string {
+	var conditions []string
+	for _, column := range s.columns {
+		if column.key {
+			quoted := s.dialect.quoteColumn(column.name)
+			conditions = append(conditions, "target."+quoted+" = source."+quoted)
+		}
+	}
+	if !strings.HasPrefix(rows, "\n") {
+		rows = " " + rows
+	}
+	statement := "MERGE INTO " + table + " AS target\nUSING (VALUES" + rows + ") AS source (" + columns + ")\n" +
+		"ON " + strings.Join(conditions, " AND ")
+	if s.updatesColumns() {
+		statement += "\nWHEN MATCHED THEN UPDATE SET " + s.assignments(func(column string) string { return "source." + column })
+	}
+	return statement + "\nWHEN NOT MATCHED THEN INSERT (" + columns + ") VALUES (" + s.columnList("source.") + ")"
+}
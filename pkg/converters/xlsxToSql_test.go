@@ -0,0 +1,90 @@
+package converters
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func TestXlsxToSql(t *testing.T) {
+	filePath := createTestWorkbook(t, map[string][][]any{
+		"People": {{"Id", "Name", "Score", "Joined", "Note"}, {1, "O'Brien", 9.5, "12-25-20", `C:\temp`}, {2, "Bob", 7, "", ""}},
+		"Codes":  {{"Code", "Id"}, {"007", "x"}},
+	}, "People", "Codes")
+
+	tests := []struct {
+		name string
+		opts SqlOptions
+		want string
+	}{
+		{
+			name: "Postgres Inserts",
+			opts: SqlOptions{},
+			want: `INSERT INTO "People" ("Id", "Name", "Score", "Joined", "Note") VALUES ('1', 'O''Brien', '9.5', '2020-12-25 00:00:00', 'C:\temp');` + "\n" +
+				`INSERT INTO "People" ("Id", "Name", "Score", "Joined", "Note") VALUES ('2', 'Bob', '7', NULL, NULL);` + "\n",
+		},
+		{
+			name: "Inferred Types",
+			opts: SqlOptions{InferTypes: true, Table: "crm.people", ColumnNames: map[string]string{"Id": "id", "Score": "score", "Joined": "joined"}, BatchSize: 10},
+			want: `INSERT INTO "crm"."people" ("id", "score", "joined") VALUES` + "\n" +
+				`  (1, 9.5, TIMESTAMP '2020-12-25 00:00:00'),` + "\n" +
+				`  (2, 7.0, NULL);` + "\n",
+		},
+		{
+			name: "Postgres Upsert",
+			opts: SqlOptions{Statement: UpsertStatement, KeyColumns: []string{"Id"}, ColumnNames: map[string]string{"Id": "id", "Name": "name"},
+				ColumnTypes: map[string]CellType{"Id": IntegerCell}, CreateTable: true, Sheets: SheetByName("People"), BatchSize: 1},
+			want: "CREATE TABLE \"People\" (\n  \"id\" BIGINT,\n  \"name\" TEXT,\n  PRIMARY KEY (\"id\")\n);\n\n" +
+				`INSERT INTO "People" ("id", "name") VALUES (1, 'O''Brien')` + "\n" + `ON CONFLICT ("id") DO UPDATE SET "name" = EXCLUDED."name";` + "\n" +
+				`INSERT INTO "People" ("id", "name") VALUES (2, 'Bob')` + "\n" + `ON CONFLICT ("id") DO UPDATE SET "name" = EXCLUDED."name";` + "\n",
+		},
+		{
+			name: "MySQL Upsert",
+			opts: SqlOptions{Dialect: MySqlDialect, Statement: UpsertStatement, KeyColumns: []string{"Id"}, ColumnNames: map[string]string{"Id": "id", "Note": "note"}, BatchSize: 2},
+			want: "INSERT INTO `People` (`id`, `note`) VALUES\n  ('1', 'C:\\\\temp'),\n  ('2', NULL)\nON DUPLICATE KEY UPDATE `note` = VALUES(`note`);\n",
+		},
+		{
+			name: "SQL Server Merge",
+			opts: SqlOptions{Dialect: SqlServerDialect, Statement: UpsertStatement, KeyColumns: []string{"Id"}, ColumnNames: map[string]string{"Id": "Id", "Name": "Name"}, InferTypes: true},
+			want: "MERGE INTO [People] AS target\nUSING (VALUES (1, N'O''Brien')) AS source ([Id], [Name])\nON target.[Id] = source.[Id]\n" +
+				"WHEN MATCHED THEN UPDATE SET [Name] = source.[Name]\nWHEN NOT MATCHED THEN INSERT ([Id], [Name]) VALUES (source.[Id], source.[Name]);\n" +
+				"MERGE INTO [People] AS target\nUSING (VALUES (2, N'Bob')) AS source ([Id], [Name])\nON target.[Id] = source.[Id]\n" +
+				"WHEN MATCHED THEN UPDATE SET [Name] = source.[Name]\nWHEN NOT MATCHED THEN INSERT ([Id], [Name]) VALUES (source.[Id], source.[Name]);\n",
+		},
+		{
+			name: "Leading Zeros",
+			opts: SqlOptions{Sheets: SheetByName("Codes"), InferTypes: true, CreateTable: true, Dialect: SqlServerDialect, KeyColumns: []string{"Code"}},
+			want: "CREATE TABLE [Codes] (\n  [Code] NVARCHAR(450),\n  [Id] NVARCHAR(MAX),\n  PRIMARY KEY ([Code])\n);\n\n" +
+				"INSERT INTO [Codes] ([Code], [Id]) VALUES (N'007', N'x');\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output bytes.Buffer
+			if err := XlsxToSql(context.Background(), &output, filePath, tt.opts); err != nil {
+				t.Fatalf("XlsxToSql() error = %v", err)
+			}
+			if output.String() != tt.want {
+				t.Errorf("XlsxToSql() output = \n%s\nwant:\n%s", output.String(), tt.want)
+			}
+		})
+	}
+
+	invalid := []struct {
+		name string
+		opts SqlOptions
+	}{
+		{name: "Upsert Without Keys", opts: SqlOptions{Statement: UpsertStatement}},
+		{name: "Unknown Key", opts: SqlOptions{KeyColumns: []string{"Missing"}}},
+		{name: "Invalid Typed Value", opts: SqlOptions{ColumnTypes: map[string]CellType{"Name": IntegerCell}}},
+		{name: "Oversized Batch", opts: SqlOptions{Dialect: SqlServerDialect, BatchSize: 1001}},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			var output bytes.Buffer
+			if err := XlsxToSql(context.Background(), &output, filePath, tt.opts); err == nil {
+				t.Errorf("XlsxToSql() error = nil, want an error")
+			}
+		})
+	}
+}
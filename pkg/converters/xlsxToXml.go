@@ -0,0 +1,688 @@
+// Package converters holds the conversion pipelines behind the command line tools.
+// Conversions designed to be used with the .NET Framework emit documents that deserialize into a .NET DataTable object.
+package converters
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	. "GoTools/pkg/helpers"
+	"github.com/xuri/excelize/v2"
+)
+
+// DataColumn is a single cell of a row. Its value is held in Value, in CData when it is written as a CDATA section,
+// or in Runs when it is written as rich text.
+type DataColumn struct {
+	XMLName    xml.Name
+	Attributes []xml.Attr `xml:",any,attr"`
+	Value      string     `xml:",chardata"`
+	CData      string     `xml:",cdata"`
+	Runs       []TextRun  `xml:"Run"`
+	// comment is the comment of the cell and commented tells whether any cell of its column holds one,
+	// both are only read when comments are written. link is the hyperlink target of the cell and linked tells
+	// whether its column is one of the LinkColumns.
+	comment   string
+	commented bool
+	link      string
+	linked    bool
+}
+
+// text returns the value of the column, whether it is written as character data, as a CDATA section
+// or as rich text runs.
+func (c DataColumn) text() string {
+	value := c.Value + c.CData
+	for _, run := range c.Runs {
+		value += run.Text
+	}
+	return value
+}
+
+type DataRow struct {
+	XMLName    xml.Name
+	Attributes []xml.Attr   `xml:",any,attr"`
+	Columns    []DataColumn `xml:",any"`
+}
+
+type DataTable struct {
+	XMLName    xml.Name
+	Attributes []xml.Attr `xml:",any,attr"`
+	Rows       []DataRow  `xml:"Row"`
+}
+
+// Sheet wraps the DataTable of a single worksheet when several worksheets are combined into one document.
+type Sheet struct {
+	Name  string    `xml:"name,attr"`
+	Table DataTable `xml:"DataTable"`
+}
+
+// Workbook is the root element of a combined document, holding one Sheet per converted worksheet.
+type Workbook struct {
+	XMLName    xml.Name   `xml:"Workbook"`
+	Attributes []xml.Attr `xml:",any,attr"`
+	Sheets     []Sheet    `xml:"Sheet"`
+}
+
+// XmlOptions controls the element names of the generated XML, so the output can match the schema a consumer expects.
+// RootName names the element holding the rows of a worksheet, which is the root of a single worksheet document.
+// RowName names the element of each row.
+// Empty names fall back to "DataTable" and "Row".
+// Attributes emits each column as an attribute of the row element (<Row Name="..." Age="..."></Row>)
+// instead of as a child element. The row keeps its end tag, encoding/xml having no way to self-close an element,
+// which XML parsers read the same as <Row Name="..." Age="..."/>.
+// InferTypes annotates every non-empty cell with the xsi:type its value is inferred to be, numbers, booleans
+// and dates being rewritten into their canonical lexical form. ColumnTypes forces the type of the named columns
+// regardless of InferTypes; it is keyed on the cleaned element name. Types are not annotated in attribute mode.
+// CDataColumns writes the values of the named columns as CDATA sections instead of escaped character data,
+// it is keyed on the cleaned element name too. AutoCData does the same for any value holding markup-like content,
+// that is '<', '>' or '&'. Values annotated with a type other than xs:string are never wrapped,
+// and neither option can be combined with attribute mode.
+// Namespace declares the default namespace of the document, qualifying every unprefixed element.
+// Namespaces declares additional namespaces keyed by their prefix, and ColumnPrefixes places the named columns,
+// keyed on the cleaned element name, in the namespace bound to the given prefix.
+// Namespaces are declared on the root element of the document.
+// Comments selects whether and how cell comments are written, see CommentMode.
+// EmptyCells selects how cells without a value are written, see EmptyCellMode.
+// Indent is the indentation of every nesting level, two spaces by default, and Newline the line break, either
+// "\n", the default, or "\r\n". Compact writes the whole document on a single line instead.
+// Encoding is the character encoding of the output: UTF-8, the default, UTF-16 (little endian with a byte order
+// mark), UTF-16LE, UTF-16BE or ISO-8859-1, whose unrepresentable characters are written as character references.
+// ByteOrderMark starts UTF-8 output with a byte order mark. Declaration writes the XML declaration naming the
+// encoding, which is always written for encodings other than UTF-8.
+// LinkColumns writes the hyperlink target of the cells in the named columns into a sibling element named after
+// the column with a "_link" suffix (<Website_link>https://...</Website_link>), or a sibling attribute in attribute
+// mode; it is keyed on the cleaned element name. The sibling is written on every row, empty for cells without a link.
+// Transliterate spells the accented and special Latin letters of the headers in ASCII when building element
+// names, "Größe" becoming "Grosse", instead of keeping them.
+type XmlOptions struct {
+	RootName       string
+	RowName        string
+	Attributes     bool
+	InferTypes     bool
+	ColumnTypes    map[string]CellType
+	CDataColumns   []string
+	AutoCData      bool
+	Namespace      string
+	Namespaces     map[string]string
+	ColumnPrefixes map[string]string
+	Comments       CommentMode
+	LinkColumns    []string
+	EmptyCells     EmptyCellMode
+	Indent         string
+	Newline        string
+	Compact        bool
+	Encoding       string
+	ByteOrderMark  bool
+	Declaration    bool
+	Transliterate  bool
+}
+
+// rootName returns the configured root element name or its default.
+func (o XmlOptions) rootName() string {
+	if len(o.RootName) > 0 {
+		return o.RootName
+	}
+	return "DataTable"
+}
+
+// tagOptions returns how the headers are cleaned into element names.
+func (o XmlOptions) tagOptions() TagOptions {
+	return TagOptions{PrefixInvalidStart: true, Transliterate: o.Transliterate}
+}
+
+// rowName returns the configured row element name or its default.
+func (o XmlOptions) rowName() string {
+	if len(o.RowName) > 0 {
+		return o.RowName
+	}
+	return "Row"
+}
+
+// shapeRow names the row element, writes the hyperlink targets and cell comments, annotates the column types, wraps free text in CDATA
+// sections, qualifies the columns mapped to a namespace and, in attribute mode, moves its columns into attributes.
+func (o XmlOptions) shapeRow(dataRow *DataRow) {
+	dataRow.XMLName = xml.Name{Local: o.rowName()}
+	if len(o.LinkColumns) > 0 {
+		o.writeLinks(dataRow)
+	}
+	if o.Comments != NoComments {
+		o.writeComments(dataRow)
+	}
+	if o.typed() && !o.Attributes {
+		for columnIndex := range dataRow.Columns {
+			o.annotateType(&dataRow.Columns[columnIndex])
+		}
+	}
+	if o.wrapsCData() {
+		for columnIndex := range dataRow.Columns {
+			o.wrapCData(&dataRow.Columns[columnIndex])
+		}
+	}
+	if len(o.ColumnPrefixes) > 0 {
+		for columnIndex := range dataRow.Columns {
+			o.qualifyColumn(&dataRow.Columns[columnIndex])
+		}
+	}
+	if o.EmptyCells != EmptyElements {
+		o.writeEmptyCells(dataRow)
+	}
+	if o.Attributes {
+		for _, column := range dataRow.Columns {
+			dataRow.Attributes = append(dataRow.Attributes, xml.Attr{Name: column.XMLName, Value: column.Value})
+		}
+		dataRow.Columns = nil
+	}
+}
+
+// wrapsCData reports whether any value may be written as a CDATA section.
+func (o XmlOptions) wrapsCData() bool {
+	return o.AutoCData || len(o.CDataColumns) > 0
+}
+
+// wrapCData moves the value of a column into a CDATA section if the column is configured for it
+// or the value holds markup-like content and AutoCData is set.
+func (o XmlOptions) wrapCData(column *DataColumn) {
+	if len(column.Value) < 1 {
+		return
+	}
+	for _, attr := range column.Attributes {
+		if attr.Name.Local == "xsi:type" && attr.Value != xsdType(StringCell) {
+			return
+		}
+	}
+	wrap := o.AutoCData && strings.ContainsAny(column.Value, "<>&")
+	for _, name := range o.CDataColumns {
+		wrap = wrap || name == column.XMLName.Local
+	}
+	if wrap {
+		column.CData, column.Value = column.Value, ""
+	}
+}
+
+// validate reports element names that would produce malformed XML.
+func (o XmlOptions) validate() error {
+	if o.Attributes && o.wrapsCData() {
+		return errors.New("CDATA sections cannot be written in attribute mode")
+	}
+	if o.Attributes && o.EmptyCells == NilEmpty {
+		return errors.New("empty cells cannot be marked nil in attribute mode")
+	}
+	if o.Attributes && o.Comments == CommentAttributes {
+		return errors.New("comment attributes cannot be written in attribute mode, write comments as elements instead")
+	}
+	for _, name := range []string{o.rootName(), o.rowName()} {
+		if FixXMLTags(name) != name {
+			return fmt.Errorf("'%s' is not a valid XML element name", name)
+		}
+	}
+	if err := o.validateFormat(); err != nil {
+		return err
+	}
+	if err := o.validateEncoding(); err != nil {
+		return err
+	}
+	return o.validateNamespaces()
+}
+
+// Options configures an XlsxToXml conversion.
+// Sheets selects the worksheets to convert; the zero value converts the first worksheet.
+// Combine emits a single Workbook document with a <Sheet name="..."> wrapper per worksheet
+// instead of one DataTable document per worksheet.
+// Streaming writes each row to the output as soon as it is read instead of building the whole
+// document in memory first, which keeps memory bounded for very large worksheets.
+// GenerateXSD also emits an XML Schema describing the document, with the element names, the types inferred
+// from the values and which columns hold empty cells. XlsxToXmlFiles writes it next to each document,
+// XlsxToXml writes it to SchemaOutput.
+// ChunkSize, when positive, splits every document XlsxToXmlFiles writes into numbered files of at most that many rows,
+// each a standalone document, and writes a manifest indexing them; the rows are streamed regardless of Streaming.
+// Validate, when set, checks every row written against the schema and fails the conversion with a
+// *ValidationError listing the violations once the whole document has been written.
+// Compression compresses the output, see Compression.
+// Progress, when set, is called every ProgressInterval worksheet rows read (1000 by default) and once more when the
+// conversion completes, with the number of rows read so far and the total number of rows of the selected worksheets.
+// The rows are counted before the conversion starts, which reads every selected worksheet one extra time.
+// ErrorReport, when set, collects the problems found in cells instead of failing the conversion or ignoring them,
+// see Problem, and writes them in that format. XlsxToXmlFiles writes the report next to the documents,
+// XlsxToXml writes it to ReportOutput.
+// Xml controls the element names of the output.
+// The embedded ReadOptions control how the rows of every worksheet are read.
+type Options struct {
+	ReadOptions
+	Sheets           SheetSelector
+	Combine          bool
+	Streaming        bool
+	Xml              XmlOptions
+	GenerateXSD      bool
+	SchemaOutput     io.Writer
+	ChunkSize        int
+	Validate         *Schema
+	Compression      Compression
+	Progress         func(done, total int)
+	ProgressInterval int
+	ErrorReport      ReportFormat
+	ReportOutput     io.Writer
+
+	progress *progressTracker
+	report   *problemReport
+}
+
+// validate reports options that cannot produce a well-formed conversion.
+func (o Options) validate() error {
+	if err := o.ReadOptions.validate(); err != nil {
+		return err
+	}
+	if o.ChunkSize < 0 {
+		return errors.New("chunk size must not be negative")
+	}
+	if o.GenerateXSD && len(o.Xml.ColumnPrefixes) > 0 {
+		return errors.New("a schema cannot be generated for columns in other namespaces")
+	}
+	if o.GenerateXSD && o.Xml.Comments == CommentAttributes {
+		return errors.New("a schema cannot be generated for comment attributes")
+	}
+	if o.RichText == XmlRichText {
+		if o.Xml.Attributes {
+			return errors.New("rich text runs cannot be written in attribute mode")
+		}
+		if o.GenerateXSD {
+			return errors.New("a schema cannot be generated for rich text runs")
+		}
+	}
+	return o.Xml.validate()
+}
+
+// newSchema returns the builder collecting the schema of a document, or nil if no schema is generated.
+func (o Options) newSchema() *schemaBuilder {
+	if !o.GenerateXSD {
+		return nil
+	}
+	return newSchemaBuilder(o.Xml)
+}
+
+// newValidator returns the validator checking the rows of a document, or nil if no validation is requested.
+func (o Options) newValidator() (*rowValidator, error) {
+	if o.Validate == nil {
+		return nil, nil
+	}
+	return newRowValidator(o.Validate, o)
+}
+
+// rowObserver is handed every row of a conversion after it has been shaped for output.
+type rowObserver interface {
+	startTable(sheet string)
+	observe(rowNumber int, dataRow DataRow)
+}
+
+// observers hands the rows of a conversion to each of its observers in turn.
+type observers []rowObserver
+
+// newObservers collects the schema builder and validator of a conversion, skipping those not in use.
+func newObservers(schema *schemaBuilder, validator *rowValidator) observers {
+	var watchers observers
+	if schema != nil {
+		watchers = append(watchers, schema)
+	}
+	if validator != nil {
+		watchers = append(watchers, validator)
+	}
+	return watchers
+}
+
+func (o observers) startTable(sheet string) {
+	for _, observer := range o {
+		observer.startTable(sheet)
+	}
+}
+
+func (o observers) observe(rowNumber int, dataRow DataRow) {
+	for _, observer := range o {
+		observer.observe(rowNumber, dataRow)
+	}
+}
+
+// XlsxToXml converts the worksheets of the .xlsx file at path selected by opts.Sheets and writes the XML to w.
+// Legacy .xls workbooks and OpenDocument spreadsheets (.ods) are read as well.
+// Only a single document can be written to w, so selecting more than one worksheet requires opts.Combine;
+// use XlsxToXmlFiles to write one document per worksheet instead.
+// With GzipCompression the document is compressed; the schema written to opts.SchemaOutput is not.
+// The conversion stops with the error of ctx as soon as it is cancelled or its deadline passes.
+func XlsxToXml(ctx context.Context, w io.Writer, path string, opts Options) (convertErr error) {
+	if optsErr := opts.validate(); optsErr != nil {
+		return optsErr
+	}
+	if opts.GenerateXSD && opts.SchemaOutput == nil {
+		return errors.New("generating a schema requires a schema output")
+	}
+	if opts.ErrorReport != NoReport && opts.ReportOutput == nil {
+		return errors.New("an error report requires a report output")
+	}
+	if opts.ChunkSize > 0 {
+		return errors.New("chunked output can only be written to files")
+	}
+	if opts.Compression == ZipCompression {
+		return errors.New("a zip archive can only be written to files, use gzip compression instead")
+	}
+	file, openErr := openWorkbook(ctx, path, opts.ReadOptions)
+	if openErr != nil {
+		return openErr
+	}
+	defer func(file *excelize.File) {
+		if err := file.Close(); err != nil && convertErr == nil {
+			convertErr = err
+		}
+	}(file)
+
+	sheets, selectErr := opts.selectSheets(file, opts.Sheets)
+	if selectErr != nil {
+		return selectErr
+	}
+	if len(sheets) > 1 && !opts.Combine {
+		return errors.New("multiple worksheets selected, combine them or write one file per worksheet")
+	}
+	progress, progressErr := opts.newProgressTracker(ctx, file, sheets)
+	if progressErr != nil {
+		return progressErr
+	}
+	opts.progress = progress
+	opts.report = opts.newProblemReport()
+	validator, validatorErr := opts.newValidator()
+	if validatorErr != nil {
+		return validatorErr
+	}
+	schema := opts.newSchema()
+	if opts.Compression == GzipCompression {
+		compressor := gzip.NewWriter(w)
+		if convertErr := convertSheets(ctx, compressor, file, sheets, opts, newObservers(schema, validator)); convertErr != nil {
+			return convertErr
+		}
+		if closeErr := compressor.Close(); closeErr != nil {
+			return closeErr
+		}
+	} else if convertErr := convertSheets(ctx, w, file, sheets, opts, newObservers(schema, validator)); convertErr != nil {
+		return convertErr
+	}
+	progress.finish()
+	if schema != nil {
+		if schemaErr := schema.write(opts.SchemaOutput, opts.Combine); schemaErr != nil {
+			return schemaErr
+		}
+	}
+	if opts.report != nil {
+		if reportErr := opts.report.write(opts.ReportOutput, filepath.Base(path)); reportErr != nil {
+			return reportErr
+		}
+	}
+	if validator != nil {
+		return validator.err()
+	}
+	return nil
+}
+
+// XlsxToXmlFiles converts the worksheets of the .xlsx file at path selected by opts.Sheets
+// and writes one document per worksheet into dir, named after the worksheet.
+// When opts.Combine is set a single document named after the workbook is written instead.
+// When opts.GenerateXSD is set each document is accompanied by a schema file with the same name.
+// When opts.Validate is set every document is written before the violations found in any of them are returned.
+// When opts.ErrorReport is set the problems found in all documents are reported in a single file named after the
+// workbook with an _errors suffix.
+// opts.Compression compresses every file written, or writes them all into a single archive named after the workbook.
+// The conversion stops with the error of ctx as soon as it is cancelled, leaving the files written so far.
+// It returns the paths of the files written.
+func XlsxToXmlFiles(ctx context.Context, dir, path string, opts Options) (written []string, convertErr error) {
+	if optsErr := opts.validate(); optsErr != nil {
+		return nil, optsErr
+	}
+	validator, validatorErr := opts.newValidator()
+	if validatorErr != nil {
+		return nil, validatorErr
+	}
+	file, openErr := openWorkbook(ctx, path, opts.ReadOptions)
+	if openErr != nil {
+		return nil, openErr
+	}
+	defer func(file *excelize.File) {
+		if err := file.Close(); err != nil && convertErr == nil {
+			convertErr = err
+		}
+	}(file)
+
+	sheets, selectErr := opts.selectSheets(file, opts.Sheets)
+	if selectErr != nil {
+		return nil, selectErr
+	}
+	progress, progressErr := opts.newProgressTracker(ctx, file, sheets)
+	if progressErr != nil {
+		return nil, progressErr
+	}
+	opts.progress = progress
+	opts.report = opts.newProblemReport()
+	files, filesErr := newOutputFiles(dir, path, opts.Compression)
+	if filesErr != nil {
+		return nil, filesErr
+	}
+	defer func() {
+		if err := files.close(); err != nil && convertErr == nil {
+			convertErr = err
+		}
+		written = files.written
+	}()
+	if opts.Combine {
+		name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)) + ".xml"
+		if writeErr := writeXmlFile(ctx, files, filepath.Join(dir, name), file, sheets, opts, validator); writeErr != nil {
+			return nil, writeErr
+		}
+	} else {
+		for _, sheet := range sheets {
+			if writeErr := writeXmlFile(ctx, files, filepath.Join(dir, sheet+".xml"), file, []string{sheet}, opts, validator); writeErr != nil {
+				return nil, writeErr
+			}
+		}
+	}
+	progress.finish()
+	if opts.report != nil {
+		reportErr := files.writeFile(opts.report.reportPath(dir, path), func(w io.Writer) error {
+			return opts.report.write(w, filepath.Base(path))
+		})
+		if reportErr != nil {
+			return nil, reportErr
+		}
+	}
+	if validator != nil {
+		return nil, validator.err()
+	}
+	return nil, nil
+}
+
+// convertSheets writes a single document holding the named worksheets to w.
+// A Workbook document is written when opts.Combine is set, otherwise the DataTable of the first worksheet.
+// Every row written is also handed to the observers.
+func convertSheets(ctx context.Context, w io.Writer, file *excelize.File, sheets []string, opts Options, watchers observers) error {
+	if opts.Streaming {
+		return streamXml(ctx, w, file, sheets, opts, watchers)
+	}
+	if opts.Combine {
+		workbook, readErr := readWorkbook(ctx, file, sheets, opts, watchers)
+		if readErr != nil {
+			return readErr
+		}
+		return writeXml(w, workbook, opts.Xml)
+	}
+	dataTable, readErr := readSheet(ctx, file, sheets[0], opts, watchers)
+	if readErr != nil {
+		return readErr
+	}
+	return writeXml(w, dataTable, opts.Xml)
+}
+
+// readWorkbook reads every named worksheet into a Workbook, preserving the given order.
+func readWorkbook(ctx context.Context, file *excelize.File, sheets []string, opts Options, watchers observers) (Workbook, error) {
+	workbook := Workbook{Attributes: opts.Xml.namespaceDecls()}
+	for _, sheet := range sheets {
+		dataTable, readErr := readSheet(ctx, file, sheet, opts, watchers)
+		if readErr != nil {
+			return Workbook{}, readErr
+		}
+		workbook.Sheets = append(workbook.Sheets, Sheet{Name: sheet, Table: dataTable})
+	}
+	return workbook, nil
+}
+
+// newSheetReader prepares a reader for the named worksheet, which also loads its comments when they are written,
+// looks up the hyperlinks of the link columns and counts the rows read towards the progress of the conversion.
+func (o Options) newSheetReader(file *excelize.File, sheet string) (*sheetReader, error) {
+	reader, readerErr := newSheetReader(file, sheet, o.ReadOptions)
+	if readerErr != nil {
+		return nil, readerErr
+	}
+	reader.progress, reader.report = o.progress, o.report
+	reader.tags = o.Xml.tagOptions()
+	if len(o.Xml.LinkColumns) > 0 {
+		reader.linkColumns = make(map[string]bool, len(o.Xml.LinkColumns))
+		for _, name := range o.Xml.LinkColumns {
+			reader.linkColumns[name] = true
+		}
+	}
+	if o.Xml.Comments != NoComments {
+		if commentsErr := reader.loadComments(); commentsErr != nil {
+			return nil, commentsErr
+		}
+	}
+	return reader, nil
+}
+
+// readSheet reads the named worksheet and builds its DataTable,
+// shaping the table and row elements as configured in opts and handing them to the observers.
+func readSheet(ctx context.Context, file *excelize.File, sheet string, opts Options, watchers observers) (DataTable, error) {
+	reader, readerErr := opts.newSheetReader(file, sheet)
+	if readerErr != nil {
+		return DataTable{}, readerErr
+	}
+	watchers.startTable(sheet)
+	dataTable, buildErr := buildDataTable(ctx, reader, func(rowNumber int, dataRow *DataRow) {
+		opts.Xml.shapeRow(dataRow)
+		watchers.observe(rowNumber, *dataRow)
+	})
+	if buildErr != nil {
+		return DataTable{}, buildErr
+	}
+	dataTable.XMLName = xml.Name{Local: opts.Xml.rootName()}
+	dataTable.Attributes = opts.tableAttrs()
+	return dataTable, nil
+}
+
+// writeXml marshals the document with the layout and encoding configured in opts and writes it to w.
+func writeXml(w io.Writer, document any, opts XmlOptions) error {
+	encoder, encoderErr := opts.newEncoder(w)
+	if encoderErr != nil {
+		return encoderErr
+	}
+	if err := encoder.Encode(document); err != nil {
+		return err
+	}
+	return encoder.finish()
+}
+
+// writeXmlFile creates the file at path and converts the named worksheets into it,
+// followed by the schema file when opts.GenerateXSD is set. Rows are also handed to the validator, if any.
+// When opts.ChunkSize is set the document is split into chunk files next to path instead.
+func writeXmlFile(ctx context.Context, files *outputFiles, path string, file *excelize.File, sheets []string, opts Options, validator *rowValidator) error {
+	schema := opts.newSchema()
+	var writeErr error
+	if opts.ChunkSize > 0 {
+		writeErr = writeXmlChunks(ctx, files, path, file, sheets, opts, newObservers(schema, validator))
+	} else {
+		writeErr = files.writeFile(path, func(w io.Writer) error {
+			return convertSheets(ctx, w, file, sheets, opts, newObservers(schema, validator))
+		})
+	}
+	if writeErr != nil || schema == nil {
+		return writeErr
+	}
+	schemaPath := strings.TrimSuffix(path, filepath.Ext(path)) + ".xsd"
+	return files.writeFile(schemaPath, func(w io.Writer) error {
+		return schema.write(w, opts.Combine)
+	})
+}
+
+// cleanHeaders returns the element names of the columns of a header row, cleaned as configured by tags.
+// It calls the SanitizeHeaders function to clean every header, replacing any invalid XML characters, and to rename
+// the names which are then equal, such as those of "Amount ($)" and "Amount (%)", so every column keeps its own.
+// Headers starting with a character an element name cannot start with, such as the digit of a year,
+// are prefixed with an underscore rather than losing it.
+// Example usage:
+//
+//	names := cleanHeaders([]string{"<Hello World!>", "Hello World"}, XmlOptions{}.tagOptions())
+//	fmt.Println(names)
+//	// Output: [Hello_x0020_World Hello_x0020_World_2]
+func cleanHeaders(header []string, tags TagOptions) []string {
+	names, _ := SanitizeHeaders(header, SanitizeOptions{Tag: tags})
+	return names
+}
+
+// buildDataTable takes a sheetReader as input and converts the worksheet into a DataTable struct.
+// It collects every DataRow produced by eachDataRow into the Rows field of the DataTable struct,
+// after handing it to shape together with its row number.
+// If a row cannot be read or ctx is done, it returns an empty DataTable struct and the error.
+// The function returns the populated DataTable struct.
+func buildDataTable(ctx context.Context, reader *sheetReader, shape func(rowNumber int, dataRow *DataRow)) (DataTable, error) {
+	var dataTable DataTable
+	rowsErr := eachDataRow(ctx, reader, func(rowNumber int, dataRow DataRow) error {
+		shape(rowNumber, &dataRow)
+		dataTable.Rows = append(dataTable.Rows, dataRow)
+		return nil
+	})
+	if rowsErr != nil {
+		return DataTable{}, rowsErr
+	}
+	return dataTable, nil
+}
+
+// eachDataRow iterates over each record of the worksheet and converts each record into a DataRow struct,
+// handing it to yield together with its one-based row number before the next record is read.
+// For the header row, it calls the cleanHeaders function to clean the headers and rename any duplicates.
+// For subsequent rows, it converts each column into a DataColumn struct, along with its comment when comments
+// were loaded and its hyperlink target when its column is a link column, and appends it to the DataRow struct.
+// Iteration stops at the first error returned by the reader or by yield.
+func eachDataRow(ctx context.Context, reader *sheetReader, yield func(rowNumber int, dataRow DataRow) error) error {
+	var headerRow []string
+	var headerIndexes []int
+	onHeader := func(columns []string, indexes []int) error {
+		headerIndexes = indexes
+		headerRow = cleanHeaders(columns, reader.tags)
+		return nil
+	}
+	return reader.eachRecord(ctx, onHeader, func(rowNumber int, columns []string) error {
+		// Dirty workaround because `(*rows).Columns()` doesn't do what it says it does.
+		for len(columns) < len(headerRow) {
+			columns = append(columns, "")
+		}
+		var dataRow DataRow
+		for columnIndex := range columns {
+			columnName := headerRow[columnIndex]
+			columnValue := ConvertToISO8601(columns[columnIndex])
+			reader.report.check(reader.sheet, rowNumber, headerIndexes[columnIndex], columnName, columns[columnIndex], columnValue)
+			column := DataColumn{XMLName: xml.Name{Local: columnName}, Value: columnValue}
+			if reader.comments != nil {
+				column.comment = reader.comment(rowNumber, headerIndexes[columnIndex])
+				column.commented = reader.hasComments(headerIndexes[columnIndex])
+			}
+			if runs := reader.textRuns(rowNumber, headerIndexes[columnIndex]); len(runs) > 0 {
+				column.Runs, column.Value = runs, ""
+			}
+			if reader.linkColumns[columnName] {
+				link, linkErr := reader.hyperlink(rowNumber, headerIndexes[columnIndex])
+				if linkErr != nil {
+					return linkErr
+				}
+				column.link, column.linked = link, true
+			}
+			dataRow.Columns = append(dataRow.Columns, column)
+		}
+		return yield(rowNumber, dataRow)
+	})
+}
@@ -0,0 +1,877 @@
+package converters
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
+)
+
+// createTestWorkbook saves a workbook with the given sheets, each holding the given rows, into a temporary directory.
+func createTestWorkbook(t *testing.T, sheets map[string][][]any, order ...string) string {
+	t.Helper()
+	file := excelize.NewFile()
+	defer func() {
+		if err := file.Close(); err != nil {
+			t.Errorf("Error closing test workbook: %v", err)
+		}
+	}()
+	for index, sheet := range order {
+		if index == 0 {
+			if err := file.SetSheetName("Sheet1", sheet); err != nil {
+				t.Fatalf("Error renaming test sheet: %v", err)
+			}
+		} else if _, err := file.NewSheet(sheet); err != nil {
+			t.Fatalf("Error creating test sheet: %v", err)
+		}
+		for rowIdx, row := range sheets[sheet] {
+			cellName, _ := excelize.CoordinatesToCellName(1, rowIdx+1)
+			if err := file.SetSheetRow(sheet, cellName, &row); err != nil {
+				t.Fatalf("Error writing test row: %v", err)
+			}
+		}
+	}
+	filePath := filepath.Join(t.TempDir(), "TestWorkbook.xlsx")
+	if err := file.SaveAs(filePath); err != nil {
+		t.Fatalf("Error saving test workbook: %v", err)
+	}
+	return filePath
+}
+
+func TestXlsxToXmlStreaming(t *testing.T) {
+	filePath := createTestWorkbook(t, map[string][][]any{
+		"People": {{"Name", "Age", "Name"}, {"Ann", 31, "A"}, {"Bob", 42}},
+		"Orders": {{"Id", "Amount"}, {1, 9.99}},
+	}, "People", "Orders")
+
+	// want, when set, is the output expected besides both modes writing the same document.
+	tests := []struct {
+		name string
+		opts Options
+		want string
+	}{
+		{name: "First Sheet", opts: Options{}},
+		{name: "Named Sheet", opts: Options{Sheets: SheetByName("Orders")}},
+		{name: "Combined Sheets", opts: Options{Sheets: AllSheets(), Combine: true}},
+		{name: "Custom Names", opts: Options{Xml: XmlOptions{RootName: "DocumentElement", RowName: "People"}}, want: "<DocumentElement>\n" +
+			"  <People>\n    <Name>Ann</Name>\n    <Age>31</Age>\n    <Name_2>A</Name_2>\n  </People>\n" +
+			"  <People>\n    <Name>Bob</Name>\n    <Age>42</Age>\n    <Name_2></Name_2>\n  </People>\n" +
+			"</DocumentElement>"},
+		{name: "Attributes", opts: Options{Xml: XmlOptions{Attributes: true}}, want: "<DataTable>\n" +
+			"  <Row Name=\"Ann\" Age=\"31\" Name_2=\"A\"></Row>\n" +
+			"  <Row Name=\"Bob\" Age=\"42\" Name_2=\"\"></Row>\n" +
+			"</DataTable>"},
+		{name: "Typed Values", opts: Options{Xml: XmlOptions{InferTypes: true}}},
+		{name: "CDATA Sections", opts: Options{Xml: XmlOptions{CDataColumns: []string{"Name"}}}},
+		{name: "Nil Cells", opts: Options{Sheets: AllSheets(), Combine: true, Xml: XmlOptions{EmptyCells: NilEmpty}}},
+		{name: "Namespaces", opts: Options{Sheets: AllSheets(), Combine: true, Xml: XmlOptions{
+			Namespace: "urn:example", Namespaces: map[string]string{"p": "urn:people"}, ColumnPrefixes: map[string]string{"Age": "p"},
+		}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buffered, streamed bytes.Buffer
+			if err := XlsxToXml(context.Background(), &buffered, filePath, tt.opts); err != nil {
+				t.Fatalf("XlsxToXml() error = %v", err)
+			}
+			tt.opts.Streaming = true
+			if err := XlsxToXml(context.Background(), &streamed, filePath, tt.opts); err != nil {
+				t.Fatalf("XlsxToXml() streaming error = %v", err)
+			}
+			if buffered.String() != streamed.String() {
+				t.Errorf("XlsxToXml() streaming output differs\nwant:\n%s\ngot:\n%s", buffered.String(), streamed.String())
+			}
+			if len(tt.want) > 0 && buffered.String() != tt.want {
+				t.Errorf("XlsxToXml() output = \n%s\nwant:\n%s", buffered.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestXlsxToXmlHeaderRows(t *testing.T) {
+	filePath := createTestWorkbook(t, map[string][][]any{
+		"Report": {
+			{"Quarterly Report"},
+			{},
+			{"Name", "Amount"},
+			{"Unit", "EUR"},
+			{"Ann", 10},
+			{"Bob", 20},
+			{"Total", 30},
+		},
+	}, "Report")
+	opts := Options{
+		ReadOptions: ReadOptions{HeaderRowIndex: 2, SkipRows: 1, SkipFooterRows: 1},
+		Xml:         XmlOptions{Attributes: true},
+	}
+	var output bytes.Buffer
+	if err := XlsxToXml(context.Background(), &output, filePath, opts); err != nil {
+		t.Fatalf("XlsxToXml() error = %v", err)
+	}
+	want := "<DataTable>\n" +
+		"  <Row Name=\"Ann\" Amount=\"10\"></Row>\n" +
+		"  <Row Name=\"Bob\" Amount=\"20\"></Row>\n" +
+		"</DataTable>"
+	if output.String() != want {
+		t.Errorf("XlsxToXml() output = \n%s\nwant:\n%s", output.String(), want)
+	}
+}
+
+func TestXlsxToXmlHeaderCollisions(t *testing.T) {
+	filePath := createTestWorkbook(t, map[string][][]any{
+		"Report": {{"Amount ($)", "Amount (%)", "2024"}, {10, 5, "x"}},
+	}, "Report")
+	var output bytes.Buffer
+	if err := XlsxToXml(context.Background(), &output, filePath, Options{}); err != nil {
+		t.Fatalf("XlsxToXml() error = %v", err)
+	}
+	want := "<DataTable>\n" +
+		"  <Row>\n" +
+		"    <Amount_x0020_>10</Amount_x0020_>\n" +
+		"    <Amount_x0020__2>5</Amount_x0020__2>\n" +
+		"    <_2024>x</_2024>\n" +
+		"  </Row>\n" +
+		"</DataTable>"
+	if output.String() != want {
+		t.Errorf("XlsxToXml() output = \n%s\nwant:\n%s", output.String(), want)
+	}
+}
+
+func TestXlsxToXmlTransliterate(t *testing.T) {
+	filePath := createTestWorkbook(t, map[string][][]any{
+		"Mesures": {{"Größe", "Prénom"}, {"groß", "Zoé"}},
+	}, "Mesures")
+	var output bytes.Buffer
+	opts := Options{Xml: XmlOptions{Attributes: true, Transliterate: true}}
+	if err := XlsxToXml(context.Background(), &output, filePath, opts); err != nil {
+		t.Fatalf("XlsxToXml() error = %v", err)
+	}
+	want := "<DataTable>\n  <Row Grosse=\"groß\" Prenom=\"Zoé\"></Row>\n</DataTable>"
+	if output.String() != want {
+		t.Errorf("XlsxToXml() output = \n%s\nwant:\n%s", output.String(), want)
+	}
+}
+
+func TestXlsxToXmlNamelessHeaders(t *testing.T) {
+	filePath := createTestWorkbook(t, map[string][][]any{
+		"Rates": {{"Name", "(%)", "", "Column2"}, {"Ann", 5, "x", "y"}},
+	}, "Rates")
+	want := "<DataTable>\n" +
+		"  <Row>\n" +
+		"    <Name>Ann</Name>\n" +
+		"    <Column2>5</Column2>\n" +
+		"    <Column3>x</Column3>\n" +
+		"    <Column2_2>y</Column2_2>\n" +
+		"  </Row>\n" +
+		"</DataTable>"
+	for _, streaming := range []bool{false, true} {
+		var output bytes.Buffer
+		if err := XlsxToXml(context.Background(), &output, filePath, Options{Streaming: streaming}); err != nil {
+			t.Fatalf("XlsxToXml(streaming %v) error = %v", streaming, err)
+		}
+		if output.String() != want {
+			t.Errorf("XlsxToXml(streaming %v) output = \n%s\nwant:\n%s", streaming, output.String(), want)
+		}
+	}
+}
+
+func TestXlsxToXmlHidden(t *testing.T) {
+	filePath := createTestWorkbook(t, map[string][][]any{
+		"People": {{"Name", "Internal", "Age"}, {"Ann", "x", 31}, {"Bob", "y", 42}, {"Cid", "z", 53}},
+	}, "People")
+	file, openErr := excelize.OpenFile(filePath)
+	if openErr != nil {
+		t.Fatalf("Error opening test workbook: %v", openErr)
+	}
+	if err := file.SetColVisible("People", "B", false); err != nil {
+		t.Fatalf("Error hiding test column: %v", err)
+	}
+	for _, row := range []int{1, 3} {
+		if err := file.SetRowVisible("People", row, false); err != nil {
+			t.Fatalf("Error hiding test row: %v", err)
+		}
+	}
+	if err := file.Save(); err != nil {
+		t.Fatalf("Error saving test workbook: %v", err)
+	}
+	_ = file.Close()
+
+	tests := []struct {
+		name string
+		opts ReadOptions
+		want string
+	}{
+		{name: "Default", want: "<DataTable>\n" +
+			"  <Row Name=\"Ann\" Internal=\"x\" Age=\"31\"></Row>\n" +
+			"  <Row Name=\"Bob\" Internal=\"y\" Age=\"42\"></Row>\n" +
+			"  <Row Name=\"Cid\" Internal=\"z\" Age=\"53\"></Row>\n" +
+			"</DataTable>"},
+		{name: "Skip Hidden Rows", opts: ReadOptions{SkipHiddenRows: true}, want: "<DataTable>\n" +
+			"  <Row Name=\"Ann\" Internal=\"x\" Age=\"31\"></Row>\n" +
+			"  <Row Name=\"Cid\" Internal=\"z\" Age=\"53\"></Row>\n" +
+			"</DataTable>"},
+		{name: "Skip Hidden Columns", opts: ReadOptions{SkipHiddenColumns: true}, want: "<DataTable>\n" +
+			"  <Row Name=\"Ann\" Age=\"31\"></Row>\n" +
+			"  <Row Name=\"Bob\" Age=\"42\"></Row>\n" +
+			"  <Row Name=\"Cid\" Age=\"53\"></Row>\n" +
+			"</DataTable>"},
+		{name: "Visible Only", opts: ReadOptions{SkipHiddenRows: true, SkipHiddenColumns: true}, want: "<DataTable>\n" +
+			"  <Row Name=\"Ann\" Age=\"31\"></Row>\n" +
+			"  <Row Name=\"Cid\" Age=\"53\"></Row>\n" +
+			"</DataTable>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output bytes.Buffer
+			if err := XlsxToXml(context.Background(), &output, filePath, Options{ReadOptions: tt.opts, Xml: XmlOptions{Attributes: true}}); err != nil {
+				t.Fatalf("XlsxToXml() error = %v", err)
+			}
+			if output.String() != tt.want {
+				t.Errorf("XlsxToXml() output = \n%s\nwant:\n%s", output.String(), tt.want)
+			}
+		})
+	}
+}
+
+// TestSheetReaderStreams checks that reading a worksheet with the default options goes through the row iterator
+// alone, without loading the worksheet into memory.
+func TestSheetReaderStreams(t *testing.T) {
+	filePath := createTestWorkbook(t, map[string][][]any{
+		"People": {{"Name", "Age"}, {"Ann", 31}, {"Bob", 42}},
+	}, "People")
+	file, openErr := excelize.OpenFile(filePath)
+	if openErr != nil {
+		t.Fatalf("Error opening test workbook: %v", openErr)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	reader, readerErr := newSheetReader(file, "People", ReadOptions{})
+	if readerErr != nil {
+		t.Fatalf("newSheetReader() error = %v", readerErr)
+	}
+	var records [][]string
+	err := reader.eachRecord(context.Background(), func([]string, []int) error {
+		return nil
+	}, func(_ int, columns []string) error {
+		records = append(records, columns)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("eachRecord() error = %v", err)
+	}
+	if want := [][]string{{"Ann", "31"}, {"Bob", "42"}}; !reflect.DeepEqual(records, want) {
+		t.Errorf("records = %v, want %v", records, want)
+	}
+	loaded := 0
+	file.Sheet.Range(func(any, any) bool {
+		loaded++
+		return true
+	})
+	if loaded > 0 {
+		t.Errorf("reading with the default options loaded %d worksheets into memory", loaded)
+	}
+}
+
+func TestXlsxToXmlMergedCells(t *testing.T) {
+	filePath := createTestWorkbook(t, map[string][][]any{
+		"Sales": {{"Region", "Q1", "Q2"}, {"North", 10}, {nil, 20, 30}, {"South", 40, 50}},
+	}, "Sales")
+	file, openErr := excelize.OpenFile(filePath)
+	if openErr != nil {
+		t.Fatalf("Error opening test workbook: %v", openErr)
+	}
+	for _, merged := range [][2]string{{"A2", "A3"}, {"B2", "C2"}} {
+		if err := file.MergeCell("Sales", merged[0], merged[1]); err != nil {
+			t.Fatalf("Error merging test cells: %v", err)
+		}
+	}
+	if err := file.Save(); err != nil {
+		t.Fatalf("Error saving test workbook: %v", err)
+	}
+	_ = file.Close()
+
+	tests := []struct {
+		name string
+		opts ReadOptions
+		want string
+	}{
+		{name: "Top Left Cell", want: "<DataTable>\n" +
+			"  <Row Region=\"North\" Q1=\"10\" Q2=\"\"></Row>\n" +
+			"  <Row Region=\"\" Q1=\"20\" Q2=\"30\"></Row>\n" +
+			"  <Row Region=\"South\" Q1=\"40\" Q2=\"50\"></Row>\n" +
+			"</DataTable>"},
+		{name: "Resolved", opts: ReadOptions{ResolveMergedCells: true}, want: "<DataTable>\n" +
+			"  <Row Region=\"North\" Q1=\"10\" Q2=\"10\"></Row>\n" +
+			"  <Row Region=\"North\" Q1=\"20\" Q2=\"30\"></Row>\n" +
+			"  <Row Region=\"South\" Q1=\"40\" Q2=\"50\"></Row>\n" +
+			"</DataTable>"},
+	}
+	for _, tt := range tests {
+		for _, streaming := range []bool{false, true} {
+			t.Run(fmt.Sprintf("%s Streaming %v", tt.name, streaming), func(t *testing.T) {
+				var output bytes.Buffer
+				opts := Options{ReadOptions: tt.opts, Xml: XmlOptions{Attributes: true}, Streaming: streaming}
+				if err := XlsxToXml(context.Background(), &output, filePath, opts); err != nil {
+					t.Fatalf("XlsxToXml() error = %v", err)
+				}
+				if output.String() != tt.want {
+					t.Errorf("XlsxToXml() output = \n%s\nwant:\n%s", output.String(), tt.want)
+				}
+			})
+		}
+	}
+}
+
+func TestXlsxToXmlFormulas(t *testing.T) {
+	filePath := createTestWorkbook(t, map[string][][]any{
+		"Orders": {{"Item", "Qty", "Price", "Total"}, {"Pen", 2, 3, 6}, {"Ink", 4, 5}},
+	}, "Orders")
+	file, openErr := excelize.OpenFile(filePath)
+	if openErr != nil {
+		t.Fatalf("Error opening test workbook: %v", openErr)
+	}
+	// D2 keeps the value written before its formula as the cached result, D3 has never been calculated.
+	for cell, formula := range map[string]string{"D2": "B2*C2", "D3": "B3*C3"} {
+		if err := file.SetCellFormula("Orders", cell, formula); err != nil {
+			t.Fatalf("Error setting test formula: %v", err)
+		}
+	}
+	if err := file.Save(); err != nil {
+		t.Fatalf("Error saving test workbook: %v", err)
+	}
+	_ = file.Close()
+
+	tests := []struct {
+		name string
+		mode FormulaMode
+		want string
+	}{
+		{name: "Cached Value", mode: CachedFormulaValue, want: "<DataTable>\n" +
+			"  <Row Item=\"Pen\" Qty=\"2\" Price=\"3\" Total=\"6\"></Row>\n" +
+			"  <Row Item=\"Ink\" Qty=\"4\" Price=\"5\" Total=\"\"></Row>\n" +
+			"</DataTable>"},
+		{name: "Formula Text", mode: FormulaText, want: "<DataTable>\n" +
+			"  <Row Item=\"Pen\" Qty=\"2\" Price=\"3\" Total=\"=B2*C2\"></Row>\n" +
+			"  <Row Item=\"Ink\" Qty=\"4\" Price=\"5\" Total=\"=B3*C3\"></Row>\n" +
+			"</DataTable>"},
+		{name: "Evaluated", mode: EvaluatedFormula, want: "<DataTable>\n" +
+			"  <Row Item=\"Pen\" Qty=\"2\" Price=\"3\" Total=\"6\"></Row>\n" +
+			"  <Row Item=\"Ink\" Qty=\"4\" Price=\"5\" Total=\"20\"></Row>\n" +
+			"</DataTable>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output bytes.Buffer
+			opts := Options{ReadOptions: ReadOptions{Formulas: tt.mode}, Xml: XmlOptions{Attributes: true}}
+			if err := XlsxToXml(context.Background(), &output, filePath, opts); err != nil {
+				t.Fatalf("XlsxToXml() error = %v", err)
+			}
+			if output.String() != tt.want {
+				t.Errorf("XlsxToXml() output = \n%s\nwant:\n%s", output.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestXlsxToXmlComments(t *testing.T) {
+	filePath := createTestWorkbook(t, map[string][][]any{
+		"Audit": {{"Name", "Amount"}, {"Ann", 10}, {"Bob", 20}},
+	}, "Audit")
+	file, openErr := excelize.OpenFile(filePath)
+	if openErr != nil {
+		t.Fatalf("Error opening test workbook: %v", openErr)
+	}
+	comments := []excelize.Comment{
+		{Cell: "B1", Author: "Reviewer", Text: "Header note"},
+		{Cell: "B3", Author: "Reviewer", Paragraph: []excelize.RichTextRun{{Text: "Reviewer:"}, {Text: "\nCheck this"}}},
+	}
+	for _, comment := range comments {
+		if err := file.AddComment("Audit", comment); err != nil {
+			t.Fatalf("Error adding test comment: %v", err)
+		}
+	}
+	if err := file.Save(); err != nil {
+		t.Fatalf("Error saving test workbook: %v", err)
+	}
+	_ = file.Close()
+
+	tests := []struct {
+		name string
+		xml  XmlOptions
+		want string
+	}{
+		{name: "Elements", xml: XmlOptions{Comments: CommentElements}, want: "<DataTable>\n" +
+			"  <Row>\n    <Name>Ann</Name>\n    <Amount>10</Amount>\n    <Amount_comment></Amount_comment>\n  </Row>\n" +
+			"  <Row>\n    <Name>Bob</Name>\n    <Amount>20</Amount>\n    <Amount_comment>Check this</Amount_comment>\n  </Row>\n" +
+			"</DataTable>"},
+		{name: "Row Attributes", xml: XmlOptions{Comments: CommentElements, Attributes: true}, want: "<DataTable>\n" +
+			"  <Row Name=\"Ann\" Amount=\"10\" Amount_comment=\"\"></Row>\n" +
+			"  <Row Name=\"Bob\" Amount=\"20\" Amount_comment=\"Check this\"></Row>\n" +
+			"</DataTable>"},
+		{name: "Attributes", xml: XmlOptions{Comments: CommentAttributes}, want: "<DataTable>\n" +
+			"  <Row>\n    <Name>Ann</Name>\n    <Amount>10</Amount>\n  </Row>\n" +
+			"  <Row>\n    <Name>Bob</Name>\n    <Amount comment=\"Check this\">20</Amount>\n  </Row>\n" +
+			"</DataTable>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output bytes.Buffer
+			if err := XlsxToXml(context.Background(), &output, filePath, Options{Xml: tt.xml}); err != nil {
+				t.Fatalf("XlsxToXml() error = %v", err)
+			}
+			if output.String() != tt.want {
+				t.Errorf("XlsxToXml() output = \n%s\nwant:\n%s", output.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestXlsxToXmlLinks(t *testing.T) {
+	filePath := createTestWorkbook(t, map[string][][]any{
+		"Sites": {{"Name", "Site"}, {"Docs", "Read the docs"}, {"Plain", "No link"}, {"Summary", "Totals"}},
+	}, "Sites")
+	file, openErr := excelize.OpenFile(filePath)
+	if openErr != nil {
+		t.Fatalf("Error opening test workbook: %v", openErr)
+	}
+	if err := file.SetCellHyperLink("Sites", "B2", "https://example.com/docs", "External"); err != nil {
+		t.Fatalf("Error adding test hyperlink: %v", err)
+	}
+	if err := file.SetCellHyperLink("Sites", "B4", "Sites!A1", "Location"); err != nil {
+		t.Fatalf("Error adding test hyperlink: %v", err)
+	}
+	if err := file.Save(); err != nil {
+		t.Fatalf("Error saving test workbook: %v", err)
+	}
+	_ = file.Close()
+
+	var output bytes.Buffer
+	opts := Options{Xml: XmlOptions{LinkColumns: []string{"Site"}, Attributes: true}}
+	if err := XlsxToXml(context.Background(), &output, filePath, opts); err != nil {
+		t.Fatalf("XlsxToXml() error = %v", err)
+	}
+	want := "<DataTable>\n" +
+		"  <Row Name=\"Docs\" Site=\"Read the docs\" Site_link=\"https://example.com/docs\"></Row>\n" +
+		"  <Row Name=\"Plain\" Site=\"No link\" Site_link=\"\"></Row>\n" +
+		"  <Row Name=\"Summary\" Site=\"Totals\" Site_link=\"Sites!A1\"></Row>\n" +
+		"</DataTable>"
+	if output.String() != want {
+		t.Errorf("XlsxToXml() output = \n%s\nwant:\n%s", output.String(), want)
+	}
+}
+
+func TestXlsxToXmlRichText(t *testing.T) {
+	filePath := createTestWorkbook(t, map[string][][]any{
+		"Notes": {{"Id", "Note"}, {1, ""}, {2, "Plain"}},
+	}, "Notes")
+	file, openErr := excelize.OpenFile(filePath)
+	if openErr != nil {
+		t.Fatalf("Error opening test workbook: %v", openErr)
+	}
+	runs := []excelize.RichTextRun{
+		{Text: "Due "},
+		{Text: "today ", Font: &excelize.Font{Bold: true, Color: "FF0000"}},
+		{Text: "or", Font: &excelize.Font{Italic: true, Strike: true}},
+		{Text: " never"},
+	}
+	if err := file.SetCellRichText("Notes", "B2", runs); err != nil {
+		t.Fatalf("Error writing test rich text: %v", err)
+	}
+	if err := file.Save(); err != nil {
+		t.Fatalf("Error saving test workbook: %v", err)
+	}
+	_ = file.Close()
+
+	tests := []struct {
+		name string
+		mode RichTextMode
+		want string
+	}{
+		{name: "Plain", want: "<DataTable>\n" +
+			"  <Row>\n    <Id>1</Id>\n    <Note>Due today or never</Note>\n  </Row>\n" +
+			"  <Row>\n    <Id>2</Id>\n    <Note>Plain</Note>\n  </Row>\n" +
+			"</DataTable>"},
+		{name: "Markdown", mode: MarkdownRichText, want: "<DataTable>\n" +
+			"  <Row>\n    <Id>1</Id>\n    <Note>Due **today** ~~_or_~~ never</Note>\n  </Row>\n" +
+			"  <Row>\n    <Id>2</Id>\n    <Note>Plain</Note>\n  </Row>\n" +
+			"</DataTable>"},
+		{name: "Xml", mode: XmlRichText, want: "<DataTable>\n" +
+			"  <Row>\n    <Id>1</Id>\n    <Note>\n" +
+			"      <Run>Due </Run>\n" +
+			"      <Run bold=\"true\" color=\"FF0000\">today </Run>\n" +
+			"      <Run italic=\"true\" strike=\"true\">or</Run>\n" +
+			"      <Run> never</Run>\n" +
+			"    </Note>\n  </Row>\n" +
+			"  <Row>\n    <Id>2</Id>\n    <Note>Plain</Note>\n  </Row>\n" +
+			"</DataTable>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output bytes.Buffer
+			if err := XlsxToXml(context.Background(), &output, filePath, Options{ReadOptions: ReadOptions{RichText: tt.mode}}); err != nil {
+				t.Fatalf("XlsxToXml() error = %v", err)
+			}
+			if output.String() != tt.want {
+				t.Errorf("XlsxToXml() output = \n%s\nwant:\n%s", output.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestXlsxToXmlEmptyCells(t *testing.T) {
+	filePath := createTestWorkbook(t, map[string][][]any{
+		"People": {{"Name", "Age"}, {"Ann", ""}, {"", 42}},
+	}, "People")
+	tests := []struct {
+		name string
+		xml  XmlOptions
+		want string
+	}{
+		{name: "Empty Elements", want: "<DataTable>\n" +
+			"  <Row>\n    <Name>Ann</Name>\n    <Age></Age>\n  </Row>\n" +
+			"  <Row>\n    <Name></Name>\n    <Age>42</Age>\n  </Row>\n" +
+			"</DataTable>"},
+		{name: "Omitted", xml: XmlOptions{EmptyCells: OmitEmpty}, want: "<DataTable>\n" +
+			"  <Row>\n    <Name>Ann</Name>\n  </Row>\n" +
+			"  <Row>\n    <Age>42</Age>\n  </Row>\n" +
+			"</DataTable>"},
+		{name: "Omitted Attributes", xml: XmlOptions{EmptyCells: OmitEmpty, Attributes: true}, want: "<DataTable>\n" +
+			"  <Row Name=\"Ann\"></Row>\n" +
+			"  <Row Age=\"42\"></Row>\n" +
+			"</DataTable>"},
+		{name: "Nil", xml: XmlOptions{EmptyCells: NilEmpty}, want: "<DataTable xmlns:xsi=\"http://www.w3.org/2001/XMLSchema-instance\">\n" +
+			"  <Row>\n    <Name>Ann</Name>\n    <Age xsi:nil=\"true\"></Age>\n  </Row>\n" +
+			"  <Row>\n    <Name xsi:nil=\"true\"></Name>\n    <Age>42</Age>\n  </Row>\n" +
+			"</DataTable>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output bytes.Buffer
+			if err := XlsxToXml(context.Background(), &output, filePath, Options{Xml: tt.xml}); err != nil {
+				t.Fatalf("XlsxToXml() error = %v", err)
+			}
+			if output.String() != tt.want {
+				t.Errorf("XlsxToXml() output = \n%s\nwant:\n%s", output.String(), tt.want)
+			}
+		})
+	}
+	var output bytes.Buffer
+	if err := XlsxToXml(context.Background(), &output, filePath, Options{Xml: XmlOptions{EmptyCells: NilEmpty, Attributes: true}}); err == nil {
+		t.Errorf("XlsxToXml() accepted nil cells in attribute mode")
+	}
+}
+
+func TestXlsxToXmlColumnOrder(t *testing.T) {
+	filePath := createTestWorkbook(t, map[string][][]any{
+		"People": {{"Name", "age", "City", "Id"}, {"Ann", 31, "Oslo", 1}},
+	}, "People")
+	tests := []struct {
+		name string
+		opts ReadOptions
+		want string
+	}{
+		{name: "Sheet Order", want: `<Row Name="Ann" age="31" City="Oslo" Id="1"></Row>`},
+		{name: "Alphabetical", opts: ReadOptions{ColumnOrder: ColumnOrder{Mode: AlphabeticalOrder}},
+			want: `<Row age="31" City="Oslo" Id="1" Name="Ann"></Row>`},
+		{name: "Explicit", opts: ReadOptions{ColumnOrder: ColumnOrder{Mode: ExplicitOrder, Names: []string{"Id", "Missing", "City"}}},
+			want: `<Row Id="1" City="Oslo" Name="Ann" age="31"></Row>`},
+		{name: "Filtered", opts: ReadOptions{
+			Columns:     ColumnFilter{Exclude: []string{"City"}},
+			ColumnOrder: ColumnOrder{Mode: ExplicitOrder, Names: []string{"Id"}},
+		}, want: `<Row Id="1" Name="Ann" age="31"></Row>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output bytes.Buffer
+			if err := XlsxToXml(context.Background(), &output, filePath, Options{ReadOptions: tt.opts, Xml: XmlOptions{Attributes: true}}); err != nil {
+				t.Fatalf("XlsxToXml() error = %v", err)
+			}
+			want := "<DataTable>\n  " + tt.want + "\n</DataTable>"
+			if output.String() != want {
+				t.Errorf("XlsxToXml() output = \n%s\nwant:\n%s", output.String(), want)
+			}
+		})
+	}
+}
+
+func TestXlsxToXmlFormat(t *testing.T) {
+	filePath := createTestWorkbook(t, map[string][][]any{
+		"People": {{"Name", "Age"}, {"Ann", 31}},
+	}, "People")
+	tests := []struct {
+		name string
+		xml  XmlOptions
+		want string
+	}{
+		{name: "Compact", xml: XmlOptions{Compact: true},
+			want: "<DataTable><Row><Name>Ann</Name><Age>31</Age></Row></DataTable>"},
+		{name: "Tabs", xml: XmlOptions{Indent: "\t"},
+			want: "<DataTable>\n\t<Row>\n\t\t<Name>Ann</Name>\n\t\t<Age>31</Age>\n\t</Row>\n</DataTable>"},
+		{name: "CRLF", xml: XmlOptions{Newline: "\r\n", Attributes: true},
+			want: "<DataTable>\r\n  <Row Name=\"Ann\" Age=\"31\"></Row>\r\n</DataTable>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, streaming := range []bool{false, true} {
+				var output bytes.Buffer
+				if err := XlsxToXml(context.Background(), &output, filePath, Options{Streaming: streaming, Xml: tt.xml}); err != nil {
+					t.Fatalf("XlsxToXml() error = %v", err)
+				}
+				if output.String() != tt.want {
+					t.Errorf("XlsxToXml() streaming %v output = %q, want %q", streaming, output.String(), tt.want)
+				}
+			}
+		})
+	}
+	var output bytes.Buffer
+	if err := XlsxToXml(context.Background(), &output, filePath, Options{Xml: XmlOptions{Indent: "--"}}); err == nil {
+		t.Errorf("XlsxToXml() accepted a non-whitespace indentation")
+	}
+}
+
+func TestXlsxToXmlEncoding(t *testing.T) {
+	filePath := createTestWorkbook(t, map[string][][]any{
+		"Prices": {{"Item", "Note"}, {"Café", "5 €"}},
+	}, "Prices")
+	tests := []struct {
+		name   string
+		xml    XmlOptions
+		decode *encoding.Decoder
+		want   string
+	}{
+		{name: "Latin-1", xml: XmlOptions{Encoding: "latin1", Attributes: true}, decode: charmap.ISO8859_1.NewDecoder(),
+			want: "<?xml version=\"1.0\" encoding=\"ISO-8859-1\"?>\n" +
+				"<DataTable>\n  <Row Item=\"Café\" Note=\"5 &#x20AC;\"></Row>\n</DataTable>"},
+		{name: "Latin-1 CDATA", xml: XmlOptions{Encoding: "ISO-8859-1", CDataColumns: []string{"Note"}, Compact: true},
+			decode: charmap.ISO8859_1.NewDecoder(),
+			want: "<?xml version=\"1.0\" encoding=\"ISO-8859-1\"?>" +
+				"<DataTable><Row><Item>Café</Item><Note><![CDATA[5 ]]>&#x20AC;<![CDATA[]]></Note></Row></DataTable>"},
+		{name: "UTF-16", xml: XmlOptions{Encoding: "UTF-16", Compact: true},
+			decode: unicode.UTF16(unicode.LittleEndian, unicode.ExpectBOM).NewDecoder(),
+			want: "<?xml version=\"1.0\" encoding=\"UTF-16\"?>" +
+				"<DataTable><Row><Item>Café</Item><Note>5 €</Note></Row></DataTable>"},
+		{name: "UTF-8 BOM", xml: XmlOptions{ByteOrderMark: true, Declaration: true, Compact: true},
+			want: "\uFEFF<?xml version=\"1.0\" encoding=\"UTF-8\"?>" +
+				"<DataTable><Row><Item>Café</Item><Note>5 €</Note></Row></DataTable>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, streaming := range []bool{false, true} {
+				var output bytes.Buffer
+				if err := XlsxToXml(context.Background(), &output, filePath, Options{Streaming: streaming, Xml: tt.xml}); err != nil {
+					t.Fatalf("XlsxToXml() error = %v", err)
+				}
+				got := output.Bytes()
+				if tt.decode != nil {
+					var decodeErr error
+					if got, decodeErr = tt.decode.Bytes(got); decodeErr != nil {
+						t.Fatalf("Error decoding output: %v", decodeErr)
+					}
+				}
+				if string(got) != tt.want {
+					t.Errorf("XlsxToXml() streaming %v output = %q, want %q", streaming, got, tt.want)
+				}
+			}
+		})
+	}
+	var output bytes.Buffer
+	if err := XlsxToXml(context.Background(), &output, filePath, Options{Xml: XmlOptions{Encoding: "EBCDIC"}}); err == nil {
+		t.Errorf("XlsxToXml() accepted an unsupported encoding")
+	}
+}
+
+func TestXlsxToXmlProgress(t *testing.T) {
+	filePath := createTestWorkbook(t, map[string][][]any{
+		"People": {{"Name"}, {"Ann"}, {"Bob"}, {"Cid"}, {"Dan"}},
+		"Orders": {{"Id"}, {1}, {2}},
+	}, "People", "Orders")
+
+	tests := []struct {
+		name string
+		opts Options
+		want [][2]int
+	}{
+		{name: "Single Sheet", opts: Options{ProgressInterval: 2}, want: [][2]int{{2, 5}, {4, 5}, {5, 5}}},
+		{name: "Streamed Sheets", opts: Options{Sheets: AllSheets(), Combine: true, Streaming: true, ProgressInterval: 4},
+			want: [][2]int{{4, 8}, {8, 8}}},
+		{name: "Default Interval", opts: Options{}, want: [][2]int{{5, 5}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got [][2]int
+			tt.opts.Progress = func(done, total int) {
+				got = append(got, [2]int{done, total})
+			}
+			var output bytes.Buffer
+			if err := XlsxToXml(context.Background(), &output, filePath, tt.opts); err != nil {
+				t.Fatalf("XlsxToXml() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Progress reports = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestXlsxToXmlCancelled(t *testing.T) {
+	filePath := createTestWorkbook(t, map[string][][]any{
+		"People": {{"Name"}, {"Ann"}, {"Bob"}, {"Cid"}},
+	}, "People")
+
+	for _, streaming := range []bool{false, true} {
+		ctx, cancel := context.WithCancel(context.Background())
+		var read []int
+		opts := Options{Streaming: streaming, ProgressInterval: 1, Progress: func(done, total int) {
+			read = append(read, done)
+			if done == 2 {
+				cancel()
+			}
+		}}
+		var output bytes.Buffer
+		if err := XlsxToXml(ctx, &output, filePath, opts); !errors.Is(err, context.Canceled) {
+			t.Errorf("XlsxToXml() streaming %v error = %v, want %v", streaming, err, context.Canceled)
+		}
+		if !reflect.DeepEqual(read, []int{1, 2}) {
+			t.Errorf("XlsxToXml() streaming %v read rows %v after cancellation, want [1 2]", streaming, read)
+		}
+		cancel()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 0)
+	defer cancel()
+	if _, err := XlsxToXmlFiles(ctx, t.TempDir(), filePath, Options{ChunkSize: 2}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("XlsxToXmlFiles() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestXlsxToXmlErrorReport(t *testing.T) {
+	filePath := createTestWorkbook(t, map[string][][]any{
+		"People": {
+			{"Name", "Joined", "Age"},
+			{"Ann", "13/45/20", "x"},
+			{"Bob", "01/02/20", 42, "stray"},
+		},
+	}, "People")
+	opts := Options{ErrorReport: JsonReport, Xml: XmlOptions{ColumnTypes: map[string]CellType{"Age": IntegerCell}}}
+
+	var output, report bytes.Buffer
+	opts.ReportOutput = &report
+	if err := XlsxToXml(context.Background(), &output, filePath, opts); err != nil {
+		t.Fatalf("XlsxToXml() error = %v", err)
+	}
+	var got struct {
+		Source   string    `json:"source"`
+		Problems []Problem `json:"problems"`
+	}
+	if err := json.Unmarshal(report.Bytes(), &got); err != nil {
+		t.Fatalf("Error parsing report: %v\n%s", err, report.String())
+	}
+	want := []Problem{
+		{Sheet: "People", Row: 2, Cell: "B2", Column: "Joined", Kind: DateProblem, Value: "13/45/20",
+			Message: "value looks like a date but is not a valid date"},
+		{Sheet: "People", Row: 2, Cell: "C2", Column: "Age", Kind: TypeProblem, Value: "x",
+			Message: "value is not a valid xs:long and is written without a type"},
+		{Sheet: "People", Row: 3, Cell: "D3", Kind: TruncatedProblem, Value: "stray",
+			Message: "value is beyond the last header column and is left out"},
+	}
+	if got.Source != "TestWorkbook.xlsx" || !reflect.DeepEqual(got.Problems, want) {
+		t.Errorf("Report = %+v, want %+v", got, want)
+	}
+	characters := opts.newProblemReport()
+	characters.check("People", 3, 0, "Name", "B\x01b", "B\x01b")
+	if len(characters.problems) != 1 || characters.problems[0].Kind != CharacterProblem || characters.problems[0].Cell != "A3" {
+		t.Errorf("Problems of a control character = %+v, want a character problem in A3", characters.problems)
+	}
+
+	opts.ErrorReport, opts.ReportOutput = NoReport, nil
+	if err := XlsxToXml(context.Background(), &output, filePath, opts); err == nil || !strings.Contains(err.Error(), "D3") {
+		t.Errorf("XlsxToXml() without report error = %v, want the cell beyond the header", err)
+	}
+
+	dir := t.TempDir()
+	written, err := XlsxToXmlFiles(context.Background(), dir, filePath, Options{ErrorReport: CsvReport})
+	if err != nil {
+		t.Fatalf("XlsxToXmlFiles() error = %v", err)
+	}
+	wantFiles := []string{filepath.Join(dir, "People.xml"), filepath.Join(dir, "TestWorkbook_errors.csv")}
+	if !reflect.DeepEqual(written, wantFiles) {
+		t.Fatalf("XlsxToXmlFiles() written = %v, want %v", written, wantFiles)
+	}
+	content, readErr := os.ReadFile(written[1])
+	if readErr != nil {
+		t.Fatalf("Error reading report: %v", readErr)
+	}
+	if lines := strings.Split(strings.TrimSpace(string(content)), "\n"); len(lines) != 3 || lines[0] != "sheet,row,cell,column,kind,value,message" {
+		t.Errorf("CSV report = %q, want a header and 2 problems", content)
+	}
+}
+
+func TestXlsxToXmlCData(t *testing.T) {
+	filePath := createTestWorkbook(t, map[string][][]any{
+		"Notes": {{"Id", "Body", "Title"}, {1, "<b>bold</b> & more", "Plain"}, {2, "a]]>b", ""}},
+	}, "Notes")
+	opts := Options{Xml: XmlOptions{CDataColumns: []string{"Title"}, AutoCData: true, InferTypes: true}}
+	var output bytes.Buffer
+	if err := XlsxToXml(context.Background(), &output, filePath, opts); err != nil {
+		t.Fatalf("XlsxToXml() error = %v", err)
+	}
+	want := "<DataTable xmlns:xsi=\"http://www.w3.org/2001/XMLSchema-instance\" xmlns:xs=\"http://www.w3.org/2001/XMLSchema\">\n" +
+		"  <Row>\n" +
+		"    <Id xsi:type=\"xs:long\">1</Id>\n" +
+		"    <Body xsi:type=\"xs:string\"><![CDATA[<b>bold</b> & more]]></Body>\n" +
+		"    <Title xsi:type=\"xs:string\"><![CDATA[Plain]]></Title>\n" +
+		"  </Row>\n" +
+		"  <Row>\n" +
+		"    <Id xsi:type=\"xs:long\">2</Id>\n" +
+		"    <Body xsi:type=\"xs:string\"><![CDATA[a]]]]><![CDATA[>b]]></Body>\n" +
+		"    <Title></Title>\n" +
+		"  </Row>\n" +
+		"</DataTable>"
+	if output.String() != want {
+		t.Errorf("XlsxToXml() output = \n%s\nwant:\n%s", output.String(), want)
+	}
+	opts.Xml.Attributes = true
+	if err := XlsxToXml(context.Background(), &output, filePath, opts); err == nil {
+		t.Errorf("XlsxToXml() accepted CDATA sections in attribute mode")
+	}
+}
+
+func TestXlsxToXmlNamespaces(t *testing.T) {
+	filePath := createTestWorkbook(t, map[string][][]any{
+		"Orders": {{"Id", "Amount"}, {1, 9.99}},
+	}, "Orders")
+	opts := Options{Xml: XmlOptions{
+		Namespace:      "urn:example:orders",
+		Namespaces:     map[string]string{"m": "urn:example:money"},
+		ColumnPrefixes: map[string]string{"Amount": "m"},
+	}}
+	var output bytes.Buffer
+	if err := XlsxToXml(context.Background(), &output, filePath, opts); err != nil {
+		t.Fatalf("XlsxToXml() error = %v", err)
+	}
+	want := "<DataTable xmlns=\"urn:example:orders\" xmlns:m=\"urn:example:money\">\n" +
+		"  <Row>\n" +
+		"    <Id>1</Id>\n" +
+		"    <m:Amount>9.99</m:Amount>\n" +
+		"  </Row>\n" +
+		"</DataTable>"
+	if output.String() != want {
+		t.Errorf("XlsxToXml() output = \n%s\nwant:\n%s", output.String(), want)
+	}
+	opts.Xml.ColumnPrefixes = map[string]string{"Amount": "undeclared"}
+	if err := XlsxToXml(context.Background(), &output, filePath, opts); err == nil {
+		t.Errorf("XlsxToXml() accepted a column in an undeclared namespace")
+	}
+}
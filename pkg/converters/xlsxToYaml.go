@@ -0,0 +1,235 @@
+package converters
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"GoTools/pkg/datatable"
+	. "GoTools/pkg/helpers"
+	"github.com/xuri/excelize/v2"
+)
+
+// YamlLayout selects how the records of a worksheet are laid out in YAML.
+type YamlLayout int
+
+const (
+	// ListLayout writes a sequence holding a mapping per record, keyed by column name. It is the default.
+	ListLayout YamlLayout = iota
+	// KeyedLayout writes a mapping from the value of the key column of every record to a mapping of its other
+	// columns, which suits configuration keyed by name. Key values must be unique and not empty.
+	KeyedLayout
+)
+
+// ParseYamlLayout returns the YamlLayout called list or keyed.
+func ParseYamlLayout(name string) (YamlLayout, error) {
+	switch name {
+	case "", "list":
+		return ListLayout, nil
+	case "keyed":
+		return KeyedLayout, nil
+	}
+	return ListLayout, fmt.Errorf("unknown YAML layout '%s'", name)
+}
+
+// YamlOptions configures the conversion of worksheets into YAML.
+// Sheets selects the worksheets to convert. Every worksheet is written as a document of its own, the documents
+// being separated by "---" lines, unless Combine writes them into a single mapping keyed by worksheet name.
+// Layout selects between a sequence of records and a mapping keyed by KeyColumn, the first column by default.
+// The keys are the names in the header cells, with duplicates renamed as in the XML conversion.
+// InferTypes writes numbers, booleans and dates as YAML numbers, booleans and timestamps in their canonical form,
+// and empty cells as null; every value is a string otherwise, quoted whenever YAML would read it as another type.
+// OmitEmpty leaves out the columns of a record whose cell is empty.
+// The embedded ReadOptions control how the rows of every worksheet are read.
+type YamlOptions struct {
+	ReadOptions
+	Sheets     SheetSelector
+	Combine    bool
+	Layout     YamlLayout
+	KeyColumn  string
+	InferTypes bool
+	OmitEmpty  bool
+}
+
+// XlsxToYaml converts the worksheets of the .xlsx file at path selected by opts.Sheets and writes the YAML to w.
+// Legacy .xls workbooks and OpenDocument spreadsheets (.ods) are read as well.
+// The conversion stops with the error of ctx as soon as it is cancelled or its deadline passes.
+func XlsxToYaml(ctx context.Context, w io.Writer, path string, opts YamlOptions) (convertErr error) {
+	if optsErr := opts.ReadOptions.validate(); optsErr != nil {
+		return optsErr
+	}
+	file, openErr := openWorkbook(ctx, path, opts.ReadOptions)
+	if openErr != nil {
+		return openErr
+	}
+	defer func(file *excelize.File) {
+		if err := file.Close(); err != nil && convertErr == nil {
+			convertErr = err
+		}
+	}(file)
+
+	sheets, selectErr := opts.selectSheets(file, opts.Sheets)
+	if selectErr != nil {
+		return selectErr
+	}
+	writer := bufio.NewWriter(w)
+	for index, sheet := range sheets {
+		indent := ""
+		switch {
+		case opts.Combine:
+			indent = defaultIndent
+			if _, err := writer.WriteString(yamlString(sheet) + ":"); err != nil {
+				return err
+			}
+		case index > 0:
+			if _, err := writer.WriteString("---\n"); err != nil {
+				return err
+			}
+		}
+		if err := writeYamlSheet(ctx, writer, file, sheet, opts, indent); err != nil {
+			return fmt.Errorf("worksheet '%s': %w", sheet, err)
+		}
+	}
+	return writer.Flush()
+}
+
+// writeYamlSheet writes the records of the named worksheet to w in the configured layout, indenting every line
+// by indent. A nested sheet, one with an indent, starts on the line of its key.
+func writeYamlSheet(ctx context.Context, w *bufio.Writer, file *excelize.File, sheet string, opts YamlOptions, indent string) error {
+	reader, readerErr := newSheetReader(file, sheet, opts.ReadOptions)
+	if readerErr != nil {
+		return readerErr
+	}
+	var header []string
+	keyIndex := 0
+	onHeader := func(columns []string, _ []int) error {
+		header = RenameDuplicates(columns, false)
+		if opts.Layout != KeyedLayout {
+			return nil
+		}
+		if len(opts.KeyColumn) > 0 {
+			if keyIndex = slices.Index(header, opts.KeyColumn); keyIndex < 0 {
+				return fmt.Errorf("unknown key column '%s'", opts.KeyColumn)
+			}
+		}
+		if len(header) < 1 {
+			return errors.New("a keyed layout needs a key column")
+		}
+		return nil
+	}
+	keys := make(map[string]bool)
+	written := 0
+	rowErr := reader.eachRecord(ctx, onHeader, func(rowNumber int, columns []string) error {
+		var record strings.Builder
+		if written == 0 && len(indent) > 0 {
+			record.WriteString("\n")
+		}
+		fieldIndent := indent + defaultIndent
+		if opts.Layout == KeyedLayout {
+			key := cellAt(columns, keyIndex)
+			if len(key) < 1 {
+				return fmt.Errorf("row %d has no key", rowNumber)
+			}
+			if keys[key] {
+				return fmt.Errorf("row %d repeats the key '%s'", rowNumber, key)
+			}
+			keys[key] = true
+			record.WriteString(indent + yamlString(key) + ":")
+		} else {
+			record.WriteString(indent + "-")
+		}
+		fields := 0
+		for columnIndex, name := range header {
+			value := ConvertToISO8601(cellAt(columns, columnIndex))
+			if (opts.Layout == KeyedLayout && columnIndex == keyIndex) || (opts.OmitEmpty && len(value) < 1) {
+				continue
+			}
+			switch {
+			case opts.Layout == KeyedLayout:
+				record.WriteString("\n" + fieldIndent)
+			case fields > 0:
+				record.WriteString(indent + defaultIndent)
+			default:
+				record.WriteString(" ")
+			}
+			record.WriteString(yamlString(name) + ": " + opts.yamlValue(value))
+			if opts.Layout != KeyedLayout {
+				record.WriteString("\n")
+			}
+			fields++
+		}
+		switch {
+		case fields == 0:
+			record.WriteString(" {}\n")
+		case opts.Layout == KeyedLayout:
+			record.WriteString("\n")
+		}
+		written++
+		_, err := w.WriteString(record.String())
+		return err
+	})
+	if rowErr != nil {
+		return rowErr
+	}
+	if written > 0 {
+		return nil
+	}
+	empty := " []\n"
+	if opts.Layout == KeyedLayout {
+		empty = " {}\n"
+	}
+	if len(indent) < 1 {
+		empty = empty[1:]
+	}
+	_, err := w.WriteString(empty)
+	return err
+}
+
+// cellAt returns the cell at columnIndex of a record, missing trailing cells being empty.
+func cellAt(columns []string, columnIndex int) string {
+	if columnIndex < len(columns) {
+		return columns[columnIndex]
+	}
+	return ""
+}
+
+// yamlValue returns the YAML scalar of a cell value, typed when InferTypes is set.
+func (o YamlOptions) yamlValue(value string) string {
+	if !o.InferTypes {
+		return yamlString(value)
+	}
+	if len(value) < 1 {
+		return "null"
+	}
+	cellType := datatable.InferCellType(value)
+	canonical, _ := cellType.Canonical(value)
+	switch cellType {
+	case IntegerCell, DecimalCell, DoubleCell, DateCell, DateTimeCell:
+		return canonical
+	case BooleanCell:
+		return strings.ToLower(value)
+	}
+	return yamlString(value)
+}
+
+// yamlResolvedPattern matches the plain scalars YAML 1.1 or 1.2 resolve to something other than a string:
+// booleans, nulls, numbers in any base or with separators, sexagesimals, timestamps and infinities.
+var yamlResolvedPattern = regexp.MustCompile(`(?i)^(y|yes|n|no|true|false|on|off|null|~|[-+]?\.(inf|nan)|[-+.]?[0-9][0-9a-fox_.:+\-eEtTzZ ]*)$`)
+
+// yamlString returns value as a YAML string, plain when YAML reads it back unchanged as a string
+// and double quoted otherwise.
+func yamlString(value string) string {
+	if len(value) < 1 || yamlResolvedPattern.MatchString(value) || strings.TrimSpace(value) != value ||
+		strings.ContainsRune("-?:,[]{}#&*!|>'\"%@`", rune(value[0])) ||
+		strings.Contains(value, ": ") || strings.Contains(value, " #") || strings.HasSuffix(value, ":") ||
+		strings.IndexFunc(value, func(r rune) bool { return !unicode.IsPrint(r) && r != ' ' }) >= 0 {
+		return jsonString(value)
+	}
+	return value
+}
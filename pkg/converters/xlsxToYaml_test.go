@@ -0,0 +1,97 @@
+package converters
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func TestXlsxToYaml(t *testing.T) {
+	filePath := createTestWorkbook(t, map[string][][]any{
+		"Services": {{"Name", "Port", "Enabled", "Note"}, {"api", 8080, "true", "a: b"}, {"db", "5432", "no", ""}},
+		"Empty":    {{"Name"}},
+	}, "Services", "Empty")
+
+	tests := []struct {
+		name string
+		opts YamlOptions
+		want string
+	}{
+		{
+			name: "List",
+			opts: YamlOptions{},
+			want: "- Name: api\n  Port: \"8080\"\n  Enabled: \"true\"\n  Note: \"a: b\"\n" +
+				"- Name: db\n  Port: \"5432\"\n  Enabled: \"no\"\n  Note: \"\"\n",
+		},
+		{
+			name: "Typed List",
+			opts: YamlOptions{InferTypes: true},
+			want: "- Name: api\n  Port: 8080\n  Enabled: true\n  Note: \"a: b\"\n" +
+				"- Name: db\n  Port: 5432\n  Enabled: \"no\"\n  Note: null\n",
+		},
+		{
+			name: "Keyed",
+			opts: YamlOptions{Layout: KeyedLayout, InferTypes: true, OmitEmpty: true},
+			want: "api:\n  Port: 8080\n  Enabled: true\n  Note: \"a: b\"\n" +
+				"db:\n  Port: 5432\n  Enabled: \"no\"\n",
+		},
+		{
+			name: "Documents",
+			opts: YamlOptions{Sheets: AllSheets(), Layout: KeyedLayout, KeyColumn: "Name", OmitEmpty: true},
+			want: "api:\n  Port: \"8080\"\n  Enabled: \"true\"\n  Note: \"a: b\"\n" +
+				"db:\n  Port: \"5432\"\n  Enabled: \"no\"\n" +
+				"---\n{}\n",
+		},
+		{
+			name: "Combined",
+			opts: YamlOptions{Sheets: AllSheets(), Combine: true, InferTypes: true, OmitEmpty: true},
+			want: "Services:\n  - Name: api\n    Port: 8080\n    Enabled: true\n    Note: \"a: b\"\n" +
+				"  - Name: db\n    Port: 5432\n    Enabled: \"no\"\n" +
+				"Empty: []\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output bytes.Buffer
+			if err := XlsxToYaml(context.Background(), &output, filePath, tt.opts); err != nil {
+				t.Fatalf("XlsxToYaml() error = %v", err)
+			}
+			if output.String() != tt.want {
+				t.Errorf("XlsxToYaml() output = \n%s\nwant:\n%s", output.String(), tt.want)
+			}
+		})
+	}
+
+	var output bytes.Buffer
+	if err := XlsxToYaml(context.Background(), &output, filePath, YamlOptions{Layout: KeyedLayout, KeyColumn: "Note"}); err == nil {
+		t.Errorf("XlsxToYaml() accepted an empty key")
+	}
+}
+
+func TestYamlString(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{value: "plain text", want: "plain text"},
+		{value: "1st floor", want: "1st floor"},
+		{value: "", want: `""`},
+		{value: "yes", want: `"yes"`},
+		{value: "0x1F", want: `"0x1F"`},
+		{value: "1_000", want: `"1_000"`},
+		{value: "12:30", want: `"12:30"`},
+		{value: "- item", want: `"- item"`},
+		{value: " padded", want: `" padded"`},
+		{value: "key: value", want: `"key: value"`},
+		{value: "two\nlines", want: `"two\nlines"`},
+		{value: "café", want: "café"},
+		{value: "note #1 x", want: `"note #1 x"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := yamlString(tt.value); got != tt.want {
+				t.Errorf("yamlString(%q) = %s, want %s", tt.value, got, tt.want)
+			}
+		})
+	}
+}
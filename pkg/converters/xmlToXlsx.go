@@ -0,0 +1,312 @@
+package converters
+
+import (
+	"bufio"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+	"golang.org/x/text/transform"
+)
+
+// XmlToXlsxOptions configures building a workbook from the records of an XML document.
+// Records selects the elements holding a record: an absolute path of element names from the root, such as
+// /DataTable/Row, or a path starting with // or without any leading slash, such as //Row, matched at any depth.
+// A * step matches any element and element names are matched without their namespace prefix.
+// When Records is empty the children of the root element are the records, and a combined Workbook document
+// written by XlsxToXml gets a worksheet per Sheet element, named after it.
+// Every attribute and child element of a record becomes a column, in the order they are first met, holding the text
+// of the child including that of its descendants; the attributes of a child go into a column named after the child
+// with a "_<attribute>" suffix, and repeated children are joined with "; ". Namespace declarations and xsi
+// attributes are skipped, so the typed and nil cells of a converted worksheet read back as their values.
+// SheetName names the worksheet of a document that is not a combined Workbook, after the XML file by default.
+// StyleHeader, AutoWidth and CoerceTypes work as in CsvToXlsxOptions.
+type XmlToXlsxOptions struct {
+	Records     string
+	SheetName   string
+	StyleHeader bool
+	AutoWidth   bool
+	CoerceTypes bool
+}
+
+// recordSelector matches the path of the elements holding a record.
+type recordSelector struct {
+	steps    []string
+	anywhere bool
+}
+
+// parseRecordSelector returns the selector of the XPath-like record path expr.
+func parseRecordSelector(expr string) (recordSelector, error) {
+	selector := recordSelector{anywhere: !strings.HasPrefix(expr, "/") || strings.HasPrefix(expr, "//")}
+	expr = strings.TrimPrefix(strings.TrimPrefix(expr, "/"), "/")
+	for _, step := range strings.Split(expr, "/") {
+		if _, local, prefixed := strings.Cut(step, ":"); prefixed {
+			step = local
+		}
+		if len(step) < 1 || strings.ContainsAny(step, "[]@()") {
+			return selector, fmt.Errorf("unsupported record path '%s', use element names, * and a leading / or //", expr)
+		}
+		selector.steps = append(selector.steps, step)
+	}
+	return selector, nil
+}
+
+// matches reports whether the element at the end of path holds a record.
+func (s recordSelector) matches(path []string) bool {
+	if len(path) < len(s.steps) || (!s.anywhere && len(path) != len(s.steps)) {
+		return false
+	}
+	path = path[len(path)-len(s.steps):]
+	for index, step := range s.steps {
+		if step != "*" && step != path[index] {
+			return false
+		}
+	}
+	return true
+}
+
+// XmlToXlsx builds the .xlsx workbook at outPath holding the records of the XML document at path.
+// Documents declaring a single-byte encoding such as ISO-8859-1 are decoded; UTF-16 documents are not supported.
+// The document is read twice, once to collect the columns and once to write the records, so it is never held in memory.
+// The conversion stops with the error of ctx as soon as it is cancelled or its deadline passes.
+func XmlToXlsx(ctx context.Context, outPath, path string, opts XmlToXlsxOptions) (convertErr error) {
+	if len(opts.Records) > 0 {
+		if _, err := parseRecordSelector(opts.Records); err != nil {
+			return err
+		}
+	}
+	file := excelize.NewFile()
+	defer func(file *excelize.File) {
+		if err := file.Close(); err != nil && convertErr == nil {
+			convertErr = err
+		}
+	}(file)
+
+	styles, stylesErr := newWorkbookStyles(file)
+	if stylesErr != nil {
+		return stylesErr
+	}
+	var layouts []*sheetLayout
+	onSheet := func(name string) error {
+		if len(name) < 1 {
+			name = opts.SheetName
+		}
+		if len(name) < 1 {
+			name = sheetNameFor(path, len(layouts), nil)
+		}
+		layouts = append(layouts, newSheetLayout(name))
+		return createSheet(file, len(layouts)-1, name)
+	}
+	layoutErr := opts.eachXmlRecord(ctx, path, onSheet, func(row flatRow) error {
+		layouts[len(layouts)-1].add(row, opts.AutoWidth)
+		return nil
+	})
+	if layoutErr != nil {
+		return layoutErr
+	}
+
+	var stream *excelize.StreamWriter
+	var layout *sheetLayout
+	var rowNumber int
+	sheetIndex := -1
+	onSheet = func(string) error {
+		if stream != nil {
+			if err := stream.Flush(); err != nil {
+				return err
+			}
+		}
+		sheetIndex++
+		layout, rowNumber = layouts[sheetIndex], 1
+		var streamErr error
+		if stream, streamErr = file.NewStreamWriter(layout.name); streamErr != nil {
+			return streamErr
+		}
+		return layout.writeHeader(stream, styles, opts.StyleHeader, opts.AutoWidth)
+	}
+	rowErr := opts.eachXmlRecord(ctx, path, onSheet, func(row flatRow) error {
+		rowNumber++
+		values := layout.values(row, func(value any) any {
+			text, _ := value.(string)
+			switch {
+			case len(text) < 1:
+				return nil
+			case opts.CoerceTypes:
+				return coerceCell(text, styles)
+			}
+			return text
+		})
+		cell, _ := excelize.CoordinatesToCellName(1, rowNumber)
+		return stream.SetRow(cell, values)
+	})
+	if rowErr != nil {
+		return rowErr
+	}
+	if stream != nil {
+		if err := stream.Flush(); err != nil {
+			return err
+		}
+	}
+	return file.SaveAs(outPath)
+}
+
+// eachXmlRecord hands the cells of every record of the XML document at path to onRecord. It calls onSheet
+// with the name of every Sheet of a combined Workbook before its records, or once with an empty name before
+// the records of any other document.
+func (o XmlToXlsxOptions) eachXmlRecord(ctx context.Context, path string, onSheet func(name string) error, onRecord func(row flatRow) error) (readErr error) {
+	xmlFile, openErr := os.Open(path)
+	if openErr != nil {
+		return openErr
+	}
+	defer func(xmlFile *os.File) {
+		if err := xmlFile.Close(); err != nil && readErr == nil {
+			readErr = err
+		}
+	}(xmlFile)
+
+	decoder := xml.NewDecoder(bufio.NewReader(xmlFile))
+	decoder.CharsetReader = func(label string, input io.Reader) (io.Reader, error) {
+		source, err := csvEncoding(label)
+		if err != nil || source == nil {
+			return input, err
+		}
+		return transform.NewReader(input, source.NewDecoder()), nil
+	}
+	var selector recordSelector
+	var combined bool
+	var stack []string
+	var record flatRow
+	var child string
+	var text strings.Builder
+	recordDepth, sheets, nested := 0, 0, false
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		token, tokenErr := decoder.Token()
+		if tokenErr == io.EOF {
+			return nil
+		}
+		if tokenErr != nil {
+			return tokenErr
+		}
+		switch element := token.(type) {
+		case xml.StartElement:
+			stack = append(stack, element.Name.Local)
+			switch {
+			case len(stack) == 1:
+				var err error
+				if selector, combined, err = o.selector(element.Name.Local); err != nil {
+					return err
+				}
+				if !combined {
+					if err := onSheet(""); err != nil {
+						return err
+					}
+				}
+			case combined && len(stack) == 2 && element.Name.Local == "Sheet":
+				sheets++
+				name := xmlAttr(element, "name")
+				if len(name) < 1 {
+					name = "Sheet" + strconv.Itoa(sheets)
+				}
+				if err := onSheet(name); err != nil {
+					return err
+				}
+			case recordDepth < 1 && selector.matches(stack):
+				recordDepth, record = len(stack), nil
+				for _, attr := range recordAttrs(element) {
+					record = addRecordCell(record, attr.Name.Local, attr.Value)
+				}
+			case recordDepth > 0 && len(stack) == recordDepth+1:
+				child, nested = element.Name.Local, false
+				text.Reset()
+				record = addRecordCell(record, child, "")
+				for _, attr := range recordAttrs(element) {
+					record = addRecordCell(record, child+"_"+attr.Name.Local, attr.Value)
+				}
+			case len(child) > 0 && !nested:
+				// Drop the indentation before the first nested element, such as a rich text run.
+				if len(strings.TrimSpace(text.String())) < 1 {
+					text.Reset()
+				}
+				nested = true
+			}
+		case xml.CharData:
+			if len(child) < 1 || (nested && len(stack) == recordDepth+1 && len(strings.TrimSpace(string(element))) < 1) {
+				break
+			}
+			text.Write(element)
+		case xml.EndElement:
+			switch {
+			case recordDepth < 1:
+			case len(stack) == recordDepth+1:
+				record = addRecordCell(record, child, text.String())
+				child = ""
+			case len(stack) == recordDepth:
+				recordDepth = 0
+				if err := onRecord(record); err != nil {
+					return err
+				}
+			}
+			stack = stack[:len(stack)-1]
+		}
+	}
+}
+
+// selector returns the record selector of a document whose root element is called root,
+// and whether the document is a combined Workbook.
+func (o XmlToXlsxOptions) selector(root string) (recordSelector, bool, error) {
+	if len(o.Records) > 0 {
+		selector, err := parseRecordSelector(o.Records)
+		return selector, false, err
+	}
+	if root == "Workbook" {
+		return recordSelector{steps: []string{"Workbook", "Sheet", "*", "*"}}, true, nil
+	}
+	return recordSelector{steps: []string{"*", "*"}}, false, nil
+}
+
+// recordAttrs returns the attributes of element that hold values, leaving out namespace declarations
+// and attributes of the XML schema instance namespace.
+func recordAttrs(element xml.StartElement) []xml.Attr {
+	var attrs []xml.Attr
+	for _, attr := range element.Attr {
+		if attr.Name.Space == "xmlns" || attr.Name.Local == "xmlns" || attr.Name.Space == xsiNamespace || attr.Name.Space == "xsi" {
+			continue
+		}
+		attrs = append(attrs, attr)
+	}
+	return attrs
+}
+
+// xmlAttr returns the value of the attribute of element called name.
+func xmlAttr(element xml.StartElement, name string) string {
+	for _, attr := range element.Attr {
+		if attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+// addRecordCell adds the value of column to record, joining it to the value already held by a repeated column.
+func addRecordCell(record flatRow, column, value string) flatRow {
+	for index, cell := range record {
+		if cell.column != column {
+			continue
+		}
+		if text, _ := cell.value.(string); len(text) > 0 && len(value) > 0 {
+			value = text + "; " + value
+		} else if len(text) > 0 {
+			value = text
+		}
+		record[index].value = value
+		return record
+	}
+	return append(record, flatCell{column: column, value: value})
+}
@@ -0,0 +1,124 @@
+package converters
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestXmlToXlsx(t *testing.T) {
+	workbook := createTestWorkbook(t, map[string][][]any{
+		"People": {{"Name", "Age"}, {"Ann", 31}, {"Bob", ""}},
+		"Orders": {{"Id", "Amount"}, {1, 9.99}},
+	}, "People", "Orders")
+	dir := t.TempDir()
+	convert := func(name string, opts Options) string {
+		path := filepath.Join(dir, name)
+		output, createErr := os.Create(path)
+		if createErr != nil {
+			t.Fatalf("Error creating XML file: %v", createErr)
+		}
+		defer output.Close()
+		if err := XlsxToXml(context.Background(), output, workbook, opts); err != nil {
+			t.Fatalf("XlsxToXml() error = %v", err)
+		}
+		return path
+	}
+	elements := convert("people.xml", Options{Xml: XmlOptions{InferTypes: true, EmptyCells: NilEmpty}})
+	attributes := convert("attributes.xml", Options{Xml: XmlOptions{Attributes: true}})
+	combined := convert("combined.xml", Options{Sheets: AllSheets(), Combine: true})
+	custom := filepath.Join(dir, "custom.xml")
+	customXml := `<?xml version="1.0" encoding="ISO-8859-1"?>
+<feed><meta><count>2</count></meta><items>
+  <item id="1"><title lang="en">Caf` + "\xe9" + `</title><tag>a</tag><tag>b</tag></item>
+  <item id="2"><title>
+    <Run>Bold </Run>
+    <Run>text</Run>
+  </title></item>
+</items></feed>`
+	if err := os.WriteFile(custom, []byte(customXml), 0o644); err != nil {
+		t.Fatalf("Error writing XML file: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		path   string
+		opts   XmlToXlsxOptions
+		sheets map[string][][]string
+	}{
+		{
+			name:   "Elements",
+			path:   elements,
+			opts:   XmlToXlsxOptions{CoerceTypes: true},
+			sheets: map[string][][]string{"people": {{"Name", "Age"}, {"Ann", "31"}, {"Bob"}}},
+		},
+		{
+			name:   "Attributes",
+			path:   attributes,
+			opts:   XmlToXlsxOptions{SheetName: "People"},
+			sheets: map[string][][]string{"People": {{"Name", "Age"}, {"Ann", "31"}, {"Bob"}}},
+		},
+		{
+			name: "Combined Workbook",
+			path: combined,
+			sheets: map[string][][]string{
+				"People": {{"Name", "Age"}, {"Ann", "31"}, {"Bob"}},
+				"Orders": {{"Id", "Amount"}, {"1", "9.99"}},
+			},
+		},
+		{
+			name: "Record Path",
+			path: custom,
+			opts: XmlToXlsxOptions{Records: "//items/item"},
+			sheets: map[string][][]string{"custom": {
+				{"id", "title", "title_lang", "tag"},
+				{"1", "Café", "en", "a; b"},
+				{"2", "Bold text"},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outPath := filepath.Join(dir, "out.xlsx")
+			if err := XmlToXlsx(context.Background(), outPath, tt.path, tt.opts); err != nil {
+				t.Fatalf("XmlToXlsx() error = %v", err)
+			}
+			file, openErr := excelize.OpenFile(outPath)
+			if openErr != nil {
+				t.Fatalf("Error opening workbook: %v", openErr)
+			}
+			defer file.Close()
+			if len(file.GetSheetList()) != len(tt.sheets) {
+				t.Errorf("XmlToXlsx() sheets = %v", file.GetSheetList())
+			}
+			for sheet, want := range tt.sheets {
+				if rows, _ := file.GetRows(sheet); !reflect.DeepEqual(rows, want) {
+					t.Errorf("XmlToXlsx() %s rows = %q, want %q", sheet, rows, want)
+				}
+			}
+		})
+	}
+
+	if cellType := cellTypeAt(t, filepath.Join(dir, "out.xlsx"), "custom", "A2"); cellType != excelize.CellTypeInlineString {
+		t.Errorf("XmlToXlsx() coerced a value without CoerceTypes, type = %v", cellType)
+	}
+	if err := XmlToXlsx(context.Background(), filepath.Join(dir, "out.xlsx"), custom, XmlToXlsxOptions{Records: "/feed/item[1]"}); err == nil {
+		t.Errorf("XmlToXlsx() accepted a predicate in the record path")
+	}
+}
+
+// cellTypeAt returns the type of a cell of the workbook at path.
+func cellTypeAt(t *testing.T, path, sheet, cell string) excelize.CellType {
+	t.Helper()
+	file, err := excelize.OpenFile(path)
+	if err != nil {
+		t.Fatalf("Error opening workbook: %v", err)
+	}
+	defer file.Close()
+	cellType, _ := file.GetCellType(sheet, cell)
+	return cellType
+}
@@ -0,0 +1,118 @@
+package datatable
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AppendMode selects how Append reconciles the columns of the tables it stacks.
+type AppendMode int
+
+const (
+	// StrictColumns fails the append when the tables do not have the same columns, in any order. It is the default.
+	StrictColumns AppendMode = iota
+	// UnionColumns adds the columns only the appended table has after the last column of the table, blank in its
+	// earlier rows, and leaves the columns the appended table lacks blank in its rows.
+	UnionColumns
+)
+
+// ParseAppendMode returns the AppendMode called strict or union.
+func ParseAppendMode(name string) (AppendMode, error) {
+	switch strings.ToLower(name) {
+	case "", "strict":
+		return StrictColumns, nil
+	case "union":
+		return UnionColumns, nil
+	}
+	return StrictColumns, fmt.Errorf("unknown append mode '%s'", name)
+}
+
+// AppendReport describes an append: the number of rows appended, the columns of the table the appended table
+// lacks, left blank in its rows, and the columns only the appended table has, added by UnionColumns.
+type AppendReport struct {
+	Rows    int      `json:"rows"`
+	Missing []string `json:"missing,omitempty"`
+	Extra   []string `json:"extra,omitempty"`
+}
+
+// Mismatched tells whether the columns of the appended table differ from those of the table.
+func (r AppendReport) Mismatched() bool {
+	return len(r.Missing) > 0 || len(r.Extra) > 0
+}
+
+// foldName returns name in lower case, without surrounding spaces and with inner spaces collapsed.
+func foldName(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// Append adds the rows of other after the last row of t, such as the same export of several months, and reports
+// how their columns differ. Values are aligned with the columns of t by name, matched exactly or else regardless of
+// case and of surrounding and repeated spaces, so "Unit Price" matches "unit  price"; mode decides what becomes of
+// the columns they do not share. Extra columns take their name and type from other. On failure t is unchanged.
+//
+//	report, err := january.Append(february, UnionColumns)
+func (t *DataTable) Append(other *DataTable, mode AppendMode) (AppendReport, error) {
+	folded := make(map[string]int, len(t.columns))
+	for position := len(t.columns) - 1; position >= 0; position-- {
+		folded[foldName(t.columns[position].Name)] = position
+	}
+	// targets holds the position in t of every column of other, -1 for the columns t lacks. Exact matches are
+	// made first so a column matching another only regardless of case cannot take its place.
+	targets := make([]int, len(other.columns))
+	matched := make(map[int]bool, len(other.columns))
+	for position, column := range other.columns {
+		targets[position] = -1
+		if target, found := t.index[column.Name]; found {
+			targets[position] = target
+			matched[target] = true
+		}
+	}
+	for position, column := range other.columns {
+		if target, found := folded[foldName(column.Name)]; found && targets[position] < 0 && !matched[target] {
+			targets[position] = target
+			matched[target] = true
+		}
+	}
+	var report AppendReport
+	var extra []Column
+	for position, target := range targets {
+		if target < 0 {
+			extra = append(extra, other.columns[position])
+			report.Extra = append(report.Extra, other.columns[position].Name)
+		}
+	}
+	for position, column := range t.columns {
+		if !matched[position] {
+			report.Missing = append(report.Missing, column.Name)
+		}
+	}
+	if mode == StrictColumns && report.Mismatched() {
+		return report, fmt.Errorf("the appended columns differ: missing %v, extra %v", report.Missing, report.Extra)
+	}
+
+	width := len(t.columns)
+	for _, column := range extra {
+		if err := t.AddColumn(column); err != nil {
+			return report, err
+		}
+	}
+	added := width
+	for position, target := range targets {
+		if target < 0 {
+			targets[position] = added
+			added++
+		}
+	}
+	rows := make([][]string, len(other.rows))
+	for index, values := range other.rows {
+		row := make([]string, len(t.columns))
+		for position, value := range values {
+			row[targets[position]] = value
+		}
+		rows[index] = row
+	}
+	t.rows = append(t.rows, rows...)
+	t.stats.Rows += len(rows)
+	report.Rows = len(rows)
+	return report, nil
+}
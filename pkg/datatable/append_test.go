@@ -0,0 +1,95 @@
+package datatable
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAppend(t *testing.T) {
+	tests := []struct {
+		name        string
+		header      []string
+		records     [][]string
+		mode        AppendMode
+		wantColumns []string
+		want        [][]string
+		wantReport  AppendReport
+		wantErr     string
+	}{
+		{
+			name:        "Same Columns In Another Order",
+			header:      []string{"qty", "Item"},
+			records:     [][]string{{"4", "Washer"}},
+			wantColumns: []string{"Item", "Qty"},
+			want:        [][]string{{"Bolt", "3"}, {"Washer", "4"}},
+			wantReport:  AppendReport{Rows: 1},
+		},
+		{
+			name:    "Strict Mismatch",
+			header:  []string{"Item", "Price"},
+			records: [][]string{{"Washer", "0.05"}},
+			wantErr: "the appended columns differ: missing [Qty], extra [Price]",
+		},
+		{
+			name:        "Union",
+			header:      []string{"Price", " item ", "Note"},
+			records:     [][]string{{"0.05", "Washer", "new"}, {"0.10", "Nut"}},
+			mode:        UnionColumns,
+			wantColumns: []string{"Item", "Qty", "Price", "Note"},
+			want:        [][]string{{"Bolt", "3", "", ""}, {"Washer", "", "0.05", "new"}, {"Nut", "", "0.10", ""}},
+			wantReport:  AppendReport{Rows: 2, Missing: []string{"Qty"}, Extra: []string{"Price", "Note"}},
+		},
+		{
+			name:        "Exact Match First",
+			header:      []string{"item", "Item", "Qty"},
+			records:     [][]string{{"x", "Nut", "1"}},
+			mode:        UnionColumns,
+			wantColumns: []string{"Item", "Qty", "item"},
+			want:        [][]string{{"Bolt", "3", ""}, {"Nut", "1", "x"}},
+			wantReport:  AppendReport{Rows: 1, Extra: []string{"item"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table := mustTable(t, []string{"Item", "Qty"}, []string{"Bolt", "3"})
+			other := mustTable(t, tt.header, tt.records...)
+			report, err := table.Append(other, tt.mode)
+			if len(tt.wantErr) > 0 {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Append() error = %v, want %s", err, tt.wantErr)
+				}
+				if table.Len() != 1 || len(table.Columns()) != 2 {
+					t.Errorf("Append() changed the table on failure")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Append() error = %v", err)
+			}
+			if !reflect.DeepEqual(table.ColumnNames(), tt.wantColumns) {
+				t.Errorf("Append() columns = %v, want %v", table.ColumnNames(), tt.wantColumns)
+			}
+			if !reflect.DeepEqual(table.Records(), tt.want) {
+				t.Errorf("Append() records = %v, want %v", table.Records(), tt.want)
+			}
+			if !reflect.DeepEqual(report, tt.wantReport) {
+				t.Errorf("Append() report = %+v, want %+v", report, tt.wantReport)
+			}
+			if table.RowStats().Rows != 1+tt.wantReport.Rows {
+				t.Errorf("RowStats().Rows = %d, want %d", table.RowStats().Rows, 1+tt.wantReport.Rows)
+			}
+		})
+	}
+}
+
+func TestParseAppendMode(t *testing.T) {
+	for name, want := range map[string]AppendMode{"": StrictColumns, "strict": StrictColumns, "Union": UnionColumns} {
+		if mode, err := ParseAppendMode(name); err != nil || mode != want {
+			t.Errorf("ParseAppendMode(%q) = %v, %v, want %v", name, mode, err, want)
+		}
+	}
+	if _, err := ParseAppendMode("intersect"); err == nil {
+		t.Errorf("ParseAppendMode(intersect) error = nil")
+	}
+}
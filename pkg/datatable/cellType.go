@@ -0,0 +1,214 @@
+package datatable
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CellType is the lexical type of a cell value, as the converters serialize it and as the type of a column is
+// inferred; see TypeInferrer and InferType.
+type CellType int
+
+const (
+	StringCell CellType = iota
+	IntegerCell
+	DecimalCell
+	DoubleCell
+	BooleanCell
+	DateCell
+	DateTimeCell
+)
+
+var (
+	integerPattern = regexp.MustCompile(`^[+-]?\d+$`)
+	decimalPattern = regexp.MustCompile(`^[+-]?(\d+\.\d*|\.\d+)$`)
+	doublePattern  = regexp.MustCompile(`^[+-]?(\d+\.?\d*|\.\d+)[eE][+-]?\d+$`)
+	// Leading zeros mark identifiers such as postal codes or account numbers, which must stay strings.
+	leadingZeroPattern = regexp.MustCompile(`^[+-]?0\d`)
+)
+
+// ParseCellType returns the CellType called string, integer, decimal, double, boolean, date or datetime.
+func ParseCellType(name string) (CellType, error) {
+	switch strings.ToLower(name) {
+	case "string":
+		return StringCell, nil
+	case "integer":
+		return IntegerCell, nil
+	case "decimal":
+		return DecimalCell, nil
+	case "double":
+		return DoubleCell, nil
+	case "boolean":
+		return BooleanCell, nil
+	case "date":
+		return DateCell, nil
+	case "datetime":
+		return DateTimeCell, nil
+	}
+	return StringCell, fmt.Errorf("unknown cell type '%s'", name)
+}
+
+// String returns the name of the cell type, as ParseCellType reads it.
+func (t CellType) String() string {
+	switch t {
+	case IntegerCell:
+		return "integer"
+	case DecimalCell:
+		return "decimal"
+	case DoubleCell:
+		return "double"
+	case BooleanCell:
+		return "boolean"
+	case DateCell:
+		return "date"
+	case DateTimeCell:
+		return "datetime"
+	}
+	return "string"
+}
+
+// ColumnType returns the type a column of values of the cell type is read as: decimals and doubles are both
+// DecimalType.
+func (t CellType) ColumnType() ColumnType {
+	switch t {
+	case IntegerCell:
+		return IntegerType
+	case DecimalCell, DoubleCell:
+		return DecimalType
+	case BooleanCell:
+		return BooleanType
+	case DateCell:
+		return DateType
+	case DateTimeCell:
+		return DateTimeType
+	}
+	return StringType
+}
+
+// Canonical returns the canonical lexical form of value for the cell type.
+// It returns false if value is not a valid lexical form of the cell type.
+func (t CellType) Canonical(value string) (string, bool) {
+	switch t {
+	case StringCell:
+		return value, true
+	case IntegerCell:
+		if !integerPattern.MatchString(value) {
+			return "", false
+		}
+		integer, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return "", false
+		}
+		return strconv.FormatInt(integer, 10), true
+	case DecimalCell:
+		if integerPattern.MatchString(value) {
+			value += ".0"
+		}
+		if !decimalPattern.MatchString(value) {
+			return "", false
+		}
+		return canonicalDecimal(value), true
+	case DoubleCell:
+		double, err := strconv.ParseFloat(value, 64)
+		if err != nil || !(integerPattern.MatchString(value) || decimalPattern.MatchString(value) || doublePattern.MatchString(value)) {
+			return "", false
+		}
+		return strconv.FormatFloat(double, 'E', -1, 64), true
+	case BooleanCell:
+		switch strings.ToLower(value) {
+		case "true", "1":
+			return "true", true
+		case "false", "0":
+			return "false", true
+		}
+		return "", false
+	case DateCell:
+		if date, err := time.Parse(time.DateOnly, value); err == nil {
+			return date.Format(time.DateOnly), true
+		}
+		return "", false
+	case DateTimeCell:
+		for _, layout := range []string{time.DateTime, "2006-01-02T15:04:05", time.RFC3339Nano} {
+			if dateTime, err := time.Parse(layout, value); err == nil {
+				if layout == time.RFC3339Nano {
+					return dateTime.Format(time.RFC3339Nano), true
+				}
+				return dateTime.Format("2006-01-02T15:04:05"), true
+			}
+		}
+		return "", false
+	}
+	return "", false
+}
+
+// Valid reports whether value is a valid lexical form of the cell type which is not a number written with
+// leading zeros, the way InferCellType reads values.
+func (t CellType) Valid(value string) bool {
+	if t != StringCell && leadingZeroPattern.MatchString(value) {
+		return false
+	}
+	_, ok := t.Canonical(value)
+	return ok
+}
+
+// canonicalDecimal strips the sign of positive values, redundant leading zeros and trailing zeros
+// from a decimal that matches decimalPattern, keeping at least one digit on each side of the point.
+func canonicalDecimal(value string) string {
+	negative := strings.HasPrefix(value, "-")
+	value = strings.TrimLeft(value, "+-")
+	integerPart, fractionPart, _ := strings.Cut(value, ".")
+	integerPart = strings.TrimLeft(integerPart, "0")
+	fractionPart = strings.TrimRight(fractionPart, "0")
+	if len(integerPart) < 1 {
+		integerPart = "0"
+	}
+	if len(fractionPart) < 1 {
+		fractionPart = "0"
+	}
+	if negative && (integerPart != "0" || fractionPart != "0") {
+		integerPart = "-" + integerPart
+	}
+	return integerPart + "." + fractionPart
+}
+
+// InferCellType returns the most specific cell type value is a valid lexical form of.
+// Empty values and numbers with leading zeros are inferred as strings, and booleans only when written
+// true or false.
+func InferCellType(value string) CellType {
+	if len(value) < 1 {
+		return StringCell
+	}
+	for _, cellType := range []CellType{IntegerCell, DecimalCell, DoubleCell, DateCell, DateTimeCell} {
+		// Integers are valid decimals, so only accept a decimal if it holds a point.
+		if cellType == DecimalCell && !strings.Contains(value, ".") {
+			continue
+		}
+		if cellType.Valid(value) {
+			return cellType
+		}
+	}
+	if strings.EqualFold(value, "true") || strings.EqualFold(value, "false") {
+		return BooleanCell
+	}
+	return StringCell
+}
+
+// widenCellType returns the type holding values of both cell types: the wider number type of two number types,
+// a date and time for a date and a date and time, and a string otherwise.
+func widenCellType(current, next CellType) CellType {
+	numeric := func(cellType CellType) bool {
+		return cellType == IntegerCell || cellType == DecimalCell || cellType == DoubleCell
+	}
+	switch {
+	case current == next:
+		return current
+	case numeric(current) && numeric(next):
+		return max(current, next)
+	case (current == DateCell || current == DateTimeCell) && (next == DateCell || next == DateTimeCell):
+		return DateTimeCell
+	}
+	return StringCell
+}
@@ -0,0 +1,34 @@
+package datatable
+
+import "testing"
+
+func TestInferCellType(t *testing.T) {
+	tests := []struct {
+		value     string
+		wantType  CellType
+		wantValue string
+	}{
+		{value: "42", wantType: IntegerCell, wantValue: "42"},
+		{value: "+42", wantType: IntegerCell, wantValue: "42"},
+		{value: "00042", wantType: StringCell, wantValue: "00042"},
+		{value: "42.50", wantType: DecimalCell, wantValue: "42.5"},
+		{value: "-.5", wantType: DecimalCell, wantValue: "-0.5"},
+		{value: "1.5e3", wantType: DoubleCell, wantValue: "1.5E+03"},
+		{value: "TRUE", wantType: BooleanCell, wantValue: "true"},
+		{value: "2024-03-01", wantType: DateCell, wantValue: "2024-03-01"},
+		{value: "2024-03-01 10:30:00", wantType: DateTimeCell, wantValue: "2024-03-01T10:30:00"},
+		{value: "NaN", wantType: StringCell, wantValue: "NaN"},
+		{value: "Hello", wantType: StringCell, wantValue: "Hello"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			gotType := InferCellType(tt.value)
+			if gotType != tt.wantType {
+				t.Fatalf("InferCellType() = %v, want %v", gotType, tt.wantType)
+			}
+			if gotValue, _ := gotType.Canonical(tt.value); gotValue != tt.wantValue {
+				t.Errorf("Canonical() = %v, want %v", gotValue, tt.wantValue)
+			}
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package datatable
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ChangeKind tells how a row changed between two tables.
+type ChangeKind int
+
+const (
+	// RowAdded is a row of the new table whose key is not in the old one.
+	RowAdded ChangeKind = iota
+	// RowRemoved is a row of the old table whose key is not in the new one.
+	RowRemoved
+	// RowChanged is a row whose key is in both tables with different values.
+	RowChanged
+)
+
+// String returns added, removed or changed.
+func (k ChangeKind) String() string {
+	return [...]string{"added", "removed", "changed"}[k]
+}
+
+// CellChange is a value of a column which changed from Old to New.
+type CellChange struct {
+	Column string `json:"column"`
+	Old    string `json:"old"`
+	New    string `json:"new"`
+}
+
+// RowChange is a row which changed between two tables: the values of its key columns, its one-based number in
+// the old and the new table, 0 when it is not in that table, and the cells which changed, for RowChanged.
+type RowChange struct {
+	Kind   ChangeKind   `json:"-"`
+	Key    []string     `json:"key"`
+	OldRow int          `json:"oldRow,omitempty"`
+	NewRow int          `json:"newRow,omitempty"`
+	Cells  []CellChange `json:"cells,omitempty"`
+}
+
+// Diff is the outcome of Compare: the rows which changed, in the order of the new table followed by the rows
+// removed in the order of the old one, and the columns added to or removed from the new table, which are not
+// compared.
+type Diff struct {
+	Keys           []string    `json:"keys"`
+	Changes        []RowChange `json:"changes,omitempty"`
+	AddedColumns   []string    `json:"addedColumns,omitempty"`
+	RemovedColumns []string    `json:"removedColumns,omitempty"`
+}
+
+// Count returns the number of rows which changed as kind.
+func (d *Diff) Count(kind ChangeKind) int {
+	count := 0
+	for _, change := range d.Changes {
+		if change.Kind == kind {
+			count++
+		}
+	}
+	return count
+}
+
+// CompareOptions configures Compare.
+// Keys names the columns identifying a row in both tables, such as an order number; their values must be
+// distinct in each table. Ignore names columns left out of the comparison, such as a timestamp.
+// IgnoreCase compares values, keys included, regardless of case and of surrounding spaces.
+// Values of columns typed other than StringType in the new table compare as values of that type, so 10 and 10.0
+// are equal in a DecimalType column.
+type CompareOptions struct {
+	Keys       []string
+	Ignore     []string
+	IgnoreCase bool
+}
+
+// Compare reports the rows added, removed and changed from old to current, such as yesterday's and today's export
+// of the same report, matching their rows by the values of opts.Keys.
+func Compare(old, current *DataTable, opts CompareOptions) (*Diff, error) {
+	if len(opts.Keys) < 1 {
+		return nil, errors.New("no key columns to match the rows on")
+	}
+	oldKeys, err := old.positions(opts.Keys)
+	if err != nil {
+		return nil, fmt.Errorf("old table: %w", err)
+	}
+	currentKeys, err := current.positions(opts.Keys)
+	if err != nil {
+		return nil, fmt.Errorf("new table: %w", err)
+	}
+	diff := &Diff{Keys: opts.Keys}
+	skipped := make(map[string]bool, len(opts.Keys)+len(opts.Ignore))
+	for _, name := range append(append([]string(nil), opts.Keys...), opts.Ignore...) {
+		skipped[name] = true
+	}
+	var compared []string
+	for _, column := range current.columns {
+		switch {
+		case !old.HasColumn(column.Name):
+			diff.AddedColumns = append(diff.AddedColumns, column.Name)
+		case !skipped[column.Name]:
+			compared = append(compared, column.Name)
+		}
+	}
+	for _, column := range old.columns {
+		if !current.HasColumn(column.Name) {
+			diff.RemovedColumns = append(diff.RemovedColumns, column.Name)
+		}
+	}
+
+	normalize := func(value string) string {
+		if opts.IgnoreCase {
+			return strings.ToLower(strings.TrimSpace(value))
+		}
+		return value
+	}
+	keyOf := func(values []string, positions []int) (key []string, id string) {
+		key = make([]string, len(positions))
+		parts := make([]string, len(positions))
+		for position, keyPosition := range positions {
+			key[position] = values[keyPosition]
+			parts[position] = normalize(values[keyPosition])
+		}
+		return key, strings.Join(parts, "\x1f")
+	}
+	index := func(table *DataTable, positions []int, name string) (map[string]int, error) {
+		rows := make(map[string]int, len(table.rows))
+		for number, values := range table.rows {
+			key, id := keyOf(values, positions)
+			if first, repeated := rows[id]; repeated {
+				return nil, fmt.Errorf("%s table: rows %d and %d have the same key %v", name, first+1, number+1, key)
+			}
+			rows[id] = number
+		}
+		return rows, nil
+	}
+	oldRows, err := index(old, oldKeys, "old")
+	if err != nil {
+		return nil, err
+	}
+	currentRows, err := index(current, currentKeys, "new")
+	if err != nil {
+		return nil, err
+	}
+
+	for number, values := range current.rows {
+		key, id := keyOf(values, currentKeys)
+		oldNumber, found := oldRows[id]
+		if !found {
+			diff.Changes = append(diff.Changes, RowChange{Kind: RowAdded, Key: key, NewRow: number + 1})
+			continue
+		}
+		change := RowChange{Kind: RowChanged, Key: key, OldRow: oldNumber + 1, NewRow: number + 1}
+		oldRow, currentRow := old.Row(oldNumber), current.Row(number)
+		for _, name := range compared {
+			before, after := oldRow.Get(name), currentRow.Get(name)
+			if normalize(before) == normalize(after) {
+				continue
+			}
+			if column, _ := current.Column(name); column.Type != StringType && column.Type.compare(before, after) == 0 {
+				continue
+			}
+			change.Cells = append(change.Cells, CellChange{Column: name, Old: before, New: after})
+		}
+		if len(change.Cells) > 0 {
+			diff.Changes = append(diff.Changes, change)
+		}
+	}
+	for number, values := range old.rows {
+		key, id := keyOf(values, oldKeys)
+		if _, found := currentRows[id]; !found {
+			diff.Changes = append(diff.Changes, RowChange{Kind: RowRemoved, Key: key, OldRow: number + 1})
+		}
+	}
+	return diff, nil
+}
+
+// Table returns the changes of the diff as a table: a row per added or removed row and per changed cell, holding
+// the kind of the change in Change, the key values, and the Column, Old and New values of the changed cells.
+// It fails when a key column is called Change, Column, Old or New.
+func (d *Diff) Table() (*DataTable, error) {
+	names := append(append([]string{"Change"}, d.Keys...), "Column", "Old", "New")
+	table, err := New(names...)
+	if err != nil {
+		return nil, err
+	}
+	for _, change := range d.Changes {
+		row := append([]string{change.Kind.String()}, change.Key...)
+		if change.Kind != RowChanged {
+			if err := table.AddRow(row...); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		for _, cell := range change.Cells {
+			if err := table.AddRow(append(row, cell.Column, cell.Old, cell.New)...); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return table, nil
+}
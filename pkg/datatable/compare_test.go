@@ -0,0 +1,83 @@
+package datatable
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	yesterday := mustTable(t, []string{"Id", "Name", "Amount", "Exported"},
+		[]string{"1", "Ann", "10", "2024-03-01"},
+		[]string{"2", "Bob", "20", "2024-03-01"},
+		[]string{"3", "Cid", "30", "2024-03-01"})
+	today := mustTable(t, []string{"Id", "Name", "Amount", "Exported", "Region"},
+		[]string{"3", "cid ", "30.0", "2024-03-02", "North"},
+		[]string{"1", "Ann", "12", "2024-03-02", "South"},
+		[]string{"4", "Dan", "40", "2024-03-02", "North"})
+	if err := today.SetColumnType("Amount", DecimalType); err != nil {
+		t.Fatal(err)
+	}
+
+	diff, err := Compare(yesterday, today, CompareOptions{Keys: []string{"Id"}, Ignore: []string{"Exported"}})
+	if err != nil {
+		t.Fatalf("Compare() error = %v", err)
+	}
+	want := []RowChange{
+		{Kind: RowChanged, Key: []string{"3"}, OldRow: 3, NewRow: 1, Cells: []CellChange{{Column: "Name", Old: "Cid", New: "cid "}}},
+		{Kind: RowChanged, Key: []string{"1"}, OldRow: 1, NewRow: 2, Cells: []CellChange{{Column: "Amount", Old: "10", New: "12"}}},
+		{Kind: RowAdded, Key: []string{"4"}, NewRow: 3},
+		{Kind: RowRemoved, Key: []string{"2"}, OldRow: 2},
+	}
+	if !reflect.DeepEqual(diff.Changes, want) {
+		t.Errorf("Compare() changes = %+v, want %+v", diff.Changes, want)
+	}
+	if !reflect.DeepEqual(diff.AddedColumns, []string{"Region"}) || diff.RemovedColumns != nil {
+		t.Errorf("Compare() columns added %v, removed %v", diff.AddedColumns, diff.RemovedColumns)
+	}
+	if diff.Count(RowChanged) != 2 || diff.Count(RowAdded) != 1 || diff.Count(RowRemoved) != 1 {
+		t.Errorf("Count() = %d changed, %d added, %d removed", diff.Count(RowChanged), diff.Count(RowAdded), diff.Count(RowRemoved))
+	}
+
+	table, err := diff.Table()
+	if err != nil {
+		t.Fatalf("Table() error = %v", err)
+	}
+	wantRecords := [][]string{
+		{"changed", "3", "Name", "Cid", "cid "},
+		{"changed", "1", "Amount", "10", "12"},
+		{"added", "4", "", "", ""},
+		{"removed", "2", "", "", ""},
+	}
+	if !reflect.DeepEqual(table.ColumnNames(), []string{"Change", "Id", "Column", "Old", "New"}) || !reflect.DeepEqual(table.Records(), wantRecords) {
+		t.Errorf("Table() = %v %v, want %v", table.ColumnNames(), table.Records(), wantRecords)
+	}
+
+	folded, err := Compare(yesterday, today, CompareOptions{Keys: []string{"Id"}, Ignore: []string{"Exported"}, IgnoreCase: true})
+	if err != nil {
+		t.Fatalf("Compare() error = %v", err)
+	}
+	if got := folded.Count(RowChanged); got != 1 {
+		t.Errorf("Compare(IgnoreCase) changed %d rows, want 1", got)
+	}
+}
+
+func TestCompareErrors(t *testing.T) {
+	table := mustTable(t, []string{"Id", "Name"}, []string{"1", "Ann"}, []string{"1", "Bob"})
+	tests := []struct {
+		name    string
+		opts    CompareOptions
+		wantErr string
+	}{
+		{name: "No Keys", wantErr: "no key columns"},
+		{name: "Unknown Key", opts: CompareOptions{Keys: []string{"Code"}}, wantErr: "old table: unknown column 'Code'"},
+		{name: "Repeated Key", opts: CompareOptions{Keys: []string{"Id"}}, wantErr: "old table: rows 1 and 2 have the same key [1]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Compare(table, table, tt.opts); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Compare() error = %v, want %s", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package datatable
+
+import "fmt"
+
+// Detached returns a row of the columns of t holding values, in the order of the columns, without adding it to t,
+// such as a record read elsewhere to evaluate an expression on. Missing values are blank and extra ones are left
+// out; its Index is -1.
+func (t *DataTable) Detached(values ...string) Row {
+	row := make([]string, len(t.columns))
+	copy(row, values)
+	return Row{table: t, index: -1, values: row}
+}
+
+// Compute adds a column called name holding the value expression computes for every row, such as
+// Quantity * [Unit Price] or if(Amount > 1000, "large", "small"), written as FormatValue writes it. It fails when
+// the table lacks a column expression reads, when the column exists, or for the first row expression fails on,
+// leaving the table unchanged.
+func (t *DataTable) Compute(name string, expression *Expression) error {
+	if err := expression.Check(t); err != nil {
+		return err
+	}
+	if t.HasColumn(name) {
+		return fmt.Errorf("the column '%s' is defined twice", name)
+	}
+	values := make([]string, len(t.rows))
+	eachErr := t.Each(func(row Row) error {
+		value, err := expression.Eval(row)
+		if err != nil {
+			return err
+		}
+		values[row.index] = FormatValue(value)
+		return nil
+	})
+	if eachErr != nil {
+		return eachErr
+	}
+	if err := t.AddColumn(Column{Name: name}); err != nil {
+		return err
+	}
+	position := len(t.columns) - 1
+	for index, value := range values {
+		t.rows[index][position] = value
+	}
+	return nil
+}
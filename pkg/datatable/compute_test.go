@@ -0,0 +1,44 @@
+package datatable
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCompute(t *testing.T) {
+	table := mustTable(t, []string{"Item", "Quantity", "Price"}, []string{"Pen", "3", "1.5"}, []string{"Pad", "", "4"})
+	total, err := ParseExpression("if(isblank(Quantity), null, Quantity * Price)")
+	if err != nil {
+		t.Fatalf("ParseExpression() error = %v", err)
+	}
+	if err := table.Compute("Total", total); err != nil {
+		t.Fatalf("Compute() error = %v", err)
+	}
+	if want := [][]string{{"Pen", "3", "1.5", "4.5"}, {"Pad", "", "4", ""}}; !reflect.DeepEqual(table.Records(), want) {
+		t.Errorf("Compute() records = %v, want %v", table.Records(), want)
+	}
+	if err := table.Compute("Total", total); err == nil || !strings.Contains(err.Error(), "defined twice") {
+		t.Errorf("Compute() error = %v, want defined twice", err)
+	}
+
+	failing, _ := ParseExpression("Item * 2")
+	if err := table.Compute("Double", failing); err == nil || !strings.Contains(err.Error(), "row 1") {
+		t.Errorf("Compute() error = %v, want row 1", err)
+	}
+	if table.HasColumn("Double") {
+		t.Error("Compute() added a column it failed to compute")
+	}
+}
+
+func TestDetached(t *testing.T) {
+	table := mustTable(t, []string{"A", "B"})
+	row := table.Detached("1")
+	if row.Index() != -1 || !reflect.DeepEqual(row.Values(), []string{"1", ""}) || table.Len() != 0 {
+		t.Errorf("Detached() = %d %v, table of %d rows", row.Index(), row.Values(), table.Len())
+	}
+	expression, _ := ParseExpression("A * B + C")
+	if _, err := expression.Eval(row); err == nil || strings.Contains(err.Error(), "row") {
+		t.Errorf("Eval() error = %v, want an error without a row number", err)
+	}
+}
@@ -0,0 +1,163 @@
+package datatable
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Default labels of the cross tabulation.
+const (
+	defaultTotalLabel   = "Total"
+	defaultKeySeparator = " / "
+)
+
+// CrosstabOptions configures summarising a table into a cross tabulation.
+// RowKeys names the columns whose values give the rows of the summary and ColumnKeys those whose values give
+// its columns, joined by KeySeparator, " / " by default, when there are several.
+// Value names the column summarised with Func, as Aggregate does; it may be left out to Count the rows.
+// Totals adds a column and a row summarising every value of their row and column, labelled TotalLabel,
+// "Total" by default. Empty is the value of the cells no value of the table falls into, counts included.
+type CrosstabOptions struct {
+	RowKeys      []string
+	ColumnKeys   []string
+	Value        string
+	Func         AggregateFunc
+	Totals       bool
+	TotalLabel   string
+	KeySeparator string
+	Empty        string
+}
+
+// Crosstab summarises t into a cross tabulation, such as the sales of every region per quarter: the summary
+// holds a row per distinct combination of the values of the row keys and, after the row key columns, a column
+// per distinct combination of the values of the column keys, each cell summarising the values of the rows of t
+// falling into it. Rows and columns are in the order their keys are first met in t.
+// Blank values are left out, and Sum and Average fail on values which are not numbers. The row key columns keep
+// their type, the first one being StringType with Totals, and the other columns are of the type Aggregate
+// gives the summary of the value column.
+// Example usage:
+//
+//	sales.Crosstab(CrosstabOptions{RowKeys: []string{"Region"}, ColumnKeys: []string{"Quarter"}, Value: "Amount"})
+func (t *DataTable) Crosstab(opts CrosstabOptions) (*DataTable, error) {
+	if len(opts.RowKeys) < 1 || len(opts.ColumnKeys) < 1 {
+		return nil, errors.New("a cross tabulation needs row keys and column keys")
+	}
+	if err := opts.Func.check(); err != nil {
+		return nil, err
+	}
+	if len(opts.Value) < 1 && opts.Func != Count {
+		return nil, errors.New("no value column to aggregate")
+	}
+	rowPositions, err := t.positions(opts.RowKeys)
+	if err != nil {
+		return nil, err
+	}
+	columnPositions, err := t.positions(opts.ColumnKeys)
+	if err != nil {
+		return nil, err
+	}
+	aggregation := Aggregation{Column: opts.Value, Func: opts.Func}
+	valuePosition, valueType, resultType := -1, StringType, IntegerType
+	if len(opts.Value) > 0 {
+		if valuePosition, err = t.ColumnIndex(opts.Value); err != nil {
+			return nil, err
+		}
+		valueType = t.columns[valuePosition].Type
+		resultType = opts.Func.resultType(valueType)
+	}
+	separator := opts.KeySeparator
+	if len(separator) < 1 {
+		separator = defaultKeySeparator
+	}
+	totalLabel := defaultName(opts.TotalLabel, defaultTotalLabel)
+
+	type summaryRow struct {
+		keys  []string
+		cells map[string]*group
+		total group
+	}
+	var rows []*summaryRow
+	byKey := make(map[string]*summaryRow)
+	var columns []string
+	columnTotals := make(map[string]*group)
+	var grandTotal group
+	for index, values := range t.rows {
+		keys := make([]string, len(rowPositions))
+		for position, rowPosition := range rowPositions {
+			keys[position] = values[rowPosition]
+		}
+		columnKeys := make([]string, len(columnPositions))
+		for position, columnPosition := range columnPositions {
+			columnKeys[position] = values[columnPosition]
+		}
+		column := strings.Join(columnKeys, separator)
+		id := strings.Join(keys, "\x1f")
+		summary, known := byKey[id]
+		if !known {
+			summary = &summaryRow{keys: keys, cells: make(map[string]*group)}
+			byKey[id] = summary
+			rows = append(rows, summary)
+		}
+		if _, known := columnTotals[column]; !known {
+			columnTotals[column] = &group{}
+			columns = append(columns, column)
+		}
+		if summary.cells[column] == nil {
+			summary.cells[column] = &group{}
+		}
+		for _, acc := range []*group{summary.cells[column], &summary.total, columnTotals[column], &grandTotal} {
+			if valuePosition < 0 {
+				acc.count++
+				continue
+			}
+			if err := aggregation.add(acc, values[valuePosition], valueType); err != nil {
+				return nil, fmt.Errorf("row %d: %w", index+1, err)
+			}
+		}
+	}
+
+	header := make([]Column, 0, len(opts.RowKeys)+len(columns)+1)
+	for _, position := range rowPositions {
+		header = append(header, t.columns[position])
+	}
+	if opts.Totals {
+		header[0].Type = StringType
+	}
+	for _, column := range columns {
+		header = append(header, Column{Name: column, Type: resultType})
+	}
+	if opts.Totals {
+		header = append(header, Column{Name: totalLabel, Type: resultType})
+	}
+	summaryTable, err := NewWithColumns(header...)
+	if err != nil {
+		return nil, err
+	}
+	result := func(acc *group) string {
+		if acc == nil || acc.count < 1 {
+			return opts.Empty
+		}
+		return aggregation.result(acc, resultType)
+	}
+	addRow := func(keys []string, cell func(column string) *group, total *group) {
+		row := append([]string(nil), keys...)
+		for _, column := range columns {
+			row = append(row, result(cell(column)))
+		}
+		if opts.Totals {
+			row = append(row, result(total))
+		}
+		summaryTable.rows = append(summaryTable.rows, row)
+		summaryTable.stats.Rows++
+	}
+	for _, summary := range rows {
+		addRow(summary.keys, func(column string) *group { return summary.cells[column] }, &summary.total)
+	}
+	if opts.Totals {
+		keys := make([]string, len(opts.RowKeys))
+		keys[0] = totalLabel
+		addRow(keys, func(column string) *group { return columnTotals[column] }, &grandTotal)
+	}
+	return summaryTable, nil
+}
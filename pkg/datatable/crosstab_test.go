@@ -0,0 +1,112 @@
+package datatable
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCrosstab(t *testing.T) {
+	sales := mustTable(t, []string{"Region", "Quarter", "Channel", "Amount"},
+		[]string{"North", "Q1", "Web", "10"},
+		[]string{"North", "Q2", "Web", "20"},
+		[]string{"South", "Q1", "Shop", "5"},
+		[]string{"North", "Q1", "Shop", "2.5"},
+		[]string{"South", "Q2", "Web", ""})
+	sales.InferTypes()
+
+	tests := []struct {
+		name        string
+		table       *DataTable
+		opts        CrosstabOptions
+		wantColumns []string
+		want        [][]string
+		wantErr     string
+	}{
+		{
+			name:        "Sum With Totals",
+			table:       sales,
+			opts:        CrosstabOptions{RowKeys: []string{"Region"}, ColumnKeys: []string{"Quarter"}, Value: "Amount", Totals: true},
+			wantColumns: []string{"Region", "Q1", "Q2", "Total"},
+			want:        [][]string{{"North", "12.5", "20", "32.5"}, {"South", "5", "", "5"}, {"Total", "17.5", "20", "37.5"}},
+		},
+		{
+			name:        "Average Of Several Column Keys",
+			table:       sales,
+			opts:        CrosstabOptions{RowKeys: []string{"Region"}, ColumnKeys: []string{"Quarter", "Channel"}, Value: "Amount", Func: Average, Empty: "-"},
+			wantColumns: []string{"Region", "Q1 / Web", "Q2 / Web", "Q1 / Shop"},
+			want:        [][]string{{"North", "10", "20", "2.5"}, {"South", "-", "-", "5"}},
+		},
+		{
+			name:        "Count Of Rows",
+			table:       sales,
+			opts:        CrosstabOptions{RowKeys: []string{"Channel"}, ColumnKeys: []string{"Region"}, Func: Count, Totals: true, TotalLabel: "All", Empty: "0"},
+			wantColumns: []string{"Channel", "North", "South", "All"},
+			want:        [][]string{{"Web", "2", "1", "3"}, {"Shop", "1", "1", "2"}, {"All", "3", "2", "5"}},
+		},
+		{
+			name:        "Max",
+			table:       sales,
+			opts:        CrosstabOptions{RowKeys: []string{"Quarter"}, ColumnKeys: []string{"Region"}, Value: "Amount", Func: Max},
+			wantColumns: []string{"Quarter", "North", "South"},
+			want:        [][]string{{"Q1", "10", "5"}, {"Q2", "20", ""}},
+		},
+		{
+			name:        "Concat",
+			table:       sales,
+			opts:        CrosstabOptions{RowKeys: []string{"Region"}, ColumnKeys: []string{"Quarter"}, Value: "Channel", Func: Concat},
+			wantColumns: []string{"Region", "Q1", "Q2"},
+			want:        [][]string{{"North", "Web, Shop", "Web"}, {"South", "Shop", "Web"}},
+		},
+		{
+			name:    "Not A Number",
+			table:   mustTable(t, []string{"Region", "Quarter", "Amount"}, []string{"North", "Q1", "ten"}),
+			opts:    CrosstabOptions{RowKeys: []string{"Region"}, ColumnKeys: []string{"Quarter"}, Value: "Amount"},
+			wantErr: "row 1: 'ten' in column 'Amount' is not a number",
+		},
+		{
+			name:    "Column Clashing With Row Key",
+			table:   mustTable(t, []string{"Region", "Quarter"}, []string{"North", "Region"}),
+			opts:    CrosstabOptions{RowKeys: []string{"Region"}, ColumnKeys: []string{"Quarter"}, Func: Count},
+			wantErr: "the column 'Region' is defined twice",
+		},
+		{
+			name:    "Unknown Function",
+			table:   sales,
+			opts:    CrosstabOptions{RowKeys: []string{"Region"}, ColumnKeys: []string{"Quarter"}, Value: "Amount", Func: Concat + 1},
+			wantErr: "unknown aggregate function 'AggregateFunc(7)'",
+		},
+		{
+			name:    "No Value Column",
+			table:   sales,
+			opts:    CrosstabOptions{RowKeys: []string{"Region"}, ColumnKeys: []string{"Quarter"}},
+			wantErr: "no value column to aggregate",
+		},
+		{
+			name:    "Unknown Column",
+			table:   sales,
+			opts:    CrosstabOptions{RowKeys: []string{"Country"}, ColumnKeys: []string{"Quarter"}, Value: "Amount"},
+			wantErr: "unknown column 'Country'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.table.Crosstab(tt.opts)
+			if len(tt.wantErr) > 0 {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Crosstab() error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Crosstab() error = %v", err)
+			}
+			if !reflect.DeepEqual(got.ColumnNames(), tt.wantColumns) {
+				t.Errorf("Crosstab() columns = %v, want %v", got.ColumnNames(), tt.wantColumns)
+			}
+			if !reflect.DeepEqual(got.Records(), tt.want) {
+				t.Errorf("Crosstab() = %v, want %v", got.Records(), tt.want)
+			}
+		})
+	}
+}
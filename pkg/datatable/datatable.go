@@ -0,0 +1,312 @@
+// Package datatable holds tabular data in memory as a DataTable: named, typed columns and rows of string values,
+// the model the converters read worksheets into and the transforms shape before the data is written out.
+package datatable
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Column is a column of a DataTable: its name and the type of its values.
+type Column struct {
+	Name string
+	Type ColumnType
+}
+
+// DataTable is a table of rows, each holding a value per column. Values are kept as the strings they were read
+// as, blank for missing values, and read as the type of their column by the accessors of Row.
+// The zero DataTable is an empty table without columns.
+type DataTable struct {
+	columns []Column
+	index   map[string]int
+	rows    [][]string
+	policy  RowPolicy
+	stats   RowStats
+}
+
+// New returns an empty table with columns of the given names, all of StringType.
+func New(names ...string) (*DataTable, error) {
+	columns := make([]Column, len(names))
+	for position, name := range names {
+		columns[position] = Column{Name: name}
+	}
+	return NewWithColumns(columns...)
+}
+
+// NewWithColumns returns an empty table with the given columns, whose names must be distinct and not blank.
+func NewWithColumns(columns ...Column) (*DataTable, error) {
+	t := &DataTable{index: make(map[string]int, len(columns))}
+	for _, column := range columns {
+		if err := t.addColumn(column); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+// FromRecords returns a table with the columns named by header and a row per record, fitted to the header as
+// PadRows does: records shorter than the header are padded with blank values and longer ones are an error,
+// unless their extra values are empty.
+func FromRecords(header []string, records [][]string) (*DataTable, error) {
+	t, err := New(header...)
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		if err := t.AddRow(record...); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+// addColumn adds column after the last column of the table.
+func (t *DataTable) addColumn(column Column) error {
+	if len(strings.TrimSpace(column.Name)) < 1 {
+		return errors.New("a column has no name")
+	}
+	if _, exists := t.index[column.Name]; exists {
+		return fmt.Errorf("the column '%s' is defined twice", column.Name)
+	}
+	if t.index == nil {
+		t.index = make(map[string]int)
+	}
+	t.index[column.Name] = len(t.columns)
+	t.columns = append(t.columns, column)
+	return nil
+}
+
+// AddColumn adds column after the last column of the table, blank in every row.
+func (t *DataTable) AddColumn(column Column) error {
+	if err := t.addColumn(column); err != nil {
+		return err
+	}
+	for position := range t.rows {
+		t.rows[position] = append(t.rows[position], "")
+	}
+	return nil
+}
+
+// Columns returns the columns of the table, in their order.
+func (t *DataTable) Columns() []Column {
+	return append([]Column(nil), t.columns...)
+}
+
+// ColumnNames returns the names of the columns of the table, in their order.
+func (t *DataTable) ColumnNames() []string {
+	names := make([]string, len(t.columns))
+	for position, column := range t.columns {
+		names[position] = column.Name
+	}
+	return names
+}
+
+// Column returns the column called name.
+func (t *DataTable) Column(name string) (Column, error) {
+	position, err := t.ColumnIndex(name)
+	if err != nil {
+		return Column{}, err
+	}
+	return t.columns[position], nil
+}
+
+// ColumnIndex returns the zero-based position of the column called name.
+func (t *DataTable) ColumnIndex(name string) (int, error) {
+	position, known := t.index[name]
+	if !known {
+		return -1, fmt.Errorf("unknown column '%s'", name)
+	}
+	return position, nil
+}
+
+// HasColumn reports whether the table has a column called name.
+func (t *DataTable) HasColumn(name string) bool {
+	_, known := t.index[name]
+	return known
+}
+
+// SetColumnType sets the type of the column called name.
+func (t *DataTable) SetColumnType(name string, columnType ColumnType) error {
+	position, err := t.ColumnIndex(name)
+	if err != nil {
+		return err
+	}
+	t.columns[position].Type = columnType
+	return nil
+}
+
+// Len returns the number of rows of the table.
+func (t *DataTable) Len() int {
+	return len(t.rows)
+}
+
+// AddRow adds a row holding values, in the order of the columns, after the last row of the table. Rows without
+// a value per column are fitted to the columns as the row policy of the table says, see SetRowPolicy.
+func (t *DataTable) AddRow(values ...string) error {
+	row, err := t.fit(values)
+	if err != nil {
+		return err
+	}
+	t.rows = append(t.rows, row)
+	return nil
+}
+
+// AddRecord adds a row holding the values of record keyed by column name; columns missing from record are blank
+// and keys naming no column are an error.
+func (t *DataTable) AddRecord(record map[string]string) error {
+	row := make([]string, len(t.columns))
+	for name, value := range record {
+		position, err := t.ColumnIndex(name)
+		if err != nil {
+			return err
+		}
+		row[position] = value
+	}
+	t.stats.Rows++
+	t.rows = append(t.rows, row)
+	return nil
+}
+
+// Row returns the row at the zero-based index, which must be below Len.
+func (t *DataTable) Row(index int) Row {
+	return Row{table: t, index: index, values: t.rows[index]}
+}
+
+// Each calls yield with every row of the table in order, stopping at the first error it returns.
+func (t *DataTable) Each(yield func(row Row) error) error {
+	for index := range t.rows {
+		if err := yield(t.Row(index)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Records returns a copy of the values of every row, in the order of the columns.
+func (t *DataTable) Records() [][]string {
+	records := make([][]string, len(t.rows))
+	for index, row := range t.rows {
+		records[index] = append([]string(nil), row...)
+	}
+	return records
+}
+
+// Clone returns a copy of the table which can be changed without changing t.
+func (t *DataTable) Clone() *DataTable {
+	clone := t.empty()
+	clone.rows = t.Records()
+	clone.stats = t.stats
+	return clone
+}
+
+// empty returns a table with the columns of t and no rows.
+func (t *DataTable) empty() *DataTable {
+	clone := &DataTable{columns: t.Columns(), index: make(map[string]int, len(t.columns)), policy: t.policy}
+	for name, position := range t.index {
+		clone.index[name] = position
+	}
+	return clone
+}
+
+// Row is a row of a DataTable. Changing its values with Set changes the table.
+type Row struct {
+	table  *DataTable
+	index  int
+	values []string
+}
+
+// Index returns the zero-based index of the row in its table.
+func (r Row) Index() int {
+	return r.index
+}
+
+// Get returns the value of the column called name, blank when the table has no such column.
+func (r Row) Get(name string) string {
+	if position, known := r.table.index[name]; known {
+		return r.values[position]
+	}
+	return ""
+}
+
+// Lookup returns the value of the column called name and whether the table has such a column.
+func (r Row) Lookup(name string) (string, bool) {
+	position, known := r.table.index[name]
+	if !known {
+		return "", false
+	}
+	return r.values[position], true
+}
+
+// Set sets the value of the column called name.
+func (r Row) Set(name, value string) error {
+	position, err := r.table.ColumnIndex(name)
+	if err != nil {
+		return err
+	}
+	r.values[position] = value
+	return nil
+}
+
+// Values returns a copy of the values of the row, in the order of the columns.
+func (r Row) Values() []string {
+	return append([]string(nil), r.values...)
+}
+
+// Map returns the values of the row keyed by column name.
+func (r Row) Map() map[string]string {
+	record := make(map[string]string, len(r.values))
+	for position, column := range r.table.columns {
+		record[column.Name] = r.values[position]
+	}
+	return record
+}
+
+// Value returns the value of the column called name read as the type of the column: a string, an int64,
+// a float64, a bool or a time.Time, or nil when it is blank.
+func (r Row) Value(name string) (any, error) {
+	column, err := r.table.Column(name)
+	if err != nil {
+		return nil, err
+	}
+	return column.Type.Parse(r.Get(name))
+}
+
+// Int returns the value of the column called name as a whole number.
+func (r Row) Int(name string) (int64, error) {
+	return typed[int64](r, name, IntegerType)
+}
+
+// Float returns the value of the column called name as a number.
+func (r Row) Float(name string) (float64, error) {
+	return typed[float64](r, name, DecimalType)
+}
+
+// Bool returns the value of the column called name as a boolean.
+func (r Row) Bool(name string) (bool, error) {
+	return typed[bool](r, name, BooleanType)
+}
+
+// Time returns the value of the column called name as a date and time.
+func (r Row) Time(name string) (time.Time, error) {
+	return typed[time.Time](r, name, DateTimeType)
+}
+
+// typed returns the value of the column called name read as columnType, whatever the type of the column.
+// Blank values are an error.
+func typed[T any](r Row, name string, columnType ColumnType) (T, error) {
+	var zero T
+	value, found := r.Lookup(name)
+	if !found {
+		return zero, fmt.Errorf("unknown column '%s'", name)
+	}
+	parsed, err := columnType.Parse(value)
+	if err != nil {
+		return zero, fmt.Errorf("row %d: %w", r.index+1, err)
+	}
+	if parsed == nil {
+		return zero, fmt.Errorf("row %d: the column '%s' is blank", r.index+1, name)
+	}
+	return parsed.(T), nil
+}
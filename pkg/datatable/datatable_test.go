@@ -0,0 +1,147 @@
+package datatable
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// mustTable returns the table of header and records, failing the test on error.
+func mustTable(t *testing.T, header []string, records ...[]string) *DataTable {
+	t.Helper()
+	table, err := FromRecords(header, records)
+	if err != nil {
+		t.Fatalf("FromRecords() error = %v", err)
+	}
+	return table
+}
+
+func TestDataTable(t *testing.T) {
+	table := mustTable(t, []string{"Item", "Qty", "Price"}, []string{"Bolt", "3", "0.25"}, []string{"Nut"})
+	if table.Len() != 2 || !reflect.DeepEqual(table.ColumnNames(), []string{"Item", "Qty", "Price"}) {
+		t.Fatalf("FromRecords() = %d rows, columns %v", table.Len(), table.ColumnNames())
+	}
+	if want := [][]string{{"Bolt", "3", "0.25"}, {"Nut", "", ""}}; !reflect.DeepEqual(table.Records(), want) {
+		t.Errorf("Records() = %v, want %v", table.Records(), want)
+	}
+	row := table.Row(0)
+	if row.Get("Item") != "Bolt" || row.Get("Colour") != "" || row.Index() != 0 {
+		t.Errorf("Row(0) = %v", row.Values())
+	}
+	if want := map[string]string{"Item": "Nut", "Qty": "", "Price": ""}; !reflect.DeepEqual(table.Row(1).Map(), want) {
+		t.Errorf("Row(1).Map() = %v, want %v", table.Row(1).Map(), want)
+	}
+	if err := table.Row(1).Set("Qty", "10"); err != nil || table.Row(1).Get("Qty") != "10" {
+		t.Errorf("Set() error = %v, value %q", err, table.Row(1).Get("Qty"))
+	}
+	if err := row.Set("Colour", "red"); err == nil || err.Error() != "unknown column 'Colour'" {
+		t.Errorf("Set(Colour) error = %v", err)
+	}
+
+	clone := table.Clone()
+	_ = clone.Row(0).Set("Item", "Screw")
+	if table.Row(0).Get("Item") != "Bolt" {
+		t.Errorf("Clone() shares its rows with the table")
+	}
+	if err := table.AddColumn(Column{Name: "Note", Type: StringType}); err != nil || table.Row(1).Get("Note") != "" || len(table.Row(1).Values()) != 4 {
+		t.Errorf("AddColumn() error = %v, row %v", err, table.Row(1).Values())
+	}
+	if err := table.AddRecord(map[string]string{"Item": "Washer", "Note": "new"}); err != nil {
+		t.Fatalf("AddRecord() error = %v", err)
+	}
+	if values := table.Row(2).Values(); !reflect.DeepEqual(values, []string{"Washer", "", "", "new"}) {
+		t.Errorf("AddRecord() row = %v", values)
+	}
+	var items []string
+	_ = table.Each(func(row Row) error {
+		items = append(items, row.Get("Item"))
+		return nil
+	})
+	if want := []string{"Bolt", "Nut", "Washer"}; !reflect.DeepEqual(items, want) {
+		t.Errorf("Each() items = %v, want %v", items, want)
+	}
+}
+
+func TestDataTableErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		build   func() error
+		wantErr string
+	}{
+		{name: "Repeated Column", build: func() error { _, err := New("Id", "Id"); return err }, wantErr: "the column 'Id' is defined twice"},
+		{name: "Blank Column", build: func() error { _, err := New("Id", " "); return err }, wantErr: "a column has no name"},
+		{name: "Long Record", build: func() error {
+			_, err := FromRecords([]string{"Id"}, [][]string{{"1", "2"}})
+			return err
+		}, wantErr: "row 1 has 2 values for 1 columns"},
+		{name: "Unknown Key", build: func() error {
+			table, _ := New("Id")
+			return table.AddRecord(map[string]string{"Name": "x"})
+		}, wantErr: "unknown column 'Name'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.build(); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want %s", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTypedColumns(t *testing.T) {
+	table := mustTable(t, []string{"Zip", "Qty", "Price", "Paid", "Due", "Stamp", "Note"},
+		[]string{"01234", "3", "2.5", "true", "2024-03-01", "2024-03-01T10:30:00Z", "x"},
+		[]string{"98765", "", "4", "false", "2024-04-01", "2024-04-01 08:00:00", ""})
+	table.InferTypes()
+	var types []ColumnType
+	for _, column := range table.Columns() {
+		types = append(types, column.Type)
+	}
+	if want := []ColumnType{StringType, IntegerType, DecimalType, BooleanType, DateType, DateTimeType, StringType}; !reflect.DeepEqual(types, want) {
+		t.Errorf("InferTypes() = %v, want %v", types, want)
+	}
+
+	row := table.Row(0)
+	if qty, err := row.Int("Qty"); err != nil || qty != 3 {
+		t.Errorf("Int(Qty) = %v, %v", qty, err)
+	}
+	if price, err := row.Float("Price"); err != nil || price != 2.5 {
+		t.Errorf("Float(Price) = %v, %v", price, err)
+	}
+	if paid, err := row.Bool("Paid"); err != nil || !paid {
+		t.Errorf("Bool(Paid) = %v, %v", paid, err)
+	}
+	if due, err := row.Time("Due"); err != nil || !due.Equal(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Time(Due) = %v, %v", due, err)
+	}
+	if value, err := row.Value("Zip"); err != nil || value != "01234" {
+		t.Errorf("Value(Zip) = %#v, %v", value, err)
+	}
+	if value, err := table.Row(1).Value("Qty"); err != nil || value != nil {
+		t.Errorf("Value(blank Qty) = %#v, %v", value, err)
+	}
+	if _, err := table.Row(1).Int("Qty"); err == nil || err.Error() != "row 2: the column 'Qty' is blank" {
+		t.Errorf("Int(blank Qty) error = %v", err)
+	}
+	if _, err := row.Int("Note"); err == nil || err.Error() != "row 1: 'x' is not a whole number" {
+		t.Errorf("Int(Note) error = %v", err)
+	}
+	if err := table.SetColumnType("Zip", IntegerType); err != nil {
+		t.Fatalf("SetColumnType() error = %v", err)
+	}
+	if value, _ := row.Value("Zip"); value != int64(1234) {
+		t.Errorf("Value(Zip) as integer = %#v", value)
+	}
+}
+
+func TestParseColumnType(t *testing.T) {
+	for _, columnType := range []ColumnType{StringType, IntegerType, DecimalType, BooleanType, DateType, DateTimeType} {
+		if got, err := ParseColumnType(columnType.String()); err != nil || got != columnType {
+			t.Errorf("ParseColumnType(%s) = %v, %v", columnType, got, err)
+		}
+	}
+	if _, err := ParseColumnType("money"); err == nil || err.Error() != "unknown column type 'money'" {
+		t.Errorf("ParseColumnType(money) error = %v", err)
+	}
+}
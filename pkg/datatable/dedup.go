@@ -0,0 +1,129 @@
+package datatable
+
+import (
+	"fmt"
+	"strings"
+)
+
+// KeepPolicy selects which row DedupRows keeps of rows with equal keys.
+type KeepPolicy int
+
+const (
+	// KeepFirst keeps the first of the rows. It is the default.
+	KeepFirst KeepPolicy = iota
+	// KeepLast keeps the last of the rows, in the place of the first.
+	KeepLast
+	// Collect keeps a single row whose non-key columns join the distinct non-blank values of the rows.
+	Collect
+)
+
+// ParseKeepPolicy returns the KeepPolicy called first, last or collect.
+func ParseKeepPolicy(name string) (KeepPolicy, error) {
+	switch strings.ToLower(name) {
+	case "", "first":
+		return KeepFirst, nil
+	case "last":
+		return KeepLast, nil
+	case "collect":
+		return Collect, nil
+	}
+	return KeepFirst, fmt.Errorf("unknown keep policy '%s'", name)
+}
+
+// DedupOptions configures DedupRows.
+// IgnoreCase compares key values regardless of case and of surrounding spaces.
+// Separator separates the values joined by Collect, ", " by default.
+type DedupOptions struct {
+	Keep       KeepPolicy
+	IgnoreCase bool
+	Separator  string
+}
+
+// DedupRows returns t with a single row per distinct combination of the values of the key columns, every column
+// when keys is empty, kept by opts.Keep in the place of the first of its rows, and a table of the removed rows
+// for audit: the columns of t preceded by Row, the one-based number of the removed row in t, and Kept, the
+// number of the row kept in its place; t must not have columns of these names.
+// Example usage:
+//
+//	deduped, removed, err := customers.DedupRows([]string{"Email"}, DedupOptions{IgnoreCase: true})
+func (t *DataTable) DedupRows(keys []string, opts DedupOptions) (deduped, removed *DataTable, err error) {
+	if len(keys) < 1 {
+		keys = t.ColumnNames()
+	}
+	positions, err := t.positions(keys)
+	if err != nil {
+		return nil, nil, err
+	}
+	removed, err = NewWithColumns(append([]Column{{Name: "Row", Type: IntegerType}, {Name: "Kept", Type: IntegerType}}, t.columns...)...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("removed rows: %w", err)
+	}
+	separator := opts.Separator
+	if len(separator) < 1 {
+		separator = defaultConcatSeparator
+	}
+
+	type kept struct {
+		members []int
+	}
+	var order []*kept
+	byKey := make(map[string]*kept)
+	for index, values := range t.rows {
+		parts := make([]string, len(positions))
+		for position, keyPosition := range positions {
+			parts[position] = values[keyPosition]
+			if opts.IgnoreCase {
+				parts[position] = strings.ToLower(strings.TrimSpace(parts[position]))
+			}
+		}
+		id := strings.Join(parts, "\x1f")
+		group, known := byKey[id]
+		if !known {
+			group = &kept{}
+			byKey[id] = group
+			order = append(order, group)
+		}
+		group.members = append(group.members, index)
+	}
+
+	deduped = t.empty()
+	isKey := make(map[int]bool, len(positions))
+	for _, position := range positions {
+		isKey[position] = true
+	}
+	for _, group := range order {
+		keptIndex := group.members[0]
+		if opts.Keep == KeepLast {
+			keptIndex = group.members[len(group.members)-1]
+		}
+		row := append([]string(nil), t.rows[keptIndex]...)
+		if opts.Keep == Collect && len(group.members) > 1 {
+			for position := range row {
+				if isKey[position] {
+					continue
+				}
+				var values []string
+				seen := make(map[string]bool)
+				for _, member := range group.members {
+					value := t.rows[member][position]
+					if len(strings.TrimSpace(value)) > 0 && !seen[value] {
+						seen[value] = true
+						values = append(values, value)
+					}
+				}
+				row[position] = strings.Join(values, separator)
+			}
+		}
+		deduped.rows = append(deduped.rows, row)
+		deduped.stats.Rows++
+		for _, member := range group.members {
+			if member == keptIndex {
+				continue
+			}
+			record := append([]string{fmt.Sprint(member + 1), fmt.Sprint(keptIndex + 1)}, t.rows[member]...)
+			removed.rows = append(removed.rows, record)
+			removed.stats.Rows++
+		}
+	}
+	return deduped, removed, nil
+}
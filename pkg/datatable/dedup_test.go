@@ -0,0 +1,76 @@
+package datatable
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDedupRows(t *testing.T) {
+	customers := mustTable(t, []string{"Email", "Name", "Phone"},
+		[]string{"ann@example.com", "Ann", "111"},
+		[]string{"bob@example.com", "Bob", ""},
+		[]string{"ANN@example.com ", "Ann B.", "222"},
+		[]string{"ann@example.com", "Ann", "333"})
+
+	tests := []struct {
+		name        string
+		keys        []string
+		opts        DedupOptions
+		want        [][]string
+		wantRemoved [][]string
+	}{
+		{
+			name:        "First",
+			keys:        []string{"Email"},
+			want:        [][]string{{"ann@example.com", "Ann", "111"}, {"bob@example.com", "Bob", ""}, {"ANN@example.com ", "Ann B.", "222"}},
+			wantRemoved: [][]string{{"4", "1", "ann@example.com", "Ann", "333"}},
+		},
+		{
+			name: "Last Ignoring Case",
+			keys: []string{"Email"},
+			opts: DedupOptions{Keep: KeepLast, IgnoreCase: true},
+			want: [][]string{{"ann@example.com", "Ann", "333"}, {"bob@example.com", "Bob", ""}},
+			wantRemoved: [][]string{
+				{"1", "4", "ann@example.com", "Ann", "111"},
+				{"3", "4", "ANN@example.com ", "Ann B.", "222"},
+			},
+		},
+		{
+			name:        "Collect",
+			keys:        []string{"Email"},
+			opts:        DedupOptions{Keep: Collect, IgnoreCase: true, Separator: "|"},
+			want:        [][]string{{"ann@example.com", "Ann|Ann B.", "111|222|333"}, {"bob@example.com", "Bob", ""}},
+			wantRemoved: [][]string{{"3", "1", "ANN@example.com ", "Ann B.", "222"}, {"4", "1", "ann@example.com", "Ann", "333"}},
+		},
+		{
+			name:        "Every Column",
+			want:        [][]string{{"ann@example.com", "Ann", "111"}, {"bob@example.com", "Bob", ""}, {"ANN@example.com ", "Ann B.", "222"}, {"ann@example.com", "Ann", "333"}},
+			wantRemoved: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deduped, removed, err := customers.DedupRows(tt.keys, tt.opts)
+			if err != nil {
+				t.Fatalf("DedupRows() error = %v", err)
+			}
+			if !reflect.DeepEqual(deduped.Records(), tt.want) {
+				t.Errorf("DedupRows() = %v, want %v", deduped.Records(), tt.want)
+			}
+			if got := removed.Records(); len(got) > 0 || len(tt.wantRemoved) > 0 {
+				if !reflect.DeepEqual(got, tt.wantRemoved) {
+					t.Errorf("DedupRows() removed = %v, want %v", got, tt.wantRemoved)
+				}
+			}
+			if want := []string{"Row", "Kept", "Email", "Name", "Phone"}; !reflect.DeepEqual(removed.ColumnNames(), want) {
+				t.Errorf("DedupRows() removed columns = %v, want %v", removed.ColumnNames(), want)
+			}
+		})
+	}
+	if _, _, err := customers.DedupRows([]string{"Id"}, DedupOptions{}); err == nil || err.Error() != "unknown column 'Id'" {
+		t.Errorf("DedupRows(Id) error = %v", err)
+	}
+	if _, err := ParseKeepPolicy("middle"); err == nil || err.Error() != "unknown keep policy 'middle'" {
+		t.Errorf("ParseKeepPolicy(middle) error = %v", err)
+	}
+}
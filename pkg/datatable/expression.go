@@ -0,0 +1,798 @@
+package datatable
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	. "GoTools/pkg/helpers"
+)
+
+// Expression is a formula computed from the values of a row, such as Amount * 1.21 or EndDate >= StartDate.
+//
+// Columns are named as they are when their name is a word, or between square brackets, as in [Unit Price].
+// Literals are numbers, strings between single or double quotes, a quote being written twice inside them, and
+// true, false and null. The operators are, from the loosest to the tightest: or (||), and (&&), not (!),
+// the comparisons = (==), != (<>), <, <=, > and >=, + and -, & concatenating text, *, / and %, and unary minus.
+// The functions are abs, coalesce, concat, contains, endswith, if, isblank, left, len, lower, max, min,
+// replace, right, round, startswith, substr, trim and upper, and for dates adddays, date, day, days, format,
+// month, today and year; see functions.
+//
+// Values of columns are read as the type of their column, blank values being null, which compares equal to blank
+// text; arithmetic reads text as a number and comparisons compare numbers, dates and booleans as such when
+// both sides read as one, text otherwise.
+type Expression struct {
+	text    string
+	root    node
+	columns []string
+}
+
+// ParseExpression returns the expression written in text.
+func ParseExpression(text string) (*Expression, error) {
+	tokens, err := tokenize(text)
+	if err != nil {
+		return nil, fmt.Errorf("expression '%s': %w", text, err)
+	}
+	parser := &expressionParser{tokens: tokens, seen: make(map[string]bool)}
+	root, err := parser.parseOr()
+	if err == nil && parser.position < len(tokens) {
+		err = fmt.Errorf("unexpected '%s' at %d", tokens[parser.position].text, tokens[parser.position].offset+1)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("expression '%s': %w", text, err)
+	}
+	return &Expression{text: text, root: root, columns: parser.columns}, nil
+}
+
+// String returns the text of the expression.
+func (e *Expression) String() string {
+	return e.text
+}
+
+// Columns returns the names of the columns the expression reads, in the order they are first met.
+func (e *Expression) Columns() []string {
+	return e.columns
+}
+
+// Check fails when table lacks a column the expression reads.
+func (e *Expression) Check(table *DataTable) error {
+	_, err := table.positions(e.columns)
+	return err
+}
+
+// Eval returns the value of the expression for row: a string, a float64, a bool, a time.Time or nil.
+func (e *Expression) Eval(row Row) (any, error) {
+	value, err := e.root.eval(row)
+	if err != nil && row.index < 0 {
+		return nil, fmt.Errorf("%s: %w", e.text, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("row %d: %s: %w", row.index+1, e.text, err)
+	}
+	return value, nil
+}
+
+// Test returns whether the expression holds for row; it must compute a boolean, null counting as false.
+func (e *Expression) Test(row Row) (bool, error) {
+	value, err := e.Eval(row)
+	if err != nil {
+		return false, err
+	}
+	switch value := value.(type) {
+	case bool:
+		return value, nil
+	case nil:
+		return false, nil
+	}
+	return false, fmt.Errorf("row %d: %s computes %v, not a boolean", row.index+1, e.text, value)
+}
+
+// FormatValue returns a value computed by an expression as the text of a cell: numbers in their shortest form,
+// dates in ISO 8601, without a time at midnight, and null as blank.
+func FormatValue(value any) string {
+	switch value := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(value)
+	case time.Time:
+		if value.Hour() == 0 && value.Minute() == 0 && value.Second() == 0 && value.Nanosecond() == 0 {
+			return value.Format(time.DateOnly)
+		}
+		return value.Format(time.RFC3339)
+	}
+	return fmt.Sprint(value)
+}
+
+// tokenKind tells what a token of an expression is.
+type tokenKind int
+
+const (
+	numberToken tokenKind = iota
+	stringToken
+	nameToken
+	columnToken
+	operatorToken
+)
+
+// token is a token of an expression, at offset bytes into its text.
+type token struct {
+	kind   tokenKind
+	text   string
+	offset int
+}
+
+// operators are the operators of expressions, the two-character ones first.
+var operators = []string{"==", "!=", "<>", "<=", ">=", "&&", "||", "=", "<", ">", "+", "-", "*", "/", "%", "&", "!", "(", ")", ","}
+
+// tokenize splits text into tokens.
+func tokenize(text string) ([]token, error) {
+	var tokens []token
+	for offset := 0; offset < len(text); {
+		rest := text[offset:]
+		char := rune(rest[0])
+		switch {
+		case unicode.IsSpace(char):
+			offset++
+		case char >= '0' && char <= '9' || char == '.' && len(rest) > 1 && rest[1] >= '0' && rest[1] <= '9':
+			end := 1
+			for end < len(rest) && (rest[end] >= '0' && rest[end] <= '9' || rest[end] == '.') {
+				end++
+			}
+			tokens = append(tokens, token{kind: numberToken, text: rest[:end], offset: offset})
+			offset += end
+		case char == '\'' || char == '"':
+			var value strings.Builder
+			end := 1
+			for {
+				if end >= len(rest) {
+					return nil, fmt.Errorf("unterminated string at %d", offset+1)
+				}
+				if rest[end] == byte(char) {
+					if end+1 < len(rest) && rest[end+1] == byte(char) {
+						value.WriteByte(byte(char))
+						end += 2
+						continue
+					}
+					break
+				}
+				value.WriteByte(rest[end])
+				end++
+			}
+			tokens = append(tokens, token{kind: stringToken, text: value.String(), offset: offset})
+			offset += end + 1
+		case char == '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated column name at %d", offset+1)
+			}
+			tokens = append(tokens, token{kind: columnToken, text: rest[1:end], offset: offset})
+			offset += end + 1
+		case char == '_' || char >= 0x80 || unicode.IsLetter(char):
+			end := strings.IndexFunc(rest, func(r rune) bool {
+				return r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r)
+			})
+			if end < 0 {
+				end = len(rest)
+			}
+			tokens = append(tokens, token{kind: nameToken, text: rest[:end], offset: offset})
+			offset += end
+		default:
+			found := false
+			for _, operator := range operators {
+				if strings.HasPrefix(rest, operator) {
+					tokens = append(tokens, token{kind: operatorToken, text: operator, offset: offset})
+					offset += len(operator)
+					found = true
+					break
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf("unexpected '%c' at %d", char, offset+1)
+			}
+		}
+	}
+	return tokens, nil
+}
+
+// expressionParser parses tokens into the nodes of an expression by recursive descent.
+type expressionParser struct {
+	tokens   []token
+	position int
+	columns  []string
+	seen     map[string]bool
+}
+
+// peek returns whether the next token is the operator or the keyword, ignoring case, called one of names.
+func (p *expressionParser) peek(names ...string) (string, bool) {
+	if p.position >= len(p.tokens) {
+		return "", false
+	}
+	next := p.tokens[p.position]
+	if next.kind != operatorToken && next.kind != nameToken {
+		return "", false
+	}
+	for _, name := range names {
+		if next.kind == operatorToken && next.text == name || next.kind == nameToken && strings.EqualFold(next.text, name) {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// expect consumes the operator, failing when the next token is another one.
+func (p *expressionParser) expect(operator string) error {
+	if _, found := p.peek(operator); !found {
+		if p.position >= len(p.tokens) {
+			return fmt.Errorf("missing '%s' at the end", operator)
+		}
+		return fmt.Errorf("expected '%s' at %d", operator, p.tokens[p.position].offset+1)
+	}
+	p.position++
+	return nil
+}
+
+// binary parses a left-associative sequence of operands joined by the operators, operands being parsed by next.
+func (p *expressionParser) binary(next func() (node, error), operators map[string]string) (node, error) {
+	left, err := next()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(operators))
+	for name := range operators {
+		names = append(names, name)
+	}
+	for {
+		name, found := p.peek(names...)
+		if !found {
+			return left, nil
+		}
+		p.position++
+		right, err := next()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{operator: operators[name], left: left, right: right}
+	}
+}
+
+func (p *expressionParser) parseOr() (node, error) {
+	return p.binary(p.parseAnd, map[string]string{"or": "or", "||": "or"})
+}
+
+func (p *expressionParser) parseAnd() (node, error) {
+	return p.binary(p.parseNot, map[string]string{"and": "and", "&&": "and"})
+}
+
+func (p *expressionParser) parseNot() (node, error) {
+	if _, found := p.peek("not", "!"); found {
+		p.position++
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{operand: operand}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *expressionParser) parseComparison() (node, error) {
+	return p.binary(p.parseAdditive, map[string]string{"=": "=", "==": "=", "!=": "!=", "<>": "!=", "<": "<", "<=": "<=", ">": ">", ">=": ">="})
+}
+
+func (p *expressionParser) parseAdditive() (node, error) {
+	return p.binary(p.parseTerm, map[string]string{"+": "+", "-": "-", "&": "&"})
+}
+
+func (p *expressionParser) parseTerm() (node, error) {
+	return p.binary(p.parseUnary, map[string]string{"*": "*", "/": "/", "%": "%"})
+}
+
+func (p *expressionParser) parseUnary() (node, error) {
+	if _, found := p.peek("-"); found {
+		p.position++
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return binaryNode{operator: "-", left: literalNode{value: 0.0}, right: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *expressionParser) parsePrimary() (node, error) {
+	if p.position >= len(p.tokens) {
+		return nil, errors.New("unexpected end")
+	}
+	next := p.tokens[p.position]
+	p.position++
+	switch next.kind {
+	case numberToken:
+		number, err := strconv.ParseFloat(next.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("'%s' at %d is not a number", next.text, next.offset+1)
+		}
+		return literalNode{value: number}, nil
+	case stringToken:
+		return literalNode{value: next.text}, nil
+	case columnToken:
+		return p.column(next.text), nil
+	case nameToken:
+		switch strings.ToLower(next.text) {
+		case "true":
+			return literalNode{value: true}, nil
+		case "false":
+			return literalNode{value: false}, nil
+		case "null":
+			return literalNode{}, nil
+		}
+		if _, found := p.peek("("); !found {
+			return p.column(next.text), nil
+		}
+		p.position++
+		function, known := functions[strings.ToLower(next.text)]
+		if !known {
+			return nil, fmt.Errorf("unknown function '%s' at %d", next.text, next.offset+1)
+		}
+		var args []node
+		if _, found := p.peek(")"); !found {
+			for {
+				arg, err := p.parseOr()
+				if err != nil {
+					return nil, err
+				}
+				args = append(args, arg)
+				if _, found := p.peek(","); !found {
+					break
+				}
+				p.position++
+			}
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		if len(args) < function.minArgs || function.maxArgs >= 0 && len(args) > function.maxArgs {
+			return nil, fmt.Errorf("%s takes %s, not %d", next.text, function.arity(), len(args))
+		}
+		return callNode{name: strings.ToLower(next.text), function: function, args: args}, nil
+	case operatorToken:
+		if next.text == "(" {
+			inner, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			return inner, p.expect(")")
+		}
+	}
+	return nil, fmt.Errorf("unexpected '%s' at %d", next.text, next.offset+1)
+}
+
+// column returns the node reading the column called name, noting that the expression reads it.
+func (p *expressionParser) column(name string) node {
+	if !p.seen[name] {
+		p.seen[name] = true
+		p.columns = append(p.columns, name)
+	}
+	return columnNode{name: name}
+}
+
+// node is a node of the tree of an expression.
+type node interface {
+	eval(row Row) (any, error)
+}
+
+type literalNode struct {
+	value any
+}
+
+func (n literalNode) eval(Row) (any, error) {
+	return n.value, nil
+}
+
+type columnNode struct {
+	name string
+}
+
+func (n columnNode) eval(row Row) (any, error) {
+	value, err := row.Value(n.name)
+	if err != nil {
+		return nil, err
+	}
+	switch value := value.(type) {
+	case int64:
+		return float64(value), nil
+	case string:
+		if len(strings.TrimSpace(value)) < 1 {
+			return nil, nil
+		}
+	}
+	return value, nil
+}
+
+type notNode struct {
+	operand node
+}
+
+func (n notNode) eval(row Row) (any, error) {
+	value, err := n.operand.eval(row)
+	if err != nil {
+		return nil, err
+	}
+	truth, err := truthOf(value)
+	return !truth, err
+}
+
+type binaryNode struct {
+	operator    string
+	left, right node
+}
+
+func (n binaryNode) eval(row Row) (any, error) {
+	left, err := n.left.eval(row)
+	if err != nil {
+		return nil, err
+	}
+	switch n.operator {
+	case "and", "or":
+		truth, err := truthOf(left)
+		if err != nil || truth == (n.operator == "or") {
+			return truth, err
+		}
+		right, err := n.right.eval(row)
+		if err != nil {
+			return nil, err
+		}
+		return truthOf(right)
+	}
+	right, err := n.right.eval(row)
+	if err != nil {
+		return nil, err
+	}
+	switch n.operator {
+	case "&":
+		return FormatValue(left) + FormatValue(right), nil
+	case "=":
+		return compareValues(left, right) == 0, nil
+	case "!=":
+		return compareValues(left, right) != 0, nil
+	case "<":
+		return compareValues(left, right) < 0, nil
+	case "<=":
+		return compareValues(left, right) <= 0, nil
+	case ">":
+		return compareValues(left, right) > 0, nil
+	case ">=":
+		return compareValues(left, right) >= 0, nil
+	}
+	if left == nil || right == nil {
+		return nil, nil
+	}
+	first, err := numberOf(left)
+	if err != nil {
+		return nil, err
+	}
+	second, err := numberOf(right)
+	if err != nil {
+		return nil, err
+	}
+	switch n.operator {
+	case "+":
+		return first + second, nil
+	case "-":
+		return first - second, nil
+	case "*":
+		return first * second, nil
+	case "/":
+		if second == 0 {
+			return nil, errors.New("division by zero")
+		}
+		return first / second, nil
+	}
+	if second == 0 {
+		return nil, errors.New("division by zero")
+	}
+	return math.Mod(first, second), nil
+}
+
+type callNode struct {
+	name     string
+	function function
+	args     []node
+}
+
+func (n callNode) eval(row Row) (any, error) {
+	args := make([]any, len(n.args))
+	for index, arg := range n.args {
+		var err error
+		if args[index], err = arg.eval(row); err != nil {
+			return nil, err
+		}
+	}
+	value, err := n.function.call(args)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", n.name, err)
+	}
+	return value, nil
+}
+
+// function is a function of expressions taking between minArgs and maxArgs arguments, any number when maxArgs
+// is negative.
+type function struct {
+	minArgs, maxArgs int
+	call             func(args []any) (any, error)
+}
+
+// arity describes the number of arguments of the function.
+func (f function) arity() string {
+	switch {
+	case f.maxArgs < 0:
+		return fmt.Sprintf("at least %d arguments", f.minArgs)
+	case f.minArgs == f.maxArgs && f.minArgs == 1:
+		return "1 argument"
+	case f.minArgs == f.maxArgs:
+		return fmt.Sprintf("%d arguments", f.minArgs)
+	}
+	return fmt.Sprintf("%d to %d arguments", f.minArgs, f.maxArgs)
+}
+
+// textFunction returns a function of a single text argument, null giving null.
+func textFunction(apply func(string) any) function {
+	return function{minArgs: 1, maxArgs: 1, call: func(args []any) (any, error) {
+		if args[0] == nil {
+			return nil, nil
+		}
+		return apply(FormatValue(args[0])), nil
+	}}
+}
+
+// numberFunction returns a function of number arguments, a null argument giving null.
+func numberFunction(minArgs, maxArgs int, apply func([]float64) any) function {
+	return function{minArgs: minArgs, maxArgs: maxArgs, call: func(args []any) (any, error) {
+		numbers := make([]float64, len(args))
+		for index, arg := range args {
+			if arg == nil {
+				return nil, nil
+			}
+			var err error
+			if numbers[index], err = numberOf(arg); err != nil {
+				return nil, err
+			}
+		}
+		return apply(numbers), nil
+	}}
+}
+
+// textTest returns a function telling whether its first text argument relates to the second.
+func textTest(test func(value, part string) bool) function {
+	return function{minArgs: 2, maxArgs: 2, call: func(args []any) (any, error) {
+		return test(FormatValue(args[0]), FormatValue(args[1])), nil
+	}}
+}
+
+// functions are the functions of expressions by name.
+var functions = map[string]function{
+	"abs":   numberFunction(1, 1, func(numbers []float64) any { return math.Abs(numbers[0]) }),
+	"upper": textFunction(func(value string) any { return strings.ToUpper(value) }),
+	"lower": textFunction(func(value string) any { return strings.ToLower(value) }),
+	"trim":  textFunction(func(value string) any { return strings.TrimSpace(value) }),
+	"len":   textFunction(func(value string) any { return float64(len([]rune(value))) }),
+	"round": numberFunction(1, 2, func(numbers []float64) any {
+		scale := 1.0
+		if len(numbers) > 1 {
+			scale = math.Pow(10, math.Trunc(numbers[1]))
+		}
+		return math.Round(numbers[0]*scale) / scale
+	}),
+	"min": numberFunction(1, -1, func(numbers []float64) any {
+		result := numbers[0]
+		for _, number := range numbers[1:] {
+			result = math.Min(result, number)
+		}
+		return result
+	}),
+	"max": numberFunction(1, -1, func(numbers []float64) any {
+		result := numbers[0]
+		for _, number := range numbers[1:] {
+			result = math.Max(result, number)
+		}
+		return result
+	}),
+	"contains":   textTest(strings.Contains),
+	"startswith": textTest(strings.HasPrefix),
+	"endswith":   textTest(strings.HasSuffix),
+	"concat": {minArgs: 1, maxArgs: -1, call: func(args []any) (any, error) {
+		var text strings.Builder
+		for _, arg := range args {
+			text.WriteString(FormatValue(arg))
+		}
+		return text.String(), nil
+	}},
+	"coalesce": {minArgs: 1, maxArgs: -1, call: func(args []any) (any, error) {
+		for _, arg := range args {
+			if FormatValue(arg) != "" {
+				return arg, nil
+			}
+		}
+		return nil, nil
+	}},
+	"isblank": {minArgs: 1, maxArgs: 1, call: func(args []any) (any, error) {
+		return len(strings.TrimSpace(FormatValue(args[0]))) < 1, nil
+	}},
+	"left": textNumberFunction(func(runes []rune, count int) any { return string(runes[:clamp(count, len(runes))]) }),
+	"right": textNumberFunction(func(runes []rune, count int) any {
+		return string(runes[len(runes)-clamp(count, len(runes)):])
+	}),
+	"substr": {minArgs: 2, maxArgs: 3, call: func(args []any) (any, error) {
+		if args[0] == nil {
+			return nil, nil
+		}
+		runes := []rune(FormatValue(args[0]))
+		start, err := numberOf(args[1])
+		if err != nil {
+			return nil, err
+		}
+		from := clamp(int(start)-1, len(runes))
+		to := len(runes)
+		if len(args) > 2 {
+			length, err := numberOf(args[2])
+			if err != nil {
+				return nil, err
+			}
+			to = from + clamp(int(length), len(runes)-from)
+		}
+		return string(runes[from:to]), nil
+	}},
+	"replace": {minArgs: 3, maxArgs: 3, call: func(args []any) (any, error) {
+		if args[0] == nil {
+			return nil, nil
+		}
+		return strings.ReplaceAll(FormatValue(args[0]), FormatValue(args[1]), FormatValue(args[2])), nil
+	}},
+	"date":  dateFunction(1, 1, func(date time.Time, _ []any) (any, error) { return date, nil }),
+	"year":  dateFunction(1, 1, func(date time.Time, _ []any) (any, error) { return float64(date.Year()), nil }),
+	"month": dateFunction(1, 1, func(date time.Time, _ []any) (any, error) { return float64(date.Month()), nil }),
+	"day":   dateFunction(1, 1, func(date time.Time, _ []any) (any, error) { return float64(date.Day()), nil }),
+	"adddays": dateFunction(2, 2, func(date time.Time, args []any) (any, error) {
+		days, err := numberOf(args[0])
+		if err != nil {
+			return nil, err
+		}
+		return date.AddDate(0, 0, int(days)), nil
+	}),
+	"days": dateFunction(2, 2, func(date time.Time, args []any) (any, error) {
+		end, err := timeOf(args[0])
+		if err != nil {
+			return nil, err
+		}
+		return math.Round(end.Sub(date).Hours() / 24), nil
+	}),
+	"format": dateFunction(2, 2, func(date time.Time, args []any) (any, error) {
+		return date.Format(ParseDateOutput(FormatValue(args[0]))), nil
+	}),
+	"today": {minArgs: 0, maxArgs: 0, call: func([]any) (any, error) {
+		year, month, day := time.Now().Date()
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC), nil
+	}},
+	"if": {minArgs: 2, maxArgs: 3, call: func(args []any) (any, error) {
+		truth, err := truthOf(args[0])
+		if err != nil {
+			return nil, err
+		}
+		if truth {
+			return args[1], nil
+		}
+		if len(args) > 2 {
+			return args[2], nil
+		}
+		return nil, nil
+	}},
+}
+
+// textNumberFunction returns a function of a text and a count, a null text giving null.
+func textNumberFunction(apply func(runes []rune, count int) any) function {
+	return function{minArgs: 2, maxArgs: 2, call: func(args []any) (any, error) {
+		if args[0] == nil {
+			return nil, nil
+		}
+		count, err := numberOf(args[1])
+		if err != nil {
+			return nil, err
+		}
+		return apply([]rune(FormatValue(args[0])), int(count)), nil
+	}}
+}
+
+// dateFunction returns a function of a date followed by other arguments, a null date giving null.
+func dateFunction(minArgs, maxArgs int, apply func(date time.Time, args []any) (any, error)) function {
+	return function{minArgs: minArgs, maxArgs: maxArgs, call: func(args []any) (any, error) {
+		if args[0] == nil {
+			return nil, nil
+		}
+		date, err := timeOf(args[0])
+		if err != nil {
+			return nil, err
+		}
+		return apply(date, args[1:])
+	}}
+}
+
+// clamp returns value kept between 0 and most.
+func clamp(value, most int) int {
+	return max(0, min(value, most))
+}
+
+// truthOf returns value as a boolean, null being false.
+func truthOf(value any) (bool, error) {
+	switch value := value.(type) {
+	case bool:
+		return value, nil
+	case nil:
+		return false, nil
+	}
+	return false, fmt.Errorf("%v is not a boolean", value)
+}
+
+// numberOf returns value as a number, reading text as one.
+func numberOf(value any) (float64, error) {
+	switch value := value.(type) {
+	case float64:
+		return value, nil
+	case string:
+		if number, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+			return number, nil
+		}
+	}
+	return 0, fmt.Errorf("%v is not a number", value)
+}
+
+// compareValues compares two values of expressions: as numbers, dates or booleans when both read as one, as
+// text otherwise, null being blank text.
+func compareValues(left, right any) int {
+	if first, err := numberOf(left); err == nil {
+		if second, err := numberOf(right); err == nil {
+			return cmp.Compare(first, second)
+		}
+	}
+	if first, isTime := left.(time.Time); isTime {
+		if second, err := timeOf(right); err == nil {
+			return first.Compare(second)
+		}
+	}
+	if second, isTime := right.(time.Time); isTime {
+		if first, err := timeOf(left); err == nil {
+			return first.Compare(second)
+		}
+	}
+	if first, isBool := left.(bool); isBool {
+		if second, isBool := right.(bool); isBool {
+			switch {
+			case first == second:
+				return 0
+			case first:
+				return 1
+			}
+			return -1
+		}
+	}
+	return strings.Compare(FormatValue(left), FormatValue(right))
+}
+
+// timeOf returns value as a date and time, reading text as one.
+func timeOf(value any) (time.Time, error) {
+	switch value := value.(type) {
+	case time.Time:
+		return value, nil
+	case string:
+		return parseTime(strings.TrimSpace(value))
+	}
+	return time.Time{}, fmt.Errorf("%v is not a date", value)
+}
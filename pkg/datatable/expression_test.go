@@ -0,0 +1,117 @@
+package datatable
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExpression(t *testing.T) {
+	table := mustTable(t, []string{"Name", "Unit Price", "Quantity", "Start", "End", "Note"},
+		[]string{"Ann", "2.5", "4", "2024-03-01", "2024-03-31", ""})
+	for _, name := range []string{"Unit Price", "Quantity"} {
+		if err := table.SetColumnType(name, DecimalType); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := table.SetColumnType("Start", DateType); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		text string
+		want string
+	}{
+		{text: "[Unit Price] * Quantity", want: "10"},
+		{text: "1 + 2 * 3 - -1", want: "8"},
+		{text: "(1 + 2) * 3 % 4", want: "1"},
+		{text: "End >= Start and Quantity > 3", want: "true"},
+		{text: "Start = '2024-03-01' && not (Name <> 'Ann')", want: "true"},
+		{text: "Name & ' x' & Quantity", want: "Ann x4"},
+		{text: "upper(Name) == 'ANN' || false", want: "true"},
+		{text: "if(isblank(Note), 'none', Note)", want: "none"},
+		{text: "coalesce(Note, Name)", want: "Ann"},
+		{text: "Note = '' and Note = null", want: "true"},
+		{text: "round(10 / 3, 2)", want: "3.33"},
+		{text: "max(Quantity, 7, [Unit Price])", want: "7"},
+		{text: "len(concat(Name, 'é'))", want: "4"},
+		{text: "contains(Name, 'n') and startswith(Name, 'A') and !endswith(Name, 'x')", want: "true"},
+		{text: "Note + 1", want: ""},
+		{text: "'it''s'", want: "it's"},
+		{text: "Start", want: "2024-03-01"},
+		{text: "left(Name, 2) & right(Name, 9) & substr('abcdef', 2, 3) & substr('abc', 3)", want: "AnAnnbcdc"},
+		{text: "replace('a-b-c', '-', '/')", want: "a/b/c"},
+		{text: "year(Start) * 100 + month(Start) + day(End)", want: "202434"},
+		{text: "adddays(Start, 30)", want: "2024-03-31"},
+		{text: "days(Start, End)", want: "30"},
+		{text: "format(date('2024-03-01 08:30:00'), '02.01.2006 15:04')", want: "01.03.2024 08:30"},
+		{text: "today() >= Start", want: "true"},
+		{text: "year(Note)", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			expression, err := ParseExpression(tt.text)
+			if err != nil {
+				t.Fatalf("ParseExpression() error = %v", err)
+			}
+			if err := expression.Check(table); err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			value, err := expression.Eval(table.Row(0))
+			if err != nil {
+				t.Fatalf("Eval() error = %v", err)
+			}
+			if got := FormatValue(value); got != tt.want {
+				t.Errorf("Eval() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	expression, err := ParseExpression("[Unit Price] * Quantity > [Unit Price]")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"Unit Price", "Quantity"}; !reflect.DeepEqual(expression.Columns(), want) {
+		t.Errorf("Columns() = %v, want %v", expression.Columns(), want)
+	}
+}
+
+func TestExpressionErrors(t *testing.T) {
+	table := mustTable(t, []string{"Name", "Amount"}, []string{"Ann", "ten"})
+	tests := []struct {
+		text    string
+		wantErr string
+	}{
+		{text: "1 +", wantErr: "unexpected end"},
+		{text: "(1 + 2", wantErr: "missing ')' at the end"},
+		{text: "1 2", wantErr: "unexpected '2' at 3"},
+		{text: "'open", wantErr: "unterminated string at 1"},
+		{text: "[Unit Price", wantErr: "unterminated column name at 1"},
+		{text: "1 $ 2", wantErr: "unexpected '$' at 3"},
+		{text: "median(1)", wantErr: "unknown function 'median'"},
+		{text: "if(true)", wantErr: "if takes 2 to 3 arguments, not 1"},
+		{text: "Price > 1", wantErr: "unknown column 'Price'"},
+		{text: "Amount * 2", wantErr: "row 1: Amount * 2: ten is not a number"},
+		{text: "Amount / 0", wantErr: "ten is not a number"},
+		{text: "Name and true", wantErr: "Ann is not a boolean"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			expression, err := ParseExpression(tt.text)
+			if err == nil {
+				if err = expression.Check(table); err == nil {
+					_, err = expression.Eval(table.Row(0))
+				}
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want %s", err, tt.wantErr)
+			}
+		})
+	}
+	expression, err := ParseExpression("Name")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := expression.Test(table.Row(0)); err == nil || !strings.Contains(err.Error(), "not a boolean") {
+		t.Errorf("Test() error = %v, want not a boolean", err)
+	}
+}
@@ -0,0 +1,307 @@
+package datatable
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// AggregateFunc selects how an Aggregation summarises the values of a group.
+type AggregateFunc int
+
+const (
+	// Sum adds the values up. It is the default.
+	Sum AggregateFunc = iota
+	// Count counts the non-blank values, or the rows when no column is named.
+	Count
+	// Min keeps the smallest value, compared as the type of the column.
+	Min
+	// Max keeps the largest value, compared as the type of the column.
+	Max
+	// Average averages the values.
+	Average
+	// First keeps the first non-blank value.
+	First
+	// Concat joins the non-blank values with a separator.
+	Concat
+)
+
+// ParseAggregateFunc returns the AggregateFunc called sum, count, min, max, avg, first or concat.
+func ParseAggregateFunc(name string) (AggregateFunc, error) {
+	switch strings.ToLower(name) {
+	case "", "sum":
+		return Sum, nil
+	case "count":
+		return Count, nil
+	case "min":
+		return Min, nil
+	case "max":
+		return Max, nil
+	case "avg", "average", "mean":
+		return Average, nil
+	case "first":
+		return First, nil
+	case "concat":
+		return Concat, nil
+	}
+	return Sum, fmt.Errorf("unknown aggregate function '%s'", name)
+}
+
+// aggregateFuncNames are the names of the functions, in the order of their values.
+var aggregateFuncNames = [...]string{"sum", "count", "min", "max", "avg", "first", "concat"}
+
+// String returns the name of the function, as taken by ParseAggregateFunc, or AggregateFunc(<n>) for a value
+// which is no function.
+func (f AggregateFunc) String() string {
+	if f < 0 || int(f) >= len(aggregateFuncNames) {
+		return "AggregateFunc(" + strconv.Itoa(int(f)) + ")"
+	}
+	return aggregateFuncNames[f]
+}
+
+// check returns an error when the function is none of the defined ones.
+func (f AggregateFunc) check() error {
+	if f < 0 || int(f) >= len(aggregateFuncNames) {
+		return fmt.Errorf("unknown aggregate function '%s'", f)
+	}
+	return nil
+}
+
+// defaultConcatSeparator separates the values joined by Concat when Aggregation.Separator is empty.
+const defaultConcatSeparator = ", "
+
+// Aggregation is a column of a summary: Func applied to the values of Column in each group, called As or
+// <column>_<func> by default, such as Amount_sum, and count for the rows counted by Count without a column.
+// Separator separates the values joined by Concat, ", " by default.
+type Aggregation struct {
+	Column    string
+	Func      AggregateFunc
+	As        string
+	Separator string
+}
+
+// name returns the name of the column of the summary.
+func (a Aggregation) name() string {
+	switch {
+	case len(a.As) > 0:
+		return a.As
+	case len(a.Column) < 1:
+		return a.Func.String()
+	}
+	return a.Column + "_" + a.Func.String()
+}
+
+// ParseAggregations returns the aggregations of a comma separated list of <func>:<column> pairs, such as
+// "sum:Amount,count" or "concat:Name as Names".
+func ParseAggregations(list string) ([]Aggregation, error) {
+	var aggregations []Aggregation
+	for _, item := range strings.Split(list, ",") {
+		item = strings.TrimSpace(item)
+		spec, as, _ := strings.Cut(item, " as ")
+		name, column, _ := strings.Cut(spec, ":")
+		function, err := ParseAggregateFunc(strings.TrimSpace(name))
+		if err != nil {
+			return nil, err
+		}
+		aggregation := Aggregation{Column: strings.TrimSpace(column), Func: function, As: strings.TrimSpace(as)}
+		if len(aggregation.Column) < 1 && function != Count {
+			return nil, fmt.Errorf("the aggregation '%s' names no column", item)
+		}
+		aggregations = append(aggregations, aggregation)
+	}
+	return aggregations, nil
+}
+
+// Grouping is a table whose rows are grouped by the values of key columns, see DataTable.GroupBy.
+type Grouping struct {
+	table *DataTable
+	keys  []string
+}
+
+// GroupBy groups the rows of t by the values of the key columns, to be summarised with Aggregate.
+func (t *DataTable) GroupBy(keys ...string) *Grouping {
+	return &Grouping{table: t, keys: keys}
+}
+
+// group accumulates the values of an aggregation in a group.
+type group struct {
+	count  int
+	sum    float64
+	whole  int64
+	best   string
+	values []string
+}
+
+// Aggregate returns a summary of the groups: a row per distinct combination of key values, in the order they
+// are first met, holding the key values followed by a column per aggregation. Blank values are left out, and
+// Sum and Average fail on values which are not numbers. Sums of IntegerType columns are IntegerType too.
+// Example usage:
+//
+//	sales.GroupBy("Region").Aggregate(Aggregation{Column: "Amount"}, Aggregation{Func: Count})
+func (g *Grouping) Aggregate(aggregations ...Aggregation) (*DataTable, error) {
+	t := g.table
+	if len(g.keys) < 1 {
+		return nil, errors.New("no key columns to group by")
+	}
+	if len(aggregations) < 1 {
+		return nil, errors.New("no aggregations to summarise the groups with")
+	}
+	for _, aggregation := range aggregations {
+		if err := aggregation.Func.check(); err != nil {
+			return nil, err
+		}
+	}
+	keyPositions, err := t.positions(g.keys)
+	if err != nil {
+		return nil, err
+	}
+	columns := make([]Column, 0, len(g.keys)+len(aggregations))
+	for _, position := range keyPositions {
+		columns = append(columns, t.columns[position])
+	}
+	positions := make([]int, len(aggregations))
+	for index, aggregation := range aggregations {
+		positions[index] = -1
+		column := Column{Name: aggregation.name()}
+		if len(aggregation.Column) > 0 {
+			if positions[index], err = t.ColumnIndex(aggregation.Column); err != nil {
+				return nil, err
+			}
+			column.Type = aggregation.Func.resultType(t.columns[positions[index]].Type)
+		} else if aggregation.Func != Count {
+			return nil, fmt.Errorf("the %s aggregation names no column", aggregation.Func)
+		} else {
+			column.Type = IntegerType
+		}
+		columns = append(columns, column)
+	}
+	summary, err := NewWithColumns(columns...)
+	if err != nil {
+		return nil, err
+	}
+
+	var order []string
+	keyValues := make(map[string][]string)
+	groups := make(map[string][]*group)
+	for index, values := range t.rows {
+		parts := make([]string, len(keyPositions))
+		for position, keyPosition := range keyPositions {
+			parts[position] = values[keyPosition]
+		}
+		id := strings.Join(parts, "\x1f")
+		accumulators, known := groups[id]
+		if !known {
+			accumulators = make([]*group, len(aggregations))
+			for position := range accumulators {
+				accumulators[position] = &group{}
+			}
+			groups[id] = accumulators
+			keyValues[id] = parts
+			order = append(order, id)
+		}
+		for position, aggregation := range aggregations {
+			if positions[position] < 0 {
+				accumulators[position].count++
+				continue
+			}
+			if err := aggregation.add(accumulators[position], values[positions[position]], t.columns[positions[position]].Type); err != nil {
+				return nil, fmt.Errorf("row %d: %w", index+1, err)
+			}
+		}
+	}
+	for _, id := range order {
+		row := append([]string(nil), keyValues[id]...)
+		for position, aggregation := range aggregations {
+			row = append(row, aggregation.result(groups[id][position], summary.columns[len(keyPositions)+position].Type))
+		}
+		summary.rows = append(summary.rows, row)
+		summary.stats.Rows++
+	}
+	return summary, nil
+}
+
+// resultType returns the type of the summary column of the function applied to a column of columnType.
+func (f AggregateFunc) resultType(columnType ColumnType) ColumnType {
+	switch f {
+	case Count:
+		return IntegerType
+	case Sum:
+		if columnType == IntegerType {
+			return IntegerType
+		}
+		return DecimalType
+	case Average:
+		return DecimalType
+	case Concat:
+		return StringType
+	}
+	return columnType
+}
+
+// add accumulates value, a value of a column of columnType, into the accumulator of its group.
+func (a Aggregation) add(acc *group, value string, columnType ColumnType) error {
+	trimmed := strings.TrimSpace(value)
+	if len(trimmed) < 1 {
+		return nil
+	}
+	switch a.Func {
+	case Sum, Average:
+		number, err := strconv.ParseFloat(trimmed, 64)
+		if err != nil {
+			return fmt.Errorf("'%s' in column '%s' is not a number", value, a.Column)
+		}
+		acc.sum += number
+		if columnType == IntegerType {
+			whole, wholeErr := strconv.ParseInt(trimmed, 10, 64)
+			if wholeErr != nil {
+				return fmt.Errorf("'%s' in column '%s' is not a whole number", value, a.Column)
+			}
+			acc.whole += whole
+		}
+	case Min, Max:
+		if acc.count < 1 {
+			acc.best = value
+			break
+		}
+		result := columnType.compare(value, acc.best)
+		if a.Func == Min && result < 0 || a.Func == Max && result > 0 {
+			acc.best = value
+		}
+	case First:
+		if acc.count < 1 {
+			acc.best = value
+		}
+	case Concat:
+		acc.values = append(acc.values, value)
+	}
+	acc.count++
+	return nil
+}
+
+// result returns the summary value of the accumulator of a group, as a value of resultType.
+func (a Aggregation) result(acc *group, resultType ColumnType) string {
+	switch a.Func {
+	case Count:
+		return strconv.Itoa(acc.count)
+	case Concat:
+		separator := a.Separator
+		if len(separator) < 1 {
+			separator = defaultConcatSeparator
+		}
+		return strings.Join(acc.values, separator)
+	}
+	if acc.count < 1 {
+		return ""
+	}
+	switch a.Func {
+	case Sum:
+		if resultType == IntegerType {
+			return strconv.FormatInt(acc.whole, 10)
+		}
+		return strconv.FormatFloat(acc.sum, 'f', -1, 64)
+	case Average:
+		return strconv.FormatFloat(acc.sum/float64(acc.count), 'f', -1, 64)
+	}
+	return acc.best
+}
@@ -0,0 +1,116 @@
+package datatable
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAggregate(t *testing.T) {
+	sales := mustTable(t, []string{"Region", "Rep", "Amount", "Due"},
+		[]string{"North", "Ann", "10", "2024-03-01"},
+		[]string{"South", "Bob", "2.5", "2024-01-15"},
+		[]string{"North", "Cy", "", "2023-12-31"},
+		[]string{"North", "Ann", "20", ""},
+		[]string{"", "Di", "4", "2024-02-01"})
+	sales.InferTypes()
+
+	tests := []struct {
+		name         string
+		keys         []string
+		aggregations []Aggregation
+		wantColumns  []string
+		want         [][]string
+		wantErr      string
+	}{
+		{
+			name:         "Sum Count Average",
+			keys:         []string{"Region"},
+			aggregations: []Aggregation{{Column: "Amount"}, {Func: Count}, {Column: "Amount", Func: Count}, {Column: "Amount", Func: Average, As: "Mean"}},
+			wantColumns:  []string{"Region", "Amount_sum", "count", "Amount_count", "Mean"},
+			want:         [][]string{{"North", "30", "3", "2", "15"}, {"South", "2.5", "1", "1", "2.5"}, {"", "4", "1", "1", "4"}},
+		},
+		{
+			name:         "Min Max First Concat",
+			keys:         []string{"Region"},
+			aggregations: []Aggregation{{Column: "Due", Func: Min}, {Column: "Due", Func: Max}, {Column: "Rep", Func: First}, {Column: "Rep", Func: Concat, Separator: "/"}},
+			want: [][]string{
+				{"North", "2023-12-31", "2024-03-01", "Ann", "Ann/Cy/Ann"},
+				{"South", "2024-01-15", "2024-01-15", "Bob", "Bob"},
+				{"", "2024-02-01", "2024-02-01", "Di", "Di"},
+			},
+		},
+		{
+			name:         "Several Keys",
+			keys:         []string{"Region", "Rep"},
+			aggregations: []Aggregation{{Column: "Amount", Func: Max}},
+			want:         [][]string{{"North", "Ann", "20"}, {"South", "Bob", "2.5"}, {"North", "Cy", ""}, {"", "Di", "4"}},
+		},
+		{name: "Not A Number", keys: []string{"Region"}, aggregations: []Aggregation{{Column: "Rep"}}, wantErr: "row 1: 'Ann' in column 'Rep' is not a number"},
+		{name: "Unknown Column", keys: []string{"Country"}, aggregations: []Aggregation{{Func: Count}}, wantErr: "unknown column 'Country'"},
+		{name: "No Column", keys: []string{"Region"}, aggregations: []Aggregation{{Func: Max}}, wantErr: "the max aggregation names no column"},
+		{name: "No Keys", aggregations: []Aggregation{{Func: Count}}, wantErr: "no key columns to group by"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summary, err := sales.GroupBy(tt.keys...).Aggregate(tt.aggregations...)
+			if len(tt.wantErr) > 0 {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("Aggregate() error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Aggregate() error = %v", err)
+			}
+			if tt.wantColumns != nil && !reflect.DeepEqual(summary.ColumnNames(), tt.wantColumns) {
+				t.Errorf("Aggregate() columns = %v, want %v", summary.ColumnNames(), tt.wantColumns)
+			}
+			if !reflect.DeepEqual(summary.Records(), tt.want) {
+				t.Errorf("Aggregate() = %v, want %v", summary.Records(), tt.want)
+			}
+		})
+	}
+}
+
+func TestAggregateIntegerSums(t *testing.T) {
+	table := mustTable(t, []string{"Region", "Qty"}, []string{"North", "2"}, []string{"North", "3"})
+	table.InferTypes()
+	summary, err := table.GroupBy("Region").Aggregate(Aggregation{Column: "Qty"})
+	if err != nil {
+		t.Fatalf("Aggregate() error = %v", err)
+	}
+	if column, _ := summary.Column("Qty_sum"); column.Type != IntegerType || summary.Row(0).Get("Qty_sum") != "5" {
+		t.Errorf("Aggregate() = %v of type %v", summary.Records(), column.Type)
+	}
+	_ = table.AddRow("North", "1.5")
+	if _, err := table.GroupBy("Region").Aggregate(Aggregation{Column: "Qty"}); err == nil || !strings.Contains(err.Error(), "is not a whole number") {
+		t.Errorf("Aggregate() error = %v", err)
+	}
+}
+
+func TestParseAggregations(t *testing.T) {
+	aggregations, err := ParseAggregations("sum:Amount, count, concat:Rep as Reps")
+	want := []Aggregation{{Column: "Amount"}, {Func: Count}, {Column: "Rep", Func: Concat, As: "Reps"}}
+	if err != nil || !reflect.DeepEqual(aggregations, want) {
+		t.Errorf("ParseAggregations() = %+v, %v, want %+v", aggregations, err, want)
+	}
+	if _, err := ParseAggregations("median:Amount"); err == nil || err.Error() != "unknown aggregate function 'median'" {
+		t.Errorf("ParseAggregations(median) error = %v", err)
+	}
+	if _, err := ParseAggregations("max"); err == nil || err.Error() != "the aggregation 'max' names no column" {
+		t.Errorf("ParseAggregations(max) error = %v", err)
+	}
+}
+
+func TestAggregateFuncString(t *testing.T) {
+	for function, want := range map[AggregateFunc]string{Sum: "sum", Average: "avg", Concat: "concat", Concat + 1: "AggregateFunc(7)", -1: "AggregateFunc(-1)"} {
+		if got := function.String(); got != want {
+			t.Errorf("AggregateFunc(%d).String() = %s, want %s", int(function), got, want)
+		}
+	}
+	table := mustTable(t, []string{"Region", "Amount"}, []string{"North", "10"})
+	if _, err := table.GroupBy("Region").Aggregate(Aggregation{Column: "Amount", Func: 9}); err == nil || err.Error() != "unknown aggregate function 'AggregateFunc(9)'" {
+		t.Errorf("Aggregate(AggregateFunc(9)) error = %v", err)
+	}
+}
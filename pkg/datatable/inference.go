@@ -0,0 +1,111 @@
+package datatable
+
+import "fmt"
+
+// InferenceOptions configures inferring the type of a column from its values.
+// SampleSize is the number of values looked at, counting blank ones, every value by default; a sample spares
+// reading whole worksheets twice.
+// MinConfidence is the share of the non-blank sampled values which must be valid for a type for the column to be
+// given it, 1 by default; lowering it lets a few exceptions, such as "n/a" in a column of numbers, pass.
+type InferenceOptions struct {
+	SampleSize    int
+	MinConfidence float64
+}
+
+// Validate reports options that cannot infer a type.
+func (o InferenceOptions) Validate() error {
+	if o.SampleSize < 0 {
+		return fmt.Errorf("invalid sample size %d", o.SampleSize)
+	}
+	if o.MinConfidence < 0 || o.MinConfidence > 1 {
+		return fmt.Errorf("confidence %v is not between 0 and 1", o.MinConfidence)
+	}
+	return nil
+}
+
+// TypeInference is the type inferred for a column. Confidence is the share of the non-blank sampled values valid
+// for the type, and Exceptions the number of the others. Sampled counts the values looked at and Blanks the blank
+// ones among them. A column without any value is a string with no confidence.
+type TypeInference struct {
+	Type       CellType
+	Confidence float64
+	Sampled    int
+	Blanks     int
+	Exceptions int
+}
+
+// inferenceOrder lists the types a column may be inferred as, the most specific first.
+var inferenceOrder = []CellType{IntegerCell, DecimalCell, DoubleCell, BooleanCell, DateCell, DateTimeCell}
+
+// TypeInferrer infers the type of a column from the values added to it. The type is the most specific one
+// enough values are valid for, integers being valid decimals and doubles and dates valid dates and times.
+// Example usage:
+//
+//	inferrer := NewTypeInferrer(InferenceOptions{MinConfidence: 0.9})
+//	for _, value := range column {
+//		if !inferrer.Add(ConvertToISO8601(value)) {
+//			break
+//		}
+//	}
+//	inference := inferrer.Result()
+type TypeInferrer struct {
+	opts    InferenceOptions
+	counts  map[CellType]int
+	sampled int
+	blanks  int
+}
+
+// NewTypeInferrer returns a TypeInferrer without any value.
+func NewTypeInferrer(opts InferenceOptions) *TypeInferrer {
+	return &TypeInferrer{opts: opts, counts: make(map[CellType]int)}
+}
+
+// Add samples value and reports whether the sample has room for more values; a value added once it is full
+// is ignored.
+func (i *TypeInferrer) Add(value string) bool {
+	if i.Full() {
+		return false
+	}
+	i.sampled++
+	if len(value) < 1 {
+		i.blanks++
+	} else {
+		i.counts[InferCellType(value)]++
+	}
+	return !i.Full()
+}
+
+// Full reports whether the sample holds SampleSize values.
+func (i *TypeInferrer) Full() bool {
+	return i.opts.SampleSize > 0 && i.sampled >= i.opts.SampleSize
+}
+
+// Result returns the type inferred from the values sampled so far.
+func (i *TypeInferrer) Result() TypeInference {
+	inference := TypeInference{Type: StringCell, Sampled: i.sampled, Blanks: i.blanks}
+	values := i.sampled - i.blanks
+	if values < 1 {
+		return inference
+	}
+	minConfidence := i.opts.MinConfidence
+	if minConfidence == 0 {
+		minConfidence = 1
+	}
+	inference.Confidence = 1
+	best := 0
+	for _, candidate := range inferenceOrder {
+		valid := 0
+		for cellType, count := range i.counts {
+			if widenCellType(cellType, candidate) == candidate {
+				valid += count
+			}
+		}
+		if valid > best && float64(valid) >= minConfidence*float64(values) {
+			best = valid
+			inference.Type = candidate
+			inference.Confidence = float64(valid) / float64(values)
+			inference.Exceptions = values - valid
+		}
+	}
+	return inference
+}
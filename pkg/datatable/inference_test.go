@@ -0,0 +1,66 @@
+package datatable
+
+import "testing"
+
+func TestTypeInferrer(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []string
+		opts   InferenceOptions
+		want   TypeInference
+	}{
+		{
+			name:   "Integers",
+			values: []string{"1", "", "3"},
+			want:   TypeInference{Type: IntegerCell, Confidence: 1, Sampled: 3, Blanks: 1},
+		},
+		{
+			name:   "Integers And Decimals",
+			values: []string{"1", "2.5"},
+			want:   TypeInference{Type: DecimalCell, Confidence: 1, Sampled: 2},
+		},
+		{
+			name:   "Dates And Times",
+			values: []string{"2024-03-01", "2024-03-01 10:30:00"},
+			want:   TypeInference{Type: DateTimeCell, Confidence: 1, Sampled: 2},
+		},
+		{
+			name:   "Exception Rejected",
+			values: []string{"1", "2", "3", "n/a"},
+			want:   TypeInference{Type: StringCell, Confidence: 1, Sampled: 4},
+		},
+		{
+			name:   "Exception Tolerated",
+			values: []string{"1", "2", "3", "n/a"},
+			opts:   InferenceOptions{MinConfidence: 0.75},
+			want:   TypeInference{Type: IntegerCell, Confidence: 0.75, Sampled: 4, Exceptions: 1},
+		},
+		{
+			name:   "Booleans",
+			values: []string{"TRUE", "false"},
+			want:   TypeInference{Type: BooleanCell, Confidence: 1, Sampled: 2},
+		},
+		{
+			name:   "Sampled",
+			values: []string{"1", "2", "Total"},
+			opts:   InferenceOptions{SampleSize: 2},
+			want:   TypeInference{Type: IntegerCell, Confidence: 1, Sampled: 2},
+		},
+		{
+			name:   "Blank",
+			values: []string{"", ""},
+			want:   TypeInference{Type: StringCell, Sampled: 2, Blanks: 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inferrer := NewTypeInferrer(tt.opts)
+			for _, value := range tt.values {
+				inferrer.Add(value)
+			}
+			if got := inferrer.Result(); got != tt.want {
+				t.Errorf("Result() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
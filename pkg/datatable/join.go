@@ -0,0 +1,195 @@
+package datatable
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// JoinKind selects which rows a join keeps.
+type JoinKind int
+
+const (
+	// InnerJoin keeps the rows whose keys are in both tables. It is the default.
+	InnerJoin JoinKind = iota
+	// LeftJoin keeps every row of the left table, with blank right values when no right row matches it.
+	LeftJoin
+	// RightJoin keeps every row of the right table, with blank left values when no left row matches it.
+	RightJoin
+	// FullJoin keeps every row of both tables.
+	FullJoin
+)
+
+// ParseJoinKind returns the JoinKind called inner, left, right or full.
+func ParseJoinKind(name string) (JoinKind, error) {
+	switch strings.ToLower(name) {
+	case "", "inner":
+		return InnerJoin, nil
+	case "left":
+		return LeftJoin, nil
+	case "right":
+		return RightJoin, nil
+	case "full", "outer":
+		return FullJoin, nil
+	}
+	return InnerJoin, fmt.Errorf("unknown join kind '%s'", name)
+}
+
+// defaultRightSuffix is appended to the right columns whose name is a left column when JoinOptions.RightSuffix
+// is empty.
+const defaultRightSuffix = "_right"
+
+// JoinOptions configures joining two tables.
+// Keys names the key columns of the left table, matched to the columns of the right table of the same names or,
+// when RightKeys is set, to those it names in the same order. IgnoreCase matches key values regardless of case
+// and of surrounding spaces; rows with blank keys match no row.
+// The non-key columns of the right table whose name is a column of the left table get RightSuffix, "_right"
+// by default, and the left one gets LeftSuffix.
+type JoinOptions struct {
+	Kind        JoinKind
+	Keys        []string
+	RightKeys   []string
+	IgnoreCase  bool
+	LeftSuffix  string
+	RightSuffix string
+}
+
+// Join returns the rows of t and right with equal keys joined, such as transactions with the lookup sheet of
+// their products: the columns of t followed by the non-key columns of right, and a row per pair of matching rows,
+// in the order of the rows of t and then of right, followed by the rows opts.Kind keeps without a match.
+// Rows of right matching no row of t take their keys from right.
+// Example usage:
+//
+//	orders.Join(products, JoinOptions{Kind: LeftJoin, Keys: []string{"SKU"}})
+func (t *DataTable) Join(right *DataTable, opts JoinOptions) (*DataTable, error) {
+	if len(opts.Keys) < 1 {
+		return nil, errors.New("no key columns to join on")
+	}
+	rightKeys := opts.RightKeys
+	if len(rightKeys) < 1 {
+		rightKeys = opts.Keys
+	}
+	if len(rightKeys) != len(opts.Keys) {
+		return nil, fmt.Errorf("%d left keys are matched to %d right keys", len(opts.Keys), len(rightKeys))
+	}
+	leftPositions, err := t.positions(opts.Keys)
+	if err != nil {
+		return nil, fmt.Errorf("left table: %w", err)
+	}
+	rightPositions, err := right.positions(rightKeys)
+	if err != nil {
+		return nil, fmt.Errorf("right table: %w", err)
+	}
+
+	isRightKey := make(map[int]bool, len(rightPositions))
+	for _, position := range rightPositions {
+		isRightKey[position] = true
+	}
+	var columns []Column
+	var rightColumns []int
+	for position := range right.columns {
+		if !isRightKey[position] {
+			rightColumns = append(rightColumns, position)
+		}
+	}
+	rightSuffix := opts.RightSuffix
+	if len(rightSuffix) < 1 {
+		rightSuffix = defaultRightSuffix
+	}
+	for _, column := range t.columns {
+		for _, position := range rightColumns {
+			if right.columns[position].Name == column.Name {
+				column.Name += opts.LeftSuffix
+				break
+			}
+		}
+		columns = append(columns, column)
+	}
+	for _, position := range rightColumns {
+		column := right.columns[position]
+		if t.HasColumn(column.Name) {
+			column.Name += rightSuffix
+		}
+		columns = append(columns, column)
+	}
+	joined, err := NewWithColumns(columns...)
+	if err != nil {
+		return nil, fmt.Errorf("joined table: %w", err)
+	}
+	joined.policy = t.policy
+
+	key := func(values []string, positions []int) (string, bool) {
+		parts := make([]string, len(positions))
+		for index, position := range positions {
+			value := values[position]
+			if opts.IgnoreCase {
+				value = strings.ToLower(strings.TrimSpace(value))
+			}
+			if len(strings.TrimSpace(value)) < 1 {
+				return "", false
+			}
+			parts[index] = value
+		}
+		return strings.Join(parts, "\x1f"), true
+	}
+	byKey := make(map[string][]int)
+	for index, values := range right.rows {
+		if id, valid := key(values, rightPositions); valid {
+			byKey[id] = append(byKey[id], index)
+		}
+	}
+	matched := make([]bool, len(right.rows))
+	add := func(left, rightValues []string) {
+		row := make([]string, 0, len(columns))
+		row = append(row, left...)
+		for _, position := range rightColumns {
+			value := ""
+			if rightValues != nil {
+				value = rightValues[position]
+			}
+			row = append(row, value)
+		}
+		joined.rows = append(joined.rows, row)
+		joined.stats.Rows++
+	}
+	for _, values := range t.rows {
+		id, valid := key(values, leftPositions)
+		matches := byKey[id]
+		if !valid || len(matches) < 1 {
+			if opts.Kind == LeftJoin || opts.Kind == FullJoin {
+				add(values, nil)
+			}
+			continue
+		}
+		for _, index := range matches {
+			matched[index] = true
+			add(values, right.rows[index])
+		}
+	}
+	if opts.Kind == RightJoin || opts.Kind == FullJoin {
+		for index, values := range right.rows {
+			if matched[index] {
+				continue
+			}
+			left := make([]string, len(t.columns))
+			for position, leftPosition := range leftPositions {
+				left[leftPosition] = values[rightPositions[position]]
+			}
+			add(left, values)
+		}
+	}
+	return joined, nil
+}
+
+// positions returns the positions of the named columns.
+func (t *DataTable) positions(names []string) ([]int, error) {
+	positions := make([]int, len(names))
+	for index, name := range names {
+		position, err := t.ColumnIndex(name)
+		if err != nil {
+			return nil, err
+		}
+		positions[index] = position
+	}
+	return positions, nil
+}
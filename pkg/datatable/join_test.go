@@ -0,0 +1,108 @@
+package datatable
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestJoin(t *testing.T) {
+	orders := mustTable(t, []string{"Order", "SKU", "Qty", "Name"},
+		[]string{"1", "A1", "2", "first"},
+		[]string{"2", "b2", "1", "second"},
+		[]string{"3", "Z9", "5", "third"},
+		[]string{"4", "", "1", "fourth"},
+		[]string{"5", "A1", "1", "fifth"})
+	products := mustTable(t, []string{"Code", "Name", "Price"},
+		[]string{"A1", "Bolt", "0.25"},
+		[]string{"B2", "Nut", "0.10"},
+		[]string{"C3", "Washer", "0.05"})
+
+	tests := []struct {
+		name        string
+		opts        JoinOptions
+		wantColumns []string
+		want        [][]string
+		wantErr     string
+	}{
+		{
+			name:        "Inner",
+			opts:        JoinOptions{Keys: []string{"SKU"}, RightKeys: []string{"Code"}},
+			wantColumns: []string{"Order", "SKU", "Qty", "Name", "Name_right", "Price"},
+			want:        [][]string{{"1", "A1", "2", "first", "Bolt", "0.25"}, {"5", "A1", "1", "fifth", "Bolt", "0.25"}},
+		},
+		{
+			name:        "Left Ignoring Case",
+			opts:        JoinOptions{Kind: LeftJoin, Keys: []string{"SKU"}, RightKeys: []string{"Code"}, IgnoreCase: true, LeftSuffix: "_order", RightSuffix: "_product"},
+			wantColumns: []string{"Order", "SKU", "Qty", "Name_order", "Name_product", "Price"},
+			want: [][]string{
+				{"1", "A1", "2", "first", "Bolt", "0.25"},
+				{"2", "b2", "1", "second", "Nut", "0.10"},
+				{"3", "Z9", "5", "third", "", ""},
+				{"4", "", "1", "fourth", "", ""},
+				{"5", "A1", "1", "fifth", "Bolt", "0.25"},
+			},
+		},
+		{
+			name: "Right",
+			opts: JoinOptions{Kind: RightJoin, Keys: []string{"SKU"}, RightKeys: []string{"Code"}},
+			want: [][]string{
+				{"1", "A1", "2", "first", "Bolt", "0.25"},
+				{"5", "A1", "1", "fifth", "Bolt", "0.25"},
+				{"", "B2", "", "", "Nut", "0.10"},
+				{"", "C3", "", "", "Washer", "0.05"},
+			},
+		},
+		{
+			name: "Full",
+			opts: JoinOptions{Kind: FullJoin, Keys: []string{"SKU"}, RightKeys: []string{"Code"}, IgnoreCase: true},
+			want: [][]string{
+				{"1", "A1", "2", "first", "Bolt", "0.25"},
+				{"2", "b2", "1", "second", "Nut", "0.10"},
+				{"3", "Z9", "5", "third", "", ""},
+				{"4", "", "1", "fourth", "", ""},
+				{"5", "A1", "1", "fifth", "Bolt", "0.25"},
+				{"", "C3", "", "", "Washer", "0.05"},
+			},
+		},
+		{name: "No Keys", wantErr: "no key columns to join on"},
+		{name: "Unknown Right Key", opts: JoinOptions{Keys: []string{"SKU"}}, wantErr: "right table: unknown column 'SKU'"},
+		{name: "Key Counts", opts: JoinOptions{Keys: []string{"SKU"}, RightKeys: []string{"Code", "Name"}}, wantErr: "1 left keys are matched to 2 right keys"},
+		{name: "Colliding Suffix", opts: JoinOptions{Keys: []string{"SKU"}, RightKeys: []string{"Code"}, RightSuffix: "x", LeftSuffix: "x"}, wantErr: "the column 'Namex' is defined twice"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			joined, err := orders.Join(products, tt.opts)
+			if len(tt.wantErr) > 0 {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Join() error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Join() error = %v", err)
+			}
+			if tt.wantColumns != nil && !reflect.DeepEqual(joined.ColumnNames(), tt.wantColumns) {
+				t.Errorf("Join() columns = %v, want %v", joined.ColumnNames(), tt.wantColumns)
+			}
+			if !reflect.DeepEqual(joined.Records(), tt.want) {
+				t.Errorf("Join() = %v, want %v", joined.Records(), tt.want)
+			}
+		})
+	}
+}
+
+func TestJoinSeveralKeys(t *testing.T) {
+	sales := mustTable(t, []string{"Region", "Year", "Amount"}, []string{"North", "2023", "10"}, []string{"North", "2024", "12"})
+	targets := mustTable(t, []string{"Region", "Year", "Target"}, []string{"North", "2024", "15"})
+	joined, err := sales.Join(targets, JoinOptions{Kind: LeftJoin, Keys: []string{"Region", "Year"}})
+	if err != nil {
+		t.Fatalf("Join() error = %v", err)
+	}
+	if want := [][]string{{"North", "2023", "10", ""}, {"North", "2024", "12", "15"}}; !reflect.DeepEqual(joined.Records(), want) {
+		t.Errorf("Join() = %v, want %v", joined.Records(), want)
+	}
+	if _, err := ParseJoinKind("cross"); err == nil || err.Error() != "unknown join kind 'cross'" {
+		t.Errorf("ParseJoinKind(cross) error = %v", err)
+	}
+}
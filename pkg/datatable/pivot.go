@@ -0,0 +1,218 @@
+package datatable
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Default names of the columns Unpivot writes the names and values of the melted columns into.
+const (
+	defaultNameColumn  = "Variable"
+	defaultValueColumn = "Value"
+)
+
+// errNoColumns is returned by Unpivot when every column of the table is kept.
+var errNoColumns = errors.New("no columns to unpivot")
+
+// UnpivotOptions configures reshaping a wide table into a long one.
+// Keep names the identifier columns copied into every long row, such as Region for a report with a column
+// per month. Columns names the columns melted, every column not kept by default.
+// NameColumn and ValueColumn name the columns receiving the name and the value of each melted column,
+// "Variable" and "Value" by default. DropBlank leaves out the melted cells whose value is blank.
+type UnpivotOptions struct {
+	Keep        []string
+	Columns     []string
+	NameColumn  string
+	ValueColumn string
+	DropBlank   bool
+}
+
+// PivotOptions configures reshaping a long table into a wide one, the reverse of Unpivot.
+// NameColumn names the column whose values become the new columns and ValueColumn the column holding their
+// values, "Variable" and "Value" by default. Keys names the columns identifying a wide row, every other column
+// by default; the long rows sharing their values are folded into one wide row.
+// Fill is the value of the cells of new columns a wide row has no long row for.
+type PivotOptions struct {
+	Keys        []string
+	NameColumn  string
+	ValueColumn string
+	Fill        string
+}
+
+// Unpivot melts the wide table t into a long one, such as the rows of a report holding a column per month
+// into a row per region and month: every row of t gives a row per melted column, holding the kept columns,
+// the name of the melted column and its value. The rows keep the order of t and the melted columns their
+// order in t. The kept columns keep their type, and the value column is of the type of the melted columns
+// when they share one, StringType otherwise.
+func (t *DataTable) Unpivot(opts UnpivotOptions) (*DataTable, error) {
+	nameColumn := defaultName(opts.NameColumn, defaultNameColumn)
+	valueColumn := defaultName(opts.ValueColumn, defaultValueColumn)
+	if nameColumn == valueColumn {
+		return nil, fmt.Errorf("the name and value columns are both called '%s'", nameColumn)
+	}
+	keptPositions, err := t.positions(opts.Keep)
+	if err != nil {
+		return nil, err
+	}
+	melted := opts.Columns
+	if len(melted) < 1 {
+		melted = without(t.ColumnNames(), opts.Keep)
+	}
+	meltedPositions, err := t.positions(melted)
+	if err != nil {
+		return nil, err
+	}
+	if len(melted) < 1 {
+		return nil, errNoColumns
+	}
+	for _, name := range opts.Keep {
+		if name == nameColumn || name == valueColumn {
+			return nil, fmt.Errorf("the kept column '%s' clashes with the name or value column", name)
+		}
+	}
+
+	columns := make([]Column, 0, len(opts.Keep)+2)
+	for _, position := range keptPositions {
+		columns = append(columns, t.columns[position])
+	}
+	valueType := t.columns[meltedPositions[0]].Type
+	for _, position := range meltedPositions {
+		if t.columns[position].Type != valueType {
+			valueType = StringType
+		}
+	}
+	columns = append(columns, Column{Name: nameColumn}, Column{Name: valueColumn, Type: valueType})
+	long, err := NewWithColumns(columns...)
+	if err != nil {
+		return nil, err
+	}
+	for _, values := range t.rows {
+		for index, position := range meltedPositions {
+			if opts.DropBlank && len(strings.TrimSpace(values[position])) < 1 {
+				continue
+			}
+			row := make([]string, 0, len(columns))
+			for _, kept := range keptPositions {
+				row = append(row, values[kept])
+			}
+			long.rows = append(long.rows, append(row, melted[index], values[position]))
+			long.stats.Rows++
+		}
+	}
+	return long, nil
+}
+
+// Pivot folds the long table t into a wide one, such as a row per region and month into a row per region
+// holding a column per month: the rows of t sharing the values of the key columns give a single row holding
+// the key columns followed by a column per distinct value of the name column, in the order the values are
+// first met, holding the value column. The wide rows are in the order their keys are first met.
+// The key columns keep their type and the new columns are of the type of the value column.
+// Two rows of t holding a value for the same key and name make Pivot fail.
+func (t *DataTable) Pivot(opts PivotOptions) (*DataTable, error) {
+	nameColumn := defaultName(opts.NameColumn, defaultNameColumn)
+	valueColumn := defaultName(opts.ValueColumn, defaultValueColumn)
+	if nameColumn == valueColumn {
+		return nil, fmt.Errorf("the name and value columns are both called '%s'", nameColumn)
+	}
+	positions, err := t.positions([]string{nameColumn, valueColumn})
+	if err != nil {
+		return nil, err
+	}
+	namePosition, valuePosition := positions[0], positions[1]
+	keys := opts.Keys
+	if len(keys) < 1 {
+		keys = without(t.ColumnNames(), []string{nameColumn, valueColumn})
+	}
+	keyPositions, err := t.positions(keys)
+	if err != nil {
+		return nil, err
+	}
+
+	type wideRow struct {
+		keys   []string
+		values map[string]string
+	}
+	var wideRows []*wideRow
+	byKey := make(map[string]*wideRow)
+	var names []string
+	knownNames := make(map[string]bool)
+	for index, values := range t.rows {
+		keyValues := make([]string, len(keyPositions))
+		for position, keyPosition := range keyPositions {
+			keyValues[position] = values[keyPosition]
+		}
+		id := strings.Join(keyValues, "\x1f")
+		wide, known := byKey[id]
+		if !known {
+			wide = &wideRow{keys: keyValues, values: make(map[string]string)}
+			byKey[id] = wide
+			wideRows = append(wideRows, wide)
+		}
+		name := values[namePosition]
+		if len(strings.TrimSpace(name)) < 1 {
+			return nil, fmt.Errorf("row %d has no value in the name column '%s'", index+1, nameColumn)
+		}
+		if _, repeated := wide.values[name]; repeated {
+			return nil, fmt.Errorf("row %d repeats the value of '%s' for the keys %v", index+1, name, keyValues)
+		}
+		wide.values[name] = values[valuePosition]
+		if !knownNames[name] {
+			knownNames[name] = true
+			names = append(names, name)
+		}
+	}
+
+	columns := make([]Column, 0, len(keys)+len(names))
+	for _, position := range keyPositions {
+		columns = append(columns, t.columns[position])
+	}
+	for _, name := range names {
+		for _, key := range keys {
+			if name == key {
+				return nil, fmt.Errorf("the new column '%s' clashes with a key column", name)
+			}
+		}
+		columns = append(columns, Column{Name: name, Type: t.columns[valuePosition].Type})
+	}
+	wideTable, err := NewWithColumns(columns...)
+	if err != nil {
+		return nil, err
+	}
+	for _, wide := range wideRows {
+		row := append([]string(nil), wide.keys...)
+		for _, name := range names {
+			value, filled := wide.values[name]
+			if !filled {
+				value = opts.Fill
+			}
+			row = append(row, value)
+		}
+		wideTable.rows = append(wideTable.rows, row)
+		wideTable.stats.Rows++
+	}
+	return wideTable, nil
+}
+
+// without returns the columns not among left out, in their order.
+func without(columns, leftOut []string) []string {
+	var kept []string
+	for _, column := range columns {
+		excluded := false
+		for _, name := range leftOut {
+			excluded = excluded || name == column
+		}
+		if !excluded {
+			kept = append(kept, column)
+		}
+	}
+	return kept
+}
+
+// defaultName returns name, or fallback when it is blank.
+func defaultName(name, fallback string) string {
+	if len(strings.TrimSpace(name)) < 1 {
+		return fallback
+	}
+	return name
+}
@@ -0,0 +1,155 @@
+package datatable
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestUnpivot(t *testing.T) {
+	wide := mustTable(t, []string{"Region", "Jan", "Feb", "Mar"},
+		[]string{"North", "10", "", "30"},
+		[]string{"South", "5", "6", "7"})
+	wide.InferTypes()
+
+	tests := []struct {
+		name        string
+		opts        UnpivotOptions
+		wantColumns []Column
+		want        [][]string
+		wantErr     string
+	}{
+		{
+			name:        "Every Other Column",
+			opts:        UnpivotOptions{Keep: []string{"Region"}, DropBlank: true},
+			wantColumns: []Column{{Name: "Region"}, {Name: "Variable"}, {Name: "Value", Type: IntegerType}},
+			want: [][]string{
+				{"North", "Jan", "10"},
+				{"North", "Mar", "30"},
+				{"South", "Jan", "5"},
+				{"South", "Feb", "6"},
+				{"South", "Mar", "7"},
+			},
+		},
+		{
+			name:        "Selected Columns",
+			opts:        UnpivotOptions{Keep: []string{"Region"}, Columns: []string{"Feb", "Region"}, NameColumn: "Month", ValueColumn: "Sales"},
+			wantColumns: []Column{{Name: "Region"}, {Name: "Month"}, {Name: "Sales"}},
+			want: [][]string{
+				{"North", "Feb", ""},
+				{"North", "Region", "North"},
+				{"South", "Feb", "6"},
+				{"South", "Region", "South"},
+			},
+		},
+		{name: "Unknown Column", opts: UnpivotOptions{Keep: []string{"Country"}}, wantErr: "unknown column 'Country'"},
+		{name: "Everything Kept", opts: UnpivotOptions{Keep: []string{"Region", "Jan", "Feb", "Mar"}}, wantErr: "no columns to unpivot"},
+		{name: "Clashing Names", opts: UnpivotOptions{NameColumn: "Month", ValueColumn: "Month"}, wantErr: "both called 'Month'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := wide.Unpivot(tt.opts)
+			if len(tt.wantErr) > 0 {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Unpivot() error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unpivot() error = %v", err)
+			}
+			if !reflect.DeepEqual(got.Columns(), tt.wantColumns) {
+				t.Errorf("Unpivot() columns = %v, want %v", got.Columns(), tt.wantColumns)
+			}
+			if !reflect.DeepEqual(got.Records(), tt.want) {
+				t.Errorf("Unpivot() = %v, want %v", got.Records(), tt.want)
+			}
+		})
+	}
+}
+
+func TestPivot(t *testing.T) {
+	long := mustTable(t, []string{"Region", "Channel", "Variable", "Value"},
+		[]string{"North", "Web", "Jan", "10"},
+		[]string{"North", "Web", "Mar", "30"},
+		[]string{"South", "Shop", "Feb", "6"},
+		[]string{"North", "Web", "Feb", "20"},
+		[]string{"North", "Shop", "Jan", "1"})
+
+	tests := []struct {
+		name    string
+		table   *DataTable
+		opts    PivotOptions
+		want    [][]string
+		wantErr string
+	}{
+		{
+			name:  "Every Other Column As Keys",
+			table: long,
+			opts:  PivotOptions{Fill: "0"},
+			want: [][]string{
+				{"North", "Web", "10", "30", "20"},
+				{"South", "Shop", "0", "0", "6"},
+				{"North", "Shop", "1", "0", "0"},
+			},
+		},
+		{
+			name:    "Repeated Value",
+			table:   long,
+			opts:    PivotOptions{Keys: []string{"Region"}},
+			wantErr: "row 5 repeats the value of 'Jan' for the keys [North]",
+		},
+		{
+			name:    "Unknown Column",
+			table:   long,
+			opts:    PivotOptions{NameColumn: "Month"},
+			wantErr: "unknown column 'Month'",
+		},
+		{
+			name:    "Blank Name",
+			table:   mustTable(t, []string{"Region", "Variable", "Value"}, []string{"North", "", "1"}),
+			wantErr: "row 1 has no value in the name column 'Variable'",
+		},
+		{
+			name:    "Name Clashing With Key",
+			table:   mustTable(t, []string{"Region", "Variable", "Value"}, []string{"North", "Region", "1"}),
+			wantErr: "the new column 'Region' clashes with a key column",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.table.Pivot(tt.opts)
+			if len(tt.wantErr) > 0 {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Pivot() error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Pivot() error = %v", err)
+			}
+			if want := []string{"Region", "Channel", "Jan", "Mar", "Feb"}; !reflect.DeepEqual(got.ColumnNames(), want) {
+				t.Errorf("Pivot() columns = %v, want %v", got.ColumnNames(), want)
+			}
+			if !reflect.DeepEqual(got.Records(), tt.want) {
+				t.Errorf("Pivot() = %v, want %v", got.Records(), tt.want)
+			}
+		})
+	}
+}
+
+func TestPivotRoundTrip(t *testing.T) {
+	wide := mustTable(t, []string{"Region", "Jan", "Feb"}, []string{"North", "1", "2"}, []string{"South", "3", "4"})
+	wide.InferTypes()
+	long, err := wide.Unpivot(UnpivotOptions{Keep: []string{"Region"}})
+	if err != nil {
+		t.Fatalf("Unpivot() error = %v", err)
+	}
+	back, err := long.Pivot(PivotOptions{})
+	if err != nil {
+		t.Fatalf("Pivot() error = %v", err)
+	}
+	if !reflect.DeepEqual(back.Columns(), wide.Columns()) || !reflect.DeepEqual(back.Records(), wide.Records()) {
+		t.Errorf("Pivot(Unpivot()) = %v %v, want %v %v", back.Columns(), back.Records(), wide.Columns(), wide.Records())
+	}
+}
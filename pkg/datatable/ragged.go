@@ -0,0 +1,85 @@
+package datatable
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RowPolicy selects how AddRow handles rows which do not have a value per column, as exports leaving out
+// trailing empty cells or holding stray values beyond the last column produce.
+// Trailing empty values beyond the last column are always dropped.
+type RowPolicy int
+
+const (
+	// PadRows pads short rows with blank values and rejects rows holding values beyond the last column.
+	// It is the default.
+	PadRows RowPolicy = iota
+	// TruncateRows pads short rows and drops the values beyond the last column.
+	TruncateRows
+	// StrictRows rejects every row without a value per column, short or long.
+	StrictRows
+)
+
+// ParseRowPolicy returns the RowPolicy called pad, truncate or strict.
+func ParseRowPolicy(name string) (RowPolicy, error) {
+	switch strings.ToLower(name) {
+	case "", "pad":
+		return PadRows, nil
+	case "truncate":
+		return TruncateRows, nil
+	case "strict":
+		return StrictRows, nil
+	}
+	return PadRows, fmt.Errorf("unknown row policy '%s'", name)
+}
+
+// RowStats counts the rows added to a table and those AddRow adjusted to its columns: the short rows it padded,
+// the long rows whose values beyond the last column were all empty and dropped, and those it truncated.
+type RowStats struct {
+	Rows      int `json:"rows"`
+	Padded    int `json:"padded"`
+	Trimmed   int `json:"trimmed"`
+	Truncated int `json:"truncated"`
+}
+
+// Adjusted returns the number of rows which did not have a value per column.
+func (s RowStats) Adjusted() int {
+	return s.Padded + s.Trimmed + s.Truncated
+}
+
+// SetRowPolicy sets how the rows added after it are fitted to the columns of the table.
+func (t *DataTable) SetRowPolicy(policy RowPolicy) {
+	t.policy = policy
+}
+
+// RowStats returns the statistics of the rows added to the table so far.
+func (t *DataTable) RowStats() RowStats {
+	return t.stats
+}
+
+// fit returns values fitted to the columns of the table according to its row policy, counting it in its stats.
+func (t *DataTable) fit(values []string) ([]string, error) {
+	width := len(t.columns)
+	number := len(t.rows) + 1
+	switch {
+	case len(values) < width:
+		if t.policy == StrictRows {
+			return nil, fmt.Errorf("row %d has %d values for %d columns", number, len(values), width)
+		}
+		t.stats.Padded++
+	case len(values) > width:
+		extra := values[width:]
+		if len(strings.Join(extra, "")) < 1 {
+			t.stats.Trimmed++
+			break
+		}
+		if t.policy != TruncateRows {
+			return nil, fmt.Errorf("row %d has %d values for %d columns", number, len(values), width)
+		}
+		t.stats.Truncated++
+	}
+	row := make([]string, width)
+	copy(row, values)
+	t.stats.Rows++
+	return row, nil
+}
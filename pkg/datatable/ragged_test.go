@@ -0,0 +1,73 @@
+package datatable
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRowPolicy(t *testing.T) {
+	records := [][]string{{"1", "a"}, {"2"}, {"3", "c", "", ""}, {"4", "d", "stray"}}
+	tests := []struct {
+		name      string
+		policy    RowPolicy
+		records   [][]string
+		want      [][]string
+		wantStats RowStats
+		wantErr   string
+	}{
+		{
+			name:      "Pad",
+			records:   records[:3],
+			want:      [][]string{{"1", "a"}, {"2", ""}, {"3", "c"}},
+			wantStats: RowStats{Rows: 3, Padded: 1, Trimmed: 1},
+		},
+		{
+			name:    "Pad Rejects Long Rows",
+			records: records,
+			wantErr: "row 4 has 3 values for 2 columns",
+		},
+		{
+			name:      "Truncate",
+			policy:    TruncateRows,
+			records:   records,
+			want:      [][]string{{"1", "a"}, {"2", ""}, {"3", "c"}, {"4", "d"}},
+			wantStats: RowStats{Rows: 4, Padded: 1, Trimmed: 1, Truncated: 1},
+		},
+		{
+			name:    "Strict",
+			policy:  StrictRows,
+			records: records,
+			wantErr: "row 2 has 1 values for 2 columns",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table, _ := New("Id", "Name")
+			table.SetRowPolicy(tt.policy)
+			var err error
+			for _, record := range tt.records {
+				if err = table.AddRow(record...); err != nil {
+					break
+				}
+			}
+			if len(tt.wantErr) > 0 {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("AddRow() error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("AddRow() error = %v", err)
+			}
+			if !reflect.DeepEqual(table.Records(), tt.want) {
+				t.Errorf("Records() = %v, want %v", table.Records(), tt.want)
+			}
+			if stats := table.RowStats(); stats != tt.wantStats || stats.Adjusted() != tt.wantStats.Padded+tt.wantStats.Trimmed+tt.wantStats.Truncated {
+				t.Errorf("RowStats() = %+v, want %+v", stats, tt.wantStats)
+			}
+		})
+	}
+	if _, err := ParseRowPolicy("ragged"); err == nil || err.Error() != "unknown row policy 'ragged'" {
+		t.Errorf("ParseRowPolicy(ragged) error = %v", err)
+	}
+}
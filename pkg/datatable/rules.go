@@ -0,0 +1,337 @@
+package datatable
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Rule is a check the rows of a table must pass, defined in Go or in a YAML rules file, see ParseRules.
+// Column names the column the rule checks, which may be left out by rules only holding an Expression or a Check.
+// Required fails blank values; Pattern fails values not matching the regular expression as a whole; Min and Max
+// bound the values, read as the type of the column, or as numbers in a StringType column when both bounds are
+// numbers; Enum lists the values allowed, compared regardless of case with IgnoreCase; Unique fails values met in
+// an earlier row, compared regardless of case with IgnoreCase. Blank values only fail Required.
+// Expression is a cross-column condition every row must meet, such as EndDate >= StartDate, see Expression;
+// Check is a check written in Go, failing the row when it returns an error.
+// Name labels the violations of the rule, the column by default; Message replaces the reason they give.
+type Rule struct {
+	Name       string              `yaml:"name"`
+	Column     string              `yaml:"column"`
+	Required   bool                `yaml:"required"`
+	Pattern    string              `yaml:"pattern"`
+	Min        string              `yaml:"min"`
+	Max        string              `yaml:"max"`
+	Enum       []string            `yaml:"enum"`
+	IgnoreCase bool                `yaml:"ignoreCase"`
+	Unique     bool                `yaml:"unique"`
+	Expression string              `yaml:"expression"`
+	Check      func(row Row) error `yaml:"-"`
+	Message    string              `yaml:"message"`
+}
+
+// ParseRules returns the rules of a YAML rules file, holding a list of rules under rules:
+//
+//	rules:
+//	  - column: Email
+//	    required: true
+//	    pattern: '[^@ ]+@[^@ ]+'
+//	    unique: true
+//	  - column: Amount
+//	    min: 0
+//	  - name: Period
+//	    expression: EndDate >= StartDate
+func ParseRules(r io.Reader) ([]Rule, error) {
+	var file struct {
+		Rules []Rule `yaml:"rules"`
+	}
+	decoder := yaml.NewDecoder(r)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("rules: %w", err)
+	}
+	return file.Rules, nil
+}
+
+// LoadRules returns the rules of the YAML rules file at path, see ParseRules.
+func LoadRules(path string) (rules []Rule, loadErr error) {
+	file, openErr := os.Open(path)
+	if openErr != nil {
+		return nil, openErr
+	}
+	defer func(file *os.File) {
+		if err := file.Close(); err != nil && loadErr == nil {
+			loadErr = err
+		}
+	}(file)
+	return ParseRules(file)
+}
+
+// Violation is a check of a rule a row failed: the one-based number of the row, the name of the rule, the check
+// failed, one of required, pattern, range, enum, unique, expression or check, the column and value checked and
+// the reason the check failed.
+type Violation struct {
+	Row     int    `json:"row"`
+	Rule    string `json:"rule"`
+	Check   string `json:"check"`
+	Column  string `json:"column,omitempty"`
+	Value   string `json:"value,omitempty"`
+	Message string `json:"message"`
+}
+
+// String describes the violation.
+func (v Violation) String() string {
+	return fmt.Sprintf("row %d: %s: %s", v.Row, v.Rule, v.Message)
+}
+
+// ValidationReport is the outcome of Validate: the number of rows validated and their violations, per row in the
+// order of the rules.
+type ValidationReport struct {
+	Rows       int         `json:"rows"`
+	Violations []Violation `json:"violations,omitempty"`
+}
+
+// Valid returns whether every row passed every rule.
+func (r *ValidationReport) Valid() bool {
+	return len(r.Violations) < 1
+}
+
+// FailedRows returns the one-based numbers of the rows failing a rule, in ascending order.
+func (r *ValidationReport) FailedRows() []int {
+	var rows []int
+	for _, violation := range r.Violations {
+		if len(rows) < 1 || rows[len(rows)-1] != violation.Row {
+			rows = append(rows, violation.Row)
+		}
+	}
+	return rows
+}
+
+// Table returns the violations as a table with the columns Row, Rule, Check, Column, Value and Message.
+func (r *ValidationReport) Table() *DataTable {
+	table, _ := NewWithColumns(Column{Name: "Row", Type: IntegerType}, Column{Name: "Rule"}, Column{Name: "Check"},
+		Column{Name: "Column"}, Column{Name: "Value"}, Column{Name: "Message"})
+	for _, violation := range r.Violations {
+		table.rows = append(table.rows, []string{fmt.Sprint(violation.Row), violation.Rule, violation.Check,
+			violation.Column, violation.Value, violation.Message})
+	}
+	table.stats.Rows = len(table.rows)
+	return table
+}
+
+// compiledRule is a rule checked against a table.
+type compiledRule struct {
+	Rule
+	name       string
+	pattern    *regexp.Regexp
+	rangeType  ColumnType
+	enum       map[string]bool
+	expression *Expression
+	seen       map[string]int
+}
+
+// compile checks the rule against table and prepares its checks.
+func (r Rule) compile(table *DataTable, index int) (*compiledRule, error) {
+	rule := &compiledRule{Rule: r, name: defaultRuleName(r, index)}
+	fail := func(err error) (*compiledRule, error) {
+		return nil, fmt.Errorf("rule '%s': %w", rule.name, err)
+	}
+	checksColumn := r.Required || len(r.Pattern) > 0 || len(r.Min) > 0 || len(r.Max) > 0 || len(r.Enum) > 0 || r.Unique
+	switch {
+	case checksColumn && len(r.Column) < 1:
+		return fail(errors.New("no column to check"))
+	case !checksColumn && len(r.Expression) < 1 && r.Check == nil:
+		return fail(errors.New("the rule checks nothing"))
+	}
+	if len(r.Column) > 0 {
+		column, err := table.Column(r.Column)
+		if err != nil {
+			return fail(err)
+		}
+		rule.rangeType = column.Type
+	}
+	if len(r.Pattern) > 0 {
+		pattern, err := regexp.Compile(`^(?:` + r.Pattern + `)$`)
+		if err != nil {
+			return fail(err)
+		}
+		rule.pattern = pattern
+	}
+	if rule.rangeType == StringType && (len(r.Min) > 0 || len(r.Max) > 0) {
+		rule.rangeType = DecimalType
+		for _, bound := range []string{r.Min, r.Max} {
+			if _, err := DecimalType.Parse(bound); err != nil {
+				rule.rangeType = StringType
+			}
+		}
+	}
+	for _, bound := range []string{r.Min, r.Max} {
+		if _, err := rule.rangeType.Parse(bound); err != nil {
+			return fail(fmt.Errorf("bound: %w", err))
+		}
+	}
+	if len(r.Enum) > 0 {
+		rule.enum = make(map[string]bool, len(r.Enum))
+		for _, value := range r.Enum {
+			rule.enum[rule.fold(value)] = true
+		}
+	}
+	if r.Unique {
+		rule.seen = make(map[string]int)
+	}
+	if len(r.Expression) > 0 {
+		expression, err := ParseExpression(r.Expression)
+		if err != nil {
+			return fail(err)
+		}
+		if err := expression.Check(table); err != nil {
+			return fail(err)
+		}
+		rule.expression = expression
+	}
+	return rule, nil
+}
+
+// defaultRuleName returns the name of the rule, its column or its expression when it has none, or its
+// one-based position at last.
+func defaultRuleName(r Rule, index int) string {
+	switch {
+	case len(r.Name) > 0:
+		return r.Name
+	case len(r.Column) > 0:
+		return r.Column
+	case len(r.Expression) > 0:
+		return r.Expression
+	}
+	return fmt.Sprintf("rule %d", index+1)
+}
+
+// fold returns value as the rule compares it.
+func (r *compiledRule) fold(value string) string {
+	value = strings.TrimSpace(value)
+	if r.IgnoreCase {
+		return strings.ToLower(value)
+	}
+	return value
+}
+
+// check returns the violations of the rule by row.
+func (r *compiledRule) check(row Row) ([]Violation, error) {
+	var violations []Violation
+	violate := func(check, value, reason string) {
+		if len(r.Message) > 0 {
+			reason = r.Message
+		}
+		violations = append(violations, Violation{Row: row.index + 1, Rule: r.name, Check: check, Column: r.Column, Value: value, Message: reason})
+	}
+	if len(r.Column) > 0 {
+		value := row.Get(r.Column)
+		blank := len(strings.TrimSpace(value)) < 1
+		switch {
+		case blank && r.Required:
+			violate("required", value, "a value is required")
+		case blank:
+		default:
+			if r.pattern != nil && !r.pattern.MatchString(value) {
+				violate("pattern", value, fmt.Sprintf("'%s' does not match %s", value, r.Pattern))
+			}
+			if len(r.Min) > 0 || len(r.Max) > 0 {
+				if _, err := r.rangeType.Parse(value); err != nil {
+					violate("range", value, err.Error())
+				} else if len(r.Min) > 0 && r.rangeType.compare(value, r.Min) < 0 {
+					violate("range", value, fmt.Sprintf("'%s' is below the minimum %s", value, r.Min))
+				} else if len(r.Max) > 0 && r.rangeType.compare(value, r.Max) > 0 {
+					violate("range", value, fmt.Sprintf("'%s' is above the maximum %s", value, r.Max))
+				}
+			}
+			if r.enum != nil && !r.enum[r.fold(value)] {
+				violate("enum", value, fmt.Sprintf("'%s' is not one of %s", value, strings.Join(r.Enum, ", ")))
+			}
+			if r.seen != nil {
+				if first, repeated := r.seen[r.fold(value)]; repeated {
+					violate("unique", value, fmt.Sprintf("'%s' repeats row %d", value, first))
+				} else {
+					r.seen[r.fold(value)] = row.index + 1
+				}
+			}
+		}
+	}
+	if r.expression != nil {
+		holds, err := r.expression.Test(row)
+		if err != nil {
+			return nil, fmt.Errorf("rule '%s': %w", r.name, err)
+		}
+		if !holds {
+			violate("expression", "", fmt.Sprintf("%s does not hold", r.Expression))
+		}
+	}
+	if r.Check != nil {
+		if err := r.Check(row); err != nil {
+			violate("check", "", err.Error())
+		}
+	}
+	return violations, nil
+}
+
+// Validate checks every row of t against the rules and reports the violations. It fails when a rule is
+// malformed, names an unknown column, or has an expression which cannot be computed for a row; rows failing
+// a rule are reported rather than failing Validate.
+//
+//	report, err := orders.Validate([]Rule{
+//		{Column: "Id", Required: true, Unique: true},
+//		{Column: "Status", Enum: []string{"open", "closed"}, IgnoreCase: true},
+//		{Name: "Period", Expression: "EndDate >= StartDate"},
+//	})
+func (t *DataTable) Validate(rules []Rule) (*ValidationReport, error) {
+	compiled := make([]*compiledRule, len(rules))
+	for index, rule := range rules {
+		var err error
+		if compiled[index], err = rule.compile(t, index); err != nil {
+			return nil, err
+		}
+	}
+	report := &ValidationReport{Rows: len(t.rows)}
+	for index := range t.rows {
+		for _, rule := range compiled {
+			violations, err := rule.check(t.Row(index))
+			if err != nil {
+				return nil, err
+			}
+			report.Violations = append(report.Violations, violations...)
+		}
+	}
+	return report, nil
+}
+
+// Quarantine splits t by report, the outcome of t.Validate: passed holds the rows without a violation and
+// quarantined those with one, for review before the rest is converted, under the columns of t preceded by Row,
+// the one-based number of the row in t, and Violations, the reasons it failed, separated by "; ".
+func (t *DataTable) Quarantine(report *ValidationReport) (passed, quarantined *DataTable, err error) {
+	quarantined, err = NewWithColumns(append([]Column{{Name: "Row", Type: IntegerType}, {Name: "Violations"}}, t.columns...)...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("quarantined rows: %w", err)
+	}
+	reasons := make(map[int][]string)
+	for _, violation := range report.Violations {
+		reasons[violation.Row] = append(reasons[violation.Row], violation.Rule+": "+violation.Message)
+	}
+	passed = t.empty()
+	for index, values := range t.rows {
+		failed, found := reasons[index+1]
+		if !found {
+			passed.rows = append(passed.rows, slices.Clone(values))
+			continue
+		}
+		record := append([]string{fmt.Sprint(index + 1), strings.Join(failed, "; ")}, values...)
+		quarantined.rows = append(quarantined.rows, record)
+	}
+	passed.stats.Rows = len(passed.rows)
+	quarantined.stats.Rows = len(quarantined.rows)
+	return passed, quarantined, nil
+}
@@ -0,0 +1,141 @@
+package datatable
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	orders := mustTable(t, []string{"Id", "Email", "Status", "Amount", "Start", "End"},
+		[]string{"1", "ann@example.com", "open", "10", "2024-03-01", "2024-03-31"},
+		[]string{"2", "bob", "Closed", "-5", "2024-03-01", "2024-02-01"},
+		[]string{"", "cid@example.com", "lost", "ten", "2024-03-01", "2024-03-01"},
+		[]string{"1", "ANN@example.com", "", "", "2024-03-01", "2024-03-02"})
+	rules := []Rule{
+		{Column: "Id", Required: true, Unique: true},
+		{Column: "Email", Pattern: `[^@ ]+@[^@ ]+`, Unique: true, IgnoreCase: true},
+		{Column: "Status", Enum: []string{"open", "closed"}, IgnoreCase: true},
+		{Column: "Amount", Min: "0", Max: "100"},
+		{Name: "Period", Expression: "End >= Start"},
+		{Name: "Even", Check: func(row Row) error {
+			if row.Get("Id") == "2" {
+				return errors.New("no even ids")
+			}
+			return nil
+		}},
+	}
+	report, err := orders.Validate(rules)
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	var got []string
+	for _, violation := range report.Violations {
+		got = append(got, violation.Check+" "+violation.String())
+	}
+	want := []string{
+		"pattern row 2: Email: 'bob' does not match [^@ ]+@[^@ ]+",
+		"range row 2: Amount: '-5' is below the minimum 0",
+		"expression row 2: Period: End >= Start does not hold",
+		"check row 2: Even: no even ids",
+		"required row 3: Id: a value is required",
+		"enum row 3: Status: 'lost' is not one of open, closed",
+		"range row 3: Amount: 'ten' is not a number",
+		"unique row 4: Id: '1' repeats row 1",
+		"unique row 4: Email: 'ANN@example.com' repeats row 1",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Validate() violations =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if report.Valid() || !reflect.DeepEqual(report.FailedRows(), []int{2, 3, 4}) {
+		t.Errorf("FailedRows() = %v, want [2 3 4]", report.FailedRows())
+	}
+	if table := report.Table(); table.Len() != len(want) || table.Row(0).Get("Column") != "Email" {
+		t.Errorf("Table() = %v", table.Records())
+	}
+
+	passed, quarantined, err := orders.Quarantine(report)
+	if err != nil {
+		t.Fatalf("Quarantine() error = %v", err)
+	}
+	if passed.Len() != 1 || passed.Row(0).Get("Id") != "1" {
+		t.Errorf("Quarantine() passed = %v", passed.Records())
+	}
+	if quarantined.Len() != 3 || !reflect.DeepEqual(quarantined.ColumnNames()[:3], []string{"Row", "Violations", "Id"}) {
+		t.Fatalf("Quarantine() quarantined = %v %v", quarantined.ColumnNames(), quarantined.Records())
+	}
+	if got, want := quarantined.Row(2).Get("Violations"), "Id: '1' repeats row 1; Email: 'ANN@example.com' repeats row 1"; got != want {
+		t.Errorf("Quarantine() violations = %q, want %q", got, want)
+	}
+}
+
+func TestValidateTypedRange(t *testing.T) {
+	table := mustTable(t, []string{"Due"}, []string{"2024-01-31"}, []string{"2023-12-31"})
+	if err := table.SetColumnType("Due", DateType); err != nil {
+		t.Fatal(err)
+	}
+	report, err := table.Validate([]Rule{{Column: "Due", Min: "2024-01-01", Message: "too early"}})
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if want := []Violation{{Row: 2, Rule: "Due", Check: "range", Column: "Due", Value: "2023-12-31", Message: "too early"}}; !reflect.DeepEqual(report.Violations, want) {
+		t.Errorf("Validate() violations = %+v, want %+v", report.Violations, want)
+	}
+}
+
+func TestValidateErrors(t *testing.T) {
+	table := mustTable(t, []string{"Id", "Amount"}, []string{"1", "x"})
+	tests := []struct {
+		name    string
+		rule    Rule
+		wantErr string
+	}{
+		{name: "Nothing", rule: Rule{Name: "Empty"}, wantErr: "rule 'Empty': the rule checks nothing"},
+		{name: "No Column", rule: Rule{Required: true}, wantErr: "rule 'rule 1': no column to check"},
+		{name: "Unknown Column", rule: Rule{Column: "Code", Required: true}, wantErr: "unknown column 'Code'"},
+		{name: "Bad Pattern", rule: Rule{Column: "Id", Pattern: "("}, wantErr: "missing closing )"},
+		{name: "Unknown Expression Column", rule: Rule{Expression: "Price > 0"}, wantErr: "unknown column 'Price'"},
+		{name: "Expression Failing", rule: Rule{Expression: "Amount > 0 and Amount"}, wantErr: "x is not a boolean"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := table.Validate([]Rule{tt.rule}); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %s", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseRules(t *testing.T) {
+	rules, err := ParseRules(strings.NewReader(`
+rules:
+  - column: Email
+    required: true
+    pattern: '[^@ ]+@[^@ ]+'
+  - column: Amount
+    min: 0
+    max: 1e6
+  - column: Status
+    enum: [open, closed]
+    ignoreCase: true
+  - name: Period
+    expression: End >= Start
+    message: the period ends before it starts
+`))
+	if err != nil {
+		t.Fatalf("ParseRules() error = %v", err)
+	}
+	want := []Rule{
+		{Column: "Email", Required: true, Pattern: "[^@ ]+@[^@ ]+"},
+		{Column: "Amount", Min: "0", Max: "1e6"},
+		{Column: "Status", Enum: []string{"open", "closed"}, IgnoreCase: true},
+		{Name: "Period", Expression: "End >= Start", Message: "the period ends before it starts"},
+	}
+	if !reflect.DeepEqual(rules, want) {
+		t.Errorf("ParseRules() = %+v, want %+v", rules, want)
+	}
+	if _, err := ParseRules(strings.NewReader("rules:\n  - colum: Email\n")); err == nil || !strings.Contains(err.Error(), "field colum not found") {
+		t.Errorf("ParseRules() error = %v, want an unknown field", err)
+	}
+}
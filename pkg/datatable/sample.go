@@ -0,0 +1,85 @@
+package datatable
+
+import (
+	"math/rand"
+	"sort"
+)
+
+// Head returns a table with the columns of t and its first n rows, all of them when it has fewer.
+func (t *DataTable) Head(n int) *DataTable {
+	head := t.empty()
+	for _, row := range t.rows[:clamp(n, len(t.rows))] {
+		head.rows = append(head.rows, append([]string(nil), row...))
+	}
+	return head
+}
+
+// Tail returns a table with the columns of t and its last n rows, all of them when it has fewer.
+func (t *DataTable) Tail(n int) *DataTable {
+	tail := t.empty()
+	for _, row := range t.rows[len(t.rows)-clamp(n, len(t.rows)):] {
+		tail.rows = append(tail.rows, append([]string(nil), row...))
+	}
+	return tail
+}
+
+// Sample returns a table with the columns of t and n of its rows picked at random, in their order in t, all of
+// them when it has fewer. The same seed picks the same rows of the same table, see Reservoir.
+func (t *DataTable) Sample(n int, seed int64) *DataTable {
+	reservoir := NewReservoir(n, seed)
+	for _, row := range t.rows {
+		reservoir.Add(row)
+	}
+	sample := t.empty()
+	sample.rows = reservoir.Records()
+	return sample
+}
+
+// Reservoir keeps a uniform random sample of at most n of the records added to it, without holding the others,
+// so large files can be sampled while they are read. The same seed keeps the same records of the same sequence,
+// as DataTable.Sample does.
+type Reservoir struct {
+	size   int
+	random *rand.Rand
+	seen   int
+	kept   []sampledRecord
+}
+
+// sampledRecord is a record kept by a Reservoir with its position among the records added.
+type sampledRecord struct {
+	position int
+	values   []string
+}
+
+// NewReservoir returns an empty reservoir keeping at most n records, picked with the random numbers of seed.
+func NewReservoir(n int, seed int64) *Reservoir {
+	return &Reservoir{size: max(n, 0), random: rand.New(rand.NewSource(seed))}
+}
+
+// Add offers record to the reservoir, which keeps a copy of it or not.
+func (r *Reservoir) Add(record []string) {
+	r.seen++
+	if len(r.kept) < r.size {
+		r.kept = append(r.kept, sampledRecord{position: r.seen, values: append([]string(nil), record...)})
+		return
+	}
+	if slot := r.random.Intn(r.seen); slot < r.size {
+		r.kept[slot] = sampledRecord{position: r.seen, values: append([]string(nil), record...)}
+	}
+}
+
+// Seen returns the number of records added to the reservoir.
+func (r *Reservoir) Seen() int {
+	return r.seen
+}
+
+// Records returns the records kept, in the order they were added.
+func (r *Reservoir) Records() [][]string {
+	kept := append([]sampledRecord(nil), r.kept...)
+	sort.Slice(kept, func(i, j int) bool { return kept[i].position < kept[j].position })
+	records := make([][]string, len(kept))
+	for index, record := range kept {
+		records[index] = append([]string(nil), record.values...)
+	}
+	return records
+}
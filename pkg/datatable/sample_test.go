@@ -0,0 +1,65 @@
+package datatable
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"testing"
+)
+
+func TestHeadTail(t *testing.T) {
+	table := mustTable(t, []string{"N"}, []string{"1"}, []string{"2"}, []string{"3"})
+	tests := []struct {
+		name string
+		got  *DataTable
+		want [][]string
+	}{
+		{name: "Head", got: table.Head(2), want: [][]string{{"1"}, {"2"}}},
+		{name: "Head Beyond", got: table.Head(5), want: [][]string{{"1"}, {"2"}, {"3"}}},
+		{name: "Head Zero", got: table.Head(0), want: [][]string{}},
+		{name: "Tail", got: table.Tail(2), want: [][]string{{"2"}, {"3"}}},
+		{name: "Tail Negative", got: table.Tail(-1), want: [][]string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !reflect.DeepEqual(tt.got.Records(), tt.want) || !reflect.DeepEqual(tt.got.ColumnNames(), []string{"N"}) {
+				t.Errorf("records = %v, want %v", tt.got.Records(), tt.want)
+			}
+		})
+	}
+	head := table.Head(1)
+	head.Row(0).Set("N", "changed")
+	if table.Row(0).Get("N") != "1" {
+		t.Error("changing the head changed the table")
+	}
+}
+
+func TestSample(t *testing.T) {
+	table := mustTable(t, []string{"N"})
+	for number := 1; number <= 100; number++ {
+		if err := table.AddRow(strconv.Itoa(number)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sample := table.Sample(10, 42)
+	if sample.Len() != 10 {
+		t.Fatalf("Sample() has %d rows, want 10", sample.Len())
+	}
+	var numbers []int
+	for _, record := range sample.Records() {
+		number, _ := strconv.Atoi(record[0])
+		numbers = append(numbers, number)
+	}
+	if !sort.IntsAreSorted(numbers) {
+		t.Errorf("Sample() rows %v are not in table order", numbers)
+	}
+	if again := table.Sample(10, 42); !reflect.DeepEqual(again.Records(), sample.Records()) {
+		t.Errorf("Sample() with the same seed = %v, want %v", again.Records(), sample.Records())
+	}
+	if other := table.Sample(10, 7); reflect.DeepEqual(other.Records(), sample.Records()) {
+		t.Errorf("Sample() with another seed picked the same rows %v", other.Records())
+	}
+	if all := table.Sample(500, 1); all.Len() != 100 {
+		t.Errorf("Sample() of more rows than the table has %d rows, want 100", all.Len())
+	}
+}
@@ -0,0 +1,165 @@
+package datatable
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Filter returns a table with the columns of t and the rows keep returns true for, in their order.
+func (t *DataTable) Filter(keep func(row Row) bool) *DataTable {
+	filtered := t.empty()
+	for index, values := range t.rows {
+		if keep(t.Row(index)) {
+			filtered.rows = append(filtered.rows, append([]string(nil), values...))
+		}
+	}
+	return filtered
+}
+
+// SortOrder selects whether a sort puts the smallest values first or last.
+type SortOrder int
+
+const (
+	// Ascending puts the smallest values first. It is the default.
+	Ascending SortOrder = iota
+	// Descending puts the largest values first.
+	Descending
+)
+
+// ParseSortOrder returns the SortOrder called asc or desc.
+func ParseSortOrder(name string) (SortOrder, error) {
+	switch strings.ToLower(name) {
+	case "", "asc", "ascending":
+		return Ascending, nil
+	case "desc", "descending":
+		return Descending, nil
+	}
+	return Ascending, fmt.Errorf("unknown sort order '%s'", name)
+}
+
+// Comparator compares two values, returning a negative number when a sorts before b, a positive one when it
+// sorts after it and 0 when they are equal.
+type Comparator func(a, b string) int
+
+// CompareText compares values as text, byte by byte.
+func CompareText(a, b string) int {
+	return strings.Compare(a, b)
+}
+
+// CompareFold compares values as text regardless of case.
+func CompareFold(a, b string) int {
+	return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+}
+
+// SortKey sorts a table on the values of Column in Order. Compare compares them, by the type of the column
+// when it is nil: numbers as numbers, dates in time order, false before true and text byte by byte.
+type SortKey struct {
+	Column  string
+	Order   SortOrder
+	Compare Comparator
+}
+
+// ParseSortKeys returns the sort keys of a comma separated list of column names, each sorted in Ascending order
+// unless preceded by a minus sign, such as "Region,-Amount".
+func ParseSortKeys(list string) ([]SortKey, error) {
+	var keys []SortKey
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		key := SortKey{Column: name}
+		if strings.HasPrefix(name, "-") {
+			key = SortKey{Column: strings.TrimSpace(name[1:]), Order: Descending}
+		}
+		if len(key.Column) < 1 {
+			return nil, fmt.Errorf("the sort keys '%s' name a blank column", list)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// Sort returns a table with the rows of t sorted on the values of column, see SortBy.
+func (t *DataTable) Sort(column string, order SortOrder, compare Comparator) (*DataTable, error) {
+	return t.SortBy(SortKey{Column: column, Order: order, Compare: compare})
+}
+
+// SortBy returns a table with the rows of t sorted on the first key, rows with equal values on the following
+// keys, and rows equal on every key in their order in t. Blank values sort last whatever the order, and values
+// which are not a valid form of the type of their column after those which are.
+func (t *DataTable) SortBy(keys ...SortKey) (*DataTable, error) {
+	if len(keys) < 1 {
+		return nil, errors.New("no column to sort on")
+	}
+	positions := make([]int, len(keys))
+	compares := make([]Comparator, len(keys))
+	for index, key := range keys {
+		position, err := t.ColumnIndex(key.Column)
+		if err != nil {
+			return nil, err
+		}
+		positions[index] = position
+		if compares[index] = key.Compare; compares[index] == nil {
+			compares[index] = t.columns[position].Type.compare
+		}
+	}
+	sorted := t.Clone()
+	sort.SliceStable(sorted.rows, func(i, j int) bool {
+		for index, key := range keys {
+			a, b := sorted.rows[i][positions[index]], sorted.rows[j][positions[index]]
+			aBlank, bBlank := len(strings.TrimSpace(a)) < 1, len(strings.TrimSpace(b)) < 1
+			if aBlank || bBlank {
+				if aBlank != bBlank {
+					return bBlank
+				}
+				continue
+			}
+			result := compares[index](a, b)
+			if key.Order == Descending {
+				result = -result
+			}
+			if result != 0 {
+				return result < 0
+			}
+		}
+		return false
+	})
+	return sorted, nil
+}
+
+// compare compares a and b, which are not blank, as values of the type, putting the values which are not a valid
+// form of it after those which are, and comparing those as text.
+func (t ColumnType) compare(a, b string) int {
+	if t == StringType {
+		return strings.Compare(a, b)
+	}
+	first, firstErr := t.Parse(a)
+	second, secondErr := t.Parse(b)
+	switch {
+	case firstErr != nil && secondErr != nil:
+		return strings.Compare(a, b)
+	case firstErr != nil:
+		return 1
+	case secondErr != nil:
+		return -1
+	}
+	switch first := first.(type) {
+	case int64:
+		return cmp.Compare(first, second.(int64))
+	case float64:
+		return cmp.Compare(first, second.(float64))
+	case bool:
+		if first == second.(bool) {
+			return 0
+		}
+		if first {
+			return 1
+		}
+		return -1
+	case time.Time:
+		return first.Compare(second.(time.Time))
+	}
+	return strings.Compare(a, b)
+}
@@ -0,0 +1,94 @@
+package datatable
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// column returns the values of the named column of table.
+func column(table *DataTable, name string) []string {
+	var values []string
+	_ = table.Each(func(row Row) error {
+		values = append(values, row.Get(name))
+		return nil
+	})
+	return values
+}
+
+func TestFilter(t *testing.T) {
+	table := mustTable(t, []string{"Region", "Amount"}, []string{"North", "10"}, []string{"South", "5"}, []string{"North", "7"})
+	north := table.Filter(func(row Row) bool { return row.Get("Region") == "North" })
+	if want := [][]string{{"North", "10"}, {"North", "7"}}; !reflect.DeepEqual(north.Records(), want) {
+		t.Errorf("Filter() = %v, want %v", north.Records(), want)
+	}
+	_ = north.Row(0).Set("Amount", "0")
+	if table.Row(0).Get("Amount") != "10" {
+		t.Errorf("Filter() shares its rows with the table")
+	}
+}
+
+func TestSortBy(t *testing.T) {
+	table := mustTable(t, []string{"Name", "Region", "Amount", "Due"},
+		[]string{"a", "North", "10", "2024-03-01"},
+		[]string{"b", "south", "9", ""},
+		[]string{"c", "North", "", "2023-12-31"},
+		[]string{"d", "South", "100", "2024-01-15"},
+		[]string{"e", "North", "n/a", "2024-03-01"},
+		[]string{"f", "North", "10", "2024-02-01"})
+	table.InferTypes()
+	_ = table.SetColumnType("Amount", IntegerType)
+
+	tests := []struct {
+		name    string
+		keys    []SortKey
+		want    []string
+		wantErr string
+	}{
+		{name: "Numbers", keys: []SortKey{{Column: "Amount"}}, want: []string{"b", "a", "f", "d", "e", "c"}},
+		{name: "Numbers Descending", keys: []SortKey{{Column: "Amount", Order: Descending}}, want: []string{"e", "d", "a", "f", "b", "c"}},
+		{name: "Dates", keys: []SortKey{{Column: "Due"}}, want: []string{"c", "d", "f", "a", "e", "b"}},
+		{name: "Text", keys: []SortKey{{Column: "Region"}}, want: []string{"a", "c", "e", "f", "d", "b"}},
+		{name: "Case Insensitive", keys: []SortKey{{Column: "Region", Compare: CompareFold}}, want: []string{"a", "c", "e", "f", "b", "d"}},
+		{name: "Several Keys", keys: []SortKey{{Column: "Region", Compare: CompareFold}, {Column: "Amount", Order: Descending}, {Column: "Due"}}, want: []string{"e", "f", "a", "c", "d", "b"}},
+		{name: "Unknown Column", keys: []SortKey{{Column: "Country"}}, wantErr: "unknown column 'Country'"},
+		{name: "No Keys", wantErr: "no column to sort on"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sorted, err := table.SortBy(tt.keys...)
+			if len(tt.wantErr) > 0 {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("SortBy() error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SortBy() error = %v", err)
+			}
+			if got := column(sorted, "Name"); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SortBy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if got := column(table, "Name"); !reflect.DeepEqual(got, []string{"a", "b", "c", "d", "e", "f"}) {
+		t.Errorf("SortBy() sorted the table itself: %v", got)
+	}
+	sorted, err := table.Sort("Name", Descending, CompareText)
+	if err != nil || !reflect.DeepEqual(column(sorted, "Name"), []string{"f", "e", "d", "c", "b", "a"}) {
+		t.Errorf("Sort() = %v, %v", column(sorted, "Name"), err)
+	}
+}
+
+func TestParseSortKeys(t *testing.T) {
+	keys, err := ParseSortKeys("Region, -Amount")
+	if want := []SortKey{{Column: "Region"}, {Column: "Amount", Order: Descending}}; err != nil || !reflect.DeepEqual(keys, want) {
+		t.Errorf("ParseSortKeys() = %+v, %v, want %+v", keys, err, want)
+	}
+	if _, err := ParseSortKeys("Region,-"); err == nil || !strings.Contains(err.Error(), "blank column") {
+		t.Errorf("ParseSortKeys(Region,-) error = %v", err)
+	}
+	if _, err := ParseSortOrder("up"); err == nil || err.Error() != "unknown sort order 'up'" {
+		t.Errorf("ParseSortOrder(up) error = %v", err)
+	}
+}
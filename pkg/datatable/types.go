@@ -0,0 +1,137 @@
+package datatable
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	. "GoTools/pkg/helpers"
+)
+
+// ColumnType is the type the values of a column are read as.
+type ColumnType int
+
+const (
+	// StringType reads values as they are. It is the default.
+	StringType ColumnType = iota
+	// IntegerType reads whole numbers as int64.
+	IntegerType
+	// DecimalType reads numbers as float64.
+	DecimalType
+	// BooleanType reads booleans, such as true, yes or 1, as bool; see DefaultBooleans.
+	BooleanType
+	// DateType reads dates as time.Time at midnight.
+	DateType
+	// DateTimeType reads dates and times as time.Time.
+	DateTimeType
+)
+
+// ParseColumnType returns the ColumnType called string, integer, decimal, boolean, date or datetime.
+func ParseColumnType(name string) (ColumnType, error) {
+	switch strings.ToLower(name) {
+	case "", "string":
+		return StringType, nil
+	case "integer", "int":
+		return IntegerType, nil
+	case "decimal", "number", "double":
+		return DecimalType, nil
+	case "boolean", "bool":
+		return BooleanType, nil
+	case "date":
+		return DateType, nil
+	case "datetime":
+		return DateTimeType, nil
+	}
+	return StringType, fmt.Errorf("unknown column type '%s'", name)
+}
+
+// String returns the name of the type, as taken by ParseColumnType.
+func (t ColumnType) String() string {
+	switch t {
+	case IntegerType:
+		return "integer"
+	case DecimalType:
+		return "decimal"
+	case BooleanType:
+		return "boolean"
+	case DateType:
+		return "date"
+	case DateTimeType:
+		return "datetime"
+	}
+	return "string"
+}
+
+// Parse returns value read as the type: a string, an int64, a float64, a bool or a time.Time, or nil when
+// value is blank. Dates are read in ISO 8601, or as ParseToISO8601 reads them.
+func (t ColumnType) Parse(value string) (any, error) {
+	if t == StringType {
+		return value, nil
+	}
+	trimmed := strings.TrimSpace(value)
+	if len(trimmed) < 1 {
+		return nil, nil
+	}
+	switch t {
+	case IntegerType:
+		number, err := strconv.ParseInt(trimmed, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("'%s' is not a whole number", value)
+		}
+		return number, nil
+	case DecimalType:
+		number, err := strconv.ParseFloat(trimmed, 64)
+		if err != nil {
+			return nil, fmt.Errorf("'%s' is %w", value, ErrNotANumber)
+		}
+		return number, nil
+	case BooleanType:
+		return NormalizeBoolean(trimmed, BooleanOptions{})
+	}
+	date, err := parseTime(trimmed)
+	if err != nil {
+		return nil, err
+	}
+	if t == DateType {
+		year, month, day := date.Date()
+		return time.Date(year, month, day, 0, 0, 0, 0, date.Location()), nil
+	}
+	return date, nil
+}
+
+// isoLayouts are the layouts of the dates and times written in ISO 8601 Parse reads.
+var isoLayouts = []string{time.DateOnly, time.RFC3339Nano, "2006-01-02T15:04:05", time.DateTime}
+
+// parseTime returns value read in one of the ISO 8601 layouts, or as ParseToISO8601 reads it.
+func parseTime(value string) (time.Time, error) {
+	for _, layout := range isoLayouts {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date, nil
+		}
+	}
+	return ParseToISO8601(value)
+}
+
+// InferType returns the type of the column of values, inferred by a TypeInferrer from every value converted with
+// ConvertToISO8601, as the converters infer the type of a worksheet column: integers before decimals and doubles,
+// booleans only when written true or false, and dates and times mixed as DateTimeType. Numbers with leading
+// zeros, such as postal codes, and columns of blank values are StringType.
+func InferType(values []string) ColumnType {
+	inferrer := NewTypeInferrer(InferenceOptions{})
+	for _, value := range values {
+		inferrer.Add(ConvertToISO8601(value))
+	}
+	return inferrer.Result().Type.ColumnType()
+}
+
+// InferTypes sets the type of every column of the table to the type InferType finds for its values.
+func (t *DataTable) InferTypes() {
+	for position := range t.columns {
+		values := make([]string, len(t.rows))
+		for index, row := range t.rows {
+			values[index] = row[position]
+		}
+		t.columns[position].Type = InferType(values)
+	}
+}
@@ -0,0 +1,210 @@
+// Package fixedwidth reads fixed-width text files, such as mainframe extracts, whose fields sit at the same
+// character positions on every line. A Layout lists the fields, either read from a layout file or taken
+// from the tags of a struct.
+package fixedwidth
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Field is a single field of a record: the characters from the one-based column Start that span Width columns.
+type Field struct {
+	Name  string
+	Start int
+	Width int
+}
+
+// end returns the one-based column following the field.
+func (f Field) end() int {
+	return f.Start + f.Width
+}
+
+// Layout lists the fields of a record in the order they are read, which need not be the order of their positions.
+type Layout []Field
+
+// Validate reports fields without a name, with a name used by another field, with a start before the first column
+// or without a width, and fields overlapping one another.
+func (l Layout) Validate() error {
+	if len(l) < 1 {
+		return errors.New("the layout holds no fields")
+	}
+	names := make(map[string]bool, len(l))
+	for _, field := range l {
+		switch {
+		case len(field.Name) < 1:
+			return fmt.Errorf("the field at column %d has no name", field.Start)
+		case names[field.Name]:
+			return fmt.Errorf("the field '%s' is defined twice", field.Name)
+		case field.Start < 1:
+			return fmt.Errorf("the field '%s' starts before the first column", field.Name)
+		case field.Width < 1:
+			return fmt.Errorf("the field '%s' has no width", field.Name)
+		}
+		names[field.Name] = true
+	}
+	sorted := append(Layout(nil), l...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Start < sorted[j].Start })
+	for index := 1; index < len(sorted); index++ {
+		if previous := sorted[index-1]; sorted[index].Start < previous.end() {
+			return fmt.Errorf("the field '%s' overlaps the field '%s'", sorted[index].Name, previous.Name)
+		}
+	}
+	return nil
+}
+
+// Names returns the names of the fields, in layout order.
+func (l Layout) Names() []string {
+	names := make([]string, len(l))
+	for index, field := range l {
+		names[index] = field.Name
+	}
+	return names
+}
+
+// Split cuts line into the values of the fields, in layout order, with their surrounding spaces removed.
+// Positions count characters rather than bytes, and fields past the end of a short line are empty.
+func (l Layout) Split(line string) []string {
+	runes := []rune(line)
+	values := make([]string, len(l))
+	for index, field := range l {
+		start, end := min(field.Start-1, len(runes)), min(field.end()-1, len(runes))
+		values[index] = strings.TrimSpace(string(runes[start:end]))
+	}
+	return values
+}
+
+// ParseLayout reads a layout file from r. Every line defines a field as its name, its one-based start column and
+// its width, separated by commas or by spaces; a name holding spaces needs commas. Blank lines and lines starting
+// with # are skipped, so the first line may be a comment naming the columns.
+func ParseLayout(r io.Reader) (Layout, error) {
+	var layout Layout
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) < 1 || strings.HasPrefix(line, "#") {
+			continue
+		}
+		var parts []string
+		if strings.Contains(line, ",") {
+			parts = strings.Split(line, ",")
+		} else {
+			parts = strings.Fields(line)
+		}
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("line %d: expected a name, a start column and a width, got '%s'", lineNumber, line)
+		}
+		field := Field{Name: strings.TrimSpace(parts[0])}
+		var startErr, widthErr error
+		field.Start, startErr = strconv.Atoi(strings.TrimSpace(parts[1]))
+		field.Width, widthErr = strconv.Atoi(strings.TrimSpace(parts[2]))
+		if startErr != nil || widthErr != nil {
+			return nil, fmt.Errorf("line %d: the start column and width must be whole numbers, got '%s'", lineNumber, line)
+		}
+		layout = append(layout, field)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return layout, layout.Validate()
+}
+
+// LoadLayout reads the layout file at path, see ParseLayout.
+func LoadLayout(path string) (layout Layout, loadErr error) {
+	file, openErr := os.Open(path)
+	if openErr != nil {
+		return nil, openErr
+	}
+	defer func(file *os.File) {
+		if err := file.Close(); err != nil && loadErr == nil {
+			loadErr = err
+		}
+	}(file)
+	return ParseLayout(file)
+}
+
+// LayoutOf returns the layout described by the fixed tags of the fields of the struct v, or of the struct v
+// points to. A tag holds the name of the field, its one-based start column and its width, as in
+// `fixed:"CUST-NAME,1,20"`; an empty name stands for the name of the struct field. Struct fields without
+// a fixed tag or tagged "-" are not part of the layout.
+func LayoutOf(v any) (Layout, error) {
+	structType := reflect.TypeOf(v)
+	for structType != nil && structType.Kind() == reflect.Pointer {
+		structType = structType.Elem()
+	}
+	if structType == nil || structType.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("fixed-width layouts are read from structs, got %v", structType)
+	}
+	var layout Layout
+	for index := 0; index < structType.NumField(); index++ {
+		structField := structType.Field(index)
+		tag, tagged := structField.Tag.Lookup("fixed")
+		if !tagged || tag == "-" {
+			continue
+		}
+		parts := strings.Split(tag, ",")
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("field %s: expected a fixed tag holding a name, a start column and a width, got '%s'", structField.Name, tag)
+		}
+		field := Field{Name: parts[0]}
+		if len(field.Name) < 1 {
+			field.Name = structField.Name
+		}
+		var startErr, widthErr error
+		field.Start, startErr = strconv.Atoi(parts[1])
+		field.Width, widthErr = strconv.Atoi(parts[2])
+		if startErr != nil || widthErr != nil {
+			return nil, fmt.Errorf("field %s: the start column and width must be whole numbers, got '%s'", structField.Name, tag)
+		}
+		layout = append(layout, field)
+	}
+	return layout, layout.Validate()
+}
+
+// Reader reads the records of a fixed-width text file.
+type Reader struct {
+	layout     Layout
+	scanner    *bufio.Scanner
+	lineNumber int
+}
+
+// NewReader returns a Reader cutting the lines read from r into the fields of layout.
+func NewReader(r io.Reader, layout Layout) *Reader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	return &Reader{layout: layout, scanner: scanner}
+}
+
+// Read returns the values of the next record, in layout order, or io.EOF once every line has been read.
+// Blank lines are skipped, and a byte order mark at the start of the input is ignored.
+func (r *Reader) Read() ([]string, error) {
+	for r.scanner.Scan() {
+		r.lineNumber++
+		line := strings.TrimSuffix(r.scanner.Text(), "\r")
+		if r.lineNumber == 1 {
+			line = strings.TrimPrefix(line, "\uFEFF")
+		}
+		if len(strings.TrimSpace(line)) < 1 {
+			continue
+		}
+		return r.layout.Split(line), nil
+	}
+	if err := r.scanner.Err(); err != nil {
+		return nil, fmt.Errorf("line %d: %w", r.lineNumber+1, err)
+	}
+	return nil, io.EOF
+}
+
+// Line returns the one-based number of the line of the record last read.
+func (r *Reader) Line() int {
+	return r.lineNumber
+}
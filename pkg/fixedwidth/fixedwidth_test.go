@@ -0,0 +1,111 @@
+package fixedwidth
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseLayout(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    Layout
+		wantErr bool
+	}{
+		{
+			name:  "Spaces",
+			input: "# name start width\nID 1 5\n\nNAME 6 20\n",
+			want:  Layout{{Name: "ID", Start: 1, Width: 5}, {Name: "NAME", Start: 6, Width: 20}},
+		},
+		{
+			name:  "Commas",
+			input: "Customer Name, 1, 20\nBalance,21,9\n",
+			want:  Layout{{Name: "Customer Name", Start: 1, Width: 20}, {Name: "Balance", Start: 21, Width: 9}},
+		},
+		{name: "Missing Width", input: "ID 1\n", wantErr: true},
+		{name: "Not A Number", input: "ID one 5\n", wantErr: true},
+		{name: "Overlap", input: "ID 1 5\nNAME 5 20\n", wantErr: true},
+		{name: "Duplicate", input: "ID 1 5\nID 6 5\n", wantErr: true},
+		{name: "Zero Start", input: "ID 0 5\n", wantErr: true},
+		{name: "Empty", input: "# nothing\n", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseLayout(strings.NewReader(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseLayout() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseLayout() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLayoutOf(t *testing.T) {
+	type account struct {
+		ID      string `fixed:",1,5"`
+		Name    string `fixed:"CUST-NAME,6,10"`
+		Balance string `fixed:"BALANCE,16,8"`
+		Note    string `fixed:"-"`
+		Ignored string
+	}
+	tests := []struct {
+		name    string
+		value   any
+		want    Layout
+		wantErr bool
+	}{
+		{
+			name:  "Struct",
+			value: account{},
+			want:  Layout{{Name: "ID", Start: 1, Width: 5}, {Name: "CUST-NAME", Start: 6, Width: 10}, {Name: "BALANCE", Start: 16, Width: 8}},
+		},
+		{
+			name:  "Pointer",
+			value: &account{},
+			want:  Layout{{Name: "ID", Start: 1, Width: 5}, {Name: "CUST-NAME", Start: 6, Width: 10}, {Name: "BALANCE", Start: 16, Width: 8}},
+		},
+		{name: "Not A Struct", value: 5, wantErr: true},
+		{name: "Bad Tag", value: struct {
+			ID string `fixed:"1,5"`
+		}{}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := LayoutOf(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LayoutOf() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LayoutOf() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReader(t *testing.T) {
+	layout := Layout{{Name: "NAME", Start: 6, Width: 6}, {Name: "ID", Start: 1, Width: 5}, {Name: "CITY", Start: 12, Width: 8}}
+	input := "\uFEFF00001Zoë   Paris   \r\n\n   42Bob\n00003Carl  Rome    extra\n"
+	want := [][]string{{"Zoë", "00001", "Paris"}, {"Bob", "42", ""}, {"Carl", "00003", "Rome"}}
+	reader := NewReader(strings.NewReader(input), layout)
+	var got [][]string
+	for {
+		values, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Read() error = %v", err)
+		}
+		got = append(got, values)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Read() = %q, want %q", got, want)
+	}
+	if reader.Line() != 4 {
+		t.Errorf("Line() = %d, want 4", reader.Line())
+	}
+}
@@ -0,0 +1,335 @@
+// Package gsheets reads the cell values of Google Sheets spreadsheets through the Sheets API, authenticating
+// as a service account. Only what a conversion needs is implemented: the titles of the sheets of a spreadsheet
+// and the values of ranges of cells, read as they are displayed.
+package gsheets
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultEndpoint is the root of the Sheets API.
+	DefaultEndpoint = "https://sheets.googleapis.com/v4"
+	// defaultTokenURI issues the access tokens of service accounts whose key does not name a token URI.
+	defaultTokenURI = "https://oauth2.googleapis.com/token"
+	// readOnlyScope grants read access to every spreadsheet shared with the service account.
+	readOnlyScope = "https://www.googleapis.com/auth/spreadsheets.readonly"
+	// tokenLifetime is how long the access tokens requested are valid, the longest Google issues.
+	tokenLifetime = time.Hour
+	// tokenMargin renews an access token that long before it expires, so no request is sent with an expired token.
+	tokenMargin = time.Minute
+)
+
+// ServiceAccount holds the fields of a service account key file, as downloaded from the Google Cloud console,
+// that authentication needs.
+type ServiceAccount struct {
+	ClientEmail  string `json:"client_email"`
+	PrivateKeyID string `json:"private_key_id"`
+	PrivateKey   string `json:"private_key"`
+	TokenURI     string `json:"token_uri"`
+}
+
+// ParseServiceAccount reads a service account key file from data.
+func ParseServiceAccount(data []byte) (ServiceAccount, error) {
+	var account ServiceAccount
+	if err := json.Unmarshal(data, &account); err != nil {
+		return account, fmt.Errorf("reading the service account key: %w", err)
+	}
+	if len(account.ClientEmail) < 1 || len(account.PrivateKey) < 1 {
+		return account, errors.New("the service account key holds no client email or private key")
+	}
+	return account, nil
+}
+
+// LoadServiceAccount reads the service account key file at path.
+func LoadServiceAccount(path string) (ServiceAccount, error) {
+	data, readErr := os.ReadFile(path)
+	if readErr != nil {
+		return ServiceAccount{}, readErr
+	}
+	return ParseServiceAccount(data)
+}
+
+// Client sends requests to the Sheets API as a service account, renewing its access token as it expires.
+// The spreadsheets read must be shared with the email address of the service account.
+// Endpoint is the root of the API, DefaultEndpoint unless overridden, for instance to go through a proxy.
+// A Client is safe for concurrent use.
+type Client struct {
+	Endpoint   string
+	account    ServiceAccount
+	key        *rsa.PrivateKey
+	httpClient *http.Client
+	mutex      sync.Mutex
+	token      string
+	expiry     time.Time
+}
+
+// NewClient returns a Client authenticating as account, sending its requests with httpClient,
+// or with http.DefaultClient when it is nil.
+func NewClient(account ServiceAccount, httpClient *http.Client) (*Client, error) {
+	block, _ := pem.Decode([]byte(account.PrivateKey))
+	if block == nil {
+		return nil, errors.New("the private key of the service account is not PEM encoded")
+	}
+	parsed, parseErr := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if parseErr != nil {
+		if parsed, parseErr = x509.ParsePKCS1PrivateKey(block.Bytes); parseErr != nil {
+			return nil, fmt.Errorf("reading the private key of the service account: %w", parseErr)
+		}
+	}
+	key, isRsa := parsed.(*rsa.PrivateKey)
+	if !isRsa {
+		return nil, errors.New("the private key of the service account is not an RSA key")
+	}
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Client{Endpoint: DefaultEndpoint, account: account, key: key, httpClient: httpClient}, nil
+}
+
+// SheetProperties describes a sheet of a spreadsheet.
+type SheetProperties struct {
+	Title  string `json:"title"`
+	Hidden bool   `json:"hidden"`
+}
+
+// Sheets returns the properties of the sheets of the spreadsheet, in tab order.
+func (c *Client) Sheets(ctx context.Context, spreadsheetID string) ([]SheetProperties, error) {
+	var response struct {
+		Sheets []struct {
+			Properties SheetProperties `json:"properties"`
+		} `json:"sheets"`
+	}
+	query := url.Values{"fields": {"sheets.properties(title,hidden)"}}
+	if err := c.get(ctx, "/spreadsheets/"+url.PathEscape(spreadsheetID), query, &response); err != nil {
+		return nil, err
+	}
+	sheets := make([]SheetProperties, len(response.Sheets))
+	for index, sheet := range response.Sheets {
+		sheets[index] = sheet.Properties
+	}
+	return sheets, nil
+}
+
+// ValueRange holds the values of a range of cells, row by row. Range is the A1 notation of the range read,
+// starting with the title of its sheet. Rows end at their last non-empty cell.
+type ValueRange struct {
+	Range  string
+	Values [][]string
+}
+
+// Sheet returns the title of the sheet the range was read from.
+func (r ValueRange) Sheet() string {
+	title, _ := SplitRange(r.Range)
+	return title
+}
+
+// Values returns the values of the ranges of the spreadsheet, given in A1 notation such as Orders!A1:F
+// or just a sheet title, read with a single request. Values are read as they are displayed, with their
+// number and date formats applied.
+func (c *Client) Values(ctx context.Context, spreadsheetID string, ranges ...string) ([]ValueRange, error) {
+	var response struct {
+		ValueRanges []struct {
+			Range  string  `json:"range"`
+			Values [][]any `json:"values"`
+		} `json:"valueRanges"`
+	}
+	query := url.Values{
+		"ranges":               ranges,
+		"majorDimension":       {"ROWS"},
+		"valueRenderOption":    {"FORMATTED_VALUE"},
+		"dateTimeRenderOption": {"FORMATTED_STRING"},
+	}
+	if err := c.get(ctx, "/spreadsheets/"+url.PathEscape(spreadsheetID)+"/values:batchGet", query, &response); err != nil {
+		return nil, err
+	}
+	valueRanges := make([]ValueRange, len(response.ValueRanges))
+	for index, valueRange := range response.ValueRanges {
+		rows := make([][]string, len(valueRange.Values))
+		for rowIndex, row := range valueRange.Values {
+			rows[rowIndex] = make([]string, len(row))
+			for columnIndex, value := range row {
+				if value != nil {
+					rows[rowIndex][columnIndex] = fmt.Sprint(value)
+				}
+			}
+		}
+		valueRanges[index] = ValueRange{Range: valueRange.Range, Values: rows}
+	}
+	return valueRanges, nil
+}
+
+// QuoteSheet returns the title of a sheet quoted for use in a range, as in 'Q1 Orders'!A1:F.
+func QuoteSheet(title string) string {
+	return "'" + strings.ReplaceAll(title, "'", "''") + "'"
+}
+
+// SplitRange splits a range in A1 notation into the title of its sheet, unquoted, and its cells,
+// which are empty for a range naming a whole sheet.
+func SplitRange(a1 string) (title, cells string) {
+	if strings.HasPrefix(a1, "'") {
+		// A quoted title ends at the first single quote that is not doubled.
+		for index := 1; index < len(a1); index++ {
+			if a1[index] != '\'' {
+				continue
+			}
+			if index+1 < len(a1) && a1[index+1] == '\'' {
+				index++
+				continue
+			}
+			title = strings.ReplaceAll(a1[1:index], "''", "'")
+			return title, strings.TrimPrefix(a1[index+1:], "!")
+		}
+	}
+	if separator := strings.LastIndex(a1, "!"); separator >= 0 {
+		return a1[:separator], a1[separator+1:]
+	}
+	return a1, ""
+}
+
+// apiError is the error body the Sheets API and the token endpoint answer failed requests with.
+type apiError struct {
+	Error            json.RawMessage `json:"error"`
+	ErrorDescription string          `json:"error_description"`
+}
+
+// message returns the description of the error held in body, or the status text of the response.
+func (e apiError) message(body []byte, status int) string {
+	if json.Unmarshal(body, &e) != nil {
+		return http.StatusText(status)
+	}
+	var detailed struct {
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(e.Error, &detailed) == nil && len(detailed.Message) > 0 {
+		return detailed.Message
+	}
+	if len(e.ErrorDescription) > 0 {
+		return e.ErrorDescription
+	}
+	var code string
+	if json.Unmarshal(e.Error, &code) == nil && len(code) > 0 {
+		return code
+	}
+	return http.StatusText(status)
+}
+
+// get sends an authenticated GET request for path below the endpoint and decodes the JSON response into target.
+func (c *Client) get(ctx context.Context, path string, query url.Values, target any) error {
+	token, tokenErr := c.accessToken(ctx)
+	if tokenErr != nil {
+		return tokenErr
+	}
+	request, requestErr := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(c.Endpoint, "/")+path+"?"+query.Encode(), nil)
+	if requestErr != nil {
+		return requestErr
+	}
+	request.Header.Set("Authorization", "Bearer "+token)
+	return c.send(request, target)
+}
+
+// send sends request and decodes the JSON response into target, turning failed responses into errors.
+func (c *Client) send(request *http.Request, target any) (sendErr error) {
+	response, responseErr := c.httpClient.Do(request)
+	if responseErr != nil {
+		return responseErr
+	}
+	defer func(body io.ReadCloser) {
+		if err := body.Close(); err != nil && sendErr == nil {
+			sendErr = err
+		}
+	}(response.Body)
+	body, readErr := io.ReadAll(response.Body)
+	if readErr != nil {
+		return readErr
+	}
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("google sheets request failed with status %d: %s", response.StatusCode,
+			apiError{}.message(body, response.StatusCode))
+	}
+	return json.Unmarshal(body, target)
+}
+
+// accessToken returns an access token of the service account, requesting a new one when none is held
+// or the one held is about to expire.
+func (c *Client) accessToken(ctx context.Context) (string, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	now := time.Now()
+	if len(c.token) > 0 && now.Add(tokenMargin).Before(c.expiry) {
+		return c.token, nil
+	}
+	tokenURI := c.account.TokenURI
+	if len(tokenURI) < 1 {
+		tokenURI = defaultTokenURI
+	}
+	assertion, signErr := c.assertion(tokenURI, now)
+	if signErr != nil {
+		return "", signErr
+	}
+	form := url.Values{"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"}, "assertion": {assertion}}
+	request, requestErr := http.NewRequestWithContext(ctx, http.MethodPost, tokenURI, strings.NewReader(form.Encode()))
+	if requestErr != nil {
+		return "", requestErr
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	var response struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := c.send(request, &response); err != nil {
+		return "", fmt.Errorf("authenticating as %s: %w", c.account.ClientEmail, err)
+	}
+	if len(response.AccessToken) < 1 {
+		return "", fmt.Errorf("authenticating as %s: no access token issued", c.account.ClientEmail)
+	}
+	c.token, c.expiry = response.AccessToken, now.Add(time.Duration(response.ExpiresIn)*time.Second)
+	return c.token, nil
+}
+
+// assertion returns the signed JSON Web Token exchanged for an access token at tokenURI.
+func (c *Client) assertion(tokenURI string, now time.Time) (string, error) {
+	header := map[string]string{"alg": "RS256", "typ": "JWT"}
+	if len(c.account.PrivateKeyID) > 0 {
+		header["kid"] = c.account.PrivateKeyID
+	}
+	claims := map[string]any{
+		"iss":   c.account.ClientEmail,
+		"scope": readOnlyScope,
+		"aud":   tokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(tokenLifetime).Unix(),
+	}
+	encodedHeader, headerErr := json.Marshal(header)
+	if headerErr != nil {
+		return "", headerErr
+	}
+	encodedClaims, claimsErr := json.Marshal(claims)
+	if claimsErr != nil {
+		return "", claimsErr
+	}
+	unsigned := base64.RawURLEncoding.EncodeToString(encodedHeader) + "." + base64.RawURLEncoding.EncodeToString(encodedClaims)
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, signErr := rsa.SignPKCS1v15(rand.Reader, c.key, crypto.SHA256, digest[:])
+	if signErr != nil {
+		return "", signErr
+	}
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
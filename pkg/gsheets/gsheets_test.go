@@ -0,0 +1,153 @@
+package gsheets
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// newTestServer serves the token endpoint and the Sheets API for a service account holding key,
+// checking the signature of every assertion and the token of every request. It counts the tokens issued.
+func newTestServer(t *testing.T, key *rsa.PrivateKey, tokens *int) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.Split(r.FormValue("assertion"), ".")
+		if len(parts) != 3 {
+			http.Error(w, `{"error":"invalid_grant","error_description":"malformed assertion"}`, http.StatusBadRequest)
+			return
+		}
+		digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+		signature, _ := base64.RawURLEncoding.DecodeString(parts[2])
+		if err := rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], signature); err != nil {
+			http.Error(w, `{"error":"invalid_grant","error_description":"bad signature"}`, http.StatusBadRequest)
+			return
+		}
+		*tokens++
+		_, _ = w.Write([]byte(`{"access_token":"secret","expires_in":3600}`))
+	})
+	mux.HandleFunc("/v4/spreadsheets/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":{"code":401,"message":"Request had invalid authentication credentials."}}`))
+			return
+		}
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/missing/values:batchGet"):
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":{"code":404,"message":"Requested entity was not found."}}`))
+		case strings.HasSuffix(r.URL.Path, "/values:batchGet"):
+			var ranges []string
+			for _, a1 := range r.URL.Query()["ranges"] {
+				ranges = append(ranges, `{"range":"`+strings.ReplaceAll(a1, `"`, `\"`)+`!A1:C3","values":[["Name","Qty"],["Ann",2,true]]}`)
+			}
+			_, _ = w.Write([]byte(`{"valueRanges":[` + strings.Join(ranges, ",") + `]}`))
+		default:
+			_, _ = w.Write([]byte(`{"sheets":[{"properties":{"title":"Orders"}},{"properties":{"title":"Q1 'Plan'","hidden":true}}]}`))
+		}
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// newTestClient returns a client of a new service account talking to a test server.
+func newTestClient(t *testing.T, tokens *int) *Client {
+	t.Helper()
+	key, keyErr := rsa.GenerateKey(rand.Reader, 2048)
+	if keyErr != nil {
+		t.Fatalf("Error generating test key: %v", keyErr)
+	}
+	server := newTestServer(t, key, tokens)
+	encoded, _ := x509.MarshalPKCS8PrivateKey(key)
+	keyFile, _ := json.Marshal(map[string]string{
+		"type":         "service_account",
+		"client_email": "reader@project.iam.gserviceaccount.com",
+		"private_key":  string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: encoded})),
+		"token_uri":    server.URL + "/token",
+	})
+	account, accountErr := ParseServiceAccount(keyFile)
+	if accountErr != nil {
+		t.Fatalf("ParseServiceAccount() error = %v", accountErr)
+	}
+	client, clientErr := NewClient(account, server.Client())
+	if clientErr != nil {
+		t.Fatalf("NewClient() error = %v", clientErr)
+	}
+	client.Endpoint = server.URL + "/v4"
+	return client
+}
+
+func TestClient(t *testing.T) {
+	var tokens int
+	client := newTestClient(t, &tokens)
+	ctx := context.Background()
+
+	sheets, sheetsErr := client.Sheets(ctx, "sheet-id")
+	if sheetsErr != nil {
+		t.Fatalf("Sheets() error = %v", sheetsErr)
+	}
+	wantSheets := []SheetProperties{{Title: "Orders"}, {Title: "Q1 'Plan'", Hidden: true}}
+	if !reflect.DeepEqual(sheets, wantSheets) {
+		t.Errorf("Sheets() = %v, want %v", sheets, wantSheets)
+	}
+
+	values, valuesErr := client.Values(ctx, "sheet-id", "Orders", QuoteSheet("Q1 'Plan'"))
+	if valuesErr != nil {
+		t.Fatalf("Values() error = %v", valuesErr)
+	}
+	wantValues := [][]string{{"Name", "Qty"}, {"Ann", "2", "true"}}
+	if len(values) != 2 || values[0].Sheet() != "Orders" || values[1].Sheet() != "Q1 'Plan'" || !reflect.DeepEqual(values[0].Values, wantValues) {
+		t.Errorf("Values() = %v, want the values of Orders and Q1 'Plan'", values)
+	}
+	if tokens != 1 {
+		t.Errorf("Client requested %d tokens, want 1", tokens)
+	}
+
+	_, missingErr := client.Values(ctx, "missing", "Orders")
+	if missingErr == nil || !strings.Contains(missingErr.Error(), "Requested entity was not found.") {
+		t.Errorf("Values() error = %v, want the message of the API", missingErr)
+	}
+}
+
+func TestSplitRange(t *testing.T) {
+	tests := []struct {
+		a1        string
+		wantTitle string
+		wantCells string
+	}{
+		{a1: "Orders", wantTitle: "Orders"},
+		{a1: "Orders!A1:F", wantTitle: "Orders", wantCells: "A1:F"},
+		{a1: "'Q1 Orders'!B2", wantTitle: "Q1 Orders", wantCells: "B2"},
+		{a1: "'Bob''s!Sheet'!A:A", wantTitle: "Bob's!Sheet", wantCells: "A:A"},
+		{a1: "'Plan'", wantTitle: "Plan"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a1, func(t *testing.T) {
+			title, cells := SplitRange(tt.a1)
+			if title != tt.wantTitle || cells != tt.wantCells {
+				t.Errorf("SplitRange(%q) = %q, %q, want %q, %q", tt.a1, title, cells, tt.wantTitle, tt.wantCells)
+			}
+		})
+	}
+}
+
+func TestNewClient(t *testing.T) {
+	if _, err := NewClient(ServiceAccount{ClientEmail: "a@b", PrivateKey: "not a key"}, nil); err == nil {
+		t.Errorf("NewClient() accepted a key that is not PEM encoded")
+	}
+	if _, err := ParseServiceAccount([]byte(`{"client_email":"a@b"}`)); err == nil {
+		t.Errorf("ParseServiceAccount() accepted a key file without a private key")
+	}
+}
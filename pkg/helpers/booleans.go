@@ -0,0 +1,60 @@
+package helpers
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// ErrNotABoolean is returned by NormalizeBoolean for values the mapping does not know.
+var ErrNotABoolean = errors.New("not a boolean")
+
+// DefaultBooleans maps the lower case truthy and falsy values found in spreadsheets to booleans.
+// It is the mapping of NormalizeBoolean unless BooleanOptions.Mapping is set; copy it to extend it.
+var DefaultBooleans = map[string]bool{
+	"true": true, "false": false,
+	"t": true, "f": false,
+	"yes": true, "no": false,
+	"y": true, "n": false,
+	"1": true, "0": false,
+	"on": true, "off": false,
+	"x": true, "✓": true, "✔": true,
+	"ja": true, "j": true, "nein": false,
+	"oui": true, "non": false,
+	"sí": true, "si": true,
+	"wahr": true, "falsch": false,
+}
+
+// BooleanOptions configures NormalizeBoolean.
+// Mapping maps the values, in lower case, to booleans, replacing DefaultBooleans when set.
+// BlankFalse reads blank values as false, as in columns ticked with an "X"; they are rejected otherwise, as a
+// blank cell usually means the value is unknown.
+type BooleanOptions struct {
+	Mapping    map[string]bool
+	BlankFalse bool
+}
+
+// NormalizeBoolean returns the boolean value, such as "Yes", "N", "1", "TRUE" or "Nein", stands for, ignoring
+// case and surrounding spaces. The error wraps ErrNotABoolean for values the mapping does not know.
+// Example usage:
+//
+//	NormalizeBoolean(" Ja ", BooleanOptions{})
+//	// Output: true, nil
+//
+//	NormalizeBoolean("", BooleanOptions{BlankFalse: true})
+//	// Output: false, nil
+func NormalizeBoolean(value string, opts BooleanOptions) (bool, error) {
+	key := strings.ToLower(strings.TrimFunc(value, unicode.IsSpace))
+	if len(key) < 1 && opts.BlankFalse {
+		return false, nil
+	}
+	mapping := opts.Mapping
+	if mapping == nil {
+		mapping = DefaultBooleans
+	}
+	if boolean, known := mapping[key]; known {
+		return boolean, nil
+	}
+	return false, fmt.Errorf("'%s' is %w", value, ErrNotABoolean)
+}
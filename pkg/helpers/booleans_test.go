@@ -0,0 +1,46 @@
+package helpers
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalizeBoolean(t *testing.T) {
+	tests := []struct {
+		value   string
+		opts    BooleanOptions
+		want    bool
+		wantErr bool
+	}{
+		{value: "Yes", want: true},
+		{value: "no", want: false},
+		{value: " Y ", want: true},
+		{value: "N", want: false},
+		{value: "1", want: true},
+		{value: "0", want: false},
+		{value: "TRUE", want: true},
+		{value: "False", want: false},
+		{value: "x", want: true},
+		{value: "Ja", want: true},
+		{value: "NEIN", want: false},
+		{value: "", wantErr: true},
+		{value: "", opts: BooleanOptions{BlankFalse: true}, want: false},
+		{value: "maybe", wantErr: true},
+		{value: "aye", opts: BooleanOptions{Mapping: map[string]bool{"aye": true, "nay": false}}, want: true},
+		{value: "yes", opts: BooleanOptions{Mapping: map[string]bool{"aye": true, "nay": false}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := NormalizeBoolean(tt.value, tt.opts)
+			if tt.wantErr {
+				if !errors.Is(err, ErrNotABoolean) {
+					t.Fatalf("NormalizeBoolean(%q) error = %v, want %v", tt.value, err, ErrNotABoolean)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("NormalizeBoolean(%q) = %v, %v, want %v", tt.value, got, err, tt.want)
+			}
+		})
+	}
+}
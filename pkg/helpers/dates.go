@@ -0,0 +1,333 @@
+package helpers
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DateOrder selects how ConvertToISO8601With reads a date whose day and month are both numbers.
+type DateOrder int
+
+const (
+	// MonthFirst reads "01-02-06" as January 2nd, as in the United States. It is the default.
+	MonthFirst DateOrder = iota
+	// DayFirst reads "01-02-06" as February 1st, as in most of Europe.
+	DayFirst
+)
+
+// ParseDateOrder returns the DateOrder called month-first or day-first.
+func ParseDateOrder(name string) (DateOrder, error) {
+	switch name {
+	case "", "month-first", "mdy":
+		return MonthFirst, nil
+	case "day-first", "dmy":
+		return DayFirst, nil
+	}
+	return MonthFirst, fmt.Errorf("unknown date order '%s'", name)
+}
+
+// ParseDateOutput returns the output layout called datetime, date, rfc3339 or iso; any other name is taken
+// as a time layout of its own, such as "02.01.2006".
+func ParseDateOutput(name string) string {
+	switch name {
+	case "", "datetime":
+		return time.DateTime
+	case "date":
+		return time.DateOnly
+	case "rfc3339":
+		return time.RFC3339
+	case "iso":
+		return "2006-01-02T15:04:05"
+	}
+	return name
+}
+
+// The built-in layouts are each date layout followed by each clock layout, so "1/02/06 15:04" is one of them.
+var (
+	monthFirstDates = []string{"01-02-06", "1/02/06", "01/02/06"}
+	dayFirstDates   = []string{"02-01-06", "02/1/06", "02/01/06"}
+	clockLayouts    = []string{"", " 15:04", " 15:04:05"}
+)
+
+// ErrNotADate is returned by ParseToISO8601 for values no layout reads.
+var ErrNotADate = errors.New("not a date")
+
+// ErrAmbiguousDate is returned by ParseToISO8601With in strict mode for dates which read as another date
+// with the day and the month swapped.
+var ErrAmbiguousDate = errors.New("ambiguous date")
+
+// ErrTwoDigitYear is returned by ParseToISO8601With in strict mode for dates with a two-digit year when
+// RejectTwoDigitYears is set.
+var ErrTwoDigitYear = errors.New("two-digit year")
+
+// defaultYearPivot is the pivot of two-digit years of the time package.
+const defaultYearPivot = 69
+
+// builtinLayouts returns the built-in layouts reading dates in order.
+func builtinLayouts(order DateOrder) []string {
+	dates := monthFirstDates
+	if order == DayFirst {
+		dates = dayFirstDates
+	}
+	var layouts []string
+	for _, date := range dates {
+		for _, clock := range clockLayouts {
+			layouts = append(layouts, date+clock)
+		}
+	}
+	return layouts
+}
+
+// registeredLayouts holds the layouts added with RegisterDateLayouts.
+var registeredLayouts struct {
+	sync.RWMutex
+	layouts []string
+}
+
+// RegisterDateLayouts adds layouts, in the notation of the time package, to the ones every conversion to
+// ISO-8601 tries, such as "2006.01.02" or "Jan 2, 2006". They are tried after the layouts of the options
+// and before the built-in ones. It is safe to call while values are converted.
+func RegisterDateLayouts(layouts ...string) {
+	registeredLayouts.Lock()
+	defer registeredLayouts.Unlock()
+	registeredLayouts.layouts = append(registeredLayouts.layouts, layouts...)
+}
+
+// DateOptions configures converting values to ISO-8601.
+// Layouts are tried first, before the ones added with RegisterDateLayouts and the built-in ones, which read
+// month-first dates such as "12-25-20" and "1/02/06 15:04:05" unless Order is DayFirst.
+// Output is the layout of the converted value, time.DateTime by default; time.DateOnly and time.RFC3339
+// are common choices, see ParseDateOutput.
+// Serials reads numbers such as 44927 or 44927.5 as Excel serial dates, the days since the start of the date
+// system of the workbook, 1900 unless Date1904 is set; they are left alone otherwise, being ordinary numbers too.
+// YearPivot decides the century of two-digit years: those below it are in the 2000s and the others in the
+// 1900s, so with a pivot of 50 "69" is 1969 and "49" 2049. It is 69 by default, as in the time package, and
+// 100 puts every two-digit year in the 2000s.
+// Strict rejects the dates which read as another date in the other order, such as "01-02-06" which is
+// January 2nd month-first and February 1st day-first, rather than trusting Order. RejectTwoDigitYears
+// tightens the strict mode further, rejecting every date written with a two-digit year.
+// SourceZone is the time zone of the values which carry no offset of their own, UTC by default, and
+// TargetZone the one they are converted to, time.UTC to normalise them; either makes the default Output
+// time.RFC3339, so the offset is written with the value rather than lost.
+// Fuzzy reads, when no layout does, the dates people write in words such as "25th Dec 2023", "March 3, 2024"
+// or "yesterday", see parseFuzzy; Reference is the time relative dates count from, the current time by default.
+type DateOptions struct {
+	Layouts             []string
+	Output              string
+	Order               DateOrder
+	Serials             bool
+	Date1904            bool
+	YearPivot           int
+	Strict              bool
+	RejectTwoDigitYears bool
+	SourceZone          *time.Location
+	TargetZone          *time.Location
+	Fuzzy               bool
+	Reference           time.Time
+}
+
+// layouts returns the layouts tried in order.
+func (o DateOptions) layouts() []string {
+	registeredLayouts.RLock()
+	layouts := append(append([]string(nil), o.Layouts...), registeredLayouts.layouts...)
+	registeredLayouts.RUnlock()
+	return append(layouts, builtinLayouts(o.Order)...)
+}
+
+// output returns the layout of the converted value.
+func (o DateOptions) output() string {
+	if len(o.Output) < 1 && (o.SourceZone != nil || o.TargetZone != nil) {
+		return time.RFC3339
+	}
+	if len(o.Output) < 1 {
+		return time.DateTime
+	}
+	return o.Output
+}
+
+// ConvertToISO8601 converts a given string value representing a date or time to ISO-8601 format.
+// It supports various date and time formats such as "MM-DD-YY", "MM-DD-YY HH:mm:ss", "1/02/06", etc.
+// The function iterates through the array of supported formats and attempts to parse the value using each format.
+// If a format successfully parses the value, it returns the parsed date in ISO-8601 format using time.DateTime layout.
+// If none of the formats can parse the value, it returns the original value.
+// Use ConvertToISO8601With to read other formats or write another layout.
+//
+// Example usage:
+//
+//	input := "12-25-20 12:34:56"
+//	result := ConvertToISO8601(input)
+//	fmt.Println(result)
+//	// Output: "2020-12-25 12:34:56"
+//
+//	input := "invalid date"
+//	result := ConvertToISO8601(input)
+//	fmt.Println(result)
+//	// Output: "invalid date"
+func ConvertToISO8601(value string) string {
+	return ConvertToISO8601With(value, DateOptions{})
+}
+
+// ConvertToISO8601With converts value like ConvertToISO8601, trying the layouts and writing the layout of opts.
+// In strict mode ambiguous dates are returned unchanged like any other value which is not a date.
+// Example usage:
+//
+//	ConvertToISO8601With("25.12.2020", DateOptions{Layouts: []string{"02.01.2006"}, Output: time.DateOnly})
+//	// Output: "2020-12-25"
+func ConvertToISO8601With(value string, opts DateOptions) string {
+	parsedDate, parseErr := ParseToISO8601With(value, opts)
+	if parseErr != nil {
+		return value
+	}
+	return parsedDate.Format(opts.output())
+}
+
+// ParseToISO8601 reads value with the layouts of ConvertToISO8601 and returns the time it represents, so a value
+// which is not a date can be told apart from one converted to itself. The error wraps ErrNotADate then.
+// Example usage:
+//
+//	parsed, err := ParseToISO8601("12-25-20 12:34:56")
+//	// parsed: 2020-12-25 12:34:56 +0000 UTC, err: nil
+//
+//	_, err = ParseToISO8601("13-45-20")
+//	// err: '13-45-20' is not a date
+func ParseToISO8601(value string) (time.Time, error) {
+	return ParseToISO8601With(value, DateOptions{})
+}
+
+// sourceZone returns the time zone of the values without an offset.
+func (o DateOptions) sourceZone() *time.Location {
+	if o.SourceZone == nil {
+		return time.UTC
+	}
+	return o.SourceZone
+}
+
+// inTargetZone returns parsedDate converted to the target zone, if any.
+func (o DateOptions) inTargetZone(parsedDate time.Time) time.Time {
+	if o.TargetZone == nil {
+		return parsedDate
+	}
+	return parsedDate.In(o.TargetZone)
+}
+
+// twoDigitYear reports whether layout writes the year with two digits.
+func twoDigitYear(layout string) bool {
+	return strings.Contains(strings.ReplaceAll(layout, "2006", ""), "06")
+}
+
+// parse reads value with layout, moving two-digit years to the century of the year pivot. Moving them never
+// lands on a day which does not exist, as 2000 is a leap year and "00" stays in it whatever the pivot.
+func (o DateOptions) parse(layout, value string) (time.Time, error) {
+	parsedDate, parseErr := time.ParseInLocation(layout, value, o.sourceZone())
+	if parseErr != nil || !twoDigitYear(layout) {
+		return parsedDate, parseErr
+	}
+	year := o.fullYear(parsedDate.Year() % 100)
+	return parsedDate.AddDate(year-parsedDate.Year(), 0, 0), nil
+}
+
+// fullYear returns the year a two-digit year stands for with the year pivot.
+func (o DateOptions) fullYear(twoDigits int) int {
+	pivot := o.YearPivot
+	if pivot == 0 {
+		pivot = defaultYearPivot
+	}
+	if twoDigits < pivot {
+		return 2000 + twoDigits
+	}
+	return 1900 + twoDigits
+}
+
+// ParseToISO8601With reads value like ParseToISO8601, trying the layouts of opts. In strict mode the error
+// wraps ErrAmbiguousDate for dates which read as another date in the other order and, if they are rejected,
+// ErrTwoDigitYear for dates with a two-digit year.
+func ParseToISO8601With(value string, opts DateOptions) (time.Time, error) {
+	if opts.YearPivot < 0 || opts.YearPivot > 100 {
+		return time.Time{}, fmt.Errorf("year pivot %d is not between 0 and 100", opts.YearPivot)
+	}
+	if opts.Serials {
+		if serial, parseErr := strconv.ParseFloat(strings.TrimSpace(value), 64); parseErr == nil {
+			parsedDate, serialErr := ExcelSerialToTime(serial, opts.Date1904)
+			if serialErr != nil {
+				return time.Time{}, serialErr
+			}
+			year, month, day := parsedDate.Date()
+			parsedDate = time.Date(year, month, day, parsedDate.Hour(), parsedDate.Minute(), parsedDate.Second(),
+				parsedDate.Nanosecond(), opts.sourceZone())
+			return opts.inTargetZone(parsedDate), nil
+		}
+	}
+	for _, layout := range opts.layouts() {
+		parsedDate, parseErr := opts.parse(layout, value)
+		if parseErr != nil {
+			continue
+		}
+		if opts.Strict && opts.RejectTwoDigitYears && twoDigitYear(layout) {
+			return time.Time{}, fmt.Errorf("'%s' has a %w", value, ErrTwoDigitYear)
+		}
+		if opts.Strict {
+			other := MonthFirst
+			if opts.Order == MonthFirst {
+				other = DayFirst
+			}
+			for _, otherLayout := range builtinLayouts(other) {
+				if swapped, swapErr := opts.parse(otherLayout, value); swapErr == nil && !swapped.Equal(parsedDate) {
+					return time.Time{}, fmt.Errorf("'%s' is an %w, it reads as %s or %s", value, ErrAmbiguousDate,
+						parsedDate.Format(time.DateOnly), swapped.Format(time.DateOnly))
+				}
+			}
+		}
+		return opts.inTargetZone(parsedDate), nil
+	}
+	if opts.Fuzzy {
+		if parsedDate, twoDigits, ok := opts.parseFuzzy(value); ok {
+			if opts.Strict && opts.RejectTwoDigitYears && twoDigits {
+				return time.Time{}, fmt.Errorf("'%s' has a %w", value, ErrTwoDigitYear)
+			}
+			return opts.inTargetZone(parsedDate), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("'%s' is %w", value, ErrNotADate)
+}
+
+// Serial dates of the two date systems of Excel.
+var (
+	excelEpoch1900 = time.Date(1899, time.December, 31, 0, 0, 0, 0, time.UTC)
+	excelEpoch1904 = time.Date(1904, time.January, 1, 0, 0, 0, 0, time.UTC)
+	// excelLeapDay is the serial of February 29th 1900, which Excel counts although 1900 is no leap year.
+	excelLeapDay = 60.0
+	// excelLastSerial is the serial of December 31st 9999 in the 1900 date system, the last date of Excel.
+	excelLastSerial = 2958465.0
+)
+
+// ExcelSerialToTime returns the time of an Excel serial date, the days since the start of the date system with
+// the time of day as fraction: 44927.5 is noon on January 1st 2023. In the 1900 date system serial 1 is
+// January 1st 1900 and, as Excel takes 1900 for a leap year, the serials from 61 on are one day ahead of the
+// calendar; serial 60 is February 29th 1900, a day which does not exist, and gives an error. In the 1904 date
+// system of old Mac workbooks serial 0 is January 1st 1904. The time is rounded to the millisecond.
+func ExcelSerialToTime(serial float64, date1904 bool) (time.Time, error) {
+	last := excelLastSerial
+	if date1904 {
+		last -= 1462
+	}
+	if math.IsNaN(serial) || serial < 0 || serial >= last+1 {
+		return time.Time{}, fmt.Errorf("serial date %v is out of range", serial)
+	}
+	epoch := excelEpoch1900
+	switch {
+	case date1904:
+		epoch = excelEpoch1904
+	case math.Floor(serial) == excelLeapDay:
+		return time.Time{}, fmt.Errorf("serial date %v is February 29th 1900, which does not exist", serial)
+	case serial > excelLeapDay:
+		serial--
+	}
+	days := math.Floor(serial)
+	milliseconds := math.Round((serial - days) * 24 * 60 * 60 * 1000)
+	return epoch.AddDate(0, 0, int(days)).Add(time.Duration(milliseconds) * time.Millisecond), nil
+}
@@ -0,0 +1,240 @@
+package helpers
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConvertToISO8601With(t *testing.T) {
+	tests := []struct {
+		value string
+		opts  DateOptions
+		want  string
+	}{
+		{value: "12-25-20 12:34:56", want: "2020-12-25 12:34:56"},
+		{value: "1/02/06", want: "2006-01-02 00:00:00"},
+		{value: "invalid date", want: "invalid date"},
+		{value: "01-02-06", opts: DateOptions{Order: DayFirst}, want: "2006-02-01 00:00:00"},
+		{value: "25/12/20 08:15", opts: DateOptions{Order: DayFirst}, want: "2020-12-25 08:15:00"},
+		{value: "25/12/20", want: "25/12/20"},
+		{value: "12-25-20", opts: DateOptions{Output: time.DateOnly}, want: "2020-12-25"},
+		{value: "12-25-20 12:34", opts: DateOptions{Output: time.RFC3339}, want: "2020-12-25T12:34:00Z"},
+		{value: "25.12.2020", opts: DateOptions{Layouts: []string{"02.01.2006"}, Output: time.DateOnly}, want: "2020-12-25"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := ConvertToISO8601With(tt.value, tt.opts); got != tt.want {
+				t.Errorf("ConvertToISO8601With(%q, %+v) = %q, want %q", tt.value, tt.opts, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegisterDateLayouts(t *testing.T) {
+	if got := ConvertToISO8601("Dec 25, 2020"); got != "Dec 25, 2020" {
+		t.Fatalf("ConvertToISO8601() = %q before registering the layout", got)
+	}
+	RegisterDateLayouts("Jan 2, 2006")
+	t.Cleanup(func() { registeredLayouts.layouts = nil })
+	if got := ConvertToISO8601("Dec 25, 2020"); got != "2020-12-25 00:00:00" {
+		t.Errorf("ConvertToISO8601() = %q, want the registered layout to be tried", got)
+	}
+}
+
+func TestParseDateOrder(t *testing.T) {
+	if order, err := ParseDateOrder("day-first"); err != nil || order != DayFirst {
+		t.Errorf("ParseDateOrder(day-first) = %v, %v", order, err)
+	}
+	if _, err := ParseDateOrder("ymd"); err == nil || err.Error() != "unknown date order 'ymd'" {
+		t.Errorf("ParseDateOrder(ymd) error = %v", err)
+	}
+	if got := ParseDateOutput("date"); got != time.DateOnly {
+		t.Errorf("ParseDateOutput(date) = %q", got)
+	}
+}
+
+func TestParseToISO8601With(t *testing.T) {
+	tests := []struct {
+		value   string
+		opts    DateOptions
+		want    time.Time
+		wantErr error
+	}{
+		{value: "12-25-20 12:34:56", want: time.Date(2020, 12, 25, 12, 34, 56, 0, time.UTC)},
+		{value: "13-45-20", wantErr: ErrNotADate},
+		{value: "12-25-20", want: time.Date(2020, 12, 25, 0, 0, 0, 0, time.UTC)},
+		{value: "12-25-20", opts: DateOptions{Strict: true}, want: time.Date(2020, 12, 25, 0, 0, 0, 0, time.UTC)},
+		{value: "05-05-20", opts: DateOptions{Strict: true}, want: time.Date(2020, 5, 5, 0, 0, 0, 0, time.UTC)},
+		{value: "01-02-06", opts: DateOptions{Strict: true}, wantErr: ErrAmbiguousDate},
+		{value: "01/02/06 10:00", opts: DateOptions{Strict: true, Order: DayFirst}, wantErr: ErrAmbiguousDate},
+		{value: "25/12/20", opts: DateOptions{Strict: true, Order: DayFirst}, want: time.Date(2020, 12, 25, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseToISO8601With(tt.value, tt.opts)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ParseToISO8601With(%q) error = %v, want %v", tt.value, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || !got.Equal(tt.want) {
+				t.Errorf("ParseToISO8601With(%q) = %v, %v, want %v", tt.value, got, err, tt.want)
+			}
+		})
+	}
+	if _, err := ParseToISO8601("13-45-20"); err == nil || err.Error() != "'13-45-20' is not a date" {
+		t.Errorf("ParseToISO8601(13-45-20) error = %v", err)
+	}
+	if got := ConvertToISO8601With("01-02-06", DateOptions{Strict: true}); got != "01-02-06" {
+		t.Errorf("ConvertToISO8601With() = %q, want the ambiguous date unchanged", got)
+	}
+}
+
+func TestExcelSerialToTime(t *testing.T) {
+	tests := []struct {
+		serial   float64
+		date1904 bool
+		want     time.Time
+		wantErr  string
+	}{
+		{serial: 1, want: time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{serial: 59, want: time.Date(1900, 2, 28, 0, 0, 0, 0, time.UTC)},
+		{serial: 60, wantErr: "February 29th 1900"},
+		{serial: 61, want: time.Date(1900, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{serial: 44927, want: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{serial: 44927.5, want: time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)},
+		{serial: 44927.524259259, want: time.Date(2023, 1, 1, 12, 34, 56, 0, time.UTC)},
+		{serial: 2958465, want: time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC)},
+		{serial: 2958466, wantErr: "out of range"},
+		{serial: -1, wantErr: "out of range"},
+		{serial: 0, date1904: true, want: time.Date(1904, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{serial: 43465, date1904: true, want: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := ExcelSerialToTime(tt.serial, tt.date1904)
+		if len(tt.wantErr) > 0 {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ExcelSerialToTime(%v, %v) error = %v, want %s", tt.serial, tt.date1904, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || !got.Equal(tt.want) {
+			t.Errorf("ExcelSerialToTime(%v, %v) = %v, %v, want %v", tt.serial, tt.date1904, got, err, tt.want)
+		}
+	}
+	if got := ConvertToISO8601With("44927.5", DateOptions{Serials: true}); got != "2023-01-01 12:00:00" {
+		t.Errorf("ConvertToISO8601With(44927.5) = %q", got)
+	}
+	if got := ConvertToISO8601("44927"); got != "44927" {
+		t.Errorf("ConvertToISO8601(44927) = %q, want numbers left alone by default", got)
+	}
+}
+
+func TestYearPivot(t *testing.T) {
+	tests := []struct {
+		value   string
+		opts    DateOptions
+		want    string
+		wantErr error
+	}{
+		{value: "01-02-68", want: "2068-01-02"},
+		{value: "01-02-69", want: "1969-01-02"},
+		{value: "01-02-69", opts: DateOptions{YearPivot: 70}, want: "2069-01-02"},
+		{value: "01-02-49", opts: DateOptions{YearPivot: 50}, want: "2049-01-02"},
+		{value: "01-02-50", opts: DateOptions{YearPivot: 50}, want: "1950-01-02"},
+		{value: "12-31-99", opts: DateOptions{YearPivot: 100}, want: "2099-12-31"},
+		{value: "02-29-00", opts: DateOptions{YearPivot: 1}, want: "2000-02-29"},
+		{value: "12-25-20", opts: DateOptions{RejectTwoDigitYears: true}, want: "2020-12-25"},
+		{value: "12-25-20", opts: DateOptions{Strict: true, RejectTwoDigitYears: true}, wantErr: ErrTwoDigitYear},
+		{value: "12/25/2020", opts: DateOptions{Strict: true, RejectTwoDigitYears: true, Layouts: []string{"01/02/2006"}}, want: "2020-12-25"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseToISO8601With(tt.value, tt.opts)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ParseToISO8601With(%q, %+v) error = %v, want %v", tt.value, tt.opts, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got.Format(time.DateOnly) != tt.want {
+				t.Errorf("ParseToISO8601With(%q, %+v) = %v, %v, want %s", tt.value, tt.opts, got, err, tt.want)
+			}
+		})
+	}
+	if _, err := ParseToISO8601With("12-25-20", DateOptions{YearPivot: 101}); err == nil || err.Error() != "year pivot 101 is not between 0 and 100" {
+		t.Errorf("ParseToISO8601With() error = %v, want the pivot rejected", err)
+	}
+}
+
+func TestDateZones(t *testing.T) {
+	berlin := time.FixedZone("CET", 60*60)
+	newYork := time.FixedZone("EST", -5*60*60)
+	tests := []struct {
+		value string
+		opts  DateOptions
+		want  string
+	}{
+		{value: "12-25-20 12:00", opts: DateOptions{SourceZone: berlin}, want: "2020-12-25T12:00:00+01:00"},
+		{value: "12-25-20 12:00", opts: DateOptions{SourceZone: berlin, TargetZone: time.UTC}, want: "2020-12-25T11:00:00Z"},
+		{value: "12-25-20 12:00", opts: DateOptions{SourceZone: berlin, TargetZone: newYork}, want: "2020-12-25T06:00:00-05:00"},
+		{value: "12-25-20 12:00", opts: DateOptions{TargetZone: newYork, Output: time.DateTime}, want: "2020-12-25 07:00:00"},
+		{value: "44927.5", opts: DateOptions{Serials: true, SourceZone: berlin, TargetZone: time.UTC}, want: "2023-01-01T11:00:00Z"},
+		{
+			value: "2020-12-25T12:00:00-05:00",
+			opts:  DateOptions{Layouts: []string{time.RFC3339}, SourceZone: berlin, TargetZone: time.UTC},
+			want:  "2020-12-25T17:00:00Z",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := ConvertToISO8601With(tt.value, tt.opts); got != tt.want {
+				t.Errorf("ConvertToISO8601With(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFuzzyDates(t *testing.T) {
+	reference := time.Date(2024, 3, 4, 15, 30, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		opts  DateOptions
+		want  string
+	}{
+		{value: "25th Dec 2023", want: "2023-12-25 00:00:00"},
+		{value: "March 3, 2024", want: "2024-03-03 00:00:00"},
+		{value: "Monday, March 4th, 2024", want: "2024-03-04 00:00:00"},
+		{value: "3rd of March 2024", want: "2024-03-03 00:00:00"},
+		{value: "2024 Mar 3", want: "2024-03-03 00:00:00"},
+		{value: "Dec. 25, 2023 9pm", want: "2023-12-25 21:00:00"},
+		{value: "25 December 2023 10:30", want: "2023-12-25 10:30:00"},
+		{value: "25 Dec 23 12:15 am", want: "2023-12-25 00:15:00"},
+		{value: "Sept 1 70", want: "1970-09-01 00:00:00"},
+		{value: "yesterday", want: "2024-03-03 00:00:00"},
+		{value: "Today", want: "2024-03-04 00:00:00"},
+		{value: "tomorrow", want: "2024-03-05 00:00:00"},
+		{value: "31st Feb 2024", want: "31st Feb 2024"},
+		{value: "22st Dec 2023", want: "22st Dec 2023"},
+		{value: "Dec 2023", want: "Dec 2023"},
+		{value: "Meeting on March 3, 2024", want: "Meeting on March 3, 2024"},
+		{value: "March 3, 2024 13pm", want: "March 3, 2024 13pm"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			opts := DateOptions{Fuzzy: true, Reference: reference}
+			if got := ConvertToISO8601With(tt.value, opts); got != tt.want {
+				t.Errorf("ConvertToISO8601With(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+	if got := ConvertToISO8601("March 3, 2024"); got != "March 3, 2024" {
+		t.Errorf("ConvertToISO8601() = %q, want fuzzy dates left alone by default", got)
+	}
+	if _, err := ParseToISO8601With("25 Dec 23", DateOptions{Fuzzy: true, Strict: true, RejectTwoDigitYears: true}); !errors.Is(err, ErrTwoDigitYear) {
+		t.Errorf("ParseToISO8601With() error = %v, want %v", err, ErrTwoDigitYear)
+	}
+}
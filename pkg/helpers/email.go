@@ -0,0 +1,129 @@
+package helpers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"strings"
+)
+
+// ErrInvalidEmail is returned by NormalizeEmail for values which are not a valid email address.
+var ErrInvalidEmail = errors.New("not a valid email address")
+
+// Limits of the parts of an email address, from RFC 5321.
+const (
+	maxLocalPart   = 64
+	maxDomain      = 253
+	maxDomainLabel = 63
+)
+
+// EmailOptions configures CheckEmail.
+// CheckMX looks the mail exchangers of the domain up, so addresses at domains which cannot receive mail are
+// reported. LookupMX replaces the lookup, net.DefaultResolver.LookupMX by default.
+type EmailOptions struct {
+	CheckMX  bool
+	LookupMX func(ctx context.Context, domain string) ([]*net.MX, error)
+}
+
+// EmailCheck is the result of checking a value of a cell holding an email address.
+// Address is the normalized address and DisplayName the name it was written with, if any.
+// Valid reports whether the value is a valid address, and Problem tells what is wrong with it otherwise.
+// MXChecked reports whether the mail exchangers of Domain were looked up.
+type EmailCheck struct {
+	Value       string `json:"value"`
+	Address     string `json:"address"`
+	DisplayName string `json:"displayName,omitempty"`
+	Domain      string `json:"domain"`
+	Valid       bool   `json:"valid"`
+	Problem     string `json:"problem,omitempty"`
+	MXChecked   bool   `json:"mxChecked"`
+}
+
+// NormalizeEmail returns the email address in value, which may be written with a display name or in angle
+// brackets such as "Jane Doe <Jane.Doe@Example.COM>", with its domain in lower case: "Jane.Doe@example.com".
+// The local part keeps its case, as mail servers may tell it apart. The address must have a local part of at most
+// 64 characters and a domain of at most 253 made of letters, digits and hyphens, with a dot; the error wraps
+// ErrInvalidEmail otherwise.
+func NormalizeEmail(value string) (string, error) {
+	address, _, err := parseEmail(value)
+	return address, err
+}
+
+// parseEmail returns the normalized address in value and its display name.
+func parseEmail(value string) (string, string, error) {
+	fail := func(reason string) (string, string, error) {
+		return "", "", fmt.Errorf("'%s' is %w: %s", value, ErrInvalidEmail, reason)
+	}
+	text := strings.TrimSpace(value)
+	if len(text) < 1 {
+		return fail("it is empty")
+	}
+	parsed, parseErr := mail.ParseAddress(text)
+	if parseErr != nil {
+		return fail(strings.TrimPrefix(parseErr.Error(), "mail: "))
+	}
+	at := strings.LastIndex(parsed.Address, "@")
+	local, domain := parsed.Address[:at], strings.ToLower(parsed.Address[at+1:])
+	if len(local) > maxLocalPart {
+		return fail(fmt.Sprintf("the local part is longer than %d characters", maxLocalPart))
+	}
+	if len(domain) > maxDomain {
+		return fail(fmt.Sprintf("the domain is longer than %d characters", maxDomain))
+	}
+	labels := strings.Split(domain, ".")
+	if len(labels) < 2 {
+		return fail("the domain has no dot")
+	}
+	for _, label := range labels {
+		if len(label) < 1 || len(label) > maxDomainLabel || strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-") {
+			return fail(fmt.Sprintf("'%s' is not a valid domain", domain))
+		}
+		for _, char := range label {
+			if !('a' <= char && char <= 'z' || '0' <= char && char <= '9' || char == '-' || char > 127) {
+				return fail(fmt.Sprintf("'%s' is not a valid domain", domain))
+			}
+		}
+	}
+	if strings.Trim(labels[len(labels)-1], "0123456789") == "" {
+		return fail(fmt.Sprintf("'%s' is not a valid domain", domain))
+	}
+	return local + "@" + domain, parsed.Name, nil
+}
+
+// CheckEmail checks the email address in value like NormalizeEmail and, if opts.CheckMX is set, whether its
+// domain has mail exchangers, returning the result rather than an error so it can be reported.
+// Example usage:
+//
+//	check := CheckEmail(ctx, "Jane Doe <jane@Example.com>", EmailOptions{CheckMX: true})
+//	if !check.Valid {
+//		fmt.Println(check.Problem)
+//	}
+func CheckEmail(ctx context.Context, value string, opts EmailOptions) EmailCheck {
+	check := EmailCheck{Value: value}
+	address, name, err := parseEmail(value)
+	if err != nil {
+		check.Problem = strings.TrimPrefix(err.Error(), fmt.Sprintf("'%s' is ", value))
+		return check
+	}
+	check.Address, check.DisplayName, check.Valid = address, name, true
+	check.Domain = address[strings.LastIndex(address, "@")+1:]
+	if !opts.CheckMX {
+		return check
+	}
+	lookup := opts.LookupMX
+	if lookup == nil {
+		lookup = net.DefaultResolver.LookupMX
+	}
+	check.MXChecked = true
+	records, lookupErr := lookup(ctx, check.Domain)
+	var dnsErr *net.DNSError
+	switch {
+	case errors.As(lookupErr, &dnsErr) && dnsErr.IsNotFound, lookupErr == nil && len(records) < 1:
+		check.Valid, check.Problem = false, fmt.Sprintf("the domain '%s' has no mail exchanger", check.Domain)
+	case lookupErr != nil:
+		check.MXChecked, check.Problem = false, fmt.Sprintf("the mail exchangers of '%s' could not be looked up: %v", check.Domain, lookupErr)
+	}
+	return check
+}
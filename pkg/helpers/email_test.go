@@ -0,0 +1,85 @@
+package helpers
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeEmail(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    string
+		wantErr string
+	}{
+		{value: "jane@example.com", want: "jane@example.com"},
+		{value: " Jane.Doe@Example.COM ", want: "Jane.Doe@example.com"},
+		{value: "<jane@example.com>", want: "jane@example.com"},
+		{value: "Jane Doe <jane@EXAMPLE.com>", want: "jane@example.com"},
+		{value: `"Doe, Jane" <jane+news@example.co.uk>`, want: "jane+news@example.co.uk"},
+		{value: "jane.example.com", wantErr: "missing '@'"},
+		{value: "jane@localhost", wantErr: "the domain has no dot"},
+		{value: "jane@-example.com", wantErr: "is not a valid domain"},
+		{value: "jane@example.123", wantErr: "is not a valid domain"},
+		{value: strings.Repeat("a", 65) + "@example.com", wantErr: "longer than 64 characters"},
+		{value: "", wantErr: "it is empty"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := NormalizeEmail(tt.value)
+			if len(tt.wantErr) > 0 {
+				if !errors.Is(err, ErrInvalidEmail) || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("NormalizeEmail(%q) error = %v, want %s", tt.value, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("NormalizeEmail(%q) = %q, %v, want %q", tt.value, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckEmail(t *testing.T) {
+	lookup := func(_ context.Context, domain string) ([]*net.MX, error) {
+		switch domain {
+		case "example.com":
+			return []*net.MX{{Host: "mail.example.com.", Pref: 10}}, nil
+		case "broken.example":
+			return nil, errors.New("server misbehaving")
+		}
+		return nil, &net.DNSError{Err: "no such host", Name: domain, IsNotFound: true}
+	}
+	opts := EmailOptions{CheckMX: true, LookupMX: lookup}
+	tests := []struct {
+		value string
+		want  EmailCheck
+	}{
+		{
+			value: "Jane Doe <jane@Example.com>",
+			want:  EmailCheck{Address: "jane@example.com", DisplayName: "Jane Doe", Domain: "example.com", Valid: true, MXChecked: true},
+		},
+		{
+			value: "jane@nomail.example",
+			want:  EmailCheck{Address: "jane@nomail.example", Domain: "nomail.example", Problem: "the domain 'nomail.example' has no mail exchanger", MXChecked: true},
+		},
+		{
+			value: "jane@broken.example",
+			want:  EmailCheck{Address: "jane@broken.example", Domain: "broken.example", Valid: true, Problem: "the mail exchangers of 'broken.example' could not be looked up: server misbehaving"},
+		},
+		{
+			value: "jane@localhost",
+			want:  EmailCheck{Problem: "not a valid email address: the domain has no dot"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			tt.want.Value = tt.value
+			if got := CheckEmail(context.Background(), tt.value, opts); got != tt.want {
+				t.Errorf("CheckEmail(%q) = %+v, want %+v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
@@ -1,8 +1,11 @@
 package helpers
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"unicode/utf8"
 )
 
 const (
@@ -17,6 +20,8 @@ const (
 	ErrNoFile
 	ErrInvalidFileType
 	ErrParse
+	ErrValidation
+	ErrCancelled
 )
 
 // ErrMsg is a custom error type that represents an error and its corresponding Code.
@@ -52,3 +57,26 @@ func (e *ErrMsg) Exit() {
 		fmt.Printf("An error occured!\nError Code: %d\nDetail: %v\n", e.Code, e.Err)
 	}
 }
+
+// ConversionCode returns the exit code of a failed conversion, telling conversions cancelled or timed out,
+// ErrCancelled, apart from other errors, ErrParse.
+// Example usage:
+//
+//	if parseErr := converters.XlsxToHtml(ctx, writer, filePath, opts); parseErr != nil {
+//		processingErr = ErrMsg{Err: parseErr, Code: ConversionCode(parseErr)}
+//	}
+func ConversionCode(err error) int {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return ErrCancelled
+	}
+	return ErrParse
+}
+
+// SingleRune returns the only character of the value of the named flag, such as a delimiter.
+func SingleRune(name, value string) (rune, error) {
+	if utf8.RuneCountInString(value) != 1 {
+		return 0, fmt.Errorf("%s must be a single character, got '%s'", name, value)
+	}
+	char, _ := utf8.DecodeRuneInString(value)
+	return char, nil
+}
@@ -0,0 +1,55 @@
+package helpers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestConversionCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "Cancelled", err: fmt.Errorf("reading: %w", context.Canceled), want: ErrCancelled},
+		{name: "Timed Out", err: context.DeadlineExceeded, want: ErrCancelled},
+		{name: "Other", err: errors.New("bad cell"), want: ErrParse},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ConversionCode(tt.err); got != tt.want {
+				t.Errorf("ConversionCode(%v) = %d, want %d", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSingleRune(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    rune
+		wantErr string
+	}{
+		{value: ";", want: ';'},
+		{value: "\t", want: '\t'},
+		{value: "é", want: 'é'},
+		{value: "", wantErr: "-delimiter must be a single character, got ''"},
+		{value: ";;", wantErr: "-delimiter must be a single character, got ';;'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := SingleRune("-delimiter", tt.value)
+			if len(tt.wantErr) > 0 {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("SingleRune(%q) error = %v, want %s", tt.value, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("SingleRune(%q) = %q, %v, want %q", tt.value, got, err, tt.want)
+			}
+		})
+	}
+}
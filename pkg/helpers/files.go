@@ -1,8 +1,8 @@
 package helpers
 
 import (
+	"fmt"
 	"io"
-	"log"
 	"os"
 	"path/filepath"
 	"strings"
@@ -62,12 +62,12 @@ func MoveFile(src, dst string) (err error) {
 	// Closes files
 	err = newFile.Close()
 	if err != nil {
-		log.Printf("Failed to close new file: %v", err)
+		Logger().Warn(fmt.Sprintf("Failed to close new file: %v", err), "path", dst, "error", err)
 	}
 
 	err = originalFile.Close()
 	if err != nil {
-		log.Printf("Failed to close original file: %v", err)
+		Logger().Warn(fmt.Sprintf("Failed to close original file: %v", err), "path", src, "error", err)
 	}
 
 	// Remove original file.
@@ -0,0 +1,171 @@
+package helpers
+
+import (
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// monthNames maps the English names of the months and their abbreviations to the months.
+var monthNames = map[string]time.Month{
+	"january": time.January, "jan": time.January,
+	"february": time.February, "feb": time.February,
+	"march": time.March, "mar": time.March,
+	"april": time.April, "apr": time.April,
+	"may":  time.May,
+	"june": time.June, "jun": time.June,
+	"july": time.July, "jul": time.July,
+	"august": time.August, "aug": time.August,
+	"september": time.September, "sep": time.September, "sept": time.September,
+	"october": time.October, "oct": time.October,
+	"november": time.November, "nov": time.November,
+	"december": time.December, "dec": time.December,
+}
+
+// relativeDays maps the words naming a day relative to the reference time to the days they are away from it.
+var relativeDays = map[string]int{"today": 0, "now": 0, "yesterday": -1, "tomorrow": 1}
+
+// ignoredWords are left out of fuzzy dates, such as the weekday of "Monday, March 3, 2024" or the "of" of
+// "3rd of March 2024".
+var ignoredWords = map[string]bool{
+	"monday": true, "mon": true, "tuesday": true, "tue": true, "tues": true, "wednesday": true, "wed": true,
+	"thursday": true, "thu": true, "thurs": true, "friday": true, "fri": true, "saturday": true, "sat": true,
+	"sunday": true, "sun": true, "of": true, "the": true, "at": true,
+}
+
+// parseFuzzy reads a date written in words: a relative day such as "yesterday", or a month name with a day and
+// a year in either order, an ordinal suffix, a weekday and punctuation being allowed, such as "25th Dec 2023",
+// "March 3, 2024" or "Mon 3 Mar 24", optionally followed by a time such as "10:30" or "9pm". Two-digit years
+// go through the year pivot, and twoDigits reports them.
+func (o DateOptions) parseFuzzy(value string) (parsed time.Time, twoDigits bool, ok bool) {
+	text := strings.ToLower(strings.TrimSpace(value))
+	if days, relative := relativeDays[text]; relative {
+		reference := o.Reference
+		if reference.IsZero() {
+			reference = time.Now()
+		}
+		year, month, day := reference.In(o.sourceZone()).Date()
+		return time.Date(year, month, day+days, 0, 0, 0, 0, o.sourceZone()), false, true
+	}
+
+	fields := strings.FieldsFunc(text, func(char rune) bool {
+		return unicode.IsSpace(char) || char == ','
+	})
+	var month time.Month
+	var numbers, suffixes []string
+	clock := ""
+	for index := 0; index < len(fields); index++ {
+		field := strings.TrimSuffix(fields[index], ".")
+		number, suffix := splitNumber(field)
+		switch {
+		case ignoredWords[field]:
+		case monthNames[field] != 0 && month == 0:
+			month = monthNames[field]
+		case len(number) > 0 && (strings.HasPrefix(suffix, ":") || suffix == "am" || suffix == "pm" ||
+			len(suffix) < 1 && index+1 < len(fields) && (fields[index+1] == "am" || fields[index+1] == "pm")):
+			if len(clock) > 0 {
+				return time.Time{}, false, false
+			}
+			clock = field
+			if index+1 < len(fields) && (fields[index+1] == "am" || fields[index+1] == "pm") {
+				clock += fields[index+1]
+				index++
+			}
+		case len(number) > 0 && (len(suffix) < 1 || isOrdinal(number, suffix)):
+			numbers = append(numbers, number)
+			suffixes = append(suffixes, suffix)
+		default:
+			return time.Time{}, false, false
+		}
+	}
+	if month == 0 || len(numbers) != 2 {
+		return time.Time{}, false, false
+	}
+
+	// The day is the number with an ordinal suffix, or else the first one which is not a four-digit year.
+	dayIndex := 0
+	if len(suffixes[1]) > 0 || len(suffixes[0]) < 1 && len(numbers[0]) > 2 {
+		dayIndex = 1
+	}
+	day, _ := strconv.Atoi(numbers[dayIndex])
+	yearText := numbers[1-dayIndex]
+	if len(suffixes[1-dayIndex]) > 0 || len(numbers[dayIndex]) > 2 || len(yearText) != 2 && len(yearText) != 4 {
+		return time.Time{}, false, false
+	}
+	year, _ := strconv.Atoi(yearText)
+	if twoDigits = len(yearText) == 2; twoDigits {
+		year = o.fullYear(year)
+	}
+	hour, minute, second, clockOk := parseClock(clock)
+	if !clockOk {
+		return time.Time{}, false, false
+	}
+	parsed = time.Date(year, month, day, hour, minute, second, 0, o.sourceZone())
+	if parsed.Day() != day || parsed.Month() != month {
+		return time.Time{}, false, false
+	}
+	return parsed, twoDigits, true
+}
+
+// splitNumber splits field into its leading digits and the rest, such as "25" and "th" for "25th".
+func splitNumber(field string) (number, suffix string) {
+	end := strings.IndexFunc(field, func(char rune) bool { return char < '0' || char > '9' })
+	if end < 0 {
+		return field, ""
+	}
+	return field[:end], field[end:]
+}
+
+// isOrdinal reports whether suffix is the English ordinal suffix of number, such as "st" for 1 or "th" for 11.
+func isOrdinal(number, suffix string) bool {
+	value, _ := strconv.Atoi(number)
+	switch {
+	case value%100 >= 11 && value%100 <= 13:
+		return suffix == "th"
+	case value%10 == 1:
+		return suffix == "st"
+	case value%10 == 2:
+		return suffix == "nd"
+	case value%10 == 3:
+		return suffix == "rd"
+	}
+	return suffix == "th"
+}
+
+// parseClock reads a time of day such as "10:30", "10:30:15", "9pm" or "9:15am"; an empty clock is midnight.
+func parseClock(clock string) (hour, minute, second int, ok bool) {
+	if len(clock) < 1 {
+		return 0, 0, 0, true
+	}
+	meridiem := ""
+	if strings.HasSuffix(clock, "am") || strings.HasSuffix(clock, "pm") {
+		clock, meridiem = clock[:len(clock)-2], clock[len(clock)-2:]
+	}
+	parts := strings.Split(clock, ":")
+	if len(parts) > 3 {
+		return 0, 0, 0, false
+	}
+	values := make([]int, 3)
+	for index, part := range parts {
+		number, err := strconv.Atoi(part)
+		if err != nil || index > 0 && len(part) != 2 {
+			return 0, 0, 0, false
+		}
+		values[index] = number
+	}
+	hour, minute, second = values[0], values[1], values[2]
+	if len(meridiem) > 0 {
+		if hour < 1 || hour > 12 {
+			return 0, 0, 0, false
+		}
+		hour %= 12
+		if meridiem == "pm" {
+			hour += 12
+		}
+	}
+	if hour > 23 || minute > 59 || second > 59 {
+		return 0, 0, 0, false
+	}
+	return hour, minute, second, true
+}
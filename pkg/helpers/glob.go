@@ -0,0 +1,82 @@
+package helpers
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// hasMeta reports whether element holds any of the metacharacters of filepath.Match.
+func hasMeta(element string) bool {
+	return strings.ContainsAny(element, `*?[\`)
+}
+
+// GlobBase returns the directory the files matching pattern lie under: the leading elements of pattern holding
+// no metacharacters, its last element excluded, such as in for in/**/*.xlsx and in/2024 for in/2024/*.xlsx.
+func GlobBase(pattern string) string {
+	elements := strings.Split(filepath.Clean(pattern), string(filepath.Separator))
+	end := len(elements) - 1
+	for index, element := range elements[:end] {
+		if hasMeta(element) {
+			end = index
+			break
+		}
+	}
+	base := strings.Join(elements[:end], string(filepath.Separator))
+	if len(base) < 1 {
+		if filepath.IsAbs(pattern) {
+			return string(filepath.Separator)
+		}
+		return "."
+	}
+	return base
+}
+
+// Glob returns the paths matching pattern in lexical order, as filepath.Glob does, except that an element ** of
+// the pattern matches any number of directories, none included, such as in/**/*.xlsx matching in/a.xlsx and
+// in/2024/03/b.xlsx.
+func Glob(pattern string) ([]string, error) {
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return nil, err
+	}
+	if !strings.Contains(pattern, "**") {
+		return filepath.Glob(pattern)
+	}
+	base := GlobBase(pattern)
+	rest, _ := filepath.Rel(base, filepath.Clean(pattern))
+	elements := strings.Split(rest, string(filepath.Separator))
+	var matches []string
+	walkErr := filepath.WalkDir(base, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		relative, _ := filepath.Rel(base, path)
+		if relative != "." && matchElements(elements, strings.Split(relative, string(filepath.Separator))) {
+			matches = append(matches, path)
+		}
+		return nil
+	})
+	if walkErr != nil && !errors.Is(walkErr, fs.ErrNotExist) {
+		return nil, walkErr
+	}
+	slices.Sort(matches)
+	return matches, nil
+}
+
+// matchElements reports whether the elements of a path match the elements of a pattern, ** matching any number
+// of them.
+func matchElements(pattern, path []string) bool {
+	if len(pattern) < 1 {
+		return len(path) < 1
+	}
+	if pattern[0] == "**" {
+		return matchElements(pattern[1:], path) || (len(path) > 0 && matchElements(pattern, path[1:]))
+	}
+	if len(path) < 1 {
+		return false
+	}
+	matched, _ := filepath.Match(pattern[0], path[0])
+	return matched && matchElements(pattern[1:], path[1:])
+}
@@ -0,0 +1,71 @@
+package helpers
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestGlobBase(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    string
+	}{
+		{pattern: "in/**/*.xlsx", want: "in"},
+		{pattern: "in/2024/*.xlsx", want: "in/2024"},
+		{pattern: "in/20*/q1/*.xlsx", want: "in"},
+		{pattern: "*.xlsx", want: "."},
+		{pattern: "in/a.xlsx", want: "in"},
+		{pattern: "/data/**/*.xlsx", want: "/data"},
+		{pattern: "/*.xlsx", want: "/"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			if got := GlobBase(filepath.FromSlash(tt.pattern)); got != filepath.FromSlash(tt.want) {
+				t.Errorf("GlobBase(%q) = %q, want %q", tt.pattern, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGlob(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a.xlsx", "b.csv", "2024/c.xlsx", "2024/03/d.xlsx", "2025/e.xlsx"} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tests := []struct {
+		pattern string
+		want    []string
+	}{
+		{pattern: "**/*.xlsx", want: []string{"2024/03/d.xlsx", "2024/c.xlsx", "2025/e.xlsx", "a.xlsx"}},
+		{pattern: "2024/**/*.xlsx", want: []string{"2024/03/d.xlsx", "2024/c.xlsx"}},
+		{pattern: "**/03/*", want: []string{"2024/03/d.xlsx"}},
+		{pattern: "*.xlsx", want: []string{"a.xlsx"}},
+		{pattern: "missing/**/*.xlsx", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			got, err := Glob(filepath.Join(dir, filepath.FromSlash(tt.pattern)))
+			if err != nil {
+				t.Fatalf("Glob(%q) error = %v", tt.pattern, err)
+			}
+			var want []string
+			for _, name := range tt.want {
+				want = append(want, filepath.Join(dir, filepath.FromSlash(name)))
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Glob(%q) = %v, want %v", tt.pattern, got, want)
+			}
+		})
+	}
+	if _, err := Glob("[a-"); err == nil {
+		t.Error("Glob accepted a malformed pattern")
+	}
+}
@@ -0,0 +1,99 @@
+package helpers
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// CaseStyle selects the naming convention NormalizeHeaderCase rewrites headers into.
+type CaseStyle int
+
+const (
+	// SnakeCase writes "Unit Price" as "unit_price". It is the default.
+	SnakeCase CaseStyle = iota
+	// CamelCase writes "Unit Price" as "unitPrice".
+	CamelCase
+	// PascalCase writes "Unit Price" as "UnitPrice".
+	PascalCase
+	// KebabCase writes "Unit Price" as "unit-price".
+	KebabCase
+)
+
+// ParseCaseStyle returns the CaseStyle called snake, camel, pascal or kebab.
+func ParseCaseStyle(name string) (CaseStyle, error) {
+	switch name {
+	case "", "snake":
+		return SnakeCase, nil
+	case "camel":
+		return CamelCase, nil
+	case "pascal":
+		return PascalCase, nil
+	case "kebab":
+		return KebabCase, nil
+	}
+	return SnakeCase, fmt.Errorf("unknown case style '%s'", name)
+}
+
+// NormalizeHeaderCase rewrites every header into the given naming convention and returns the results in a new
+// slice, so the output matches models generated from it. Headers are cut into words at every character other
+// than a letter or a digit and where a lower case letter is followed by an upper case one, an acronym ending
+// where its last capital starts the next word: "Order ID", "order_id" and "orderId" all give the words
+// "order" and "id". Headers which then are equal are left for RenameDuplicatesWith to tell apart.
+// Example usage:
+//
+//	NormalizeHeaderCase([]string{"Unit Price", "HTTPStatus", "Q1 total"}, CamelCase)
+//	// Output: []string{"unitPrice", "httpStatus", "q1Total"}
+func NormalizeHeaderCase(headers []string, style CaseStyle) []string {
+	normalized := make([]string, len(headers))
+	for i, header := range headers {
+		words := headerWords(header)
+		for index, word := range words {
+			word = strings.ToLower(word)
+			if style == PascalCase || style == CamelCase && index > 0 {
+				runes := []rune(word)
+				runes[0] = unicode.ToUpper(runes[0])
+				word = string(runes)
+			}
+			words[index] = word
+		}
+		switch style {
+		case CamelCase, PascalCase:
+			normalized[i] = strings.Join(words, "")
+		case KebabCase:
+			normalized[i] = strings.Join(words, "-")
+		default:
+			normalized[i] = strings.Join(words, "_")
+		}
+	}
+	return normalized
+}
+
+// headerWords cuts header into its words, see NormalizeHeaderCase.
+func headerWords(header string) []string {
+	var words []string
+	var word []rune
+	runes := []rune(header)
+	for index, char := range runes {
+		if !unicode.IsLetter(char) && !unicode.IsDigit(char) {
+			if len(word) > 0 {
+				words = append(words, string(word))
+				word = nil
+			}
+			continue
+		}
+		if len(word) > 0 && unicode.IsUpper(char) {
+			previous := word[len(word)-1]
+			nextLower := index+1 < len(runes) && unicode.IsLower(runes[index+1])
+			if unicode.IsLower(previous) || unicode.IsDigit(previous) || unicode.IsUpper(previous) && nextLower {
+				words = append(words, string(word))
+				word = nil
+			}
+		}
+		word = append(word, char)
+	}
+	if len(word) > 0 {
+		words = append(words, string(word))
+	}
+	return words
+}
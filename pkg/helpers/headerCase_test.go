@@ -0,0 +1,30 @@
+package helpers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeHeaderCase(t *testing.T) {
+	headers := []string{"Unit Price", "HTTPStatus", "Q1 total", "order_id", "orderId", "  Ship-To  Address ", "Größe", "**"}
+	tests := []struct {
+		style CaseStyle
+		want  []string
+	}{
+		{style: SnakeCase, want: []string{"unit_price", "http_status", "q1_total", "order_id", "order_id", "ship_to_address", "größe", ""}},
+		{style: CamelCase, want: []string{"unitPrice", "httpStatus", "q1Total", "orderId", "orderId", "shipToAddress", "größe", ""}},
+		{style: PascalCase, want: []string{"UnitPrice", "HttpStatus", "Q1Total", "OrderId", "OrderId", "ShipToAddress", "Größe", ""}},
+		{style: KebabCase, want: []string{"unit-price", "http-status", "q1-total", "order-id", "order-id", "ship-to-address", "größe", ""}},
+	}
+	for _, tt := range tests {
+		if got := NormalizeHeaderCase(headers, tt.style); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("NormalizeHeaderCase(%v) = %v, want %v", tt.style, got, tt.want)
+		}
+	}
+	if headers[0] != "Unit Price" {
+		t.Errorf("NormalizeHeaderCase() modified its input: %v", headers)
+	}
+	if _, err := ParseCaseStyle("title"); err == nil || err.Error() != "unknown case style 'title'" {
+		t.Errorf("ParseCaseStyle(title) error = %v", err)
+	}
+}
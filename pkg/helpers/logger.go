@@ -0,0 +1,32 @@
+package helpers
+
+import (
+	"io"
+	"log/slog"
+	"sync/atomic"
+)
+
+// discardLogger drops every message, it is used once the messages of the helpers are silenced.
+var discardLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+// packageLogger holds the logger set with SetLogger, nil until then.
+var packageLogger atomic.Pointer[slog.Logger]
+
+// SetLogger routes the messages of the helpers, such as the duplicate headers reported by RenameDuplicates
+// or the files MoveFile fails to close, to logger. A nil logger silences them.
+// Until SetLogger is called they go to slog.Default, which writes through the standard log package.
+// Functions taking options accept a logger of their own, which takes precedence.
+func SetLogger(logger *slog.Logger) {
+	if logger == nil {
+		logger = discardLogger
+	}
+	packageLogger.Store(logger)
+}
+
+// Logger returns the logger the helpers write their messages to, see SetLogger.
+func Logger() *slog.Logger {
+	if logger := packageLogger.Load(); logger != nil {
+		return logger
+	}
+	return slog.Default()
+}
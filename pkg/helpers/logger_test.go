@@ -0,0 +1,30 @@
+package helpers
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestLoggerInjection(t *testing.T) {
+	defer packageLogger.Store(nil)
+
+	var packageOutput, callOutput bytes.Buffer
+	SetLogger(slog.New(slog.NewTextHandler(&packageOutput, nil)))
+	RenameDuplicates([]string{"Name", "Name"}, true)
+	if !strings.Contains(packageOutput.String(), "header=Name occurrences=2") {
+		t.Errorf("package logger output = %q, want the duplicate header", packageOutput.String())
+	}
+
+	packageOutput.Reset()
+	RenameDuplicatesWith([]string{"Id", "Id"}, RenameOptions{PrintOffending: true, Logger: slog.New(slog.NewTextHandler(&callOutput, nil))})
+	if packageOutput.Len() > 0 || !strings.Contains(callOutput.String(), "header=Id") {
+		t.Errorf("package logger output = %q, call logger output = %q, want only the call logger used", packageOutput.String(), callOutput.String())
+	}
+
+	SetLogger(nil)
+	if Logger() != discardLogger {
+		t.Errorf("SetLogger(nil) did not silence the helpers")
+	}
+}
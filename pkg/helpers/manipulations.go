@@ -2,9 +2,9 @@ package helpers
 
 import (
 	"fmt"
-	"log"
+	"log/slog"
+	"strconv"
 	"strings"
-	"time"
 )
 
 // RenameDuplicates takes an input slice of strings and renames any duplicate headers
@@ -14,8 +14,8 @@ import (
 // the count of each header occurrence. If a header occurs more than once, its count is
 // incremented and the header is renamed by appending "_<count>" to it.
 //
-// After the renaming is done, the counts map is iterated to print a message for each header
-// that had duplicates.
+// After the renaming is done, a message is logged for each header that had duplicates when printOffending
+// is set, to the logger of the package, see SetLogger.
 //
 // Example usage:
 //
@@ -32,94 +32,350 @@ import (
 //	The modifiedHeaders slice will be:
 //	[]string{"Name", "Age", "Name_2", "City", "Age_2"}
 func RenameDuplicates(input []string, printOffending bool) []string {
+	return RenameDuplicatesWith(input, RenameOptions{PrintOffending: printOffending})
+}
+
+// Suffix formats accepted by RenameOptions.Format.
+const (
+	// UnderscoreSuffix renames the second "Name" to "Name_2". It is the default.
+	UnderscoreSuffix = "_%d"
+	// ParenthesesSuffix renames the second "Name" to "Name (2)", as spreadsheet applications do.
+	ParenthesesSuffix = " (%d)"
+	// PaddedSuffix renames the second "Name" to "Name_02", so the renamed headers sort in order.
+	PaddedSuffix = "_%02d"
+)
+
+// RenameOptions configures how RenameDuplicatesWith renames duplicate headers.
+// Format is the fmt format of the suffix appended to a duplicate, receiving its number, UnderscoreSuffix by default;
+// a format without a verb has "%d" appended. Start is the number of the first duplicate, 2 by default, so the
+// second "Name" becomes "Name_2"; the first occurrence of a header always keeps its name.
+// IgnoreCase treats headers differing only in case, such as "Name" and "name", as duplicates.
+// PrintOffending logs every header which was present more than once to Logger, or to the logger of the
+// package when it is nil, see SetLogger.
+type RenameOptions struct {
+	Format         string
+	Start          int
+	IgnoreCase     bool
+	PrintOffending bool
+	Logger         *slog.Logger
+}
+
+// RenameDuplicatesWith renames the duplicate headers of the input slice as configured by opts,
+// the way RenameDuplicates does by default, and returns the modified input slice.
+//
+// Example usage:
+//
+//	headers := []string{"Name", "name", "City"}
+//	RenameDuplicatesWith(headers, RenameOptions{Format: ParenthesesSuffix, Start: 1, IgnoreCase: true})
+//
+//	The headers slice will be:
+//	[]string{"Name", "name (1)", "City"}
+func RenameDuplicatesWith(input []string, opts RenameOptions) []string {
+	renamed, _, duplicates := renameDuplicates(input, opts)
+	copy(input, renamed)
+	if opts.PrintOffending {
+		logger := opts.Logger
+		if logger == nil {
+			logger = Logger()
+		}
+		for _, duplicate := range duplicates {
+			logger.Info(fmt.Sprintf("Header '%s' was present %d times", duplicate.Original, duplicate.Occurrences),
+				"header", duplicate.Original, "occurrences", duplicate.Occurrences)
+		}
+	}
+	return input
+}
+
+// Rename describes a header renamed by RenameDuplicatesReport: its name before and after the rename,
+// its zero-based index in the input and the number of times its original name is present in the input.
+type Rename struct {
+	Original    string
+	Renamed     string
+	Index       int
+	Occurrences int
+}
+
+// RenameDuplicatesReport renames the duplicate headers as RenameDuplicatesWith does, but leaves the input slice
+// untouched: it returns the renamed headers in a new slice together with a Rename per renamed header,
+// in input order, so callers can log or keep what changed. opts.PrintOffending is ignored.
+//
+// Example usage:
+//
+//	headers, renames := RenameDuplicatesReport([]string{"Name", "Name"}, RenameOptions{})
+//
+//	headers is []string{"Name", "Name_2"} and renames is
+//	[]Rename{{Original: "Name", Renamed: "Name_2", Index: 1, Occurrences: 2}}
+func RenameDuplicatesReport(input []string, opts RenameOptions) ([]string, []Rename) {
+	renamed, renames, _ := renameDuplicates(input, opts)
+	return renamed, renames
+}
+
+// renameDuplicates returns a renamed copy of the input, a Rename per renamed header and, for every header
+// present more than once, a Rename holding its first occurrence, in the order the headers are first met.
+func renameDuplicates(input []string, opts RenameOptions) ([]string, []Rename, []Rename) {
+	format := opts.Format
+	if len(format) < 1 {
+		format = UnderscoreSuffix
+	} else if !strings.Contains(format, "%") {
+		format += "%d"
+	}
+	start := opts.Start
+	if start < 1 {
+		start = 2
+	}
+	renamed := make([]string, len(input))
 	counts := make(map[string]int)
+	firsts := make(map[string]int)
+	var order []string
+	var renames []Rename
 
 	for i, header := range input {
-		counts[header]++
-		if counts[header] > 1 {
-			input[i] = fmt.Sprintf("%s_%d", header, counts[header])
+		key := header
+		if opts.IgnoreCase {
+			key = strings.ToLower(header)
+		}
+		if counts[key] < 1 {
+			firsts[key] = i
+			order = append(order, key)
+		}
+		counts[key]++
+		renamed[i] = header
+		if counts[key] > 1 {
+			renamed[i] = header + fmt.Sprintf(format, start+counts[key]-2)
+			renames = append(renames, Rename{Original: header, Renamed: renamed[i], Index: i})
 		}
 	}
-	if printOffending {
-		for header, count := range counts {
-			if count > 1 {
-				log.Printf("Header '%s' was present %d times\n", header, count)
-			}
+	for i := range renames {
+		key := renames[i].Original
+		if opts.IgnoreCase {
+			key = strings.ToLower(key)
 		}
+		renames[i].Occurrences = counts[key]
 	}
-	return input
+	var duplicates []Rename
+	for _, key := range order {
+		if count := counts[key]; count > 1 {
+			first := firsts[key]
+			duplicates = append(duplicates, Rename{Original: input[first], Renamed: input[first], Index: first, Occurrences: count})
+		}
+	}
+	return renamed, renames, duplicates
 }
 
-// FixXMLTags takes a string `tag` as input and removes any invalid XML characters from it.
-// It returns the modified string with the cleaned tag.
-// The function first initializes a slice `invalidXmlChars` with a list of invalid XML characters.
-// These characters are identified as parentheses, angle brackets, slashes, backslashes,
-// question marks, exclamation marks, double and single quotation marks, at signs, hash signs, dollar signs,
-// percent signs, caret symbols, ampersands, asterisks, plus signs, equal signs, tilde, backticks,
-// vertical bars, square brackets, curly braces, semicolons, colons, commas, and periods.
-// The function then iterates over each character in the `invalidXmlChars` slice.
-// For each character, it removes all occurrences of that character in the `tag` string
-// using the `ReplaceAll` function from the `strings` package,
-// and assigns the result back to the `cleanTag` variable.
-// Finally, the function returns the `cleanTag` string, which contains the modified tag
-// with all invalid XML characters removed.
+// FixXMLTags takes a string `tag` as input and removes the characters an XML element name cannot hold.
+// It returns the modified string with the cleaned tag, a valid XML 1.0 Name without a namespace prefix, or an
+// empty string when tag holds no character a name can hold, such as "(%)"; SanitizeHeaders names those columns.
+// A Name starts with a letter or an underscore and goes on with letters, digits, hyphens, periods,
+// underscores and combining marks, any Unicode letter included, as the NameStartChar and NameChar productions
+// of the XML 1.0 recommendation define them. Every other character is removed, as are the characters the name
+// starts with which may only follow the first one, such as digits. Colons, which separate namespace prefixes,
+// are removed too. Spaces are escaped as "_x0020_", the way Excel and SharePoint encode them.
 // Example usage:
 //
 //	tag := "<Hello World!>"
 //	cleanTag := FixXMLTags(tag)
 //	fmt.Println(cleanTag)
-//	// Output: "Hello World"
+//	// Output: "Hello_x0020_World"
 func FixXMLTags(tag string) string {
-	invalidXmlChars := []rune{
-		'(', ')', '<', '>', '/', '\\',
-		'?', '!', '"', '\'', '@', '#', '$',
-		'%', '^', '&', '*', '+', '=', '~',
-		'`', '|', '[', ']', '{', '}', ';',
-		':', ',', '.',
-	}
-	// Replace invalid characters
-	cleanTag := tag
-	for _, char := range invalidXmlChars {
-		cleanTag = strings.ReplaceAll(cleanTag, string(char), "")
-	}
-	cleanTag = strings.ReplaceAll(cleanTag, " ", "_x0020_")
-	return cleanTag
+	return FixXMLTagsWith(tag, TagOptions{})
 }
 
-// ConvertToISO8601 converts a given string value representing a date or time to ISO-8601 format.
-// It supports various date and time formats such as "MM-DD-YY", "MM-DD-YY HH:mm:ss", "1/02/06", etc.
-// The function iterates through the array of supported formats and attempts to parse the value using each format.
-// If a format successfully parses the value, it returns the parsed date in ISO-8601 format using time.DateTime layout.
-// If none of the formats can parse the value, it returns the original value.
+// TagReplacement selects what FixXMLTagsWith does with the characters an XML element name cannot hold.
+type TagReplacement int
+
+const (
+	// StripInvalid removes the characters and escapes spaces as "_x0020_". It is the default.
+	StripInvalid TagReplacement = iota
+	// ReplaceInvalid replaces every character, spaces included, with TagOptions.Placeholder.
+	ReplaceInvalid
+	// EscapeInvalid escapes every character, spaces included, as "_xHHHH_" with its hexadecimal code point,
+	// the convention of Excel and SharePoint, so distinct headers keep distinct names.
+	EscapeInvalid
+)
+
+// ParseTagReplacement returns the TagReplacement called strip, replace or escape.
+func ParseTagReplacement(name string) (TagReplacement, error) {
+	switch name {
+	case "", "strip":
+		return StripInvalid, nil
+	case "replace":
+		return ReplaceInvalid, nil
+	case "escape":
+		return EscapeInvalid, nil
+	}
+	return StripInvalid, fmt.Errorf("unknown tag replacement '%s'", name)
+}
+
+// defaultPlaceholder replaces invalid characters under ReplaceInvalid when TagOptions.Placeholder is empty.
+const defaultPlaceholder = "_"
+
+// TagOptions configures how FixXMLTagsWith cleans XML element names.
+// Replacement selects what becomes of the characters a name cannot hold, colons included;
+// Placeholder is what ReplaceInvalid replaces them with, "_" by default, and should be valid in a name itself.
+// The characters a name cannot start with, such as the digits of "2024", are removed by StripInvalid,
+// kept behind the placeholder by ReplaceInvalid and escaped by EscapeInvalid, unless PrefixInvalidStart is set:
+// they are then kept and the name is prefixed with an underscore, giving "_2024".
+// Transliterate spells accented and special Latin letters in ASCII before cleaning, see Transliterate,
+// so "Größe" becomes "Grosse" rather than keeping letters consumers may not expect.
+type TagOptions struct {
+	PrefixInvalidStart bool
+	Transliterate      bool
+	Replacement        TagReplacement
+	Placeholder        string
+}
+
+// FixXMLTagsWith cleans tag into a valid XML element name as FixXMLTags does, configured by opts.
+//
+// Example usage:
+//
+//	FixXMLTagsWith("2024 Sales", TagOptions{PrefixInvalidStart: true})
+//	// Output: "_2024_x0020_Sales"
+//	FixXMLTagsWith("Amount ($)", TagOptions{Replacement: ReplaceInvalid})
+//	// Output: "Amount____"
+//	FixXMLTagsWith("Amount ($)", TagOptions{Replacement: EscapeInvalid})
+//	// Output: "Amount_x0020__x0028__x0024__x0029_"
+func FixXMLTagsWith(tag string, opts TagOptions) string {
+	placeholder := opts.Placeholder
+	if len(placeholder) < 1 {
+		placeholder = defaultPlaceholder
+	}
+	if opts.Transliterate {
+		tag = Transliterate(tag)
+	}
+	var cleanTag strings.Builder
+	invalid := func(char rune) {
+		switch opts.Replacement {
+		case ReplaceInvalid:
+			cleanTag.WriteString(placeholder)
+		case EscapeInvalid:
+			fmt.Fprintf(&cleanTag, "_x%04X_", char)
+		default:
+			if char == ' ' {
+				cleanTag.WriteString("_x0020_")
+			}
+		}
+	}
+	for _, char := range tag {
+		switch {
+		case char == ':' || !isNameChar(char):
+			invalid(char)
+		case cleanTag.Len() > 0 || isNameStartChar(char):
+			cleanTag.WriteRune(char)
+		case opts.PrefixInvalidStart:
+			cleanTag.WriteByte('_')
+			cleanTag.WriteRune(char)
+		case opts.Replacement == StripInvalid:
+		default:
+			invalid(char)
+			if opts.Replacement == ReplaceInvalid {
+				cleanTag.WriteRune(char)
+			}
+		}
+	}
+	return cleanTag.String()
+}
+
+// SanitizeOptions configures SanitizeHeaders: Tag how every header is cleaned into an XML element name and
+// Rename how the names which are then equal are told apart; Rename.PrintOffending is ignored.
+type SanitizeOptions struct {
+	Tag    TagOptions
+	Rename RenameOptions
+}
+
+// SanitizeHeaders cleans every header into a valid XML element name with FixXMLTagsWith and then renames the
+// names which are equal, the distinct headers "Amount ($)" and "Amount (%)" both becoming "Amount_x0020_"
+// when stripped, so every column keeps an element of its own. The first header keeps its cleaned name and the
+// following ones get the suffix of Rename, numbered in input order and skipping the names already taken by other
+// headers, so the result only depends on the headers. It returns the names in a new slice together with a Rename
+// per renamed header, holding its header as given, its final name and the number of headers sharing its cleaned
+// name. Headers which clean to nothing, such as blank ones or "(%)", are called Column<n>, n being their one-based
+// position, as blank headers are elsewhere.
 //
 // Example usage:
 //
-//	input := "12-25-20 12:34:56"
-//	result := convertToISO8601(input)
-//	fmt.Println(result)
-//	// Output: "2020-12-25T12:34:56"
-//
-//	input := "invalid date"
-//	result := convertToISO8601(input)
-//	fmt.Println(result)
-//	// Output: "invalid date"
-func ConvertToISO8601(value string) string {
-	formats := [9]string{
-		"01-02-06",
-		"01-02-06 15:04",
-		"01-02-06 15:04:05",
-		"1/02/06",
-		"1/02/06 15:04",
-		"1/02/06 15:04:05",
-		"01/02/06",
-		"01/02/06 15:04",
-		"01/02/06 15:04:05",
-	}
-	for _, format := range formats {
-		parsedDate, parseErr := time.Parse(format, value)
-		if parseErr == nil {
-			return parsedDate.Format(time.DateTime)
+//	names, renames := SanitizeHeaders([]string{"Amount ($)", "Amount (%)", "Amount_x0020__2"}, SanitizeOptions{})
+//
+//	names is []string{"Amount_x0020_", "Amount_x0020__3", "Amount_x0020__2"}
+func SanitizeHeaders(headers []string, opts SanitizeOptions) ([]string, []Rename) {
+	format := opts.Rename.Format
+	if len(format) < 1 {
+		format = UnderscoreSuffix
+	} else if !strings.Contains(format, "%") {
+		format += "%d"
+	}
+	start := opts.Rename.Start
+	if start < 1 {
+		start = 2
+	}
+	key := func(name string) string {
+		if opts.Rename.IgnoreCase {
+			return strings.ToLower(name)
+		}
+		return name
+	}
+	names := make([]string, len(headers))
+	taken := make(map[string]bool, len(headers))
+	counts := make(map[string]int, len(headers))
+	for i, header := range headers {
+		if names[i] = FixXMLTagsWith(header, opts.Tag); len(names[i]) < 1 || len(strings.TrimSpace(header)) < 1 {
+			names[i] = "Column" + strconv.Itoa(i+1)
+		}
+		taken[key(names[i])] = true
+		counts[key(names[i])]++
+	}
+
+	var renames []Rename
+	next := make(map[string]int)
+	seen := make(map[string]bool, len(headers))
+	for i, name := range names {
+		if !seen[key(name)] {
+			seen[key(name)] = true
+			continue
 		}
+		if next[key(name)] < start {
+			next[key(name)] = start
+		}
+		renamed := name
+		for number := next[key(name)]; ; number++ {
+			renamed = FixXMLTagsWith(name+fmt.Sprintf(format, number), opts.Tag)
+			if !taken[key(renamed)] {
+				next[key(name)] = number + 1
+				break
+			}
+		}
+		taken[key(renamed)] = true
+		renames = append(renames, Rename{Original: headers[i], Renamed: renamed, Index: i, Occurrences: counts[key(name)]})
+		names[i] = renamed
+	}
+	return names, renames
+}
+
+// isNameStartChar reports whether char may start an XML name, following the NameStartChar production.
+func isNameStartChar(char rune) bool {
+	switch {
+	case char == ':' || char == '_' || 'A' <= char && char <= 'Z' || 'a' <= char && char <= 'z':
+		return true
+	case 0xC0 <= char && char <= 0xD6, 0xD8 <= char && char <= 0xF6, 0xF8 <= char && char <= 0x2FF:
+		return true
+	case 0x370 <= char && char <= 0x37D, 0x37F <= char && char <= 0x1FFF, 0x200C <= char && char <= 0x200D:
+		return true
+	case 0x2070 <= char && char <= 0x218F, 0x2C00 <= char && char <= 0x2FEF, 0x3001 <= char && char <= 0xD7FF:
+		return true
+	case 0xF900 <= char && char <= 0xFDCF, 0xFDF0 <= char && char <= 0xFFFD, 0x10000 <= char && char <= 0xEFFFF:
+		return true
+	}
+	return false
+}
+
+// isNameChar reports whether char may appear in an XML name, following the NameChar production.
+func isNameChar(char rune) bool {
+	switch {
+	case isNameStartChar(char):
+		return true
+	case char == '-' || char == '.' || '0' <= char && char <= '9' || char == 0xB7:
+		return true
+	case 0x300 <= char && char <= 0x36F, 0x203F <= char && char <= 0x2040:
+		return true
 	}
-	return value
+	return false
 }
@@ -0,0 +1,207 @@
+package helpers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRenameDuplicatesWith(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []string
+		opts  RenameOptions
+		want  []string
+	}{
+		{
+			name:  "Default",
+			input: []string{"Name", "Age", "Name", "City", "Age", "Name"},
+			want:  []string{"Name", "Age", "Name_2", "City", "Age_2", "Name_3"},
+		},
+		{
+			name:  "Parentheses",
+			input: []string{"Name", "Name"},
+			opts:  RenameOptions{Format: ParenthesesSuffix},
+			want:  []string{"Name", "Name (2)"},
+		},
+		{
+			name:  "Padded From One",
+			input: []string{"Name", "Name", "Name"},
+			opts:  RenameOptions{Format: PaddedSuffix, Start: 1},
+			want:  []string{"Name", "Name_01", "Name_02"},
+		},
+		{
+			name:  "Separator Only",
+			input: []string{"Name", "Name"},
+			opts:  RenameOptions{Format: "-"},
+			want:  []string{"Name", "Name-2"},
+		},
+		{
+			name:  "Ignore Case",
+			input: []string{"Name", "name", "NAME", "City"},
+			opts:  RenameOptions{IgnoreCase: true},
+			want:  []string{"Name", "name_2", "NAME_3", "City"},
+		},
+		{
+			name:  "Case Sensitive",
+			input: []string{"Name", "name"},
+			want:  []string{"Name", "name"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RenameDuplicatesWith(tt.input, tt.opts); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("RenameDuplicatesWith() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenameDuplicatesReport(t *testing.T) {
+	input := []string{"Name", "name", "City", "NAME", "City"}
+	renamed, renames := RenameDuplicatesReport(input, RenameOptions{IgnoreCase: true})
+	if want := []string{"Name", "name_2", "City", "NAME_3", "City_2"}; !reflect.DeepEqual(renamed, want) {
+		t.Errorf("RenameDuplicatesReport() headers = %v, want %v", renamed, want)
+	}
+	wantRenames := []Rename{
+		{Original: "name", Renamed: "name_2", Index: 1, Occurrences: 3},
+		{Original: "NAME", Renamed: "NAME_3", Index: 3, Occurrences: 3},
+		{Original: "City", Renamed: "City_2", Index: 4, Occurrences: 2},
+	}
+	if !reflect.DeepEqual(renames, wantRenames) {
+		t.Errorf("RenameDuplicatesReport() renames = %+v, want %+v", renames, wantRenames)
+	}
+	if want := []string{"Name", "name", "City", "NAME", "City"}; !reflect.DeepEqual(input, want) {
+		t.Errorf("RenameDuplicatesReport() modified its input: %v", input)
+	}
+	if _, renames := RenameDuplicatesReport([]string{"Id", "Name"}, RenameOptions{}); renames != nil {
+		t.Errorf("RenameDuplicatesReport() renames = %+v, want none", renames)
+	}
+}
+
+func TestFixXMLTagsWith(t *testing.T) {
+	tests := []struct {
+		tag  string
+		opts TagOptions
+		want string
+	}{
+		{tag: "<Hello World!>", want: "Hello_x0020_World"},
+		{tag: "Amount ($)", want: "Amount_x0020_"},
+		{tag: "No.", want: "No."},
+		{tag: "e-mail", want: "e-mail"},
+		{tag: "ns:Name", want: "nsName"},
+		{tag: "Größe", want: "Größe"},
+		{tag: "名前", want: "名前"},
+		{tag: "2024", want: ""},
+		{tag: "1st-Name", want: "st-Name"},
+		{tag: "-.9Name", want: "Name"},
+		{tag: "2024", opts: TagOptions{PrefixInvalidStart: true}, want: "_2024"},
+		{tag: "2024 Sales", opts: TagOptions{PrefixInvalidStart: true}, want: "_2024_x0020_Sales"},
+		{tag: "Sales 2024", opts: TagOptions{PrefixInvalidStart: true}, want: "Sales_x0020_2024"},
+		{tag: "Tab\tStop", want: "TabStop"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			if got := FixXMLTagsWith(tt.tag, tt.opts); got != tt.want {
+				t.Errorf("FixXMLTagsWith(%q) = %q, want %q", tt.tag, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFixXMLTagsReplacement(t *testing.T) {
+	tests := []struct {
+		tag  string
+		opts TagOptions
+		want string
+	}{
+		{tag: "Amount ($)", opts: TagOptions{Replacement: ReplaceInvalid}, want: "Amount____"},
+		{tag: "Amount ($)", opts: TagOptions{Replacement: ReplaceInvalid, Placeholder: "-"}, want: "Amount----"},
+		{tag: "Amount ($)", opts: TagOptions{Replacement: EscapeInvalid}, want: "Amount_x0020__x0028__x0024__x0029_"},
+		{tag: "Amount (%)", opts: TagOptions{Replacement: EscapeInvalid}, want: "Amount_x0020__x0028__x0025__x0029_"},
+		{tag: "ns:Name", opts: TagOptions{Replacement: EscapeInvalid}, want: "ns_x003A_Name"},
+		{tag: "2024", opts: TagOptions{Replacement: ReplaceInvalid}, want: "_2024"},
+		{tag: "2024", opts: TagOptions{Replacement: EscapeInvalid}, want: "_x0032_024"},
+		{tag: "2024", opts: TagOptions{Replacement: EscapeInvalid, PrefixInvalidStart: true}, want: "_2024"},
+		{tag: "First Name", opts: TagOptions{Replacement: ReplaceInvalid}, want: "First_Name"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			if got := FixXMLTagsWith(tt.tag, tt.opts); got != tt.want {
+				t.Errorf("FixXMLTagsWith(%q, %+v) = %q, want %q", tt.tag, tt.opts, got, tt.want)
+			}
+		})
+	}
+	if _, err := ParseTagReplacement("mangle"); err == nil || err.Error() != "unknown tag replacement 'mangle'" {
+		t.Errorf("ParseTagReplacement(mangle) error = %v", err)
+	}
+}
+
+func TestSanitizeHeaders(t *testing.T) {
+	tests := []struct {
+		name        string
+		headers     []string
+		opts        SanitizeOptions
+		want        []string
+		wantRenamed []int
+	}{
+		{
+			name:        "Collapsed By Stripping",
+			headers:     []string{"Amount ($)", "Amount (%)", "Amount_x0020__2"},
+			want:        []string{"Amount_x0020_", "Amount_x0020__3", "Amount_x0020__2"},
+			wantRenamed: []int{1},
+		},
+		{
+			name:    "Kept Apart By Escaping",
+			headers: []string{"Amount ($)", "Amount (%)"},
+			opts:    SanitizeOptions{Tag: TagOptions{Replacement: EscapeInvalid}},
+			want:    []string{"Amount_x0020__x0028__x0024__x0029_", "Amount_x0020__x0028__x0025__x0029_"},
+		},
+		{
+			name:        "Plain Duplicates",
+			headers:     []string{"Name", "Name", "Name"},
+			opts:        SanitizeOptions{Rename: RenameOptions{Format: ParenthesesSuffix}},
+			want:        []string{"Name", "Name_x0020_2", "Name_x0020_3"},
+			wantRenamed: []int{1, 2},
+		},
+		{
+			name:        "Ignore Case",
+			headers:     []string{"Id", "ID", "Id_2"},
+			opts:        SanitizeOptions{Rename: RenameOptions{IgnoreCase: true}},
+			want:        []string{"Id", "ID_3", "Id_2"},
+			wantRenamed: []int{1},
+		},
+		{
+			name:    "Nothing Left",
+			headers: []string{"Name", "", "(%)", "  "},
+			want:    []string{"Name", "Column2", "Column3", "Column4"},
+		},
+		{
+			name:        "Nothing Left Among Taken Names",
+			headers:     []string{"Column2", "$"},
+			want:        []string{"Column2", "Column2_2"},
+			wantRenamed: []int{1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := append([]string(nil), tt.headers...)
+			got, renames := SanitizeHeaders(input, tt.opts)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SanitizeHeaders() = %v, want %v", got, tt.want)
+			}
+			var renamed []int
+			for _, rename := range renames {
+				renamed = append(renamed, rename.Index)
+				if rename.Original != tt.headers[rename.Index] || rename.Renamed != got[rename.Index] {
+					t.Errorf("SanitizeHeaders() rename = %+v, want it to describe header %d", rename, rename.Index)
+				}
+			}
+			if !reflect.DeepEqual(renamed, tt.wantRenamed) {
+				t.Errorf("SanitizeHeaders() renamed %v, want %v", renamed, tt.wantRenamed)
+			}
+			if !reflect.DeepEqual(input, tt.headers) {
+				t.Errorf("SanitizeHeaders() modified its input: %v", input)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package helpers
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// ErrNotAnAmount is returned by ParseMoney for values naming no currency it knows, or several.
+var ErrNotAnAmount = errors.New("not a currency amount")
+
+// currencySymbols maps the currency symbols ParseMoney reads to their ISO 4217 code. The dollar and yen signs
+// alone are read as the United States dollar and the Japanese yen.
+var currencySymbols = map[string]string{
+	"$": "USD", "US$": "USD", "C$": "CAD", "CA$": "CAD", "A$": "AUD", "AU$": "AUD", "NZ$": "NZD", "HK$": "HKD",
+	"S$": "SGD", "R$": "BRL", "MX$": "MXN", "€": "EUR", "£": "GBP", "¥": "JPY", "JP¥": "JPY", "CN¥": "CNY",
+	"元": "CNY", "₹": "INR", "₩": "KRW", "₽": "RUB", "₺": "TRY", "₪": "ILS", "₫": "VND", "₱": "PHP", "₴": "UAH",
+	"฿": "THB", "₦": "NGN", "zł": "PLN", "Kč": "CZK", "Fr.": "CHF",
+}
+
+// currencyDigits maps the ISO 4217 currencies whose amounts do not have two decimal digits to their number.
+var currencyDigits = map[string]int{
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0, "KRW": 0, "PYG": 0, "RWF": 0,
+	"UGX": 0, "VND": 0, "VUV": 0, "XAF": 0, "XOF": 0, "XPF": 0,
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+}
+
+// CurrencyDigits returns the number of decimal digits ISO 4217 gives the amounts of the currency code,
+// such as 2 for "EUR" and 0 for "JPY".
+func CurrencyDigits(code string) int {
+	if digits, known := currencyDigits[strings.ToUpper(code)]; known {
+		return digits
+	}
+	return 2
+}
+
+// Money is an amount of a currency: Amount is a canonical decimal string, as returned by NormalizeNumber,
+// and Currency an ISO 4217 code such as "EUR".
+type Money struct {
+	Amount   string `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// String returns the amount followed by the currency code, such as "1234.56 EUR".
+func (m Money) String() string {
+	return strings.TrimSpace(m.Amount + " " + m.Currency)
+}
+
+// MoneyOptions configures ParseMoney.
+// Locale is how the amounts are written; when it is left out, ParseMoney detects it from each amount.
+// DefaultCurrency is the currency code of the amounts naming none; Currency is left empty otherwise.
+type MoneyOptions struct {
+	Locale          NumberLocale
+	DefaultCurrency string
+}
+
+// ParseMoney returns the amount and the currency of value, a currency amount written with a symbol or an ISO
+// 4217 code before or after the number, such as "€1.234,56", "USD 1,200.00" or "-12,50 zł". Without a locale,
+// the last of a point and a comma both in the amount is the decimal separator, and a lone separator followed
+// by three digits groups thousands unless the currency has three decimal digits, so "1,200" is twelve hundred
+// but "KWD 1,200" is one and a fifth. The error wraps ErrNotAnAmount when the currency is unknown, otherwise
+// the error of NormalizeNumber.
+// Example usage:
+//
+//	ParseMoney("€1.234,56", MoneyOptions{})
+//	// Output: Money{Amount: "1234.56", Currency: "EUR"}, nil
+func ParseMoney(value string, opts MoneyOptions) (Money, error) {
+	var currency, number strings.Builder
+	tokens := 0
+	inToken, afterLetter := false, false
+	for _, char := range value {
+		isCurrency := unicode.IsLetter(char) || unicode.Is(unicode.Sc, char) || afterLetter && char == '.'
+		afterLetter = unicode.IsLetter(char)
+		if isCurrency && !inToken {
+			tokens++
+		}
+		inToken = isCurrency
+		if isCurrency {
+			currency.WriteRune(char)
+			continue
+		}
+		number.WriteRune(char)
+	}
+	if tokens > 1 {
+		return Money{}, fmt.Errorf("'%s' is %w: it names more than one currency", value, ErrNotAnAmount)
+	}
+	money := Money{Currency: strings.ToUpper(opts.DefaultCurrency)}
+	if code := currency.String(); len(code) > 0 {
+		symbol, known := currencySymbols[code]
+		switch {
+		case known:
+			money.Currency = symbol
+		case len(code) == 3 && isLatinCode(code):
+			money.Currency = strings.ToUpper(code)
+		default:
+			return Money{}, fmt.Errorf("'%s' is %w: '%s' is not a currency", value, ErrNotAnAmount, code)
+		}
+	}
+	locale := opts.Locale
+	if locale.Decimal == 0 {
+		locale = detectNumberLocale(number.String(), CurrencyDigits(money.Currency))
+	}
+	amount, err := NormalizeNumber(number.String(), locale)
+	if err != nil {
+		return Money{}, fmt.Errorf("'%s' is %w: %w", value, ErrNotAnAmount, err)
+	}
+	money.Amount = amount
+	return money, nil
+}
+
+// isLatinCode reports whether code only holds the letters A to Z, in either case.
+func isLatinCode(code string) bool {
+	for _, char := range strings.ToUpper(code) {
+		if char < 'A' || char > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// detectNumberLocale returns the locale number, an amount of a currency with digits decimal digits, is written in.
+func detectNumberLocale(number string, digits int) NumberLocale {
+	if strings.ContainsAny(number, "'’") {
+		return SwissNumbers
+	}
+	point, comma := strings.LastIndex(number, "."), strings.LastIndex(number, ",")
+	switch {
+	case point >= 0 && comma >= 0:
+		if point > comma {
+			return EnglishNumbers
+		}
+		return EuropeanNumbers
+	case point < 0 && comma < 0:
+		return EnglishNumbers
+	}
+	separator, last := ".", point
+	if comma >= 0 {
+		separator, last = ",", comma
+	}
+	trailing := strings.TrimRightFunc(number[last+1:], func(char rune) bool { return !unicode.IsDigit(char) })
+	grouping := strings.Count(number, separator) > 1 || len(trailing) == 3 && digits != 3
+	if grouping == (separator == ",") {
+		return EnglishNumbers
+	}
+	return EuropeanNumbers
+}
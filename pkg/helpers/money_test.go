@@ -0,0 +1,55 @@
+package helpers
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		value   string
+		opts    MoneyOptions
+		want    Money
+		wantErr error
+	}{
+		{value: "€1.234,56", want: Money{Amount: "1234.56", Currency: "EUR"}},
+		{value: "USD 1,200.00", want: Money{Amount: "1200.00", Currency: "USD"}},
+		{value: "1 234,56 €", want: Money{Amount: "1234.56", Currency: "EUR"}},
+		{value: "-12,50 zł", want: Money{Amount: "-12.50", Currency: "PLN"}},
+		{value: "(£1,000)", want: Money{Amount: "-1000", Currency: "GBP"}},
+		{value: "CHF 1'234.50", want: Money{Amount: "1234.50", Currency: "CHF"}},
+		{value: "US$ 9.99", want: Money{Amount: "9.99", Currency: "USD"}},
+		{value: "$.99", want: Money{Amount: "0.99", Currency: "USD"}},
+		{value: "Fr. 12.50", want: Money{Amount: "12.50", Currency: "CHF"}},
+		{value: "1,200", want: Money{Amount: "1200"}},
+		{value: "KWD 1,200", want: Money{Amount: "1.200", Currency: "KWD"}},
+		{value: "¥1,200,000", want: Money{Amount: "1200000", Currency: "JPY"}},
+		{value: "12.5 eur", want: Money{Amount: "12.5", Currency: "EUR"}},
+		{value: "12.50", opts: MoneyOptions{DefaultCurrency: "gbp"}, want: Money{Amount: "12.50", Currency: "GBP"}},
+		{value: "1.200", opts: MoneyOptions{Locale: EnglishNumbers}, want: Money{Amount: "1.200"}},
+		{value: "€ 12 USD", wantErr: ErrNotAnAmount},
+		{value: "12 dollars", wantErr: ErrNotAnAmount},
+		{value: "EUR twelve", wantErr: ErrNotAnAmount},
+		{value: "EUR 1,2,3", wantErr: ErrNotANumber},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseMoney(tt.value, tt.opts)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ParseMoney(%q) error = %v, want %v", tt.value, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("ParseMoney(%q) = %+v, %v, want %+v", tt.value, got, err, tt.want)
+			}
+		})
+	}
+	if got := (Money{Amount: "1234.56", Currency: "EUR"}).String(); got != "1234.56 EUR" {
+		t.Errorf("Money.String() = %q", got)
+	}
+	if CurrencyDigits("jpy") != 0 || CurrencyDigits("EUR") != 2 || CurrencyDigits("BHD") != 3 {
+		t.Errorf("CurrencyDigits() are wrong")
+	}
+}
@@ -0,0 +1,48 @@
+package helpers
+
+import (
+	"strings"
+	"unicode"
+)
+
+// DefaultNullTokens lists the values spreadsheets commonly hold in place of a missing value.
+var DefaultNullTokens = []string{"N/A", "NA", "#N/A", "NULL", "nil", "None", "-", "--", "—", "–", "?"}
+
+// IsNullToken reports whether value, ignoring case and surrounding spaces, is one of tokens, which stand for a
+// missing value; DefaultNullTokens is used when tokens is nil.
+// Example usage:
+//
+//	IsNullToken(" n/a ", nil)
+//	// Output: true
+func IsNullToken(value string, tokens []string) bool {
+	if tokens == nil {
+		tokens = DefaultNullTokens
+	}
+	value = strings.TrimFunc(value, unicode.IsSpace)
+	for _, token := range tokens {
+		if strings.EqualFold(value, token) {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeNull returns an empty string for the null tokens, see IsNullToken, and value otherwise.
+func NormalizeNull(value string, tokens []string) string {
+	if IsNullToken(value, tokens) {
+		return ""
+	}
+	return value
+}
+
+// ParseNullTokens returns the comma separated null tokens of list, DefaultNullTokens for "default" and none
+// for an empty list.
+func ParseNullTokens(list string) []string {
+	switch list {
+	case "":
+		return nil
+	case "default":
+		return DefaultNullTokens
+	}
+	return strings.Split(list, ",")
+}
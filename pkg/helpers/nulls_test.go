@@ -0,0 +1,39 @@
+package helpers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeNull(t *testing.T) {
+	tests := []struct {
+		value  string
+		tokens []string
+		want   string
+	}{
+		{value: "N/A", want: ""},
+		{value: " n/a ", want: ""},
+		{value: "#N/A", want: ""},
+		{value: "null", want: ""},
+		{value: "—", want: ""},
+		{value: "-", want: ""},
+		{value: "-5", want: "-5"},
+		{value: "Nathan", want: "Nathan"},
+		{value: "", want: ""},
+		{value: "missing", tokens: []string{"missing"}, want: ""},
+		{value: "N/A", tokens: []string{"missing"}, want: "N/A"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := NormalizeNull(tt.value, tt.tokens); got != tt.want {
+				t.Errorf("NormalizeNull(%q, %v) = %q, want %q", tt.value, tt.tokens, got, tt.want)
+			}
+		})
+	}
+	if got := ParseNullTokens("n/a,-"); !reflect.DeepEqual(got, []string{"n/a", "-"}) {
+		t.Errorf("ParseNullTokens() = %v", got)
+	}
+	if got := ParseNullTokens(""); got != nil {
+		t.Errorf("ParseNullTokens(\"\") = %v, want none", got)
+	}
+}
@@ -0,0 +1,176 @@
+package helpers
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// ErrNotANumber is returned by NormalizeNumber for values which are not a number in the locale.
+var ErrNotANumber = errors.New("not a number")
+
+// NumberLocale describes how a locale writes numbers: the character before the fraction and the characters
+// grouping the digits of the integer part by thousands.
+type NumberLocale struct {
+	Decimal   rune
+	Groupings []rune
+}
+
+// The number locales known to ParseNumberLocale.
+var (
+	// EnglishNumbers writes one thousand and a half as "1,234.5".
+	EnglishNumbers = NumberLocale{Decimal: '.', Groupings: []rune{',', ' ', '\u00A0', '\u202F'}}
+	// EuropeanNumbers writes it as "1.234,5", as in Germany, Italy, Spain or the Netherlands.
+	EuropeanNumbers = NumberLocale{Decimal: ',', Groupings: []rune{'.', ' ', '\u00A0', '\u202F'}}
+	// FrenchNumbers writes it as "1 234,5", grouping with spaces only.
+	FrenchNumbers = NumberLocale{Decimal: ',', Groupings: []rune{' ', '\u00A0', '\u202F'}}
+	// SwissNumbers writes it as "1'234.5".
+	SwissNumbers = NumberLocale{Decimal: '.', Groupings: []rune{'\'', '\u2019', ' ', '\u00A0', '\u202F'}}
+)
+
+// ParseNumberLocale returns the NumberLocale called en, de, fr or ch, or by one of the other languages
+// writing numbers like them.
+func ParseNumberLocale(name string) (NumberLocale, error) {
+	switch strings.ToLower(name) {
+	case "", "en", "us", "uk", "english":
+		return EnglishNumbers, nil
+	case "de", "es", "it", "nl", "pt", "da", "european":
+		return EuropeanNumbers, nil
+	case "fr", "fi", "sv", "nb", "pl", "cs", "french":
+		return FrenchNumbers, nil
+	case "ch", "swiss":
+		return SwissNumbers, nil
+	}
+	return NumberLocale{}, fmt.Errorf("unknown number locale '%s'", name)
+}
+
+// groups reports whether locale groups digits with char.
+func (l NumberLocale) groups(char rune) bool {
+	for _, grouping := range l.Groupings {
+		if char == grouping {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeNumber returns value, a number written as in locale, as a canonical decimal string: an optional
+// minus sign, the digits of the integer part without grouping or leading zeros and the fraction after a point,
+// so "1.234,50 €" in EuropeanNumbers gives "1234.50", fit for a database. Currency symbols and codes such as
+// "$" or "EUR" before or after the number are dropped, and the number is negative when it has a minus sign
+// before or after it or is in parentheses, as accountants write it. Thousands separators must group the integer
+// part by three digits. The error wraps ErrNotANumber for anything else.
+// Example usage:
+//
+//	NormalizeNumber("(1,234.56)", EnglishNumbers)
+//	// Output: "-1234.56", nil
+//
+//	NormalizeNumber("1.234,56", EnglishNumbers)
+//	// Output: "", '1.234,56' is not a number: the group separator ',' follows the decimal separator
+func NormalizeNumber(value string, locale NumberLocale) (string, error) {
+	fail := func(reason string) (string, error) {
+		if len(reason) > 0 {
+			return "", fmt.Errorf("'%s' is %w: %s", value, ErrNotANumber, reason)
+		}
+		return "", fmt.Errorf("'%s' is %w", value, ErrNotANumber)
+	}
+	number := strings.TrimFunc(value, unicode.IsSpace)
+	negative := false
+	if strings.HasPrefix(number, "(") && strings.HasSuffix(number, ")") {
+		negative = true
+		number = number[1 : len(number)-1]
+	}
+	number = trimCurrency(number)
+	for _, minus := range []string{"-", "−"} {
+		switch {
+		case strings.HasPrefix(number, minus):
+			number = strings.TrimPrefix(number, minus)
+		case strings.HasSuffix(number, minus):
+			number = strings.TrimSuffix(number, minus)
+		default:
+			continue
+		}
+		if negative {
+			return fail("it has two minus signs")
+		}
+		negative = true
+		break
+	}
+	number = trimCurrency(strings.TrimPrefix(number, "+"))
+	if len(number) < 1 {
+		return fail("")
+	}
+
+	var integer, fraction strings.Builder
+	inFraction := false
+	grouped, groupDigits := false, 0
+	for _, char := range number {
+		switch {
+		case '0' <= char && char <= '9':
+			if inFraction {
+				fraction.WriteRune(char)
+				continue
+			}
+			integer.WriteRune(char)
+			groupDigits++
+		case char == locale.Decimal:
+			if inFraction {
+				return fail(fmt.Sprintf("the decimal separator '%c' appears twice", char))
+			}
+			if grouped && groupDigits != 3 {
+				return fail("the digits are not grouped by three")
+			}
+			inFraction = true
+		case locale.groups(char):
+			if inFraction {
+				return fail(fmt.Sprintf("the group separator '%c' follows the decimal separator", char))
+			}
+			if groupDigits < 1 || groupDigits > 3 || grouped && groupDigits != 3 {
+				return fail("the digits are not grouped by three")
+			}
+			grouped, groupDigits = true, 0
+		default:
+			return fail(fmt.Sprintf("it contains '%c'", char))
+		}
+	}
+	if grouped && !inFraction && groupDigits != 3 {
+		return fail("the digits are not grouped by three")
+	}
+	if integer.Len() < 1 && fraction.Len() < 1 {
+		return fail("")
+	}
+
+	digits := strings.TrimLeft(integer.String(), "0")
+	if len(digits) < 1 {
+		digits = "0"
+	}
+	if fraction.Len() > 0 {
+		digits += "." + fraction.String()
+	}
+	if negative && strings.Trim(digits, "0.") != "" {
+		digits = "-" + digits
+	}
+	return digits, nil
+}
+
+// trimCurrency removes a currency symbol or a three-letter currency code, such as "$" or "EUR", and the spaces
+// next to it from the start or the end of number.
+func trimCurrency(number string) string {
+	number = strings.TrimFunc(number, func(char rune) bool { return unicode.Is(unicode.Sc, char) || unicode.IsSpace(char) })
+	runes := []rune(number)
+	isCode := func(code []rune) bool {
+		for _, char := range code {
+			if char < 'A' || char > 'Z' {
+				return false
+			}
+		}
+		return true
+	}
+	if len(runes) > 3 && isCode(runes[:3]) {
+		runes = runes[3:]
+	} else if len(runes) > 3 && isCode(runes[len(runes)-3:]) {
+		runes = runes[:len(runes)-3]
+	}
+	return strings.TrimFunc(string(runes), unicode.IsSpace)
+}
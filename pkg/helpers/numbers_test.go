@@ -0,0 +1,65 @@
+package helpers
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeNumber(t *testing.T) {
+	tests := []struct {
+		value   string
+		locale  NumberLocale
+		want    string
+		wantErr string
+	}{
+		{value: "1234.56", locale: EnglishNumbers, want: "1234.56"},
+		{value: "1,234.56", locale: EnglishNumbers, want: "1234.56"},
+		{value: "1.234,56", locale: EuropeanNumbers, want: "1234.56"},
+		{value: "1 234,56", locale: FrenchNumbers, want: "1234.56"},
+		{value: "1\u202F234,56", locale: FrenchNumbers, want: "1234.56"},
+		{value: "1'234'567.5", locale: SwissNumbers, want: "1234567.5"},
+		{value: "(1,234.56)", locale: EnglishNumbers, want: "-1234.56"},
+		{value: "-42", locale: EnglishNumbers, want: "-42"},
+		{value: "42-", locale: EuropeanNumbers, want: "-42"},
+		{value: "−7,5", locale: EuropeanNumbers, want: "-7.5"},
+		{value: "$1,000", locale: EnglishNumbers, want: "1000"},
+		{value: "-$1,000.00", locale: EnglishNumbers, want: "-1000.00"},
+		{value: "1.234,50 €", locale: EuropeanNumbers, want: "1234.50"},
+		{value: "EUR 12,00", locale: EuropeanNumbers, want: "12.00"},
+		{value: "(12.00 USD)", locale: EnglishNumbers, want: "-12.00"},
+		{value: "007", locale: EnglishNumbers, want: "7"},
+		{value: ".5", locale: EnglishNumbers, want: "0.5"},
+		{value: "-0.00", locale: EnglishNumbers, want: "0.00"},
+		{value: "1.234", locale: EnglishNumbers, want: "1.234"},
+		{value: "1.234", locale: EuropeanNumbers, want: "1234"},
+		{value: "1.234,56", locale: EnglishNumbers, wantErr: "the group separator ',' follows the decimal separator"},
+		{value: "12,34", locale: EnglishNumbers, wantErr: "not grouped by three"},
+		{value: "1,2345", locale: EnglishNumbers, wantErr: "not grouped by three"},
+		{value: "1..2", locale: EnglishNumbers, wantErr: "appears twice"},
+		{value: "(-5)", locale: EnglishNumbers, wantErr: "two minus signs"},
+		{value: "12a", locale: EnglishNumbers, wantErr: "it contains 'a'"},
+		{value: "", locale: EnglishNumbers, wantErr: "is not a number"},
+		{value: "€", locale: EuropeanNumbers, wantErr: "is not a number"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := NormalizeNumber(tt.value, tt.locale)
+			if len(tt.wantErr) > 0 {
+				if !errors.Is(err, ErrNotANumber) || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("NormalizeNumber(%q) error = %v, want %s", tt.value, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("NormalizeNumber(%q) = %q, %v, want %q", tt.value, got, err, tt.want)
+			}
+		})
+	}
+	if locale, err := ParseNumberLocale("DE"); err != nil || locale.Decimal != ',' {
+		t.Errorf("ParseNumberLocale(DE) = %+v, %v", locale, err)
+	}
+	if _, err := ParseNumberLocale("xx"); err == nil || err.Error() != "unknown number locale 'xx'" {
+		t.Errorf("ParseNumberLocale(xx) error = %v", err)
+	}
+}
@@ -0,0 +1,147 @@
+package helpers
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidPhone is returned by NormalizePhone for values which are not a valid phone number.
+var ErrInvalidPhone = errors.New("not a valid phone number")
+
+// phoneRegion describes how the phone numbers of a region are written: the country calling code, the trunk
+// prefix dialled before national numbers, the prefix dialled before international numbers and the number of
+// digits of the national significant number, which follows the calling code.
+type phoneRegion struct {
+	callingCode   string
+	trunkPrefix   string
+	international string
+	minDigits     int
+	maxDigits     int
+}
+
+// phoneRegions maps the ISO 3166 region codes known to NormalizePhone to their numbering plans.
+var phoneRegions = map[string]phoneRegion{
+	"US": {"1", "1", "011", 10, 10},
+	"CA": {"1", "1", "011", 10, 10},
+	"GB": {"44", "0", "00", 9, 10},
+	"IE": {"353", "0", "00", 7, 9},
+	"DE": {"49", "0", "00", 6, 13},
+	"AT": {"43", "0", "00", 4, 13},
+	"CH": {"41", "0", "00", 9, 9},
+	"FR": {"33", "0", "00", 9, 9},
+	"BE": {"32", "0", "00", 8, 9},
+	"NL": {"31", "0", "00", 9, 9},
+	"LU": {"352", "", "00", 4, 11},
+	"ES": {"34", "", "00", 9, 9},
+	"PT": {"351", "", "00", 9, 9},
+	"IT": {"39", "", "00", 6, 11},
+	"DK": {"45", "", "00", 8, 8},
+	"NO": {"47", "", "00", 8, 8},
+	"SE": {"46", "0", "00", 7, 10},
+	"FI": {"358", "0", "00", 5, 12},
+	"PL": {"48", "", "00", 9, 9},
+	"CZ": {"420", "", "00", 9, 9},
+	"AU": {"61", "0", "0011", 9, 9},
+	"NZ": {"64", "0", "00", 8, 10},
+	"IN": {"91", "0", "00", 10, 10},
+	"JP": {"81", "0", "010", 9, 10},
+	"BR": {"55", "0", "00", 10, 11},
+	"MX": {"52", "", "00", 10, 10},
+	"ZA": {"27", "0", "00", 9, 9},
+}
+
+// E.164 numbers hold at most 15 digits; the shortest numbers in use hold 8 with their calling code.
+const (
+	minPhoneDigits = 8
+	maxPhoneDigits = 15
+)
+
+// NormalizePhone returns value, a phone number, in the E.164 format, such as "+4930123456": a plus sign, the
+// country calling code and the national significant number without any separator. Numbers written with a plus
+// sign or an international prefix, such as "+44 (0)20 7946 0958" or "0049 30 123456", carry their region;
+// the others are national numbers of defaultRegion, an ISO 3166 code such as "US" or "DE", whose trunk prefix is
+// dropped. Spaces, dots, dashes, slashes and parentheses are allowed as separators. The number of digits is
+// checked against the numbering plan of the regions NormalizePhone knows and against the limits of E.164 for the
+// others; the error wraps ErrInvalidPhone.
+// Example usage:
+//
+//	NormalizePhone("(555) 010-4477", "US")
+//	// Output: "+15550104477", nil
+//
+//	NormalizePhone("030 123456", "DE")
+//	// Output: "+4930123456", nil
+func NormalizePhone(value, defaultRegion string) (string, error) {
+	fail := func(reason string) (string, error) {
+		return "", fmt.Errorf("'%s' is %w: %s", value, ErrInvalidPhone, reason)
+	}
+	var region *phoneRegion
+	if len(defaultRegion) > 0 {
+		known, ok := phoneRegions[strings.ToUpper(defaultRegion)]
+		if !ok {
+			return "", fmt.Errorf("unknown phone region '%s'", defaultRegion)
+		}
+		region = &known
+	}
+
+	number := strings.TrimSpace(value)
+	international := strings.HasPrefix(number, "+")
+	var digits strings.Builder
+	for index, char := range strings.ReplaceAll(number, "(0)", "") {
+		switch {
+		case '0' <= char && char <= '9':
+			digits.WriteRune(char)
+		case char == '+' && index == 0:
+		case strings.ContainsRune(" .-/() ", char):
+		default:
+			return fail(fmt.Sprintf("it contains '%c'", char))
+		}
+	}
+	national := digits.String()
+	if !international && region != nil && strings.HasPrefix(national, region.international) {
+		international, national = true, strings.TrimPrefix(national, region.international)
+	} else if !international && strings.HasPrefix(national, "00") {
+		international, national = true, strings.TrimPrefix(national, "00")
+	}
+
+	if international {
+		if len(national) < minPhoneDigits || len(national) > maxPhoneDigits {
+			return fail(fmt.Sprintf("an international number holds %d to %d digits", minPhoneDigits, maxPhoneDigits))
+		}
+		for length := 1; length <= 3; length++ {
+			for _, plan := range phoneRegions {
+				if plan.callingCode != national[:length] {
+					continue
+				}
+				if subscriber := len(national) - length; subscriber < plan.minDigits || subscriber > plan.maxDigits {
+					return fail(fmt.Sprintf("numbers with the calling code +%s hold %s digits after it", plan.callingCode, digitRange(plan)))
+				}
+				return "+" + national, nil
+			}
+		}
+		return "+" + national, nil
+	}
+
+	if region == nil {
+		return fail("it has no calling code and no default region is given")
+	}
+	// A trunk prefix of 1, as in North America, is only told apart from the number when it makes it too long.
+	switch {
+	case region.trunkPrefix == "0":
+		national = strings.TrimPrefix(national, region.trunkPrefix)
+	case len(region.trunkPrefix) > 0 && len(national) > region.maxDigits:
+		national = strings.TrimPrefix(national, region.trunkPrefix)
+	}
+	if len(national) < region.minDigits || len(national) > region.maxDigits {
+		return fail(fmt.Sprintf("national numbers of %s hold %s digits", strings.ToUpper(defaultRegion), digitRange(*region)))
+	}
+	return "+" + region.callingCode + national, nil
+}
+
+// digitRange describes the number of digits of the national significant numbers of region.
+func digitRange(region phoneRegion) string {
+	if region.minDigits == region.maxDigits {
+		return fmt.Sprint(region.minDigits)
+	}
+	return fmt.Sprintf("%d to %d", region.minDigits, region.maxDigits)
+}
@@ -0,0 +1,52 @@
+package helpers
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNormalizePhone(t *testing.T) {
+	tests := []struct {
+		value   string
+		region  string
+		want    string
+		wantErr string
+	}{
+		{value: "(555) 010-4477", region: "US", want: "+15550104477"},
+		{value: "1-555-010-4477", region: "US", want: "+15550104477"},
+		{value: "+1 555 010 4477", want: "+15550104477"},
+		{value: "011 44 20 7946 0958", region: "US", want: "+442079460958"},
+		{value: "030 123456", region: "DE", want: "+4930123456"},
+		{value: "0049 30 123456", region: "de", want: "+4930123456"},
+		{value: "+44 (0)20 7946 0958", region: "DE", want: "+442079460958"},
+		{value: "020 7946 0958", region: "GB", want: "+442079460958"},
+		{value: "06.12.34.56.78", region: "FR", want: "+33612345678"},
+		{value: "06 12 34 56 78", region: "IT", want: "+390612345678"},
+		{value: "0412 345 678", region: "AU", want: "+61412345678"},
+		{value: "+372 5123 4567", want: "+37251234567"},
+		{value: "555 0104", region: "US", wantErr: "national numbers of US hold 10 digits"},
+		{value: "+1 555 0104", wantErr: "numbers with the calling code +1 hold 10 digits after it"},
+		{value: "+49 30 1", wantErr: "an international number holds 8 to 15 digits"},
+		{value: "+33 6 12 34 56 789", wantErr: "numbers with the calling code +33 hold 9 digits after it"},
+		{value: "555-0104 ext. 12", region: "US", wantErr: "it contains 'e'"},
+		{value: "030 123456", wantErr: "no default region"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := NormalizePhone(tt.value, tt.region)
+			if len(tt.wantErr) > 0 {
+				if !errors.Is(err, ErrInvalidPhone) || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("NormalizePhone(%q, %q) error = %v, want %s", tt.value, tt.region, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("NormalizePhone(%q, %q) = %q, %v, want %q", tt.value, tt.region, got, err, tt.want)
+			}
+		})
+	}
+	if _, err := NormalizePhone("555 0104", "XX"); err == nil || err.Error() != "unknown phone region 'XX'" {
+		t.Errorf("NormalizePhone() error = %v, want the region rejected", err)
+	}
+}
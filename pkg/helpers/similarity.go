@@ -0,0 +1,115 @@
+package helpers
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Levenshtein returns the edit distance between a and b: the least number of characters to insert, delete or
+// substitute to turn one into the other.
+func Levenshtein(a, b string) int {
+	first, second := []rune(a), []rune(b)
+	previous := make([]int, len(second)+1)
+	current := make([]int, len(second)+1)
+	for index := range previous {
+		previous[index] = index
+	}
+	for i, char := range first {
+		current[0] = i + 1
+		for j, other := range second {
+			cost := 1
+			if char == other {
+				cost = 0
+			}
+			current[j+1] = min(previous[j+1]+1, current[j]+1, previous[j]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(second)]
+}
+
+// LevenshteinSimilarity returns the Levenshtein distance between a and b as a similarity from 0, for strings
+// having nothing in common, to 1, for equal strings.
+func LevenshteinSimilarity(a, b string) float64 {
+	longest := max(len([]rune(a)), len([]rune(b)))
+	if longest < 1 {
+		return 1
+	}
+	return 1 - float64(Levenshtein(a, b))/float64(longest)
+}
+
+// JaroWinkler returns the Jaro-Winkler similarity of a and b, from 0 to 1 for equal strings. It favours strings
+// sharing their first characters, so it suits names and headers with varying endings such as "Customer" and
+// "Customer No".
+func JaroWinkler(a, b string) float64 {
+	first, second := []rune(a), []rune(b)
+	if len(first) < 1 && len(second) < 1 {
+		return 1
+	}
+	if len(first) < 1 || len(second) < 1 {
+		return 0
+	}
+	window := max(len(first), len(second))/2 - 1
+	window = max(window, 0)
+	firstMatched := make([]bool, len(first))
+	secondMatched := make([]bool, len(second))
+	matches := 0
+	for i, char := range first {
+		for j := max(0, i-window); j < min(len(second), i+window+1); j++ {
+			if !secondMatched[j] && second[j] == char {
+				firstMatched[i], secondMatched[j] = true, true
+				matches++
+				break
+			}
+		}
+	}
+	if matches < 1 {
+		return 0
+	}
+	transpositions, j := 0, 0
+	for i, char := range first {
+		if !firstMatched[i] {
+			continue
+		}
+		for !secondMatched[j] {
+			j++
+		}
+		if char != second[j] {
+			transpositions++
+		}
+		j++
+	}
+	m := float64(matches)
+	jaro := (m/float64(len(first)) + m/float64(len(second)) + (m-float64(transpositions/2))/m) / 3
+	prefix := 0
+	for prefix < min(4, len(first), len(second)) && first[prefix] == second[prefix] {
+		prefix++
+	}
+	return jaro + float64(prefix)*0.1*(1-jaro)
+}
+
+// FindBestMatch returns the candidate most similar to value and their Jaro-Winkler similarity, comparing them
+// regardless of case and of the spaces, underscores and punctuation between words, so "customer_no" matches
+// "Customer No." exactly. found is false when no candidate is at least threshold similar; the first candidate
+// wins ties.
+// Example usage:
+//
+//	FindBestMatch("Adress", []string{"Name", "Address", "City"}, 0.9)
+//	// Output: "Address", 0.96, true
+func FindBestMatch(value string, candidates []string, threshold float64) (match string, score float64, found bool) {
+	key := similarityKey(value)
+	for _, candidate := range candidates {
+		similarity := JaroWinkler(key, similarityKey(candidate))
+		if similarity >= threshold && (!found || similarity > score) {
+			match, score, found = candidate, similarity, true
+		}
+	}
+	return match, score, found
+}
+
+// similarityKey returns value lower cased with its words separated by single spaces.
+func similarityKey(value string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(value), func(char rune) bool {
+		return !unicode.IsLetter(char) && !unicode.IsDigit(char)
+	}), " ")
+}
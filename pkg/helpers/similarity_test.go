@@ -0,0 +1,74 @@
+package helpers
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b       string
+		want       int
+		similarity float64
+	}{
+		{a: "kitten", b: "sitting", want: 3, similarity: 1 - 3.0/7},
+		{a: "", b: "abc", want: 3, similarity: 0},
+		{a: "", b: "", want: 0, similarity: 1},
+		{a: "Größe", b: "Grösse", want: 2, similarity: 1 - 2.0/6},
+		{a: "flaw", b: "lawn", want: 2, similarity: 0.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+"/"+tt.b, func(t *testing.T) {
+			if got := Levenshtein(tt.a, tt.b); got != tt.want {
+				t.Errorf("Levenshtein() = %d, want %d", got, tt.want)
+			}
+			if got := LevenshteinSimilarity(tt.a, tt.b); math.Abs(got-tt.similarity) > 1e-9 {
+				t.Errorf("LevenshteinSimilarity() = %v, want %v", got, tt.similarity)
+			}
+		})
+	}
+}
+
+func TestJaroWinkler(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{a: "MARTHA", b: "MARHTA", want: 0.961},
+		{a: "DWAYNE", b: "DUANE", want: 0.840},
+		{a: "DIXON", b: "DICKSONX", want: 0.813},
+		{a: "abc", b: "xyz", want: 0},
+		{a: "same", b: "same", want: 1},
+		{a: "", b: "", want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+"/"+tt.b, func(t *testing.T) {
+			if got := JaroWinkler(tt.a, tt.b); math.Abs(got-tt.want) > 0.001 {
+				t.Errorf("JaroWinkler() = %.3f, want %.3f", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindBestMatch(t *testing.T) {
+	candidates := []string{"Customer No.", "Customer Name", "Address", "City"}
+	tests := []struct {
+		value     string
+		threshold float64
+		want      string
+		wantFound bool
+	}{
+		{value: "customer_no", threshold: 0.9, want: "Customer No.", wantFound: true},
+		{value: "Adress", threshold: 0.9, want: "Address", wantFound: true},
+		{value: "CUSTOMER NAME", threshold: 1, want: "Customer Name", wantFound: true},
+		{value: "Zip Code", threshold: 0.8},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, score, found := FindBestMatch(tt.value, candidates, tt.threshold)
+			if got != tt.want || found != tt.wantFound || found && score < tt.threshold {
+				t.Errorf("FindBestMatch(%q) = %q, %v, %v, want %q, %v", tt.value, got, score, found, tt.want, tt.wantFound)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package helpers
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// SlugTarget selects what Slugify makes a name safe for.
+type SlugTarget int
+
+const (
+	// FileNameSlug makes names safe as file names on Windows, macOS and Linux. It is the default.
+	FileNameSlug SlugTarget = iota
+	// SheetNameSlug makes names safe as Excel worksheet names.
+	SheetNameSlug
+)
+
+// Default lengths of the names written by Slugify.
+const (
+	// defaultFileNameLength keeps file names below the 255 bytes most file systems allow, leaving room for
+	// an extension and a numbered suffix.
+	defaultFileNameLength = 200
+	// maxSheetNameLength is the longest worksheet name Excel accepts, in characters.
+	maxSheetNameLength = 31
+)
+
+// reservedFileNames are the device names Windows reserves, whatever their extension.
+var reservedFileNames = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true,
+	"COM1": true, "COM2": true, "COM3": true, "COM4": true, "COM5": true, "COM6": true, "COM7": true, "COM8": true, "COM9": true,
+	"LPT1": true, "LPT2": true, "LPT3": true, "LPT4": true, "LPT5": true, "LPT6": true, "LPT7": true, "LPT8": true, "LPT9": true,
+}
+
+// SlugOptions configures Slugify.
+// Target selects the rules the name must follow. MaxLength caps its length, in bytes for file names and in
+// characters for worksheet names; it defaults to 200 bytes and to the 31 characters Excel allows.
+// The characters the target forbids are replaced by Replacement, "_" by default, a run of them by a single one.
+// ReplaceSpaces replaces runs of spaces too, Lower lower cases the name and ASCII transliterates it to plain
+// ASCII, replacing any character left, so "Région Nord" gives "region-nord" with all three and "-".
+type SlugOptions struct {
+	Target        SlugTarget
+	MaxLength     int
+	Replacement   string
+	ReplaceSpaces bool
+	Lower         bool
+	ASCII         bool
+}
+
+// replacement returns the string replacing forbidden characters.
+func (o SlugOptions) replacement() string {
+	if len(o.Replacement) < 1 {
+		return "_"
+	}
+	return o.Replacement
+}
+
+// forbids reports whether the target of the options forbids char.
+func (o SlugOptions) forbids(char rune) bool {
+	if unicode.IsControl(char) || o.ASCII && char > unicode.MaxASCII || o.ReplaceSpaces && unicode.IsSpace(char) {
+		return true
+	}
+	if o.Target == SheetNameSlug {
+		return strings.ContainsRune(`[]:*?/\`, char)
+	}
+	return strings.ContainsRune(`<>:"/\|?*`, char)
+}
+
+// Slugify returns value as a name safe for the target of opts: without the characters it forbids, without
+// leading or trailing spaces, trailing dots for file names and surrounding apostrophes for worksheet names,
+// at most MaxLength long, and never one of the names reserved by Windows, such as "CON" or "LPT1", or by Excel,
+// "History", which get Replacement appended. It returns "" when nothing of value is left, for callers to pick
+// a name of their own.
+// Example usage:
+//
+//	Slugify("Q1: North/South?", SlugOptions{})
+//	// Output: "Q1_ North_South_"
+func Slugify(value string, opts SlugOptions) string {
+	replacement := opts.replacement()
+	if opts.ASCII {
+		value = Transliterate(value)
+	}
+	if opts.Lower {
+		value = strings.ToLower(value)
+	}
+	var slug strings.Builder
+	replaced := false
+	for _, char := range value {
+		if opts.forbids(char) {
+			if !replaced {
+				slug.WriteString(replacement)
+			}
+			replaced = true
+			continue
+		}
+		slug.WriteRune(char)
+		replaced = false
+	}
+	name := slug.String()
+	if opts.ReplaceSpaces {
+		name = strings.Trim(name, replacement)
+	}
+	name = opts.trim(name)
+	if limit := opts.MaxLength; opts.Target == SheetNameSlug {
+		if limit < 1 || limit > maxSheetNameLength {
+			limit = maxSheetNameLength
+		}
+		if utf8.RuneCountInString(name) > limit {
+			name = opts.trim(string([]rune(name)[:limit]))
+		}
+	} else {
+		if limit < 1 {
+			limit = defaultFileNameLength
+		}
+		if len(name) > limit {
+			cut := limit
+			for cut > 0 && !utf8.RuneStart(name[cut]) {
+				cut--
+			}
+			name = opts.trim(name[:cut])
+		}
+	}
+	if len(name) > 0 && opts.reserved(name) {
+		name += replacement
+	}
+	return name
+}
+
+// trim removes the characters name must not start or end with for the target of the options.
+func (o SlugOptions) trim(name string) string {
+	name = strings.TrimFunc(name, unicode.IsSpace)
+	if o.Target == SheetNameSlug {
+		return strings.TrimFunc(strings.Trim(name, "'"), unicode.IsSpace)
+	}
+	return strings.TrimRightFunc(name, func(char rune) bool { return char == '.' || unicode.IsSpace(char) })
+}
+
+// reserved reports whether name is reserved for the target of the options.
+func (o SlugOptions) reserved(name string) bool {
+	if o.Target == SheetNameSlug {
+		return strings.EqualFold(name, "History")
+	}
+	base, _, _ := strings.Cut(name, ".")
+	return reservedFileNames[strings.ToUpper(strings.TrimSpace(base))]
+}
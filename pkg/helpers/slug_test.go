@@ -0,0 +1,42 @@
+package helpers
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSlugify(t *testing.T) {
+	slug := SlugOptions{Replacement: "-", ReplaceSpaces: true, Lower: true, ASCII: true}
+	tests := []struct {
+		value string
+		opts  SlugOptions
+		want  string
+	}{
+		{value: "Q1: North/South?", want: "Q1_ North_South_"},
+		{value: "a<>b", want: "a_b"},
+		{value: " Report. ", want: "Report"},
+		{value: "Tab\tStop", want: "Tab_Stop"},
+		{value: "con", want: "con_"},
+		{value: "LPT1.backup", want: "LPT1.backup_"},
+		{value: "Console", want: "Console"},
+		{value: "Région Nord / Sud", opts: slug, want: "region-nord-sud"},
+		{value: "Größe 名前", opts: slug, want: "grosse"},
+		{value: "***", want: "_"},
+		{value: "...", want: ""},
+		{value: "Sales [2024]: Q1/Q2", opts: SlugOptions{Target: SheetNameSlug}, want: "Sales _2024_ Q1_Q2"},
+		{value: "'Quoted'", opts: SlugOptions{Target: SheetNameSlug}, want: "Quoted"},
+		{value: "history", opts: SlugOptions{Target: SheetNameSlug}, want: "history_"},
+		{value: "Notes: \"final\"", opts: SlugOptions{Target: SheetNameSlug}, want: "Notes_ \"final\""},
+		{value: strings.Repeat("é", 40), opts: SlugOptions{Target: SheetNameSlug}, want: strings.Repeat("é", 31)},
+		{value: "Regional sales figures", opts: SlugOptions{Target: SheetNameSlug, MaxLength: 9}, want: "Regional"},
+		{value: strings.Repeat("é", 150), want: strings.Repeat("é", 100)},
+		{value: "abcé", opts: SlugOptions{MaxLength: 4}, want: "abc"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := Slugify(tt.value, tt.opts); got != tt.want {
+				t.Errorf("Slugify(%q, %+v) = %q, want %q", tt.value, tt.opts, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package helpers
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// transliterations spells the Latin letters which do not decompose into a base letter and diacritics in ASCII.
+var transliterations = map[rune]string{
+	'ß': "ss", 'ẞ': "SS", 'æ': "ae", 'Æ': "AE", 'œ': "oe", 'Œ': "OE",
+	'ø': "o", 'Ø': "O", 'đ': "d", 'Đ': "D", 'ð': "d", 'Ð': "D",
+	'þ': "th", 'Þ': "Th", 'ł': "l", 'Ł': "L", 'ı': "i", 'ħ': "h", 'Ħ': "H",
+}
+
+// Transliterate spells the accented and special Latin letters of value in plain ASCII letters, such as
+// "é" as "e" and "ß" as "ss", so French or German headers keep readable names where only ASCII is wanted.
+// Diacritics are only removed from Latin letters; other scripts are left as they are.
+// Example usage:
+//
+//	Transliterate("Größe à Noël")
+//	// Output: "Grosse a Noel"
+func Transliterate(value string) string {
+	var plain strings.Builder
+	latin := false
+	for _, char := range norm.NFD.String(value) {
+		if unicode.Is(unicode.Mn, char) {
+			if !latin {
+				plain.WriteRune(char)
+			}
+			continue
+		}
+		latin = unicode.Is(unicode.Latin, char)
+		if replacement, known := transliterations[char]; known {
+			plain.WriteString(replacement)
+			continue
+		}
+		plain.WriteRune(char)
+	}
+	return norm.NFC.String(plain.String())
+}
@@ -0,0 +1,29 @@
+package helpers
+
+import "testing"
+
+func TestTransliterate(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{value: "Größe à Noël", want: "Grosse a Noel"},
+		{value: "Société Générale", want: "Societe Generale"},
+		{value: "Æble Øre Łódź", want: "AEble Ore Lodz"},
+		{value: "Straße", want: "Strasse"},
+		{value: "Plain", want: "Plain"},
+		{value: "名前", want: "名前"},
+		{value: "한국어", want: "한국어"},
+		{value: "हिन्दी", want: "हिन्दी"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := Transliterate(tt.value); got != tt.want {
+				t.Errorf("Transliterate(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+	if got := FixXMLTagsWith("Größe (m²)", TagOptions{Transliterate: true}); got != "Grosse_x0020_m" {
+		t.Errorf("FixXMLTagsWith() = %q, want Grosse_x0020_m", got)
+	}
+}
@@ -0,0 +1,51 @@
+package helpers
+
+import (
+	"strings"
+	"unicode"
+)
+
+// CleanOptions configures CleanValue.
+// KeepLineBreaks keeps the line breaks of multi-line cells, collapsing the whitespace within each line and
+// dropping blank lines; every line break becomes a space otherwise.
+type CleanOptions struct {
+	KeepLineBreaks bool
+}
+
+// isInvisible reports whether char is a format character, such as a zero-width space, a zero-width joiner,
+// a byte order mark, a soft hyphen or a direction mark, or a control character other than whitespace.
+func isInvisible(char rune) bool {
+	return unicode.Is(unicode.Cf, char) || unicode.IsControl(char) && !unicode.IsSpace(char)
+}
+
+// CleanValue removes the invisible characters which make equal looking values differ: it drops zero-width and
+// control characters, turns non-breaking and other Unicode spaces into plain spaces, collapses runs of
+// whitespace into a single space and trims the value.
+// Example usage:
+//
+//	CleanValue("\u00A0Acme\u200B  Corp\t\n", CleanOptions{})
+//	// Output: "Acme Corp"
+func CleanValue(value string, opts CleanOptions) string {
+	var cleaned strings.Builder
+	pending := ""
+	for _, char := range value {
+		switch {
+		case isInvisible(char):
+			continue
+		case opts.KeepLineBreaks && (char == '\n' || char == '\r' || char == '\u2028'):
+			if cleaned.Len() > 0 {
+				pending = "\n"
+			}
+			continue
+		case unicode.IsSpace(char):
+			if cleaned.Len() > 0 && pending == "" {
+				pending = " "
+			}
+			continue
+		}
+		cleaned.WriteString(pending)
+		pending = ""
+		cleaned.WriteRune(char)
+	}
+	return cleaned.String()
+}
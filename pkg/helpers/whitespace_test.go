@@ -0,0 +1,30 @@
+package helpers
+
+import "testing"
+
+func TestCleanValue(t *testing.T) {
+	tests := []struct {
+		value string
+		opts  CleanOptions
+		want  string
+	}{
+		{value: "  Acme  ", want: "Acme"},
+		{value: "Acme\u00A0Corp", want: "Acme Corp"},
+		{value: "Acme\u200B Corp", want: "Acme Corp"},
+		{value: "\uFEFFId", want: "Id"},
+		{value: "Ac\u00ADme", want: "Acme"},
+		{value: "Acme\u0007\u0000", want: "Acme"},
+		{value: "Acme \t\u2003 Corp", want: "Acme Corp"},
+		{value: "Line one \r\n\r\n  line two ", want: "Line one line two"},
+		{value: "Line one \r\n\r\n  line two ", opts: CleanOptions{KeepLineBreaks: true}, want: "Line one\nline two"},
+		{value: "\n\nLine\n", opts: CleanOptions{KeepLineBreaks: true}, want: "Line"},
+		{value: "\u200B\u00A0", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := CleanValue(tt.value, tt.opts); got != tt.want {
+				t.Errorf("CleanValue(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
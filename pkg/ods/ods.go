@@ -0,0 +1,328 @@
+// Package ods reads the cell values of OpenDocument spreadsheets (.ods), as written by LibreOffice and OpenOffice.
+// Only what a conversion needs is decoded: sheet names and visibility, the rows and columns hidden, cell values
+// with the text they are displayed as, and merged cell ranges. Formulas are read as the result stored with them.
+package ods
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ErrEncrypted is returned for documents protected with a password.
+var ErrEncrypted = errors.New("encrypted OpenDocument spreadsheets are not supported")
+
+const (
+	officeNamespace = "urn:oasis:names:tc:opendocument:xmlns:office:1.0"
+	tableNamespace  = "urn:oasis:names:tc:opendocument:xmlns:table:1.0"
+	textNamespace   = "urn:oasis:names:tc:opendocument:xmlns:text:1.0"
+	styleNamespace  = "urn:oasis:names:tc:opendocument:xmlns:style:1.0"
+)
+
+// Cell is a single non-empty cell of a sheet, with zero-based coordinates.
+// Type is the office:value-type of the cell, such as float, currency, percentage, date, time, boolean or string.
+// Value is its machine-readable value: the number of numeric cells, the ISO 8601 date of date cells, the ISO 8601
+// duration of time cells, true or false for boolean cells and empty for strings.
+// Text is the text the cell is displayed as, lines being separated with a newline.
+type Cell struct {
+	Row, Col int
+	Type     string
+	Value    string
+	Text     string
+}
+
+// MergedRange is a merged cell range with zero-based, inclusive coordinates.
+type MergedRange struct {
+	FirstRow, LastRow int
+	FirstCol, LastCol int
+}
+
+// Span is a run of rows or columns with zero-based, inclusive bounds.
+type Span struct {
+	First, Last int
+}
+
+// Sheet is a single sheet and the cells it holds, in row order.
+// HiddenRows and HiddenCols list the runs of rows and columns collapsed or filtered out of the sheet.
+type Sheet struct {
+	Name       string
+	Hidden     bool
+	Cells      []Cell
+	Merged     []MergedRange
+	HiddenRows []Span
+	HiddenCols []Span
+}
+
+// Workbook holds the sheets of a spreadsheet, in tab order.
+type Workbook struct {
+	Sheets []Sheet
+}
+
+// Open reads the spreadsheet at path.
+func Open(path string) (*Workbook, error) {
+	reader, openErr := zip.OpenReader(path)
+	if openErr != nil {
+		return nil, openErr
+	}
+	defer func(reader *zip.ReadCloser) {
+		_ = reader.Close()
+	}(reader)
+	return read(&reader.Reader)
+}
+
+// Read reads a spreadsheet from r, which holds size bytes.
+func Read(r io.ReaderAt, size int64) (*Workbook, error) {
+	reader, zipErr := zip.NewReader(r, size)
+	if zipErr != nil {
+		return nil, zipErr
+	}
+	return read(reader)
+}
+
+func read(archive *zip.Reader) (*Workbook, error) {
+	var content *zip.File
+	for _, file := range archive.File {
+		switch file.Name {
+		case "content.xml":
+			content = file
+		case "META-INF/manifest.xml":
+			encrypted, manifestErr := isEncrypted(file)
+			if manifestErr != nil {
+				return nil, manifestErr
+			}
+			if encrypted {
+				return nil, ErrEncrypted
+			}
+		}
+	}
+	if content == nil {
+		return nil, errors.New("not an OpenDocument spreadsheet, content.xml is missing")
+	}
+	stream, openErr := content.Open()
+	if openErr != nil {
+		return nil, openErr
+	}
+	defer func(stream io.ReadCloser) {
+		_ = stream.Close()
+	}(stream)
+	return parseContent(xml.NewDecoder(stream))
+}
+
+// isEncrypted reports whether the manifest declares encrypted entries.
+func isEncrypted(manifest *zip.File) (bool, error) {
+	stream, openErr := manifest.Open()
+	if openErr != nil {
+		return false, openErr
+	}
+	defer func(stream io.ReadCloser) {
+		_ = stream.Close()
+	}(stream)
+	decoder := xml.NewDecoder(stream)
+	for {
+		token, tokenErr := decoder.Token()
+		if tokenErr == io.EOF {
+			return false, nil
+		}
+		if tokenErr != nil {
+			return false, tokenErr
+		}
+		if start, isStart := token.(xml.StartElement); isStart && start.Name.Local == "encryption-data" {
+			return true, nil
+		}
+	}
+}
+
+// attr returns the value of the attribute of element in the given namespace, or an empty string.
+func attr(element xml.StartElement, space, local string) string {
+	for _, attribute := range element.Attr {
+		if attribute.Name.Space == space && attribute.Name.Local == local {
+			return attribute.Value
+		}
+	}
+	return ""
+}
+
+// repeat returns the count held in the named attribute, which defaults to one.
+func repeat(element xml.StartElement, local string) int {
+	count, err := strconv.Atoi(attr(element, tableNamespace, local))
+	if err != nil || count < 1 {
+		return 1
+	}
+	return count
+}
+
+// sheetParser tracks the position within the sheet being decoded. column counts the column definitions read.
+type sheetParser struct {
+	sheet  *Sheet
+	row    int
+	col    int
+	column int
+}
+
+// hidden reports whether the table:visibility of element hides its rows or columns.
+func hidden(element xml.StartElement) bool {
+	visibility := attr(element, tableNamespace, "visibility")
+	return visibility == "collapse" || visibility == "filter"
+}
+
+// parseContent decodes the sheets of content.xml. Rows and cells repeated over the empty remainder of a sheet
+// are only counted, never materialized.
+func parseContent(decoder *xml.Decoder) (*Workbook, error) {
+	book := &Workbook{}
+	hiddenStyles := make(map[string]bool)
+	var styleName string
+	var parser *sheetParser
+	var rowRepeat int
+	for {
+		token, tokenErr := decoder.Token()
+		if tokenErr == io.EOF {
+			return book, nil
+		}
+		if tokenErr != nil {
+			return nil, fmt.Errorf("reading content.xml: %w", tokenErr)
+		}
+		switch element := token.(type) {
+		case xml.StartElement:
+			switch {
+			case element.Name.Space == styleNamespace && element.Name.Local == "style":
+				styleName = attr(element, styleNamespace, "name")
+			case element.Name.Space == styleNamespace && element.Name.Local == "table-properties":
+				if attr(element, tableNamespace, "display") == "false" {
+					hiddenStyles[styleName] = true
+				}
+			case element.Name.Space != tableNamespace:
+			case element.Name.Local == "table":
+				book.Sheets = append(book.Sheets, Sheet{
+					Name:   attr(element, tableNamespace, "name"),
+					Hidden: hiddenStyles[attr(element, tableNamespace, "style-name")],
+				})
+				parser = &sheetParser{sheet: &book.Sheets[len(book.Sheets)-1]}
+			case element.Name.Local == "table-column" && parser != nil:
+				colRepeat := repeat(element, "number-columns-repeated")
+				if hidden(element) {
+					parser.sheet.HiddenCols = append(parser.sheet.HiddenCols, Span{First: parser.column, Last: parser.column + colRepeat - 1})
+				}
+				parser.column += colRepeat
+			case element.Name.Local == "table-row" && parser != nil:
+				rowRepeat = repeat(element, "number-rows-repeated")
+				parser.col = 0
+				if hidden(element) {
+					parser.sheet.HiddenRows = append(parser.sheet.HiddenRows, Span{First: parser.row, Last: parser.row + rowRepeat - 1})
+				}
+			case (element.Name.Local == "table-cell" || element.Name.Local == "covered-table-cell") && parser != nil:
+				if cellErr := parser.parseCell(decoder, element, rowRepeat); cellErr != nil {
+					return nil, cellErr
+				}
+			}
+		case xml.EndElement:
+			if element.Name.Space == tableNamespace && element.Name.Local == "table-row" && parser != nil {
+				parser.row += rowRepeat
+			}
+			if element.Name.Space == tableNamespace && element.Name.Local == "table" && parser != nil {
+				// Cells repeated over several rows are appended together, restore the row order.
+				sort.SliceStable(parser.sheet.Cells, func(i, j int) bool {
+					return parser.sheet.Cells[i].Row < parser.sheet.Cells[j].Row
+				})
+				parser = nil
+			}
+		}
+	}
+}
+
+// parseCell decodes a cell element and its content, repeating non-empty cells over the repeated columns and rows.
+func (p *sheetParser) parseCell(decoder *xml.Decoder, element xml.StartElement, rowRepeat int) error {
+	colRepeat := repeat(element, "number-columns-repeated")
+	cell := Cell{Row: p.row, Col: p.col, Type: attr(element, officeNamespace, "value-type")}
+	switch cell.Type {
+	case "float", "percentage", "currency":
+		cell.Value = attr(element, officeNamespace, "value")
+	case "date":
+		cell.Value = attr(element, officeNamespace, "date-value")
+	case "time":
+		cell.Value = attr(element, officeNamespace, "time-value")
+	case "boolean":
+		cell.Value = attr(element, officeNamespace, "boolean-value")
+	}
+	if element.Name.Local == "table-cell" {
+		rowSpan, colSpan := repeat(element, "number-rows-spanned"), repeat(element, "number-columns-spanned")
+		if rowSpan > 1 || colSpan > 1 {
+			p.sheet.Merged = append(p.sheet.Merged, MergedRange{
+				FirstRow: p.row, LastRow: p.row + rowSpan - 1,
+				FirstCol: p.col, LastCol: p.col + colSpan - 1,
+			})
+		}
+	}
+	text, textErr := readText(decoder)
+	if textErr != nil {
+		return textErr
+	}
+	cell.Text = text
+	if len(cell.Text) > 0 || len(cell.Value) > 0 {
+		for row := 0; row < rowRepeat; row++ {
+			for col := 0; col < colRepeat; col++ {
+				repeated := cell
+				repeated.Row, repeated.Col = p.row+row, p.col+col
+				p.sheet.Cells = append(p.sheet.Cells, repeated)
+			}
+		}
+	}
+	p.col += colRepeat
+	return nil
+}
+
+// readText reads the paragraphs of a cell up to the end of the cell element, joining them with newlines.
+// Annotations attached to the cell are skipped.
+func readText(decoder *xml.Decoder) (string, error) {
+	var text strings.Builder
+	paragraphs, inParagraph := 0, 0
+	depth, skip := 0, 0
+	for {
+		token, tokenErr := decoder.Token()
+		if tokenErr != nil {
+			return "", fmt.Errorf("reading content.xml: %w", tokenErr)
+		}
+		switch element := token.(type) {
+		case xml.StartElement:
+			depth++
+			switch {
+			case skip > 0 || element.Name.Space == officeNamespace && element.Name.Local == "annotation":
+				skip++
+			case element.Name.Space != textNamespace:
+			case element.Name.Local == "p" || element.Name.Local == "h":
+				if paragraphs++; paragraphs > 1 {
+					text.WriteByte('\n')
+				}
+				inParagraph++
+			case element.Name.Local == "s":
+				count, err := strconv.Atoi(attr(element, textNamespace, "c"))
+				if err != nil || count < 1 {
+					count = 1
+				}
+				text.WriteString(strings.Repeat(" ", count))
+			case element.Name.Local == "tab":
+				text.WriteByte('\t')
+			case element.Name.Local == "line-break":
+				text.WriteByte('\n')
+			}
+		case xml.EndElement:
+			if depth == 0 {
+				return text.String(), nil
+			}
+			depth--
+			if skip > 0 {
+				skip--
+			} else if element.Name.Space == textNamespace && (element.Name.Local == "p" || element.Name.Local == "h") {
+				inParagraph--
+			}
+		case xml.CharData:
+			if skip == 0 && inParagraph > 0 {
+				text.Write(element)
+			}
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package ods
+
+import (
+	"archive/zip"
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+const testContent = `<?xml version="1.0" encoding="UTF-8"?>
+<office:document-content
+    xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0"
+    xmlns:style="urn:oasis:names:tc:opendocument:xmlns:style:1.0"
+    xmlns:table="urn:oasis:names:tc:opendocument:xmlns:table:1.0"
+    xmlns:text="urn:oasis:names:tc:opendocument:xmlns:text:1.0">
+  <office:automatic-styles>
+    <style:style style:name="ta2" style:family="table"><style:table-properties table:display="false"/></style:style>
+  </office:automatic-styles>
+  <office:body>
+    <office:spreadsheet>
+      <table:table table:name="People">
+        <table:table-column/>
+        <table:table-column table:visibility="collapse" table:number-columns-repeated="2"/>
+        <table:table-row>
+          <table:table-cell office:value-type="string"><text:p>Name</text:p></table:table-cell>
+          <table:table-cell office:value-type="string"><text:p>Joined</text:p></table:table-cell>
+          <table:table-cell office:value-type="string"><text:p>Score</text:p></table:table-cell>
+        </table:table-row>
+        <table:table-row>
+          <table:table-cell office:value-type="string" table:number-columns-spanned="2"><text:p>Ann<text:s text:c="2"/>Lee</text:p><text:p>second <text:span>line</text:span></text:p></table:table-cell>
+          <table:covered-table-cell/>
+          <table:table-cell office:value-type="float" office:value="1234.5"><text:p>1,234.50</text:p></table:table-cell>
+        </table:table-row>
+        <table:table-row table:number-rows-repeated="2" table:visibility="filter">
+          <table:table-cell/>
+          <table:table-cell office:value-type="date" office:date-value="2006-01-02"><text:p>01/02/06</text:p><office:annotation><text:p>note</text:p></office:annotation></table:table-cell>
+          <table:table-cell office:value-type="boolean" office:boolean-value="true"><text:p>TRUE</text:p></table:table-cell>
+        </table:table-row>
+        <table:table-row table:number-rows-repeated="1048570"><table:table-cell table:number-columns-repeated="1024"/></table:table-row>
+      </table:table>
+      <table:table table:name="Hidden" table:style-name="ta2">
+        <table:table-row><table:table-cell office:value-type="percentage" office:value="0.25"><text:p>25%</text:p></table:table-cell></table:table-row>
+      </table:table>
+    </office:spreadsheet>
+  </office:body>
+</office:document-content>`
+
+func TestRead(t *testing.T) {
+	var archive bytes.Buffer
+	writer := zip.NewWriter(&archive)
+	for name, content := range map[string]string{"mimetype": "application/vnd.oasis.opendocument.spreadsheet", "content.xml": testContent} {
+		entry, err := writer.Create(name)
+		if err != nil {
+			t.Fatalf("Error creating test archive: %v", err)
+		}
+		if _, err = entry.Write([]byte(content)); err != nil {
+			t.Fatalf("Error writing test archive: %v", err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Error closing test archive: %v", err)
+	}
+
+	book, err := Read(bytes.NewReader(archive.Bytes()), int64(archive.Len()))
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	want := &Workbook{Sheets: []Sheet{
+		{
+			Name: "People",
+			Cells: []Cell{
+				{Row: 0, Col: 0, Type: "string", Text: "Name"},
+				{Row: 0, Col: 1, Type: "string", Text: "Joined"},
+				{Row: 0, Col: 2, Type: "string", Text: "Score"},
+				{Row: 1, Col: 0, Type: "string", Text: "Ann  Lee\nsecond line"},
+				{Row: 1, Col: 2, Type: "float", Value: "1234.5", Text: "1,234.50"},
+				{Row: 2, Col: 1, Type: "date", Value: "2006-01-02", Text: "01/02/06"},
+				{Row: 2, Col: 2, Type: "boolean", Value: "true", Text: "TRUE"},
+				{Row: 3, Col: 1, Type: "date", Value: "2006-01-02", Text: "01/02/06"},
+				{Row: 3, Col: 2, Type: "boolean", Value: "true", Text: "TRUE"},
+			},
+			Merged:     []MergedRange{{FirstRow: 1, LastRow: 1, FirstCol: 0, LastCol: 1}},
+			HiddenRows: []Span{{First: 2, Last: 3}},
+			HiddenCols: []Span{{First: 1, Last: 2}},
+		},
+		{Name: "Hidden", Hidden: true, Cells: []Cell{{Type: "percentage", Value: "0.25", Text: "25%"}}},
+	}}
+	if !reflect.DeepEqual(book, want) {
+		t.Errorf("Read() = %+v, want %+v", book, want)
+	}
+}
@@ -0,0 +1,257 @@
+package transforms
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// SplitOptions configures splitting a column. The values are split at Delimiter into at most as many parts as
+// there are columns, the last part keeping the rest, or matched by Pattern, each of its groups filling a column;
+// values Pattern does not match leave the columns blank. The parts are trimmed of leading and trailing
+// whitespace. Keep keeps the split column before the new ones.
+type SplitOptions struct {
+	Delimiter string
+	Pattern   *regexp.Regexp
+	Keep      bool
+}
+
+// Split adds a stage splitting the column called name into the columns called into, which take its place, such as
+// "Last, First" into Last and First.
+//
+//	pipeline.Split("Name", []string{"Last", "First"}, transforms.SplitOptions{Delimiter: ","})
+func (p *Pipeline) Split(name string, into []string, opts SplitOptions) *Pipeline {
+	return p.add(splitStage{column: name, into: into, opts: opts})
+}
+
+// MergeOptions configures merging columns. Format writes the merged value, the columns being written as their
+// name in braces, such as "{Last}, {First}", and braces as "{{" and "}}"; without it the values which are not
+// blank are joined with Separator, a space by default. Keep keeps the merged columns after the new one.
+type MergeOptions struct {
+	Format    string
+	Separator string
+	Keep      bool
+}
+
+// Merge adds a stage merging the columns called names into the column called into, which takes the place of the
+// first of them, such as First and Last into Name.
+//
+//	pipeline.Merge([]string{"First", "Last"}, "Name", transforms.MergeOptions{Format: "{Last}, {First}"})
+func (p *Pipeline) Merge(names []string, into string, opts MergeOptions) *Pipeline {
+	return p.add(mergeStage{columns: names, into: into, opts: opts})
+}
+
+// checkNewColumns fails when a column of names is in header, but for the columns at the positions in replaced, or
+// twice in names.
+func checkNewColumns(header, names []string, replaced map[int]bool) error {
+	seen := make(map[string]bool, len(header)+len(names))
+	for index, name := range header {
+		if !replaced[index] {
+			seen[name] = true
+		}
+	}
+	for _, name := range names {
+		if len(name) < 1 {
+			return errors.New("a new column has no name")
+		}
+		if seen[name] {
+			return fmt.Errorf("the column '%s' already exists", name)
+		}
+		seen[name] = true
+	}
+	return nil
+}
+
+// splitStage splits a column into several.
+type splitStage struct {
+	column string
+	into   []string
+	opts   SplitOptions
+}
+
+func (s splitStage) start(header []string) ([]string, []int, func([]string) ([]string, error), error) {
+	index, err := position(header, s.column)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	switch {
+	case len(s.into) < 1:
+		return nil, nil, nil, fmt.Errorf("no columns to split '%s' into", s.column)
+	case (len(s.opts.Delimiter) > 0) == (s.opts.Pattern != nil):
+		return nil, nil, nil, fmt.Errorf("splitting '%s' takes a delimiter or a pattern", s.column)
+	case s.opts.Pattern != nil && s.opts.Pattern.NumSubexp() != len(s.into):
+		return nil, nil, nil, fmt.Errorf("the pattern splitting '%s' has %d groups for %d columns", s.column, s.opts.Pattern.NumSubexp(), len(s.into))
+	}
+	if err := checkNewColumns(header, s.into, map[int]bool{index: !s.opts.Keep}); err != nil {
+		return nil, nil, nil, err
+	}
+	after := index
+	if s.opts.Keep {
+		after++
+	}
+	next := append(append(append([]string(nil), header[:after]...), s.into...), header[index+1:]...)
+	sources := make([]int, len(next))
+	for position := range sources {
+		switch {
+		case position < after:
+			sources[position] = position
+		case position < after+len(s.into):
+			sources[position] = index
+		default:
+			sources[position] = position - after - len(s.into) + index + 1
+		}
+	}
+	return next, sources, func(values []string) ([]string, error) {
+		var value string
+		if index < len(values) {
+			value = values[index]
+		}
+		parts := make([]string, len(s.into))
+		if s.opts.Pattern != nil {
+			if match := s.opts.Pattern.FindStringSubmatch(value); match != nil {
+				copy(parts, match[1:])
+			}
+		} else if len(strings.TrimSpace(value)) > 0 {
+			copy(parts, strings.SplitN(value, s.opts.Delimiter, len(s.into)))
+		}
+		result := append(make([]string, 0, len(next)), values[:min(after, len(values))]...)
+		for len(result) < after {
+			result = append(result, "")
+		}
+		for _, part := range parts {
+			result = append(result, strings.TrimSpace(part))
+		}
+		if index+1 < len(values) {
+			result = append(result, values[index+1:]...)
+		}
+		return result, nil
+	}, nil
+}
+
+// mergeStage merges several columns into one.
+type mergeStage struct {
+	columns []string
+	into    string
+	opts    MergeOptions
+}
+
+// formatPart is a part of the format of a merge: literal text, or the value of the column at field when field is
+// not negative.
+type formatPart struct {
+	text  string
+	field int
+}
+
+// parseFormat returns the parts of format, whose columns are at the positions of indexes by name.
+func parseFormat(format string, indexes map[string]int) ([]formatPart, error) {
+	var parts []formatPart
+	var text strings.Builder
+	for rest := format; len(rest) > 0; {
+		switch {
+		case strings.HasPrefix(rest, "{{"), strings.HasPrefix(rest, "}}"):
+			text.WriteByte(rest[0])
+			rest = rest[2:]
+		case rest[0] == '{':
+			end := strings.IndexByte(rest, '}')
+			if end < 0 {
+				return nil, fmt.Errorf("the format '%s' has an unclosed brace", format)
+			}
+			field, known := indexes[rest[1:end]]
+			if !known {
+				return nil, fmt.Errorf("the format '%s' writes the column '%s', which is not merged", format, rest[1:end])
+			}
+			parts = append(parts, formatPart{text: text.String(), field: -1}, formatPart{field: field})
+			text.Reset()
+			rest = rest[end+1:]
+		case rest[0] == '}':
+			return nil, fmt.Errorf("the format '%s' has an unopened brace", format)
+		default:
+			text.WriteByte(rest[0])
+			rest = rest[1:]
+		}
+	}
+	return append(parts, formatPart{text: text.String(), field: -1}), nil
+}
+
+func (s mergeStage) start(header []string) ([]string, []int, func([]string) ([]string, error), error) {
+	if len(s.columns) < 1 {
+		return nil, nil, nil, fmt.Errorf("no columns to merge into '%s'", s.into)
+	}
+	fields := make([]int, len(s.columns))
+	indexes := make(map[string]int, len(s.columns))
+	merged := make(map[int]bool, len(s.columns))
+	for at, name := range s.columns {
+		index, err := position(header, name)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		fields[at] = index
+		indexes[name] = index
+		merged[index] = !s.opts.Keep
+	}
+	var parts []formatPart
+	if len(s.opts.Format) > 0 {
+		var err error
+		if parts, err = parseFormat(s.opts.Format, indexes); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	if err := checkNewColumns(header, []string{s.into}, merged); err != nil {
+		return nil, nil, nil, err
+	}
+	separator := s.opts.Separator
+	if len(separator) < 1 {
+		separator = " "
+	}
+	first := fields[0]
+	for _, index := range fields {
+		first = min(first, index)
+	}
+	next := make([]string, 0, len(header)+1)
+	sources := make([]int, 0, len(header)+1)
+	for index, name := range header {
+		if index == first {
+			next, sources = append(next, s.into), append(sources, fields[0])
+		}
+		if !merged[index] {
+			next, sources = append(next, name), append(sources, index)
+		}
+	}
+	value := func(values []string, index int) string {
+		if index < len(values) {
+			return values[index]
+		}
+		return ""
+	}
+	return next, sources, func(values []string) ([]string, error) {
+		var text strings.Builder
+		if parts != nil {
+			for _, part := range parts {
+				if part.field < 0 {
+					text.WriteString(part.text)
+				} else {
+					text.WriteString(value(values, part.field))
+				}
+			}
+		} else {
+			var written []string
+			for _, index := range fields {
+				if part := value(values, index); len(strings.TrimSpace(part)) > 0 {
+					written = append(written, part)
+				}
+			}
+			text.WriteString(strings.Join(written, separator))
+		}
+		result := make([]string, 0, len(next))
+		for index := range header {
+			if index == first {
+				result = append(result, text.String())
+			}
+			if !merged[index] {
+				result = append(result, value(values, index))
+			}
+		}
+		return result, nil
+	}, nil
+}
@@ -0,0 +1,157 @@
+package transforms
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		name        string
+		into        []string
+		opts        SplitOptions
+		values      []string
+		wantHeader  []string
+		wantSources []int
+		want        []string
+	}{
+		{
+			name: "Delimiter", into: []string{"Last", "First"}, opts: SplitOptions{Delimiter: ","},
+			values: []string{"1", "Doe, Jane", "x"}, wantHeader: []string{"ID", "Last", "First", "Note"},
+			wantSources: []int{0, 1, 1, 2}, want: []string{"1", "Doe", "Jane", "x"},
+		},
+		{
+			name: "Rest In Last Part", into: []string{"Last", "First"}, opts: SplitOptions{Delimiter: " "},
+			values: []string{"1", "Doe Mary Jane", "x"}, wantHeader: []string{"ID", "Last", "First", "Note"},
+			wantSources: []int{0, 1, 1, 2}, want: []string{"1", "Doe", "Mary Jane", "x"},
+		},
+		{
+			name: "Keep", into: []string{"Last", "First"}, opts: SplitOptions{Delimiter: ",", Keep: true},
+			values: []string{"1", "Doe", "x"}, wantHeader: []string{"ID", "Name", "Last", "First", "Note"},
+			wantSources: []int{0, 1, 1, 1, 2}, want: []string{"1", "Doe", "Doe", "", "x"},
+		},
+		{
+			name: "Pattern", into: []string{"First", "Last"}, opts: SplitOptions{Pattern: regexp.MustCompile(`^(\w+) (\w+)$`)},
+			values: []string{"1", "Jane Doe", "x"}, wantHeader: []string{"ID", "First", "Last", "Note"},
+			wantSources: []int{0, 1, 1, 2}, want: []string{"1", "Jane", "Doe", "x"},
+		},
+		{
+			name: "Pattern No Match", into: []string{"First", "Last"}, opts: SplitOptions{Pattern: regexp.MustCompile(`^(\w+) (\w+)$`)},
+			values: []string{"1", "Cher", "x"}, wantHeader: []string{"ID", "First", "Last", "Note"},
+			wantSources: []int{0, 1, 1, 2}, want: []string{"1", "", "", "x"},
+		},
+		{
+			name: "Short Record", into: []string{"Last", "First"}, opts: SplitOptions{Delimiter: ","},
+			values: []string{"1"}, wantHeader: []string{"ID", "Last", "First", "Note"},
+			wantSources: []int{0, 1, 1, 2}, want: []string{"1", "", ""},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			run, err := New().Split("Name", tt.into, tt.opts).Start([]string{"ID", "Name", "Note"})
+			if err != nil {
+				t.Fatalf("Start() error = %v", err)
+			}
+			if !reflect.DeepEqual(run.Header(), tt.wantHeader) || !reflect.DeepEqual(run.Sources(), tt.wantSources) {
+				t.Errorf("Header(), Sources() = %v, %v, want %v, %v", run.Header(), run.Sources(), tt.wantHeader, tt.wantSources)
+			}
+			if got, err := run.Record(tt.values); err != nil || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Record(%v) = %v, %v, want %v", tt.values, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestMerge(t *testing.T) {
+	tests := []struct {
+		name        string
+		columns     []string
+		opts        MergeOptions
+		wantHeader  []string
+		wantSources []int
+		want        []string
+	}{
+		{
+			name: "Separator", columns: []string{"First", "Last"},
+			wantHeader: []string{"ID", "Name", "City"}, wantSources: []int{0, 1, 3}, want: []string{"1", "Jane", "Paris"},
+		},
+		{
+			name: "Format", columns: []string{"Last", "First"}, opts: MergeOptions{Format: "{Last}, {First} {{}}"},
+			wantHeader: []string{"ID", "Name", "City"}, wantSources: []int{0, 2, 3}, want: []string{"1", ", Jane {}", "Paris"},
+		},
+		{
+			name: "Keep", columns: []string{"City", "ID"}, opts: MergeOptions{Separator: "-", Keep: true},
+			wantHeader: []string{"Name", "ID", "First", "Last", "City"}, wantSources: []int{3, 0, 1, 2, 3}, want: []string{"Paris-1", "1", "Jane", "", "Paris"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			run, err := New().Merge(tt.columns, "Name", tt.opts).Start([]string{"ID", "First", "Last", "City"})
+			if err != nil {
+				t.Fatalf("Start() error = %v", err)
+			}
+			if !reflect.DeepEqual(run.Header(), tt.wantHeader) || !reflect.DeepEqual(run.Sources(), tt.wantSources) {
+				t.Errorf("Header(), Sources() = %v, %v, want %v, %v", run.Header(), run.Sources(), tt.wantHeader, tt.wantSources)
+			}
+			if got, err := run.Record([]string{"1", "Jane", "", "Paris"}); err != nil || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Record() = %v, %v, want %v", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitMergeErrors(t *testing.T) {
+	header := []string{"ID", "First", "Last"}
+	tests := []struct {
+		name     string
+		pipeline *Pipeline
+		wantErr  string
+	}{
+		{name: "No Columns", pipeline: New().Split("First", nil, SplitOptions{Delimiter: ","}), wantErr: "no columns to split"},
+		{name: "No Delimiter", pipeline: New().Split("First", []string{"A"}, SplitOptions{}), wantErr: "a delimiter or a pattern"},
+		{name: "Groups", pipeline: New().Split("First", []string{"A", "B"}, SplitOptions{Pattern: regexp.MustCompile(`(\w+)`)}), wantErr: "1 groups for 2 columns"},
+		{name: "Existing Column", pipeline: New().Split("First", []string{"ID", "B"}, SplitOptions{Delimiter: ","}), wantErr: "the column 'ID' already exists"},
+		{name: "Unknown Column", pipeline: New().Merge([]string{"First", "Middle"}, "Name", MergeOptions{}), wantErr: "unknown column 'Middle'"},
+		{name: "Not Merged", pipeline: New().Merge([]string{"First"}, "Name", MergeOptions{Format: "{First} {ID}"}), wantErr: "'ID', which is not merged"},
+		{name: "Unclosed Brace", pipeline: New().Merge([]string{"First"}, "Name", MergeOptions{Format: "{First"}), wantErr: "unclosed brace"},
+		{name: "Merged Into Kept", pipeline: New().Merge([]string{"First", "Last"}, "First", MergeOptions{Keep: true}), wantErr: "the column 'First' already exists"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.pipeline.Start(header); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Start() error = %v, want %s", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseConfigSplitMerge(t *testing.T) {
+	config := `
+stages:
+  - column: Contact
+    split: {into: [Last, First], pattern: '^(\w+), (\w+)$'}
+  - merge: {columns: [First, Last], into: Name, format: "{First} {Last}"}
+`
+	pipeline, err := ParseConfig(strings.NewReader(config))
+	if err != nil {
+		t.Fatalf("ParseConfig() error = %v", err)
+	}
+	run, err := pipeline.Start([]string{"Contact"})
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if got, err := run.Record([]string{"Doe, Jane"}); err != nil || !reflect.DeepEqual(got, []string{"Jane Doe"}) {
+		t.Errorf("Record() = %v, %v", got, err)
+	}
+	for input, wantErr := range map[string]string{
+		`{"stages": [{"column": "A", "merge": {"columns": ["A"], "into": "B"}}]}`:                           "a merge takes its columns",
+		`{"stages": [{"column": "A", "split": {"into": ["B"], "delimiter": ","}, "transforms": ["trim"]}]}`: "a split takes no transforms",
+		`{"stages": [{"column": "A", "split": {"into": ["B"], "pattern": "("}}]}`:                           "missing closing )",
+	} {
+		if _, err := ParseConfig(strings.NewReader(input)); err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Errorf("ParseConfig(%s) error = %v, want %s", input, err, wantErr)
+		}
+	}
+}
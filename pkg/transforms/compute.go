@@ -0,0 +1,73 @@
+package transforms
+
+import (
+	"fmt"
+
+	"GoTools/pkg/datatable"
+)
+
+// Compute adds a stage writing the value expression computes from the other columns of a record into the column
+// called name, such as Quantity * [Unit Price]; the column is added after the last one unless it exists. The
+// columns expression reads are text, so arithmetic reads them as numbers and date functions as dates, see
+// datatable.Expression.
+//
+//	total, _ := datatable.ParseExpression("round(Quantity * [Unit Price], 2)")
+//	pipeline.Compute("Total", total)
+func (p *Pipeline) Compute(name string, expression *datatable.Expression) *Pipeline {
+	return p.add(computeStage{column: name, expression: expression})
+}
+
+// computeStage computes the values of a column.
+type computeStage struct {
+	column     string
+	expression *datatable.Expression
+}
+
+func (s computeStage) start(header []string) ([]string, []int, func([]string) ([]string, error), error) {
+	if len(s.column) < 1 {
+		return nil, nil, nil, fmt.Errorf("no column to compute %s into", s.expression)
+	}
+	reads := s.expression.Columns()
+	fields := make([]int, len(reads))
+	for at, name := range reads {
+		index, err := position(header, name)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		fields[at] = index
+	}
+	table, err := datatable.New(reads...)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	next, sources := header, []int(nil)
+	target, err := position(header, s.column)
+	if err != nil {
+		target = len(header)
+		next = append(append(make([]string, 0, len(header)+1), header...), s.column)
+		sources = make([]int, len(next))
+		for index := range header {
+			sources[index] = index
+		}
+		if len(fields) > 0 {
+			sources[target] = fields[0]
+		}
+	}
+	return next, sources, func(values []string) ([]string, error) {
+		read := make([]string, len(fields))
+		for at, index := range fields {
+			if index < len(values) {
+				read[at] = values[index]
+			}
+		}
+		value, err := s.expression.Eval(table.Detached(read...))
+		if err != nil {
+			return nil, fmt.Errorf("column '%s': %w", s.column, err)
+		}
+		for len(values) <= target {
+			values = append(values, "")
+		}
+		values[target] = datatable.FormatValue(value)
+		return values, nil
+	}, nil
+}
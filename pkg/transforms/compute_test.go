@@ -0,0 +1,52 @@
+package transforms
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"GoTools/pkg/datatable"
+)
+
+func TestCompute(t *testing.T) {
+	config := `
+stages:
+  - column: Total
+    expression: round(Quantity * [Unit Price], 2)
+  - column: Due
+    expression: adddays(Ordered, 30)
+  - column: Size
+    expression: if(Total > 100, 'large', 'small')
+  - column: Item
+    expression: upper(Item)
+`
+	pipeline, err := ParseConfig(strings.NewReader(config))
+	if err != nil {
+		t.Fatalf("ParseConfig() error = %v", err)
+	}
+	run, err := pipeline.Start([]string{"Item", "Ordered", "Quantity", "Unit Price"})
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if want := []string{"Item", "Ordered", "Quantity", "Unit Price", "Total", "Due", "Size"}; !reflect.DeepEqual(run.Header(), want) {
+		t.Errorf("Header() = %v, want %v", run.Header(), want)
+	}
+	if want := []int{0, 1, 2, 3, 2, 1, 2}; !reflect.DeepEqual(run.Sources(), want) {
+		t.Errorf("Sources() = %v, want %v", run.Sources(), want)
+	}
+	got, err := run.Record([]string{"pen", "2024-01-15", "3", "40.333"})
+	if want := []string{"PEN", "2024-01-15", "3", "40.333", "121", "2024-02-14", "large"}; err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("Record() = %v, %v, want %v", got, err, want)
+	}
+	if _, err := run.Record([]string{"pen", "2024-01-15", "three", "1"}); err == nil || !strings.Contains(err.Error(), "column 'Total'") {
+		t.Errorf("Record() error = %v, want column 'Total'", err)
+	}
+
+	expression, _ := datatable.ParseExpression("Price * 2")
+	if _, err := New().Compute("Double", expression).Start([]string{"Item"}); err == nil || !strings.Contains(err.Error(), "unknown column 'Price'") {
+		t.Errorf("Start() error = %v, want unknown column", err)
+	}
+	if _, err := ParseConfig(strings.NewReader(`{"stages": [{"column": "A", "expression": "1 +"}]}`)); err == nil {
+		t.Error("ParseConfig() should fail for an invalid expression")
+	}
+}
@@ -0,0 +1,264 @@
+package transforms
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+
+	"GoTools/pkg/datatable"
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the configuration of a Pipeline, as written in a YAML file. JSON files are read as well, JSON being
+// YAML too.
+//
+//	stages:
+//	  - column: Name
+//	    transforms: [trim, title]
+//	  - column: Born
+//	    transforms:
+//	      - date: [date, day-first]
+//	  - column: Status
+//	    transforms:
+//	      - trim
+//	      - upper
+//	      - map: {O: Open, C: Closed}
+//	  - column: Country
+//	    transforms:
+//	      - lookup: {workbook: codes.xlsx, sheet: Countries, key: Code, value: Name, unmapped: error}
+//	  - column: Contact
+//	    split: {into: [Last, First], delimiter: ","}
+//	  - merge: {columns: [Street, City], into: Address, format: "{Street}, {City}"}
+//	  - fillDown: [Region]
+//	  - column: Total
+//	    expression: round(Quantity * [Unit Price], 2)
+type Config struct {
+	Stages []Stage `yaml:"stages" json:"stages"`
+}
+
+// Stage is the configuration of a stage of a Pipeline, which does one of five things: Transforms transform the
+// values of the column called Column, see Pipeline.Column, Split splits it, see Pipeline.Split, Expression
+// computes it, see Pipeline.Compute, Merge merges columns, see Pipeline.Merge, and FillDown fills the blanks of
+// columns, see Pipeline.FillDown.
+type Stage struct {
+	Column     string       `yaml:"column" json:"column"`
+	Transforms []Step       `yaml:"transforms" json:"transforms"`
+	Split      *SplitConfig `yaml:"split" json:"split"`
+	Expression string       `yaml:"expression" json:"expression"`
+	Merge      *MergeConfig `yaml:"merge" json:"merge"`
+	FillDown   []string     `yaml:"fillDown" json:"fillDown"`
+}
+
+// SplitConfig is the configuration of splitting a column into the columns Into at Delimiter or with the groups of
+// the regular expression Pattern, see SplitOptions.
+type SplitConfig struct {
+	Into      []string `yaml:"into" json:"into"`
+	Delimiter string   `yaml:"delimiter" json:"delimiter"`
+	Pattern   string   `yaml:"pattern" json:"pattern"`
+	Keep      bool     `yaml:"keep" json:"keep"`
+}
+
+// MergeConfig is the configuration of merging the columns Columns into the column Into, see MergeOptions.
+type MergeConfig struct {
+	Columns   []string `yaml:"columns" json:"columns"`
+	Into      string   `yaml:"into" json:"into"`
+	Format    string   `yaml:"format" json:"format"`
+	Separator string   `yaml:"separator" json:"separator"`
+	Keep      bool     `yaml:"keep" json:"keep"`
+}
+
+// Step is a transform of a Stage: the transform registered as Name, see Lookup, configured by Args, the
+// mapping of the map transform, see Map, or the table of the lookup transform, see LookupConfig. In YAML it is
+// written as the name of a transform taking no arguments, or as a mapping from the name to its argument, the
+// list of its arguments, the mapping of map or the table of lookup.
+type Step struct {
+	Name   string
+	Args   []string
+	Map    map[string]string
+	Lookup *LookupConfig
+}
+
+// LookupConfig is the configuration of the lookup transform, replacing values through a mapping table, see
+// MapWith. The table is Values, the CSV file Csv or the worksheet Sheet of the workbook Workbook, the first
+// worksheet when Sheet is left out, Values then adding to or overriding the table of the file. Key and Value name
+// the columns of the file mapping keys to values, the first two by default. Unmapped is keep, blank, error or default, see ParseUnmapped, Default being the value written
+// for unmapped values by default. Paths are relative to the working directory.
+type LookupConfig struct {
+	Values     map[string]string `yaml:"values" json:"values"`
+	Csv        string            `yaml:"csv" json:"csv"`
+	Workbook   string            `yaml:"workbook" json:"workbook"`
+	Sheet      string            `yaml:"sheet" json:"sheet"`
+	Key        string            `yaml:"key" json:"key"`
+	Value      string            `yaml:"value" json:"value"`
+	Unmapped   string            `yaml:"unmapped" json:"unmapped"`
+	Default    string            `yaml:"default" json:"default"`
+	IgnoreCase bool              `yaml:"ignoreCase" json:"ignoreCase"`
+	TrimSpace  bool              `yaml:"trimSpace" json:"trimSpace"`
+}
+
+// Func returns the transform configured by c, reading its mapping table.
+func (c LookupConfig) Func() (Func, error) {
+	unmapped, err := ParseUnmapped(c.Unmapped)
+	if err != nil {
+		return nil, err
+	}
+	mapping := c.Values
+	switch {
+	case len(c.Csv) > 0 && len(c.Workbook) > 0:
+		return nil, errors.New("the transform 'lookup' takes a CSV file or a workbook, not both")
+	case len(c.Csv) > 0:
+		mapping, err = LoadMappingCsv(c.Csv, c.Key, c.Value)
+	case len(c.Workbook) > 0:
+		mapping, err = LoadMappingSheet(c.Workbook, c.Sheet, c.Key, c.Value)
+	case mapping == nil:
+		return nil, errors.New("the transform 'lookup' takes values, a CSV file or a workbook")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(c.Csv) > 0 || len(c.Workbook) > 0 {
+		for key, value := range c.Values {
+			mapping[key] = value
+		}
+	}
+	return MapWith(mapping, LookupOptions{Unmapped: unmapped, Default: c.Default, IgnoreCase: c.IgnoreCase, TrimSpace: c.TrimSpace}), nil
+}
+
+// UnmarshalYAML reads a step written as a name or as a mapping from the name to its arguments.
+func (s *Step) UnmarshalYAML(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		return node.Decode(&s.Name)
+	case yaml.MappingNode:
+		if len(node.Content) != 2 {
+			return fmt.Errorf("line %d: a transform is a name or a mapping from a single name to its arguments", node.Line)
+		}
+		if err := node.Content[0].Decode(&s.Name); err != nil {
+			return err
+		}
+		args := node.Content[1]
+		switch {
+		case s.Name == "map":
+			return args.Decode(&s.Map)
+		case s.Name == "lookup":
+			s.Lookup = &LookupConfig{}
+			return args.Decode(s.Lookup)
+		case args.Kind == yaml.SequenceNode:
+			return args.Decode(&s.Args)
+		}
+		var value string
+		if err := args.Decode(&value); err != nil {
+			return err
+		}
+		s.Args = []string{value}
+		return nil
+	}
+	return fmt.Errorf("line %d: a transform is a name or a mapping from a single name to its arguments", node.Line)
+}
+
+// Func returns the transform of the step.
+func (s Step) Func() (Func, error) {
+	if s.Name == "map" {
+		if s.Map == nil {
+			return nil, errors.New("the transform 'map' takes a mapping")
+		}
+		return Map(s.Map), nil
+	}
+	if s.Name == "lookup" {
+		if s.Lookup == nil {
+			return nil, errors.New("the transform 'lookup' takes a mapping table")
+		}
+		return s.Lookup.Func()
+	}
+	return Lookup(s.Name, s.Args...)
+}
+
+// Pipeline returns the pipeline configured by c.
+func (c Config) Pipeline() (*Pipeline, error) {
+	pipeline := New()
+	for index, stage := range c.Stages {
+		if err := stage.add(pipeline); err != nil {
+			return nil, fmt.Errorf("stage %d: %w", index+1, err)
+		}
+	}
+	return pipeline, nil
+}
+
+// ParseConfig returns the pipeline configured by the YAML or JSON read from r, see Config.
+func ParseConfig(r io.Reader) (*Pipeline, error) {
+	var config Config
+	decoder := yaml.NewDecoder(r)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&config); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("transforms: %w", err)
+	}
+	return config.Pipeline()
+}
+
+// LoadConfig returns the pipeline configured by the YAML or JSON file at path, see Config.
+func LoadConfig(path string) (pipeline *Pipeline, loadErr error) {
+	file, openErr := os.Open(path)
+	if openErr != nil {
+		return nil, openErr
+	}
+	defer func(file *os.File) {
+		if err := file.Close(); err != nil && loadErr == nil {
+			loadErr = err
+		}
+	}(file)
+	return ParseConfig(file)
+}
+
+// add adds the stage configured by s to pipeline.
+func (s Stage) add(pipeline *Pipeline) error {
+	switch {
+	case len(s.FillDown) > 0:
+		if len(s.Column) > 0 || len(s.Transforms) > 0 || s.Split != nil || s.Merge != nil {
+			return errors.New("a fill down takes its columns and nothing else")
+		}
+		pipeline.FillDown(s.FillDown...)
+		return nil
+	case s.Merge != nil:
+		if len(s.Column) > 0 || len(s.Transforms) > 0 || s.Split != nil {
+			return errors.New("a merge takes its columns and nothing else")
+		}
+		pipeline.Merge(s.Merge.Columns, s.Merge.Into, MergeOptions{Format: s.Merge.Format, Separator: s.Merge.Separator, Keep: s.Merge.Keep})
+		return nil
+	case len(s.Column) < 1:
+		return errors.New("no column to transform")
+	case len(s.Expression) > 0:
+		if len(s.Transforms) > 0 || s.Split != nil {
+			return errors.New("a computed column takes no transforms")
+		}
+		expression, err := datatable.ParseExpression(s.Expression)
+		if err != nil {
+			return err
+		}
+		pipeline.Compute(s.Column, expression)
+		return nil
+	case s.Split != nil:
+		if len(s.Transforms) > 0 {
+			return errors.New("a split takes no transforms")
+		}
+		opts := SplitOptions{Delimiter: s.Split.Delimiter, Keep: s.Split.Keep}
+		if len(s.Split.Pattern) > 0 {
+			var err error
+			if opts.Pattern, err = regexp.Compile(s.Split.Pattern); err != nil {
+				return err
+			}
+		}
+		pipeline.Split(s.Column, s.Split.Into, opts)
+		return nil
+	}
+	funcs := make([]Func, len(s.Transforms))
+	for position, step := range s.Transforms {
+		var err error
+		if funcs[position], err = step.Func(); err != nil {
+			return err
+		}
+	}
+	pipeline.Column(s.Column, funcs...)
+	return nil
+}
@@ -0,0 +1,44 @@
+package transforms
+
+import "strings"
+
+// FillDown adds a stage filling the blank values of the columns called names with the last value above them which
+// is not blank, as in the grouped reports whose group key is only written on the first row of each block.
+//
+//	pipeline.FillDown("Region", "Account")
+func (p *Pipeline) FillDown(names ...string) *Pipeline {
+	return p.add(fillDownStage{columns: names})
+}
+
+// fillDownStage fills the blank values of columns with the last value seen.
+type fillDownStage struct {
+	columns []string
+}
+
+func (s fillDownStage) start(header []string) ([]string, []int, func([]string) ([]string, error), error) {
+	indexes := make([]int, len(s.columns))
+	for at, name := range s.columns {
+		index, err := position(header, name)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		indexes[at] = index
+	}
+	last := make([]string, len(indexes))
+	return header, nil, func(values []string) ([]string, error) {
+		for at, index := range indexes {
+			if index >= len(values) {
+				if len(last[at]) < 1 {
+					continue
+				}
+				values = append(values, make([]string, index+1-len(values))...)
+			}
+			if len(strings.TrimSpace(values[index])) > 0 {
+				last[at] = values[index]
+			} else {
+				values[index] = last[at]
+			}
+		}
+		return values, nil
+	}, nil
+}
@@ -0,0 +1,47 @@
+package transforms
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFillDown(t *testing.T) {
+	run, err := New().FillDown("Region", "Account").Start([]string{"Region", "Account", "Amount"})
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	records := [][]string{
+		{"", "", "5"},
+		{"North", "A-1", "10"},
+		{"", "", "20"},
+		{" ", "A-2", "30"},
+		{"South", "B-1", "40"},
+		{"", "", ""},
+		{},
+	}
+	want := [][]string{
+		{"", "", "5"},
+		{"North", "A-1", "10"},
+		{"North", "A-1", "20"},
+		{"North", "A-2", "30"},
+		{"South", "B-1", "40"},
+		{"South", "B-1", ""},
+		{"South", "B-1"},
+	}
+	for index, record := range records {
+		if got, err := run.Record(record); err != nil || !reflect.DeepEqual(got, want[index]) {
+			t.Errorf("Record(%q) = %q, %v, want %q", record, got, err, want[index])
+		}
+	}
+
+	if _, err := New().FillDown("Region").Start([]string{"Account"}); err == nil || !strings.Contains(err.Error(), "unknown column 'Region'") {
+		t.Errorf("Start() error = %v, want unknown column", err)
+	}
+	if pipeline, err := ParseConfig(strings.NewReader(`{"stages": [{"fillDown": ["Region"]}]}`)); err != nil || pipeline.Len() != 1 {
+		t.Errorf("ParseConfig() = %v, %v", pipeline, err)
+	}
+	if _, err := ParseConfig(strings.NewReader(`{"stages": [{"column": "A", "fillDown": ["Region"]}]}`)); err == nil {
+		t.Error("ParseConfig() should fail for a fill down with a column")
+	}
+}
@@ -0,0 +1,183 @@
+package transforms
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Unmapped is what a lookup does with the values its mapping lacks.
+type Unmapped int
+
+const (
+	// KeepUnmapped keeps the value as it is.
+	KeepUnmapped Unmapped = iota
+	// BlankUnmapped empties the value.
+	BlankUnmapped
+	// FailUnmapped fails the conversion.
+	FailUnmapped
+	// DefaultUnmapped replaces the value with the default of the lookup.
+	DefaultUnmapped
+)
+
+// ParseUnmapped returns the behaviour called keep, blank, error or default; an empty name keeps the value.
+func ParseUnmapped(name string) (Unmapped, error) {
+	switch strings.ToLower(name) {
+	case "", "keep":
+		return KeepUnmapped, nil
+	case "blank":
+		return BlankUnmapped, nil
+	case "error", "fail":
+		return FailUnmapped, nil
+	case "default":
+		return DefaultUnmapped, nil
+	}
+	return KeepUnmapped, fmt.Errorf("unknown unmapped behaviour '%s'", name)
+}
+
+// LookupOptions configures a lookup. Unmapped decides what becomes of the values the mapping lacks, Default
+// being the value DefaultUnmapped writes. IgnoreCase and TrimSpace match the values to the keys of the mapping
+// regardless of case and of leading and trailing whitespace.
+type LookupOptions struct {
+	Unmapped   Unmapped
+	Default    string
+	IgnoreCase bool
+	TrimSpace  bool
+}
+
+// key returns value as it is matched to the keys of a mapping.
+func (o LookupOptions) key(value string) string {
+	if o.TrimSpace {
+		value = strings.TrimSpace(value)
+	}
+	if o.IgnoreCase {
+		value = strings.ToLower(value)
+	}
+	return value
+}
+
+// MapWith returns a transform replacing the values found in mapping with their mapped value, such as codes with
+// their description, and the others as opts.Unmapped says. Blank values are kept unless they are mapped.
+func MapWith(mapping map[string]string, opts LookupOptions) Func {
+	keyed := make(map[string]string, len(mapping))
+	for key, mapped := range mapping {
+		keyed[opts.key(key)] = mapped
+	}
+	return func(value string) (string, error) {
+		if mapped, found := keyed[opts.key(value)]; found {
+			return mapped, nil
+		}
+		if len(strings.TrimSpace(value)) < 1 {
+			return value, nil
+		}
+		switch opts.Unmapped {
+		case BlankUnmapped:
+			return "", nil
+		case FailUnmapped:
+			return "", fmt.Errorf("'%s' is not mapped", value)
+		case DefaultUnmapped:
+			return opts.Default, nil
+		}
+		return value, nil
+	}
+}
+
+// mappingTable returns the mapping of the rows of a table, whose first row is the header, from the values of its
+// column called key to those of its column called value; the first two columns by default. Blank keys are skipped
+// and keys mapped to two different values fail.
+func mappingTable(rows [][]string, key, value string) (map[string]string, error) {
+	if len(rows) < 1 {
+		return nil, errors.New("the mapping table has no header")
+	}
+	keyIndex, valueIndex := 0, 1
+	var err error
+	if len(key) > 0 {
+		if keyIndex, err = position(rows[0], key); err != nil {
+			return nil, err
+		}
+	}
+	if len(value) > 0 {
+		if valueIndex, err = position(rows[0], value); err != nil {
+			return nil, err
+		}
+	}
+	if keyIndex >= len(rows[0]) || valueIndex >= len(rows[0]) {
+		return nil, errors.New("the mapping table needs a key column and a value column")
+	}
+	mapping := make(map[string]string, len(rows)-1)
+	for index, row := range rows[1:] {
+		if keyIndex >= len(row) || len(strings.TrimSpace(row[keyIndex])) < 1 {
+			continue
+		}
+		var mapped string
+		if valueIndex < len(row) {
+			mapped = row[valueIndex]
+		}
+		if previous, found := mapping[row[keyIndex]]; found && previous != mapped {
+			return nil, fmt.Errorf("row %d: '%s' is mapped to '%s' and '%s'", index+2, row[keyIndex], previous, mapped)
+		}
+		mapping[row[keyIndex]] = mapped
+	}
+	return mapping, nil
+}
+
+// ReadMappingCsv returns the mapping of the CSV read from r, whose first row is the header, from the values of its
+// column called key to those of its column called value; the first two columns when they are left out.
+func ReadMappingCsv(r io.Reader, key, value string) (map[string]string, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	return mappingTable(rows, key, value)
+}
+
+// LoadMappingCsv returns the mapping of the CSV file at path, see ReadMappingCsv.
+func LoadMappingCsv(path, key, value string) (mapping map[string]string, loadErr error) {
+	file, openErr := os.Open(path)
+	if openErr != nil {
+		return nil, openErr
+	}
+	defer func(file *os.File) {
+		if err := file.Close(); err != nil && loadErr == nil {
+			loadErr = err
+		}
+	}(file)
+	if mapping, loadErr = ReadMappingCsv(file, key, value); loadErr != nil {
+		return nil, fmt.Errorf("%s: %w", path, loadErr)
+	}
+	return mapping, nil
+}
+
+// LoadMappingSheet returns the mapping of the worksheet called sheet of the workbook at path, the first worksheet
+// when sheet is left out, such as a sheet of codes next to the data of the workbook being converted. Its first row
+// is the header, and the mapping is from the values of its column called key to those of its column called value;
+// the first two columns when they are left out.
+func LoadMappingSheet(path, sheet, key, value string) (mapping map[string]string, loadErr error) {
+	file, openErr := excelize.OpenFile(path)
+	if openErr != nil {
+		return nil, openErr
+	}
+	defer func(file *excelize.File) {
+		if err := file.Close(); err != nil && loadErr == nil {
+			loadErr = err
+		}
+	}(file)
+	if len(sheet) < 1 {
+		sheet = file.GetSheetName(0)
+	}
+	rows, rowsErr := file.GetRows(sheet)
+	if rowsErr != nil {
+		return nil, rowsErr
+	}
+	if mapping, loadErr = mappingTable(rows, key, value); loadErr != nil {
+		return nil, fmt.Errorf("worksheet '%s': %w", sheet, loadErr)
+	}
+	return mapping, nil
+}
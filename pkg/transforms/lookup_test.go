@@ -0,0 +1,123 @@
+package transforms
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestMapWith(t *testing.T) {
+	mapping := map[string]string{"DE": "Germany", "FR": "France"}
+	tests := []struct {
+		name    string
+		opts    LookupOptions
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{name: "Mapped", value: "DE", want: "Germany"},
+		{name: "Keep", value: "XX", want: "XX"},
+		{name: "Blank", opts: LookupOptions{Unmapped: BlankUnmapped}, value: "XX", want: ""},
+		{name: "Default", opts: LookupOptions{Unmapped: DefaultUnmapped, Default: "Other"}, value: "XX", want: "Other"},
+		{name: "Error", opts: LookupOptions{Unmapped: FailUnmapped}, value: "XX", wantErr: true},
+		{name: "Error Blank", opts: LookupOptions{Unmapped: FailUnmapped}, value: " ", want: " "},
+		{name: "Ignore Case", opts: LookupOptions{IgnoreCase: true, TrimSpace: true}, value: " fr ", want: "France"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MapWith(mapping, tt.opts)(tt.value)
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "not mapped") {
+					t.Fatalf("MapWith()(%q) = %q, %v, want an error", tt.value, got, err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("MapWith()(%q) = %q, %v, want %q", tt.value, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadMappingCsv(t *testing.T) {
+	mapping, err := ReadMappingCsv(strings.NewReader("Name,Code,Notes\nOpen,O,\nClosed,C,\nUnused,,\n"), "Code", "Name")
+	if err != nil {
+		t.Fatalf("ReadMappingCsv() error = %v", err)
+	}
+	if len(mapping) != 2 || mapping["O"] != "Open" || mapping["C"] != "Closed" {
+		t.Errorf("ReadMappingCsv() = %v", mapping)
+	}
+	for input, wantErr := range map[string]string{
+		"Code,Name\nO,Open\nO,Opened\n": "'O' is mapped to 'Open' and 'Opened'",
+		"Code\nO\n":                     "a key column and a value column",
+		"":                              "no header",
+	} {
+		if _, err := ReadMappingCsv(strings.NewReader(input), "", ""); err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Errorf("ReadMappingCsv(%q) error = %v, want %s", input, err, wantErr)
+		}
+	}
+	if _, err := ReadMappingCsv(strings.NewReader("Code,Name\n"), "Key", ""); err == nil || !strings.Contains(err.Error(), "unknown column 'Key'") {
+		t.Errorf("ReadMappingCsv() error = %v, want unknown column", err)
+	}
+}
+
+func TestLookupConfig(t *testing.T) {
+	dir := t.TempDir()
+	csvPath := filepath.Join(dir, "status.csv")
+	if err := os.WriteFile(csvPath, []byte("Code,Description\nO,Open\nC,Closed\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	workbookPath := filepath.Join(dir, "codes.xlsx")
+	file := excelize.NewFile()
+	if _, err := file.NewSheet("Countries"); err != nil {
+		t.Fatalf("NewSheet() error = %v", err)
+	}
+	for index, row := range [][]any{{"Code", "Name"}, {"DE", "Germany"}, {"FR", "France"}} {
+		cell, _ := excelize.CoordinatesToCellName(1, index+1)
+		if err := file.SetSheetRow("Countries", cell, &row); err != nil {
+			t.Fatalf("SetSheetRow() error = %v", err)
+		}
+	}
+	if err := file.SaveAs(workbookPath); err != nil {
+		t.Fatalf("SaveAs() error = %v", err)
+	}
+
+	config := `
+stages:
+  - column: Status
+    transforms:
+      - lookup: {csv: ` + csvPath + `, values: {X: Cancelled}, unmapped: error}
+  - column: Country
+    transforms:
+      - lookup: {workbook: ` + workbookPath + `, sheet: Countries, unmapped: default, default: Other}
+`
+	pipeline, err := ParseConfig(strings.NewReader(config))
+	if err != nil {
+		t.Fatalf("ParseConfig() error = %v", err)
+	}
+	run, err := pipeline.Start([]string{"Status", "Country"})
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if got, err := run.Record([]string{"C", "FR"}); err != nil || got[0] != "Closed" || got[1] != "France" {
+		t.Errorf("Record() = %v, %v", got, err)
+	}
+	if got, err := run.Record([]string{"X", "US"}); err != nil || got[0] != "Cancelled" || got[1] != "Other" {
+		t.Errorf("Record() = %v, %v", got, err)
+	}
+	if _, err := run.Record([]string{"Z", "DE"}); err == nil || !strings.Contains(err.Error(), "'Z' is not mapped") {
+		t.Errorf("Record() error = %v, want 'Z' is not mapped", err)
+	}
+
+	for input, wantErr := range map[string]string{
+		`{"stages": [{"column": "A", "transforms": [{"lookup": {}}]}]}`:                                 "takes values, a CSV file or a workbook",
+		`{"stages": [{"column": "A", "transforms": [{"lookup": {"values": {}, "unmapped": "drop"}}]}]}`: "unknown unmapped behaviour 'drop'",
+	} {
+		if _, err := ParseConfig(strings.NewReader(input)); err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Errorf("ParseConfig(%s) error = %v, want %s", input, err, wantErr)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package transforms
+
+import (
+	"fmt"
+
+	"GoTools/pkg/datatable"
+)
+
+// stage is a stage of a Pipeline. start binds it to the header of a worksheet and returns the header it hands
+// on, the position in header of the column each of its columns comes from, nil when they keep their position,
+// and the function processing the records below it, which holds any state of the stage, such as the last value
+// seen.
+type stage interface {
+	start(header []string) (next []string, sources []int, process func(values []string) ([]string, error), err error)
+}
+
+// Pipeline is a sequence of stages the records of a worksheet go through in order, such as transforming the
+// values of a column. Build it with Column and the other methods, or read it from a configuration file with
+// ParseConfig, then Start it for every worksheet; a Pipeline can be started any number of times, even
+// concurrently.
+//
+//	pipeline := transforms.New().
+//		Column("Name", transforms.Trim, transforms.Title).
+//		Column("Born", transforms.Date(helpers.DateOptions{Order: helpers.DayFirst}))
+type Pipeline struct {
+	stages []stage
+}
+
+// New returns an empty pipeline.
+func New() *Pipeline {
+	return &Pipeline{}
+}
+
+// Len returns the number of stages of the pipeline.
+func (p *Pipeline) Len() int {
+	return len(p.stages)
+}
+
+// add appends stage to the pipeline and returns the pipeline.
+func (p *Pipeline) add(stage stage) *Pipeline {
+	p.stages = append(p.stages, stage)
+	return p
+}
+
+// Column adds a stage transforming the values of the column called name with funcs in order.
+func (p *Pipeline) Column(name string, funcs ...Func) *Pipeline {
+	return p.add(columnStage{column: name, funcs: funcs})
+}
+
+// Run is a Pipeline started on the header of a worksheet. It is not safe for concurrent use.
+type Run struct {
+	header    []string
+	sources   []int
+	processes []func(values []string) ([]string, error)
+}
+
+// Start binds the pipeline to header, failing when a stage names a column it lacks.
+func (p *Pipeline) Start(header []string) (*Run, error) {
+	run := &Run{header: header, sources: make([]int, len(header))}
+	for index := range run.sources {
+		run.sources[index] = index
+	}
+	for _, stage := range p.stages {
+		next, sources, process, err := stage.start(run.header)
+		if err != nil {
+			return nil, err
+		}
+		if sources != nil {
+			composed := make([]int, len(sources))
+			for index, source := range sources {
+				composed[index] = run.sources[source]
+			}
+			run.sources = composed
+		}
+		run.header = next
+		run.processes = append(run.processes, process)
+	}
+	return run, nil
+}
+
+// Header returns the header of the records the pipeline hands on.
+func (r *Run) Header() []string {
+	return r.header
+}
+
+// Sources returns, for every column of Header, the position in the header the pipeline was started on of the
+// column it comes from; the columns a split or a merge adds come from the column split or the first column merged,
+// and a computed column from the first column its expression reads.
+func (r *Run) Sources() []int {
+	return r.sources
+}
+
+// Record returns values, a record below the header the pipeline was started on, through every stage. values may
+// be changed; records shorter than the header are missing their last values.
+func (r *Run) Record(values []string) ([]string, error) {
+	for _, process := range r.processes {
+		var err error
+		if values, err = process(values); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+// ApplyTable returns table with its records through the pipeline. The columns the pipeline keeps keep their type.
+func (p *Pipeline) ApplyTable(table *datatable.DataTable) (*datatable.DataTable, error) {
+	run, err := p.Start(table.ColumnNames())
+	if err != nil {
+		return nil, err
+	}
+	columns := make([]datatable.Column, len(run.Header()))
+	for index, name := range run.Header() {
+		columns[index] = datatable.Column{Name: name}
+		if column, err := table.Column(name); err == nil {
+			columns[index].Type = column.Type
+		}
+	}
+	result, err := datatable.NewWithColumns(columns...)
+	if err != nil {
+		return nil, err
+	}
+	transformErr := table.Each(func(row datatable.Row) error {
+		values, err := run.Record(row.Values())
+		if err != nil {
+			return fmt.Errorf("row %d: %w", row.Index()+1, err)
+		}
+		return result.AddRow(values...)
+	})
+	if transformErr != nil {
+		return nil, transformErr
+	}
+	return result, nil
+}
+
+// position returns the position of the column called name in header.
+func position(header []string, name string) (int, error) {
+	for index, column := range header {
+		if column == name {
+			return index, nil
+		}
+	}
+	return -1, fmt.Errorf("unknown column '%s'", name)
+}
+
+// columnStage transforms the values of a column.
+type columnStage struct {
+	column string
+	funcs  []Func
+}
+
+func (s columnStage) start(header []string) ([]string, []int, func([]string) ([]string, error), error) {
+	index, err := position(header, s.column)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return header, nil, func(values []string) ([]string, error) {
+		if index >= len(values) {
+			return values, nil
+		}
+		for _, transform := range s.funcs {
+			var err error
+			if values[index], err = transform(values[index]); err != nil {
+				return nil, fmt.Errorf("column '%s': %w", s.column, err)
+			}
+		}
+		return values, nil
+	}, nil
+}
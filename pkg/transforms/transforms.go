@@ -0,0 +1,296 @@
+// Package transforms cleans the values of columns while converting them: a Pipeline chains transforms per
+// column, such as trimming, normalizing dates and mapping codes to descriptions, so the output of a conversion
+// needs no further massaging. Transforms are looked up by name, so pipelines can be configured in a file, see
+// ParseConfig, and Register adds new ones.
+package transforms
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	. "GoTools/pkg/helpers"
+	"GoTools/pkg/validators"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+)
+
+// Func transforms a value of a column, failing for values it cannot transform, such as a date it cannot read.
+type Func func(value string) (string, error)
+
+// Factory returns the Func configured by args, the arguments given to a transform in a configuration file.
+type Factory func(args []string) (Func, error)
+
+// Trim removes the leading and trailing whitespace.
+func Trim(value string) (string, error) {
+	return strings.TrimSpace(value), nil
+}
+
+// Clean removes invisible characters and collapses whitespace, see CleanValue.
+func Clean(value string) (string, error) {
+	return CleanValue(value, CleanOptions{}), nil
+}
+
+// Upper writes the value in upper case.
+func Upper(value string) (string, error) {
+	return strings.ToUpper(value), nil
+}
+
+// Lower writes the value in lower case.
+func Lower(value string) (string, error) {
+	return strings.ToLower(value), nil
+}
+
+// Title writes every word of the value with an initial capital and the rest in lower case.
+func Title(value string) (string, error) {
+	return cases.Title(language.Und).String(value), nil
+}
+
+// ASCII spells accented letters in ASCII, see Transliterate.
+func ASCII(value string) (string, error) {
+	return Transliterate(value), nil
+}
+
+// Email normalizes an email address, see NormalizeEmail. Blank values are kept.
+func Email(value string) (string, error) {
+	return unlessBlank(value, NormalizeEmail)
+}
+
+// Date returns a transform reading dates in ISO 8601 or with opts, see ParseToISO8601With, and writing them in
+// the layout opts.Output, see ParseDateOutput. When opts.Output is left out, dates at midnight are written as
+// dates and the others with their time. Values which are not a date fail; blank values are kept.
+func Date(opts DateOptions) Func {
+	return func(value string) (string, error) {
+		return unlessBlank(value, func(value string) (string, error) {
+			date, err := parseDate(strings.TrimSpace(value), opts)
+			if err != nil {
+				return "", err
+			}
+			output := ParseDateOutput(opts.Output)
+			if len(opts.Output) < 1 && date.Equal(date.Truncate(24*time.Hour)) {
+				output = time.DateOnly
+			}
+			return date.Format(output), nil
+		})
+	}
+}
+
+// isoLayouts are the ISO 8601 layouts Date reads before the layouts of its options.
+var isoLayouts = []string{time.DateOnly, time.DateTime, "2006-01-02T15:04:05", time.RFC3339Nano}
+
+// parseDate reads value in ISO 8601 or with opts.
+func parseDate(value string, opts DateOptions) (time.Time, error) {
+	for _, layout := range isoLayouts {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date, nil
+		}
+	}
+	return ParseToISO8601With(value, opts)
+}
+
+// Number returns a transform writing numbers written as in locale as canonical decimals, see NormalizeNumber.
+// Blank values are kept.
+func Number(locale NumberLocale) Func {
+	return func(value string) (string, error) {
+		return unlessBlank(value, func(value string) (string, error) {
+			return NormalizeNumber(value, locale)
+		})
+	}
+}
+
+// Phone returns a transform writing phone numbers in E.164, national ones being of defaultRegion, see
+// NormalizePhone. Blank values are kept.
+func Phone(defaultRegion string) Func {
+	return func(value string) (string, error) {
+		return unlessBlank(value, func(value string) (string, error) {
+			return NormalizePhone(value, defaultRegion)
+		})
+	}
+}
+
+// Validate returns a transform checking values with validator and writing them normalized. Blank values are kept.
+func Validate(validator validators.Validator) Func {
+	return func(value string) (string, error) {
+		return unlessBlank(value, Func(validator))
+	}
+}
+
+// Default returns a transform replacing blank values with value.
+func Default(value string) Func {
+	return func(current string) (string, error) {
+		if len(strings.TrimSpace(current)) < 1 {
+			return value, nil
+		}
+		return current, nil
+	}
+}
+
+// Replace returns a transform replacing every occurrence of old with replacement.
+func Replace(old, replacement string) Func {
+	return func(value string) (string, error) {
+		return strings.ReplaceAll(value, old, replacement), nil
+	}
+}
+
+// Prefix returns a transform writing prefix before the values which are not blank.
+func Prefix(prefix string) Func {
+	return func(value string) (string, error) {
+		return unlessBlank(value, func(value string) (string, error) { return prefix + value, nil })
+	}
+}
+
+// Suffix returns a transform writing suffix after the values which are not blank.
+func Suffix(suffix string) Func {
+	return func(value string) (string, error) {
+		return unlessBlank(value, func(value string) (string, error) { return value + suffix, nil })
+	}
+}
+
+// Map returns a transform replacing the values found in mapping with their mapped value and keeping the others,
+// see MapWith.
+func Map(mapping map[string]string) Func {
+	return MapWith(mapping, LookupOptions{})
+}
+
+// unlessBlank returns value transformed by transform, or as it is when it is blank.
+func unlessBlank(value string, transform Func) (string, error) {
+	if len(strings.TrimSpace(value)) < 1 {
+		return value, nil
+	}
+	return transform(value)
+}
+
+// noArgs returns the factory of transform, which takes no arguments.
+func noArgs(name string, transform Func) Factory {
+	return func(args []string) (Func, error) {
+		if len(args) > 0 {
+			return nil, fmt.Errorf("the transform '%s' takes no arguments", name)
+		}
+		return transform, nil
+	}
+}
+
+// argCount fails when the transform called name is given fewer than least or more than most arguments.
+func argCount(name string, args []string, least, most int) error {
+	if len(args) < least || len(args) > most {
+		if least == most {
+			return fmt.Errorf("the transform '%s' takes %d arguments, not %d", name, least, len(args))
+		}
+		return fmt.Errorf("the transform '%s' takes %d to %d arguments, not %d", name, least, most, len(args))
+	}
+	return nil
+}
+
+// arg returns the argument at index, or fallback when there are fewer.
+func arg(args []string, index int, fallback string) string {
+	if index < len(args) {
+		return args[index]
+	}
+	return fallback
+}
+
+// registry holds the transforms known by name.
+var registry = struct {
+	sync.RWMutex
+	factories map[string]Factory
+}{factories: map[string]Factory{
+	"trim":  noArgs("trim", Trim),
+	"clean": noArgs("clean", Clean),
+	"upper": noArgs("upper", Upper),
+	"lower": noArgs("lower", Lower),
+	"title": noArgs("title", Title),
+	"ascii": noArgs("ascii", ASCII),
+	"email": noArgs("email", Email),
+	"date": func(args []string) (Func, error) {
+		if err := argCount("date", args, 0, 2); err != nil {
+			return nil, err
+		}
+		order, err := ParseDateOrder(arg(args, 1, ""))
+		if err != nil {
+			return nil, err
+		}
+		return Date(DateOptions{Output: arg(args, 0, ""), Order: order}), nil
+	},
+	"number": func(args []string) (Func, error) {
+		if err := argCount("number", args, 0, 1); err != nil {
+			return nil, err
+		}
+		locale, err := ParseNumberLocale(arg(args, 0, ""))
+		if err != nil {
+			return nil, err
+		}
+		return Number(locale), nil
+	},
+	"phone": func(args []string) (Func, error) {
+		if err := argCount("phone", args, 0, 1); err != nil {
+			return nil, err
+		}
+		return Phone(arg(args, 0, "")), nil
+	},
+	"validate": func(args []string) (Func, error) {
+		if err := argCount("validate", args, 1, 1); err != nil {
+			return nil, err
+		}
+		validator, err := validators.Lookup(args[0])
+		if err != nil {
+			return nil, err
+		}
+		return Validate(validator), nil
+	},
+	"default": func(args []string) (Func, error) {
+		if err := argCount("default", args, 1, 1); err != nil {
+			return nil, err
+		}
+		return Default(args[0]), nil
+	},
+	"replace": func(args []string) (Func, error) {
+		if err := argCount("replace", args, 2, 2); err != nil {
+			return nil, err
+		}
+		return Replace(args[0], args[1]), nil
+	},
+	"prefix": func(args []string) (Func, error) {
+		if err := argCount("prefix", args, 1, 1); err != nil {
+			return nil, err
+		}
+		return Prefix(args[0]), nil
+	},
+	"suffix": func(args []string) (Func, error) {
+		if err := argCount("suffix", args, 1, 1); err != nil {
+			return nil, err
+		}
+		return Suffix(args[0]), nil
+	},
+}}
+
+// Register makes the transforms returned by factory available under name, replacing any transform of that name.
+func Register(name string, factory Factory) {
+	registry.Lock()
+	defer registry.Unlock()
+	registry.factories[strings.ToLower(name)] = factory
+}
+
+// Lookup returns the transform registered under name, ignoring case, configured by args.
+func Lookup(name string, args ...string) (Func, error) {
+	registry.RLock()
+	factory, known := registry.factories[strings.ToLower(name)]
+	registry.RUnlock()
+	if !known {
+		return nil, fmt.Errorf("unknown transform '%s'", name)
+	}
+	return factory(args)
+}
+
+// Names returns the names of the registered transforms in alphabetical order.
+func Names() []string {
+	registry.RLock()
+	defer registry.RUnlock()
+	names := make([]string, 0, len(registry.factories))
+	for name := range registry.factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,152 @@
+package transforms
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"GoTools/pkg/datatable"
+	. "GoTools/pkg/helpers"
+)
+
+func TestFuncs(t *testing.T) {
+	tests := []struct {
+		name      string
+		transform Func
+		value     string
+		want      string
+		wantErr   bool
+	}{
+		{name: "Trim", transform: Trim, value: "  Acme \t", want: "Acme"},
+		{name: "Upper", transform: Upper, value: "open", want: "OPEN"},
+		{name: "Title", transform: Title, value: "jane DOE", want: "Jane Doe"},
+		{name: "Date Day First", transform: Date(DateOptions{Order: DayFirst}), value: "25/12/20", want: "2020-12-25"},
+		{name: "Date ISO", transform: Date(DateOptions{Output: "02.01.2006"}), value: "2020-12-25", want: "25.12.2020"},
+		{name: "Date Time", transform: Date(DateOptions{}), value: "2020-12-25 08:15:00", want: "2020-12-25 08:15:00"},
+		{name: "Date Blank", transform: Date(DateOptions{}), value: " ", want: " "},
+		{name: "Date Invalid", transform: Date(DateOptions{}), value: "soon", wantErr: true},
+		{name: "Default", transform: Default("n/a"), value: "", want: "n/a"},
+		{name: "Prefix Blank", transform: Prefix("#"), value: "", want: ""},
+		{name: "Map", transform: Map(map[string]string{"O": "Open"}), value: "O", want: "Open"},
+		{name: "Map Unknown", transform: Map(map[string]string{"O": "Open"}), value: "X", want: "X"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.transform(tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("transform(%q) = %q, want an error", tt.value, got)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("transform(%q) = %q, %v, want %q", tt.value, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLookup(t *testing.T) {
+	replace, err := Lookup("REPLACE", "-", "/")
+	if err != nil {
+		t.Fatalf("Lookup() error = %v", err)
+	}
+	if got, _ := replace("a-b-c"); got != "a/b/c" {
+		t.Errorf("replace() = %q, want a/b/c", got)
+	}
+	for name, args := range map[string][]string{"missing": nil, "trim": {"x"}, "replace": {"-"}, "date": {"date", "sideways"}} {
+		if _, err := Lookup(name, args...); err == nil {
+			t.Errorf("Lookup(%s, %v) should fail", name, args)
+		}
+	}
+}
+
+func TestPipeline(t *testing.T) {
+	pipeline := New().
+		Column("Status", Trim, Upper, Map(map[string]string{"O": "Open", "C": "Closed"})).
+		Column("Born", Date(DateOptions{Order: DayFirst}))
+	run, err := pipeline.Start([]string{"Name", "Status", "Born"})
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	got, err := run.Record([]string{"Acme", " o ", "01/02/06"})
+	if want := []string{"Acme", "Open", "2006-02-01"}; err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("Record() = %v, %v, want %v", got, err, want)
+	}
+	if got, err := run.Record([]string{"Acme", "c"}); err != nil || !reflect.DeepEqual(got, []string{"Acme", "Closed"}) {
+		t.Errorf("Record() of a short record = %v, %v", got, err)
+	}
+	if _, err := run.Record([]string{"Acme", "O", "never"}); err == nil || !strings.Contains(err.Error(), "column 'Born'") {
+		t.Errorf("Record() error = %v, want the column named", err)
+	}
+	if _, err := pipeline.Start([]string{"Name"}); err == nil || !strings.Contains(err.Error(), "unknown column 'Status'") {
+		t.Errorf("Start() error = %v, want unknown column", err)
+	}
+}
+
+func TestApplyTable(t *testing.T) {
+	table, err := datatable.New("Name", "Code")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if err := table.AddRow(" acme ", "x"); err != nil {
+		t.Fatalf("AddRow() error = %v", err)
+	}
+	result, err := New().Column("Name", Trim, Title).Column("Code", Upper).ApplyTable(table)
+	if err != nil {
+		t.Fatalf("ApplyTable() error = %v", err)
+	}
+	var rows [][]string
+	_ = result.Each(func(row datatable.Row) error {
+		rows = append(rows, row.Values())
+		return nil
+	})
+	if want := [][]string{{"Acme", "X"}}; !reflect.DeepEqual(rows, want) {
+		t.Errorf("ApplyTable() rows = %v, want %v", rows, want)
+	}
+}
+
+func TestParseConfig(t *testing.T) {
+	config := `
+stages:
+  - column: Status
+    transforms:
+      - trim
+      - upper
+      - map: {O: Open, C: Closed}
+  - column: Born
+    transforms:
+      - date: [date, day-first]
+  - column: Code
+    transforms:
+      - prefix: "#"
+`
+	pipeline, err := ParseConfig(strings.NewReader(config))
+	if err != nil {
+		t.Fatalf("ParseConfig() error = %v", err)
+	}
+	run, err := pipeline.Start([]string{"Status", "Born", "Code"})
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	got, err := run.Record([]string{" c", "31/01/24", "7"})
+	if want := []string{"Closed", "2024-01-31", "#7"}; err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("Record() = %v, %v, want %v", got, err, want)
+	}
+
+	json := `{"stages": [{"column": "Name", "transforms": ["trim", "lower"]}]}`
+	if pipeline, err := ParseConfig(strings.NewReader(json)); err != nil || pipeline.Len() != 1 {
+		t.Errorf("ParseConfig(%s) = %v, %v", json, pipeline, err)
+	}
+
+	for input, wantErr := range map[string]string{
+		`{"stages": [{"transforms": ["trim"]}]}`:                      "no column",
+		`{"stages": [{"column": "A", "transforms": ["shout"]}]}`:      "unknown transform 'shout'",
+		`{"stages": [{"column": "A", "transforms": [{"map": "x"}]}]}`: "cannot unmarshal",
+		`{"steps": []}`: "not found",
+	} {
+		if _, err := ParseConfig(strings.NewReader(input)); err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Errorf("ParseConfig(%s) error = %v, want %s", input, err, wantErr)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package validators
+
+// ibanLengths maps the countries using IBANs to the length of their IBANs.
+var ibanLengths = map[string]int{
+	"AD": 24, "AE": 23, "AL": 28, "AT": 20, "AZ": 28, "BA": 20, "BE": 16, "BG": 22, "BH": 22, "BR": 29,
+	"BY": 28, "CH": 21, "CR": 22, "CY": 28, "CZ": 24, "DE": 22, "DK": 18, "DO": 28, "EE": 20, "EG": 29,
+	"ES": 24, "FI": 18, "FO": 18, "FR": 27, "GB": 22, "GE": 22, "GI": 23, "GL": 18, "GR": 27, "GT": 28,
+	"HR": 21, "HU": 28, "IE": 22, "IL": 23, "IQ": 23, "IS": 26, "IT": 27, "JO": 30, "KW": 30, "KZ": 20,
+	"LB": 28, "LC": 32, "LI": 21, "LT": 20, "LU": 20, "LV": 21, "MC": 27, "MD": 24, "ME": 22, "MK": 19,
+	"MR": 27, "MT": 31, "MU": 30, "NL": 18, "NO": 15, "PK": 24, "PL": 28, "PS": 29, "PT": 25, "QA": 29,
+	"RO": 24, "RS": 22, "SA": 24, "SC": 31, "SE": 24, "SI": 19, "SK": 24, "SM": 27, "ST": 25, "SV": 28,
+	"TL": 23, "TN": 24, "TR": 26, "UA": 29, "VA": 22, "VG": 24, "XK": 20,
+}
+
+// IBAN checks an International Bank Account Number, written with or without spaces such as
+// "DE89 3704 0044 0532 0130 00": its country, its length for that country and its check digits, which must give
+// the remainder 1 when the number is divided by 97 as ISO 13616 defines. It returns the IBAN without spaces.
+func IBAN(value string) (string, error) {
+	iban := compact(value)
+	if len(iban) < 4 {
+		return "", invalid(value, "IBAN", "it is too short")
+	}
+	length, known := ibanLengths[iban[:2]]
+	if !known {
+		return "", invalid(value, "IBAN", "'%s' is not a country using IBANs", iban[:2])
+	}
+	if len(iban) != length {
+		return "", invalid(value, "IBAN", "IBANs of %s have %d characters, not %d", iban[:2], length, len(iban))
+	}
+	if !isDigits(iban[2:4]) {
+		return "", invalid(value, "IBAN", "the check digits '%s' are not digits", iban[2:4])
+	}
+	remainder := 0
+	for _, char := range iban[4:] + iban[:4] {
+		switch {
+		case '0' <= char && char <= '9':
+			remainder = (remainder*10 + int(char-'0')) % 97
+		case 'A' <= char && char <= 'Z':
+			remainder = (remainder*100 + int(char-'A') + 10) % 97
+		default:
+			return "", invalid(value, "IBAN", "it contains '%c'", char)
+		}
+	}
+	if remainder != 1 {
+		return "", invalid(value, "IBAN", "the check digits do not match")
+	}
+	return iban, nil
+}
+
+// BIC checks a Business Identifier Code such as "DEUTDEFF" or "DEUTDEFF500": four letters naming the bank, the
+// two letters of its country, two letters or digits naming its location and optionally three more naming the
+// branch, as ISO 9362 defines. BICs carry no check digits. It returns the BIC in upper case.
+func BIC(value string) (string, error) {
+	bic := compact(value)
+	if len(bic) != 8 && len(bic) != 11 {
+		return "", invalid(value, "BIC", "BICs have 8 or 11 characters, not %d", len(bic))
+	}
+	for index, char := range bic {
+		letter := 'A' <= char && char <= 'Z'
+		if index < 6 && !letter {
+			return "", invalid(value, "BIC", "the bank and country codes '%s' must be letters", bic[:6])
+		}
+		if !letter && (char < '0' || char > '9') {
+			return "", invalid(value, "BIC", "it contains '%c'", char)
+		}
+	}
+	return bic, nil
+}
@@ -0,0 +1,117 @@
+package validators
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Luhn checks a number protected by the Luhn check digit, such as a payment card number or an IMEI, written
+// with or without spaces and dashes. It returns its digits.
+func Luhn(value string) (string, error) {
+	number := compact(value)
+	if len(number) < 2 || !isDigits(number) {
+		return "", invalid(value, "Luhn number", "it must hold at least two digits and nothing else")
+	}
+	sum := 0
+	for index := range number {
+		digit := int(number[len(number)-1-index] - '0')
+		if index%2 == 1 {
+			if digit *= 2; digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+	}
+	if sum%10 != 0 {
+		return "", invalid(value, "Luhn number", "the check digit does not match")
+	}
+	return number, nil
+}
+
+// USSocialSecurityNumber checks a United States Social Security Number such as "123-45-6789": nine digits whose
+// area is neither 000, 666 nor above 899, whose group is not 00 and whose serial is not 0000. SSNs carry no check
+// digit. It returns the number written with dashes.
+func USSocialSecurityNumber(value string) (string, error) {
+	number := compact(value)
+	if len(number) != 9 || !isDigits(number) {
+		return "", invalid(value, "SSN", "it must hold nine digits")
+	}
+	area, group, serial := number[:3], number[3:5], number[5:]
+	switch {
+	case area == "000" || area == "666" || area[0] == '9':
+		return "", invalid(value, "SSN", "'%s' is not an area number", area)
+	case group == "00":
+		return "", invalid(value, "SSN", "the group number is 00")
+	case serial == "0000":
+		return "", invalid(value, "SSN", "the serial number is 0000")
+	}
+	return area + "-" + group + "-" + serial, nil
+}
+
+// UKNationalInsuranceNumber checks a United Kingdom National Insurance number such as "QQ 12 34 56 C": two
+// prefix letters from those HMRC allocates, six digits and a suffix letter from A to D. It returns the number
+// without spaces.
+func UKNationalInsuranceNumber(value string) (string, error) {
+	number := compact(value)
+	if len(number) != 9 || !isDigits(number[2:8]) {
+		return "", invalid(value, "National Insurance number", "it must be two letters, six digits and a letter")
+	}
+	first, second := number[0], number[1]
+	switch {
+	case strings.IndexByte("ABCEGHJKLMNOPRSTWXYZ", first) < 0, strings.IndexByte("ABCEGHJKLMNPRSTWXYZ", second) < 0:
+		return "", invalid(value, "National Insurance number", "'%s' is not a prefix", number[:2])
+	case strings.Contains(" BG GB KN NK NT TN ZZ ", " "+number[:2]+" "):
+		return "", invalid(value, "National Insurance number", "'%s' is not a prefix", number[:2])
+	case number[8] < 'A' || number[8] > 'D':
+		return "", invalid(value, "National Insurance number", "'%c' is not a suffix", number[8])
+	}
+	return number, nil
+}
+
+// spanishDNILetters are the check letters of Spanish identity numbers, indexed by the number modulo 23.
+const spanishDNILetters = "TRWAGMYFPDXBNJZSQVHLCKE"
+
+// SpanishDNI checks a Spanish identity number, a DNI such as "12345678Z" or an NIE for foreigners such as
+// "X1234567L": eight digits, or a letter from X to Z and seven digits, followed by their check letter.
+// It returns the number in upper case without separators.
+func SpanishDNI(value string) (string, error) {
+	number := compact(value)
+	if len(number) != 9 {
+		return "", invalid(value, "DNI", "it must be eight digits and a letter")
+	}
+	digits := number[:8]
+	if prefix := strings.IndexByte("XYZ", digits[0]); prefix >= 0 {
+		digits = fmt.Sprint(prefix) + digits[1:]
+	}
+	if !isDigits(digits) {
+		return "", invalid(value, "DNI", "it must be eight digits and a letter")
+	}
+	numeric, _ := strconv.Atoi(digits)
+	if want := spanishDNILetters[numeric%23]; number[8] != want {
+		return "", invalid(value, "DNI", "the check letter should be '%c'", want)
+	}
+	return number, nil
+}
+
+// DutchBSN checks a Dutch citizen service number, a burgerservicenummer such as "111222333": nine digits passing
+// the eleven test, the digits weighted 9 down to 2 and the last one -1 adding up to a multiple of eleven.
+// Eight digit numbers are read with a leading zero. It returns the nine digits.
+func DutchBSN(value string) (string, error) {
+	number := compact(value)
+	if len(number) == 8 {
+		number = "0" + number
+	}
+	if len(number) != 9 || !isDigits(number) {
+		return "", invalid(value, "BSN", "it must hold nine digits")
+	}
+	sum := 0
+	for index := 0; index < 8; index++ {
+		sum += int(number[index]-'0') * (9 - index)
+	}
+	sum -= int(number[8] - '0')
+	if sum%11 != 0 || number == "000000000" {
+		return "", invalid(value, "BSN", "it fails the eleven test")
+	}
+	return number, nil
+}
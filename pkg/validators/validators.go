@@ -0,0 +1,136 @@
+// Package validators checks identifiers carrying check digits, such as IBANs, and other structured codes, such as
+// BICs and national identity numbers, so rows holding mistyped values can be rejected while converting them.
+// Every validator returns the value in its normalized form. Validators are looked up by name, so they can be
+// configured from the command line, and Register adds new ones.
+package validators
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Validator checks value and returns it normalized, or a *ValidationError.
+type Validator func(value string) (string, error)
+
+// ValidationError tells why Value is not a valid Kind, such as an IBAN.
+type ValidationError struct {
+	Value  string
+	Kind   string
+	Reason string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("'%s' is not a valid %s: %s", e.Value, e.Kind, e.Reason)
+}
+
+// invalid returns the ValidationError of value.
+func invalid(value, kind, reason string, args ...any) error {
+	return &ValidationError{Value: value, Kind: kind, Reason: fmt.Sprintf(reason, args...)}
+}
+
+// registry holds the validators known by name.
+var registry = struct {
+	sync.RWMutex
+	validators map[string]Validator
+}{validators: map[string]Validator{
+	"iban":    IBAN,
+	"bic":     BIC,
+	"luhn":    Luhn,
+	"us-ssn":  USSocialSecurityNumber,
+	"uk-nino": UKNationalInsuranceNumber,
+	"es-dni":  SpanishDNI,
+	"nl-bsn":  DutchBSN,
+}}
+
+// Register makes validator available under name, replacing any validator of that name.
+func Register(name string, validator Validator) {
+	registry.Lock()
+	defer registry.Unlock()
+	registry.validators[strings.ToLower(name)] = validator
+}
+
+// Lookup returns the validator registered under name, ignoring case.
+func Lookup(name string) (Validator, error) {
+	registry.RLock()
+	defer registry.RUnlock()
+	validator, known := registry.validators[strings.ToLower(name)]
+	if !known {
+		return nil, fmt.Errorf("unknown validator '%s'", name)
+	}
+	return validator, nil
+}
+
+// Names returns the names of the registered validators in alphabetical order.
+func Names() []string {
+	registry.RLock()
+	defer registry.RUnlock()
+	names := make([]string, 0, len(registry.validators))
+	for name := range registry.validators {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Rule validates the values of the column named Column.
+type Rule struct {
+	Column    string
+	Validator Validator
+}
+
+// ParseRules returns the rules of a comma separated list of <column>=<validator> pairs, such as
+// "IBAN=iban,Card Number=luhn".
+func ParseRules(list string) ([]Rule, error) {
+	var rules []Rule
+	for _, pair := range strings.Split(list, ",") {
+		column, name, found := strings.Cut(pair, "=")
+		if !found || len(strings.TrimSpace(column)) < 1 {
+			return nil, fmt.Errorf("validation rules are <column>=<validator> pairs, got '%s'", pair)
+		}
+		validator, lookupErr := Lookup(strings.TrimSpace(name))
+		if lookupErr != nil {
+			return nil, lookupErr
+		}
+		rules = append(rules, Rule{Column: strings.TrimSpace(column), Validator: validator})
+	}
+	return rules, nil
+}
+
+// RowFilter returns a filter of the records keyed by their header, as taken by converters.ReadOptions.RowFilter,
+// dropping the records holding a value one of rules rejects; blank values are missing rather than invalid and are
+// kept. onInvalid, when set, is called with the column and the error of every rejected value.
+func RowFilter(rules []Rule, onInvalid func(column string, err error)) func(record map[string]string) bool {
+	return func(record map[string]string) bool {
+		valid := true
+		for _, rule := range rules {
+			value := strings.TrimSpace(record[rule.Column])
+			if len(value) < 1 {
+				continue
+			}
+			if _, err := rule.Validator(value); err != nil {
+				valid = false
+				if onInvalid != nil {
+					onInvalid(rule.Column, err)
+				}
+			}
+		}
+		return valid
+	}
+}
+
+// compact removes the spaces and dashes separating the groups of characters of value and upper cases it.
+func compact(value string) string {
+	return strings.ToUpper(strings.NewReplacer(" ", "", "-", "", ".", "").Replace(strings.TrimSpace(value)))
+}
+
+// isDigits reports whether value only holds the digits 0 to 9.
+func isDigits(value string) bool {
+	for _, char := range value {
+		if char < '0' || char > '9' {
+			return false
+		}
+	}
+	return len(value) > 0
+}
@@ -0,0 +1,112 @@
+package validators
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValidators(t *testing.T) {
+	tests := []struct {
+		name      string
+		validator Validator
+		value     string
+		want      string
+		wantErr   string
+	}{
+		{name: "IBAN", validator: IBAN, value: "DE89 3704 0044 0532 0130 00", want: "DE89370400440532013000"},
+		{name: "IBAN Lower Case", validator: IBAN, value: "gb82west12345698765432", want: "GB82WEST12345698765432"},
+		{name: "IBAN Check Digits", validator: IBAN, value: "DE88 3704 0044 0532 0130 00", wantErr: "the check digits do not match"},
+		{name: "IBAN Length", validator: IBAN, value: "DE89 3704 0044 0532 0130", wantErr: "IBANs of DE have 22 characters, not 20"},
+		{name: "IBAN Country", validator: IBAN, value: "US89370400440532013000", wantErr: "'US' is not a country using IBANs"},
+		{name: "BIC", validator: BIC, value: "deutdeff", want: "DEUTDEFF"},
+		{name: "BIC Branch", validator: BIC, value: "DEUTDEFF500", want: "DEUTDEFF500"},
+		{name: "BIC Length", validator: BIC, value: "DEUTDEF", wantErr: "BICs have 8 or 11 characters, not 7"},
+		{name: "BIC Digits", validator: BIC, value: "DEU1DEFF", wantErr: "must be letters"},
+		{name: "Luhn", validator: Luhn, value: "4111-1111-1111-1111", want: "4111111111111111"},
+		{name: "Luhn Odd Length", validator: Luhn, value: "79927398713", want: "79927398713"},
+		{name: "Luhn Check Digit", validator: Luhn, value: "79927398710", wantErr: "the check digit does not match"},
+		{name: "SSN", validator: USSocialSecurityNumber, value: "123 45 6789", want: "123-45-6789"},
+		{name: "SSN Area", validator: USSocialSecurityNumber, value: "666-45-6789", wantErr: "'666' is not an area number"},
+		{name: "SSN Serial", validator: USSocialSecurityNumber, value: "123-45-0000", wantErr: "the serial number is 0000"},
+		{name: "NINO", validator: UKNationalInsuranceNumber, value: "ab 12 34 56 c", want: "AB123456C"},
+		{name: "NINO Prefix", validator: UKNationalInsuranceNumber, value: "GB123456C", wantErr: "'GB' is not a prefix"},
+		{name: "NINO Suffix", validator: UKNationalInsuranceNumber, value: "AB123456E", wantErr: "'E' is not a suffix"},
+		{name: "DNI", validator: SpanishDNI, value: "12345678-z", want: "12345678Z"},
+		{name: "NIE", validator: SpanishDNI, value: "X1234567L", want: "X1234567L"},
+		{name: "DNI Letter", validator: SpanishDNI, value: "12345678A", wantErr: "the check letter should be 'Z'"},
+		{name: "BSN", validator: DutchBSN, value: "111.222.333", want: "111222333"},
+		{name: "BSN Eleven Test", validator: DutchBSN, value: "111222334", wantErr: "it fails the eleven test"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.validator(tt.value)
+			if len(tt.wantErr) > 0 {
+				var validationErr *ValidationError
+				if !errors.As(err, &validationErr) || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("validator(%q) error = %v, want %s", tt.value, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("validator(%q) = %q, %v, want %q", tt.value, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRules(t *testing.T) {
+	rules, err := ParseRules("IBAN=iban, Card Number = LUHN")
+	if err != nil {
+		t.Fatalf("ParseRules() error = %v", err)
+	}
+	if len(rules) != 2 || rules[0].Column != "IBAN" || rules[1].Column != "Card Number" {
+		t.Fatalf("ParseRules() = %+v", rules)
+	}
+	if _, err := ParseRules("IBAN=swift"); err == nil || err.Error() != "unknown validator 'swift'" {
+		t.Errorf("ParseRules(IBAN=swift) error = %v", err)
+	}
+	if _, err := ParseRules("iban"); err == nil {
+		t.Errorf("ParseRules(iban) error = nil")
+	}
+}
+
+func TestRegister(t *testing.T) {
+	Register("Upper", func(value string) (string, error) {
+		if strings.ToUpper(value) != value {
+			return "", invalid(value, "code", "it is not in upper case")
+		}
+		return value, nil
+	})
+	if _, err := Lookup("upper"); err != nil {
+		t.Fatalf("Lookup(upper) error = %v", err)
+	}
+	if names := Names(); !reflect.DeepEqual(names[6:], []string{"upper", "us-ssn"}) {
+		t.Errorf("Names() = %v, want them sorted with upper", names)
+	}
+}
+
+func TestRowFilter(t *testing.T) {
+	rules, err := ParseRules("IBAN=iban,SSN=us-ssn")
+	if err != nil {
+		t.Fatalf("ParseRules() error = %v", err)
+	}
+	var rejected []string
+	keep := RowFilter(rules, func(column string, err error) { rejected = append(rejected, column) })
+	records := []map[string]string{
+		{"IBAN": "DE89370400440532013000", "SSN": "123-45-6789"},
+		{"IBAN": "DE88370400440532013000", "SSN": "000-45-6789"},
+		{"IBAN": " ", "SSN": ""},
+	}
+	var kept []bool
+	for _, record := range records {
+		kept = append(kept, keep(record))
+	}
+	if want := []bool{true, false, true}; !reflect.DeepEqual(kept, want) {
+		t.Errorf("RowFilter() kept %v, want %v", kept, want)
+	}
+	if want := []string{"IBAN", "SSN"}; !reflect.DeepEqual(rejected, want) {
+		t.Errorf("RowFilter() rejected %v, want %v", rejected, want)
+	}
+}
@@ -0,0 +1,180 @@
+package xls
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf16"
+)
+
+// readString decodes an unformatted string at offset, whose character count is two bytes long when wide is set
+// and a single byte otherwise. It returns the string and the offset following it.
+func readString(data []byte, offset int, wide bool) (string, int, error) {
+	var count int
+	if wide {
+		if offset+2 > len(data) {
+			return "", offset, io.ErrUnexpectedEOF
+		}
+		count = int(binary.LittleEndian.Uint16(data[offset:]))
+		offset += 2
+	} else {
+		if offset+1 > len(data) {
+			return "", offset, io.ErrUnexpectedEOF
+		}
+		count = int(data[offset])
+		offset++
+	}
+	if offset+1 > len(data) {
+		return "", offset, io.ErrUnexpectedEOF
+	}
+	highByte := data[offset]&0x01 != 0
+	offset++
+	size := count
+	if highByte {
+		size *= 2
+	}
+	if offset+size > len(data) {
+		return "", offset, io.ErrUnexpectedEOF
+	}
+	return decodeChars(data[offset:offset+size], highByte), offset + size, nil
+}
+
+// decodeChars decodes characters stored either as UTF-16LE or, compressed, as their low byte only.
+func decodeChars(chars []byte, highByte bool) string {
+	if !highByte {
+		var text strings.Builder
+		for _, char := range chars {
+			text.WriteRune(rune(char))
+		}
+		return text.String()
+	}
+	units := make([]uint16, len(chars)/2)
+	for index := range units {
+		units[index] = binary.LittleEndian.Uint16(chars[index*2:])
+	}
+	return string(utf16.Decode(units))
+}
+
+// sstReader reads the shared string table, which spans the SST record and the CONTINUE records following it.
+// The characters of a string may be split across records, in which case the continuation starts with a new
+// option byte telling how the remaining characters are stored.
+type sstReader struct {
+	data     []byte
+	segments []int
+	pos      int
+}
+
+// segmentEnd returns the offset at which the record holding the current position ends.
+func (r *sstReader) segmentEnd() int {
+	for _, start := range r.segments {
+		if start > r.pos {
+			return start
+		}
+	}
+	return len(r.data)
+}
+
+// atSegmentStart reports whether the current position is the start of a CONTINUE record.
+func (r *sstReader) atSegmentStart() bool {
+	for _, start := range r.segments[1:] {
+		if start == r.pos {
+			return true
+		}
+	}
+	return false
+}
+
+// bytes returns the next n bytes, which may span records.
+func (r *sstReader) bytes(n int) ([]byte, error) {
+	if n < 0 || r.pos+n > len(r.data) {
+		return nil, io.ErrUnexpectedEOF
+	}
+	chunk := r.data[r.pos : r.pos+n]
+	r.pos += n
+	return chunk, nil
+}
+
+func (r *sstReader) uint16() (int, error) {
+	chunk, err := r.bytes(2)
+	if err != nil {
+		return 0, err
+	}
+	return int(binary.LittleEndian.Uint16(chunk)), nil
+}
+
+// chars reads count characters, switching encoding at every record boundary the characters span.
+func (r *sstReader) chars(count int, highByte bool) (string, error) {
+	var text strings.Builder
+	for count > 0 {
+		if r.atSegmentStart() {
+			option, err := r.bytes(1)
+			if err != nil {
+				return "", err
+			}
+			highByte = option[0]&0x01 != 0
+		}
+		width := 1
+		if highByte {
+			width = 2
+		}
+		available := (r.segmentEnd() - r.pos) / width
+		if available < 1 {
+			return "", io.ErrUnexpectedEOF
+		}
+		take := min(available, count)
+		chunk, err := r.bytes(take * width)
+		if err != nil {
+			return "", err
+		}
+		text.WriteString(decodeChars(chunk, highByte))
+		count -= take
+	}
+	return text.String(), nil
+}
+
+// readSharedStrings decodes every string of the shared string table, dropping rich text runs and phonetic data.
+func readSharedStrings(rec record) ([]string, error) {
+	reader := &sstReader{data: rec.data, segments: rec.segments}
+	if _, err := reader.bytes(4); err != nil {
+		return nil, err
+	}
+	uniqueBytes, err := reader.bytes(4)
+	if err != nil {
+		return nil, err
+	}
+	unique := int(binary.LittleEndian.Uint32(uniqueBytes))
+	sharedStrings := make([]string, 0, min(unique, len(rec.data)/3))
+	for index := 0; index < unique; index++ {
+		count, countErr := reader.uint16()
+		if countErr != nil {
+			return nil, fmt.Errorf("reading shared string %d: %w", index, countErr)
+		}
+		option, optionErr := reader.bytes(1)
+		if optionErr != nil {
+			return nil, fmt.Errorf("reading shared string %d: %w", index, optionErr)
+		}
+		var runs, extSize int
+		if option[0]&0x08 != 0 {
+			if runs, err = reader.uint16(); err != nil {
+				return nil, err
+			}
+		}
+		if option[0]&0x04 != 0 {
+			sizeBytes, sizeErr := reader.bytes(4)
+			if sizeErr != nil {
+				return nil, sizeErr
+			}
+			extSize = int(int32(binary.LittleEndian.Uint32(sizeBytes)))
+		}
+		text, charsErr := reader.chars(count, option[0]&0x01 != 0)
+		if charsErr != nil {
+			return nil, fmt.Errorf("reading shared string %d: %w", index, charsErr)
+		}
+		if _, err = reader.bytes(runs*4 + extSize); err != nil {
+			return nil, fmt.Errorf("reading shared string %d: %w", index, err)
+		}
+		sharedStrings = append(sharedStrings, text)
+	}
+	return sharedStrings, nil
+}
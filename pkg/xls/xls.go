@@ -0,0 +1,454 @@
+// Package xls reads the cell values of legacy Excel 97-2003 workbooks, stored in the BIFF8 format.
+// Only what a conversion needs is decoded: worksheet names and visibility, the rows and columns hidden, cell values
+// with the number format they are displayed with, the cached results of formulas and merged cell ranges. Formulas, styles other than
+// number formats, charts and macros are ignored. Encrypted workbooks and the older BIFF5 format are rejected.
+package xls
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+
+	"github.com/richardlehane/mscfb"
+)
+
+var (
+	// ErrEncrypted is returned for workbooks protected with a password to open.
+	ErrEncrypted = errors.New("encrypted .xls workbooks are not supported")
+	// ErrNotBIFF8 is returned for compound files that do not hold a BIFF8 workbook stream.
+	ErrNotBIFF8 = errors.New("not an Excel 97-2003 workbook")
+)
+
+// CellKind is the type of value a cell holds.
+type CellKind int
+
+const (
+	NumberCell CellKind = iota
+	TextCell
+	BoolCell
+	ErrorCell
+)
+
+// Cell is a single non-blank cell of a worksheet, with zero-based coordinates.
+// Number holds the value of number cells, Text the value of text and error cells and Bool the value of boolean cells.
+// Format is the number format the cell is displayed with, identified by FormatID; built-in formats
+// (FormatID below 164) have an empty Format.
+type Cell struct {
+	Row, Col int
+	Kind     CellKind
+	Number   float64
+	Text     string
+	Bool     bool
+	FormatID int
+	Format   string
+}
+
+// MergedRange is a merged cell range with zero-based, inclusive coordinates.
+type MergedRange struct {
+	FirstRow, LastRow int
+	FirstCol, LastCol int
+}
+
+// Span is a run of rows or columns with zero-based, inclusive bounds.
+type Span struct {
+	First, Last int
+}
+
+// Sheet is a single worksheet and the cells it holds, in the order they are stored.
+// HiddenRows and HiddenCols list the runs of rows and columns hidden in the worksheet.
+type Sheet struct {
+	Name       string
+	Hidden     bool
+	Cells      []Cell
+	Merged     []MergedRange
+	HiddenRows []Span
+	HiddenCols []Span
+}
+
+// Workbook holds the worksheets of a workbook, in tab order. Date1904 reports whether serial dates count from 1904.
+type Workbook struct {
+	Sheets   []Sheet
+	Date1904 bool
+}
+
+// Record types of the BIFF8 format.
+const (
+	recordFormula    = 0x0006
+	recordEOF        = 0x000A
+	recordDateMode   = 0x0022
+	recordFilePass   = 0x002F
+	recordContinue   = 0x003C
+	recordColInfo    = 0x007D
+	recordBoundSheet = 0x0085
+	recordMulRK      = 0x00BD
+	recordXF         = 0x00E0
+	recordMergeCells = 0x00E5
+	recordSST        = 0x00FC
+	recordLabelSST   = 0x00FD
+	recordNumber     = 0x0203
+	recordLabel      = 0x0204
+	recordBoolErr    = 0x0205
+	recordString     = 0x0207
+	recordRow        = 0x0208
+	recordBOF        = 0x0809
+	recordRK         = 0x027E
+	recordFormat     = 0x041E
+)
+
+// Open reads the workbook at path.
+func Open(path string) (*Workbook, error) {
+	file, openErr := os.Open(path)
+	if openErr != nil {
+		return nil, openErr
+	}
+	defer func(file *os.File) {
+		_ = file.Close()
+	}(file)
+	return Read(file)
+}
+
+// Read reads a workbook from the compound file r.
+func Read(r io.ReaderAt) (*Workbook, error) {
+	compound, compoundErr := mscfb.New(r)
+	if compoundErr != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNotBIFF8, compoundErr)
+	}
+	for entry, nextErr := compound.Next(); nextErr == nil; entry, nextErr = compound.Next() {
+		switch entry.Name {
+		case "Workbook":
+			stream := make([]byte, entry.Size)
+			if _, readErr := io.ReadFull(entry, stream); readErr != nil {
+				return nil, readErr
+			}
+			return parseWorkbook(stream)
+		case "EncryptedPackage":
+			return nil, ErrEncrypted
+		}
+	}
+	return nil, ErrNotBIFF8
+}
+
+// record is a single record of a BIFF8 stream; data includes the payload of the CONTINUE records following it.
+type record struct {
+	kind     uint16
+	data     []byte
+	segments []int
+}
+
+// recordReader iterates over the records of a BIFF8 stream.
+type recordReader struct {
+	stream []byte
+	pos    int
+}
+
+// next returns the record at the current position, joined with the CONTINUE records that follow it.
+func (r *recordReader) next() (record, error) {
+	if r.pos+4 > len(r.stream) {
+		return record{}, io.ErrUnexpectedEOF
+	}
+	var rec record
+	rec.kind = binary.LittleEndian.Uint16(r.stream[r.pos:])
+	for first := true; first || r.peek() == recordContinue; first = false {
+		size := int(binary.LittleEndian.Uint16(r.stream[r.pos+2:]))
+		if r.pos+4+size > len(r.stream) {
+			return record{}, io.ErrUnexpectedEOF
+		}
+		rec.segments = append(rec.segments, len(rec.data))
+		rec.data = append(rec.data, r.stream[r.pos+4:r.pos+4+size]...)
+		r.pos += 4 + size
+	}
+	return rec, nil
+}
+
+// peek returns the type of the record at the current position, or zero at the end of the stream.
+func (r *recordReader) peek() uint16 {
+	if r.pos+4 > len(r.stream) {
+		return 0
+	}
+	return binary.LittleEndian.Uint16(r.stream[r.pos:])
+}
+
+// sheetEntry is a worksheet listed in the workbook globals.
+type sheetEntry struct {
+	offset int
+	name   string
+	hidden bool
+}
+
+// globals holds what the workbook globals substream declares for the worksheets.
+type globals struct {
+	strings []string
+	formats map[int]string
+	xfs     []int
+	sheets  []sheetEntry
+}
+
+// parseWorkbook decodes the workbook globals and then every worksheet they list.
+func parseWorkbook(stream []byte) (*Workbook, error) {
+	reader := &recordReader{stream: stream}
+	bof, bofErr := reader.next()
+	if bofErr != nil || bof.kind != recordBOF || len(bof.data) < 4 || binary.LittleEndian.Uint16(bof.data) != 0x0600 {
+		return nil, ErrNotBIFF8
+	}
+	book := &Workbook{}
+	meta := globals{formats: make(map[int]string)}
+	for {
+		rec, recErr := reader.next()
+		if recErr != nil {
+			return nil, fmt.Errorf("reading workbook globals: %w", recErr)
+		}
+		if rec.kind == recordEOF {
+			break
+		}
+		if len(rec.data) < 2 && rec.kind != recordFilePass {
+			continue
+		}
+		switch rec.kind {
+		case recordFilePass:
+			return nil, ErrEncrypted
+		case recordDateMode:
+			book.Date1904 = binary.LittleEndian.Uint16(rec.data) == 1
+		case recordXF:
+			if len(rec.data) >= 4 {
+				meta.xfs = append(meta.xfs, int(binary.LittleEndian.Uint16(rec.data[2:])))
+			}
+		case recordFormat:
+			text, _, textErr := readString(rec.data, 2, true)
+			if textErr != nil {
+				return nil, textErr
+			}
+			meta.formats[int(binary.LittleEndian.Uint16(rec.data))] = text
+		case recordBoundSheet:
+			if len(rec.data) < 8 {
+				return nil, io.ErrUnexpectedEOF
+			}
+			// Only worksheets are read, charts and macro sheets hold no cell values worth converting.
+			if rec.data[5] != 0 {
+				continue
+			}
+			name, _, nameErr := readString(rec.data, 6, false)
+			if nameErr != nil {
+				return nil, nameErr
+			}
+			meta.sheets = append(meta.sheets, sheetEntry{
+				offset: int(binary.LittleEndian.Uint32(rec.data)),
+				name:   name,
+				hidden: rec.data[4]&0x03 != 0,
+			})
+		case recordSST:
+			sharedStrings, sstErr := readSharedStrings(rec)
+			if sstErr != nil {
+				return nil, sstErr
+			}
+			meta.strings = sharedStrings
+		}
+	}
+	for _, entry := range meta.sheets {
+		sheet, sheetErr := meta.parseSheet(stream, entry)
+		if sheetErr != nil {
+			return nil, fmt.Errorf("reading worksheet %s: %w", entry.name, sheetErr)
+		}
+		book.Sheets = append(book.Sheets, sheet)
+	}
+	return book, nil
+}
+
+// parseSheet decodes the cells of the worksheet substream listed by entry.
+// Substreams embedded in the worksheet, such as charts, are skipped.
+func (g *globals) parseSheet(stream []byte, entry sheetEntry) (Sheet, error) {
+	sheet := Sheet{Name: entry.name, Hidden: entry.hidden}
+	if entry.offset < 0 || entry.offset >= len(stream) {
+		return sheet, io.ErrUnexpectedEOF
+	}
+	reader := &recordReader{stream: stream, pos: entry.offset}
+	depth := 0
+	var pendingFormula *Cell
+	for {
+		rec, recErr := reader.next()
+		if recErr != nil {
+			return sheet, recErr
+		}
+		switch rec.kind {
+		case recordBOF:
+			depth++
+			continue
+		case recordEOF:
+			if depth--; depth <= 0 {
+				return sheet, nil
+			}
+			continue
+		}
+		if depth > 1 {
+			continue
+		}
+		if rec.kind == recordString && pendingFormula != nil {
+			text, _, textErr := readString(rec.data, 0, true)
+			if textErr != nil {
+				return sheet, textErr
+			}
+			pendingFormula.Text = text
+			sheet.Cells = append(sheet.Cells, *pendingFormula)
+			pendingFormula = nil
+			continue
+		}
+		if rec.kind == recordMergeCells {
+			sheet.Merged = append(sheet.Merged, readMergedRanges(rec.data)...)
+			continue
+		}
+		if rec.kind == recordRow {
+			// Bit 5 of the option flags hides the row.
+			if len(rec.data) >= 14 && binary.LittleEndian.Uint16(rec.data[12:])&0x0020 != 0 {
+				row := int(binary.LittleEndian.Uint16(rec.data))
+				sheet.HiddenRows = append(sheet.HiddenRows, Span{First: row, Last: row})
+			}
+			continue
+		}
+		if rec.kind == recordColInfo {
+			// Bit 0 of the option flags hides the columns.
+			if len(rec.data) >= 10 && binary.LittleEndian.Uint16(rec.data[8:])&0x0001 != 0 {
+				sheet.HiddenCols = append(sheet.HiddenCols, Span{
+					First: int(binary.LittleEndian.Uint16(rec.data)),
+					Last:  int(binary.LittleEndian.Uint16(rec.data[2:])),
+				})
+			}
+			continue
+		}
+		if len(rec.data) < 6 {
+			continue
+		}
+		cell := Cell{Row: int(binary.LittleEndian.Uint16(rec.data)), Col: int(binary.LittleEndian.Uint16(rec.data[2:]))}
+		g.applyFormat(&cell, int(binary.LittleEndian.Uint16(rec.data[4:])))
+		switch rec.kind {
+		case recordNumber:
+			if len(rec.data) < 14 {
+				return sheet, io.ErrUnexpectedEOF
+			}
+			cell.Number = math.Float64frombits(binary.LittleEndian.Uint64(rec.data[6:]))
+		case recordRK:
+			if len(rec.data) < 10 {
+				return sheet, io.ErrUnexpectedEOF
+			}
+			cell.Number = decodeRK(binary.LittleEndian.Uint32(rec.data[6:]))
+		case recordMulRK:
+			sheet.Cells = append(sheet.Cells, g.readMulRK(rec.data)...)
+			continue
+		case recordLabelSST:
+			if len(rec.data) < 10 {
+				return sheet, io.ErrUnexpectedEOF
+			}
+			index := int(binary.LittleEndian.Uint32(rec.data[6:]))
+			if index >= len(g.strings) {
+				return sheet, fmt.Errorf("shared string %d out of range", index)
+			}
+			cell.Kind, cell.Text = TextCell, g.strings[index]
+		case recordLabel:
+			text, _, textErr := readString(rec.data, 6, true)
+			if textErr != nil {
+				return sheet, textErr
+			}
+			cell.Kind, cell.Text = TextCell, text
+		case recordBoolErr:
+			if len(rec.data) < 8 {
+				return sheet, io.ErrUnexpectedEOF
+			}
+			setBoolErr(&cell, rec.data[6], rec.data[7] != 0)
+		case recordFormula:
+			if len(rec.data) < 14 {
+				return sheet, io.ErrUnexpectedEOF
+			}
+			result := rec.data[6:14]
+			if binary.LittleEndian.Uint16(result[6:]) != 0xFFFF {
+				cell.Number = math.Float64frombits(binary.LittleEndian.Uint64(result))
+				break
+			}
+			switch result[0] {
+			case 0:
+				// The string result is stored in the STRING record that follows.
+				cell.Kind = TextCell
+				pendingFormula = &cell
+				continue
+			case 1:
+				setBoolErr(&cell, result[2], false)
+			case 2:
+				setBoolErr(&cell, result[2], true)
+			default:
+				cell.Kind = TextCell
+			}
+		default:
+			continue
+		}
+		sheet.Cells = append(sheet.Cells, cell)
+	}
+}
+
+// applyFormat sets the number format of the cell from its extended format.
+func (g *globals) applyFormat(cell *Cell, xf int) {
+	if xf < len(g.xfs) {
+		cell.FormatID = g.xfs[xf]
+		cell.Format = g.formats[cell.FormatID]
+	}
+}
+
+// readMulRK decodes the run of RK numbers of a MULRK record.
+func (g *globals) readMulRK(data []byte) []Cell {
+	row := int(binary.LittleEndian.Uint16(data))
+	firstCol := int(binary.LittleEndian.Uint16(data[2:]))
+	var cells []Cell
+	for pos, col := 4, firstCol; pos+6 <= len(data)-2; pos, col = pos+6, col+1 {
+		cell := Cell{Row: row, Col: col, Number: decodeRK(binary.LittleEndian.Uint32(data[pos+2:]))}
+		g.applyFormat(&cell, int(binary.LittleEndian.Uint16(data[pos:])))
+		cells = append(cells, cell)
+	}
+	return cells
+}
+
+// readMergedRanges decodes the ranges of a MERGECELLS record.
+func readMergedRanges(data []byte) []MergedRange {
+	if len(data) < 2 {
+		return nil
+	}
+	count := int(binary.LittleEndian.Uint16(data))
+	var ranges []MergedRange
+	for index := 0; index < count && 2+index*8+8 <= len(data); index++ {
+		ref := data[2+index*8:]
+		ranges = append(ranges, MergedRange{
+			FirstRow: int(binary.LittleEndian.Uint16(ref)),
+			LastRow:  int(binary.LittleEndian.Uint16(ref[2:])),
+			FirstCol: int(binary.LittleEndian.Uint16(ref[4:])),
+			LastCol:  int(binary.LittleEndian.Uint16(ref[6:])),
+		})
+	}
+	return ranges
+}
+
+// decodeRK decodes the compressed RK representation of a number.
+func decodeRK(rk uint32) float64 {
+	var number float64
+	if rk&0x02 != 0 {
+		number = float64(int32(rk) >> 2)
+	} else {
+		number = math.Float64frombits(uint64(rk&0xFFFFFFFC) << 32)
+	}
+	if rk&0x01 != 0 {
+		number /= 100
+	}
+	return number
+}
+
+// errorTexts maps BIFF8 error codes to the text Excel displays for them.
+var errorTexts = map[byte]string{
+	0x00: "#NULL!", 0x07: "#DIV/0!", 0x0F: "#VALUE!", 0x17: "#REF!",
+	0x1D: "#NAME?", 0x24: "#NUM!", 0x2A: "#N/A",
+}
+
+// setBoolErr sets a boolean or, when isError is set, an error value.
+func setBoolErr(cell *Cell, value byte, isError bool) {
+	if isError {
+		cell.Kind, cell.Text = ErrorCell, errorTexts[value]
+		return
+	}
+	cell.Kind, cell.Bool = BoolCell, value != 0
+}
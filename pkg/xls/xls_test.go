@@ -0,0 +1,179 @@
+package xls
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"reflect"
+	"testing"
+	"unicode/utf16"
+)
+
+// biffRecord encodes a single BIFF8 record.
+func biffRecord(kind uint16, fields ...any) []byte {
+	var data bytes.Buffer
+	for _, field := range fields {
+		_ = binary.Write(&data, binary.LittleEndian, field)
+	}
+	var out bytes.Buffer
+	_ = binary.Write(&out, binary.LittleEndian, kind)
+	_ = binary.Write(&out, binary.LittleEndian, uint16(data.Len()))
+	out.Write(data.Bytes())
+	return out.Bytes()
+}
+
+// compoundFile wraps stream into a minimal compound file holding a single Workbook stream.
+func compoundFile(t *testing.T, stream []byte) []byte {
+	t.Helper()
+	const sector = 512
+	// Streams shorter than the mini stream cutoff live in the mini stream, pad to keep to regular sectors.
+	for len(stream) < 4096 {
+		stream = append(stream, 0)
+	}
+	dataSectors := (len(stream) + sector - 1) / sector
+	if dataSectors+2 > sector/4 {
+		t.Fatalf("Test workbook stream too large")
+	}
+	le := binary.LittleEndian
+	header := make([]byte, sector)
+	copy(header, []byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1})
+	le.PutUint16(header[24:], 0x003E)
+	le.PutUint16(header[26:], 0x0003)
+	le.PutUint16(header[28:], 0xFFFE)
+	le.PutUint16(header[30:], 9)
+	le.PutUint16(header[32:], 6)
+	le.PutUint32(header[44:], 1)
+	le.PutUint32(header[48:], uint32(dataSectors+1))
+	le.PutUint32(header[56:], 4096)
+	le.PutUint32(header[60:], 0xFFFFFFFE)
+	le.PutUint32(header[68:], 0xFFFFFFFE)
+	for index := 0; index < 109; index++ {
+		le.PutUint32(header[76+index*4:], 0xFFFFFFFF)
+	}
+	le.PutUint32(header[76:], 0)
+
+	fat := make([]byte, sector)
+	for index := 0; index < sector/4; index++ {
+		le.PutUint32(fat[index*4:], 0xFFFFFFFF)
+	}
+	le.PutUint32(fat, 0xFFFFFFFD)
+	for index := 1; index < dataSectors; index++ {
+		le.PutUint32(fat[index*4:], uint32(index+1))
+	}
+	le.PutUint32(fat[dataSectors*4:], 0xFFFFFFFE)
+	le.PutUint32(fat[(dataSectors+1)*4:], 0xFFFFFFFE)
+
+	directory := make([]byte, sector)
+	entry := func(index int, name string, kind byte, child, start uint32, size int) {
+		raw := directory[index*128:]
+		units := utf16.Encode([]rune(name))
+		for pos, unit := range units {
+			le.PutUint16(raw[pos*2:], unit)
+		}
+		le.PutUint16(raw[64:], uint16(len(units)*2+2))
+		raw[66], raw[67] = kind, 1
+		le.PutUint32(raw[68:], 0xFFFFFFFF)
+		le.PutUint32(raw[72:], 0xFFFFFFFF)
+		le.PutUint32(raw[76:], child)
+		le.PutUint32(raw[116:], start)
+		le.PutUint32(raw[120:], uint32(size))
+	}
+	entry(0, "Root Entry", 5, 1, 0xFFFFFFFE, 0)
+	entry(1, "Workbook", 2, 0xFFFFFFFF, 1, len(stream))
+	for index := 2; index < 4; index++ {
+		entry(index, "", 0, 0xFFFFFFFF, 0, 0)
+	}
+
+	body := append(header, fat...)
+	body = append(body, stream...)
+	for len(body) < (dataSectors+2)*sector {
+		body = append(body, 0)
+	}
+	return append(body, directory...)
+}
+
+func TestRead(t *testing.T) {
+	bof := func(kind uint16) []byte { return biffRecord(recordBOF, uint16(0x0600), kind, uint16(0), uint16(0)) }
+	eof := biffRecord(recordEOF)
+	people := bytes.Join([][]byte{
+		bof(0x0010),
+		biffRecord(recordColInfo, uint16(1), uint16(2), uint16(2048), uint16(0), uint16(0x0001), uint16(0)),
+		biffRecord(recordRow, uint16(0), uint16(0), uint16(2), uint16(255), uint16(0), uint16(0), uint16(0), uint16(15)),
+		biffRecord(recordRow, uint16(2), uint16(0), uint16(2), uint16(255), uint16(0), uint16(0), uint16(0x0020), uint16(15)),
+		biffRecord(recordLabelSST, uint16(0), uint16(0), uint16(0), uint32(0)),
+		biffRecord(recordLabelSST, uint16(0), uint16(1), uint16(0), uint32(1)),
+		biffRecord(recordLabelSST, uint16(1), uint16(0), uint16(0), uint32(2)),
+		biffRecord(recordNumber, uint16(1), uint16(1), uint16(1), math.Float64bits(38719)),
+		biffRecord(recordMulRK, uint16(2), uint16(0), uint16(2), uint32(3141<<2|0x03), uint16(0), uint32(100<<2|0x02), uint16(1)),
+		biffRecord(recordFormula, uint16(3), uint16(0), uint16(0), []byte{0, 0, 0, 0, 0, 0, 0xFF, 0xFF}, uint16(0), uint32(0)),
+		biffRecord(recordString, uint16(2), uint8(0), []byte("ok")),
+		biffRecord(recordBoolErr, uint16(3), uint16(1), uint16(0), uint8(1), uint8(0)),
+		biffRecord(recordBOF, uint16(0x0600), uint16(0x0020), uint16(0), uint16(0)),
+		biffRecord(recordNumber, uint16(9), uint16(9), uint16(0), math.Float64bits(1)),
+		eof,
+		biffRecord(recordBoolErr, uint16(4), uint16(0), uint16(0), uint8(0x07), uint8(1)),
+		biffRecord(recordMergeCells, uint16(1), uint16(4), uint16(5), uint16(0), uint16(1)),
+		biffRecord(recordLabelSST, uint16(6), uint16(0), uint16(0), uint32(3)),
+		eof,
+	}, nil)
+	hidden := bytes.Join([][]byte{bof(0x0010), biffRecord(recordRK, uint16(0), uint16(0), uint16(0), uint32(0x3FF00000)), eof}, nil)
+
+	sheetEntry := func(offset int, hidden uint8, name string) []byte {
+		return biffRecord(recordBoundSheet, uint32(offset), hidden, uint8(0), uint8(len(name)), uint8(0), []byte(name))
+	}
+	// The last shared string is split across a CONTINUE record which switches it to two-byte characters.
+	sst := biffRecord(recordSST, uint32(4), uint32(4),
+		uint16(4), uint8(0), []byte("Name"),
+		uint16(6), uint8(0), []byte("Joined"),
+		uint16(3), uint8(0x01), utf16.Encode([]rune("Ann")),
+		uint16(4), uint8(0), []byte("Zo"))
+	continued := biffRecord(recordContinue, uint8(0x01), utf16.Encode([]rune("ë!")))
+	globalsFor := func(peopleOffset, hiddenOffset int) []byte {
+		return bytes.Join([][]byte{
+			bof(0x0005),
+			biffRecord(recordDateMode, uint16(1)),
+			biffRecord(recordFormat, uint16(164), uint16(5), uint8(0), []byte("0.000")),
+			biffRecord(recordXF, uint16(0), uint16(0), make([]byte, 16)),
+			biffRecord(recordXF, uint16(0), uint16(14), make([]byte, 16)),
+			biffRecord(recordXF, uint16(0), uint16(164), make([]byte, 16)),
+			sheetEntry(peopleOffset, 0, "People"),
+			sheetEntry(hiddenOffset, 1, "Hidden"),
+			sst, continued,
+			eof,
+		}, nil)
+	}
+	globalsSize := len(globalsFor(0, 0))
+	stream := bytes.Join([][]byte{globalsFor(globalsSize, globalsSize+len(people)), people, hidden}, nil)
+
+	book, err := Read(bytes.NewReader(compoundFile(t, stream)))
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	want := &Workbook{
+		Date1904: true,
+		Sheets: []Sheet{
+			{
+				Name: "People",
+				Cells: []Cell{
+					{Row: 0, Col: 0, Kind: TextCell, Text: "Name"},
+					{Row: 0, Col: 1, Kind: TextCell, Text: "Joined"},
+					{Row: 1, Col: 0, Kind: TextCell, Text: "Ann"},
+					{Row: 1, Col: 1, Number: 38719, FormatID: 14},
+					{Row: 2, Col: 0, Number: 31.41, FormatID: 164, Format: "0.000"},
+					{Row: 2, Col: 1, Number: 100},
+					{Row: 3, Col: 0, Kind: TextCell, Text: "ok"},
+					{Row: 3, Col: 1, Kind: BoolCell, Bool: true},
+					{Row: 4, Col: 0, Kind: ErrorCell, Text: "#DIV/0!"},
+					{Row: 6, Col: 0, Kind: TextCell, Text: "Zoë!"},
+				},
+				Merged:     []MergedRange{{FirstRow: 4, LastRow: 5, FirstCol: 0, LastCol: 1}},
+				HiddenRows: []Span{{First: 2, Last: 2}},
+				HiddenCols: []Span{{First: 1, Last: 2}},
+			},
+			{Name: "Hidden", Hidden: true, Cells: []Cell{{Number: 1}}},
+		},
+	}
+	if !reflect.DeepEqual(book, want) {
+		t.Errorf("Read() = %+v, want %+v", book, want)
+	}
+}